@@ -0,0 +1,150 @@
+// Package admin provides an administrative service, permitting inspection and adjustment of the runtime
+// configuration of the server without restarting.
+package admin
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"regexp"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/scheduler"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Options configures the admin service
+type Options struct {
+	Server      *server.Server           // used to list registered providers and health checks
+	Settings    func() map[string]string // returns the effective configuration, which is redacted before it is returned
+	ReloadRules func() (int, error)      // reloads document routing rules, returning the number of rules; optional
+	Scheduler   *scheduler.Scheduler     // scheduled jobs, such as cache warming; optional
+}
+
+// Server provides the admin service
+type Server struct {
+	opts Options
+}
+
+var _ apiv1.AdminServer = (*Server)(nil)
+
+// New creates a new admin service
+func New(opts Options) *Server {
+	return &Server{opts: opts}
+}
+
+// RegisterServer registers this server
+func (svc *Server) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterAdminServer(s, svc)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (svc *Server) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterAdminHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (svc *Server) Close() error { return nil }
+
+// GetConfiguration returns the effective configuration, with secrets redacted
+func (svc *Server) GetConfiguration(ctx context.Context, r *apiv1.GetConfigurationRequest) (*apiv1.Configuration, error) {
+	result := &apiv1.Configuration{Settings: make(map[string]string)}
+	if svc.opts.Settings == nil {
+		return result, nil
+	}
+	for k, v := range svc.opts.Settings() {
+		result.Settings[k] = Redact(k, v)
+	}
+	return result, nil
+}
+
+// ListProviders returns the registered providers, health checks, resolvers and mappers
+func (svc *Server) ListProviders(ctx context.Context, r *apiv1.ListProvidersRequest) (*apiv1.ListProvidersResponse, error) {
+	result := &apiv1.ListProvidersResponse{
+		Resolvers: identifiers.Resolvers(),
+		Mappers:   identifiers.Mappers(),
+	}
+	if svc.opts.Server != nil {
+		result.Providers = svc.opts.Server.Providers()
+		result.HealthChecks = svc.opts.Server.HealthChecks()
+	}
+	return result, nil
+}
+
+// ReloadRules reloads the document routing rules
+func (svc *Server) ReloadRules(ctx context.Context, r *apiv1.ReloadRulesRequest) (*apiv1.ReloadRulesResponse, error) {
+	if svc.opts.ReloadRules == nil {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "admin: no document routing rules file configured")
+	}
+	n, err := svc.opts.ReloadRules()
+	if err != nil {
+		log.Printf("admin: failed to reload document routing rules: %s", err)
+		return nil, status.Errorf(codes.InvalidArgument, "admin: failed to reload document routing rules: %s", err)
+	}
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("admin: '%s|%s' reloaded %d document routing rules", user.GetSystem(), user.GetValue(), n)
+	return &apiv1.ReloadRulesResponse{Rules: int32(n)}, nil
+}
+
+// SetLogLevel sets the log level for a component, or the default level if no component is specified.
+// Log messages are only filtered once FilterLog has been called.
+func (svc *Server) SetLogLevel(ctx context.Context, r *apiv1.LogLevel) (*apiv1.LogLevels, error) {
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("admin: '%s|%s' setting log level for '%s' to %s", user.GetSystem(), user.GetValue(), r.GetComponent(), r.GetLevel())
+	return filter.setLevel(r.GetComponent(), r.GetLevel()), nil
+}
+
+// ListJobs returns the scheduled jobs, with the report of their most recent run
+func (svc *Server) ListJobs(ctx context.Context, r *apiv1.ListJobsRequest) (*apiv1.ListJobsResponse, error) {
+	if svc.opts.Scheduler == nil {
+		return &apiv1.ListJobsResponse{}, nil
+	}
+	return &apiv1.ListJobsResponse{Jobs: svc.opts.Scheduler.Jobs()}, nil
+}
+
+// RunJob runs a scheduled job immediately, returning the report of the run
+func (svc *Server) RunJob(ctx context.Context, r *apiv1.RunJobRequest) (*apiv1.JobRun, error) {
+	if r.GetName() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "admin: missing parameter: name")
+	}
+	if svc.opts.Scheduler == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "scheduler: job not found: %s", r.GetName())
+	}
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("admin: '%s|%s' running job '%s'", user.GetSystem(), user.GetValue(), r.GetName())
+	return svc.opts.Scheduler.Run(ctx, r.GetName())
+}
+
+var (
+	// rxSecret matches configuration keys whose values are secrets
+	rxSecret = regexp.MustCompile(`(?i)(password|secret|token|api-?key|credential)`)
+	// rxConnPassword matches a password within a database connection string e.g. "dbname=concierge password=secret"
+	rxConnPassword = regexp.MustCompile(`(?i)(password=)('[^']*'|\S+)`)
+)
+
+// Redact returns the value of a configuration setting, redacting secrets, including passwords within URLs
+// and database connection strings
+func Redact(key string, value string) string {
+	if value == "" {
+		return value
+	}
+	if rxSecret.MatchString(key) {
+		return "REDACTED"
+	}
+	if rxConnPassword.MatchString(value) {
+		return rxConnPassword.ReplaceAllString(value, "${1}REDACTED")
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+			return u.String()
+		}
+	}
+	return value
+}
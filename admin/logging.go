@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+// rxComponent matches the component prefixing a log message, after the header written by the standard logger
+var rxComponent = regexp.MustCompile(`^(?:\d{4}/\d{2}/\d{2} )?(?:\d{2}:\d{2}:\d{2}(?:\.\d+)? )?(?:\S+\.go:\d+: )?([a-z][a-z0-9-]*): `)
+
+// logFilter filters the output of the standard logger by component, as concierge's log messages are
+// conventionally prefixed by the name of the component, e.g. "empi: request from..."
+type logFilter struct {
+	mu       sync.RWMutex
+	out      io.Writer
+	fallback apiv1.LogLevel_Level            // level for components without a specific level
+	levels   map[string]apiv1.LogLevel_Level // component -> level
+}
+
+var filter = &logFilter{levels: make(map[string]apiv1.LogLevel_Level)}
+
+// FilterLog installs a filter on the output of the standard logger, so that the log level of each component
+// can be set at runtime using SetLogLevel. This should be called after the output of the logger has been set.
+func FilterLog() {
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+	if filter.out == nil {
+		filter.out = log.Writer()
+		log.SetOutput(filter)
+	}
+}
+
+func (lf *logFilter) Write(p []byte) (int, error) {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	level := lf.fallback
+	if m := rxComponent.FindSubmatch(p); m != nil {
+		if l, ok := lf.levels[string(m[1])]; ok {
+			level = l
+		}
+	}
+	if level == apiv1.LogLevel_OFF {
+		return len(p), nil
+	}
+	return lf.out.Write(p)
+}
+
+// setLevel sets the level for the component specified, or the default level if no component is specified,
+// returning the current levels
+func (lf *logFilter) setLevel(component string, level apiv1.LogLevel_Level) *apiv1.LogLevels {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if component == "" {
+		lf.fallback = level
+		lf.levels = make(map[string]apiv1.LogLevel_Level)
+	} else if level == lf.fallback {
+		delete(lf.levels, component)
+	} else {
+		lf.levels[component] = level
+	}
+	result := &apiv1.LogLevels{Default: lf.fallback, Levels: make([]*apiv1.LogLevel, 0, len(lf.levels))}
+	for c, l := range lf.levels {
+		result.Levels = append(result.Levels, &apiv1.LogLevel{Component: c, Level: l})
+	}
+	sort.Slice(result.Levels, func(i, j int) bool { return result.Levels[i].GetComponent() < result.Levels[j].GetComponent() })
+	return result
+}
@@ -1,5 +1,12 @@
 package apiv1
 
+//go:generate go run gen/swagger.go
+
+import (
+	"strconv"
+	"strings"
+)
+
 // GetIdentifiersForSystem returns the identifier matching the system specified, it is exists
 func (pt *Patient) GetIdentifiersForSystem(s string) ([]*Identifier, bool) {
 	if pt == nil {
@@ -14,44 +21,132 @@ func (pt *Patient) GetIdentifiersForSystem(s string) ([]*Identifier, bool) {
 	return result, len(result) > 0
 }
 
-// Match determines whether one patient is the same as another
-func (pt *Patient) Match(other *Patient, identifierSystems []string) bool {
-	if matchedIdentifiers(pt, other, identifierSystems) == false {
-		return false
-	}
-	if pt.GetLastname() != other.GetLastname() {
-		return false
+// Format returns the name formatted for display e.g. "Dr Mark Wardle"
+func (n *HumanName) Format() string {
+	parts := append([]string{}, n.GetPrefixes()...)
+	parts = append(parts, n.GetGiven(), n.GetFamily())
+	parts = append(parts, n.GetSuffices()...)
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// DisplayName returns the name of the practitioner formatted for display, or an empty string if there is no name.
+// The usual or official name is preferred.
+func (p *Practitioner) DisplayName() string {
+	var name *HumanName
+	for _, n := range p.GetNames() {
+		if name == nil || n.GetUse() == HumanName_USUAL || (n.GetUse() == HumanName_OFFICIAL && name.GetUse() != HumanName_USUAL) {
+			name = n
+		}
 	}
-	if pt.GetBirthDate() != other.GetBirthDate() {
-		return false
+	return name.Format()
+}
+
+// ParseNHSNumberVerificationStatus parses an NHS number status indicator code, such as "01", or as
+// used in HL7 v2 messages, "NSTS01", returning NHS_NUMBER_STATUS_UNKNOWN if the code is not recognised.
+func ParseNHSNumberVerificationStatus(code string) NHSNumberVerificationStatus {
+	code = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(code)), "NSTS")
+	n, err := strconv.Atoi(code)
+	if err != nil || n < 1 || n > int(NHSNumberVerificationStatus_NHS_NUMBER_TRACE_POSTPONED) {
+		return NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN
 	}
-	if pt.GetGender() != other.GetGender() {
-		return false
+	return NHSNumberVerificationStatus(n)
+}
+
+// Code returns the HL7 confidentiality code for the sensitivity: N (normal), R (restricted) or V (very restricted)
+func (s Document_Sensitivity) Code() string {
+	switch s {
+	case Document_RESTRICTED:
+		return "R"
+	case Document_VERY_RESTRICTED:
+		return "V"
 	}
-	return true
+	return "N"
 }
 
-func matchedIdentifiers(pt1 *Patient, pt2 *Patient, systems []string) bool {
-	for _, system := range systems {
-		if matchedIdentifiersForSystem(pt1, pt2, system) == false {
-			return false
+// ParseSensitivity parses a document sensitivity, either by name (e.g. "restricted") or HL7 confidentiality code (e.g. "R")
+func ParseSensitivity(s string) (Document_Sensitivity, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for value, name := range Document_Sensitivity_name {
+		sensitivity := Document_Sensitivity(value)
+		if s == name || s == strings.ReplaceAll(name, "_", "-") || s == sensitivity.Code() {
+			return sensitivity, true
 		}
 	}
-	return true
+	return Document_NORMAL, false
 }
 
-// checks that at least one identifiers for a specified namespace matches
-func matchedIdentifiersForSystem(pt1 *Patient, pt2 *Patient, system string) bool {
-	if ids1, found := pt1.GetIdentifiersForSystem(system); found {
-		if ids2, found := pt2.GetIdentifiersForSystem(system); found {
-			for _, id1 := range ids1 {
-				for _, id2 := range ids2 {
-					if id1.GetValue() != id2.GetValue() {
-						return true
-					}
-				}
-			}
+// Lines returns the non-empty lines of the address, excluding postcode and country, for display
+// e.g. ["1 Station Road", "Heath", "Cardiff", "South Glamorgan"]
+func (a *Address) Lines() []string {
+	result := make([]string, 0, 4)
+	for _, line := range []string{a.GetAddress1(), a.GetAddress2(), a.GetAddress3(), a.GetCounty()} {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
 		}
 	}
-	return false
+	return result
+}
+
+// addressUseCodes maps HL7 address type codes (table 0190) to address use
+var addressUseCodes = map[string]Address_Use{
+	"H":  Address_HOME,
+	"P":  Address_HOME,
+	"L":  Address_HOME,
+	"C":  Address_TEMPORARY,
+	"M":  Address_CORRESPONDENCE,
+	"B":  Address_WORK,
+	"O":  Address_WORK,
+	"BA": Address_OLD,
+}
+
+// ParseAddressUse parses an HL7 address type code (XAD-7) e.g. "H" for home, returning UNKNOWN if the code is not recognised
+func ParseAddressUse(code string) Address_Use {
+	return addressUseCodes[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// Code returns the HL7 address type code (XAD-7) for the use e.g. "H" for home, or an empty string if unknown
+func (u Address_Use) Code() string {
+	switch u {
+	case Address_HOME:
+		return "H"
+	case Address_TEMPORARY:
+		return "C"
+	case Address_CORRESPONDENCE:
+		return "M"
+	case Address_WORK:
+		return "B"
+	case Address_OLD:
+		return "BA"
+	}
+	return ""
+}
+
+// telephoneUseCodes maps HL7 telecommunication use codes (table 0201) to telephone use
+var telephoneUseCodes = map[string]Telephone_Use{
+	"PRN": Telephone_HOME,
+	"ORN": Telephone_HOME,
+	"VHN": Telephone_HOME,
+	"WPN": Telephone_WORK,
+}
+
+// ParseTelephoneUse parses an HL7 telecommunication use code (XTN-2) and equipment type (XTN-3) e.g. "PRN" and "PH"
+// for a home telephone; mobile telephones, with equipment type "CP", are always MOBILE whatever their use.
+// Returns UNKNOWN if neither is recognised.
+func ParseTelephoneUse(useCode string, equipmentType string) Telephone_Use {
+	if strings.EqualFold(strings.TrimSpace(equipmentType), "CP") {
+		return Telephone_MOBILE
+	}
+	return telephoneUseCodes[strings.ToUpper(strings.TrimSpace(useCode))]
+}
+
+// Codes returns the HL7 telecommunication use code (XTN-2) and equipment type (XTN-3) for the use
+// e.g. "PRN" and "PH" for home
+func (u Telephone_Use) Codes() (useCode string, equipmentType string) {
+	switch u {
+	case Telephone_WORK:
+		return "WPN", "PH"
+	case Telephone_MOBILE:
+		return "PRN", "CP"
+	}
+	return "PRN", "PH"
 }
@@ -0,0 +1,48 @@
+package apiv1
+
+import (
+	"time"
+)
+
+// NewDate returns the calendar date of the time specified, in the time's own location, or nil for the zero time
+func NewDate(t time.Time) *Date {
+	if t.IsZero() {
+		return nil
+	}
+	return &Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+}
+
+// ParseDate parses a date using the layout specified (see time.Parse) e.g. "20060102"
+func ParseDate(layout string, value string) (*Date, error) {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return nil, err
+	}
+	return NewDate(t), nil
+}
+
+// IsValid returns whether the date is a valid calendar date
+func (d *Date) IsValid() bool {
+	if d == nil || d.Year < 1 || d.Year > 9999 || d.Month < 1 || d.Month > 12 || d.Day < 1 {
+		return false
+	}
+	return d.Time().Day() == int(d.Day) // e.g. 31 February normalises to March
+}
+
+// Time returns the start of the date in UTC, or the zero time if the date is not valid.
+// The result should only be formatted or compared with other dates, and not converted to another time zone.
+func (d *Date) Time() time.Time {
+	if d == nil || d.Year < 1 || d.Month < 1 || d.Day < 1 {
+		return time.Time{}
+	}
+	return time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.UTC)
+}
+
+// Format returns the date formatted using the layout specified (see time.Format) e.g. "2006-01-02",
+// or an empty string if the date is not valid
+func (d *Date) Format(layout string) string {
+	if !d.IsValid() {
+		return ""
+	}
+	return d.Time().Format(layout)
+}
@@ -0,0 +1,45 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	// a birth date recorded as local midnight is the same date, whatever the time zone
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+	d := NewDate(time.Date(1980, time.June, 1, 0, 0, 0, 0, london))
+	if d.GetYear() != 1980 || d.GetMonth() != 6 || d.GetDay() != 1 {
+		t.Fatalf("expected 1980-06-01. got: %v", d)
+	}
+	if s := d.Format("2006-01-02"); s != "1980-06-01" {
+		t.Fatalf("expected 1980-06-01. got: %s", s)
+	}
+	if NewDate(time.Time{}) != nil {
+		t.Fatal("expected no date for zero time")
+	}
+	if d, err := ParseDate("20060102", "19800601"); err != nil || d.Format("02/01/2006") != "01/06/1980" {
+		t.Fatalf("failed to parse date: %v %v", d, err)
+	}
+	tests := []struct {
+		date  *Date
+		valid bool
+	}{
+		{&Date{Year: 2020, Month: 2, Day: 29}, true},
+		{&Date{Year: 2019, Month: 2, Day: 29}, false},
+		{&Date{Year: 2020, Month: 13, Day: 1}, false},
+		{&Date{Month: 1, Day: 1}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if test.date.IsValid() != test.valid {
+			t.Errorf("%v: expected valid %t", test.date, test.valid)
+		}
+		if !test.valid && test.date.Format("2006-01-02") != "" {
+			t.Errorf("%v: expected invalid date not to be formatted", test.date)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// +build ignore
+
+// This program generates services.swagger.go, embedding the OpenAPI definition generated by
+// protoc-gen-swagger so that it can be served by the HTTP gateway. Invoked by go generate.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+func main() {
+	b, err := ioutil.ReadFile("services.swagger.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if bytes.ContainsRune(b, '`') {
+		log.Fatal("swagger: definition contains a backtick and cannot be embedded as a raw string")
+	}
+	var sb strings.Builder
+	sb.WriteString("// Code generated by gen/swagger.go from services.swagger.json. DO NOT EDIT.\n\n")
+	sb.WriteString("package apiv1\n\n")
+	sb.WriteString("// SwaggerJSON is the OpenAPI (Swagger 2.0) definition of the REST API provided by the HTTP gateway\n")
+	sb.WriteString("const SwaggerJSON = `")
+	sb.Write(b)
+	sb.WriteString("`\n")
+	if err := ioutil.WriteFile("services.swagger.go", []byte(sb.String()), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,280 @@
+package apiv1
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMatchThreshold is the minimum score for two patients to be considered the same person
+const DefaultMatchThreshold = 0.8
+
+// MatchResult is the result of scoring whether two patients are the same person
+type MatchResult struct {
+	Score   float64  // confidence that the patients are the same person, from 0 (different) to 1 (identical)
+	Reasons []string // reasons for the score, such as which fields matched, and how
+}
+
+// String returns a summary of the result e.g. "0.85 (lastname: exact match; birth date: day and month transposed)"
+func (mr *MatchResult) String() string {
+	return fmt.Sprintf("%.2f (%s)", mr.Score, strings.Join(mr.Reasons, "; "))
+}
+
+// weights of each demographic field when scoring matches; fields missing from either patient are ignored
+const (
+	lastnameWeight  = 0.3
+	firstnameWeight = 0.2
+	birthDateWeight = 0.35
+	genderWeight    = 0.15
+	minimumWeight   = lastnameWeight + birthDateWeight // minimum total weight of the fields compared
+)
+
+// scores for each type of match of an individual field
+const (
+	exactMatch     = 1.0
+	partialName    = 0.9 // e.g. one of a double-barrelled surname
+	singleEdit     = 0.8 // e.g. one mistyped or transposed character
+	transposedDate = 0.8 // day and month transposed
+	phonetic       = 0.7
+	initialOnly    = 0.7
+	partialDate    = 0.6 // one of day, month or year differs
+	reorderedNames = 0.6
+	noMatch        = 0.0
+)
+
+// Match determines whether one patient is the same as another, with a score of at least DefaultMatchThreshold.
+// See MatchScore.
+func (pt *Patient) Match(other *Patient, identifierSystems []string) bool {
+	return pt.MatchScore(other, identifierSystems).Score >= DefaultMatchThreshold
+}
+
+// MatchScore scores whether one patient is the same as another, using their identifiers and demographics.
+// Patients with conflicting identifiers in any of the systems specified, having identifiers in that system
+// but none in common, are never considered the same person. Names are compared ignoring case, accents
+// and punctuation, allowing for transposed or mistyped characters, phonetic equivalence (Soundex),
+// double-barrelled surnames and initials. Birth dates are compared allowing for transposed day and month,
+// and a single incorrect component. Patients with fewer demographics to compare than a lastname and birth date score zero.
+func (pt *Patient) MatchScore(other *Patient, identifierSystems []string) *MatchResult {
+	result := &MatchResult{}
+	for _, system := range identifierSystems {
+		ids1, found1 := pt.GetIdentifiersForSystem(system)
+		ids2, found2 := other.GetIdentifiersForSystem(system)
+		if !found1 || !found2 {
+			continue
+		}
+		if !sharedIdentifier(ids1, ids2) {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("conflicting identifiers: %s", system))
+			return result
+		}
+		result.Reasons = append(result.Reasons, fmt.Sprintf("matching identifiers: %s", system))
+	}
+	var total, weights float64
+	add := func(weight float64, field string, score float64, reason string) {
+		total += weight * score
+		weights += weight
+		result.Reasons = append(result.Reasons, field+": "+reason)
+	}
+	if a, b := normaliseName(pt.GetLastname()), normaliseName(other.GetLastname()); a != "" && b != "" {
+		score, reason := compareLastnames(a, b)
+		add(lastnameWeight, "lastname", score, reason)
+	}
+	if a, b := strings.Fields(normaliseNames(pt.GetFirstnames())), strings.Fields(normaliseNames(other.GetFirstnames())); len(a) > 0 && len(b) > 0 {
+		score, reason := compareFirstnames(a, b)
+		add(firstnameWeight, "firstnames", score, reason)
+	}
+	if pt.GetBirthDate() != nil && other.GetBirthDate() != nil {
+		score, reason := compareBirthDates(pt, other)
+		add(birthDateWeight, "birth date", score, reason)
+	}
+	if pt.GetGender() != Gender_UNKNOWN && other.GetGender() != Gender_UNKNOWN {
+		if pt.GetGender() == other.GetGender() {
+			add(genderWeight, "gender", exactMatch, "exact match")
+		} else {
+			add(genderWeight, "gender", noMatch, "no match")
+		}
+	}
+	if weights < minimumWeight-1e-9 {
+		result.Reasons = append(result.Reasons, "insufficient demographics to compare")
+		return result
+	}
+	result.Score = total / weights
+	return result
+}
+
+// sharedIdentifier returns whether any identifier value is in both lists, ignoring case and whitespace
+func sharedIdentifier(ids1, ids2 []*Identifier) bool {
+	for _, id1 := range ids1 {
+		for _, id2 := range ids2 {
+			if normaliseIdentifier(id1.GetValue()) == normaliseIdentifier(id2.GetValue()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normaliseIdentifier(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// normaliseNames returns names in upper case, without accents or punctuation, and separated by a single space,
+// treating hyphens as separators e.g. "Jean-Luc  O'Brien" becomes "JEAN LUC OBRIEN"
+func normaliseNames(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '\'', r == '’', r == '.':
+			continue
+		case unicode.IsLetter(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// normaliseName returns a name normalised as for normaliseNames, but with its parts joined e.g. "JONESSMITH"
+func normaliseName(s string) string {
+	return strings.ReplaceAll(normaliseNames(s), " ", "")
+}
+
+// compareLastnames compares two normalised lastnames, allowing for double-barrelled or multiple surnames
+func compareLastnames(a, b string) (float64, string) {
+	if score, reason := compareName(a, b); score > noMatch {
+		return score, reason
+	}
+	if len(a) >= 3 && len(b) >= 3 && (strings.HasPrefix(a, b) || strings.HasSuffix(a, b) || strings.HasPrefix(b, a) || strings.HasSuffix(b, a)) {
+		return partialName, "partial match (double-barrelled or multiple surnames)"
+	}
+	return noMatch, "no match"
+}
+
+// compareFirstnames compares normalised first names, allowing for initials and reordered names
+func compareFirstnames(a, b []string) (float64, string) {
+	if score, reason := compareName(a[0], b[0]); score > noMatch {
+		return score, reason
+	}
+	if (len(a[0]) == 1 || len(b[0]) == 1) && a[0][0] == b[0][0] {
+		return initialOnly, "initial match"
+	}
+	for _, n1 := range a {
+		for _, n2 := range b {
+			if len(n1) > 1 && n1 == n2 {
+				return reorderedNames, "partial match (reordered or missing names)"
+			}
+		}
+	}
+	return noMatch, "no match"
+}
+
+// compareName compares two normalised names, allowing for a single mistyped or transposed character and phonetic equivalence
+func compareName(a, b string) (float64, string) {
+	switch {
+	case a == b:
+		return exactMatch, "exact match"
+	case len(a) > 3 && len(b) > 3 && editDistance(a, b) == 1:
+		return singleEdit, "single character difference or transposition"
+	case soundex(a) == soundex(b) && len(a) > 1 && len(b) > 1:
+		return phonetic, "phonetic match"
+	}
+	return noMatch, "no match"
+}
+
+// compareBirthDates compares the birth dates of two patients, allowing for transposed day and month, and a single incorrect component
+func compareBirthDates(pt, other *Patient) (float64, string) {
+	if !pt.GetBirthDate().IsValid() || !other.GetBirthDate().IsValid() {
+		return noMatch, "invalid date"
+	}
+	y1, m1, d1 := pt.GetBirthDate().GetYear(), pt.GetBirthDate().GetMonth(), pt.GetBirthDate().GetDay()
+	y2, m2, d2 := other.GetBirthDate().GetYear(), other.GetBirthDate().GetMonth(), other.GetBirthDate().GetDay()
+	same := 0
+	for _, eq := range []bool{y1 == y2, m1 == m2, d1 == d2} {
+		if eq {
+			same++
+		}
+	}
+	switch {
+	case same == 3:
+		return exactMatch, "exact match"
+	case y1 == y2 && m1 == d2 && d1 == m2:
+		return transposedDate, "day and month transposed"
+	case same == 2:
+		return partialDate, "partial match (one of day, month or year differs)"
+	}
+	return noMatch, "no match"
+}
+
+// editDistance returns the optimal string alignment distance between two strings, being the number of
+// insertions, deletions, substitutions or transpositions of adjacent characters needed to make them equal
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+// soundexCodes are the Soundex digits for each letter A-Z; 0 for vowels, and H, W and Y, which are not coded
+var soundexCodes = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', '0', '0', '2', '2', '4', '5', '5', '0', '1', '2', '6', '2', '3', '0', '1', '0', '2', '0', '2',
+}
+
+// soundex returns the American Soundex code for a normalised name e.g. "R163" for "ROBERT" and "RUPERT"
+func soundex(s string) string {
+	var b []byte
+	var last byte
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		code := soundexCodes[r-'A']
+		if len(b) == 0 {
+			b = append(b, byte(r))
+		} else if code != '0' && code != last {
+			b = append(b, code)
+		}
+		if r != 'H' && r != 'W' { // H and W do not separate letters with the same code
+			last = code
+		}
+		if len(b) == 4 {
+			break
+		}
+	}
+	if len(b) == 0 {
+		return ""
+	}
+	for len(b) < 4 {
+		b = append(b, '0')
+	}
+	return string(b)
+}
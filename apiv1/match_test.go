@@ -0,0 +1,76 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+)
+
+const nhsNumber = "https://fhir.nhs.uk/Id/nhs-number"
+
+func testPatient(lastname, firstnames string, year int, month time.Month, day int, gender Gender) *Patient {
+	return &Patient{
+		Lastname:    lastname,
+		Firstnames:  firstnames,
+		BirthDate:   &Date{Year: int32(year), Month: int32(month), Day: int32(day)},
+		Gender:      gender,
+		Identifiers: []*Identifier{{System: nhsNumber, Value: "1111111111"}},
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	pt := testPatient("Jones-Smith", "Wendy Anne", 1980, time.April, 3, Gender_FEMALE)
+	tests := []struct {
+		name  string
+		other *Patient
+		match bool
+	}{
+		{"identical", testPatient("JONES-SMITH", "WENDY ANNE", 1980, time.April, 3, Gender_FEMALE), true},
+		{"punctuation", testPatient("Jones Smith", "Wendy", 1980, time.April, 3, Gender_FEMALE), true},
+		{"double-barrelled", testPatient("Smith", "Wendy", 1980, time.April, 3, Gender_FEMALE), true},
+		{"transposition", testPatient("Jones-Simth", "Wnedy", 1980, time.April, 3, Gender_FEMALE), true},
+		{"initial", testPatient("Jones-Smith", "W", 1980, time.April, 3, Gender_FEMALE), true},
+		{"transposed date", testPatient("Jones-Smith", "Wendy", 1980, time.March, 4, Gender_FEMALE), true},
+		{"different person", testPatient("Evans", "Gareth", 1980, time.April, 3, Gender_MALE), false},
+		{"different birth date", testPatient("Jones-Smith", "Wendy", 1975, time.November, 21, Gender_FEMALE), false},
+	}
+	for _, test := range tests {
+		result := pt.MatchScore(test.other, []string{nhsNumber})
+		if (result.Score >= DefaultMatchThreshold) != test.match || pt.Match(test.other, []string{nhsNumber}) != test.match {
+			t.Errorf("%s: expected match %v, got %s", test.name, test.match, result)
+		}
+		if len(result.Reasons) == 0 {
+			t.Errorf("%s: missing reasons", test.name)
+		}
+	}
+	conflicting := testPatient("Jones-Smith", "Wendy", 1980, time.April, 3, Gender_FEMALE)
+	conflicting.Identifiers[0].Value = "2222222222"
+	if result := pt.MatchScore(conflicting, []string{nhsNumber}); result.Score != 0 {
+		t.Errorf("expected conflicting identifiers not to match, got %s", result)
+	}
+	if result := pt.MatchScore(&Patient{Lastname: "Jones-Smith"}, nil); result.Score != 0 {
+		t.Errorf("expected insufficient demographics not to match, got %s", result)
+	}
+}
+
+func TestSoundex(t *testing.T) {
+	tests := map[string]string{"ROBERT": "R163", "RUPERT": "R163", "RUBIN": "R150", "ASHCRAFT": "A261", "TYMCZAK": "T522", "PFISTER": "P236", "LEE": "L000"}
+	for name, expected := range tests {
+		if code := soundex(name); code != expected {
+			t.Errorf("soundex(%s): expected %s, got %s", name, expected, code)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"SMITH", "SMITH", 0}, {"SMITH", "SMIHT", 1}, {"SMITH", "SMYTH", 1}, {"SMITH", "SMITHE", 1}, {"SMITH", "JONES", 5},
+	}
+	for _, test := range tests {
+		if d := editDistance(test.a, test.b); d != test.expected {
+			t.Errorf("editDistance(%s, %s): expected %d, got %d", test.a, test.b, test.expected, d)
+		}
+	}
+}
@@ -26,6 +26,75 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+// NHSNumberVerificationStatus is the NHS number status indicator, as defined in the NHS Data Dictionary.
+// Values correspond to the status indicator codes (e.g. 01 - number present and verified).
+type NHSNumberVerificationStatus int32
+
+const (
+	NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN    NHSNumberVerificationStatus = 0
+	NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED          NHSNumberVerificationStatus = 1 // number present and verified
+	NHSNumberVerificationStatus_NHS_NUMBER_NOT_TRACED        NHSNumberVerificationStatus = 2 // number present but not traced
+	NHSNumberVerificationStatus_NHS_NUMBER_TRACE_REQUIRED    NHSNumberVerificationStatus = 3 // trace required
+	NHSNumberVerificationStatus_NHS_NUMBER_TRACE_NO_MATCH    NHSNumberVerificationStatus = 4 // trace attempted - no match or multiple match found
+	NHSNumberVerificationStatus_NHS_NUMBER_TRACE_UNRESOLVED  NHSNumberVerificationStatus = 5 // trace needs to be resolved - NHS number or patient detail conflict
+	NHSNumberVerificationStatus_NHS_NUMBER_TRACE_IN_PROGRESS NHSNumberVerificationStatus = 6 // trace in progress
+	NHSNumberVerificationStatus_NHS_NUMBER_NOT_PRESENT       NHSNumberVerificationStatus = 7 // number not present and trace not required
+	NHSNumberVerificationStatus_NHS_NUMBER_TRACE_POSTPONED   NHSNumberVerificationStatus = 8 // trace postponed (baby under six weeks old)
+)
+
+// Enum value maps for NHSNumberVerificationStatus.
+var (
+	NHSNumberVerificationStatus_name = map[int32]string{
+		0: "NHS_NUMBER_STATUS_UNKNOWN",
+		1: "NHS_NUMBER_VERIFIED",
+		2: "NHS_NUMBER_NOT_TRACED",
+		3: "NHS_NUMBER_TRACE_REQUIRED",
+		4: "NHS_NUMBER_TRACE_NO_MATCH",
+		5: "NHS_NUMBER_TRACE_UNRESOLVED",
+		6: "NHS_NUMBER_TRACE_IN_PROGRESS",
+		7: "NHS_NUMBER_NOT_PRESENT",
+		8: "NHS_NUMBER_TRACE_POSTPONED",
+	}
+	NHSNumberVerificationStatus_value = map[string]int32{
+		"NHS_NUMBER_STATUS_UNKNOWN":    0,
+		"NHS_NUMBER_VERIFIED":          1,
+		"NHS_NUMBER_NOT_TRACED":        2,
+		"NHS_NUMBER_TRACE_REQUIRED":    3,
+		"NHS_NUMBER_TRACE_NO_MATCH":    4,
+		"NHS_NUMBER_TRACE_UNRESOLVED":  5,
+		"NHS_NUMBER_TRACE_IN_PROGRESS": 6,
+		"NHS_NUMBER_NOT_PRESENT":       7,
+		"NHS_NUMBER_TRACE_POSTPONED":   8,
+	}
+)
+
+func (x NHSNumberVerificationStatus) Enum() *NHSNumberVerificationStatus {
+	p := new(NHSNumberVerificationStatus)
+	*p = x
+	return p
+}
+
+func (x NHSNumberVerificationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NHSNumberVerificationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_proto_enumTypes[0].Descriptor()
+}
+
+func (NHSNumberVerificationStatus) Type() protoreflect.EnumType {
+	return &file_model_proto_enumTypes[0]
+}
+
+func (x NHSNumberVerificationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NHSNumberVerificationStatus.Descriptor instead.
+func (NHSNumberVerificationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{0}
+}
+
 type Gender int32
 
 const (
@@ -59,11 +128,11 @@ func (x Gender) String() string {
 }
 
 func (Gender) Descriptor() protoreflect.EnumDescriptor {
-	return file_model_proto_enumTypes[0].Descriptor()
+	return file_model_proto_enumTypes[1].Descriptor()
 }
 
 func (Gender) Type() protoreflect.EnumType {
-	return &file_model_proto_enumTypes[0]
+	return &file_model_proto_enumTypes[1]
 }
 
 func (x Gender) Number() protoreflect.EnumNumber {
@@ -72,7 +141,117 @@ func (x Gender) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Gender.Descriptor instead.
 func (Gender) EnumDescriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{0}
+	return file_model_proto_rawDescGZIP(), []int{1}
+}
+
+type Address_Use int32
+
+const (
+	Address_UNKNOWN        Address_Use = 0
+	Address_HOME           Address_Use = 1 // home or permanent address (HL7 H, P or L)
+	Address_TEMPORARY      Address_Use = 2 // current or temporary address (HL7 C)
+	Address_CORRESPONDENCE Address_Use = 3 // mailing address, if different from home (HL7 M)
+	Address_WORK           Address_Use = 4 // business or office address (HL7 B or O)
+	Address_OLD            Address_Use = 5 // bad address, no longer in use (HL7 BA)
+)
+
+// Enum value maps for Address_Use.
+var (
+	Address_Use_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "HOME",
+		2: "TEMPORARY",
+		3: "CORRESPONDENCE",
+		4: "WORK",
+		5: "OLD",
+	}
+	Address_Use_value = map[string]int32{
+		"UNKNOWN":        0,
+		"HOME":           1,
+		"TEMPORARY":      2,
+		"CORRESPONDENCE": 3,
+		"WORK":           4,
+		"OLD":            5,
+	}
+)
+
+func (x Address_Use) Enum() *Address_Use {
+	p := new(Address_Use)
+	*p = x
+	return p
+}
+
+func (x Address_Use) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Address_Use) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_proto_enumTypes[2].Descriptor()
+}
+
+func (Address_Use) Type() protoreflect.EnumType {
+	return &file_model_proto_enumTypes[2]
+}
+
+func (x Address_Use) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Address_Use.Descriptor instead.
+func (Address_Use) EnumDescriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type Telephone_Use int32
+
+const (
+	Telephone_UNKNOWN Telephone_Use = 0
+	Telephone_HOME    Telephone_Use = 1 // home telephone (HL7 PRN, ORN or VHN)
+	Telephone_WORK    Telephone_Use = 2 // work telephone (HL7 WPN)
+	Telephone_MOBILE  Telephone_Use = 3 // mobile telephone, for any use (HL7 equipment type CP)
+)
+
+// Enum value maps for Telephone_Use.
+var (
+	Telephone_Use_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "HOME",
+		2: "WORK",
+		3: "MOBILE",
+	}
+	Telephone_Use_value = map[string]int32{
+		"UNKNOWN": 0,
+		"HOME":    1,
+		"WORK":    2,
+		"MOBILE":  3,
+	}
+)
+
+func (x Telephone_Use) Enum() *Telephone_Use {
+	p := new(Telephone_Use)
+	*p = x
+	return p
+}
+
+func (x Telephone_Use) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Telephone_Use) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_proto_enumTypes[3].Descriptor()
+}
+
+func (Telephone_Use) Type() protoreflect.EnumType {
+	return &file_model_proto_enumTypes[3]
+}
+
+func (x Telephone_Use) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Telephone_Use.Descriptor instead.
+func (Telephone_Use) EnumDescriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{6, 0}
 }
 
 type HumanName_Use int32
@@ -123,11 +302,11 @@ func (x HumanName_Use) String() string {
 }
 
 func (HumanName_Use) Descriptor() protoreflect.EnumDescriptor {
-	return file_model_proto_enumTypes[1].Descriptor()
+	return file_model_proto_enumTypes[4].Descriptor()
 }
 
 func (HumanName_Use) Type() protoreflect.EnumType {
-	return &file_model_proto_enumTypes[1]
+	return &file_model_proto_enumTypes[4]
 }
 
 func (x HumanName_Use) Number() protoreflect.EnumNumber {
@@ -136,7 +315,7 @@ func (x HumanName_Use) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HumanName_Use.Descriptor instead.
 func (HumanName_Use) EnumDescriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{5, 0}
+	return file_model_proto_rawDescGZIP(), []int{7, 0}
 }
 
 type Document_Status int32
@@ -178,11 +357,11 @@ func (x Document_Status) String() string {
 }
 
 func (Document_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_model_proto_enumTypes[2].Descriptor()
+	return file_model_proto_enumTypes[5].Descriptor()
 }
 
 func (Document_Status) Type() protoreflect.EnumType {
-	return &file_model_proto_enumTypes[2]
+	return &file_model_proto_enumTypes[5]
 }
 
 func (x Document_Status) Number() protoreflect.EnumNumber {
@@ -191,7 +370,180 @@ func (x Document_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Document_Status.Descriptor instead.
 func (Document_Status) EnumDescriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{14, 0}
+	return file_model_proto_rawDescGZIP(), []int{25, 0}
+}
+
+// Sensitivity is the confidentiality of a document, corresponding to HL7 confidentiality codes
+type Document_Sensitivity int32
+
+const (
+	Document_NORMAL          Document_Sensitivity = 0 // no additional restrictions (N)
+	Document_RESTRICTED      Document_Sensitivity = 1 // restricted e.g. sexual health, mental health (R)
+	Document_VERY_RESTRICTED Document_Sensitivity = 2 // very restricted e.g. safeguarding (V)
+)
+
+// Enum value maps for Document_Sensitivity.
+var (
+	Document_Sensitivity_name = map[int32]string{
+		0: "NORMAL",
+		1: "RESTRICTED",
+		2: "VERY_RESTRICTED",
+	}
+	Document_Sensitivity_value = map[string]int32{
+		"NORMAL":          0,
+		"RESTRICTED":      1,
+		"VERY_RESTRICTED": 2,
+	}
+)
+
+func (x Document_Sensitivity) Enum() *Document_Sensitivity {
+	p := new(Document_Sensitivity)
+	*p = x
+	return p
+}
+
+func (x Document_Sensitivity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Document_Sensitivity) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_proto_enumTypes[6].Descriptor()
+}
+
+func (Document_Sensitivity) Type() protoreflect.EnumType {
+	return &file_model_proto_enumTypes[6]
+}
+
+func (x Document_Sensitivity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Document_Sensitivity.Descriptor instead.
+func (Document_Sensitivity) EnumDescriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{25, 1}
+}
+
+type Appointment_Status int32
+
+const (
+	Appointment_UNKNOWN   Appointment_Status = 0
+	Appointment_FREE      Appointment_Status = 1 // slot available for booking
+	Appointment_BOOKED    Appointment_Status = 2 // slot booked for a patient
+	Appointment_ATTENDED  Appointment_Status = 3 // patient attended
+	Appointment_CANCELLED Appointment_Status = 4 // appointment cancelled
+	Appointment_DNA       Appointment_Status = 5 // patient did not attend
+)
+
+// Enum value maps for Appointment_Status.
+var (
+	Appointment_Status_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "FREE",
+		2: "BOOKED",
+		3: "ATTENDED",
+		4: "CANCELLED",
+		5: "DNA",
+	}
+	Appointment_Status_value = map[string]int32{
+		"UNKNOWN":   0,
+		"FREE":      1,
+		"BOOKED":    2,
+		"ATTENDED":  3,
+		"CANCELLED": 4,
+		"DNA":       5,
+	}
+)
+
+func (x Appointment_Status) Enum() *Appointment_Status {
+	p := new(Appointment_Status)
+	*p = x
+	return p
+}
+
+func (x Appointment_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Appointment_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_proto_enumTypes[7].Descriptor()
+}
+
+func (Appointment_Status) Type() protoreflect.EnumType {
+	return &file_model_proto_enumTypes[7]
+}
+
+func (x Appointment_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Appointment_Status.Descriptor instead.
+func (Appointment_Status) EnumDescriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{26, 0}
+}
+
+// Date is a calendar date, such as a date of birth, without a time or time zone, so that it is the same date
+// wherever it is used. This is equivalent to google.type.Date.
+type Date struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year  int32 `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`   // year, from 1 to 9999
+	Month int32 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"` // month of the year, from 1 to 12
+	Day   int32 `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`     // day of the month, from 1 to 31
+}
+
+func (x *Date) Reset() {
+	*x = Date{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Date) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Date) ProtoMessage() {}
+
+func (x *Date) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Date.ProtoReflect.Descriptor instead.
+func (*Date) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Date) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Date) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *Date) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
 }
 
 type Patient struct {
@@ -199,27 +551,34 @@ type Patient struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Lastname   string               `protobuf:"bytes,1,opt,name=lastname,proto3" json:"lastname,omitempty"`
-	Firstnames string               `protobuf:"bytes,2,opt,name=firstnames,proto3" json:"firstnames,omitempty"`
-	Title      string               `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Gender     Gender               `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
-	BirthDate  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Lastname   string `protobuf:"bytes,1,opt,name=lastname,proto3" json:"lastname,omitempty"`
+	Firstnames string `protobuf:"bytes,2,opt,name=firstnames,proto3" json:"firstnames,omitempty"`
+	Title      string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Gender     Gender `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
+	BirthDate  *Date  `protobuf:"bytes,23,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
 	// Types that are assignable to Deceased:
 	//	*Patient_DeceasedDate
 	//	*Patient_DeceasedBoolean
-	Deceased            isPatient_Deceased `protobuf_oneof:"deceased"`
-	Surgery             string             `protobuf:"bytes,8,opt,name=surgery,proto3" json:"surgery,omitempty"`                                                    // TODO: fix to reference from ODS abstraction
-	GeneralPractitioner string             `protobuf:"bytes,9,opt,name=general_practitioner,json=generalPractitioner,proto3" json:"general_practitioner,omitempty"` // TODO: fix to reference from ODS abstraction
-	Identifiers         []*Identifier      `protobuf:"bytes,10,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
-	Addresses           []*Address         `protobuf:"bytes,11,rep,name=addresses,proto3" json:"addresses,omitempty"`
-	Telephones          []*Telephone       `protobuf:"bytes,12,rep,name=telephones,proto3" json:"telephones,omitempty"`
-	Emails              []string           `protobuf:"bytes,13,rep,name=emails,proto3" json:"emails,omitempty"`
+	Deceased                    isPatient_Deceased          `protobuf_oneof:"deceased"`
+	Identifiers                 []*Identifier               `protobuf:"bytes,10,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
+	Addresses                   []*Address                  `protobuf:"bytes,11,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Telephones                  []*Telephone                `protobuf:"bytes,12,rep,name=telephones,proto3" json:"telephones,omitempty"`
+	Emails                      []string                    `protobuf:"bytes,13,rep,name=emails,proto3" json:"emails,omitempty"`
+	Provenance                  []*Provenance               `protobuf:"bytes,14,rep,name=provenance,proto3" json:"provenance,omitempty"`                                                                                                                  // source of each field, for patients merged from multiple backends
+	NhsNumberVerificationStatus NHSNumberVerificationStatus `protobuf:"varint,15,opt,name=nhs_number_verification_status,json=nhsNumberVerificationStatus,proto3,enum=apiv1.NHSNumberVerificationStatus" json:"nhs_number_verification_status,omitempty"` // verification status of the patient's NHS number
+	PreferredLanguage           string                      `protobuf:"bytes,16,opt,name=preferred_language,json=preferredLanguage,proto3" json:"preferred_language,omitempty"`                                                                           // preferred language of the patient, as a BCP 47 language tag e.g. "cy" for Welsh
+	EthnicCategory              *Identifier                 `protobuf:"bytes,17,opt,name=ethnic_category,json=ethnicCategory,proto3" json:"ethnic_category,omitempty"`                                                                                    // e.g. https://fhir.hl7.org.uk/CareConnect-EthnicCategory-1|A
+	MaritalStatus               *Identifier                 `protobuf:"bytes,18,opt,name=marital_status,json=maritalStatus,proto3" json:"marital_status,omitempty"`                                                                                       // e.g. http://terminology.hl7.org/CodeSystem/v3-MaritalStatus|M
+	Occupation                  string                      `protobuf:"bytes,19,opt,name=occupation,proto3" json:"occupation,omitempty"`
+	CountryOfBirth              string                      `protobuf:"bytes,20,opt,name=country_of_birth,json=countryOfBirth,proto3" json:"country_of_birth,omitempty"`
+	Surgery                     *Identifier                 `protobuf:"bytes,21,opt,name=surgery,proto3" json:"surgery,omitempty"`                                                    // general practice e.g. https://fhir.nhs.uk/Id/ods-organization-code|W95010
+	GeneralPractitioner         *Identifier                 `protobuf:"bytes,22,opt,name=general_practitioner,json=generalPractitioner,proto3" json:"general_practitioner,omitempty"` // e.g. https://fhir.hl7.org.uk/Id/gmp-number|G9342400
 }
 
 func (x *Patient) Reset() {
 	*x = Patient{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[0]
+		mi := &file_model_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -232,7 +591,7 @@ func (x *Patient) String() string {
 func (*Patient) ProtoMessage() {}
 
 func (x *Patient) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[0]
+	mi := &file_model_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,7 +604,7 @@ func (x *Patient) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Patient.ProtoReflect.Descriptor instead.
 func (*Patient) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{0}
+	return file_model_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Patient) GetLastname() string {
@@ -276,7 +635,7 @@ func (x *Patient) GetGender() Gender {
 	return Gender_UNKNOWN
 }
 
-func (x *Patient) GetBirthDate() *timestamp.Timestamp {
+func (x *Patient) GetBirthDate() *Date {
 	if x != nil {
 		return x.BirthDate
 	}
@@ -304,44 +663,93 @@ func (x *Patient) GetDeceasedBoolean() bool {
 	return false
 }
 
-func (x *Patient) GetSurgery() string {
+func (x *Patient) GetIdentifiers() []*Identifier {
 	if x != nil {
-		return x.Surgery
+		return x.Identifiers
 	}
-	return ""
+	return nil
 }
 
-func (x *Patient) GetGeneralPractitioner() string {
+func (x *Patient) GetAddresses() []*Address {
 	if x != nil {
-		return x.GeneralPractitioner
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Patient) GetTelephones() []*Telephone {
+	if x != nil {
+		return x.Telephones
+	}
+	return nil
+}
+
+func (x *Patient) GetEmails() []string {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+func (x *Patient) GetProvenance() []*Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *Patient) GetNhsNumberVerificationStatus() NHSNumberVerificationStatus {
+	if x != nil {
+		return x.NhsNumberVerificationStatus
+	}
+	return NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN
+}
+
+func (x *Patient) GetPreferredLanguage() string {
+	if x != nil {
+		return x.PreferredLanguage
 	}
 	return ""
 }
 
-func (x *Patient) GetIdentifiers() []*Identifier {
+func (x *Patient) GetEthnicCategory() *Identifier {
 	if x != nil {
-		return x.Identifiers
+		return x.EthnicCategory
 	}
 	return nil
 }
 
-func (x *Patient) GetAddresses() []*Address {
+func (x *Patient) GetMaritalStatus() *Identifier {
 	if x != nil {
-		return x.Addresses
+		return x.MaritalStatus
 	}
 	return nil
 }
 
-func (x *Patient) GetTelephones() []*Telephone {
+func (x *Patient) GetOccupation() string {
 	if x != nil {
-		return x.Telephones
+		return x.Occupation
+	}
+	return ""
+}
+
+func (x *Patient) GetCountryOfBirth() string {
+	if x != nil {
+		return x.CountryOfBirth
+	}
+	return ""
+}
+
+func (x *Patient) GetSurgery() *Identifier {
+	if x != nil {
+		return x.Surgery
 	}
 	return nil
 }
 
-func (x *Patient) GetEmails() []string {
+func (x *Patient) GetGeneralPractitioner() *Identifier {
 	if x != nil {
-		return x.Emails
+		return x.GeneralPractitioner
 	}
 	return nil
 }
@@ -362,19 +770,75 @@ func (*Patient_DeceasedDate) isPatient_Deceased() {}
 
 func (*Patient_DeceasedBoolean) isPatient_Deceased() {}
 
-type Period struct {
+// Provenance records the source of a field within a record merged from multiple backends
+type Provenance struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Start *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
-	End   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Field  string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`   // name of the field e.g. "lastname", "addresses"
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // name of the backend from which the value was taken e.g. "empi"
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Provenance) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Provenance) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type Period struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
 }
 
 func (x *Period) Reset() {
 	*x = Period{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[1]
+		mi := &file_model_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -387,7 +851,7 @@ func (x *Period) String() string {
 func (*Period) ProtoMessage() {}
 
 func (x *Period) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[1]
+	mi := &file_model_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,7 +864,7 @@ func (x *Period) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Period.ProtoReflect.Descriptor instead.
 func (*Period) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{1}
+	return file_model_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Period) GetStart() *timestamp.Timestamp {
@@ -429,7 +893,7 @@ type Identifier struct {
 func (x *Identifier) Reset() {
 	*x = Identifier{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[2]
+		mi := &file_model_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -442,7 +906,7 @@ func (x *Identifier) String() string {
 func (*Identifier) ProtoMessage() {}
 
 func (x *Identifier) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[2]
+	mi := &file_model_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,7 +919,7 @@ func (x *Identifier) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Identifier.ProtoReflect.Descriptor instead.
 func (*Identifier) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{2}
+	return file_model_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Identifier) GetSystem() string {
@@ -477,18 +941,20 @@ type Address struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Address1 string  `protobuf:"bytes,1,opt,name=address1,proto3" json:"address1,omitempty"`
-	Address2 string  `protobuf:"bytes,2,opt,name=address2,proto3" json:"address2,omitempty"`
-	Address3 string  `protobuf:"bytes,3,opt,name=address3,proto3" json:"address3,omitempty"`
-	Postcode string  `protobuf:"bytes,4,opt,name=postcode,proto3" json:"postcode,omitempty"`
-	Country  string  `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
-	Period   *Period `protobuf:"bytes,6,opt,name=period,proto3" json:"period,omitempty"`
+	Address1 string      `protobuf:"bytes,1,opt,name=address1,proto3" json:"address1,omitempty"` // street address, including house number or name (HL7 XAD-1)
+	Address2 string      `protobuf:"bytes,2,opt,name=address2,proto3" json:"address2,omitempty"` // other designation, such as locality or district (HL7 XAD-2)
+	Address3 string      `protobuf:"bytes,3,opt,name=address3,proto3" json:"address3,omitempty"` // town or city (HL7 XAD-3)
+	Postcode string      `protobuf:"bytes,4,opt,name=postcode,proto3" json:"postcode,omitempty"` // (HL7 XAD-5)
+	Country  string      `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`   // (HL7 XAD-6)
+	Period   *Period     `protobuf:"bytes,6,opt,name=period,proto3" json:"period,omitempty"`
+	County   string      `protobuf:"bytes,7,opt,name=county,proto3" json:"county,omitempty"`                   // county, state or province (HL7 XAD-4)
+	Use      Address_Use `protobuf:"varint,8,opt,name=use,proto3,enum=apiv1.Address_Use" json:"use,omitempty"` // use of the address e.g. home, temporary or correspondence (HL7 XAD-7)
 }
 
 func (x *Address) Reset() {
 	*x = Address{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[3]
+		mi := &file_model_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -501,7 +967,7 @@ func (x *Address) String() string {
 func (*Address) ProtoMessage() {}
 
 func (x *Address) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[3]
+	mi := &file_model_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,7 +980,7 @@ func (x *Address) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Address.ProtoReflect.Descriptor instead.
 func (*Address) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{3}
+	return file_model_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Address) GetAddress1() string {
@@ -559,19 +1025,34 @@ func (x *Address) GetPeriod() *Period {
 	return nil
 }
 
+func (x *Address) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *Address) GetUse() Address_Use {
+	if x != nil {
+		return x.Use
+	}
+	return Address_UNKNOWN
+}
+
 type Telephone struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number      string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
-	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Number      string        `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Description string        `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`           // free-text description, for display
+	Use         Telephone_Use `protobuf:"varint,3,opt,name=use,proto3,enum=apiv1.Telephone_Use" json:"use,omitempty"` // use of the telephone e.g. home, work or mobile (HL7 XTN-2 and XTN-3)
 }
 
 func (x *Telephone) Reset() {
 	*x = Telephone{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[4]
+		mi := &file_model_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -584,7 +1065,7 @@ func (x *Telephone) String() string {
 func (*Telephone) ProtoMessage() {}
 
 func (x *Telephone) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[4]
+	mi := &file_model_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -597,7 +1078,7 @@ func (x *Telephone) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Telephone.ProtoReflect.Descriptor instead.
 func (*Telephone) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{4}
+	return file_model_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Telephone) GetNumber() string {
@@ -614,6 +1095,13 @@ func (x *Telephone) GetDescription() string {
 	return ""
 }
 
+func (x *Telephone) GetUse() Telephone_Use {
+	if x != nil {
+		return x.Use
+	}
+	return Telephone_UNKNOWN
+}
+
 type HumanName struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -630,7 +1118,7 @@ type HumanName struct {
 func (x *HumanName) Reset() {
 	*x = HumanName{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[5]
+		mi := &file_model_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -643,7 +1131,7 @@ func (x *HumanName) String() string {
 func (*HumanName) ProtoMessage() {}
 
 func (x *HumanName) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[5]
+	mi := &file_model_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -656,7 +1144,7 @@ func (x *HumanName) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HumanName.ProtoReflect.Descriptor instead.
 func (*HumanName) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{5}
+	return file_model_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *HumanName) GetUse() HumanName_Use {
@@ -719,7 +1207,7 @@ type Attachment struct {
 func (x *Attachment) Reset() {
 	*x = Attachment{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[6]
+		mi := &file_model_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -732,7 +1220,7 @@ func (x *Attachment) String() string {
 func (*Attachment) ProtoMessage() {}
 
 func (x *Attachment) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[6]
+	mi := &file_model_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -745,7 +1233,7 @@ func (x *Attachment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
 func (*Attachment) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{6}
+	return file_model_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Attachment) GetContentType() string {
@@ -809,22 +1297,22 @@ type Practitioner struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Identifiers   []*Identifier        `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
-	Active        bool                 `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
-	Names         []*HumanName         `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
-	Gender        Gender               `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
-	BirthDate     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
-	Photos        []*Attachment        `protobuf:"bytes,6,rep,name=photos,proto3" json:"photos,omitempty"`
-	Roles         []*PractitionerRole  `protobuf:"bytes,7,rep,name=roles,proto3" json:"roles,omitempty"`
-	Emails        []string             `protobuf:"bytes,8,rep,name=emails,proto3" json:"emails,omitempty"`
-	Telephones    []*Telephone         `protobuf:"bytes,9,rep,name=telephones,proto3" json:"telephones,omitempty"`
-	WorkAddresses []*Address           `protobuf:"bytes,10,rep,name=work_addresses,json=workAddresses,proto3" json:"work_addresses,omitempty"`
+	Identifiers   []*Identifier       `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
+	Active        bool                `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Names         []*HumanName        `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
+	Gender        Gender              `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
+	BirthDate     *Date               `protobuf:"bytes,11,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Photos        []*Attachment       `protobuf:"bytes,6,rep,name=photos,proto3" json:"photos,omitempty"`
+	Roles         []*PractitionerRole `protobuf:"bytes,7,rep,name=roles,proto3" json:"roles,omitempty"`
+	Emails        []string            `protobuf:"bytes,8,rep,name=emails,proto3" json:"emails,omitempty"`
+	Telephones    []*Telephone        `protobuf:"bytes,9,rep,name=telephones,proto3" json:"telephones,omitempty"`
+	WorkAddresses []*Address          `protobuf:"bytes,10,rep,name=work_addresses,json=workAddresses,proto3" json:"work_addresses,omitempty"`
 }
 
 func (x *Practitioner) Reset() {
 	*x = Practitioner{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[7]
+		mi := &file_model_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -837,7 +1325,7 @@ func (x *Practitioner) String() string {
 func (*Practitioner) ProtoMessage() {}
 
 func (x *Practitioner) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[7]
+	mi := &file_model_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -850,7 +1338,7 @@ func (x *Practitioner) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Practitioner.ProtoReflect.Descriptor instead.
 func (*Practitioner) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{7}
+	return file_model_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Practitioner) GetIdentifiers() []*Identifier {
@@ -881,7 +1369,7 @@ func (x *Practitioner) GetGender() Gender {
 	return Gender_UNKNOWN
 }
 
-func (x *Practitioner) GetBirthDate() *timestamp.Timestamp {
+func (x *Practitioner) GetBirthDate() *Date {
 	if x != nil {
 		return x.BirthDate
 	}
@@ -928,14 +1416,15 @@ type PractitionerRole struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Role   *Role   `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Period *Period `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	Role         *Role       `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Period       *Period     `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	Organisation *Identifier `protobuf:"bytes,3,opt,name=organisation,proto3" json:"organisation,omitempty"` // organisation in which the role is performed, eg https://fhir.nhs.uk/Id/ods-organization-code|7A4
 }
 
 func (x *PractitionerRole) Reset() {
 	*x = PractitionerRole{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[8]
+		mi := &file_model_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -948,7 +1437,7 @@ func (x *PractitionerRole) String() string {
 func (*PractitionerRole) ProtoMessage() {}
 
 func (x *PractitionerRole) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[8]
+	mi := &file_model_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -961,7 +1450,7 @@ func (x *PractitionerRole) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PractitionerRole.ProtoReflect.Descriptor instead.
 func (*PractitionerRole) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{8}
+	return file_model_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *PractitionerRole) GetRole() *Role {
@@ -978,6 +1467,13 @@ func (x *PractitionerRole) GetPeriod() *Period {
 	return nil
 }
 
+func (x *PractitionerRole) GetOrganisation() *Identifier {
+	if x != nil {
+		return x.Organisation
+	}
+	return nil
+}
+
 type Role struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -991,7 +1487,7 @@ type Role struct {
 func (x *Role) Reset() {
 	*x = Role{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[9]
+		mi := &file_model_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1004,7 +1500,7 @@ func (x *Role) String() string {
 func (*Role) ProtoMessage() {}
 
 func (x *Role) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[9]
+	mi := &file_model_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1017,7 +1513,7 @@ func (x *Role) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Role.ProtoReflect.Descriptor instead.
 func (*Role) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{9}
+	return file_model_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Role) GetIdentifier() *Identifier {
@@ -1041,34 +1537,38 @@ func (x *Role) GetDeprecated() bool {
 	return false
 }
 
-// System represents a system for identifiers.
-type System struct {
+// Organisation represents an organisation, such as a general practice, hospital or health board
+type Organisation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name            string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Uri             string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
-	MoreInformation string `protobuf:"bytes,3,opt,name=more_information,json=moreInformation,proto3" json:"more_information,omitempty"`
+	Identifiers []*Identifier       `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"` // e.g. https://fhir.nhs.uk/Id/ods-organization-code|W93036
+	Name        string              `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Active      bool                `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"` // operational status
+	Addresses   []*Address          `protobuf:"bytes,4,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Telephones  []*Telephone        `protobuf:"bytes,5,rep,name=telephones,proto3" json:"telephones,omitempty"`
+	Roles       []*OrganisationRole `protobuf:"bytes,6,rep,name=roles,proto3" json:"roles,omitempty"`
+	Period      *Period             `protobuf:"bytes,7,opt,name=period,proto3" json:"period,omitempty"` // operational period
 }
 
-func (x *System) Reset() {
-	*x = System{}
+func (x *Organisation) Reset() {
+	*x = Organisation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[10]
+		mi := &file_model_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *System) String() string {
+func (x *Organisation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*System) ProtoMessage() {}
+func (*Organisation) ProtoMessage() {}
 
-func (x *System) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[10]
+func (x *Organisation) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1079,60 +1579,90 @@ func (x *System) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use System.ProtoReflect.Descriptor instead.
-func (*System) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use Organisation.ProtoReflect.Descriptor instead.
+func (*Organisation) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *System) GetName() string {
+func (x *Organisation) GetIdentifiers() []*Identifier {
+	if x != nil {
+		return x.Identifiers
+	}
+	return nil
+}
+
+func (x *Organisation) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *System) GetUri() string {
+func (x *Organisation) GetActive() bool {
 	if x != nil {
-		return x.Uri
+		return x.Active
 	}
-	return ""
+	return false
 }
 
-func (x *System) GetMoreInformation() string {
+func (x *Organisation) GetAddresses() []*Address {
 	if x != nil {
-		return x.MoreInformation
+		return x.Addresses
 	}
-	return ""
+	return nil
 }
 
-// LoginRequest requests authentication for the (service account/user account) using the (secret/password) specified.
-// An authentication request for a user account will usually need to be submitted with a token from a service account.
-type LoginRequest struct {
+func (x *Organisation) GetTelephones() []*Telephone {
+	if x != nil {
+		return x.Telephones
+	}
+	return nil
+}
+
+func (x *Organisation) GetRoles() []*OrganisationRole {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Organisation) GetPeriod() *Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+// OrganisationRole is a role of an organisation e.g. "GP PRACTICE"
+type OrganisationRole struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User     *Identifier `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Password string      `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Identifier *Identifier `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"` // e.g. https://directory.spineservices.nhs.uk/STU3/CodeSystem/ODSAPI-OrganizationRole-1|RO76
+	Name       string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`             // e.g. "GP PRACTICE"
+	Primary    bool        `protobuf:"varint,3,opt,name=primary,proto3" json:"primary,omitempty"`      // whether this is the organisation's primary role
+	Active     bool        `protobuf:"varint,4,opt,name=active,proto3" json:"active,omitempty"`
+	Period     *Period     `protobuf:"bytes,5,opt,name=period,proto3" json:"period,omitempty"` // operational period
 }
 
-func (x *LoginRequest) Reset() {
-	*x = LoginRequest{}
+func (x *OrganisationRole) Reset() {
+	*x = OrganisationRole{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[11]
+		mi := &file_model_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LoginRequest) String() string {
+func (x *OrganisationRole) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginRequest) ProtoMessage() {}
+func (*OrganisationRole) ProtoMessage() {}
 
-func (x *LoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[11]
+func (x *OrganisationRole) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1143,89 +1673,317 @@ func (x *LoginRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
-func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use OrganisationRole.ProtoReflect.Descriptor instead.
+func (*OrganisationRole) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *LoginRequest) GetUser() *Identifier {
+func (x *OrganisationRole) GetIdentifier() *Identifier {
 	if x != nil {
-		return x.User
+		return x.Identifier
 	}
 	return nil
 }
 
-func (x *LoginRequest) GetPassword() string {
+func (x *OrganisationRole) GetName() string {
 	if x != nil {
-		return x.Password
+		return x.Name
 	}
 	return ""
 }
 
-type TokenRefreshRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-}
-
-func (x *TokenRefreshRequest) Reset() {
-	*x = TokenRefreshRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[12]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *OrganisationRole) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
 	}
+	return false
 }
 
-func (x *TokenRefreshRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*TokenRefreshRequest) ProtoMessage() {}
-
-func (x *TokenRefreshRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[12]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *OrganisationRole) GetActive() bool {
+	if x != nil {
+		return x.Active
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use TokenRefreshRequest.ProtoReflect.Descriptor instead.
-func (*TokenRefreshRequest) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{12}
+func (x *OrganisationRole) GetPeriod() *Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
 }
 
-// LoginResponse is returned for a valid authentication
-type LoginResponse struct {
+// System represents a system for identifiers.
+type System struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Name            string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Uri             string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	MoreInformation string `protobuf:"bytes,3,opt,name=more_information,json=moreInformation,proto3" json:"more_information,omitempty"`
 }
 
-func (x *LoginResponse) Reset() {
-	*x = LoginResponse{}
+func (x *System) Reset() {
+	*x = System{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[13]
+		mi := &file_model_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LoginResponse) String() string {
+func (x *System) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginResponse) ProtoMessage() {}
+func (*System) ProtoMessage() {}
 
-func (x *LoginResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[13]
+func (x *System) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use System.ProtoReflect.Descriptor instead.
+func (*System) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *System) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *System) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *System) GetMoreInformation() string {
+	if x != nil {
+		return x.MoreInformation
+	}
+	return ""
+}
+
+// LoginRequest requests authentication for the (service account/user account) using the (secret/password) specified.
+// An authentication request for a user account will usually need to be submitted with a token from a service account.
+type LoginRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User     *Identifier `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Password string      `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LoginRequest) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type TokenRefreshRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *TokenRefreshRequest) Reset() {
+	*x = TokenRefreshRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenRefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenRefreshRequest) ProtoMessage() {}
+
+func (x *TokenRefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenRefreshRequest.ProtoReflect.Descriptor instead.
+func (*TokenRefreshRequest) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{16}
+}
+
+// LogoutRequest requests revocation of the current authentication token
+type LogoutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AllSessions bool `protobuf:"varint,1,opt,name=all_sessions,json=allSessions,proto3" json:"all_sessions,omitempty"` // revoke all tokens issued to the authenticated user, not only the current token
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LogoutRequest) GetAllSessions() bool {
+	if x != nil {
+		return x.AllSessions
+	}
+	return false
+}
+
+type LogoutResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{18}
+}
+
+// LoginResponse is returned for a valid authentication
+type LoginResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1238,54 +1996,678 @@ func (x *LoginResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
 func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{13}
+	return file_model_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// RoleAssignment represents the assignment of a role to a user
+type RoleAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *Identifier `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Role string      `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *RoleAssignment) Reset() {
+	*x = RoleAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoleAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleAssignment) ProtoMessage() {}
+
+func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleAssignment.ProtoReflect.Descriptor instead.
+func (*RoleAssignment) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RoleAssignment) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *RoleAssignment) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// RoleAssignments lists the roles assigned to a user
+type RoleAssignments struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User  *Identifier `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Roles []string    `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+}
+
+func (x *RoleAssignments) Reset() {
+	*x = RoleAssignments{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoleAssignments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleAssignments) ProtoMessage() {}
+
+func (x *RoleAssignments) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleAssignments.ProtoReflect.Descriptor instead.
+func (*RoleAssignments) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RoleAssignments) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *RoleAssignments) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+// ServiceAccount is an account used by a service to login, within the namespace https://concierge.eldrix.com/Id/service-user
+type ServiceAccount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User     *Identifier          `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Roles    []string             `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	Expires  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=expires,proto3" json:"expires,omitempty"` // when the account expires, if it expires
+	Disabled bool                 `protobuf:"varint,4,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	Created  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	Rotated  *timestamp.Timestamp `protobuf:"bytes,6,opt,name=rotated,proto3" json:"rotated,omitempty"`   // when the password was last rotated, if ever
+	Password string               `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"` // generated password, returned only when the account is created or its password rotated
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ServiceAccount) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetExpires() *timestamp.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *ServiceAccount) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetRotated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Rotated
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// CreateServiceAccountRequest is a request to create a service account with a generated password
+type CreateServiceAccountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User    *Identifier          `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Roles   []string             `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`     // roles granting the scopes permitted to the account
+	Expires *timestamp.Timestamp `protobuf:"bytes,3,opt,name=expires,proto3" json:"expires,omitempty"` // when the account expires; never if omitted
+}
+
+func (x *CreateServiceAccountRequest) Reset() {
+	*x = CreateServiceAccountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountRequest) ProtoMessage() {}
+
+func (x *CreateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateServiceAccountRequest) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountRequest) GetExpires() *timestamp.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+// RotateSecretRequest is a request to replace the password of a service account with a generated password
+type RotateSecretRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User    *Identifier          `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Expires *timestamp.Timestamp `protobuf:"bytes,2,opt,name=expires,proto3" json:"expires,omitempty"` // new expiry of the account; unchanged if omitted
+}
+
+func (x *RotateSecretRequest) Reset() {
+	*x = RotateSecretRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSecretRequest) ProtoMessage() {}
+
+func (x *RotateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RotateSecretRequest) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *RotateSecretRequest) GetExpires() *timestamp.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type Document struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              *Identifier          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                  // unique identifier for this document, value typically being a UUID but some implementations will use system/primarykey approach
+	Patient         *Patient             `protobuf:"bytes,2,opt,name=patient,proto3" json:"patient,omitempty"`                                        // patient to which this refers -
+	Status          Document_Status      `protobuf:"varint,3,opt,name=status,proto3,enum=apiv1.Document_Status" json:"status,omitempty"`              // status of this document
+	Authors         []*Identifier        `protobuf:"bytes,4,rep,name=authors,proto3" json:"authors,omitempty"`                                        // author(s) of the document
+	SignedBy        []*Identifier        `protobuf:"bytes,5,rep,name=signed_by,json=signedBy,proto3" json:"signed_by,omitempty"`                      // signed by - may be author or multiple, of course
+	Responsible     []*Identifier        `protobuf:"bytes,6,rep,name=responsible,proto3" json:"responsible,omitempty"`                                // responsible author(s) (e.g. consultant)
+	Administrator   *Identifier          `protobuf:"bytes,7,opt,name=administrator,proto3" json:"administrator,omitempty"`                            // administrator/typed/prepared by  (may be same as author)
+	Encounter       *Identifier          `protobuf:"bytes,8,opt,name=encounter,proto3" json:"encounter,omitempty"`                                    // encounter to which this document refers
+	Recipients      []*Identifier        `protobuf:"bytes,9,rep,name=recipients,proto3" json:"recipients,omitempty"`                                  // recipients - e.g. the patient, other practitioners, other teams. Resolution of these is transport specific.
+	Title           string               `protobuf:"bytes,10,opt,name=title,proto3" json:"title,omitempty"`                                           // title (description) of this document
+	DateTime        *timestamp.Timestamp `protobuf:"bytes,11,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`                     // logical date/time of the document - may be the "event" date time
+	TypedDateTime   *timestamp.Timestamp `protobuf:"bytes,12,opt,name=typed_date_time,json=typedDateTime,proto3" json:"typed_date_time,omitempty"`    // when document typed
+	SignedDateTime  *timestamp.Timestamp `protobuf:"bytes,13,opt,name=signed_date_time,json=signedDateTime,proto3" json:"signed_date_time,omitempty"` // when document signed off
+	Data            *Attachment          `protobuf:"bytes,14,opt,name=data,proto3" json:"data,omitempty"`
+	Type            *Identifier          `protobuf:"bytes,15,opt,name=type,proto3" json:"type,omitempty"`                                                // type of document e.g. SNOMED CT 371531000 "report of clinical encounter"
+	Specialty       *Identifier          `protobuf:"bytes,16,opt,name=specialty,proto3" json:"specialty,omitempty"`                                      // specialty to which this document relates e.g. SNOMED CT 394591006 "neurology"
+	Author          *Practitioner        `protobuf:"bytes,17,opt,name=author,proto3" json:"author,omitempty"`                                            // details of the principal author, such as name and role, for repositories that record them
+	EventDateTime   *timestamp.Timestamp `protobuf:"bytes,18,opt,name=event_date_time,json=eventDateTime,proto3" json:"event_date_time,omitempty"`       // date/time of the event to which the document relates e.g. clinic appointment, if different from date_time
+	Sensitivity     Document_Sensitivity `protobuf:"varint,19,opt,name=sensitivity,proto3,enum=apiv1.Document_Sensitivity" json:"sensitivity,omitempty"` // sensitivity (confidentiality) of the document, which may restrict the repositories to which it is published
+	PatientDelivery bool                 `protobuf:"varint,20,opt,name=patient_delivery,json=patientDelivery,proto3" json:"patient_delivery,omitempty"`  // patient has explicitly consented to receive this document directly, e.g. by secure email link or via a patient portal
+}
+
+func (x *Document) Reset() {
+	*x = Document{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Document) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Document) ProtoMessage() {}
+
+func (x *Document) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Document.ProtoReflect.Descriptor instead.
+func (*Document) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Document) GetId() *Identifier {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Document) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+func (x *Document) GetStatus() Document_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Document_UNKNOWN
+}
+
+func (x *Document) GetAuthors() []*Identifier {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *Document) GetSignedBy() []*Identifier {
+	if x != nil {
+		return x.SignedBy
+	}
+	return nil
+}
+
+func (x *Document) GetResponsible() []*Identifier {
+	if x != nil {
+		return x.Responsible
+	}
+	return nil
+}
+
+func (x *Document) GetAdministrator() *Identifier {
+	if x != nil {
+		return x.Administrator
+	}
+	return nil
+}
+
+func (x *Document) GetEncounter() *Identifier {
+	if x != nil {
+		return x.Encounter
+	}
+	return nil
+}
+
+func (x *Document) GetRecipients() []*Identifier {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *Document) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Document) GetDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Document) GetTypedDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.TypedDateTime
+	}
+	return nil
+}
+
+func (x *Document) GetSignedDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.SignedDateTime
+	}
+	return nil
+}
+
+func (x *Document) GetData() *Attachment {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Document) GetType() *Identifier {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Document) GetSpecialty() *Identifier {
+	if x != nil {
+		return x.Specialty
+	}
+	return nil
+}
+
+func (x *Document) GetAuthor() *Practitioner {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *Document) GetEventDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EventDateTime
+	}
+	return nil
 }
 
-func (x *LoginResponse) GetToken() string {
+func (x *Document) GetSensitivity() Document_Sensitivity {
 	if x != nil {
-		return x.Token
+		return x.Sensitivity
+	}
+	return Document_NORMAL
+}
+
+func (x *Document) GetPatientDelivery() bool {
+	if x != nil {
+		return x.PatientDelivery
+	}
+	return false
+}
+
+// Appointment is a slot within a clinic session, which may be booked for a patient
+type Appointment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              *Identifier          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Clinic          *Identifier          `protobuf:"bytes,2,opt,name=clinic,proto3" json:"clinic,omitempty"`
+	Start           *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End             *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	Status          Appointment_Status   `protobuf:"varint,5,opt,name=status,proto3,enum=apiv1.Appointment_Status" json:"status,omitempty"`
+	AppointmentType string               `protobuf:"bytes,6,opt,name=appointment_type,json=appointmentType,proto3" json:"appointment_type,omitempty"` // type of appointment, e.g. "New" or "Follow up"
+	Clinician       *Practitioner        `protobuf:"bytes,7,opt,name=clinician,proto3" json:"clinician,omitempty"`                                    // clinician responsible for the clinic session
+	Patient         *Patient             `protobuf:"bytes,8,opt,name=patient,proto3" json:"patient,omitempty"`                                        // patient, if the slot is booked
+}
+
+func (x *Appointment) Reset() {
+	*x = Appointment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Appointment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Appointment) ProtoMessage() {}
+
+func (x *Appointment) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Appointment.ProtoReflect.Descriptor instead.
+func (*Appointment) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Appointment) GetId() *Identifier {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Appointment) GetClinic() *Identifier {
+	if x != nil {
+		return x.Clinic
+	}
+	return nil
+}
+
+func (x *Appointment) GetStart() *timestamp.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Appointment) GetEnd() *timestamp.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Appointment) GetStatus() Appointment_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Appointment_UNKNOWN
+}
+
+func (x *Appointment) GetAppointmentType() string {
+	if x != nil {
+		return x.AppointmentType
 	}
 	return ""
 }
 
-type Document struct {
+func (x *Appointment) GetClinician() *Practitioner {
+	if x != nil {
+		return x.Clinician
+	}
+	return nil
+}
+
+func (x *Appointment) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+// Clinic is an outpatient clinic
+type Clinic struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id             *Identifier          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                  // unique identifier for this document, value typically being a UUID but some implementations will use system/primarykey approach
-	Patient        *Patient             `protobuf:"bytes,2,opt,name=patient,proto3" json:"patient,omitempty"`                                        // patient to which this refers -
-	Status         Document_Status      `protobuf:"varint,3,opt,name=status,proto3,enum=apiv1.Document_Status" json:"status,omitempty"`              // status of this document
-	Authors        []*Identifier        `protobuf:"bytes,4,rep,name=authors,proto3" json:"authors,omitempty"`                                        // author(s) of the document
-	SignedBy       []*Identifier        `protobuf:"bytes,5,rep,name=signed_by,json=signedBy,proto3" json:"signed_by,omitempty"`                      // signed by - may be author or multiple, of course
-	Responsible    []*Identifier        `protobuf:"bytes,6,rep,name=responsible,proto3" json:"responsible,omitempty"`                                // responsible author(s) (e.g. consultant)
-	Administrator  *Identifier          `protobuf:"bytes,7,opt,name=administrator,proto3" json:"administrator,omitempty"`                            // administrator/typed/prepared by  (may be same as author)
-	Encounter      *Identifier          `protobuf:"bytes,8,opt,name=encounter,proto3" json:"encounter,omitempty"`                                    // encounter to which this document refers
-	Recipients     []*Identifier        `protobuf:"bytes,9,rep,name=recipients,proto3" json:"recipients,omitempty"`                                  // recipients - e.g. the patient, other practitioners, other teams. Resolution of these is transport specific.
-	Title          string               `protobuf:"bytes,10,opt,name=title,proto3" json:"title,omitempty"`                                           // title (description) of this document
-	DateTime       *timestamp.Timestamp `protobuf:"bytes,11,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`                     // logical date/time of the document - may be the "event" date time
-	TypedDateTime  *timestamp.Timestamp `protobuf:"bytes,12,opt,name=typed_date_time,json=typedDateTime,proto3" json:"typed_date_time,omitempty"`    // when document typed
-	SignedDateTime *timestamp.Timestamp `protobuf:"bytes,13,opt,name=signed_date_time,json=signedDateTime,proto3" json:"signed_date_time,omitempty"` // when document signed off
-	Data           *Attachment          `protobuf:"bytes,14,opt,name=data,proto3" json:"data,omitempty"`
+	Id          *Identifier   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                   // e.g. https://fhir.cardiff.wales.nhs.uk/Id/clinic-code|NEUR01
+	Description string        `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // e.g. "Neurology outpatients"
+	Location    string        `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`       // where the clinic is held
+	Specialty   *Identifier   `protobuf:"bytes,4,opt,name=specialty,proto3" json:"specialty,omitempty"`     // main specialty e.g. https://fhir.nhs.uk/CodeSystem/Specialty|400
+	Consultant  *Practitioner `protobuf:"bytes,5,opt,name=consultant,proto3" json:"consultant,omitempty"`   // consultant responsible for the clinic
+	Active      bool          `protobuf:"varint,6,opt,name=active,proto3" json:"active,omitempty"`
 }
 
-func (x *Document) Reset() {
-	*x = Document{}
+func (x *Clinic) Reset() {
+	*x = Clinic{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_model_proto_msgTypes[14]
+		mi := &file_model_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Document) String() string {
+func (x *Clinic) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Document) ProtoMessage() {}
+func (*Clinic) ProtoMessage() {}
 
-func (x *Document) ProtoReflect() protoreflect.Message {
-	mi := &file_model_proto_msgTypes[14]
+func (x *Clinic) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1296,107 +2678,349 @@ func (x *Document) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Document.ProtoReflect.Descriptor instead.
-func (*Document) Descriptor() ([]byte, []int) {
-	return file_model_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use Clinic.ProtoReflect.Descriptor instead.
+func (*Clinic) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *Document) GetId() *Identifier {
+func (x *Clinic) GetId() *Identifier {
 	if x != nil {
 		return x.Id
 	}
 	return nil
 }
 
-func (x *Document) GetPatient() *Patient {
+func (x *Clinic) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Clinic) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Clinic) GetSpecialty() *Identifier {
+	if x != nil {
+		return x.Specialty
+	}
+	return nil
+}
+
+func (x *Clinic) GetConsultant() *Practitioner {
+	if x != nil {
+		return x.Consultant
+	}
+	return nil
+}
+
+func (x *Clinic) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+// Admission is an inpatient admission (hospital spell), with the patient's current location
+type Admission struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *Identifier          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Patient    *Identifier          `protobuf:"bytes,2,opt,name=patient,proto3" json:"patient,omitempty"`
+	Admitted   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=admitted,proto3" json:"admitted,omitempty"`
+	Discharged *timestamp.Timestamp `protobuf:"bytes,4,opt,name=discharged,proto3" json:"discharged,omitempty"` // empty if the patient remains admitted
+	Ward       string               `protobuf:"bytes,5,opt,name=ward,proto3" json:"ward,omitempty"`             // ward code
+	WardName   string               `protobuf:"bytes,6,opt,name=ward_name,json=wardName,proto3" json:"ward_name,omitempty"`
+	Bed        string               `protobuf:"bytes,7,opt,name=bed,proto3" json:"bed,omitempty"`
+	Consultant *Practitioner        `protobuf:"bytes,8,opt,name=consultant,proto3" json:"consultant,omitempty"` // consultant responsible for the patient's care
+	Specialty  *Identifier          `protobuf:"bytes,9,opt,name=specialty,proto3" json:"specialty,omitempty"`   // main specialty e.g. https://fhir.nhs.uk/CodeSystem/Specialty|400
+}
+
+func (x *Admission) Reset() {
+	*x = Admission{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Admission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Admission) ProtoMessage() {}
+
+func (x *Admission) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Admission.ProtoReflect.Descriptor instead.
+func (*Admission) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Admission) GetId() *Identifier {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Admission) GetPatient() *Identifier {
 	if x != nil {
 		return x.Patient
 	}
 	return nil
 }
 
-func (x *Document) GetStatus() Document_Status {
+func (x *Admission) GetAdmitted() *timestamp.Timestamp {
 	if x != nil {
-		return x.Status
+		return x.Admitted
+	}
+	return nil
+}
+
+func (x *Admission) GetDischarged() *timestamp.Timestamp {
+	if x != nil {
+		return x.Discharged
+	}
+	return nil
+}
+
+func (x *Admission) GetWard() string {
+	if x != nil {
+		return x.Ward
+	}
+	return ""
+}
+
+func (x *Admission) GetWardName() string {
+	if x != nil {
+		return x.WardName
+	}
+	return ""
+}
+
+func (x *Admission) GetBed() string {
+	if x != nil {
+		return x.Bed
+	}
+	return ""
+}
+
+func (x *Admission) GetConsultant() *Practitioner {
+	if x != nil {
+		return x.Consultant
+	}
+	return nil
+}
+
+func (x *Admission) GetSpecialty() *Identifier {
+	if x != nil {
+		return x.Specialty
+	}
+	return nil
+}
+
+// SnomedExpression is a SNOMED CT expression, such as a post-coordinated expression using compositional
+// grammar, as parsed by the terminology server
+type SnomedExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Expression    string  `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`                                    // expression in canonical form, without terms
+	FocusConcepts []int64 `protobuf:"varint,2,rep,packed,name=focus_concepts,json=focusConcepts,proto3" json:"focus_concepts,omitempty"` // focus concepts of the expression, in canonical order
+	Refined       bool    `protobuf:"varint,3,opt,name=refined,proto3" json:"refined,omitempty"`                                         // whether the expression has refinements (i.e. is post-coordinated)
+}
+
+func (x *SnomedExpression) Reset() {
+	*x = SnomedExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnomedExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnomedExpression) ProtoMessage() {}
+
+func (x *SnomedExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnomedExpression.ProtoReflect.Descriptor instead.
+func (*SnomedExpression) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SnomedExpression) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *SnomedExpression) GetFocusConcepts() []int64 {
+	if x != nil {
+		return x.FocusConcepts
+	}
+	return nil
+}
+
+func (x *SnomedExpression) GetRefined() bool {
+	if x != nil {
+		return x.Refined
+	}
+	return false
+}
+
+// LoincCode is a LOINC (Logical Observation Identifiers Names and Codes) term, as per the LOINC table
+// See https://loinc.org/kb/users-guide/major-parts-of-a-loinc-term/
+type LoincCode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code           string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                                             // LOINC_NUM e.g. "2160-0"
+	LongCommonName string `protobuf:"bytes,2,opt,name=long_common_name,json=longCommonName,proto3" json:"long_common_name,omitempty"` // e.g. "Creatinine [Mass/volume] in Serum or Plasma"
+	Component      string `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"`                                   // the substance or entity being measured or observed
+	Property       string `protobuf:"bytes,4,opt,name=property,proto3" json:"property,omitempty"`                                     // the characteristic or attribute of the component e.g. "MCnc" (mass concentration)
+	TimeAspect     string `protobuf:"bytes,5,opt,name=time_aspect,json=timeAspect,proto3" json:"time_aspect,omitempty"`               // e.g. "Pt" (point in time)
+	System         string `protobuf:"bytes,6,opt,name=system,proto3" json:"system,omitempty"`                                         // the specimen or thing upon which the observation was made e.g. "Ser/Plas"
+	Scale          string `protobuf:"bytes,7,opt,name=scale,proto3" json:"scale,omitempty"`                                           // e.g. "Qn" (quantitative)
+	Method         string `protobuf:"bytes,8,opt,name=method,proto3" json:"method,omitempty"`                                         // the method used, if any
+	Class          string `protobuf:"bytes,9,opt,name=class,proto3" json:"class,omitempty"`                                           // e.g. "CHEM"
+	Status         string `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`                                        // ACTIVE, TRIAL, DISCOURAGED or DEPRECATED
+}
+
+func (x *LoincCode) Reset() {
+	*x = LoincCode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_model_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoincCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoincCode) ProtoMessage() {}
+
+func (x *LoincCode) ProtoReflect() protoreflect.Message {
+	mi := &file_model_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return Document_UNKNOWN
+	return mi.MessageOf(x)
 }
 
-func (x *Document) GetAuthors() []*Identifier {
-	if x != nil {
-		return x.Authors
-	}
-	return nil
+// Deprecated: Use LoincCode.ProtoReflect.Descriptor instead.
+func (*LoincCode) Descriptor() ([]byte, []int) {
+	return file_model_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *Document) GetSignedBy() []*Identifier {
+func (x *LoincCode) GetCode() string {
 	if x != nil {
-		return x.SignedBy
+		return x.Code
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetResponsible() []*Identifier {
+func (x *LoincCode) GetLongCommonName() string {
 	if x != nil {
-		return x.Responsible
+		return x.LongCommonName
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetAdministrator() *Identifier {
+func (x *LoincCode) GetComponent() string {
 	if x != nil {
-		return x.Administrator
+		return x.Component
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetEncounter() *Identifier {
+func (x *LoincCode) GetProperty() string {
 	if x != nil {
-		return x.Encounter
+		return x.Property
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetRecipients() []*Identifier {
+func (x *LoincCode) GetTimeAspect() string {
 	if x != nil {
-		return x.Recipients
+		return x.TimeAspect
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetTitle() string {
+func (x *LoincCode) GetSystem() string {
 	if x != nil {
-		return x.Title
+		return x.System
 	}
 	return ""
 }
 
-func (x *Document) GetDateTime() *timestamp.Timestamp {
+func (x *LoincCode) GetScale() string {
 	if x != nil {
-		return x.DateTime
+		return x.Scale
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetTypedDateTime() *timestamp.Timestamp {
+func (x *LoincCode) GetMethod() string {
 	if x != nil {
-		return x.TypedDateTime
+		return x.Method
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetSignedDateTime() *timestamp.Timestamp {
+func (x *LoincCode) GetClass() string {
 	if x != nil {
-		return x.SignedDateTime
+		return x.Class
 	}
-	return nil
+	return ""
 }
 
-func (x *Document) GetData() *Attachment {
+func (x *LoincCode) GetStatus() string {
 	if x != nil {
-		return x.Data
+		return x.Status
 	}
-	return nil
+	return ""
 }
 
 var File_model_proto protoreflect.FileDescriptor
@@ -1405,214 +3029,481 @@ var file_model_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61,
 	0x70, 0x69, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x04, 0x0a, 0x07, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
-	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69,
-	0x74, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x64,
-	0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x62, 0x69,
-	0x72, 0x74, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x62, 0x69, 0x72, 0x74,
-	0x68, 0x44, 0x61, 0x74, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65,
-	0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x42, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x61, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x64, 0x61, 0x79, 0x22, 0xe7, 0x07, 0x0a, 0x07, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x2a,
+	0x0a, 0x0a, 0x62, 0x69, 0x72, 0x74, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x65, 0x52,
+	0x09, 0x62, 0x69, 0x72, 0x74, 0x68, 0x44, 0x61, 0x74, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x64, 0x65,
+	0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52,
+	0x0c, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a,
+	0x10, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0f, 0x64, 0x65, 0x63, 0x65, 0x61,
+	0x73, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x12, 0x33, 0x0a, 0x0b, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12,
+	0x2c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x30, 0x0a,
+	0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68,
+	0x6f, 0x6e, 0x65, 0x52, 0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x67, 0x0a, 0x1e, 0x6e, 0x68,
+	0x73, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x48, 0x53, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x1b, 0x6e, 0x68, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64,
+	0x5f, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x11, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61,
+	0x67, 0x65, 0x12, 0x3a, 0x0a, 0x0f, 0x65, 0x74, 0x68, 0x6e, 0x69, 0x63, 0x5f, 0x63, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0e,
+	0x65, 0x74, 0x68, 0x6e, 0x69, 0x63, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x38,
+	0x0a, 0x0e, 0x6d, 0x61, 0x72, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0d, 0x6d, 0x61, 0x72, 0x69, 0x74,
+	0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x63, 0x63, 0x75,
+	0x70, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x63,
+	0x63, 0x75, 0x70, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x5f, 0x6f, 0x66, 0x5f, 0x62, 0x69, 0x72, 0x74, 0x68, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x4f, 0x66, 0x42, 0x69, 0x72,
+	0x74, 0x68, 0x12, 0x2b, 0x0a, 0x07, 0x73, 0x75, 0x72, 0x67, 0x65, 0x72, 0x79, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x73, 0x75, 0x72, 0x67, 0x65, 0x72, 0x79, 0x12,
+	0x44, 0x0a, 0x14, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x13, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65,
+	0x64, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x09, 0x4a, 0x04, 0x08, 0x09, 0x10, 0x0a, 0x4a, 0x04, 0x08,
+	0x05, 0x10, 0x06, 0x22, 0x3a, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22,
+	0x68, 0x0a, 0x06, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65,
+	0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x3a, 0x0a, 0x0a, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xcc, 0x02, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x31, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x31, 0x12, 0x1a, 0x0a,
+	0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x32, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x33, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x33, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x70,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x24, 0x0a, 0x03, 0x75, 0x73,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x52, 0x03, 0x75, 0x73, 0x65,
+	0x22, 0x52, 0x0a, 0x03, 0x55, 0x73, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
+	0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f, 0x4d, 0x45, 0x10, 0x01, 0x12, 0x0d,
+	0x0a, 0x09, 0x54, 0x45, 0x4d, 0x50, 0x4f, 0x52, 0x41, 0x52, 0x59, 0x10, 0x02, 0x12, 0x12, 0x0a,
+	0x0e, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x44, 0x45, 0x4e, 0x43, 0x45, 0x10,
+	0x03, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x4f, 0x52, 0x4b, 0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x4f,
+	0x4c, 0x44, 0x10, 0x05, 0x22, 0xa1, 0x01, 0x0a, 0x09, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f,
+	0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x03,
+	0x75, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x52,
+	0x03, 0x75, 0x73, 0x65, 0x22, 0x32, 0x0a, 0x03, 0x55, 0x73, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f, 0x4d, 0x45,
+	0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x4f, 0x52, 0x4b, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06,
+	0x4d, 0x4f, 0x42, 0x49, 0x4c, 0x45, 0x10, 0x03, 0x22, 0xae, 0x02, 0x0a, 0x09, 0x48, 0x75, 0x6d,
+	0x61, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x75, 0x73, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x48, 0x75, 0x6d, 0x61,
+	0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x52, 0x03, 0x75, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x66, 0x66,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x66, 0x66,
+	0x69, 0x63, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x6c, 0x0a, 0x03, 0x55,
+	0x73, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x09, 0x0a, 0x05, 0x55, 0x53, 0x55, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x46,
+	0x46, 0x49, 0x43, 0x49, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x4d, 0x50,
+	0x4f, 0x52, 0x41, 0x52, 0x59, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x49, 0x43, 0x4b, 0x4e,
+	0x41, 0x4d, 0x45, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x4e, 0x4f, 0x4e, 0x59, 0x4d, 0x4f,
+	0x55, 0x53, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x4c, 0x44, 0x10, 0x06, 0x12, 0x0a, 0x0a,
+	0x06, 0x4d, 0x41, 0x49, 0x44, 0x45, 0x4e, 0x10, 0x07, 0x22, 0xe5, 0x01, 0x0a, 0x0a, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c,
+	0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x65,
-	0x61, 0x73, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x10, 0x64, 0x65, 0x63, 0x65,
-	0x61, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x08, 0x48, 0x00, 0x52, 0x0f, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x42, 0x6f,
-	0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x72, 0x67, 0x65, 0x72, 0x79,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x72, 0x67, 0x65, 0x72, 0x79, 0x12,
-	0x31, 0x0a, 0x14, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x61, 0x63, 0x74,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x67,
-	0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x22, 0xb7, 0x03, 0x0a, 0x0c, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
 	0x65, 0x72, 0x12, 0x33, 0x0a, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
 	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69,
-	0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f,
-	0x6e, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x52, 0x0a, 0x74, 0x65, 0x6c,
-	0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c,
-	0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x42,
-	0x0a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x22, 0x68, 0x0a, 0x06, 0x50,
-	0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x26, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
+	0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x2a,
+	0x0a, 0x0a, 0x62, 0x69, 0x72, 0x74, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x65, 0x52,
+	0x09, 0x62, 0x69, 0x72, 0x74, 0x68, 0x44, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x70, 0x68,
+	0x6f, 0x74, 0x6f, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x70,
+	0x68, 0x6f, 0x74, 0x6f, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x05, 0x72,
+	0x6f, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x0a,
+	0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f,
+	0x6e, 0x65, 0x52, 0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x35,
+	0x0a, 0x0e, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0d, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x22, 0x91, 0x01, 0x0a, 0x10,
+	0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65,
+	0x12, 0x1f, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c,
+	0x65, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61,
+	0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x76, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x6f,
+	0x62, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a,
+	0x6f, 0x62, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x72, 0x65,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x70,
+	0x72, 0x65, 0x63, 0x61, 0x74, 0x65, 0x64, 0x22, 0xa5, 0x02, 0x0a, 0x0c, 0x4f, 0x72, 0x67, 0x61,
+	0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x0b, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x2c, 0x0a, 0x09, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x09, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70,
+	0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x52, 0x0a, 0x74,
+	0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x6f, 0x6c,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x4f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x6f, 0x6c,
+	0x65, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22,
+	0xb2, 0x01, 0x0a, 0x10, 0x4f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x6f, 0x6c, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72,
+	0x69, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x25, 0x0a,
+	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x22, 0x59, 0x0a, 0x06, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x72, 0x69, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x6f, 0x72, 0x65, 0x5f, 0x69, 0x6e, 0x66,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x6d, 0x6f, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x51, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72, 0x65,
+	0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x0d, 0x4c, 0x6f, 0x67,
+	0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c,
+	0x6c, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x10, 0x0a,
+	0x0e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x25, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x4b, 0x0a, 0x0e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72,
+	0x6f, 0x6c, 0x65, 0x22, 0x4e, 0x0a, 0x0f, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f,
+	0x6c, 0x65, 0x73, 0x22, 0xa7, 0x02, 0x0a, 0x0e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f,
+	0x6c, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x03,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x3a, 0x0a, 0x0a, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x22, 0xba, 0x01, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1a, 0x0a,
-	0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x31, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x32, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x33, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x33, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a,
-	0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f,
-	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
-	0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x45,
-	0x0a, 0x09, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae, 0x02, 0x0a, 0x09, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x75, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x4e, 0x61,
-	0x6d, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x52, 0x03, 0x75, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66,
-	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x6d,
-	0x69, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65,
-	0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x65,
-	0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x66, 0x66, 0x69, 0x63, 0x65,
-	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x66, 0x66, 0x69, 0x63, 0x65,
-	0x73, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
-	0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x6c, 0x0a, 0x03, 0x55, 0x73, 0x65, 0x12,
-	0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05,
-	0x55, 0x53, 0x55, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x46, 0x46, 0x49, 0x43,
-	0x49, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x4d, 0x50, 0x4f, 0x52, 0x41,
-	0x52, 0x59, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x49, 0x43, 0x4b, 0x4e, 0x41, 0x4d, 0x45,
-	0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x4e, 0x4f, 0x4e, 0x59, 0x4d, 0x4f, 0x55, 0x53, 0x10,
-	0x05, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x4c, 0x44, 0x10, 0x06, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x41,
-	0x49, 0x44, 0x45, 0x4e, 0x10, 0x07, 0x22, 0xe5, 0x01, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x61, 0x63,
-	0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
-	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67,
-	0x75, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67,
-	0x75, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
-	0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61,
-	0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0xc0,
-	0x03, 0x0a, 0x0c, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x12,
-	0x33, 0x0a, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x26, 0x0a, 0x05,
-	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x05, 0x6e,
-	0x61, 0x6d, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e,
-	0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x62,
-	0x69, 0x72, 0x74, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x07, 0x72,
+	0x6f, 0x74, 0x61, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x90, 0x01,
+	0x0a, 0x1b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x22, 0x72, 0x0a, 0x13, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x34,
+	0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x62, 0x69, 0x72,
-	0x74, 0x68, 0x44, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x70, 0x68, 0x6f, 0x74, 0x6f, 0x73,
-	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41,
-	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x68, 0x6f, 0x74, 0x6f,
-	0x73, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73,
-	0x12, 0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x74, 0x65, 0x6c, 0x65,
-	0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61,
-	0x70, 0x69, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x52, 0x0a,
-	0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x0e, 0x77, 0x6f,
-	0x72, 0x6b, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x52, 0x0d, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
-	0x73, 0x22, 0x5a, 0x0a, 0x10, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65,
-	0x72, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65,
-	0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50,
-	0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x76, 0x0a,
-	0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x69, 0x64,
-	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x6f, 0x62, 0x5f,
-	0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x6f, 0x62,
-	0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61,
-	0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x70, 0x72, 0x65,
-	0x63, 0x61, 0x74, 0x65, 0x64, 0x22, 0x59, 0x0a, 0x06, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x75, 0x72, 0x69, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x6f, 0x72, 0x65, 0x5f, 0x69, 0x6e,
-	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x6d, 0x6f, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x22, 0x51, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x73, 0x22, 0xf1, 0x08, 0x0a, 0x08, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x2e,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b,
+	0x0a, 0x07, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x52, 0x07, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x12, 0x2e, 0x0a, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
 	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
-	0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72,
-	0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x25, 0x0a, 0x0d, 0x4c, 0x6f,
-	0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
-	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
-	0x6e, 0x22, 0xfe, 0x05, 0x0a, 0x08, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69,
-	0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65,
-	0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x61,
-	0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61,
+	0x72, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x79, 0x12, 0x33, 0x0a, 0x0b, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x69, 0x62, 0x6c, 0x65,
+	0x12, 0x37, 0x0a, 0x0d, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0d, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x09, 0x65, 0x6e, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61,
 	0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52,
-	0x07, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x12, 0x2e, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x08,
-	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x79, 0x12, 0x33, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x52, 0x0b, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x37, 0x0a,
-	0x0d, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x07,
+	0x09, 0x65, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x0a, 0x72, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x08, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x42, 0x0a, 0x0f,
+	0x74, 0x79, 0x70, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0d, 0x74, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x44, 0x0a, 0x10, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x44, 0x61,
+	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x25, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x73, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x12, 0x42, 0x0a, 0x0f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61,
+	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x52, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x22, 0x46, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x52, 0x41, 0x46, 0x54,
+	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x49, 0x4e, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x41, 0x4d, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e,
+	0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x22, 0x3e, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41,
+	0x4c, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x45, 0x53, 0x54, 0x52, 0x49, 0x43, 0x54, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x52, 0x45, 0x53, 0x54,
+	0x52, 0x49, 0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x22, 0xc9, 0x03, 0x0a, 0x0b, 0x41, 0x70, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0d, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x65, 0x6e,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70,
-	0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a,
-	0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69,
-	0x74, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
-	0x12, 0x37, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0b, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
-	0x08, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x42, 0x0a, 0x0f, 0x74, 0x79, 0x70,
-	0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d,
-	0x74, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x44, 0x0a,
-	0x10, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d,
-	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x0e, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x54,
-	0x69, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0e, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
-	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x46, 0x0a, 0x06, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10,
-	0x00, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x52, 0x41, 0x46, 0x54, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05,
-	0x46, 0x49, 0x4e, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x4d, 0x45, 0x4e, 0x44,
-	0x45, 0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52,
-	0x10, 0x04, 0x2a, 0x2b, 0x0a, 0x06, 0x47, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07,
-	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x41, 0x4c,
-	0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x45, 0x4d, 0x41, 0x4c, 0x45, 0x10, 0x02, 0x42,
-	0x47, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6c, 0x64, 0x72, 0x69, 0x78, 0x2e, 0x63, 0x6f,
-	0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x42, 0x06, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x73, 0x50, 0x00, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c, 0x65, 0x2f, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72,
-	0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x06, 0x63,
+	0x6c, 0x69, 0x6e, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x06,
+	0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41,
+	0x70, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x70, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x70, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x69, 0x61,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x09, 0x63, 0x6c,
+	0x69, 0x6e, 0x69, 0x63, 0x69, 0x61, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x22, 0x51, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x52, 0x45, 0x45,
+	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x4f, 0x4f, 0x4b, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0c,
+	0x0a, 0x08, 0x41, 0x54, 0x54, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09,
+	0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x44,
+	0x4e, 0x41, 0x10, 0x05, 0x22, 0xe7, 0x01, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x12,
+	0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x2f, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x12, 0x33, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6c, 0x74, 0x61, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73,
+	0x75, 0x6c, 0x74, 0x61, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x22, 0xf8,
+	0x02, 0x0a, 0x09, 0x41, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x2b, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x08,
+	0x61, 0x64, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x61, 0x64, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72, 0x67,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x77, 0x61, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x77, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x77, 0x61, 0x72, 0x64, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x62, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6c, 0x74, 0x61, 0x6e,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x0a, 0x63, 0x6f,
+	0x6e, 0x73, 0x75, 0x6c, 0x74, 0x61, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09,
+	0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x22, 0x73, 0x0a, 0x10, 0x53, 0x6e, 0x6f,
+	0x6d, 0x65, 0x64, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a,
+	0x0e, 0x66, 0x6f, 0x63, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0d, 0x66, 0x6f, 0x63, 0x75, 0x73, 0x43, 0x6f, 0x6e, 0x63,
+	0x65, 0x70, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x22, 0x98,
+	0x02, 0x0a, 0x09, 0x4c, 0x6f, 0x69, 0x6e, 0x63, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x28, 0x0a, 0x10, 0x6c, 0x6f, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6c, 0x6f, 0x6e, 0x67,
+	0x43, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70,
+	0x65, 0x72, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x70,
+	0x65, 0x72, 0x74, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x61, 0x73, 0x70,
+	0x65, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x41,
+	0x73, 0x70, 0x65, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63,
+	0x61, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6c, 0x61, 0x73,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2a, 0xad, 0x02, 0x0a, 0x1b, 0x4e, 0x48,
+	0x53, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x19, 0x4e, 0x48, 0x53,
+	0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x4e, 0x48, 0x53, 0x5f,
+	0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x19, 0x0a, 0x15, 0x4e, 0x48, 0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19,
+	0x4e, 0x48, 0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45,
+	0x5f, 0x52, 0x45, 0x51, 0x55, 0x49, 0x52, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x4e,
+	0x48, 0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45, 0x5f,
+	0x4e, 0x4f, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x04, 0x12, 0x1f, 0x0a, 0x1b, 0x4e, 0x48,
+	0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45, 0x5f, 0x55,
+	0x4e, 0x52, 0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x44, 0x10, 0x05, 0x12, 0x20, 0x0a, 0x1c, 0x4e,
+	0x48, 0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45, 0x5f,
+	0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x06, 0x12, 0x1a, 0x0a,
+	0x16, 0x4e, 0x48, 0x53, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
+	0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x07, 0x12, 0x1e, 0x0a, 0x1a, 0x4e, 0x48, 0x53,
+	0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x45, 0x5f, 0x50, 0x4f,
+	0x53, 0x54, 0x50, 0x4f, 0x4e, 0x45, 0x44, 0x10, 0x08, 0x2a, 0x2b, 0x0a, 0x06, 0x47, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
+	0x12, 0x08, 0x0a, 0x04, 0x4d, 0x41, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x45,
+	0x4d, 0x41, 0x4c, 0x45, 0x10, 0x02, 0x42, 0x47, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6c,
+	0x64, 0x72, 0x69, 0x78, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2e, 0x61,
+	0x70, 0x69, 0x42, 0x06, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x50, 0x00, 0x5a, 0x21, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c, 0x65, 0x2f,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1627,72 +3518,140 @@ func file_model_proto_rawDescGZIP() []byte {
 	return file_model_proto_rawDescData
 }
 
-var file_model_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_model_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_model_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_model_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_model_proto_goTypes = []interface{}{
-	(Gender)(0),                 // 0: apiv1.Gender
-	(HumanName_Use)(0),          // 1: apiv1.HumanName.Use
-	(Document_Status)(0),        // 2: apiv1.Document.Status
-	(*Patient)(nil),             // 3: apiv1.Patient
-	(*Period)(nil),              // 4: apiv1.Period
-	(*Identifier)(nil),          // 5: apiv1.Identifier
-	(*Address)(nil),             // 6: apiv1.Address
-	(*Telephone)(nil),           // 7: apiv1.Telephone
-	(*HumanName)(nil),           // 8: apiv1.HumanName
-	(*Attachment)(nil),          // 9: apiv1.Attachment
-	(*Practitioner)(nil),        // 10: apiv1.Practitioner
-	(*PractitionerRole)(nil),    // 11: apiv1.PractitionerRole
-	(*Role)(nil),                // 12: apiv1.Role
-	(*System)(nil),              // 13: apiv1.System
-	(*LoginRequest)(nil),        // 14: apiv1.LoginRequest
-	(*TokenRefreshRequest)(nil), // 15: apiv1.TokenRefreshRequest
-	(*LoginResponse)(nil),       // 16: apiv1.LoginResponse
-	(*Document)(nil),            // 17: apiv1.Document
-	(*timestamp.Timestamp)(nil), // 18: google.protobuf.Timestamp
+	(NHSNumberVerificationStatus)(0),    // 0: apiv1.NHSNumberVerificationStatus
+	(Gender)(0),                         // 1: apiv1.Gender
+	(Address_Use)(0),                    // 2: apiv1.Address.Use
+	(Telephone_Use)(0),                  // 3: apiv1.Telephone.Use
+	(HumanName_Use)(0),                  // 4: apiv1.HumanName.Use
+	(Document_Status)(0),                // 5: apiv1.Document.Status
+	(Document_Sensitivity)(0),           // 6: apiv1.Document.Sensitivity
+	(Appointment_Status)(0),             // 7: apiv1.Appointment.Status
+	(*Date)(nil),                        // 8: apiv1.Date
+	(*Patient)(nil),                     // 9: apiv1.Patient
+	(*Provenance)(nil),                  // 10: apiv1.Provenance
+	(*Period)(nil),                      // 11: apiv1.Period
+	(*Identifier)(nil),                  // 12: apiv1.Identifier
+	(*Address)(nil),                     // 13: apiv1.Address
+	(*Telephone)(nil),                   // 14: apiv1.Telephone
+	(*HumanName)(nil),                   // 15: apiv1.HumanName
+	(*Attachment)(nil),                  // 16: apiv1.Attachment
+	(*Practitioner)(nil),                // 17: apiv1.Practitioner
+	(*PractitionerRole)(nil),            // 18: apiv1.PractitionerRole
+	(*Role)(nil),                        // 19: apiv1.Role
+	(*Organisation)(nil),                // 20: apiv1.Organisation
+	(*OrganisationRole)(nil),            // 21: apiv1.OrganisationRole
+	(*System)(nil),                      // 22: apiv1.System
+	(*LoginRequest)(nil),                // 23: apiv1.LoginRequest
+	(*TokenRefreshRequest)(nil),         // 24: apiv1.TokenRefreshRequest
+	(*LogoutRequest)(nil),               // 25: apiv1.LogoutRequest
+	(*LogoutResponse)(nil),              // 26: apiv1.LogoutResponse
+	(*LoginResponse)(nil),               // 27: apiv1.LoginResponse
+	(*RoleAssignment)(nil),              // 28: apiv1.RoleAssignment
+	(*RoleAssignments)(nil),             // 29: apiv1.RoleAssignments
+	(*ServiceAccount)(nil),              // 30: apiv1.ServiceAccount
+	(*CreateServiceAccountRequest)(nil), // 31: apiv1.CreateServiceAccountRequest
+	(*RotateSecretRequest)(nil),         // 32: apiv1.RotateSecretRequest
+	(*Document)(nil),                    // 33: apiv1.Document
+	(*Appointment)(nil),                 // 34: apiv1.Appointment
+	(*Clinic)(nil),                      // 35: apiv1.Clinic
+	(*Admission)(nil),                   // 36: apiv1.Admission
+	(*SnomedExpression)(nil),            // 37: apiv1.SnomedExpression
+	(*LoincCode)(nil),                   // 38: apiv1.LoincCode
+	(*timestamp.Timestamp)(nil),         // 39: google.protobuf.Timestamp
 }
 var file_model_proto_depIdxs = []int32{
-	0,  // 0: apiv1.Patient.gender:type_name -> apiv1.Gender
-	18, // 1: apiv1.Patient.birth_date:type_name -> google.protobuf.Timestamp
-	18, // 2: apiv1.Patient.deceased_date:type_name -> google.protobuf.Timestamp
-	5,  // 3: apiv1.Patient.identifiers:type_name -> apiv1.Identifier
-	6,  // 4: apiv1.Patient.addresses:type_name -> apiv1.Address
-	7,  // 5: apiv1.Patient.telephones:type_name -> apiv1.Telephone
-	18, // 6: apiv1.Period.start:type_name -> google.protobuf.Timestamp
-	18, // 7: apiv1.Period.end:type_name -> google.protobuf.Timestamp
-	4,  // 8: apiv1.Address.period:type_name -> apiv1.Period
-	1,  // 9: apiv1.HumanName.use:type_name -> apiv1.HumanName.Use
-	4,  // 10: apiv1.HumanName.period:type_name -> apiv1.Period
-	18, // 11: apiv1.Attachment.created:type_name -> google.protobuf.Timestamp
-	5,  // 12: apiv1.Practitioner.identifiers:type_name -> apiv1.Identifier
-	8,  // 13: apiv1.Practitioner.names:type_name -> apiv1.HumanName
-	0,  // 14: apiv1.Practitioner.gender:type_name -> apiv1.Gender
-	18, // 15: apiv1.Practitioner.birth_date:type_name -> google.protobuf.Timestamp
-	9,  // 16: apiv1.Practitioner.photos:type_name -> apiv1.Attachment
-	11, // 17: apiv1.Practitioner.roles:type_name -> apiv1.PractitionerRole
-	7,  // 18: apiv1.Practitioner.telephones:type_name -> apiv1.Telephone
-	6,  // 19: apiv1.Practitioner.work_addresses:type_name -> apiv1.Address
-	12, // 20: apiv1.PractitionerRole.role:type_name -> apiv1.Role
-	4,  // 21: apiv1.PractitionerRole.period:type_name -> apiv1.Period
-	5,  // 22: apiv1.Role.identifier:type_name -> apiv1.Identifier
-	5,  // 23: apiv1.LoginRequest.user:type_name -> apiv1.Identifier
-	5,  // 24: apiv1.Document.id:type_name -> apiv1.Identifier
-	3,  // 25: apiv1.Document.patient:type_name -> apiv1.Patient
-	2,  // 26: apiv1.Document.status:type_name -> apiv1.Document.Status
-	5,  // 27: apiv1.Document.authors:type_name -> apiv1.Identifier
-	5,  // 28: apiv1.Document.signed_by:type_name -> apiv1.Identifier
-	5,  // 29: apiv1.Document.responsible:type_name -> apiv1.Identifier
-	5,  // 30: apiv1.Document.administrator:type_name -> apiv1.Identifier
-	5,  // 31: apiv1.Document.encounter:type_name -> apiv1.Identifier
-	5,  // 32: apiv1.Document.recipients:type_name -> apiv1.Identifier
-	18, // 33: apiv1.Document.date_time:type_name -> google.protobuf.Timestamp
-	18, // 34: apiv1.Document.typed_date_time:type_name -> google.protobuf.Timestamp
-	18, // 35: apiv1.Document.signed_date_time:type_name -> google.protobuf.Timestamp
-	9,  // 36: apiv1.Document.data:type_name -> apiv1.Attachment
-	37, // [37:37] is the sub-list for method output_type
-	37, // [37:37] is the sub-list for method input_type
-	37, // [37:37] is the sub-list for extension type_name
-	37, // [37:37] is the sub-list for extension extendee
-	0,  // [0:37] is the sub-list for field type_name
+	1,  // 0: apiv1.Patient.gender:type_name -> apiv1.Gender
+	8,  // 1: apiv1.Patient.birth_date:type_name -> apiv1.Date
+	39, // 2: apiv1.Patient.deceased_date:type_name -> google.protobuf.Timestamp
+	12, // 3: apiv1.Patient.identifiers:type_name -> apiv1.Identifier
+	13, // 4: apiv1.Patient.addresses:type_name -> apiv1.Address
+	14, // 5: apiv1.Patient.telephones:type_name -> apiv1.Telephone
+	10, // 6: apiv1.Patient.provenance:type_name -> apiv1.Provenance
+	0,  // 7: apiv1.Patient.nhs_number_verification_status:type_name -> apiv1.NHSNumberVerificationStatus
+	12, // 8: apiv1.Patient.ethnic_category:type_name -> apiv1.Identifier
+	12, // 9: apiv1.Patient.marital_status:type_name -> apiv1.Identifier
+	12, // 10: apiv1.Patient.surgery:type_name -> apiv1.Identifier
+	12, // 11: apiv1.Patient.general_practitioner:type_name -> apiv1.Identifier
+	39, // 12: apiv1.Period.start:type_name -> google.protobuf.Timestamp
+	39, // 13: apiv1.Period.end:type_name -> google.protobuf.Timestamp
+	11, // 14: apiv1.Address.period:type_name -> apiv1.Period
+	2,  // 15: apiv1.Address.use:type_name -> apiv1.Address.Use
+	3,  // 16: apiv1.Telephone.use:type_name -> apiv1.Telephone.Use
+	4,  // 17: apiv1.HumanName.use:type_name -> apiv1.HumanName.Use
+	11, // 18: apiv1.HumanName.period:type_name -> apiv1.Period
+	39, // 19: apiv1.Attachment.created:type_name -> google.protobuf.Timestamp
+	12, // 20: apiv1.Practitioner.identifiers:type_name -> apiv1.Identifier
+	15, // 21: apiv1.Practitioner.names:type_name -> apiv1.HumanName
+	1,  // 22: apiv1.Practitioner.gender:type_name -> apiv1.Gender
+	8,  // 23: apiv1.Practitioner.birth_date:type_name -> apiv1.Date
+	16, // 24: apiv1.Practitioner.photos:type_name -> apiv1.Attachment
+	18, // 25: apiv1.Practitioner.roles:type_name -> apiv1.PractitionerRole
+	14, // 26: apiv1.Practitioner.telephones:type_name -> apiv1.Telephone
+	13, // 27: apiv1.Practitioner.work_addresses:type_name -> apiv1.Address
+	19, // 28: apiv1.PractitionerRole.role:type_name -> apiv1.Role
+	11, // 29: apiv1.PractitionerRole.period:type_name -> apiv1.Period
+	12, // 30: apiv1.PractitionerRole.organisation:type_name -> apiv1.Identifier
+	12, // 31: apiv1.Role.identifier:type_name -> apiv1.Identifier
+	12, // 32: apiv1.Organisation.identifiers:type_name -> apiv1.Identifier
+	13, // 33: apiv1.Organisation.addresses:type_name -> apiv1.Address
+	14, // 34: apiv1.Organisation.telephones:type_name -> apiv1.Telephone
+	21, // 35: apiv1.Organisation.roles:type_name -> apiv1.OrganisationRole
+	11, // 36: apiv1.Organisation.period:type_name -> apiv1.Period
+	12, // 37: apiv1.OrganisationRole.identifier:type_name -> apiv1.Identifier
+	11, // 38: apiv1.OrganisationRole.period:type_name -> apiv1.Period
+	12, // 39: apiv1.LoginRequest.user:type_name -> apiv1.Identifier
+	12, // 40: apiv1.RoleAssignment.user:type_name -> apiv1.Identifier
+	12, // 41: apiv1.RoleAssignments.user:type_name -> apiv1.Identifier
+	12, // 42: apiv1.ServiceAccount.user:type_name -> apiv1.Identifier
+	39, // 43: apiv1.ServiceAccount.expires:type_name -> google.protobuf.Timestamp
+	39, // 44: apiv1.ServiceAccount.created:type_name -> google.protobuf.Timestamp
+	39, // 45: apiv1.ServiceAccount.rotated:type_name -> google.protobuf.Timestamp
+	12, // 46: apiv1.CreateServiceAccountRequest.user:type_name -> apiv1.Identifier
+	39, // 47: apiv1.CreateServiceAccountRequest.expires:type_name -> google.protobuf.Timestamp
+	12, // 48: apiv1.RotateSecretRequest.user:type_name -> apiv1.Identifier
+	39, // 49: apiv1.RotateSecretRequest.expires:type_name -> google.protobuf.Timestamp
+	12, // 50: apiv1.Document.id:type_name -> apiv1.Identifier
+	9,  // 51: apiv1.Document.patient:type_name -> apiv1.Patient
+	5,  // 52: apiv1.Document.status:type_name -> apiv1.Document.Status
+	12, // 53: apiv1.Document.authors:type_name -> apiv1.Identifier
+	12, // 54: apiv1.Document.signed_by:type_name -> apiv1.Identifier
+	12, // 55: apiv1.Document.responsible:type_name -> apiv1.Identifier
+	12, // 56: apiv1.Document.administrator:type_name -> apiv1.Identifier
+	12, // 57: apiv1.Document.encounter:type_name -> apiv1.Identifier
+	12, // 58: apiv1.Document.recipients:type_name -> apiv1.Identifier
+	39, // 59: apiv1.Document.date_time:type_name -> google.protobuf.Timestamp
+	39, // 60: apiv1.Document.typed_date_time:type_name -> google.protobuf.Timestamp
+	39, // 61: apiv1.Document.signed_date_time:type_name -> google.protobuf.Timestamp
+	16, // 62: apiv1.Document.data:type_name -> apiv1.Attachment
+	12, // 63: apiv1.Document.type:type_name -> apiv1.Identifier
+	12, // 64: apiv1.Document.specialty:type_name -> apiv1.Identifier
+	17, // 65: apiv1.Document.author:type_name -> apiv1.Practitioner
+	39, // 66: apiv1.Document.event_date_time:type_name -> google.protobuf.Timestamp
+	6,  // 67: apiv1.Document.sensitivity:type_name -> apiv1.Document.Sensitivity
+	12, // 68: apiv1.Appointment.id:type_name -> apiv1.Identifier
+	12, // 69: apiv1.Appointment.clinic:type_name -> apiv1.Identifier
+	39, // 70: apiv1.Appointment.start:type_name -> google.protobuf.Timestamp
+	39, // 71: apiv1.Appointment.end:type_name -> google.protobuf.Timestamp
+	7,  // 72: apiv1.Appointment.status:type_name -> apiv1.Appointment.Status
+	17, // 73: apiv1.Appointment.clinician:type_name -> apiv1.Practitioner
+	9,  // 74: apiv1.Appointment.patient:type_name -> apiv1.Patient
+	12, // 75: apiv1.Clinic.id:type_name -> apiv1.Identifier
+	12, // 76: apiv1.Clinic.specialty:type_name -> apiv1.Identifier
+	17, // 77: apiv1.Clinic.consultant:type_name -> apiv1.Practitioner
+	12, // 78: apiv1.Admission.id:type_name -> apiv1.Identifier
+	12, // 79: apiv1.Admission.patient:type_name -> apiv1.Identifier
+	39, // 80: apiv1.Admission.admitted:type_name -> google.protobuf.Timestamp
+	39, // 81: apiv1.Admission.discharged:type_name -> google.protobuf.Timestamp
+	17, // 82: apiv1.Admission.consultant:type_name -> apiv1.Practitioner
+	12, // 83: apiv1.Admission.specialty:type_name -> apiv1.Identifier
+	84, // [84:84] is the sub-list for method output_type
+	84, // [84:84] is the sub-list for method input_type
+	84, // [84:84] is the sub-list for extension type_name
+	84, // [84:84] is the sub-list for extension extendee
+	0,  // [0:84] is the sub-list for field type_name
 }
 
 func init() { file_model_proto_init() }
@@ -1702,7 +3661,7 @@ func file_model_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_model_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Patient); i {
+			switch v := v.(*Date); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1714,7 +3673,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Period); i {
+			switch v := v.(*Patient); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1726,7 +3685,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Identifier); i {
+			switch v := v.(*Provenance); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1738,7 +3697,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Address); i {
+			switch v := v.(*Period); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1750,7 +3709,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Telephone); i {
+			switch v := v.(*Identifier); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1762,7 +3721,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HumanName); i {
+			switch v := v.(*Address); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1774,7 +3733,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Attachment); i {
+			switch v := v.(*Telephone); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1786,7 +3745,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Practitioner); i {
+			switch v := v.(*HumanName); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1798,7 +3757,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PractitionerRole); i {
+			switch v := v.(*Attachment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1810,7 +3769,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Role); i {
+			switch v := v.(*Practitioner); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1822,7 +3781,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*System); i {
+			switch v := v.(*PractitionerRole); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1834,7 +3793,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoginRequest); i {
+			switch v := v.(*Role); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1846,7 +3805,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TokenRefreshRequest); i {
+			switch v := v.(*Organisation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1858,7 +3817,7 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoginResponse); i {
+			switch v := v.(*OrganisationRole); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1870,6 +3829,138 @@ func file_model_proto_init() {
 			}
 		}
 		file_model_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*System); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenRefreshRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogoutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogoutResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoleAssignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoleAssignments); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceAccount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateServiceAccountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateSecretRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Document); i {
 			case 0:
 				return &v.state
@@ -1881,8 +3972,68 @@ func file_model_proto_init() {
 				return nil
 			}
 		}
+		file_model_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Appointment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Clinic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Admission); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnomedExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_model_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoincCode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_model_proto_msgTypes[0].OneofWrappers = []interface{}{
+	file_model_proto_msgTypes[1].OneofWrappers = []interface{}{
 		(*Patient_DeceasedDate)(nil),
 		(*Patient_DeceasedBoolean)(nil),
 	}
@@ -1891,8 +4042,8 @@ func file_model_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_model_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   15,
+			NumEnums:      8,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
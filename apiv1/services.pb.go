@@ -10,6 +10,7 @@ import (
 	context "context"
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -31,18 +32,326 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
-type IdentifierMapRequest struct {
+type PublicationStatus_Status int32
+
+const (
+	PublicationStatus_UNKNOWN   PublicationStatus_Status = 0
+	PublicationStatus_PENDING   PublicationStatus_Status = 1 // awaiting publication
+	PublicationStatus_PUBLISHED PublicationStatus_Status = 2 // successfully published
+	PublicationStatus_FAILED    PublicationStatus_Status = 3 // publication abandoned
+)
+
+// Enum value maps for PublicationStatus_Status.
+var (
+	PublicationStatus_Status_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "PENDING",
+		2: "PUBLISHED",
+		3: "FAILED",
+	}
+	PublicationStatus_Status_value = map[string]int32{
+		"UNKNOWN":   0,
+		"PENDING":   1,
+		"PUBLISHED": 2,
+		"FAILED":    3,
+	}
+)
+
+func (x PublicationStatus_Status) Enum() *PublicationStatus_Status {
+	p := new(PublicationStatus_Status)
+	*p = x
+	return p
+}
+
+func (x PublicationStatus_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PublicationStatus_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[0].Descriptor()
+}
+
+func (PublicationStatus_Status) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[0]
+}
+
+func (x PublicationStatus_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PublicationStatus_Status.Descriptor instead.
+func (PublicationStatus_Status) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type Delivery_Status int32
+
+const (
+	Delivery_UNKNOWN      Delivery_Status = 0
+	Delivery_PENDING      Delivery_Status = 1 // not yet sent
+	Delivery_SENT         Delivery_Status = 2 // sent, but not yet collected by the recipient
+	Delivery_ACKNOWLEDGED Delivery_Status = 3 // collected by the recipient
+	Delivery_FAILED       Delivery_Status = 4 // could not be delivered
+)
+
+// Enum value maps for Delivery_Status.
+var (
+	Delivery_Status_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "PENDING",
+		2: "SENT",
+		3: "ACKNOWLEDGED",
+		4: "FAILED",
+	}
+	Delivery_Status_value = map[string]int32{
+		"UNKNOWN":      0,
+		"PENDING":      1,
+		"SENT":         2,
+		"ACKNOWLEDGED": 3,
+		"FAILED":       4,
+	}
+)
+
+func (x Delivery_Status) Enum() *Delivery_Status {
+	p := new(Delivery_Status)
+	*p = x
+	return p
+}
+
+func (x Delivery_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Delivery_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[1].Descriptor()
+}
+
+func (Delivery_Status) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[1]
+}
+
+func (x Delivery_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Delivery_Status.Descriptor instead.
+func (Delivery_Status) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{16, 0}
+}
+
+type PatientStatus_Status int32
+
+const (
+	PatientStatus_UNKNOWN  PatientStatus_Status = 0
+	PatientStatus_ALIVE    PatientStatus_Status = 1 // not known to be deceased
+	PatientStatus_DECEASED PatientStatus_Status = 2
+)
+
+// Enum value maps for PatientStatus_Status.
+var (
+	PatientStatus_Status_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "ALIVE",
+		2: "DECEASED",
+	}
+	PatientStatus_Status_value = map[string]int32{
+		"UNKNOWN":  0,
+		"ALIVE":    1,
+		"DECEASED": 2,
+	}
+)
+
+func (x PatientStatus_Status) Enum() *PatientStatus_Status {
+	p := new(PatientStatus_Status)
+	*p = x
+	return p
+}
+
+func (x PatientStatus_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PatientStatus_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[2].Descriptor()
+}
+
+func (PatientStatus_Status) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[2]
+}
+
+func (x PatientStatus_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PatientStatus_Status.Descriptor instead.
+func (PatientStatus_Status) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{22, 0}
+}
+
+type ChangeEvent_Type int32
+
+const (
+	ChangeEvent_UNKNOWN              ChangeEvent_Type = 0
+	ChangeEvent_DEMOGRAPHICS_UPDATED ChangeEvent_Type = 1 // demographic details have changed
+	ChangeEvent_DECEASED             ChangeEvent_Type = 2 // the patient has died
+	ChangeEvent_DOCUMENT_PUBLISHED   ChangeEvent_Type = 3 // a document has been published for the patient
+	ChangeEvent_MERGED               ChangeEvent_Type = 4 // another record has been merged into the patient's record
+)
+
+// Enum value maps for ChangeEvent_Type.
+var (
+	ChangeEvent_Type_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "DEMOGRAPHICS_UPDATED",
+		2: "DECEASED",
+		3: "DOCUMENT_PUBLISHED",
+		4: "MERGED",
+	}
+	ChangeEvent_Type_value = map[string]int32{
+		"UNKNOWN":              0,
+		"DEMOGRAPHICS_UPDATED": 1,
+		"DECEASED":             2,
+		"DOCUMENT_PUBLISHED":   3,
+		"MERGED":               4,
+	}
+)
+
+func (x ChangeEvent_Type) Enum() *ChangeEvent_Type {
+	p := new(ChangeEvent_Type)
+	*p = x
+	return p
+}
+
+func (x ChangeEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[3].Descriptor()
+}
+
+func (ChangeEvent_Type) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[3]
+}
+
+func (x ChangeEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeEvent_Type.Descriptor instead.
+func (ChangeEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{34, 0}
+}
+
+type BackendMaintenance_Mode int32
+
+const (
+	BackendMaintenance_AVAILABLE BackendMaintenance_Mode = 0 // requests permitted
+	BackendMaintenance_READ_ONLY BackendMaintenance_Mode = 1 // reads permitted, but writes such as publishing documents are rejected
+	BackendMaintenance_OFFLINE   BackendMaintenance_Mode = 2 // all requests rejected
+)
+
+// Enum value maps for BackendMaintenance_Mode.
+var (
+	BackendMaintenance_Mode_name = map[int32]string{
+		0: "AVAILABLE",
+		1: "READ_ONLY",
+		2: "OFFLINE",
+	}
+	BackendMaintenance_Mode_value = map[string]int32{
+		"AVAILABLE": 0,
+		"READ_ONLY": 1,
+		"OFFLINE":   2,
+	}
+)
+
+func (x BackendMaintenance_Mode) Enum() *BackendMaintenance_Mode {
+	p := new(BackendMaintenance_Mode)
+	*p = x
+	return p
+}
+
+func (x BackendMaintenance_Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BackendMaintenance_Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[4].Descriptor()
+}
+
+func (BackendMaintenance_Mode) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[4]
+}
+
+func (x BackendMaintenance_Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BackendMaintenance_Mode.Descriptor instead.
+func (BackendMaintenance_Mode) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{35, 0}
+}
+
+type LogLevel_Level int32
+
+const (
+	LogLevel_INFO LogLevel_Level = 0 // all messages are logged
+	LogLevel_OFF  LogLevel_Level = 1 // no messages are logged
+)
+
+// Enum value maps for LogLevel_Level.
+var (
+	LogLevel_Level_name = map[int32]string{
+		0: "INFO",
+		1: "OFF",
+	}
+	LogLevel_Level_value = map[string]int32{
+		"INFO": 0,
+		"OFF":  1,
+	}
+)
+
+func (x LogLevel_Level) Enum() *LogLevel_Level {
+	p := new(LogLevel_Level)
+	*p = x
+	return p
+}
+
+func (x LogLevel_Level) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogLevel_Level) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[5].Descriptor()
+}
+
+func (LogLevel_Level) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[5]
+}
+
+func (x LogLevel_Level) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogLevel_Level.Descriptor instead.
+func (LogLevel_Level) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{44, 0}
+}
+
+// IdentifierMapping records that two identifiers refer to the same entity. Mappings are symmetric.
+type IdentifierMapping struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	System    string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
-	Value     string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	TargetUri string `protobuf:"bytes,3,opt,name=target_uri,json=targetUri,proto3" json:"target_uri,omitempty"`
+	From      *Identifier          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To        *Identifier          `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	CreatedBy string               `protobuf:"bytes,3,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"` // user that created the mapping
+	Created   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
 }
 
-func (x *IdentifierMapRequest) Reset() {
-	*x = IdentifierMapRequest{}
+func (x *IdentifierMapping) Reset() {
+	*x = IdentifierMapping{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -50,13 +359,13 @@ func (x *IdentifierMapRequest) Reset() {
 	}
 }
 
-func (x *IdentifierMapRequest) String() string {
+func (x *IdentifierMapping) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IdentifierMapRequest) ProtoMessage() {}
+func (*IdentifierMapping) ProtoMessage() {}
 
-func (x *IdentifierMapRequest) ProtoReflect() protoreflect.Message {
+func (x *IdentifierMapping) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -68,46 +377,50 @@ func (x *IdentifierMapRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IdentifierMapRequest.ProtoReflect.Descriptor instead.
-func (*IdentifierMapRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IdentifierMapping.ProtoReflect.Descriptor instead.
+func (*IdentifierMapping) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *IdentifierMapRequest) GetSystem() string {
+func (x *IdentifierMapping) GetFrom() *Identifier {
 	if x != nil {
-		return x.System
+		return x.From
 	}
-	return ""
+	return nil
 }
 
-func (x *IdentifierMapRequest) GetValue() string {
+func (x *IdentifierMapping) GetTo() *Identifier {
 	if x != nil {
-		return x.Value
+		return x.To
 	}
-	return ""
+	return nil
 }
 
-func (x *IdentifierMapRequest) GetTargetUri() string {
+func (x *IdentifierMapping) GetCreatedBy() string {
 	if x != nil {
-		return x.TargetUri
+		return x.CreatedBy
 	}
 	return ""
 }
 
-// PublishDocumentRequest publishes the document(s)
-// The recipient identifier list contains identifiers of those who need to be notified about the document.
-// The resolution of *how* that resolution occurs is at the discretion of the transport, so may conceivably
-// be postal mail, email or some other notification / workflow system.
-type PublishDocumentRequest struct {
+func (x *IdentifierMapping) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+type IdentifierMappings struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Document *Document `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	Identifier *Identifier          `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Mappings   []*IdentifierMapping `protobuf:"bytes,2,rep,name=mappings,proto3" json:"mappings,omitempty"`
 }
 
-func (x *PublishDocumentRequest) Reset() {
-	*x = PublishDocumentRequest{}
+func (x *IdentifierMappings) Reset() {
+	*x = IdentifierMappings{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -115,13 +428,13 @@ func (x *PublishDocumentRequest) Reset() {
 	}
 }
 
-func (x *PublishDocumentRequest) String() string {
+func (x *IdentifierMappings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishDocumentRequest) ProtoMessage() {}
+func (*IdentifierMappings) ProtoMessage() {}
 
-func (x *PublishDocumentRequest) ProtoReflect() protoreflect.Message {
+func (x *IdentifierMappings) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -133,29 +446,37 @@ func (x *PublishDocumentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishDocumentRequest.ProtoReflect.Descriptor instead.
-func (*PublishDocumentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IdentifierMappings.ProtoReflect.Descriptor instead.
+func (*IdentifierMappings) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *PublishDocumentRequest) GetDocument() *Document {
+func (x *IdentifierMappings) GetIdentifier() *Identifier {
 	if x != nil {
-		return x.Document
+		return x.Identifier
 	}
 	return nil
 }
 
-// PublishDocumentResponse is returned on successful publication
-type PublishDocumentResponse struct {
+func (x *IdentifierMappings) GetMappings() []*IdentifierMapping {
+	if x != nil {
+		return x.Mappings
+	}
+	return nil
+}
+
+type IdentifierMapRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id *Identifier `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	System    string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	Value     string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	TargetUri string `protobuf:"bytes,3,opt,name=target_uri,json=targetUri,proto3" json:"target_uri,omitempty"`
 }
 
-func (x *PublishDocumentResponse) Reset() {
-	*x = PublishDocumentResponse{}
+func (x *IdentifierMapRequest) Reset() {
+	*x = IdentifierMapRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -163,13 +484,13 @@ func (x *PublishDocumentResponse) Reset() {
 	}
 }
 
-func (x *PublishDocumentResponse) String() string {
+func (x *IdentifierMapRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishDocumentResponse) ProtoMessage() {}
+func (*IdentifierMapRequest) ProtoMessage() {}
 
-func (x *PublishDocumentResponse) ProtoReflect() protoreflect.Message {
+func (x *IdentifierMapRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -181,29 +502,40 @@ func (x *PublishDocumentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishDocumentResponse.ProtoReflect.Descriptor instead.
-func (*PublishDocumentResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use IdentifierMapRequest.ProtoReflect.Descriptor instead.
+func (*IdentifierMapRequest) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *PublishDocumentResponse) GetId() *Identifier {
+func (x *IdentifierMapRequest) GetSystem() string {
 	if x != nil {
-		return x.Id
+		return x.System
 	}
-	return nil
+	return ""
 }
 
-type NotificationRequest struct {
+func (x *IdentifierMapRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *IdentifierMapRequest) GetTargetUri() string {
+	if x != nil {
+		return x.TargetUri
+	}
+	return ""
+}
+
+type ListSystemsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Recipient *Identifier `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"` // recipient of this notification
-	Patient   *Patient    `protobuf:"bytes,2,opt,name=patient,proto3" json:"patient,omitempty"`     // patient to which this notification refers
 }
 
-func (x *NotificationRequest) Reset() {
-	*x = NotificationRequest{}
+func (x *ListSystemsRequest) Reset() {
+	*x = ListSystemsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -211,13 +543,13 @@ func (x *NotificationRequest) Reset() {
 	}
 }
 
-func (x *NotificationRequest) String() string {
+func (x *ListSystemsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotificationRequest) ProtoMessage() {}
+func (*ListSystemsRequest) ProtoMessage() {}
 
-func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
+func (x *ListSystemsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -229,36 +561,21 @@ func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
-func (*NotificationRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSystemsRequest.ProtoReflect.Descriptor instead.
+func (*ListSystemsRequest) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *NotificationRequest) GetRecipient() *Identifier {
-	if x != nil {
-		return x.Recipient
-	}
-	return nil
-}
-
-func (x *NotificationRequest) GetPatient() *Patient {
-	if x != nil {
-		return x.Patient
-	}
-	return nil
-}
-
-// incomplete
-type NotificationResponse struct {
+type ListSystemsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id *Identifier `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // unique identifier for this notification
+	Systems []*SystemCapabilities `protobuf:"bytes,1,rep,name=systems,proto3" json:"systems,omitempty"`
 }
 
-func (x *NotificationResponse) Reset() {
-	*x = NotificationResponse{}
+func (x *ListSystemsResponse) Reset() {
+	*x = ListSystemsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -266,13 +583,13 @@ func (x *NotificationResponse) Reset() {
 	}
 }
 
-func (x *NotificationResponse) String() string {
+func (x *ListSystemsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotificationResponse) ProtoMessage() {}
+func (*ListSystemsResponse) ProtoMessage() {}
 
-func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
+func (x *ListSystemsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -284,31 +601,32 @@ func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotificationResponse.ProtoReflect.Descriptor instead.
-func (*NotificationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSystemsResponse.ProtoReflect.Descriptor instead.
+func (*ListSystemsResponse) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *NotificationResponse) GetId() *Identifier {
+func (x *ListSystemsResponse) GetSystems() []*SystemCapabilities {
 	if x != nil {
-		return x.Id
+		return x.Systems
 	}
 	return nil
 }
 
-type PractitionerSearchRequest struct {
+// SystemCapabilities describes the support for identifiers within a system
+type SystemCapabilities struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	System    string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
-	Username  string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	FirstName string `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName  string `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	System     *System  `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	Resolvable bool     `protobuf:"varint,2,opt,name=resolvable,proto3" json:"resolvable,omitempty"`
+	MappableTo []string `protobuf:"bytes,3,rep,name=mappable_to,json=mappableTo,proto3" json:"mappable_to,omitempty"` // systems to which identifiers can be mapped, directly or by chaining mappers
+	Validated  bool     `protobuf:"varint,4,opt,name=validated,proto3" json:"validated,omitempty"`                    // whether identifiers are checked against the syntax rules of the system
 }
 
-func (x *PractitionerSearchRequest) Reset() {
-	*x = PractitionerSearchRequest{}
+func (x *SystemCapabilities) Reset() {
+	*x = SystemCapabilities{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_services_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -316,13 +634,13 @@ func (x *PractitionerSearchRequest) Reset() {
 	}
 }
 
-func (x *PractitionerSearchRequest) String() string {
+func (x *SystemCapabilities) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PractitionerSearchRequest) ProtoMessage() {}
+func (*SystemCapabilities) ProtoMessage() {}
 
-func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
+func (x *SystemCapabilities) ProtoReflect() protoreflect.Message {
 	mi := &file_services_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -334,444 +652,6751 @@ func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PractitionerSearchRequest.ProtoReflect.Descriptor instead.
-func (*PractitionerSearchRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SystemCapabilities.ProtoReflect.Descriptor instead.
+func (*SystemCapabilities) Descriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *PractitionerSearchRequest) GetSystem() string {
+func (x *SystemCapabilities) GetSystem() *System {
 	if x != nil {
 		return x.System
 	}
-	return ""
+	return nil
 }
 
-func (x *PractitionerSearchRequest) GetUsername() string {
+func (x *SystemCapabilities) GetResolvable() bool {
 	if x != nil {
-		return x.Username
+		return x.Resolvable
 	}
-	return ""
+	return false
 }
 
-func (x *PractitionerSearchRequest) GetFirstName() string {
+func (x *SystemCapabilities) GetMappableTo() []string {
 	if x != nil {
-		return x.FirstName
+		return x.MappableTo
 	}
-	return ""
+	return nil
 }
 
-func (x *PractitionerSearchRequest) GetLastName() string {
+func (x *SystemCapabilities) GetValidated() bool {
 	if x != nil {
-		return x.LastName
+		return x.Validated
 	}
-	return ""
+	return false
 }
 
-var File_services_proto protoreflect.FileDescriptor
+// IdentifierValidation is the result of checking an identifier against the syntax rules of its system
+type IdentifierValidation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_services_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x05, 0x61, 0x70, 0x69, 0x76, 0x31, 0x1a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
-	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x63, 0x0a,
-	0x14, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x75, 0x72,
-	0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55,
-	0x72, 0x69, 0x22, 0x45, 0x0a, 0x16, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08,
-	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x17, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x22, 0x70, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f,
-	0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12,
-	0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
-	0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x14, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x8b, 0x01, 0x0a, 0x19, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73,
-	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x32, 0xab, 0x01, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x6f, 0x72, 0x12, 0x48, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x13, 0x2e,
-	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e,
-	0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x3a, 0x01, 0x2a, 0x12, 0x50,
-	0x0a, 0x07, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f,
-	0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68,
-	0x32, 0xbb, 0x01, 0x0a, 0x0b, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73,
-	0x12, 0x58, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
-	0x72, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x1a, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x18, 0x12, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
-	0x65, 0x72, 0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x12, 0x52, 0x0a, 0x0d, 0x4d, 0x61,
-	0x70, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x0f, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x09, 0x12, 0x07, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x70, 0x30, 0x01, 0x32, 0x96,
-	0x01, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x82, 0x01, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f,
-	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75,
-	0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x30, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2a, 0x22, 0x14, 0x2f,
-	0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x75, 0x62, 0x6c,
-	0x69, 0x73, 0x68, 0x3a, 0x12, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x61,
-	0x74, 0x61, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x32, 0x6f, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58,
-	0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x6e,
-	0x6f, 0x74, 0x69, 0x66, 0x79, 0x3a, 0x01, 0x2a, 0x32, 0x87, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x61,
-	0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x79, 0x12, 0x6e, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x61, 0x63,
-	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61,
-	0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69,
-	0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x22,
-	0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61,
-	0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68,
-	0x30, 0x01, 0x42, 0x3d, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6c, 0x64, 0x72, 0x69, 0x78,
-	0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x5a, 0x21,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c,
-	0x65, 0x2f, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Valid      bool        `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Identifier *Identifier `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"` // the identifier in canonical form, such as an NHS number without spaces
+	Reason     string      `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`         // reason the identifier is invalid
+	Checked    bool        `protobuf:"varint,4,opt,name=checked,proto3" json:"checked,omitempty"`      // whether the system has syntax rules; identifiers in other systems are always valid
 }
 
-var (
-	file_services_proto_rawDescOnce sync.Once
-	file_services_proto_rawDescData = file_services_proto_rawDesc
-)
+func (x *IdentifierValidation) Reset() {
+	*x = IdentifierValidation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_services_proto_rawDescGZIP() []byte {
-	file_services_proto_rawDescOnce.Do(func() {
-		file_services_proto_rawDescData = protoimpl.X.CompressGZIP(file_services_proto_rawDescData)
-	})
-	return file_services_proto_rawDescData
+func (x *IdentifierValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_services_proto_goTypes = []interface{}{
-	(*IdentifierMapRequest)(nil),      // 0: apiv1.IdentifierMapRequest
-	(*PublishDocumentRequest)(nil),    // 1: apiv1.PublishDocumentRequest
-	(*PublishDocumentResponse)(nil),   // 2: apiv1.PublishDocumentResponse
-	(*NotificationRequest)(nil),       // 3: apiv1.NotificationRequest
-	(*NotificationResponse)(nil),      // 4: apiv1.NotificationResponse
-	(*PractitionerSearchRequest)(nil), // 5: apiv1.PractitionerSearchRequest
-	(*Document)(nil),                  // 6: apiv1.Document
-	(*Identifier)(nil),                // 7: apiv1.Identifier
-	(*Patient)(nil),                   // 8: apiv1.Patient
-	(*LoginRequest)(nil),              // 9: apiv1.LoginRequest
-	(*TokenRefreshRequest)(nil),       // 10: apiv1.TokenRefreshRequest
-	(*LoginResponse)(nil),             // 11: apiv1.LoginResponse
-	(*any.Any)(nil),                   // 12: google.protobuf.Any
-	(*Practitioner)(nil),              // 13: apiv1.Practitioner
+func (*IdentifierValidation) ProtoMessage() {}
+
+func (x *IdentifierValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_services_proto_depIdxs = []int32{
-	6,  // 0: apiv1.PublishDocumentRequest.document:type_name -> apiv1.Document
-	7,  // 1: apiv1.PublishDocumentResponse.id:type_name -> apiv1.Identifier
-	7,  // 2: apiv1.NotificationRequest.recipient:type_name -> apiv1.Identifier
-	8,  // 3: apiv1.NotificationRequest.patient:type_name -> apiv1.Patient
-	7,  // 4: apiv1.NotificationResponse.id:type_name -> apiv1.Identifier
-	9,  // 5: apiv1.Authenticator.Login:input_type -> apiv1.LoginRequest
-	10, // 6: apiv1.Authenticator.Refresh:input_type -> apiv1.TokenRefreshRequest
-	7,  // 7: apiv1.Identifiers.GetIdentifier:input_type -> apiv1.Identifier
-	0,  // 8: apiv1.Identifiers.MapIdentifier:input_type -> apiv1.IdentifierMapRequest
-	1,  // 9: apiv1.DocumentService.PublishDocument:input_type -> apiv1.PublishDocumentRequest
-	3,  // 10: apiv1.NotificationService.Notify:input_type -> apiv1.NotificationRequest
-	5,  // 11: apiv1.PractitionerDirectory.SearchPractitioner:input_type -> apiv1.PractitionerSearchRequest
-	11, // 12: apiv1.Authenticator.Login:output_type -> apiv1.LoginResponse
-	11, // 13: apiv1.Authenticator.Refresh:output_type -> apiv1.LoginResponse
-	12, // 14: apiv1.Identifiers.GetIdentifier:output_type -> google.protobuf.Any
-	7,  // 15: apiv1.Identifiers.MapIdentifier:output_type -> apiv1.Identifier
-	2,  // 16: apiv1.DocumentService.PublishDocument:output_type -> apiv1.PublishDocumentResponse
-	4,  // 17: apiv1.NotificationService.Notify:output_type -> apiv1.NotificationResponse
-	13, // 18: apiv1.PractitionerDirectory.SearchPractitioner:output_type -> apiv1.Practitioner
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+
+// Deprecated: Use IdentifierValidation.ProtoReflect.Descriptor instead.
+func (*IdentifierValidation) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{6}
 }
 
-func init() { file_services_proto_init() }
-func file_services_proto_init() {
-	if File_services_proto != nil {
-		return
+func (x *IdentifierValidation) GetValid() bool {
+	if x != nil {
+		return x.Valid
 	}
-	file_model_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_services_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IdentifierMapRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PublishDocumentRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PublishDocumentResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PractitionerSearchRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
+	return false
+}
+
+func (x *IdentifierValidation) GetIdentifier() *Identifier {
+	if x != nil {
+		return x.Identifier
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_services_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
-			NumExtensions: 0,
-			NumServices:   5,
-		},
-		GoTypes:           file_services_proto_goTypes,
-		DependencyIndexes: file_services_proto_depIdxs,
-		MessageInfos:      file_services_proto_msgTypes,
-	}.Build()
-	File_services_proto = out.File
-	file_services_proto_rawDesc = nil
-	file_services_proto_goTypes = nil
-	file_services_proto_depIdxs = nil
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func (x *IdentifierValidation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (x *IdentifierValidation) GetChecked() bool {
+	if x != nil {
+		return x.Checked
+	}
+	return false
+}
 
-// AuthenticatorClient is the client API for Authenticator service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthenticatorClient interface {
-	// Login authenticates using the credentials specified and returns an authentication token
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	// Refresh refreshes a currently valid token
-	Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+// DocumentVersion records the publication of a version of a document
+type DocumentVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId *Identifier              `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"` // identifier of the document as published
+	Version    int32                    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`                        // version, starting at 1
+	Replaces   *Identifier              `protobuf:"bytes,3,opt,name=replaces,proto3" json:"replaces,omitempty"`                       // identifier of the previous version, if any
+	ReplacedBy *Identifier              `protobuf:"bytes,4,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"` // identifier of the subsequent version, if superseded
+	Published  *timestamp.Timestamp     `protobuf:"bytes,5,opt,name=published,proto3" json:"published,omitempty"`
+	Response   *PublishDocumentResponse `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"` // response on publication, including the identifier within the repository
 }
 
-type authenticatorClient struct {
-	cc grpc.ClientConnInterface
+func (x *DocumentVersion) Reset() {
+	*x = DocumentVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func NewAuthenticatorClient(cc grpc.ClientConnInterface) AuthenticatorClient {
-	return &authenticatorClient{cc}
+func (x *DocumentVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (c *authenticatorClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Login", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (*DocumentVersion) ProtoMessage() {}
+
+func (x *DocumentVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return out, nil
+	return mi.MessageOf(x)
 }
 
-func (c *authenticatorClient) Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Refresh", in, out, opts...)
-	if err != nil {
-		return nil, err
+// Deprecated: Use DocumentVersion.ProtoReflect.Descriptor instead.
+func (*DocumentVersion) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DocumentVersion) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
 	}
-	return out, nil
+	return nil
 }
 
-// AuthenticatorServer is the server API for Authenticator service.
-type AuthenticatorServer interface {
-	// Login authenticates using the credentials specified and returns an authentication token
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	// Refresh refreshes a currently valid token
-	Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error)
+func (x *DocumentVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-// UnimplementedAuthenticatorServer can be embedded to have forward compatible implementations.
-type UnimplementedAuthenticatorServer struct {
+func (x *DocumentVersion) GetReplaces() *Identifier {
+	if x != nil {
+		return x.Replaces
+	}
+	return nil
 }
 
-func (*UnimplementedAuthenticatorServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+func (x *DocumentVersion) GetReplacedBy() *Identifier {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return nil
 }
-func (*UnimplementedAuthenticatorServer) Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+
+func (x *DocumentVersion) GetPublished() *timestamp.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
 }
 
-func RegisterAuthenticatorServer(s *grpc.Server, srv AuthenticatorServer) {
-	s.RegisterService(&_Authenticator_serviceDesc, srv)
+func (x *DocumentVersion) GetResponse() *PublishDocumentResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+// DocumentHistory is the history of versions of a document, oldest first
+type DocumentHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Versions []*DocumentVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+}
+
+func (x *DocumentHistory) Reset() {
+	*x = DocumentHistory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentHistory) ProtoMessage() {}
+
+func (x *DocumentHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentHistory.ProtoReflect.Descriptor instead.
+func (*DocumentHistory) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DocumentHistory) GetVersions() []*DocumentVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// PublicationStatus is the status of publication of a queued document
+type PublicationStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Receipt    *Identifier              `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	DocumentId *Identifier              `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Status     PublicationStatus_Status `protobuf:"varint,3,opt,name=status,proto3,enum=apiv1.PublicationStatus_Status" json:"status,omitempty"`
+	Attempts   int32                    `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error      string                   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`       // error from the last attempt, if not published
+	Response   *PublishDocumentResponse `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"` // response from the repository, if published
+	Updated    *timestamp.Timestamp     `protobuf:"bytes,7,opt,name=updated,proto3" json:"updated,omitempty"`
+}
+
+func (x *PublicationStatus) Reset() {
+	*x = PublicationStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicationStatus) ProtoMessage() {}
+
+func (x *PublicationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicationStatus.ProtoReflect.Descriptor instead.
+func (*PublicationStatus) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PublicationStatus) GetReceipt() *Identifier {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+func (x *PublicationStatus) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *PublicationStatus) GetStatus() PublicationStatus_Status {
+	if x != nil {
+		return x.Status
+	}
+	return PublicationStatus_UNKNOWN
+}
+
+func (x *PublicationStatus) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *PublicationStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PublicationStatus) GetResponse() *PublishDocumentResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *PublicationStatus) GetUpdated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+type ListPendingDocumentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeadLettersOnly bool   `protobuf:"varint,1,opt,name=dead_letters_only,json=deadLettersOnly,proto3" json:"dead_letters_only,omitempty"`
+	PageSize        int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // maximum documents to return; all remaining documents if zero
+	PageToken       string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // token from a previous response, to return the next page
+}
+
+func (x *ListPendingDocumentsRequest) Reset() {
+	*x = ListPendingDocumentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPendingDocumentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingDocumentsRequest) ProtoMessage() {}
+
+func (x *ListPendingDocumentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingDocumentsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingDocumentsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPendingDocumentsRequest) GetDeadLettersOnly() bool {
+	if x != nil {
+		return x.DeadLettersOnly
+	}
+	return false
+}
+
+func (x *ListPendingDocumentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPendingDocumentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// PendingDocument is a document queued for retry after failed publication
+type PendingDocument struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId  *Identifier              `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Request     *PublishDocumentRequest  `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`    // the original request; omitted when listing
+	Attempts    int32                    `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"` // number of attempts to publish so far
+	Created     *timestamp.Timestamp     `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	NextAttempt *timestamp.Timestamp     `protobuf:"bytes,5,opt,name=next_attempt,json=nextAttempt,proto3" json:"next_attempt,omitempty"`
+	LastError   string                   `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	DeadLetter  bool                     `protobuf:"varint,7,opt,name=dead_letter,json=deadLetter,proto3" json:"dead_letter,omitempty"` // abandoned after the maximum number of attempts
+	Receipt     *Identifier              `protobuf:"bytes,8,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	Response    *PublishDocumentResponse `protobuf:"bytes,9,opt,name=response,proto3" json:"response,omitempty"` // response from the repository, once published
+	Tenant      string                   `protobuf:"bytes,10,opt,name=tenant,proto3" json:"tenant,omitempty"`    // tenant on whose behalf the document was published, if any
+}
+
+func (x *PendingDocument) Reset() {
+	*x = PendingDocument{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingDocument) ProtoMessage() {}
+
+func (x *PendingDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingDocument.ProtoReflect.Descriptor instead.
+func (*PendingDocument) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PendingDocument) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetRequest() *PublishDocumentRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *PendingDocument) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetNextAttempt() *timestamp.Timestamp {
+	if x != nil {
+		return x.NextAttempt
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *PendingDocument) GetDeadLetter() bool {
+	if x != nil {
+		return x.DeadLetter
+	}
+	return false
+}
+
+func (x *PendingDocument) GetReceipt() *Identifier {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetResponse() *PublishDocumentResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *PendingDocument) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type PendingDocuments struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Documents     []*PendingDocument `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`                                // in order of creation
+	NextPageToken string             `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // token to fetch the next page, empty if there are no more documents
+}
+
+func (x *PendingDocuments) Reset() {
+	*x = PendingDocuments{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingDocuments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingDocuments) ProtoMessage() {}
+
+func (x *PendingDocuments) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingDocuments.ProtoReflect.Descriptor instead.
+func (*PendingDocuments) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PendingDocuments) GetDocuments() []*PendingDocument {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+func (x *PendingDocuments) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// PublishDocumentRequest publishes the document(s)
+// The recipient identifier list contains identifiers of those who need to be notified about the document.
+// The resolution of *how* that resolution occurs is at the discretion of the transport, so may conceivably
+// be postal mail, email or some other notification / workflow system.
+type PublishDocumentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Document         *Document          `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	Async            bool               `protobuf:"varint,2,opt,name=async,proto3" json:"async,omitempty"`                                              // queue the document for publication and return a receipt immediately, rather than waiting
+	AllowDeceased    bool               `protobuf:"varint,3,opt,name=allow_deceased,json=allowDeceased,proto3" json:"allow_deceased,omitempty"`         // permit publication for a deceased patient, if required by the server's deceased patient policy
+	Force            bool               `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`                                              // publish even if a document with the same identifier has already been published, rather than returning the original receipt
+	Replaces         *Identifier        `protobuf:"bytes,5,opt,name=replaces,proto3" json:"replaces,omitempty"`                                         // identifier of a previously published document replaced by this document, such as an amended version
+	PreviousVersions []*DocumentVersion `protobuf:"bytes,6,rep,name=previous_versions,json=previousVersions,proto3" json:"previous_versions,omitempty"` // set by the server: previous versions of the document replaced, most recent first, for repositories supporting supersession
+}
+
+func (x *PublishDocumentRequest) Reset() {
+	*x = PublishDocumentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishDocumentRequest) ProtoMessage() {}
+
+func (x *PublishDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishDocumentRequest.ProtoReflect.Descriptor instead.
+func (*PublishDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PublishDocumentRequest) GetDocument() *Document {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *PublishDocumentRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+func (x *PublishDocumentRequest) GetAllowDeceased() bool {
+	if x != nil {
+		return x.AllowDeceased
+	}
+	return false
+}
+
+func (x *PublishDocumentRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *PublishDocumentRequest) GetReplaces() *Identifier {
+	if x != nil {
+		return x.Replaces
+	}
+	return nil
+}
+
+func (x *PublishDocumentRequest) GetPreviousVersions() []*DocumentVersion {
+	if x != nil {
+		return x.PreviousVersions
+	}
+	return nil
+}
+
+// UploadDocumentRequest is a message in a stream uploading a single document
+type UploadDocumentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Value:
+	//	*UploadDocumentRequest_Request
+	//	*UploadDocumentRequest_Chunk
+	Value isUploadDocumentRequest_Value `protobuf_oneof:"value"`
+}
+
+func (x *UploadDocumentRequest) Reset() {
+	*x = UploadDocumentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDocumentRequest) ProtoMessage() {}
+
+func (x *UploadDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDocumentRequest.ProtoReflect.Descriptor instead.
+func (*UploadDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{14}
+}
+
+func (m *UploadDocumentRequest) GetValue() isUploadDocumentRequest_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *UploadDocumentRequest) GetRequest() *PublishDocumentRequest {
+	if x, ok := x.GetValue().(*UploadDocumentRequest_Request); ok {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *UploadDocumentRequest) GetChunk() []byte {
+	if x, ok := x.GetValue().(*UploadDocumentRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isUploadDocumentRequest_Value interface {
+	isUploadDocumentRequest_Value()
+}
+
+type UploadDocumentRequest_Request struct {
+	Request *PublishDocumentRequest `protobuf:"bytes,1,opt,name=request,proto3,oneof"` // first message only: the request, with the document's content type but not its data
+}
+
+type UploadDocumentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // subsequent messages: the next chunk of the content of the document
+}
+
+func (*UploadDocumentRequest_Request) isUploadDocumentRequest_Value() {}
+
+func (*UploadDocumentRequest_Chunk) isUploadDocumentRequest_Value() {}
+
+// PublishDocumentResponse is returned on successful publication
+// When publishing in batch, a response is returned for each document; failures are
+// reported using error_code and error.
+type PublishDocumentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *Identifier `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                   // identifier of the published document within the repository
+	DocumentId *Identifier `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"` // identifier of the document as specified in the request
+	ErrorCode  int32       `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`   // gRPC status code, if publication failed (batch only)
+	Error      string      `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                             // error message, if publication failed (batch only)
+	Deliveries []*Delivery `protobuf:"bytes,5,rep,name=deliveries,proto3" json:"deliveries,omitempty"`                   // onward deliveries e.g. to the patient's general practice
+	Queued     bool        `protobuf:"varint,6,opt,name=queued,proto3" json:"queued,omitempty"`                          // document has been queued for publication, either on request or for retry after failure
+	Receipt    *Identifier `protobuf:"bytes,7,opt,name=receipt,proto3" json:"receipt,omitempty"`                         // receipt for a queued document, used to poll for publication status
+	Duplicate  bool        `protobuf:"varint,8,opt,name=duplicate,proto3" json:"duplicate,omitempty"`                    // document had already been published, so the original response is returned and the document not published again
+}
+
+func (x *PublishDocumentResponse) Reset() {
+	*x = PublishDocumentResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishDocumentResponse) ProtoMessage() {}
+
+func (x *PublishDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishDocumentResponse.ProtoReflect.Descriptor instead.
+func (*PublishDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PublishDocumentResponse) GetId() *Identifier {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *PublishDocumentResponse) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *PublishDocumentResponse) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PublishDocumentResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PublishDocumentResponse) GetDeliveries() []*Delivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *PublishDocumentResponse) GetQueued() bool {
+	if x != nil {
+		return x.Queued
+	}
+	return false
+}
+
+func (x *PublishDocumentResponse) GetReceipt() *Identifier {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+func (x *PublishDocumentResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+// Delivery records the onward delivery of a document to a recipient, such as a general practice
+type Delivery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Recipient  string               `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`                  // recipient e.g. ODS code of general practice
+	Repository string               `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`                // name of the repository used for delivery
+	MessageId  *Identifier          `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // identifier of the message used for delivery, if sent
+	Status     Delivery_Status      `protobuf:"varint,4,opt,name=status,proto3,enum=apiv1.Delivery_Status" json:"status,omitempty"`
+	Error      string               `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`     // reason for failure, if failed
+	Updated    *timestamp.Timestamp `protobuf:"bytes,6,opt,name=updated,proto3" json:"updated,omitempty"` // time of last change in status
+}
+
+func (x *Delivery) Reset() {
+	*x = Delivery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Delivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Delivery) ProtoMessage() {}
+
+func (x *Delivery) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Delivery.ProtoReflect.Descriptor instead.
+func (*Delivery) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Delivery) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Delivery) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *Delivery) GetMessageId() *Identifier {
+	if x != nil {
+		return x.MessageId
+	}
+	return nil
+}
+
+func (x *Delivery) GetStatus() Delivery_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Delivery_UNKNOWN
+}
+
+func (x *Delivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Delivery) GetUpdated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+// DeliveryStatus is the status of all onward deliveries of a document
+type DeliveryStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId *Identifier `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Deliveries []*Delivery `protobuf:"bytes,2,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+}
+
+func (x *DeliveryStatus) Reset() {
+	*x = DeliveryStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeliveryStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryStatus) ProtoMessage() {}
+
+func (x *DeliveryStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryStatus.ProtoReflect.Descriptor instead.
+func (*DeliveryStatus) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeliveryStatus) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *DeliveryStatus) GetDeliveries() []*Delivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type NotificationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Recipient *Identifier `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"` // recipient of this notification
+	Patient   *Patient    `protobuf:"bytes,2,opt,name=patient,proto3" json:"patient,omitempty"`     // patient to which this notification refers
+}
+
+func (x *NotificationRequest) Reset() {
+	*x = NotificationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationRequest) ProtoMessage() {}
+
+func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
+func (*NotificationRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *NotificationRequest) GetRecipient() *Identifier {
+	if x != nil {
+		return x.Recipient
+	}
+	return nil
+}
+
+func (x *NotificationRequest) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+// incomplete
+type NotificationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id *Identifier `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // unique identifier for this notification
+}
+
+func (x *NotificationResponse) Reset() {
+	*x = NotificationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotificationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationResponse) ProtoMessage() {}
+
+func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationResponse.ProtoReflect.Descriptor instead.
+func (*NotificationResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *NotificationResponse) GetId() *Identifier {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+// UpdatePatientDemographicsRequest is a change in the contact details of a patient.
+// Each list, if specified, replaces the existing details; details not specified are left unchanged.
+type UpdatePatientDemographicsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier *Identifier  `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Addresses  []*Address   `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Telephones []*Telephone `protobuf:"bytes,3,rep,name=telephones,proto3" json:"telephones,omitempty"`
+	Emails     []string     `protobuf:"bytes,4,rep,name=emails,proto3" json:"emails,omitempty"`
+}
+
+func (x *UpdatePatientDemographicsRequest) Reset() {
+	*x = UpdatePatientDemographicsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePatientDemographicsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePatientDemographicsRequest) ProtoMessage() {}
+
+func (x *UpdatePatientDemographicsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePatientDemographicsRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePatientDemographicsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdatePatientDemographicsRequest) GetIdentifier() *Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *UpdatePatientDemographicsRequest) GetAddresses() []*Address {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *UpdatePatientDemographicsRequest) GetTelephones() []*Telephone {
+	if x != nil {
+		return x.Telephones
+	}
+	return nil
+}
+
+func (x *UpdatePatientDemographicsRequest) GetEmails() []string {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+type PrefetchProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier *Identifier `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`                 // identifier of the patient fetched
+	ErrorCode  int32       `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // gRPC status code, if fetching failed
+	Error      string      `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                           // error message, if fetching failed
+	Received   int32       `protobuf:"varint,4,opt,name=received,proto3" json:"received,omitempty"`                    // number of identifiers received so far
+	Completed  int32       `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`                  // number of identifiers fetched so far, including failures
+	Failed     int32       `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`                        // number of identifiers that could not be fetched so far
+}
+
+func (x *PrefetchProgress) Reset() {
+	*x = PrefetchProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrefetchProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefetchProgress) ProtoMessage() {}
+
+func (x *PrefetchProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefetchProgress.ProtoReflect.Descriptor instead.
+func (*PrefetchProgress) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PrefetchProgress) GetIdentifier() *Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *PrefetchProgress) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PrefetchProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PrefetchProgress) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *PrefetchProgress) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *PrefetchProgress) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type PatientStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier   *Identifier          `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Status       PatientStatus_Status `protobuf:"varint,2,opt,name=status,proto3,enum=apiv1.PatientStatus_Status" json:"status,omitempty"`
+	DeceasedDate *timestamp.Timestamp `protobuf:"bytes,3,opt,name=deceased_date,json=deceasedDate,proto3" json:"deceased_date,omitempty"` // date of death, if deceased and known
+}
+
+func (x *PatientStatus) Reset() {
+	*x = PatientStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientStatus) ProtoMessage() {}
+
+func (x *PatientStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientStatus.ProtoReflect.Descriptor instead.
+func (*PatientStatus) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PatientStatus) GetIdentifier() *Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *PatientStatus) GetStatus() PatientStatus_Status {
+	if x != nil {
+		return x.Status
+	}
+	return PatientStatus_UNKNOWN
+}
+
+func (x *PatientStatus) GetDeceasedDate() *timestamp.Timestamp {
+	if x != nil {
+		return x.DeceasedDate
+	}
+	return nil
+}
+
+// PatientLink records that one patient record has been superseded by another, such as following a merge
+type PatientLink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Superseded *Identifier          `protobuf:"bytes,1,opt,name=superseded,proto3" json:"superseded,omitempty"`             // identifier of the record that was merged
+	Current    *Identifier          `protobuf:"bytes,2,opt,name=current,proto3" json:"current,omitempty"`                   // identifier of the record into which it was merged
+	DateTime   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"` // time at which the merge was first seen
+}
+
+func (x *PatientLink) Reset() {
+	*x = PatientLink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientLink) ProtoMessage() {}
+
+func (x *PatientLink) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientLink.ProtoReflect.Descriptor instead.
+func (*PatientLink) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PatientLink) GetSuperseded() *Identifier {
+	if x != nil {
+		return x.Superseded
+	}
+	return nil
+}
+
+func (x *PatientLink) GetCurrent() *Identifier {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *PatientLink) GetDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+type PatientLinks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier *Identifier    `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Links      []*PatientLink `protobuf:"bytes,2,rep,name=links,proto3" json:"links,omitempty"`
+	Current    *Identifier    `protobuf:"bytes,3,opt,name=current,proto3" json:"current,omitempty"` // identifier of the current record, if the identifier requested has been superseded
+}
+
+func (x *PatientLinks) Reset() {
+	*x = PatientLinks{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientLinks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientLinks) ProtoMessage() {}
+
+func (x *PatientLinks) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientLinks.ProtoReflect.Descriptor instead.
+func (*PatientLinks) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PatientLinks) GetIdentifier() *Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *PatientLinks) GetLinks() []*PatientLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *PatientLinks) GetCurrent() *Identifier {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+type PatientSearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lastname   string `protobuf:"bytes,1,opt,name=lastname,proto3" json:"lastname,omitempty"`
+	Firstnames string `protobuf:"bytes,2,opt,name=firstnames,proto3" json:"firstnames,omitempty"`
+	BirthDate  *Date  `protobuf:"bytes,6,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Gender     Gender `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
+	Postcode   string `protobuf:"bytes,5,opt,name=postcode,proto3" json:"postcode,omitempty"`
+}
+
+func (x *PatientSearchRequest) Reset() {
+	*x = PatientSearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientSearchRequest) ProtoMessage() {}
+
+func (x *PatientSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientSearchRequest.ProtoReflect.Descriptor instead.
+func (*PatientSearchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PatientSearchRequest) GetLastname() string {
+	if x != nil {
+		return x.Lastname
+	}
+	return ""
+}
+
+func (x *PatientSearchRequest) GetFirstnames() string {
+	if x != nil {
+		return x.Firstnames
+	}
+	return ""
+}
+
+func (x *PatientSearchRequest) GetBirthDate() *Date {
+	if x != nil {
+		return x.BirthDate
+	}
+	return nil
+}
+
+func (x *PatientSearchRequest) GetGender() Gender {
+	if x != nil {
+		return x.Gender
+	}
+	return Gender_UNKNOWN
+}
+
+func (x *PatientSearchRequest) GetPostcode() string {
+	if x != nil {
+		return x.Postcode
+	}
+	return ""
+}
+
+type ClinicScheduleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Clinic    *Identifier `protobuf:"bytes,1,opt,name=clinic,proto3" json:"clinic,omitempty"`
+	Date      string      `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`                            // date of the clinic (YYYY-MM-DD), today if omitted
+	PageSize  int32       `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // maximum appointments to return; all remaining appointments if zero
+	PageToken string      `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // token from a previous response, to return the next page
+}
+
+func (x *ClinicScheduleRequest) Reset() {
+	*x = ClinicScheduleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClinicScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClinicScheduleRequest) ProtoMessage() {}
+
+func (x *ClinicScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClinicScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ClinicScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ClinicScheduleRequest) GetClinic() *Identifier {
+	if x != nil {
+		return x.Clinic
+	}
+	return nil
+}
+
+func (x *ClinicScheduleRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ClinicScheduleRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ClinicScheduleRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ClinicSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Clinic        *Identifier    `protobuf:"bytes,1,opt,name=clinic,proto3" json:"clinic,omitempty"`
+	Date          string         `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Appointments  []*Appointment `protobuf:"bytes,3,rep,name=appointments,proto3" json:"appointments,omitempty"`                          // in order of start time
+	NextPageToken string         `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // token to fetch the next page, empty if there are no more appointments
+}
+
+func (x *ClinicSchedule) Reset() {
+	*x = ClinicSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClinicSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClinicSchedule) ProtoMessage() {}
+
+func (x *ClinicSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClinicSchedule.ProtoReflect.Descriptor instead.
+func (*ClinicSchedule) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClinicSchedule) GetClinic() *Identifier {
+	if x != nil {
+		return x.Clinic
+	}
+	return nil
+}
+
+func (x *ClinicSchedule) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ClinicSchedule) GetAppointments() []*Appointment {
+	if x != nil {
+		return x.Appointments
+	}
+	return nil
+}
+
+func (x *ClinicSchedule) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// AccountStatus is the status of a user's directory account
+type AccountStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User                 *Identifier          `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Disabled             bool                 `protobuf:"varint,2,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	Locked               bool                 `protobuf:"varint,3,opt,name=locked,proto3" json:"locked,omitempty"` // locked out, e.g. after too many failed logins
+	PasswordExpired      bool                 `protobuf:"varint,4,opt,name=password_expired,json=passwordExpired,proto3" json:"password_expired,omitempty"`
+	PasswordNeverExpires bool                 `protobuf:"varint,5,opt,name=password_never_expires,json=passwordNeverExpires,proto3" json:"password_never_expires,omitempty"`
+	PasswordExpires      *timestamp.Timestamp `protobuf:"bytes,6,opt,name=password_expires,json=passwordExpires,proto3" json:"password_expires,omitempty"` // when the password expires, unless it never expires
+	PasswordLastSet      *timestamp.Timestamp `protobuf:"bytes,7,opt,name=password_last_set,json=passwordLastSet,proto3" json:"password_last_set,omitempty"`
+}
+
+func (x *AccountStatus) Reset() {
+	*x = AccountStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccountStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountStatus) ProtoMessage() {}
+
+func (x *AccountStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountStatus.ProtoReflect.Descriptor instead.
+func (*AccountStatus) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AccountStatus) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *AccountStatus) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *AccountStatus) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *AccountStatus) GetPasswordExpired() bool {
+	if x != nil {
+		return x.PasswordExpired
+	}
+	return false
+}
+
+func (x *AccountStatus) GetPasswordNeverExpires() bool {
+	if x != nil {
+		return x.PasswordNeverExpires
+	}
+	return false
+}
+
+func (x *AccountStatus) GetPasswordExpires() *timestamp.Timestamp {
+	if x != nil {
+		return x.PasswordExpires
+	}
+	return nil
+}
+
+func (x *AccountStatus) GetPasswordLastSet() *timestamp.Timestamp {
+	if x != nil {
+		return x.PasswordLastSet
+	}
+	return nil
+}
+
+type ChangePasswordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User            *Identifier `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	CurrentPassword string      `protobuf:"bytes,2,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string      `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChangePasswordRequest) GetUser() *Identifier {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type PractitionerSearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	System     string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	Username   string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	FirstName  string `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName   string `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Department string `protobuf:"bytes,5,opt,name=department,proto3" json:"department,omitempty"`
+	PageSize   int32  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // maximum results to stream; all remaining results if zero
+	PageToken  string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // token from the trailer of a previous search, to stream the next page
+}
+
+func (x *PractitionerSearchRequest) Reset() {
+	*x = PractitionerSearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PractitionerSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PractitionerSearchRequest) ProtoMessage() {}
+
+func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PractitionerSearchRequest.ProtoReflect.Descriptor instead.
+func (*PractitionerSearchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PractitionerSearchRequest) GetSystem() string {
+	if x != nil {
+		return x.System
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PractitionerSearchRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ConceptSearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	S               string  `protobuf:"bytes,1,opt,name=s,proto3" json:"s,omitempty"`                                                     // search text, mandatory
+	Constraint      string  `protobuf:"bytes,2,opt,name=constraint,proto3" json:"constraint,omitempty"`                                   // expression constraint (ECL) e.g. "<< 64572001 |Disease|" or "<! 404684003 OR << 71388002"
+	Refsets         []int64 `protobuf:"varint,3,rep,packed,name=refsets,proto3" json:"refsets,omitempty"`                                 // limit to concepts in any of these reference sets
+	MaximumHits     int32   `protobuf:"varint,4,opt,name=maximum_hits,json=maximumHits,proto3" json:"maximum_hits,omitempty"`             // maximum number of results; default used if zero
+	IncludeInactive bool    `protobuf:"varint,5,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"` // include inactive concepts
+	Fuzzy           bool    `protobuf:"varint,6,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`                                            // always use fuzzy matching, rather than only when there are no exact matches
+}
+
+func (x *ConceptSearchRequest) Reset() {
+	*x = ConceptSearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConceptSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConceptSearchRequest) ProtoMessage() {}
+
+func (x *ConceptSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConceptSearchRequest.ProtoReflect.Descriptor instead.
+func (*ConceptSearchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ConceptSearchRequest) GetS() string {
+	if x != nil {
+		return x.S
+	}
+	return ""
+}
+
+func (x *ConceptSearchRequest) GetConstraint() string {
+	if x != nil {
+		return x.Constraint
+	}
+	return ""
+}
+
+func (x *ConceptSearchRequest) GetRefsets() []int64 {
+	if x != nil {
+		return x.Refsets
+	}
+	return nil
+}
+
+func (x *ConceptSearchRequest) GetMaximumHits() int32 {
+	if x != nil {
+		return x.MaximumHits
+	}
+	return 0
+}
+
+func (x *ConceptSearchRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+func (x *ConceptSearchRequest) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+type ConceptSearchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*ConceptSearchResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ConceptSearchResponse) Reset() {
+	*x = ConceptSearchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConceptSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConceptSearchResponse) ProtoMessage() {}
+
+func (x *ConceptSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConceptSearchResponse.ProtoReflect.Descriptor instead.
+func (*ConceptSearchResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ConceptSearchResponse) GetItems() []*ConceptSearchResponse_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifiers []*Identifier `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"` // identifiers of the patients of interest, e.g. NHS number
+	Cursor      string        `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`           // cursor of the last event received, to resume a subscription after that event
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SubscribeRequest) GetIdentifiers() []*Identifier {
+	if x != nil {
+		return x.Identifiers
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// ChangeEvent records a change to the record of a subscribed patient
+type ChangeEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cursor     string               `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // opaque cursor used to resume a subscription after this event
+	Type       ChangeEvent_Type     `protobuf:"varint,2,opt,name=type,proto3,enum=apiv1.ChangeEvent_Type" json:"type,omitempty"`
+	DateTime   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	Subject    *Identifier          `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`                         // identifier of the patient, as subscribed
+	Patient    *Patient             `protobuf:"bytes,5,opt,name=patient,proto3" json:"patient,omitempty"`                         // patient demographics, for demographic updates and deaths
+	DocumentId *Identifier          `protobuf:"bytes,6,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"` // identifier of the published document
+	Merged     *Identifier          `protobuf:"bytes,7,opt,name=merged,proto3" json:"merged,omitempty"`                           // identifier of the record merged into the patient's record
+}
+
+func (x *ChangeEvent) Reset() {
+	*x = ChangeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEvent) ProtoMessage() {}
+
+func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEvent.ProtoReflect.Descriptor instead.
+func (*ChangeEvent) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ChangeEvent) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ChangeEvent) GetType() ChangeEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeEvent_UNKNOWN
+}
+
+func (x *ChangeEvent) GetDateTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetSubject() *Identifier {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetDocumentId() *Identifier {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetMerged() *Identifier {
+	if x != nil {
+		return x.Merged
+	}
+	return nil
+}
+
+type BackendMaintenance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Backend string                  `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"` // name of the backend e.g. "cav-pms" or "wales-empi"
+	Mode    BackendMaintenance_Mode `protobuf:"varint,2,opt,name=mode,proto3,enum=apiv1.BackendMaintenance_Mode" json:"mode,omitempty"`
+	Reason  string                  `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // reason reported to clients, e.g. "PAS upgrade until 18:00"
+	Since   *timestamp.Timestamp    `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`   // time from which the backend has been in maintenance
+}
+
+func (x *BackendMaintenance) Reset() {
+	*x = BackendMaintenance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackendMaintenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendMaintenance) ProtoMessage() {}
+
+func (x *BackendMaintenance) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendMaintenance.ProtoReflect.Descriptor instead.
+func (*BackendMaintenance) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BackendMaintenance) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *BackendMaintenance) GetMode() BackendMaintenance_Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return BackendMaintenance_AVAILABLE
+}
+
+func (x *BackendMaintenance) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BackendMaintenance) GetSince() *timestamp.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type ListMaintenanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListMaintenanceRequest) Reset() {
+	*x = ListMaintenanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceRequest) ProtoMessage() {}
+
+func (x *ListMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{36}
+}
+
+type ListMaintenanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Backends []*BackendMaintenance `protobuf:"bytes,1,rep,name=backends,proto3" json:"backends,omitempty"`
+}
+
+func (x *ListMaintenanceResponse) Reset() {
+	*x = ListMaintenanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceResponse) ProtoMessage() {}
+
+func (x *ListMaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListMaintenanceResponse) GetBackends() []*BackendMaintenance {
+	if x != nil {
+		return x.Backends
+	}
+	return nil
+}
+
+type GetConfigurationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetConfigurationRequest) Reset() {
+	*x = GetConfigurationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigurationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigurationRequest) ProtoMessage() {}
+
+func (x *GetConfigurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigurationRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{38}
+}
+
+type Configuration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Settings map[string]string `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // configuration key e.g. "empi-url" -> value
+}
+
+func (x *Configuration) Reset() {
+	*x = Configuration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Configuration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Configuration) ProtoMessage() {}
+
+func (x *Configuration) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Configuration.ProtoReflect.Descriptor instead.
+func (*Configuration) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Configuration) GetSettings() map[string]string {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ListProvidersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListProvidersRequest) Reset() {
+	*x = ListProvidersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProvidersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvidersRequest) ProtoMessage() {}
+
+func (x *ListProvidersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvidersRequest.ProtoReflect.Descriptor instead.
+func (*ListProvidersRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{40}
+}
+
+type ListProvidersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Providers    []string `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`                           // names of registered service providers
+	HealthChecks []string `protobuf:"bytes,2,rep,name=health_checks,json=healthChecks,proto3" json:"health_checks,omitempty"` // names of services with registered health checks
+	Resolvers    []string `protobuf:"bytes,3,rep,name=resolvers,proto3" json:"resolvers,omitempty"`                           // identifier systems that can be resolved
+	Mappers      []string `protobuf:"bytes,4,rep,name=mappers,proto3" json:"mappers,omitempty"`                               // identifier mappers, as "from-uri -> to-uri"
+}
+
+func (x *ListProvidersResponse) Reset() {
+	*x = ListProvidersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProvidersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvidersResponse) ProtoMessage() {}
+
+func (x *ListProvidersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvidersResponse.ProtoReflect.Descriptor instead.
+func (*ListProvidersResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListProvidersResponse) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *ListProvidersResponse) GetHealthChecks() []string {
+	if x != nil {
+		return x.HealthChecks
+	}
+	return nil
+}
+
+func (x *ListProvidersResponse) GetResolvers() []string {
+	if x != nil {
+		return x.Resolvers
+	}
+	return nil
+}
+
+func (x *ListProvidersResponse) GetMappers() []string {
+	if x != nil {
+		return x.Mappers
+	}
+	return nil
+}
+
+type ReloadRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReloadRulesRequest) Reset() {
+	*x = ReloadRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadRulesRequest) ProtoMessage() {}
+
+func (x *ReloadRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadRulesRequest.ProtoReflect.Descriptor instead.
+func (*ReloadRulesRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{42}
+}
+
+type ReloadRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules int32 `protobuf:"varint,1,opt,name=rules,proto3" json:"rules,omitempty"` // number of rules loaded
+}
+
+func (x *ReloadRulesResponse) Reset() {
+	*x = ReloadRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadRulesResponse) ProtoMessage() {}
+
+func (x *ReloadRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadRulesResponse.ProtoReflect.Descriptor instead.
+func (*ReloadRulesResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ReloadRulesResponse) GetRules() int32 {
+	if x != nil {
+		return x.Rules
+	}
+	return 0
+}
+
+type LogLevel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Component string         `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"` // component, as used to prefix log messages e.g. "empi", "server"
+	Level     LogLevel_Level `protobuf:"varint,2,opt,name=level,proto3,enum=apiv1.LogLevel_Level" json:"level,omitempty"`
+}
+
+func (x *LogLevel) Reset() {
+	*x = LogLevel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevel) ProtoMessage() {}
+
+func (x *LogLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevel.ProtoReflect.Descriptor instead.
+func (*LogLevel) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *LogLevel) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *LogLevel) GetLevel() LogLevel_Level {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_INFO
+}
+
+type LogLevels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Default LogLevel_Level `protobuf:"varint,1,opt,name=default,proto3,enum=apiv1.LogLevel_Level" json:"default,omitempty"`
+	Levels  []*LogLevel    `protobuf:"bytes,2,rep,name=levels,proto3" json:"levels,omitempty"`
+}
+
+func (x *LogLevels) Reset() {
+	*x = LogLevels{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogLevels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevels) ProtoMessage() {}
+
+func (x *LogLevels) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevels.ProtoReflect.Descriptor instead.
+func (*LogLevels) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LogLevels) GetDefault() LogLevel_Level {
+	if x != nil {
+		return x.Default
+	}
+	return LogLevel_INFO
+}
+
+func (x *LogLevels) GetLevels() []*LogLevel {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+type Job struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Schedule string               `protobuf:"bytes,2,opt,name=schedule,proto3" json:"schedule,omitempty"` // cron-style schedule: minute, hour, day of month, month and day of week
+	NextRun  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	LastRun  *JobRun              `protobuf:"bytes,4,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"` // most recent run, if any
+	Running  bool                 `protobuf:"varint,5,opt,name=running,proto3" json:"running,omitempty"`
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *Job) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Job) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Job) GetNextRun() *timestamp.Timestamp {
+	if x != nil {
+		return x.NextRun
+	}
+	return nil
+}
+
+func (x *Job) GetLastRun() *JobRun {
+	if x != nil {
+		return x.LastRun
+	}
+	return nil
+}
+
+func (x *Job) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+type JobRun struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job       string               `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Started   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=started,proto3" json:"started,omitempty"`
+	Finished  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=finished,proto3" json:"finished,omitempty"`
+	Processed int32                `protobuf:"varint,4,opt,name=processed,proto3" json:"processed,omitempty"`                  // number of items processed, including failures
+	Failed    int32                `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`                        // number of items that could not be processed
+	ErrorCode int32                `protobuf:"varint,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // gRPC status code, if the run failed
+	Error     string               `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                           // error message, if the run failed
+}
+
+func (x *JobRun) Reset() {
+	*x = JobRun{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRun) ProtoMessage() {}
+
+func (x *JobRun) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRun.ProtoReflect.Descriptor instead.
+func (*JobRun) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *JobRun) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *JobRun) GetStarted() *timestamp.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *JobRun) GetFinished() *timestamp.Timestamp {
+	if x != nil {
+		return x.Finished
+	}
+	return nil
+}
+
+func (x *JobRun) GetProcessed() int32 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *JobRun) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *JobRun) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *JobRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{48}
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs []*Job `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListJobsResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type RunJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RunJobRequest) Reset() {
+	*x = RunJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunJobRequest) ProtoMessage() {}
+
+func (x *RunJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunJobRequest.ProtoReflect.Descriptor instead.
+func (*RunJobRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RunJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ConceptSearchResponse_Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Term          string `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"` // matched term
+	ConceptId     int64  `protobuf:"varint,2,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
+	PreferredTerm string `protobuf:"bytes,3,opt,name=preferred_term,json=preferredTerm,proto3" json:"preferred_term,omitempty"` // preferred term for the concept, in the language requested
+}
+
+func (x *ConceptSearchResponse_Item) Reset() {
+	*x = ConceptSearchResponse_Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConceptSearchResponse_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConceptSearchResponse_Item) ProtoMessage() {}
+
+func (x *ConceptSearchResponse_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConceptSearchResponse_Item.ProtoReflect.Descriptor instead.
+func (*ConceptSearchResponse_Item) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{32, 0}
+}
+
+func (x *ConceptSearchResponse_Item) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *ConceptSearchResponse_Item) GetConceptId() int64 {
+	if x != nil {
+		return x.ConceptId
+	}
+	return 0
+}
+
+func (x *ConceptSearchResponse_Item) GetPreferredTerm() string {
+	if x != nil {
+		return x.PreferredTerm
+	}
+	return ""
+}
+
+var File_services_proto protoreflect.FileDescriptor
+
+var file_services_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x05, 0x61, 0x70, 0x69, 0x76, 0x31, 0x1a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb2,
+	0x01, 0x0a, 0x11, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70,
+	0x70, 0x69, 0x6e, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x21, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x34, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x22, 0x7d, 0x0a, 0x12, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x08,
+	0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x73, 0x22, 0x63, 0x0a, 0x14, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x55, 0x72, 0x69, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4a, 0x0a,
+	0x13, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x53, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x07, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x9a, 0x01, 0x0a, 0x12, 0x53, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x25, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52,
+	0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x70, 0x70, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61,
+	0x70, 0x70, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x91, 0x01, 0x0a, 0x14, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x22, 0xb8, 0x02, 0x0a, 0x0f, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32,
+	0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x08,
+	0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x72,
+	0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x42, 0x79, 0x12,
+	0x38, 0x0a, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x45, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x90, 0x03, 0x0a,
+	0x11, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12,
+	0x32, 0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3a,
+	0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x22, 0x3d, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48, 0x45,
+	0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x22,
+	0x85, 0x01, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2a, 0x0a, 0x11, 0x64, 0x65, 0x61, 0x64, 0x5f, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x5f,
+	0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x64, 0x65, 0x61, 0x64,
+	0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xd0, 0x03, 0x0a, 0x0f, 0x50, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x64,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x37, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x6e, 0x65,
+	0x78, 0x74, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6e, 0x65,
+	0x78, 0x74, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c,
+	0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x61, 0x64,
+	0x5f, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64,
+	0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x70, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x3a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x70, 0x0a, 0x10, 0x50, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x34,
+	0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e,
+	0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x8c, 0x02, 0x0a,
+	0x16, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x08, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x12, 0x43, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f,
+	0x75, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x73, 0x0a, 0x15, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00,
+	0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0xb9, 0x02, 0x0a, 0x17, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x32, 0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x0a, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x71, 0x75, 0x65,
+	0x75, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x64, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0xc2, 0x02, 0x0a,
+	0x08, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63,
+	0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x30, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x34, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x4a, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07,
+	0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4e,
+	0x54, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x43, 0x4b, 0x4e, 0x4f, 0x57, 0x4c, 0x45, 0x44,
+	0x47, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x04, 0x22, 0x75, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x0a, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2f, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x14, 0x4e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x02, 0x69, 0x64, 0x22, 0xcd, 0x01, 0x0a, 0x20, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x6d, 0x6f, 0x67, 0x72, 0x61, 0x70, 0x68,
+	0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x2c, 0x0a,
+	0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x74,
+	0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e,
+	0x65, 0x52, 0x0a, 0x74, 0x65, 0x6c, 0x65, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x73, 0x22, 0xcc, 0x01, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1d, 0x0a,
+	0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x22, 0xe8, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x69,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3f,
+	0x0a, 0x0d, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x22,
+	0x2e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x4c, 0x49, 0x56, 0x45, 0x10,
+	0x01, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x43, 0x45, 0x41, 0x53, 0x45, 0x44, 0x10, 0x02, 0x22,
+	0xa6, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x12,
+	0x31, 0x0a, 0x0a, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x64,
+	0x65, 0x64, 0x12, 0x2b, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12,
+	0x37, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08,
+	0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x05,
+	0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x52,
+	0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x22, 0xc7, 0x01, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6c, 0x61, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x0a, 0x62, 0x69, 0x72, 0x74,
+	0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x65, 0x52, 0x09, 0x62, 0x69, 0x72, 0x74, 0x68,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x22, 0x92, 0x01,
+	0x0a, 0x15, 0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x6e, 0x69,
+	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x6e,
+	0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0xaf, 0x01, 0x0a, 0x0e, 0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x36, 0x0a, 0x0c, 0x61, 0x70, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0c,
+	0x61, 0x70, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f,
+	0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xda, 0x02, 0x0a, 0x0d, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63,
+	0x6b, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x16,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x5f, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x76, 0x65, 0x72, 0x45, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x12, 0x45, 0x0a, 0x10, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x46, 0x0a, 0x11, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65,
+	0x74, 0x22, 0x8c, 0x01, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x22, 0xe7, 0x01, 0x0a, 0x19, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65,
+	0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xc2, 0x01, 0x0a, 0x14, 0x43,
+	0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x66, 0x73, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x66, 0x73, 0x65, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6d,
+	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x68, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x12, 0x29,
+	0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x69, 0x6e, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x49, 0x6e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x75, 0x7a,
+	0x7a, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x75, 0x7a, 0x7a, 0x79, 0x22,
+	0xb2, 0x01, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x1a, 0x60, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x49, 0x64, 0x12, 0x25, 0x0a,
+	0x0e, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64,
+	0x54, 0x65, 0x72, 0x6d, 0x22, 0x5f, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x0b, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0xa2, 0x03, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x2b, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x64, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x29,
+	0x0a, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x22, 0x5f, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x18,
+	0x0a, 0x14, 0x44, 0x45, 0x4d, 0x4f, 0x47, 0x52, 0x41, 0x50, 0x48, 0x49, 0x43, 0x53, 0x5f, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x43, 0x45,
+	0x41, 0x53, 0x45, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x44, 0x4f, 0x43, 0x55, 0x4d, 0x45,
+	0x4e, 0x54, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0a,
+	0x0a, 0x06, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x44, 0x10, 0x04, 0x22, 0xdf, 0x01, 0x0a, 0x12, 0x42,
+	0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x32, 0x0a, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x31, 0x0a, 0x04, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x00,
+	0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x4f, 0x46, 0x46, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x02, 0x22, 0x18, 0x0a, 0x16,
+	0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x50, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b,
+	0x65, 0x6e, 0x64, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x08,
+	0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x22, 0x19, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x8c, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x73, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x1a, 0x3b, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x92, 0x01, 0x0a, 0x15, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x5f, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x22,
+	0x14, 0x0a, 0x12, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2b, 0x0a, 0x13, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x22, 0x71, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x05,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x2e, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x1a, 0x0a, 0x05, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x4f, 0x46, 0x46, 0x10, 0x01, 0x22, 0x65, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x2e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x22, 0xb0, 0x01, 0x0a,
+	0x03, 0x4a, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x72, 0x75, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x07, 0x6e, 0x65, 0x78, 0x74, 0x52, 0x75, 0x6e, 0x12, 0x28, 0x0a, 0x08, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x75, 0x6e, 0x52, 0x07, 0x6c, 0x61,
+	0x73, 0x74, 0x52, 0x75, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x22,
+	0xf3, 0x01, 0x0a, 0x06, 0x4a, 0x6f, 0x62, 0x52, 0x75, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f,
+	0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x34, 0x0a, 0x07,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x12, 0x36, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x11, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x04,
+	0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0x23, 0x0a, 0x0d,
+	0x52, 0x75, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x32, 0xa9, 0x07, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x48, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x13, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x22,
+	0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x3a, 0x01, 0x2a, 0x12, 0x50, 0x0a,
+	0x07, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12,
+	0x4c, 0x0a, 0x06, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x22, 0x0a,
+	0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x3a, 0x01, 0x2a, 0x12, 0x4d, 0x0a,
+	0x08, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x76,
+	0x31, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x56, 0x0a, 0x0a,
+	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e,
+	0x74, 0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x13, 0x22, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x72, 0x6f, 0x6c, 0x65,
+	0x73, 0x3a, 0x01, 0x2a, 0x12, 0x5d, 0x0a, 0x0a, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x6f,
+	0x6c, 0x65, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a, 0x22, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x75, 0x74, 0x68, 0x2f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x3a, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x3a, 0x01, 0x2a, 0x12, 0x58, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x15, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f,
+	0x61, 0x75, 0x74, 0x68, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x6f, 0x0a,
+	0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75,
+	0x74, 0x68, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x67,
+	0x0a, 0x15, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x15, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x75, 0x74, 0x68, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x3a, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x74, 0x0a, 0x1a, 0x52, 0x6f, 0x74, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x6f,
+	0x74, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d,
+	0x22, 0x18, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x73, 0x3a, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x3a, 0x01, 0x2a, 0x32, 0x8a, 0x03,
+	0x0a, 0x0b, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x58, 0x0a,
+	0x0d, 0x47, 0x65, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x1a, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x12,
+	0x16, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2f,
+	0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x12, 0x52, 0x0a, 0x0d, 0x4d, 0x61, 0x70, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x0f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x09,
+	0x12, 0x07, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x70, 0x30, 0x01, 0x12, 0x65, 0x0a, 0x0b, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x19, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x69,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x66, 0x0a, 0x12, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x1b, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a,
+	0x12, 0x18, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x73, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x32, 0xbb, 0x02, 0x0a, 0x0f, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x57,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x1a, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x6d,
+	0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x63, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69,
+	0x6e, 0x67, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x1d, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x17, 0x22, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2f, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x6a, 0x0a, 0x0d,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x73, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f, 0x76, 0x31, 0x2f,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x3a, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x3a, 0x01, 0x2a, 0x32, 0xd3, 0x05, 0x0a, 0x0f, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x82, 0x01, 0x0a,
+	0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x30, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2a, 0x22, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x3a, 0x12, 0x64,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x57, 0x0a, 0x10, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x0e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x5c, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x1a, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x1d, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x2f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x71, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22,
+	0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x12, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x60, 0x0a,
+	0x14, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x76, 0x31, 0x2f,
+	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x5d, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x12, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x32, 0x68,
+	0x0a, 0x12, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x52, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x17, 0x12, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x32, 0x6f, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x58, 0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f,
+	0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x3a, 0x01, 0x2a, 0x32, 0xa2, 0x04, 0x0a, 0x10, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x44,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x11, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a,
+	0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22,
+	0x13, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x12, 0x5a, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x31, 0x2f,
+	0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x30, 0x01,
+	0x12, 0x54, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6e, 0x6b, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x22, 0x19, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x2f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x57, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x31,
+	0x2f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x79, 0x0a, 0x19, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x44, 0x65, 0x6d, 0x6f, 0x67, 0x72, 0x61, 0x70, 0x68, 0x69, 0x63, 0x73, 0x12, 0x27, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x44, 0x65, 0x6d, 0x6f, 0x67, 0x72, 0x61, 0x70, 0x68, 0x69, 0x63, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d, 0x22, 0x18, 0x2f,
+	0x76, 0x31, 0x2f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x64, 0x65, 0x6d, 0x6f, 0x67,
+	0x72, 0x61, 0x70, 0x68, 0x69, 0x63, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x42, 0x0a, 0x10, 0x50, 0x72,
+	0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x1a, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x28, 0x01, 0x30, 0x01, 0x32, 0x94,
+	0x02, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x65, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x6c,
+	0x69, 0x6e, 0x69, 0x63, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x6c, 0x69, 0x6e,
+	0x69, 0x63, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x15, 0x12, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x2f, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x41, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6c,
+	0x69, 0x6e, 0x69, 0x63, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x43, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a,
+	0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6c, 0x69, 0x6e, 0x69, 0x63, 0x12, 0x59, 0x0a, 0x13, 0x47, 0x65,
+	0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15,
+	0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x32, 0x91, 0x03, 0x0a, 0x15, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x6e, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x22, 0x1f, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x30, 0x01, 0x12,
+	0x3e, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x65, 0x72, 0x50, 0x68, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12,
+	0x5c, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x1f, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x6a, 0x0a,
+	0x0e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12,
+	0x1c, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f, 0x76, 0x31,
+	0x2f, 0x70, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x2f, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x3a, 0x01, 0x2a, 0x32, 0x7a, 0x0a, 0x0b, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x6b, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x12, 0x16, 0x2f,
+	0x76, 0x31, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x2f, 0x73,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x32, 0x69, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x58, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x12, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x3a, 0x01, 0x2a, 0x30, 0x01,
+	0x32, 0xdc, 0x01, 0x0a, 0x0b, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x62, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x1a, 0x19, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14,
+	0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x3a, 0x01, 0x2a, 0x12, 0x69, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12, 0x0f,
+	0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x32,
+	0xb9, 0x04, 0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x69, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31,
+	0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x67, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x12, 0x67, 0x0a,
+	0x0b, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x22, 0x16, 0x2f, 0x76, 0x31,
+	0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x2f, 0x72, 0x65, 0x6c,
+	0x6f, 0x61, 0x64, 0x3a, 0x01, 0x2a, 0x12, 0x50, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f,
+	0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18,
+	0x22, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x6c, 0x6f, 0x67, 0x2d,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x3a, 0x01, 0x2a, 0x12, 0x53, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x62, 0x73, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f,
+	0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x6a, 0x6f, 0x62, 0x73, 0x12, 0x4c, 0x0a,
+	0x06, 0x52, 0x75, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x52, 0x75, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x75, 0x6e, 0x22, 0x1d, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x17, 0x22, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f,
+	0x6a, 0x6f, 0x62, 0x73, 0x3a, 0x72, 0x75, 0x6e, 0x3a, 0x01, 0x2a, 0x42, 0x3d, 0x0a, 0x18, 0x63,
+	0x6f, 0x6d, 0x2e, 0x65, 0x6c, 0x64, 0x72, 0x69, 0x78, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65,
+	0x72, 0x67, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c, 0x65, 0x2f, 0x63, 0x6f, 0x6e, 0x63, 0x69,
+	0x65, 0x72, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_services_proto_rawDescOnce sync.Once
+	file_services_proto_rawDescData = file_services_proto_rawDesc
+)
+
+func file_services_proto_rawDescGZIP() []byte {
+	file_services_proto_rawDescOnce.Do(func() {
+		file_services_proto_rawDescData = protoimpl.X.CompressGZIP(file_services_proto_rawDescData)
+	})
+	return file_services_proto_rawDescData
+}
+
+var file_services_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
+var file_services_proto_goTypes = []interface{}{
+	(PublicationStatus_Status)(0),            // 0: apiv1.PublicationStatus.Status
+	(Delivery_Status)(0),                     // 1: apiv1.Delivery.Status
+	(PatientStatus_Status)(0),                // 2: apiv1.PatientStatus.Status
+	(ChangeEvent_Type)(0),                    // 3: apiv1.ChangeEvent.Type
+	(BackendMaintenance_Mode)(0),             // 4: apiv1.BackendMaintenance.Mode
+	(LogLevel_Level)(0),                      // 5: apiv1.LogLevel.Level
+	(*IdentifierMapping)(nil),                // 6: apiv1.IdentifierMapping
+	(*IdentifierMappings)(nil),               // 7: apiv1.IdentifierMappings
+	(*IdentifierMapRequest)(nil),             // 8: apiv1.IdentifierMapRequest
+	(*ListSystemsRequest)(nil),               // 9: apiv1.ListSystemsRequest
+	(*ListSystemsResponse)(nil),              // 10: apiv1.ListSystemsResponse
+	(*SystemCapabilities)(nil),               // 11: apiv1.SystemCapabilities
+	(*IdentifierValidation)(nil),             // 12: apiv1.IdentifierValidation
+	(*DocumentVersion)(nil),                  // 13: apiv1.DocumentVersion
+	(*DocumentHistory)(nil),                  // 14: apiv1.DocumentHistory
+	(*PublicationStatus)(nil),                // 15: apiv1.PublicationStatus
+	(*ListPendingDocumentsRequest)(nil),      // 16: apiv1.ListPendingDocumentsRequest
+	(*PendingDocument)(nil),                  // 17: apiv1.PendingDocument
+	(*PendingDocuments)(nil),                 // 18: apiv1.PendingDocuments
+	(*PublishDocumentRequest)(nil),           // 19: apiv1.PublishDocumentRequest
+	(*UploadDocumentRequest)(nil),            // 20: apiv1.UploadDocumentRequest
+	(*PublishDocumentResponse)(nil),          // 21: apiv1.PublishDocumentResponse
+	(*Delivery)(nil),                         // 22: apiv1.Delivery
+	(*DeliveryStatus)(nil),                   // 23: apiv1.DeliveryStatus
+	(*NotificationRequest)(nil),              // 24: apiv1.NotificationRequest
+	(*NotificationResponse)(nil),             // 25: apiv1.NotificationResponse
+	(*UpdatePatientDemographicsRequest)(nil), // 26: apiv1.UpdatePatientDemographicsRequest
+	(*PrefetchProgress)(nil),                 // 27: apiv1.PrefetchProgress
+	(*PatientStatus)(nil),                    // 28: apiv1.PatientStatus
+	(*PatientLink)(nil),                      // 29: apiv1.PatientLink
+	(*PatientLinks)(nil),                     // 30: apiv1.PatientLinks
+	(*PatientSearchRequest)(nil),             // 31: apiv1.PatientSearchRequest
+	(*ClinicScheduleRequest)(nil),            // 32: apiv1.ClinicScheduleRequest
+	(*ClinicSchedule)(nil),                   // 33: apiv1.ClinicSchedule
+	(*AccountStatus)(nil),                    // 34: apiv1.AccountStatus
+	(*ChangePasswordRequest)(nil),            // 35: apiv1.ChangePasswordRequest
+	(*PractitionerSearchRequest)(nil),        // 36: apiv1.PractitionerSearchRequest
+	(*ConceptSearchRequest)(nil),             // 37: apiv1.ConceptSearchRequest
+	(*ConceptSearchResponse)(nil),            // 38: apiv1.ConceptSearchResponse
+	(*SubscribeRequest)(nil),                 // 39: apiv1.SubscribeRequest
+	(*ChangeEvent)(nil),                      // 40: apiv1.ChangeEvent
+	(*BackendMaintenance)(nil),               // 41: apiv1.BackendMaintenance
+	(*ListMaintenanceRequest)(nil),           // 42: apiv1.ListMaintenanceRequest
+	(*ListMaintenanceResponse)(nil),          // 43: apiv1.ListMaintenanceResponse
+	(*GetConfigurationRequest)(nil),          // 44: apiv1.GetConfigurationRequest
+	(*Configuration)(nil),                    // 45: apiv1.Configuration
+	(*ListProvidersRequest)(nil),             // 46: apiv1.ListProvidersRequest
+	(*ListProvidersResponse)(nil),            // 47: apiv1.ListProvidersResponse
+	(*ReloadRulesRequest)(nil),               // 48: apiv1.ReloadRulesRequest
+	(*ReloadRulesResponse)(nil),              // 49: apiv1.ReloadRulesResponse
+	(*LogLevel)(nil),                         // 50: apiv1.LogLevel
+	(*LogLevels)(nil),                        // 51: apiv1.LogLevels
+	(*Job)(nil),                              // 52: apiv1.Job
+	(*JobRun)(nil),                           // 53: apiv1.JobRun
+	(*ListJobsRequest)(nil),                  // 54: apiv1.ListJobsRequest
+	(*ListJobsResponse)(nil),                 // 55: apiv1.ListJobsResponse
+	(*RunJobRequest)(nil),                    // 56: apiv1.RunJobRequest
+	(*ConceptSearchResponse_Item)(nil),       // 57: apiv1.ConceptSearchResponse.Item
+	nil,                                      // 58: apiv1.Configuration.SettingsEntry
+	(*Identifier)(nil),                       // 59: apiv1.Identifier
+	(*timestamp.Timestamp)(nil),              // 60: google.protobuf.Timestamp
+	(*System)(nil),                           // 61: apiv1.System
+	(*Document)(nil),                         // 62: apiv1.Document
+	(*Patient)(nil),                          // 63: apiv1.Patient
+	(*Address)(nil),                          // 64: apiv1.Address
+	(*Telephone)(nil),                        // 65: apiv1.Telephone
+	(*Date)(nil),                             // 66: apiv1.Date
+	(Gender)(0),                              // 67: apiv1.Gender
+	(*Appointment)(nil),                      // 68: apiv1.Appointment
+	(*LoginRequest)(nil),                     // 69: apiv1.LoginRequest
+	(*TokenRefreshRequest)(nil),              // 70: apiv1.TokenRefreshRequest
+	(*LogoutRequest)(nil),                    // 71: apiv1.LogoutRequest
+	(*RoleAssignment)(nil),                   // 72: apiv1.RoleAssignment
+	(*CreateServiceAccountRequest)(nil),      // 73: apiv1.CreateServiceAccountRequest
+	(*RotateSecretRequest)(nil),              // 74: apiv1.RotateSecretRequest
+	(*LoginResponse)(nil),                    // 75: apiv1.LoginResponse
+	(*LogoutResponse)(nil),                   // 76: apiv1.LogoutResponse
+	(*RoleAssignments)(nil),                  // 77: apiv1.RoleAssignments
+	(*ServiceAccount)(nil),                   // 78: apiv1.ServiceAccount
+	(*any.Any)(nil),                          // 79: google.protobuf.Any
+	(*Attachment)(nil),                       // 80: apiv1.Attachment
+	(*Clinic)(nil),                           // 81: apiv1.Clinic
+	(*Admission)(nil),                        // 82: apiv1.Admission
+	(*Practitioner)(nil),                     // 83: apiv1.Practitioner
+}
+var file_services_proto_depIdxs = []int32{
+	59,  // 0: apiv1.IdentifierMapping.from:type_name -> apiv1.Identifier
+	59,  // 1: apiv1.IdentifierMapping.to:type_name -> apiv1.Identifier
+	60,  // 2: apiv1.IdentifierMapping.created:type_name -> google.protobuf.Timestamp
+	59,  // 3: apiv1.IdentifierMappings.identifier:type_name -> apiv1.Identifier
+	6,   // 4: apiv1.IdentifierMappings.mappings:type_name -> apiv1.IdentifierMapping
+	11,  // 5: apiv1.ListSystemsResponse.systems:type_name -> apiv1.SystemCapabilities
+	61,  // 6: apiv1.SystemCapabilities.system:type_name -> apiv1.System
+	59,  // 7: apiv1.IdentifierValidation.identifier:type_name -> apiv1.Identifier
+	59,  // 8: apiv1.DocumentVersion.document_id:type_name -> apiv1.Identifier
+	59,  // 9: apiv1.DocumentVersion.replaces:type_name -> apiv1.Identifier
+	59,  // 10: apiv1.DocumentVersion.replaced_by:type_name -> apiv1.Identifier
+	60,  // 11: apiv1.DocumentVersion.published:type_name -> google.protobuf.Timestamp
+	21,  // 12: apiv1.DocumentVersion.response:type_name -> apiv1.PublishDocumentResponse
+	13,  // 13: apiv1.DocumentHistory.versions:type_name -> apiv1.DocumentVersion
+	59,  // 14: apiv1.PublicationStatus.receipt:type_name -> apiv1.Identifier
+	59,  // 15: apiv1.PublicationStatus.document_id:type_name -> apiv1.Identifier
+	0,   // 16: apiv1.PublicationStatus.status:type_name -> apiv1.PublicationStatus.Status
+	21,  // 17: apiv1.PublicationStatus.response:type_name -> apiv1.PublishDocumentResponse
+	60,  // 18: apiv1.PublicationStatus.updated:type_name -> google.protobuf.Timestamp
+	59,  // 19: apiv1.PendingDocument.document_id:type_name -> apiv1.Identifier
+	19,  // 20: apiv1.PendingDocument.request:type_name -> apiv1.PublishDocumentRequest
+	60,  // 21: apiv1.PendingDocument.created:type_name -> google.protobuf.Timestamp
+	60,  // 22: apiv1.PendingDocument.next_attempt:type_name -> google.protobuf.Timestamp
+	59,  // 23: apiv1.PendingDocument.receipt:type_name -> apiv1.Identifier
+	21,  // 24: apiv1.PendingDocument.response:type_name -> apiv1.PublishDocumentResponse
+	17,  // 25: apiv1.PendingDocuments.documents:type_name -> apiv1.PendingDocument
+	62,  // 26: apiv1.PublishDocumentRequest.document:type_name -> apiv1.Document
+	59,  // 27: apiv1.PublishDocumentRequest.replaces:type_name -> apiv1.Identifier
+	13,  // 28: apiv1.PublishDocumentRequest.previous_versions:type_name -> apiv1.DocumentVersion
+	19,  // 29: apiv1.UploadDocumentRequest.request:type_name -> apiv1.PublishDocumentRequest
+	59,  // 30: apiv1.PublishDocumentResponse.id:type_name -> apiv1.Identifier
+	59,  // 31: apiv1.PublishDocumentResponse.document_id:type_name -> apiv1.Identifier
+	22,  // 32: apiv1.PublishDocumentResponse.deliveries:type_name -> apiv1.Delivery
+	59,  // 33: apiv1.PublishDocumentResponse.receipt:type_name -> apiv1.Identifier
+	59,  // 34: apiv1.Delivery.message_id:type_name -> apiv1.Identifier
+	1,   // 35: apiv1.Delivery.status:type_name -> apiv1.Delivery.Status
+	60,  // 36: apiv1.Delivery.updated:type_name -> google.protobuf.Timestamp
+	59,  // 37: apiv1.DeliveryStatus.document_id:type_name -> apiv1.Identifier
+	22,  // 38: apiv1.DeliveryStatus.deliveries:type_name -> apiv1.Delivery
+	59,  // 39: apiv1.NotificationRequest.recipient:type_name -> apiv1.Identifier
+	63,  // 40: apiv1.NotificationRequest.patient:type_name -> apiv1.Patient
+	59,  // 41: apiv1.NotificationResponse.id:type_name -> apiv1.Identifier
+	59,  // 42: apiv1.UpdatePatientDemographicsRequest.identifier:type_name -> apiv1.Identifier
+	64,  // 43: apiv1.UpdatePatientDemographicsRequest.addresses:type_name -> apiv1.Address
+	65,  // 44: apiv1.UpdatePatientDemographicsRequest.telephones:type_name -> apiv1.Telephone
+	59,  // 45: apiv1.PrefetchProgress.identifier:type_name -> apiv1.Identifier
+	59,  // 46: apiv1.PatientStatus.identifier:type_name -> apiv1.Identifier
+	2,   // 47: apiv1.PatientStatus.status:type_name -> apiv1.PatientStatus.Status
+	60,  // 48: apiv1.PatientStatus.deceased_date:type_name -> google.protobuf.Timestamp
+	59,  // 49: apiv1.PatientLink.superseded:type_name -> apiv1.Identifier
+	59,  // 50: apiv1.PatientLink.current:type_name -> apiv1.Identifier
+	60,  // 51: apiv1.PatientLink.date_time:type_name -> google.protobuf.Timestamp
+	59,  // 52: apiv1.PatientLinks.identifier:type_name -> apiv1.Identifier
+	29,  // 53: apiv1.PatientLinks.links:type_name -> apiv1.PatientLink
+	59,  // 54: apiv1.PatientLinks.current:type_name -> apiv1.Identifier
+	66,  // 55: apiv1.PatientSearchRequest.birth_date:type_name -> apiv1.Date
+	67,  // 56: apiv1.PatientSearchRequest.gender:type_name -> apiv1.Gender
+	59,  // 57: apiv1.ClinicScheduleRequest.clinic:type_name -> apiv1.Identifier
+	59,  // 58: apiv1.ClinicSchedule.clinic:type_name -> apiv1.Identifier
+	68,  // 59: apiv1.ClinicSchedule.appointments:type_name -> apiv1.Appointment
+	59,  // 60: apiv1.AccountStatus.user:type_name -> apiv1.Identifier
+	60,  // 61: apiv1.AccountStatus.password_expires:type_name -> google.protobuf.Timestamp
+	60,  // 62: apiv1.AccountStatus.password_last_set:type_name -> google.protobuf.Timestamp
+	59,  // 63: apiv1.ChangePasswordRequest.user:type_name -> apiv1.Identifier
+	57,  // 64: apiv1.ConceptSearchResponse.items:type_name -> apiv1.ConceptSearchResponse.Item
+	59,  // 65: apiv1.SubscribeRequest.identifiers:type_name -> apiv1.Identifier
+	3,   // 66: apiv1.ChangeEvent.type:type_name -> apiv1.ChangeEvent.Type
+	60,  // 67: apiv1.ChangeEvent.date_time:type_name -> google.protobuf.Timestamp
+	59,  // 68: apiv1.ChangeEvent.subject:type_name -> apiv1.Identifier
+	63,  // 69: apiv1.ChangeEvent.patient:type_name -> apiv1.Patient
+	59,  // 70: apiv1.ChangeEvent.document_id:type_name -> apiv1.Identifier
+	59,  // 71: apiv1.ChangeEvent.merged:type_name -> apiv1.Identifier
+	4,   // 72: apiv1.BackendMaintenance.mode:type_name -> apiv1.BackendMaintenance.Mode
+	60,  // 73: apiv1.BackendMaintenance.since:type_name -> google.protobuf.Timestamp
+	41,  // 74: apiv1.ListMaintenanceResponse.backends:type_name -> apiv1.BackendMaintenance
+	58,  // 75: apiv1.Configuration.settings:type_name -> apiv1.Configuration.SettingsEntry
+	5,   // 76: apiv1.LogLevel.level:type_name -> apiv1.LogLevel.Level
+	5,   // 77: apiv1.LogLevels.default:type_name -> apiv1.LogLevel.Level
+	50,  // 78: apiv1.LogLevels.levels:type_name -> apiv1.LogLevel
+	60,  // 79: apiv1.Job.next_run:type_name -> google.protobuf.Timestamp
+	53,  // 80: apiv1.Job.last_run:type_name -> apiv1.JobRun
+	60,  // 81: apiv1.JobRun.started:type_name -> google.protobuf.Timestamp
+	60,  // 82: apiv1.JobRun.finished:type_name -> google.protobuf.Timestamp
+	52,  // 83: apiv1.ListJobsResponse.jobs:type_name -> apiv1.Job
+	69,  // 84: apiv1.Authenticator.Login:input_type -> apiv1.LoginRequest
+	70,  // 85: apiv1.Authenticator.Refresh:input_type -> apiv1.TokenRefreshRequest
+	71,  // 86: apiv1.Authenticator.Logout:input_type -> apiv1.LogoutRequest
+	59,  // 87: apiv1.Authenticator.GetRoles:input_type -> apiv1.Identifier
+	72,  // 88: apiv1.Authenticator.AssignRole:input_type -> apiv1.RoleAssignment
+	72,  // 89: apiv1.Authenticator.RevokeRole:input_type -> apiv1.RoleAssignment
+	59,  // 90: apiv1.Authenticator.GetServiceAccount:input_type -> apiv1.Identifier
+	73,  // 91: apiv1.Authenticator.CreateServiceAccount:input_type -> apiv1.CreateServiceAccountRequest
+	59,  // 92: apiv1.Authenticator.DisableServiceAccount:input_type -> apiv1.Identifier
+	74,  // 93: apiv1.Authenticator.RotateServiceAccountSecret:input_type -> apiv1.RotateSecretRequest
+	59,  // 94: apiv1.Identifiers.GetIdentifier:input_type -> apiv1.Identifier
+	8,   // 95: apiv1.Identifiers.MapIdentifier:input_type -> apiv1.IdentifierMapRequest
+	9,   // 96: apiv1.Identifiers.ListSystems:input_type -> apiv1.ListSystemsRequest
+	59,  // 97: apiv1.Identifiers.ValidateIdentifier:input_type -> apiv1.Identifier
+	59,  // 98: apiv1.IdentifierAdmin.GetMappings:input_type -> apiv1.Identifier
+	6,   // 99: apiv1.IdentifierAdmin.CreateMapping:input_type -> apiv1.IdentifierMapping
+	6,   // 100: apiv1.IdentifierAdmin.DeleteMapping:input_type -> apiv1.IdentifierMapping
+	19,  // 101: apiv1.DocumentService.PublishDocument:input_type -> apiv1.PublishDocumentRequest
+	19,  // 102: apiv1.DocumentService.PublishDocuments:input_type -> apiv1.PublishDocumentRequest
+	20,  // 103: apiv1.DocumentService.UploadDocument:input_type -> apiv1.UploadDocumentRequest
+	59,  // 104: apiv1.DocumentService.GetDeliveryStatus:input_type -> apiv1.Identifier
+	16,  // 105: apiv1.DocumentService.ListPendingDocuments:input_type -> apiv1.ListPendingDocumentsRequest
+	59,  // 106: apiv1.DocumentService.GetPublicationStatus:input_type -> apiv1.Identifier
+	59,  // 107: apiv1.DocumentService.GetDocumentHistory:input_type -> apiv1.Identifier
+	59,  // 108: apiv1.DocumentRepository.GetDocument:input_type -> apiv1.Identifier
+	24,  // 109: apiv1.NotificationService.Notify:input_type -> apiv1.NotificationRequest
+	59,  // 110: apiv1.PatientDirectory.GetPatient:input_type -> apiv1.Identifier
+	31,  // 111: apiv1.PatientDirectory.SearchPatient:input_type -> apiv1.PatientSearchRequest
+	59,  // 112: apiv1.PatientDirectory.GetPatientLinks:input_type -> apiv1.Identifier
+	59,  // 113: apiv1.PatientDirectory.GetPatientStatus:input_type -> apiv1.Identifier
+	26,  // 114: apiv1.PatientDirectory.UpdatePatientDemographics:input_type -> apiv1.UpdatePatientDemographicsRequest
+	59,  // 115: apiv1.PatientDirectory.PrefetchPatients:input_type -> apiv1.Identifier
+	32,  // 116: apiv1.ClinicService.GetClinicSchedule:input_type -> apiv1.ClinicScheduleRequest
+	59,  // 117: apiv1.ClinicService.GetClinic:input_type -> apiv1.Identifier
+	59,  // 118: apiv1.ClinicService.GetCurrentAdmission:input_type -> apiv1.Identifier
+	36,  // 119: apiv1.PractitionerDirectory.SearchPractitioner:input_type -> apiv1.PractitionerSearchRequest
+	59,  // 120: apiv1.PractitionerDirectory.GetPractitionerPhoto:input_type -> apiv1.Identifier
+	59,  // 121: apiv1.PractitionerDirectory.GetAccountStatus:input_type -> apiv1.Identifier
+	35,  // 122: apiv1.PractitionerDirectory.ChangePassword:input_type -> apiv1.ChangePasswordRequest
+	37,  // 123: apiv1.Terminology.SearchConcepts:input_type -> apiv1.ConceptSearchRequest
+	39,  // 124: apiv1.Subscriptions.Subscribe:input_type -> apiv1.SubscribeRequest
+	41,  // 125: apiv1.Maintenance.SetMaintenance:input_type -> apiv1.BackendMaintenance
+	42,  // 126: apiv1.Maintenance.ListMaintenance:input_type -> apiv1.ListMaintenanceRequest
+	44,  // 127: apiv1.Admin.GetConfiguration:input_type -> apiv1.GetConfigurationRequest
+	46,  // 128: apiv1.Admin.ListProviders:input_type -> apiv1.ListProvidersRequest
+	48,  // 129: apiv1.Admin.ReloadRules:input_type -> apiv1.ReloadRulesRequest
+	50,  // 130: apiv1.Admin.SetLogLevel:input_type -> apiv1.LogLevel
+	54,  // 131: apiv1.Admin.ListJobs:input_type -> apiv1.ListJobsRequest
+	56,  // 132: apiv1.Admin.RunJob:input_type -> apiv1.RunJobRequest
+	75,  // 133: apiv1.Authenticator.Login:output_type -> apiv1.LoginResponse
+	75,  // 134: apiv1.Authenticator.Refresh:output_type -> apiv1.LoginResponse
+	76,  // 135: apiv1.Authenticator.Logout:output_type -> apiv1.LogoutResponse
+	77,  // 136: apiv1.Authenticator.GetRoles:output_type -> apiv1.RoleAssignments
+	77,  // 137: apiv1.Authenticator.AssignRole:output_type -> apiv1.RoleAssignments
+	77,  // 138: apiv1.Authenticator.RevokeRole:output_type -> apiv1.RoleAssignments
+	78,  // 139: apiv1.Authenticator.GetServiceAccount:output_type -> apiv1.ServiceAccount
+	78,  // 140: apiv1.Authenticator.CreateServiceAccount:output_type -> apiv1.ServiceAccount
+	78,  // 141: apiv1.Authenticator.DisableServiceAccount:output_type -> apiv1.ServiceAccount
+	78,  // 142: apiv1.Authenticator.RotateServiceAccountSecret:output_type -> apiv1.ServiceAccount
+	79,  // 143: apiv1.Identifiers.GetIdentifier:output_type -> google.protobuf.Any
+	59,  // 144: apiv1.Identifiers.MapIdentifier:output_type -> apiv1.Identifier
+	10,  // 145: apiv1.Identifiers.ListSystems:output_type -> apiv1.ListSystemsResponse
+	12,  // 146: apiv1.Identifiers.ValidateIdentifier:output_type -> apiv1.IdentifierValidation
+	7,   // 147: apiv1.IdentifierAdmin.GetMappings:output_type -> apiv1.IdentifierMappings
+	7,   // 148: apiv1.IdentifierAdmin.CreateMapping:output_type -> apiv1.IdentifierMappings
+	7,   // 149: apiv1.IdentifierAdmin.DeleteMapping:output_type -> apiv1.IdentifierMappings
+	21,  // 150: apiv1.DocumentService.PublishDocument:output_type -> apiv1.PublishDocumentResponse
+	21,  // 151: apiv1.DocumentService.PublishDocuments:output_type -> apiv1.PublishDocumentResponse
+	21,  // 152: apiv1.DocumentService.UploadDocument:output_type -> apiv1.PublishDocumentResponse
+	23,  // 153: apiv1.DocumentService.GetDeliveryStatus:output_type -> apiv1.DeliveryStatus
+	18,  // 154: apiv1.DocumentService.ListPendingDocuments:output_type -> apiv1.PendingDocuments
+	15,  // 155: apiv1.DocumentService.GetPublicationStatus:output_type -> apiv1.PublicationStatus
+	14,  // 156: apiv1.DocumentService.GetDocumentHistory:output_type -> apiv1.DocumentHistory
+	80,  // 157: apiv1.DocumentRepository.GetDocument:output_type -> apiv1.Attachment
+	25,  // 158: apiv1.NotificationService.Notify:output_type -> apiv1.NotificationResponse
+	63,  // 159: apiv1.PatientDirectory.GetPatient:output_type -> apiv1.Patient
+	63,  // 160: apiv1.PatientDirectory.SearchPatient:output_type -> apiv1.Patient
+	30,  // 161: apiv1.PatientDirectory.GetPatientLinks:output_type -> apiv1.PatientLinks
+	28,  // 162: apiv1.PatientDirectory.GetPatientStatus:output_type -> apiv1.PatientStatus
+	63,  // 163: apiv1.PatientDirectory.UpdatePatientDemographics:output_type -> apiv1.Patient
+	27,  // 164: apiv1.PatientDirectory.PrefetchPatients:output_type -> apiv1.PrefetchProgress
+	33,  // 165: apiv1.ClinicService.GetClinicSchedule:output_type -> apiv1.ClinicSchedule
+	81,  // 166: apiv1.ClinicService.GetClinic:output_type -> apiv1.Clinic
+	82,  // 167: apiv1.ClinicService.GetCurrentAdmission:output_type -> apiv1.Admission
+	83,  // 168: apiv1.PractitionerDirectory.SearchPractitioner:output_type -> apiv1.Practitioner
+	80,  // 169: apiv1.PractitionerDirectory.GetPractitionerPhoto:output_type -> apiv1.Attachment
+	34,  // 170: apiv1.PractitionerDirectory.GetAccountStatus:output_type -> apiv1.AccountStatus
+	34,  // 171: apiv1.PractitionerDirectory.ChangePassword:output_type -> apiv1.AccountStatus
+	38,  // 172: apiv1.Terminology.SearchConcepts:output_type -> apiv1.ConceptSearchResponse
+	40,  // 173: apiv1.Subscriptions.Subscribe:output_type -> apiv1.ChangeEvent
+	41,  // 174: apiv1.Maintenance.SetMaintenance:output_type -> apiv1.BackendMaintenance
+	43,  // 175: apiv1.Maintenance.ListMaintenance:output_type -> apiv1.ListMaintenanceResponse
+	45,  // 176: apiv1.Admin.GetConfiguration:output_type -> apiv1.Configuration
+	47,  // 177: apiv1.Admin.ListProviders:output_type -> apiv1.ListProvidersResponse
+	49,  // 178: apiv1.Admin.ReloadRules:output_type -> apiv1.ReloadRulesResponse
+	51,  // 179: apiv1.Admin.SetLogLevel:output_type -> apiv1.LogLevels
+	55,  // 180: apiv1.Admin.ListJobs:output_type -> apiv1.ListJobsResponse
+	53,  // 181: apiv1.Admin.RunJob:output_type -> apiv1.JobRun
+	133, // [133:182] is the sub-list for method output_type
+	84,  // [84:133] is the sub-list for method input_type
+	84,  // [84:84] is the sub-list for extension type_name
+	84,  // [84:84] is the sub-list for extension extendee
+	0,   // [0:84] is the sub-list for field type_name
+}
+
+func init() { file_services_proto_init() }
+func file_services_proto_init() {
+	if File_services_proto != nil {
+		return
+	}
+	file_model_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_services_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentifierMapping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentifierMappings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentifierMapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSystemsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSystemsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SystemCapabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentifierValidation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublicationStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPendingDocumentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingDocument); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingDocuments); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishDocumentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadDocumentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishDocumentResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Delivery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeliveryStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePatientDemographicsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefetchProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientLink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientLinks); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientSearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClinicScheduleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClinicSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AccountStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangePasswordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PractitionerSearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConceptSearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConceptSearchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangeEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackendMaintenance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListMaintenanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListMaintenanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigurationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Configuration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProvidersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProvidersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogLevels); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Job); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobRun); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConceptSearchResponse_Item); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_services_proto_msgTypes[14].OneofWrappers = []interface{}{
+		(*UploadDocumentRequest_Request)(nil),
+		(*UploadDocumentRequest_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_services_proto_rawDesc,
+			NumEnums:      6,
+			NumMessages:   53,
+			NumExtensions: 0,
+			NumServices:   13,
+		},
+		GoTypes:           file_services_proto_goTypes,
+		DependencyIndexes: file_services_proto_depIdxs,
+		EnumInfos:         file_services_proto_enumTypes,
+		MessageInfos:      file_services_proto_msgTypes,
+	}.Build()
+	File_services_proto = out.File
+	file_services_proto_rawDesc = nil
+	file_services_proto_goTypes = nil
+	file_services_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AuthenticatorClient is the client API for Authenticator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthenticatorClient interface {
+	// Login authenticates using the credentials specified and returns an authentication token
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// Refresh refreshes a currently valid token
+	Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// Logout revokes the current token, or all tokens issued to the authenticated user
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// GetRoles returns the roles assigned to a service account
+	GetRoles(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*RoleAssignments, error)
+	// AssignRole assigns a role to a service account
+	AssignRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleAssignments, error)
+	// RevokeRole revokes a role from a service account
+	RevokeRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleAssignments, error)
+	// GetServiceAccount returns a service account
+	GetServiceAccount(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// CreateServiceAccount creates a service account, returning its generated password
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// DisableServiceAccount disables a service account, revoking all tokens issued to it
+	DisableServiceAccount(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// RotateServiceAccountSecret replaces the password of a service account, returning the new generated password
+	RotateServiceAccountSecret(ctx context.Context, in *RotateSecretRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+}
+
+type authenticatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthenticatorClient(cc grpc.ClientConnInterface) AuthenticatorClient {
+	return &authenticatorClient{cc}
+}
+
+func (c *authenticatorClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) GetRoles(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*RoleAssignments, error) {
+	out := new(RoleAssignments)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/GetRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) AssignRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleAssignments, error) {
+	out := new(RoleAssignments)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/AssignRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) RevokeRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleAssignments, error) {
+	out := new(RoleAssignments)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/RevokeRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) GetServiceAccount(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/GetServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/CreateServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) DisableServiceAccount(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/DisableServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) RotateServiceAccountSecret(ctx context.Context, in *RotateSecretRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/RotateServiceAccountSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthenticatorServer is the server API for Authenticator service.
+type AuthenticatorServer interface {
+	// Login authenticates using the credentials specified and returns an authentication token
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// Refresh refreshes a currently valid token
+	Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error)
+	// Logout revokes the current token, or all tokens issued to the authenticated user
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// GetRoles returns the roles assigned to a service account
+	GetRoles(context.Context, *Identifier) (*RoleAssignments, error)
+	// AssignRole assigns a role to a service account
+	AssignRole(context.Context, *RoleAssignment) (*RoleAssignments, error)
+	// RevokeRole revokes a role from a service account
+	RevokeRole(context.Context, *RoleAssignment) (*RoleAssignments, error)
+	// GetServiceAccount returns a service account
+	GetServiceAccount(context.Context, *Identifier) (*ServiceAccount, error)
+	// CreateServiceAccount creates a service account, returning its generated password
+	CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*ServiceAccount, error)
+	// DisableServiceAccount disables a service account, revoking all tokens issued to it
+	DisableServiceAccount(context.Context, *Identifier) (*ServiceAccount, error)
+	// RotateServiceAccountSecret replaces the password of a service account, returning the new generated password
+	RotateServiceAccountSecret(context.Context, *RotateSecretRequest) (*ServiceAccount, error)
+}
+
+// UnimplementedAuthenticatorServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthenticatorServer struct {
+}
+
+func (*UnimplementedAuthenticatorServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (*UnimplementedAuthenticatorServer) Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (*UnimplementedAuthenticatorServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedAuthenticatorServer) GetRoles(context.Context, *Identifier) (*RoleAssignments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoles not implemented")
+}
+func (*UnimplementedAuthenticatorServer) AssignRole(context.Context, *RoleAssignment) (*RoleAssignments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (*UnimplementedAuthenticatorServer) RevokeRole(context.Context, *RoleAssignment) (*RoleAssignments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
+func (*UnimplementedAuthenticatorServer) GetServiceAccount(context.Context, *Identifier) (*ServiceAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceAccount not implemented")
+}
+func (*UnimplementedAuthenticatorServer) CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*ServiceAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (*UnimplementedAuthenticatorServer) DisableServiceAccount(context.Context, *Identifier) (*ServiceAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableServiceAccount not implemented")
+}
+func (*UnimplementedAuthenticatorServer) RotateServiceAccountSecret(context.Context, *RotateSecretRequest) (*ServiceAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateServiceAccountSecret not implemented")
+}
+
+func RegisterAuthenticatorServer(s *grpc.Server, srv AuthenticatorServer) {
+	s.RegisterService(&_Authenticator_serviceDesc, srv)
+}
+
+func _Authenticator_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).Refresh(ctx, req.(*TokenRefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_GetRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).GetRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/GetRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).GetRoles(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleAssignment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/AssignRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).AssignRole(ctx, req.(*RoleAssignment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleAssignment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/RevokeRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).RevokeRole(ctx, req.(*RoleAssignment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_GetServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).GetServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/GetServiceAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).GetServiceAccount(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/CreateServiceAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_DisableServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).DisableServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/DisableServiceAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).DisableServiceAccount(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_RotateServiceAccountSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).RotateServiceAccountSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/RotateServiceAccountSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).RotateServiceAccountSecret(ctx, req.(*RotateSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Authenticator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Authenticator",
+	HandlerType: (*AuthenticatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _Authenticator_Login_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Authenticator_Refresh_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Authenticator_Logout_Handler,
+		},
+		{
+			MethodName: "GetRoles",
+			Handler:    _Authenticator_GetRoles_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _Authenticator_AssignRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _Authenticator_RevokeRole_Handler,
+		},
+		{
+			MethodName: "GetServiceAccount",
+			Handler:    _Authenticator_GetServiceAccount_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _Authenticator_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "DisableServiceAccount",
+			Handler:    _Authenticator_DisableServiceAccount_Handler,
+		},
+		{
+			MethodName: "RotateServiceAccountSecret",
+			Handler:    _Authenticator_RotateServiceAccountSecret_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// IdentifiersClient is the client API for Identifiers service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type IdentifiersClient interface {
+	GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error)
+	MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error)
+	// ListSystems returns the identifier systems supported, and whether identifiers in each system can be resolved or mapped
+	ListSystems(ctx context.Context, in *ListSystemsRequest, opts ...grpc.CallOption) (*ListSystemsResponse, error)
+	// ValidateIdentifier checks an identifier against the syntax rules of its system, such as the check digit of an NHS number
+	ValidateIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*IdentifierValidation, error)
+}
+
+type identifiersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIdentifiersClient(cc grpc.ClientConnInterface) IdentifiersClient {
+	return &identifiersClient{cc}
+}
+
+func (c *identifiersClient) GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error) {
+	out := new(any.Any)
+	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/GetIdentifier", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Identifiers_serviceDesc.Streams[0], "/apiv1.Identifiers/MapIdentifier", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &identifiersMapIdentifierClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Identifiers_MapIdentifierClient interface {
+	Recv() (*Identifier, error)
+	grpc.ClientStream
+}
+
+type identifiersMapIdentifierClient struct {
+	grpc.ClientStream
+}
+
+func (x *identifiersMapIdentifierClient) Recv() (*Identifier, error) {
+	m := new(Identifier)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *identifiersClient) ListSystems(ctx context.Context, in *ListSystemsRequest, opts ...grpc.CallOption) (*ListSystemsResponse, error) {
+	out := new(ListSystemsResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/ListSystems", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identifiersClient) ValidateIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*IdentifierValidation, error) {
+	out := new(IdentifierValidation)
+	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/ValidateIdentifier", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IdentifiersServer is the server API for Identifiers service.
+type IdentifiersServer interface {
+	GetIdentifier(context.Context, *Identifier) (*any.Any, error)
+	MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error
+	// ListSystems returns the identifier systems supported, and whether identifiers in each system can be resolved or mapped
+	ListSystems(context.Context, *ListSystemsRequest) (*ListSystemsResponse, error)
+	// ValidateIdentifier checks an identifier against the syntax rules of its system, such as the check digit of an NHS number
+	ValidateIdentifier(context.Context, *Identifier) (*IdentifierValidation, error)
+}
+
+// UnimplementedIdentifiersServer can be embedded to have forward compatible implementations.
+type UnimplementedIdentifiersServer struct {
+}
+
+func (*UnimplementedIdentifiersServer) GetIdentifier(context.Context, *Identifier) (*any.Any, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdentifier not implemented")
+}
+func (*UnimplementedIdentifiersServer) MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error {
+	return status.Errorf(codes.Unimplemented, "method MapIdentifier not implemented")
+}
+func (*UnimplementedIdentifiersServer) ListSystems(context.Context, *ListSystemsRequest) (*ListSystemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSystems not implemented")
+}
+func (*UnimplementedIdentifiersServer) ValidateIdentifier(context.Context, *Identifier) (*IdentifierValidation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateIdentifier not implemented")
+}
+
+func RegisterIdentifiersServer(s *grpc.Server, srv IdentifiersServer) {
+	s.RegisterService(&_Identifiers_serviceDesc, srv)
+}
+
+func _Identifiers_GetIdentifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifiersServer).GetIdentifier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Identifiers/GetIdentifier",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifiersServer).GetIdentifier(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identifiers_MapIdentifier_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IdentifierMapRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IdentifiersServer).MapIdentifier(m, &identifiersMapIdentifierServer{stream})
+}
+
+type Identifiers_MapIdentifierServer interface {
+	Send(*Identifier) error
+	grpc.ServerStream
+}
+
+type identifiersMapIdentifierServer struct {
+	grpc.ServerStream
+}
+
+func (x *identifiersMapIdentifierServer) Send(m *Identifier) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Identifiers_ListSystems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSystemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifiersServer).ListSystems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Identifiers/ListSystems",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifiersServer).ListSystems(ctx, req.(*ListSystemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identifiers_ValidateIdentifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifiersServer).ValidateIdentifier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Identifiers/ValidateIdentifier",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifiersServer).ValidateIdentifier(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Identifiers_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Identifiers",
+	HandlerType: (*IdentifiersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIdentifier",
+			Handler:    _Identifiers_GetIdentifier_Handler,
+		},
+		{
+			MethodName: "ListSystems",
+			Handler:    _Identifiers_ListSystems_Handler,
+		},
+		{
+			MethodName: "ValidateIdentifier",
+			Handler:    _Identifiers_ValidateIdentifier_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MapIdentifier",
+			Handler:       _Identifiers_MapIdentifier_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// IdentifierAdminClient is the client API for IdentifierAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type IdentifierAdminClient interface {
+	// GetMappings returns the stored mappings for the identifier specified
+	GetMappings(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*IdentifierMappings, error)
+	// CreateMapping records that two identifiers are equivalent, returning the stored mappings for the first identifier
+	CreateMapping(ctx context.Context, in *IdentifierMapping, opts ...grpc.CallOption) (*IdentifierMappings, error)
+	// DeleteMapping removes a mapping between two identifiers, returning the remaining mappings for the first identifier
+	DeleteMapping(ctx context.Context, in *IdentifierMapping, opts ...grpc.CallOption) (*IdentifierMappings, error)
+}
+
+type identifierAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIdentifierAdminClient(cc grpc.ClientConnInterface) IdentifierAdminClient {
+	return &identifierAdminClient{cc}
+}
+
+func (c *identifierAdminClient) GetMappings(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*IdentifierMappings, error) {
+	out := new(IdentifierMappings)
+	err := c.cc.Invoke(ctx, "/apiv1.IdentifierAdmin/GetMappings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identifierAdminClient) CreateMapping(ctx context.Context, in *IdentifierMapping, opts ...grpc.CallOption) (*IdentifierMappings, error) {
+	out := new(IdentifierMappings)
+	err := c.cc.Invoke(ctx, "/apiv1.IdentifierAdmin/CreateMapping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identifierAdminClient) DeleteMapping(ctx context.Context, in *IdentifierMapping, opts ...grpc.CallOption) (*IdentifierMappings, error) {
+	out := new(IdentifierMappings)
+	err := c.cc.Invoke(ctx, "/apiv1.IdentifierAdmin/DeleteMapping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IdentifierAdminServer is the server API for IdentifierAdmin service.
+type IdentifierAdminServer interface {
+	// GetMappings returns the stored mappings for the identifier specified
+	GetMappings(context.Context, *Identifier) (*IdentifierMappings, error)
+	// CreateMapping records that two identifiers are equivalent, returning the stored mappings for the first identifier
+	CreateMapping(context.Context, *IdentifierMapping) (*IdentifierMappings, error)
+	// DeleteMapping removes a mapping between two identifiers, returning the remaining mappings for the first identifier
+	DeleteMapping(context.Context, *IdentifierMapping) (*IdentifierMappings, error)
+}
+
+// UnimplementedIdentifierAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedIdentifierAdminServer struct {
+}
+
+func (*UnimplementedIdentifierAdminServer) GetMappings(context.Context, *Identifier) (*IdentifierMappings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMappings not implemented")
+}
+func (*UnimplementedIdentifierAdminServer) CreateMapping(context.Context, *IdentifierMapping) (*IdentifierMappings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMapping not implemented")
+}
+func (*UnimplementedIdentifierAdminServer) DeleteMapping(context.Context, *IdentifierMapping) (*IdentifierMappings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMapping not implemented")
+}
+
+func RegisterIdentifierAdminServer(s *grpc.Server, srv IdentifierAdminServer) {
+	s.RegisterService(&_IdentifierAdmin_serviceDesc, srv)
+}
+
+func _IdentifierAdmin_GetMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifierAdminServer).GetMappings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.IdentifierAdmin/GetMappings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifierAdminServer).GetMappings(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IdentifierAdmin_CreateMapping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdentifierMapping)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifierAdminServer).CreateMapping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.IdentifierAdmin/CreateMapping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifierAdminServer).CreateMapping(ctx, req.(*IdentifierMapping))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IdentifierAdmin_DeleteMapping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdentifierMapping)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifierAdminServer).DeleteMapping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.IdentifierAdmin/DeleteMapping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifierAdminServer).DeleteMapping(ctx, req.(*IdentifierMapping))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _IdentifierAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.IdentifierAdmin",
+	HandlerType: (*IdentifierAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMappings",
+			Handler:    _IdentifierAdmin_GetMappings_Handler,
+		},
+		{
+			MethodName: "CreateMapping",
+			Handler:    _IdentifierAdmin_CreateMapping_Handler,
+		},
+		{
+			MethodName: "DeleteMapping",
+			Handler:    _IdentifierAdmin_DeleteMapping_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// DocumentServiceClient is the client API for DocumentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DocumentServiceClient interface {
+	PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error)
+	// PublishDocuments publishes a stream of documents, returning a response for each document
+	// as it is processed, which may not be in the order requested. Failures are reported
+	// per-document, rather than terminating the stream.
+	PublishDocuments(ctx context.Context, opts ...grpc.CallOption) (DocumentService_PublishDocumentsClient, error)
+	// UploadDocument publishes a single document streamed in chunks, for documents larger than the maximum
+	// message size. The first message contains the request, and the remainder the content of the document.
+	// Also available over HTTP as a POST to /v1/document/upload, with the content as the request body.
+	UploadDocument(ctx context.Context, opts ...grpc.CallOption) (DocumentService_UploadDocumentClient, error)
+	// GetDeliveryStatus returns the status of onward deliveries of a published document, such as to
+	// the patient's registered general practice.
+	GetDeliveryStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*DeliveryStatus, error)
+	// ListPendingDocuments returns the documents queued for retry after failed publication, including
+	// those abandoned after the maximum number of attempts ('dead letters'), without their content.
+	ListPendingDocuments(ctx context.Context, in *ListPendingDocumentsRequest, opts ...grpc.CallOption) (*PendingDocuments, error)
+	// GetPublicationStatus returns the status of publication of a queued document, using its receipt
+	GetPublicationStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PublicationStatus, error)
+	// GetDocumentHistory returns the versions of the document with the identifier specified, including any
+	// versions it replaced and by which it has been replaced, oldest first
+	GetDocumentHistory(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*DocumentHistory, error)
+}
+
+type documentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDocumentServiceClient(cc grpc.ClientConnInterface) DocumentServiceClient {
+	return &documentServiceClient{cc}
+}
+
+func (c *documentServiceClient) PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error) {
+	out := new(PublishDocumentResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/PublishDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *documentServiceClient) PublishDocuments(ctx context.Context, opts ...grpc.CallOption) (DocumentService_PublishDocumentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DocumentService_serviceDesc.Streams[0], "/apiv1.DocumentService/PublishDocuments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &documentServicePublishDocumentsClient{stream}
+	return x, nil
+}
+
+type DocumentService_PublishDocumentsClient interface {
+	Send(*PublishDocumentRequest) error
+	Recv() (*PublishDocumentResponse, error)
+	grpc.ClientStream
+}
+
+type documentServicePublishDocumentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *documentServicePublishDocumentsClient) Send(m *PublishDocumentRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *documentServicePublishDocumentsClient) Recv() (*PublishDocumentResponse, error) {
+	m := new(PublishDocumentResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *documentServiceClient) UploadDocument(ctx context.Context, opts ...grpc.CallOption) (DocumentService_UploadDocumentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DocumentService_serviceDesc.Streams[1], "/apiv1.DocumentService/UploadDocument", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &documentServiceUploadDocumentClient{stream}
+	return x, nil
+}
+
+type DocumentService_UploadDocumentClient interface {
+	Send(*UploadDocumentRequest) error
+	CloseAndRecv() (*PublishDocumentResponse, error)
+	grpc.ClientStream
+}
+
+type documentServiceUploadDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *documentServiceUploadDocumentClient) Send(m *UploadDocumentRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *documentServiceUploadDocumentClient) CloseAndRecv() (*PublishDocumentResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PublishDocumentResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *documentServiceClient) GetDeliveryStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*DeliveryStatus, error) {
+	out := new(DeliveryStatus)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/GetDeliveryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *documentServiceClient) ListPendingDocuments(ctx context.Context, in *ListPendingDocumentsRequest, opts ...grpc.CallOption) (*PendingDocuments, error) {
+	out := new(PendingDocuments)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/ListPendingDocuments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *documentServiceClient) GetPublicationStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PublicationStatus, error) {
+	out := new(PublicationStatus)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/GetPublicationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *documentServiceClient) GetDocumentHistory(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*DocumentHistory, error) {
+	out := new(DocumentHistory)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/GetDocumentHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DocumentServiceServer is the server API for DocumentService service.
+type DocumentServiceServer interface {
+	PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error)
+	// PublishDocuments publishes a stream of documents, returning a response for each document
+	// as it is processed, which may not be in the order requested. Failures are reported
+	// per-document, rather than terminating the stream.
+	PublishDocuments(DocumentService_PublishDocumentsServer) error
+	// UploadDocument publishes a single document streamed in chunks, for documents larger than the maximum
+	// message size. The first message contains the request, and the remainder the content of the document.
+	// Also available over HTTP as a POST to /v1/document/upload, with the content as the request body.
+	UploadDocument(DocumentService_UploadDocumentServer) error
+	// GetDeliveryStatus returns the status of onward deliveries of a published document, such as to
+	// the patient's registered general practice.
+	GetDeliveryStatus(context.Context, *Identifier) (*DeliveryStatus, error)
+	// ListPendingDocuments returns the documents queued for retry after failed publication, including
+	// those abandoned after the maximum number of attempts ('dead letters'), without their content.
+	ListPendingDocuments(context.Context, *ListPendingDocumentsRequest) (*PendingDocuments, error)
+	// GetPublicationStatus returns the status of publication of a queued document, using its receipt
+	GetPublicationStatus(context.Context, *Identifier) (*PublicationStatus, error)
+	// GetDocumentHistory returns the versions of the document with the identifier specified, including any
+	// versions it replaced and by which it has been replaced, oldest first
+	GetDocumentHistory(context.Context, *Identifier) (*DocumentHistory, error)
+}
+
+// UnimplementedDocumentServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedDocumentServiceServer struct {
+}
+
+func (*UnimplementedDocumentServiceServer) PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDocument not implemented")
+}
+func (*UnimplementedDocumentServiceServer) PublishDocuments(DocumentService_PublishDocumentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishDocuments not implemented")
+}
+func (*UnimplementedDocumentServiceServer) UploadDocument(DocumentService_UploadDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadDocument not implemented")
+}
+func (*UnimplementedDocumentServiceServer) GetDeliveryStatus(context.Context, *Identifier) (*DeliveryStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStatus not implemented")
+}
+func (*UnimplementedDocumentServiceServer) ListPendingDocuments(context.Context, *ListPendingDocumentsRequest) (*PendingDocuments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingDocuments not implemented")
+}
+func (*UnimplementedDocumentServiceServer) GetPublicationStatus(context.Context, *Identifier) (*PublicationStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicationStatus not implemented")
+}
+func (*UnimplementedDocumentServiceServer) GetDocumentHistory(context.Context, *Identifier) (*DocumentHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDocumentHistory not implemented")
+}
+
+func RegisterDocumentServiceServer(s *grpc.Server, srv DocumentServiceServer) {
+	s.RegisterService(&_DocumentService_serviceDesc, srv)
+}
+
+func _DocumentService_PublishDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).PublishDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/PublishDocument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).PublishDocument(ctx, req.(*PublishDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocumentService_PublishDocuments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocumentServiceServer).PublishDocuments(&documentServicePublishDocumentsServer{stream})
+}
+
+type DocumentService_PublishDocumentsServer interface {
+	Send(*PublishDocumentResponse) error
+	Recv() (*PublishDocumentRequest, error)
+	grpc.ServerStream
+}
+
+type documentServicePublishDocumentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *documentServicePublishDocumentsServer) Send(m *PublishDocumentResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *documentServicePublishDocumentsServer) Recv() (*PublishDocumentRequest, error) {
+	m := new(PublishDocumentRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DocumentService_UploadDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocumentServiceServer).UploadDocument(&documentServiceUploadDocumentServer{stream})
+}
+
+type DocumentService_UploadDocumentServer interface {
+	SendAndClose(*PublishDocumentResponse) error
+	Recv() (*UploadDocumentRequest, error)
+	grpc.ServerStream
+}
+
+type documentServiceUploadDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *documentServiceUploadDocumentServer) SendAndClose(m *PublishDocumentResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *documentServiceUploadDocumentServer) Recv() (*UploadDocumentRequest, error) {
+	m := new(UploadDocumentRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DocumentService_GetDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).GetDeliveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/GetDeliveryStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).GetDeliveryStatus(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocumentService_ListPendingDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingDocumentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).ListPendingDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/ListPendingDocuments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).ListPendingDocuments(ctx, req.(*ListPendingDocumentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocumentService_GetPublicationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).GetPublicationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/GetPublicationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).GetPublicationStatus(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocumentService_GetDocumentHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).GetDocumentHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/GetDocumentHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).GetDocumentHistory(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DocumentService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.DocumentService",
+	HandlerType: (*DocumentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublishDocument",
+			Handler:    _DocumentService_PublishDocument_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStatus",
+			Handler:    _DocumentService_GetDeliveryStatus_Handler,
+		},
+		{
+			MethodName: "ListPendingDocuments",
+			Handler:    _DocumentService_ListPendingDocuments_Handler,
+		},
+		{
+			MethodName: "GetPublicationStatus",
+			Handler:    _DocumentService_GetPublicationStatus_Handler,
+		},
+		{
+			MethodName: "GetDocumentHistory",
+			Handler:    _DocumentService_GetDocumentHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PublishDocuments",
+			Handler:       _DocumentService_PublishDocuments_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadDocument",
+			Handler:       _DocumentService_UploadDocument_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// DocumentRepositoryClient is the client API for DocumentRepository service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DocumentRepositoryClient interface {
+	// GetDocument returns the content of a published document, such as for verification after publication
+	GetDocument(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Attachment, error)
+}
+
+type documentRepositoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDocumentRepositoryClient(cc grpc.ClientConnInterface) DocumentRepositoryClient {
+	return &documentRepositoryClient{cc}
+}
+
+func (c *documentRepositoryClient) GetDocument(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Attachment, error) {
+	out := new(Attachment)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentRepository/GetDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DocumentRepositoryServer is the server API for DocumentRepository service.
+type DocumentRepositoryServer interface {
+	// GetDocument returns the content of a published document, such as for verification after publication
+	GetDocument(context.Context, *Identifier) (*Attachment, error)
+}
+
+// UnimplementedDocumentRepositoryServer can be embedded to have forward compatible implementations.
+type UnimplementedDocumentRepositoryServer struct {
+}
+
+func (*UnimplementedDocumentRepositoryServer) GetDocument(context.Context, *Identifier) (*Attachment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDocument not implemented")
+}
+
+func RegisterDocumentRepositoryServer(s *grpc.Server, srv DocumentRepositoryServer) {
+	s.RegisterService(&_DocumentRepository_serviceDesc, srv)
+}
+
+func _DocumentRepository_GetDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentRepositoryServer).GetDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentRepository/GetDocument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentRepositoryServer).GetDocument(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DocumentRepository_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.DocumentRepository",
+	HandlerType: (*DocumentRepositoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDocument",
+			Handler:    _DocumentRepository_GetDocument_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// NotificationServiceClient is the client API for NotificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationServiceClient interface {
+	Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
+}
+
+type notificationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotificationServiceClient(cc grpc.ClientConnInterface) NotificationServiceClient {
+	return &notificationServiceClient{cc}
+}
+
+func (c *notificationServiceClient) Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
+	out := new(NotificationResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.NotificationService/Notify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationServiceServer is the server API for NotificationService service.
+type NotificationServiceServer interface {
+	Notify(context.Context, *NotificationRequest) (*NotificationResponse, error)
+}
+
+// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNotificationServiceServer struct {
+}
+
+func (*UnimplementedNotificationServiceServer) Notify(context.Context, *NotificationRequest) (*NotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
+}
+
+func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
+	s.RegisterService(&_NotificationService_serviceDesc, srv)
+}
+
+func _NotificationService_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).Notify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.NotificationService/Notify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).Notify(ctx, req.(*NotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NotificationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Notify",
+			Handler:    _NotificationService_Notify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// PatientDirectoryClient is the client API for PatientDirectory service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PatientDirectoryClient interface {
+	// GetPatient returns the patient with the specified identifier, merging data from all relevant backends
+	GetPatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Patient, error)
+	// SearchPatient searches for patients by demographic details
+	SearchPatient(ctx context.Context, in *PatientSearchRequest, opts ...grpc.CallOption) (PatientDirectory_SearchPatientClient, error)
+	// GetPatientLinks returns the history of merges of patient records linked to the patient with
+	// the specified identifier, including prior identifiers superseded by the current record
+	GetPatientLinks(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientLinks, error)
+	// GetPatientStatus returns whether the patient with the specified identifier is alive or deceased,
+	// for clients to warn users before, for example, sending correspondence
+	GetPatientStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientStatus, error)
+	// UpdatePatientDemographics submits a change in contact details (addresses, telephone numbers or
+	// email addresses) to the master patient index, returning the updated patient.
+	// This requires the patient:write scope, which is not granted to clinicians by default.
+	UpdatePatientDemographics(ctx context.Context, in *UpdatePatientDemographicsRequest, opts ...grpc.CallOption) (*Patient, error)
+	// PrefetchPatients fetches the patients with the identifiers specified, with up to the configured
+	// number fetched concurrently, so that back-end caches are populated in advance, such as for the
+	// patients booked into the day's clinics. Progress is returned for each identifier as it is fetched,
+	// which may not be in the order requested. Failures are reported per-identifier.
+	PrefetchPatients(ctx context.Context, opts ...grpc.CallOption) (PatientDirectory_PrefetchPatientsClient, error)
+}
+
+type patientDirectoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPatientDirectoryClient(cc grpc.ClientConnInterface) PatientDirectoryClient {
+	return &patientDirectoryClient{cc}
+}
+
+func (c *patientDirectoryClient) GetPatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Patient, error) {
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/GetPatient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientDirectoryClient) SearchPatient(ctx context.Context, in *PatientSearchRequest, opts ...grpc.CallOption) (PatientDirectory_SearchPatientClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PatientDirectory_serviceDesc.Streams[0], "/apiv1.PatientDirectory/SearchPatient", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &patientDirectorySearchPatientClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PatientDirectory_SearchPatientClient interface {
+	Recv() (*Patient, error)
+	grpc.ClientStream
+}
+
+type patientDirectorySearchPatientClient struct {
+	grpc.ClientStream
+}
+
+func (x *patientDirectorySearchPatientClient) Recv() (*Patient, error) {
+	m := new(Patient)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *patientDirectoryClient) GetPatientLinks(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientLinks, error) {
+	out := new(PatientLinks)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/GetPatientLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientDirectoryClient) GetPatientStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientStatus, error) {
+	out := new(PatientStatus)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/GetPatientStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientDirectoryClient) UpdatePatientDemographics(ctx context.Context, in *UpdatePatientDemographicsRequest, opts ...grpc.CallOption) (*Patient, error) {
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/UpdatePatientDemographics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientDirectoryClient) PrefetchPatients(ctx context.Context, opts ...grpc.CallOption) (PatientDirectory_PrefetchPatientsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PatientDirectory_serviceDesc.Streams[1], "/apiv1.PatientDirectory/PrefetchPatients", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &patientDirectoryPrefetchPatientsClient{stream}
+	return x, nil
+}
+
+type PatientDirectory_PrefetchPatientsClient interface {
+	Send(*Identifier) error
+	Recv() (*PrefetchProgress, error)
+	grpc.ClientStream
+}
+
+type patientDirectoryPrefetchPatientsClient struct {
+	grpc.ClientStream
+}
+
+func (x *patientDirectoryPrefetchPatientsClient) Send(m *Identifier) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *patientDirectoryPrefetchPatientsClient) Recv() (*PrefetchProgress, error) {
+	m := new(PrefetchProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PatientDirectoryServer is the server API for PatientDirectory service.
+type PatientDirectoryServer interface {
+	// GetPatient returns the patient with the specified identifier, merging data from all relevant backends
+	GetPatient(context.Context, *Identifier) (*Patient, error)
+	// SearchPatient searches for patients by demographic details
+	SearchPatient(*PatientSearchRequest, PatientDirectory_SearchPatientServer) error
+	// GetPatientLinks returns the history of merges of patient records linked to the patient with
+	// the specified identifier, including prior identifiers superseded by the current record
+	GetPatientLinks(context.Context, *Identifier) (*PatientLinks, error)
+	// GetPatientStatus returns whether the patient with the specified identifier is alive or deceased,
+	// for clients to warn users before, for example, sending correspondence
+	GetPatientStatus(context.Context, *Identifier) (*PatientStatus, error)
+	// UpdatePatientDemographics submits a change in contact details (addresses, telephone numbers or
+	// email addresses) to the master patient index, returning the updated patient.
+	// This requires the patient:write scope, which is not granted to clinicians by default.
+	UpdatePatientDemographics(context.Context, *UpdatePatientDemographicsRequest) (*Patient, error)
+	// PrefetchPatients fetches the patients with the identifiers specified, with up to the configured
+	// number fetched concurrently, so that back-end caches are populated in advance, such as for the
+	// patients booked into the day's clinics. Progress is returned for each identifier as it is fetched,
+	// which may not be in the order requested. Failures are reported per-identifier.
+	PrefetchPatients(PatientDirectory_PrefetchPatientsServer) error
+}
+
+// UnimplementedPatientDirectoryServer can be embedded to have forward compatible implementations.
+type UnimplementedPatientDirectoryServer struct {
+}
+
+func (*UnimplementedPatientDirectoryServer) GetPatient(context.Context, *Identifier) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatient not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) SearchPatient(*PatientSearchRequest, PatientDirectory_SearchPatientServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchPatient not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) GetPatientLinks(context.Context, *Identifier) (*PatientLinks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatientLinks not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) GetPatientStatus(context.Context, *Identifier) (*PatientStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatientStatus not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) UpdatePatientDemographics(context.Context, *UpdatePatientDemographicsRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePatientDemographics not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) PrefetchPatients(PatientDirectory_PrefetchPatientsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PrefetchPatients not implemented")
+}
+
+func RegisterPatientDirectoryServer(s *grpc.Server, srv PatientDirectoryServer) {
+	s.RegisterService(&_PatientDirectory_serviceDesc, srv)
+}
+
+func _PatientDirectory_GetPatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientDirectoryServer).GetPatient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PatientDirectory/GetPatient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientDirectoryServer).GetPatient(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientDirectory_SearchPatient_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PatientSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PatientDirectoryServer).SearchPatient(m, &patientDirectorySearchPatientServer{stream})
+}
+
+type PatientDirectory_SearchPatientServer interface {
+	Send(*Patient) error
+	grpc.ServerStream
+}
+
+type patientDirectorySearchPatientServer struct {
+	grpc.ServerStream
+}
+
+func (x *patientDirectorySearchPatientServer) Send(m *Patient) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PatientDirectory_GetPatientLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientDirectoryServer).GetPatientLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PatientDirectory/GetPatientLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientDirectoryServer).GetPatientLinks(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientDirectory_GetPatientStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientDirectoryServer).GetPatientStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PatientDirectory/GetPatientStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientDirectoryServer).GetPatientStatus(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientDirectory_UpdatePatientDemographics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePatientDemographicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientDirectoryServer).UpdatePatientDemographics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PatientDirectory/UpdatePatientDemographics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientDirectoryServer).UpdatePatientDemographics(ctx, req.(*UpdatePatientDemographicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientDirectory_PrefetchPatients_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PatientDirectoryServer).PrefetchPatients(&patientDirectoryPrefetchPatientsServer{stream})
+}
+
+type PatientDirectory_PrefetchPatientsServer interface {
+	Send(*PrefetchProgress) error
+	Recv() (*Identifier, error)
+	grpc.ServerStream
+}
+
+type patientDirectoryPrefetchPatientsServer struct {
+	grpc.ServerStream
+}
+
+func (x *patientDirectoryPrefetchPatientsServer) Send(m *PrefetchProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *patientDirectoryPrefetchPatientsServer) Recv() (*Identifier, error) {
+	m := new(Identifier)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _PatientDirectory_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PatientDirectory",
+	HandlerType: (*PatientDirectoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPatient",
+			Handler:    _PatientDirectory_GetPatient_Handler,
+		},
+		{
+			MethodName: "GetPatientLinks",
+			Handler:    _PatientDirectory_GetPatientLinks_Handler,
+		},
+		{
+			MethodName: "GetPatientStatus",
+			Handler:    _PatientDirectory_GetPatientStatus_Handler,
+		},
+		{
+			MethodName: "UpdatePatientDemographics",
+			Handler:    _PatientDirectory_UpdatePatientDemographics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchPatient",
+			Handler:       _PatientDirectory_SearchPatient_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PrefetchPatients",
+			Handler:       _PatientDirectory_PrefetchPatients_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// ClinicServiceClient is the client API for ClinicService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ClinicServiceClient interface {
+	// GetClinicSchedule returns the appointments, including free slots, for a clinic on a single date
+	GetClinicSchedule(ctx context.Context, in *ClinicScheduleRequest, opts ...grpc.CallOption) (*ClinicSchedule, error)
+	// GetClinic returns the details of a clinic, such as its description, location and specialty
+	GetClinic(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Clinic, error)
+	// GetCurrentAdmission returns the current inpatient admission of a patient, with their ward and bed,
+	// or NotFound if the patient is not currently admitted
+	GetCurrentAdmission(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Admission, error)
+}
+
+type clinicServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClinicServiceClient(cc grpc.ClientConnInterface) ClinicServiceClient {
+	return &clinicServiceClient{cc}
+}
+
+func (c *clinicServiceClient) GetClinicSchedule(ctx context.Context, in *ClinicScheduleRequest, opts ...grpc.CallOption) (*ClinicSchedule, error) {
+	out := new(ClinicSchedule)
+	err := c.cc.Invoke(ctx, "/apiv1.ClinicService/GetClinicSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clinicServiceClient) GetClinic(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Clinic, error) {
+	out := new(Clinic)
+	err := c.cc.Invoke(ctx, "/apiv1.ClinicService/GetClinic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clinicServiceClient) GetCurrentAdmission(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Admission, error) {
+	out := new(Admission)
+	err := c.cc.Invoke(ctx, "/apiv1.ClinicService/GetCurrentAdmission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClinicServiceServer is the server API for ClinicService service.
+type ClinicServiceServer interface {
+	// GetClinicSchedule returns the appointments, including free slots, for a clinic on a single date
+	GetClinicSchedule(context.Context, *ClinicScheduleRequest) (*ClinicSchedule, error)
+	// GetClinic returns the details of a clinic, such as its description, location and specialty
+	GetClinic(context.Context, *Identifier) (*Clinic, error)
+	// GetCurrentAdmission returns the current inpatient admission of a patient, with their ward and bed,
+	// or NotFound if the patient is not currently admitted
+	GetCurrentAdmission(context.Context, *Identifier) (*Admission, error)
+}
+
+// UnimplementedClinicServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedClinicServiceServer struct {
+}
+
+func (*UnimplementedClinicServiceServer) GetClinicSchedule(context.Context, *ClinicScheduleRequest) (*ClinicSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClinicSchedule not implemented")
+}
+func (*UnimplementedClinicServiceServer) GetClinic(context.Context, *Identifier) (*Clinic, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClinic not implemented")
+}
+func (*UnimplementedClinicServiceServer) GetCurrentAdmission(context.Context, *Identifier) (*Admission, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentAdmission not implemented")
+}
+
+func RegisterClinicServiceServer(s *grpc.Server, srv ClinicServiceServer) {
+	s.RegisterService(&_ClinicService_serviceDesc, srv)
+}
+
+func _ClinicService_GetClinicSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClinicScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClinicServiceServer).GetClinicSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.ClinicService/GetClinicSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClinicServiceServer).GetClinicSchedule(ctx, req.(*ClinicScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Authenticator_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
+func _ClinicService_GetClinic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticatorServer).Login(ctx, in)
+		return srv.(ClinicServiceServer).GetClinic(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.Authenticator/Login",
+		FullMethod: "/apiv1.ClinicService/GetClinic",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticatorServer).Login(ctx, req.(*LoginRequest))
+		return srv.(ClinicServiceServer).GetClinic(ctx, req.(*Identifier))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Authenticator_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRefreshRequest)
+func _ClinicService_GetCurrentAdmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticatorServer).Refresh(ctx, in)
+		return srv.(ClinicServiceServer).GetCurrentAdmission(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.Authenticator/Refresh",
+		FullMethod: "/apiv1.ClinicService/GetCurrentAdmission",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticatorServer).Refresh(ctx, req.(*TokenRefreshRequest))
+		return srv.(ClinicServiceServer).GetCurrentAdmission(ctx, req.(*Identifier))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Authenticator_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.Authenticator",
-	HandlerType: (*AuthenticatorServer)(nil),
+var _ClinicService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.ClinicService",
+	HandlerType: (*ClinicServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Login",
-			Handler:    _Authenticator_Login_Handler,
+			MethodName: "GetClinicSchedule",
+			Handler:    _ClinicService_GetClinicSchedule_Handler,
 		},
 		{
-			MethodName: "Refresh",
-			Handler:    _Authenticator_Refresh_Handler,
+			MethodName: "GetClinic",
+			Handler:    _ClinicService_GetClinic_Handler,
+		},
+		{
+			MethodName: "GetCurrentAdmission",
+			Handler:    _ClinicService_GetCurrentAdmission_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// IdentifiersClient is the client API for Identifiers service.
+// PractitionerDirectoryClient is the client API for PractitionerDirectory service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type IdentifiersClient interface {
-	GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error)
-	MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error)
+type PractitionerDirectoryClient interface {
+	// SearchPractitioner streams practitioners matching the search, ranked by the back-end directory.
+	// If more results are available than the page size requested, a token for the next page is
+	// returned in the "next-page-token" trailer.
+	SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error)
+	// GetPractitionerPhoto returns the photograph of a practitioner, usually as a JPEG.
+	// Over HTTP, the image itself is served at /v1/practitioners/{id}/photo, with ETag support.
+	GetPractitionerPhoto(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Attachment, error)
+	// GetAccountStatus returns the status of a user's directory account, including whether it is locked
+	// and when the password expires, so that applications can warn users before their password expires.
+	GetAccountStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*AccountStatus, error)
+	// ChangePassword changes the password of a user, who must provide their current password,
+	// returning the status of their account after the change.
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*AccountStatus, error)
 }
 
-type identifiersClient struct {
+type practitionerDirectoryClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewIdentifiersClient(cc grpc.ClientConnInterface) IdentifiersClient {
-	return &identifiersClient{cc}
-}
-
-func (c *identifiersClient) GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error) {
-	out := new(any.Any)
-	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/GetIdentifier", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func NewPractitionerDirectoryClient(cc grpc.ClientConnInterface) PractitionerDirectoryClient {
+	return &practitionerDirectoryClient{cc}
 }
 
-func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Identifiers_serviceDesc.Streams[0], "/apiv1.Identifiers/MapIdentifier", opts...)
+func (c *practitionerDirectoryClient) SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PractitionerDirectory_serviceDesc.Streams[0], "/apiv1.PractitionerDirectory/SearchPractitioner", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &identifiersMapIdentifierClient{stream}
+	x := &practitionerDirectorySearchPractitionerClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -781,341 +7406,745 @@ func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMap
 	return x, nil
 }
 
-type Identifiers_MapIdentifierClient interface {
-	Recv() (*Identifier, error)
+type PractitionerDirectory_SearchPractitionerClient interface {
+	Recv() (*Practitioner, error)
 	grpc.ClientStream
 }
 
-type identifiersMapIdentifierClient struct {
+type practitionerDirectorySearchPractitionerClient struct {
 	grpc.ClientStream
 }
 
-func (x *identifiersMapIdentifierClient) Recv() (*Identifier, error) {
-	m := new(Identifier)
+func (x *practitionerDirectorySearchPractitionerClient) Recv() (*Practitioner, error) {
+	m := new(Practitioner)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// IdentifiersServer is the server API for Identifiers service.
-type IdentifiersServer interface {
-	GetIdentifier(context.Context, *Identifier) (*any.Any, error)
-	MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error
+func (c *practitionerDirectoryClient) GetPractitionerPhoto(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Attachment, error) {
+	out := new(Attachment)
+	err := c.cc.Invoke(ctx, "/apiv1.PractitionerDirectory/GetPractitionerPhoto", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedIdentifiersServer can be embedded to have forward compatible implementations.
-type UnimplementedIdentifiersServer struct {
+func (c *practitionerDirectoryClient) GetAccountStatus(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*AccountStatus, error) {
+	out := new(AccountStatus)
+	err := c.cc.Invoke(ctx, "/apiv1.PractitionerDirectory/GetAccountStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (*UnimplementedIdentifiersServer) GetIdentifier(context.Context, *Identifier) (*any.Any, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetIdentifier not implemented")
+func (c *practitionerDirectoryClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*AccountStatus, error) {
+	out := new(AccountStatus)
+	err := c.cc.Invoke(ctx, "/apiv1.PractitionerDirectory/ChangePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (*UnimplementedIdentifiersServer) MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error {
-	return status.Errorf(codes.Unimplemented, "method MapIdentifier not implemented")
+
+// PractitionerDirectoryServer is the server API for PractitionerDirectory service.
+type PractitionerDirectoryServer interface {
+	// SearchPractitioner streams practitioners matching the search, ranked by the back-end directory.
+	// If more results are available than the page size requested, a token for the next page is
+	// returned in the "next-page-token" trailer.
+	SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error
+	// GetPractitionerPhoto returns the photograph of a practitioner, usually as a JPEG.
+	// Over HTTP, the image itself is served at /v1/practitioners/{id}/photo, with ETag support.
+	GetPractitionerPhoto(context.Context, *Identifier) (*Attachment, error)
+	// GetAccountStatus returns the status of a user's directory account, including whether it is locked
+	// and when the password expires, so that applications can warn users before their password expires.
+	GetAccountStatus(context.Context, *Identifier) (*AccountStatus, error)
+	// ChangePassword changes the password of a user, who must provide their current password,
+	// returning the status of their account after the change.
+	ChangePassword(context.Context, *ChangePasswordRequest) (*AccountStatus, error)
 }
 
-func RegisterIdentifiersServer(s *grpc.Server, srv IdentifiersServer) {
-	s.RegisterService(&_Identifiers_serviceDesc, srv)
+// UnimplementedPractitionerDirectoryServer can be embedded to have forward compatible implementations.
+type UnimplementedPractitionerDirectoryServer struct {
 }
 
-func _Identifiers_GetIdentifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func (*UnimplementedPractitionerDirectoryServer) SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchPractitioner not implemented")
+}
+func (*UnimplementedPractitionerDirectoryServer) GetPractitionerPhoto(context.Context, *Identifier) (*Attachment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPractitionerPhoto not implemented")
+}
+func (*UnimplementedPractitionerDirectoryServer) GetAccountStatus(context.Context, *Identifier) (*AccountStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStatus not implemented")
+}
+func (*UnimplementedPractitionerDirectoryServer) ChangePassword(context.Context, *ChangePasswordRequest) (*AccountStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+
+func RegisterPractitionerDirectoryServer(s *grpc.Server, srv PractitionerDirectoryServer) {
+	s.RegisterService(&_PractitionerDirectory_serviceDesc, srv)
+}
+
+func _PractitionerDirectory_SearchPractitioner_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PractitionerSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PractitionerDirectoryServer).SearchPractitioner(m, &practitionerDirectorySearchPractitionerServer{stream})
+}
+
+type PractitionerDirectory_SearchPractitionerServer interface {
+	Send(*Practitioner) error
+	grpc.ServerStream
+}
+
+type practitionerDirectorySearchPractitionerServer struct {
+	grpc.ServerStream
+}
+
+func (x *practitionerDirectorySearchPractitionerServer) Send(m *Practitioner) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PractitionerDirectory_GetPractitionerPhoto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Identifier)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(IdentifiersServer).GetIdentifier(ctx, in)
+		return srv.(PractitionerDirectoryServer).GetPractitionerPhoto(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.Identifiers/GetIdentifier",
+		FullMethod: "/apiv1.PractitionerDirectory/GetPractitionerPhoto",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(IdentifiersServer).GetIdentifier(ctx, req.(*Identifier))
+		return srv.(PractitionerDirectoryServer).GetPractitionerPhoto(ctx, req.(*Identifier))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Identifiers_MapIdentifier_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(IdentifierMapRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _PractitionerDirectory_GetAccountStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(IdentifiersServer).MapIdentifier(m, &identifiersMapIdentifierServer{stream})
-}
-
-type Identifiers_MapIdentifierServer interface {
-	Send(*Identifier) error
-	grpc.ServerStream
-}
-
-type identifiersMapIdentifierServer struct {
-	grpc.ServerStream
+	if interceptor == nil {
+		return srv.(PractitionerDirectoryServer).GetAccountStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PractitionerDirectory/GetAccountStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PractitionerDirectoryServer).GetAccountStatus(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *identifiersMapIdentifierServer) Send(m *Identifier) error {
-	return x.ServerStream.SendMsg(m)
+func _PractitionerDirectory_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PractitionerDirectoryServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PractitionerDirectory/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PractitionerDirectoryServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var _Identifiers_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.Identifiers",
-	HandlerType: (*IdentifiersServer)(nil),
+var _PractitionerDirectory_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PractitionerDirectory",
+	HandlerType: (*PractitionerDirectoryServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "GetIdentifier",
-			Handler:    _Identifiers_GetIdentifier_Handler,
+			MethodName: "GetPractitionerPhoto",
+			Handler:    _PractitionerDirectory_GetPractitionerPhoto_Handler,
+		},
+		{
+			MethodName: "GetAccountStatus",
+			Handler:    _PractitionerDirectory_GetAccountStatus_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _PractitionerDirectory_ChangePassword_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "MapIdentifier",
-			Handler:       _Identifiers_MapIdentifier_Handler,
+			StreamName:    "SearchPractitioner",
+			Handler:       _PractitionerDirectory_SearchPractitioner_Handler,
 			ServerStreams: true,
 		},
 	},
 	Metadata: "services.proto",
 }
 
-// DocumentServiceClient is the client API for DocumentService service.
+// TerminologyClient is the client API for Terminology service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type DocumentServiceClient interface {
-	PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error)
+type TerminologyClient interface {
+	// SearchConcepts searches for concepts using free text, optionally constrained by an expression
+	// constraint (ECL) or reference set membership. The preferred language and dialect of returned
+	// terms are determined from the accept-language of the request.
+	SearchConcepts(ctx context.Context, in *ConceptSearchRequest, opts ...grpc.CallOption) (*ConceptSearchResponse, error)
 }
 
-type documentServiceClient struct {
+type terminologyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTerminologyClient(cc grpc.ClientConnInterface) TerminologyClient {
+	return &terminologyClient{cc}
+}
+
+func (c *terminologyClient) SearchConcepts(ctx context.Context, in *ConceptSearchRequest, opts ...grpc.CallOption) (*ConceptSearchResponse, error) {
+	out := new(ConceptSearchResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Terminology/SearchConcepts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TerminologyServer is the server API for Terminology service.
+type TerminologyServer interface {
+	// SearchConcepts searches for concepts using free text, optionally constrained by an expression
+	// constraint (ECL) or reference set membership. The preferred language and dialect of returned
+	// terms are determined from the accept-language of the request.
+	SearchConcepts(context.Context, *ConceptSearchRequest) (*ConceptSearchResponse, error)
+}
+
+// UnimplementedTerminologyServer can be embedded to have forward compatible implementations.
+type UnimplementedTerminologyServer struct {
+}
+
+func (*UnimplementedTerminologyServer) SearchConcepts(context.Context, *ConceptSearchRequest) (*ConceptSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchConcepts not implemented")
+}
+
+func RegisterTerminologyServer(s *grpc.Server, srv TerminologyServer) {
+	s.RegisterService(&_Terminology_serviceDesc, srv)
+}
+
+func _Terminology_SearchConcepts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConceptSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminologyServer).SearchConcepts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Terminology/SearchConcepts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminologyServer).SearchConcepts(ctx, req.(*ConceptSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Terminology_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Terminology",
+	HandlerType: (*TerminologyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchConcepts",
+			Handler:    _Terminology_SearchConcepts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// SubscriptionsClient is the client API for Subscriptions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SubscriptionsClient interface {
+	// Subscribe streams changes to the records of the patients with the identifiers specified.
+	// Events are streamed until the client cancels the subscription.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Subscriptions_SubscribeClient, error)
+}
+
+type subscriptionsClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewDocumentServiceClient(cc grpc.ClientConnInterface) DocumentServiceClient {
-	return &documentServiceClient{cc}
+func NewSubscriptionsClient(cc grpc.ClientConnInterface) SubscriptionsClient {
+	return &subscriptionsClient{cc}
+}
+
+func (c *subscriptionsClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Subscriptions_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscriptions_serviceDesc.Streams[0], "/apiv1.Subscriptions/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscriptionsSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscriptions_SubscribeClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type subscriptionsSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscriptionsSubscribeClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SubscriptionsServer is the server API for Subscriptions service.
+type SubscriptionsServer interface {
+	// Subscribe streams changes to the records of the patients with the identifiers specified.
+	// Events are streamed until the client cancels the subscription.
+	Subscribe(*SubscribeRequest, Subscriptions_SubscribeServer) error
+}
+
+// UnimplementedSubscriptionsServer can be embedded to have forward compatible implementations.
+type UnimplementedSubscriptionsServer struct {
 }
 
-func (c *documentServiceClient) PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error) {
-	out := new(PublishDocumentResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/PublishDocument", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (*UnimplementedSubscriptionsServer) Subscribe(*SubscribeRequest, Subscriptions_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
 
-// DocumentServiceServer is the server API for DocumentService service.
-type DocumentServiceServer interface {
-	PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error)
+func RegisterSubscriptionsServer(s *grpc.Server, srv SubscriptionsServer) {
+	s.RegisterService(&_Subscriptions_serviceDesc, srv)
 }
 
-// UnimplementedDocumentServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedDocumentServiceServer struct {
+func _Subscriptions_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscriptionsServer).Subscribe(m, &subscriptionsSubscribeServer{stream})
 }
 
-func (*UnimplementedDocumentServiceServer) PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PublishDocument not implemented")
+type Subscriptions_SubscribeServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
 }
 
-func RegisterDocumentServiceServer(s *grpc.Server, srv DocumentServiceServer) {
-	s.RegisterService(&_DocumentService_serviceDesc, srv)
+type subscriptionsSubscribeServer struct {
+	grpc.ServerStream
 }
 
-func _DocumentService_PublishDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PublishDocumentRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(DocumentServiceServer).PublishDocument(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/apiv1.DocumentService/PublishDocument",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocumentServiceServer).PublishDocument(ctx, req.(*PublishDocumentRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func (x *subscriptionsSubscribeServer) Send(m *ChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-var _DocumentService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.DocumentService",
-	HandlerType: (*DocumentServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
+var _Subscriptions_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Subscriptions",
+	HandlerType: (*SubscriptionsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
 		{
-			MethodName: "PublishDocument",
-			Handler:    _DocumentService_PublishDocument_Handler,
+			StreamName:    "Subscribe",
+			Handler:       _Subscriptions_Subscribe_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// NotificationServiceClient is the client API for NotificationService service.
+// MaintenanceClient is the client API for Maintenance service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type NotificationServiceClient interface {
-	Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
+type MaintenanceClient interface {
+	// SetMaintenance sets the maintenance mode of a backend service
+	SetMaintenance(ctx context.Context, in *BackendMaintenance, opts ...grpc.CallOption) (*BackendMaintenance, error)
+	// ListMaintenance returns the backend services currently in maintenance
+	ListMaintenance(ctx context.Context, in *ListMaintenanceRequest, opts ...grpc.CallOption) (*ListMaintenanceResponse, error)
 }
 
-type notificationServiceClient struct {
+type maintenanceClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewNotificationServiceClient(cc grpc.ClientConnInterface) NotificationServiceClient {
-	return &notificationServiceClient{cc}
+func NewMaintenanceClient(cc grpc.ClientConnInterface) MaintenanceClient {
+	return &maintenanceClient{cc}
 }
 
-func (c *notificationServiceClient) Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
-	out := new(NotificationResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.NotificationService/Notify", in, out, opts...)
+func (c *maintenanceClient) SetMaintenance(ctx context.Context, in *BackendMaintenance, opts ...grpc.CallOption) (*BackendMaintenance, error) {
+	out := new(BackendMaintenance)
+	err := c.cc.Invoke(ctx, "/apiv1.Maintenance/SetMaintenance", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// NotificationServiceServer is the server API for NotificationService service.
-type NotificationServiceServer interface {
-	Notify(context.Context, *NotificationRequest) (*NotificationResponse, error)
+func (c *maintenanceClient) ListMaintenance(ctx context.Context, in *ListMaintenanceRequest, opts ...grpc.CallOption) (*ListMaintenanceResponse, error) {
+	out := new(ListMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Maintenance/ListMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedNotificationServiceServer struct {
+// MaintenanceServer is the server API for Maintenance service.
+type MaintenanceServer interface {
+	// SetMaintenance sets the maintenance mode of a backend service
+	SetMaintenance(context.Context, *BackendMaintenance) (*BackendMaintenance, error)
+	// ListMaintenance returns the backend services currently in maintenance
+	ListMaintenance(context.Context, *ListMaintenanceRequest) (*ListMaintenanceResponse, error)
 }
 
-func (*UnimplementedNotificationServiceServer) Notify(context.Context, *NotificationRequest) (*NotificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
+// UnimplementedMaintenanceServer can be embedded to have forward compatible implementations.
+type UnimplementedMaintenanceServer struct {
 }
 
-func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
-	s.RegisterService(&_NotificationService_serviceDesc, srv)
+func (*UnimplementedMaintenanceServer) SetMaintenance(context.Context, *BackendMaintenance) (*BackendMaintenance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (*UnimplementedMaintenanceServer) ListMaintenance(context.Context, *ListMaintenanceRequest) (*ListMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMaintenance not implemented")
 }
 
-func _NotificationService_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NotificationRequest)
+func RegisterMaintenanceServer(s *grpc.Server, srv MaintenanceServer) {
+	s.RegisterService(&_Maintenance_serviceDesc, srv)
+}
+
+func _Maintenance_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackendMaintenance)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotificationServiceServer).Notify(ctx, in)
+		return srv.(MaintenanceServer).SetMaintenance(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.NotificationService/Notify",
+		FullMethod: "/apiv1.Maintenance/SetMaintenance",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotificationServiceServer).Notify(ctx, req.(*NotificationRequest))
+		return srv.(MaintenanceServer).SetMaintenance(ctx, req.(*BackendMaintenance))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _NotificationService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.NotificationService",
-	HandlerType: (*NotificationServiceServer)(nil),
+func _Maintenance_ListMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaintenanceServer).ListMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Maintenance/ListMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaintenanceServer).ListMaintenance(ctx, req.(*ListMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Maintenance_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Maintenance",
+	HandlerType: (*MaintenanceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Notify",
-			Handler:    _NotificationService_Notify_Handler,
+			MethodName: "SetMaintenance",
+			Handler:    _Maintenance_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "ListMaintenance",
+			Handler:    _Maintenance_ListMaintenance_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// PractitionerDirectoryClient is the client API for PractitionerDirectory service.
+// AdminClient is the client API for Admin service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PractitionerDirectoryClient interface {
-	SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error)
+type AdminClient interface {
+	// GetConfiguration returns the effective configuration, with secrets such as passwords redacted
+	GetConfiguration(ctx context.Context, in *GetConfigurationRequest, opts ...grpc.CallOption) (*Configuration, error)
+	// ListProviders returns the registered service providers, health checks and identifier resolvers and mappers
+	ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersResponse, error)
+	// ReloadRules reloads the document routing rules from the configured rules file
+	ReloadRules(ctx context.Context, in *ReloadRulesRequest, opts ...grpc.CallOption) (*ReloadRulesResponse, error)
+	// SetLogLevel sets the level of logging for a component, or all components if none specified,
+	// returning the levels of all components that differ from the default
+	SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevels, error)
+	// ListJobs returns the scheduled jobs, such as cache warming, with the report of their most recent run
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// RunJob runs a scheduled job immediately, returning the report of the run once complete
+	RunJob(ctx context.Context, in *RunJobRequest, opts ...grpc.CallOption) (*JobRun, error)
 }
 
-type practitionerDirectoryClient struct {
+type adminClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewPractitionerDirectoryClient(cc grpc.ClientConnInterface) PractitionerDirectoryClient {
-	return &practitionerDirectoryClient{cc}
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
 }
 
-func (c *practitionerDirectoryClient) SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_PractitionerDirectory_serviceDesc.Streams[0], "/apiv1.PractitionerDirectory/SearchPractitioner", opts...)
+func (c *adminClient) GetConfiguration(ctx context.Context, in *GetConfigurationRequest, opts ...grpc.CallOption) (*Configuration, error) {
+	out := new(Configuration)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/GetConfiguration", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &practitionerDirectorySearchPractitionerClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	return out, nil
+}
+
+func (c *adminClient) ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersResponse, error) {
+	out := new(ListProvidersResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/ListProviders", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	return out, nil
+}
+
+func (c *adminClient) ReloadRules(ctx context.Context, in *ReloadRulesRequest, opts ...grpc.CallOption) (*ReloadRulesResponse, error) {
+	out := new(ReloadRulesResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/ReloadRules", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return x, nil
+	return out, nil
 }
 
-type PractitionerDirectory_SearchPractitionerClient interface {
-	Recv() (*Practitioner, error)
-	grpc.ClientStream
+func (c *adminClient) SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevels, error) {
+	out := new(LogLevels)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type practitionerDirectorySearchPractitionerClient struct {
-	grpc.ClientStream
+func (c *adminClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *practitionerDirectorySearchPractitionerClient) Recv() (*Practitioner, error) {
-	m := new(Practitioner)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func (c *adminClient) RunJob(ctx context.Context, in *RunJobRequest, opts ...grpc.CallOption) (*JobRun, error) {
+	out := new(JobRun)
+	err := c.cc.Invoke(ctx, "/apiv1.Admin/RunJob", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-// PractitionerDirectoryServer is the server API for PractitionerDirectory service.
-type PractitionerDirectoryServer interface {
-	SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	// GetConfiguration returns the effective configuration, with secrets such as passwords redacted
+	GetConfiguration(context.Context, *GetConfigurationRequest) (*Configuration, error)
+	// ListProviders returns the registered service providers, health checks and identifier resolvers and mappers
+	ListProviders(context.Context, *ListProvidersRequest) (*ListProvidersResponse, error)
+	// ReloadRules reloads the document routing rules from the configured rules file
+	ReloadRules(context.Context, *ReloadRulesRequest) (*ReloadRulesResponse, error)
+	// SetLogLevel sets the level of logging for a component, or all components if none specified,
+	// returning the levels of all components that differ from the default
+	SetLogLevel(context.Context, *LogLevel) (*LogLevels, error)
+	// ListJobs returns the scheduled jobs, such as cache warming, with the report of their most recent run
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// RunJob runs a scheduled job immediately, returning the report of the run once complete
+	RunJob(context.Context, *RunJobRequest) (*JobRun, error)
 }
 
-// UnimplementedPractitionerDirectoryServer can be embedded to have forward compatible implementations.
-type UnimplementedPractitionerDirectoryServer struct {
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
 }
 
-func (*UnimplementedPractitionerDirectoryServer) SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error {
-	return status.Errorf(codes.Unimplemented, "method SearchPractitioner not implemented")
+func (*UnimplementedAdminServer) GetConfiguration(context.Context, *GetConfigurationRequest) (*Configuration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfiguration not implemented")
+}
+func (*UnimplementedAdminServer) ListProviders(context.Context, *ListProvidersRequest) (*ListProvidersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProviders not implemented")
+}
+func (*UnimplementedAdminServer) ReloadRules(context.Context, *ReloadRulesRequest) (*ReloadRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadRules not implemented")
+}
+func (*UnimplementedAdminServer) SetLogLevel(context.Context, *LogLevel) (*LogLevels, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (*UnimplementedAdminServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (*UnimplementedAdminServer) RunJob(context.Context, *RunJobRequest) (*JobRun, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunJob not implemented")
 }
 
-func RegisterPractitionerDirectoryServer(s *grpc.Server, srv PractitionerDirectoryServer) {
-	s.RegisterService(&_PractitionerDirectory_serviceDesc, srv)
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
 }
 
-func _PractitionerDirectory_SearchPractitioner_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PractitionerSearchRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Admin_GetConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(PractitionerDirectoryServer).SearchPractitioner(m, &practitionerDirectorySearchPractitionerServer{stream})
+	if interceptor == nil {
+		return srv.(AdminServer).GetConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/GetConfiguration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetConfiguration(ctx, req.(*GetConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type PractitionerDirectory_SearchPractitionerServer interface {
-	Send(*Practitioner) error
-	grpc.ServerStream
+func _Admin_ListProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvidersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListProviders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/ListProviders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListProviders(ctx, req.(*ListProvidersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type practitionerDirectorySearchPractitionerServer struct {
-	grpc.ServerStream
+func _Admin_ReloadRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/ReloadRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadRules(ctx, req.(*ReloadRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *practitionerDirectorySearchPractitionerServer) Send(m *Practitioner) error {
-	return x.ServerStream.SendMsg(m)
+func _Admin_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetLogLevel(ctx, req.(*LogLevel))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var _PractitionerDirectory_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.PractitionerDirectory",
-	HandlerType: (*PractitionerDirectoryServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams: []grpc.StreamDesc{
+func _Admin_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RunJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RunJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Admin/RunJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RunJob(ctx, req.(*RunJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
 		{
-			StreamName:    "SearchPractitioner",
-			Handler:       _PractitionerDirectory_SearchPractitioner_Handler,
-			ServerStreams: true,
+			MethodName: "GetConfiguration",
+			Handler:    _Admin_GetConfiguration_Handler,
+		},
+		{
+			MethodName: "ListProviders",
+			Handler:    _Admin_ListProviders_Handler,
+		},
+		{
+			MethodName: "ReloadRules",
+			Handler:    _Admin_ReloadRules_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _Admin_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Admin_ListJobs_Handler,
+		},
+		{
+			MethodName: "RunJob",
+			Handler:    _Admin_RunJob_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
@@ -0,0 +1,254 @@
+// Package audit provides an audit trail of access to patient data, as required for information
+// governance. Each gRPC call is recorded with the authenticated user, the operation, the identifiers
+// accessed (in the request or in patient data returned), the outcome and the time.
+//
+// Records are written to a pluggable Sink (e.g. an append-only file, a PostgreSQL table or syslog).
+// Sinks are append-only; records are never updated or deleted by concierge.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Record is a single entry in the audit trail
+type Record struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`               // authenticated user as system|value, empty if unauthenticated
+	Operation string    `json:"operation"`          // gRPC method e.g. /apiv1.Identifiers/GetIdentifier
+	Subjects  []string  `json:"subjects,omitempty"` // identifiers accessed as system|value
+	Outcome   string    `json:"outcome"`            // gRPC status code e.g. OK, NotFound
+	Error     string    `json:"error,omitempty"`
+	Peer      string    `json:"peer,omitempty"` // network address of client
+}
+
+// Sink is a destination for audit records
+type Sink interface {
+	// Write writes a record to the audit trail
+	Write(ctx context.Context, r *Record) error
+	// Close closes any linked resources
+	Close() error
+}
+
+// Filter specifies the records to return when querying an audit trail
+type Filter struct {
+	User      string    // only records for this user (system|value), if specified
+	Subject   string    // only records that accessed this identifier (system|value), if specified
+	Operation string    // only records for this operation, if specified
+	From      time.Time // only records at or after this time, if specified
+	To        time.Time // only records before this time, if specified
+	Limit     int       // maximum number of records, 0 = no limit
+}
+
+// Match determines whether the record matches this filter
+func (f *Filter) Match(r *Record) bool {
+	if f.User != "" && r.User != f.User {
+		return false
+	}
+	if f.Operation != "" && r.Operation != f.Operation {
+		return false
+	}
+	if !f.From.IsZero() && r.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.Time.Before(f.To) {
+		return false
+	}
+	if f.Subject != "" {
+		for _, s := range r.Subjects {
+			if s == f.Subject {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Querier is a sink that also supports querying the audit trail
+type Querier interface {
+	// Query returns records matching the filter, in chronological order
+	Query(ctx context.Context, f *Filter) ([]*Record, error)
+}
+
+// NewSink creates a sink of the kind specified ("file", "postgres" or "syslog"), using addr as
+// the filename, the database connection string or the syslog address respectively
+func NewSink(kind string, addr string) (Sink, error) {
+	switch kind {
+	case "file":
+		return NewFileSink(addr)
+	case "postgres":
+		return NewPostgresSink(addr)
+	case "syslog":
+		return NewSyslogSink(addr)
+	}
+	return nil, fmt.Errorf("audit: unsupported sink '%s'. supported: file, postgres, syslog", kind)
+}
+
+// excluded are operations that are not recorded, as they cannot access patient data
+var excluded = map[string]struct{}{
+	"/grpc.health.v1.Health/Check": {},
+	"/grpc.health.v1.Health/Watch": {},
+}
+
+// Auditor records gRPC calls to a sink
+type Auditor struct {
+	sink Sink
+}
+
+// New creates an auditor that writes to the specified sink
+func New(sink Sink) *Auditor {
+	return &Auditor{sink: sink}
+}
+
+// Close closes the underlying sink
+func (a *Auditor) Close() error {
+	return a.sink.Close()
+}
+
+// UnaryServerInterceptor returns an interceptor recording unary calls.
+// This must run after authentication, so that the authenticated user is known.
+func (a *Auditor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, skip := excluded[info.FullMethod]; skip {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		subjects := newSubjects()
+		subjects.add(req)
+		if err == nil {
+			subjects.add(resp)
+		}
+		a.write(ctx, start, info.FullMethod, subjects.list, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor recording streaming calls.
+// This must run after authentication, so that the authenticated user is known.
+func (a *Auditor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, skip := excluded[info.FullMethod]; skip {
+			return handler(srv, ss)
+		}
+		start := time.Now()
+		as := &auditedStream{ServerStream: ss, subjects: newSubjects()}
+		err := handler(srv, as)
+		a.write(ss.Context(), start, info.FullMethod, as.subjects.list, err)
+		return err
+	}
+}
+
+// Rejected records a call rejected because the caller is not authenticated, or is not permitted to make the call,
+// as such calls do not reach the interceptors. Register the auditor with the server using RegisterAuditor.
+func (a *Auditor) Rejected(ctx context.Context, method string, req interface{}, err error) {
+	if _, skip := excluded[method]; skip {
+		return
+	}
+	subjects := newSubjects()
+	subjects.add(req)
+	a.write(ctx, time.Now(), method, subjects.list, err)
+}
+
+func (a *Auditor) write(ctx context.Context, t time.Time, operation string, subjects []string, err error) {
+	r := &Record{
+		Time:      t.UTC(),
+		Operation: operation,
+		Subjects:  subjects,
+		Outcome:   status.Code(err).String(),
+	}
+	if user := server.GetContextData(ctx).GetAuthenticatedUser(); user != nil {
+		r.User = user.GetSystem() + "|" + user.GetValue()
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		r.Peer = p.Addr.String()
+	}
+	if err := a.sink.Write(ctx, r); err != nil {
+		log.Printf("audit: failed to write audit record: %s: %+v", err, r)
+	}
+}
+
+// auditedStream records the subjects of messages received and sent on a stream
+type auditedStream struct {
+	grpc.ServerStream
+	subjects *subjects
+}
+
+func (as *auditedStream) RecvMsg(m interface{}) error {
+	err := as.ServerStream.RecvMsg(m)
+	if err == nil {
+		as.subjects.add(m)
+	}
+	return err
+}
+
+func (as *auditedStream) SendMsg(m interface{}) error {
+	as.subjects.add(m)
+	return as.ServerStream.SendMsg(m)
+}
+
+// subjects is a de-duplicated list of identifiers (system|value)
+type subjects struct {
+	seen map[string]struct{}
+	list []string
+}
+
+func newSubjects() *subjects {
+	return &subjects{seen: make(map[string]struct{})}
+}
+
+// identified is implemented by messages that represent or contain a single identifier
+type identified interface {
+	GetSystem() string
+	GetValue() string
+}
+
+// add records the identifiers within the message, if any
+func (s *subjects) add(m interface{}) {
+	switch v := m.(type) {
+	case *apiv1.Patient:
+		for _, id := range v.GetIdentifiers() {
+			s.addIdentifier(id)
+		}
+	case *apiv1.PublishDocumentRequest:
+		s.addIdentifier(v.GetDocument().GetId())
+		s.add(v.GetDocument().GetPatient())
+	case *apiv1.PublishDocumentResponse:
+		s.addIdentifier(v.GetId())
+		s.addIdentifier(v.GetDocumentId())
+	case *apiv1.LoginRequest:
+		s.addIdentifier(v.GetUser())
+	case *apiv1.CreateServiceAccountRequest:
+		s.addIdentifier(v.GetUser())
+	case *apiv1.RotateSecretRequest:
+		s.addIdentifier(v.GetUser())
+	case *apiv1.ServiceAccount:
+		s.addIdentifier(v.GetUser())
+	case identified:
+		s.addIdentifier(v)
+	}
+}
+
+func (s *subjects) addIdentifier(id identified) {
+	if id == nil || id.GetSystem() == "" || id.GetValue() == "" {
+		return
+	}
+	key := strings.TrimSpace(id.GetSystem()) + "|" + strings.TrimSpace(id.GetValue())
+	if _, dup := s.seen[key]; !dup {
+		s.seen[key] = struct{}{}
+		s.list = append(s.list, key)
+	}
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSubjects(t *testing.T) {
+	s := newSubjects()
+	s.add(&apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"})
+	s.add(&apiv1.Patient{Identifiers: []*apiv1.Identifier{
+		{System: identifiers.NHSNumber, Value: "1111111111"},
+		{System: identifiers.CardiffAndValeCRN, Value: "A123456"},
+	}})
+	s.add(&apiv1.Identifier{System: identifiers.CardiffAndValeCRN}) // no value, so ignored
+	expected := []string{identifiers.NHSNumber + "|1111111111", identifiers.CardiffAndValeCRN + "|A123456"}
+	if !reflect.DeepEqual(s.list, expected) {
+		t.Fatalf("expected subjects %v, got %v", expected, s.list)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	a := New(sink)
+	interceptor := a.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/apiv1.Identifiers/GetIdentifier"}
+	from := time.Now().Add(-time.Second)
+	for _, crn := range []string{"A123456", "A999998"} {
+		req := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn}
+		interceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			if crn == "A999998" {
+				return nil, status.Error(codes.NotFound, "not found")
+			}
+			return &apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}}, nil
+		})
+	}
+	records, err := sink.(Querier).Query(context.Background(), &Filter{From: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if r := records[0]; r.Outcome != "OK" || len(r.Subjects) != 2 || r.Operation != info.FullMethod {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if r := records[1]; r.Outcome != "NotFound" || len(r.Subjects) != 1 || r.Error == "" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	records, err = sink.(Querier).Query(context.Background(), &Filter{Subject: identifiers.NHSNumber + "|1111111111"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record for subject, got %d", len(records))
+	}
+	records, err = sink.(Querier).Query(context.Background(), &Filter{To: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("expected no records before %s, got %d", from, len(records))
+	}
+}
+
+func TestRejected(t *testing.T) {
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	a := New(sink)
+	req := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	a.Rejected(context.Background(), "/apiv1.PatientDirectory/GetPatient", req, status.Error(codes.PermissionDenied, "permission denied"))
+	a.Rejected(context.Background(), "/grpc.health.v1.Health/Check", nil, status.Error(codes.Unauthenticated, "unauthenticated"))
+	records, err := sink.(Querier).Query(context.Background(), &Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if r := records[0]; r.Outcome != "PermissionDenied" || r.Operation != "/apiv1.PatientDirectory/GetPatient" || len(r.Subjects) != 1 || r.Error == "" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
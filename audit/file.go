@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// fileSink writes audit records to an append-only file, as JSON with one record per line
+type fileSink struct {
+	filename string
+	mu       sync.Mutex
+	f        *os.File
+}
+
+// NewFileSink creates a sink that appends records to the named file, creating it if necessary.
+// Each record is synced to disk before the call returns.
+func NewFileSink(filename string) (Sink, error) {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to open audit file: %w", err)
+	}
+	return &fileSink{filename: filename, f: f}, nil
+}
+
+func (fs *fileSink) Write(ctx context.Context, r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, err := fs.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return fs.f.Sync()
+}
+
+func (fs *fileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.f.Close()
+}
+
+// Query returns records matching the filter, by reading the whole file
+func (fs *fileSink) Query(ctx context.Context, f *Filter) ([]*Record, error) {
+	file, err := os.Open(fs.filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	result := make([]*Record, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("audit: invalid record at line %d of %s: %w", line, fs.filename, err)
+		}
+		if f.Match(&r) {
+			result = append(result, &r)
+			if f.Limit > 0 && len(result) >= f.Limit {
+				break
+			}
+		}
+	}
+	return result, scanner.Err()
+}
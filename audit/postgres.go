@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// createTable creates the audit table, if it does not already exist.
+// For an immutable trail, the database role used by concierge should be granted only INSERT and SELECT.
+const createTable = `CREATE TABLE IF NOT EXISTS audit (
+	id bigserial PRIMARY KEY,
+	time timestamptz NOT NULL,
+	username text NOT NULL,
+	operation text NOT NULL,
+	subjects text[] NOT NULL,
+	outcome text NOT NULL,
+	error text NOT NULL,
+	peer text NOT NULL
+)`
+
+// postgresSink writes audit records to a PostgreSQL table
+type postgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink creates a sink that inserts records into the "audit" table of the PostgreSQL
+// database specified, creating the table if necessary.
+func NewPostgresSink(connStr string) (Sink, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("audit: failed to create audit table: %w", err)
+	}
+	return &postgresSink{db: db}, nil
+}
+
+func (ps *postgresSink) Write(ctx context.Context, r *Record) error {
+	// deliberately not using ctx, so that the record is written even if the client's request was cancelled
+	_, err := ps.db.Exec("INSERT INTO audit (time, username, operation, subjects, outcome, error, peer) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		r.Time, r.User, r.Operation, pq.Array(nonNil(r.Subjects)), r.Outcome, r.Error, r.Peer)
+	return err
+}
+
+func (ps *postgresSink) Close() error {
+	return ps.db.Close()
+}
+
+// Query returns records matching the filter
+func (ps *postgresSink) Query(ctx context.Context, f *Filter) ([]*Record, error) {
+	var where []string
+	var args []interface{}
+	add := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(clause, len(args)))
+	}
+	if f.User != "" {
+		add("username = $%d", f.User)
+	}
+	if f.Subject != "" {
+		add("$%d = ANY(subjects)", f.Subject)
+	}
+	if f.Operation != "" {
+		add("operation = $%d", f.Operation)
+	}
+	if !f.From.IsZero() {
+		add("time >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("time < $%d", f.To)
+	}
+	query := "SELECT time, username, operation, subjects, outcome, error, peer FROM audit"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY time, id"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*Record, 0)
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.Time, &r.User, &r.Operation, pq.Array(&r.Subjects), &r.Outcome, &r.Error, &r.Peer); err != nil {
+			return nil, err
+		}
+		r.Time = r.Time.UTC()
+		result = append(result, &r)
+	}
+	return result, rows.Err()
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
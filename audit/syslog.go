@@ -0,0 +1,57 @@
+//go:build !windows
+// +build !windows
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/url"
+)
+
+// syslogSink writes audit records to syslog, as JSON
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink creates a sink that writes records to syslog using the "auth" facility.
+// If addr is empty, the local syslog daemon is used; otherwise addr should be of the form
+// network://host:port, e.g. udp://localhost:514.
+func NewSyslogSink(addr string) (Sink, error) {
+	network, raddr, err := parseSyslogAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_AUTH, "concierge-audit")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (ss *syslogSink) Write(ctx context.Context, r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return ss.w.Info(string(b))
+}
+
+func (ss *syslogSink) Close() error {
+	return ss.w.Close()
+}
+
+// parseSyslogAddr parses an address of the form network://host:port, returning empty strings if
+// addr is empty so that the local syslog daemon is used
+func parseSyslogAddr(addr string) (network string, raddr string, err error) {
+	if addr == "" {
+		return "", "", nil
+	}
+	u, err := url.Parse(addr)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("audit: invalid syslog address '%s': expected network://host:port", addr)
+	}
+	return u.Scheme, u.Host, nil
+}
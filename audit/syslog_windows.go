@@ -0,0 +1,8 @@
+package audit
+
+import "fmt"
+
+// NewSyslogSink is not supported on windows
+func NewSyslogSink(addr string) (Sink, error) {
+	return nil, fmt.Errorf("audit: syslog is not supported on windows")
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	loginMethod   = "/apiv1.Authenticator/Login"
+	refreshMethod = "/apiv1.Authenticator/Refresh"
+	logoutMethod  = "/apiv1.Authenticator/Logout"
+)
+
+// refreshBefore is how long before expiry a token is refreshed
+const refreshBefore = 5 * time.Minute
+
+func isAuthMethod(method string) bool {
+	return method == loginMethod || method == refreshMethod || method == logoutMethod
+}
+
+// tokenSource provides an authentication token, logging in and refreshing the token as required
+type tokenSource struct {
+	client apiv1.AuthenticatorClient
+	opts   Options
+
+	mu      sync.Mutex
+	current string
+	expires time.Time
+}
+
+// token returns a valid token, logging in or refreshing the current token if required
+func (ts *tokenSource) token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := time.Now()
+	if ts.current != "" && now.Add(refreshBefore).Before(ts.expires) {
+		return ts.current, nil
+	}
+	if ts.current != "" && now.Before(ts.expires) {
+		if r, err := ts.client.Refresh(withToken(ctx, ts.current), &apiv1.TokenRefreshRequest{}); err == nil {
+			ts.set(r.GetToken())
+			return ts.current, nil
+		}
+	}
+	if err := ts.login(ctx); err != nil {
+		return "", err
+	}
+	return ts.current, nil
+}
+
+// login logs in using the service account and then, if configured, the user account
+func (ts *tokenSource) login(ctx context.Context) error {
+	if ts.opts.ServiceUser == "" {
+		return ErrTokenExpired
+	}
+	r, err := ts.client.Login(ctx, &apiv1.LoginRequest{
+		User:     &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: ts.opts.ServiceUser},
+		Password: ts.opts.ServicePassword,
+	})
+	if err != nil {
+		return err
+	}
+	if ts.opts.User != nil {
+		if r, err = ts.client.Login(withToken(ctx, r.GetToken()), &apiv1.LoginRequest{User: ts.opts.User, Password: ts.opts.Password}); err != nil {
+			return err
+		}
+	}
+	ts.set(r.GetToken())
+	return nil
+}
+
+// set sets the current token, determining its expiry from its claims.
+// The token is not verified, as it is verified by the server on each call.
+func (ts *tokenSource) set(token string) {
+	ts.current = token
+	ts.expires = time.Time{}
+	claims := &jwt.StandardClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err == nil && claims.ExpiresAt != 0 {
+		ts.expires = time.Unix(claims.ExpiresAt, 0)
+	}
+}
+
+// invalidate discards the current token, so that the next call logs in again
+func (ts *tokenSource) invalidate() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.current = ""
+}
+
+// logout revokes the current token, if any
+func (ts *tokenSource) logout(ctx context.Context) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.current == "" {
+		return
+	}
+	ts.client.Logout(withToken(ctx, ts.current), &apiv1.LogoutRequest{})
+	ts.current = ""
+}
+
+// withToken returns a context that passes the token specified as outgoing metadata
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
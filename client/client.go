@@ -0,0 +1,302 @@
+// Package client provides a Go client for concierge, so that consumers need not manage gRPC connections,
+// authentication tokens and retries themselves.
+//
+// A client logs in using a service account, and optionally a user account, when first used, and
+// refreshes its token before it expires. Calls that fail because a server is unavailable are retried
+// using exponential backoff. The gRPC clients for each service are available for calls not covered by
+// the convenience methods:
+//
+//	c, err := client.New(client.Options{Addr: "concierge:9090", ServiceUser: "cvx123", ServicePassword: secret})
+//	if err != nil { ... }
+//	defer c.Close()
+//	pt, err := c.ResolveNHSNumber(ctx, "1111111111")
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Options configures a client
+type Options struct {
+	Addr      string      // address of the concierge gRPC server, e.g. "localhost:9090"
+	Insecure  bool        // connect without TLS; for development only
+	TLSConfig *tls.Config // TLS configuration; the system roots are used if nil
+
+	ServiceUser     string // service account username, within the namespace https://concierge.eldrix.com/Id/service-user
+	ServicePassword string // service account password
+	User            *apiv1.Identifier
+	Password        string // password of user, if logging in as a user after logging in using the service account
+
+	Token    string // token from an earlier login, such as from a cache, used until it expires
+	NoLogout bool   // do not revoke the token on close, such as when it is cached for reuse
+
+	MaxRetries int           // maximum retries of calls that fail because the server is unavailable
+	BaseDelay  time.Duration // delay before first retry; doubled for each subsequent retry
+	MaxDelay   time.Duration // maximum delay between retries
+
+	DialOptions []grpc.DialOption // additional options, e.g. interceptors
+}
+
+// DefaultOptions are the default options for retries
+var DefaultOptions = Options{
+	MaxRetries: 3,
+	BaseDelay:  100 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+}
+
+// Client is a client for concierge. This is thread-safe.
+type Client struct {
+	opts   Options
+	conn   *grpc.ClientConn
+	tokens *tokenSource
+
+	Auth          apiv1.AuthenticatorClient
+	Identifiers   apiv1.IdentifiersClient
+	Patients      apiv1.PatientDirectoryClient
+	Practitioners apiv1.PractitionerDirectoryClient
+	Documents     apiv1.DocumentServiceClient
+	Clinics       apiv1.ClinicServiceClient
+	Terminology   apiv1.TerminologyClient
+	Subscriptions apiv1.SubscriptionsClient
+}
+
+// ErrNoCredentials is returned when a user login is requested without a service account
+var ErrNoCredentials = errors.New("client: user login requires service account credentials")
+
+// ErrTokenExpired is returned when a token given in the options has expired, and there are no credentials to login
+var ErrTokenExpired = errors.New("client: token expired: login required")
+
+// New creates a client connected to the server at the address specified. Connection is made lazily,
+// so that creating a client does not fail if the server is temporarily unavailable.
+func New(opts Options) (*Client, error) {
+	if opts.Addr == "" {
+		return nil, fmt.Errorf("client: no server address specified")
+	}
+	if opts.User != nil && opts.ServiceUser == "" {
+		return nil, ErrNoCredentials
+	}
+	if opts.MaxRetries == 0 && opts.BaseDelay == 0 && opts.MaxDelay == 0 {
+		opts.MaxRetries, opts.BaseDelay, opts.MaxDelay = DefaultOptions.MaxRetries, DefaultOptions.BaseDelay, DefaultOptions.MaxDelay
+	}
+	c := &Client{opts: opts}
+	dialOpts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(c.streamInterceptor),
+	}
+	if opts.Insecure {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	} else {
+		config := opts.TLSConfig
+		if config == nil {
+			config = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	}
+	conn, err := grpc.Dial(opts.Addr, append(dialOpts, opts.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.Auth = apiv1.NewAuthenticatorClient(conn)
+	c.Identifiers = apiv1.NewIdentifiersClient(conn)
+	c.Patients = apiv1.NewPatientDirectoryClient(conn)
+	c.Practitioners = apiv1.NewPractitionerDirectoryClient(conn)
+	c.Documents = apiv1.NewDocumentServiceClient(conn)
+	c.Clinics = apiv1.NewClinicServiceClient(conn)
+	c.Terminology = apiv1.NewTerminologyClient(conn)
+	c.Subscriptions = apiv1.NewSubscriptionsClient(conn)
+	if opts.ServiceUser != "" || opts.Token != "" {
+		c.tokens = &tokenSource{client: c.Auth, opts: opts}
+		if opts.Token != "" {
+			c.tokens.set(opts.Token)
+		}
+	}
+	return c, nil
+}
+
+// Conn returns the underlying connection, for use with gRPC clients of other services
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Token returns the current authentication token, logging in or refreshing the token if required,
+// or an empty string if the client has no credentials
+func (c *Client) Token(ctx context.Context) (string, error) {
+	if c.tokens == nil {
+		return "", nil
+	}
+	return c.tokens.token(ctx)
+}
+
+// Close closes the connection, logging out if logged in, unless configured not to do so
+func (c *Client) Close() error {
+	if c.tokens != nil && !c.opts.NoLogout {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		c.tokens.logout(ctx)
+		cancel()
+	}
+	return c.conn.Close()
+}
+
+// Resolve resolves an identifier, returning the resolved value, such as an apiv1.Patient
+func (c *Client) Resolve(ctx context.Context, system, value string) (proto.Message, error) {
+	any, err := c.Identifiers.GetIdentifier(ctx, &apiv1.Identifier{System: system, Value: value})
+	if err != nil {
+		return nil, err
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(any.GetTypeUrl())
+	if err != nil {
+		return nil, fmt.Errorf("client: unsupported type '%s': %w", any.GetTypeUrl(), err)
+	}
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(any.GetValue(), msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Map maps an identifier to identifiers in the target system specified
+func (c *Client) Map(ctx context.Context, id *apiv1.Identifier, target string) ([]*apiv1.Identifier, error) {
+	stream, err := c.Identifiers.MapIdentifier(ctx, &apiv1.IdentifierMapRequest{System: id.GetSystem(), Value: id.GetValue(), TargetUri: target})
+	if err != nil {
+		return nil, err
+	}
+	var result []*apiv1.Identifier
+	for {
+		id, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return result, err
+		}
+		result = append(result, id)
+	}
+}
+
+// Validate checks an identifier against the syntax rules of its system, such as the check digit of an NHS number
+func (c *Client) Validate(ctx context.Context, system, value string) (*apiv1.IdentifierValidation, error) {
+	return c.Identifiers.ValidateIdentifier(ctx, &apiv1.Identifier{System: system, Value: value})
+}
+
+// GetPatient returns the patient with the identifier specified, merging data from all backends
+func (c *Client) GetPatient(ctx context.Context, system, value string) (*apiv1.Patient, error) {
+	return c.Patients.GetPatient(ctx, &apiv1.Identifier{System: system, Value: value})
+}
+
+// ResolveNHSNumber returns the patient with the NHS number specified
+func (c *Client) ResolveNHSNumber(ctx context.Context, nnn string) (*apiv1.Patient, error) {
+	return c.GetPatient(ctx, identifiers.NHSNumber, nnn)
+}
+
+// ResolvePractitioner returns the practitioner with the NHS Wales' directory username specified
+func (c *Client) ResolvePractitioner(ctx context.Context, username string) (*apiv1.Practitioner, error) {
+	msg, err := c.Resolve(ctx, identifiers.CymruUserID, username)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := msg.(*apiv1.Practitioner); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("client: unexpected type resolving practitioner: %T", msg)
+}
+
+// PublishDocument publishes a document
+func (c *Client) PublishDocument(ctx context.Context, doc *apiv1.Document) (*apiv1.PublishDocumentResponse, error) {
+	return c.Documents.PublishDocument(ctx, &apiv1.PublishDocumentRequest{Document: doc})
+}
+
+// retryable determines whether a failed call should be retried
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// backoff returns the delay before the next attempt, using exponential backoff with "full jitter"
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.opts.BaseDelay << uint(attempt)
+	if d > c.opts.MaxDelay || d <= 0 {
+		d = c.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// wait waits before the next attempt, returning false if the context is done first
+func (c *Client) wait(ctx context.Context, attempt int) bool {
+	t := time.NewTimer(c.backoff(attempt))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// unaryInterceptor authenticates and retries unary calls
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	for attempt := 0; ; attempt++ {
+		authCtx, err := c.authenticate(ctx, method)
+		if err == nil {
+			err = invoker(authCtx, method, req, reply, cc, opts...)
+		}
+		if status.Code(err) == codes.Unauthenticated && attempt == 0 && c.tokens != nil && !isAuthMethod(method) {
+			c.tokens.invalidate() // the token may have been revoked, or the server restarted with a new key
+			continue
+		}
+		if err == nil || attempt >= c.opts.MaxRetries || !retryable(ctx, err) || !c.wait(ctx, attempt) {
+			return err
+		}
+	}
+}
+
+// streamInterceptor authenticates streaming calls, retrying if the stream cannot be established
+func (c *Client) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	for attempt := 0; ; attempt++ {
+		authCtx, err := c.authenticate(ctx, method)
+		var s grpc.ClientStream
+		if err == nil {
+			s, err = streamer(authCtx, desc, cc, method, opts...)
+		}
+		if err == nil || attempt >= c.opts.MaxRetries || !retryable(ctx, err) || !c.wait(ctx, attempt) {
+			return s, err
+		}
+	}
+}
+
+// authenticate returns a context with the current authentication token, logging in if necessary,
+// unless a token has already been specified
+func (c *Client) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if c.tokens == nil || method == loginMethod {
+		return ctx, nil
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		return ctx, nil
+	}
+	token, err := c.tokens.token(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return withToken(ctx, token), nil
+}
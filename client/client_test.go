@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/patients"
+	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/wales/empi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countingAuthProvider counts successful logins
+type countingAuthProvider struct {
+	server.AuthProvider
+	logins int32
+}
+
+func (ap *countingAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	ok, err := ap.AuthProvider.Authenticate(id, credential)
+	if ok {
+		atomic.AddInt32(&ap.logins, 1)
+	}
+	return ok, err
+}
+
+// startFakeServer starts a server with fake backends, returning its address and service account password
+func startFakeServer(t *testing.T) (string, string, *countingAuthProvider) {
+	password, hash, err := server.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := server.NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap := &countingAuthProvider{AuthProvider: server.NewSingleAuthProvider(hash)}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test", ap, true)
+	sv := server.New(server.Options{})
+	sv.RegisterAuthenticator(auth)
+	sv.Register("auth", auth)
+	d := &patients.Directory{}
+	d.Register("empi", &empi.App{Fake: true}, empi.Systems()...)
+	sv.Register("patients", d)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go sv.ServeGRPC(lis)
+	t.Cleanup(func() { lis.Close() })
+	return lis.Addr().String(), password, ap
+}
+
+func TestResolveNHSNumber(t *testing.T) {
+	addr, password, ap := startFakeServer(t)
+	c, err := New(Options{Addr: addr, Insecure: true, ServiceUser: "test", ServicePassword: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		pt, err := c.ResolveNHSNumber(ctx, "1111111111")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.GetLastname() != "Dummy" {
+			t.Fatalf("unexpected patient: %v", pt)
+		}
+	}
+	if n := atomic.LoadInt32(&ap.logins); n != 1 {
+		t.Fatalf("expected a single login, got %d", n)
+	}
+	if _, err := c.ResolveNHSNumber(ctx, "9434765919"); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected patient not to be found, got: %v", err)
+	}
+}
+
+func TestLoginAfterRevocation(t *testing.T) {
+	addr, password, ap := startFakeServer(t)
+	c, err := New(Options{Addr: addr, Insecure: true, ServiceUser: "test", ServicePassword: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.ResolveNHSNumber(ctx, "1111111111"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Auth.Logout(ctx, &apiv1.LogoutRequest{AllSessions: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ResolveNHSNumber(ctx, "1111111111"); err != nil {
+		t.Fatalf("expected client to login again after its token was revoked: %v", err)
+	}
+	if n := atomic.LoadInt32(&ap.logins); n != 2 {
+		t.Fatalf("expected two logins, got %d", n)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	addr, _, _ := startFakeServer(t)
+	c, err := New(Options{Addr: addr, Insecure: true, ServiceUser: "test", ServicePassword: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.ResolveNHSNumber(context.Background(), "1111111111"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated, got: %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	c, err := New(Options{Addr: "localhost:0", Insecure: true, MaxRetries: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var attempts int32
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = c.unaryInterceptor(ctx, "/apiv1.PatientDirectory/GetPatient", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&attempts, 1)
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestReuseToken(t *testing.T) {
+	addr, password, ap := startFakeServer(t)
+	c, err := New(Options{Addr: addr, Insecure: true, ServiceUser: "test", ServicePassword: password, NoLogout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	token, err := c.Token(ctx)
+	if err != nil || token == "" {
+		t.Fatalf("expected token: %v", err)
+	}
+	c.Close()
+	c, err = New(Options{Addr: addr, Insecure: true, Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.ResolveNHSNumber(ctx, "1111111111"); err != nil {
+		t.Fatalf("expected token to be reused after close without logout: %v", err)
+	}
+	if n := atomic.LoadInt32(&ap.logins); n != 1 {
+		t.Fatalf("expected a single login, got %d", n)
+	}
+}
@@ -0,0 +1,36 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/wardle/concierge/client"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func ExampleClient_ResolveNHSNumber() {
+	c, err := client.New(client.Options{Addr: "localhost:9090", ServiceUser: "cvx123", ServicePassword: "password"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	pt, err := c.ResolveNHSNumber(context.Background(), "1111111111")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s, %s (%s)", pt.GetLastname(), pt.GetFirstnames(), pt.GetBirthDate())
+}
+
+func ExampleClient_Resolve() {
+	c, err := client.New(client.Options{Addr: "localhost:9090", ServiceUser: "cvx123", ServicePassword: "password"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	concept, err := c.Resolve(context.Background(), identifiers.SNOMEDCT, "24700007")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(concept)
+}
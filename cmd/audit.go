@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/audit"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit trail utilities",
+}
+
+var auditQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Query the audit trail of patient data access",
+	Long: `Query the audit trail of patient data access, printing matching records as JSON, one per line.
+
+The audit trail is read from the same sink as used by 'serve' (--audit-sink and --audit-addr);
+only file and postgres sinks can be queried. For example:
+concierge audit query --audit-sink file --audit-addr audit.log --subject https://fhir.nhs.uk/Id/nhs-number|1111111111 --from 2020-04-01
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, addr := viper.GetString("audit-sink"), viper.GetString("audit-addr")
+		if cmd.Flags().Changed("audit-sink") {
+			kind, _ = cmd.Flags().GetString("audit-sink")
+		}
+		if cmd.Flags().Changed("audit-addr") {
+			addr, _ = cmd.Flags().GetString("audit-addr")
+		}
+		if kind == "" {
+			log.Fatal("cmd: you must specify an audit sink (--audit-sink)")
+		}
+		f := new(audit.Filter)
+		f.User, _ = cmd.Flags().GetString("user")
+		f.Subject, _ = cmd.Flags().GetString("subject")
+		f.Operation, _ = cmd.Flags().GetString("operation")
+		f.Limit, _ = cmd.Flags().GetInt("limit")
+		var err error
+		if f.From, err = parseAuditTime(cmd, "from"); err != nil {
+			log.Fatal(err)
+		}
+		if f.To, err = parseAuditTime(cmd, "to"); err != nil {
+			log.Fatal(err)
+		}
+		sink, err := audit.NewSink(kind, addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer sink.Close()
+		q, ok := sink.(audit.Querier)
+		if !ok {
+			log.Fatalf("cmd: audit sink '%s' does not support queries", kind)
+		}
+		records, err := q.Query(context.Background(), f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
+}
+
+// parseAuditTime parses the named flag as a date (YYYY-MM-DD) or a RFC 3339 timestamp
+func parseAuditTime(cmd *cobra.Command, name string) (time.Time, error) {
+	s, _ := cmd.Flags().GetString(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditQueryCmd)
+	auditQueryCmd.Flags().String("audit-sink", "", "Audit sink to query (file or postgres)")
+	auditQueryCmd.Flags().String("audit-addr", "", "Audit file name or database connection string")
+	auditQueryCmd.Flags().String("user", "", "Only records for this user (system|value)")
+	auditQueryCmd.Flags().String("subject", "", "Only records that accessed this identifier (system|value)")
+	auditQueryCmd.Flags().String("operation", "", "Only records for this operation (e.g. /apiv1.Identifiers/GetIdentifier)")
+	auditQueryCmd.Flags().String("from", "", "Only records at or after this date (YYYY-MM-DD) or time (RFC 3339)")
+	auditQueryCmd.Flags().String("to", "", "Only records before this date (YYYY-MM-DD) or time (RFC 3339)")
+	auditQueryCmd.Flags().Int("limit", 0, "Maximum number of records; no limit if zero")
+}
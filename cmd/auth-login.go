@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/client"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// authLoginCmd logs in to a running server, caching the token for use by other commands
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Login to a running concierge using a service account, caching the token",
+	Long: `Login to a running concierge using a service account, caching the token for use by other commands,
+such as 'call' and 'auth token', until it expires. Cached tokens are refreshed when used shortly before
+they expire.
+
+The password is the secret 'remote-password', from the secrets provider or configuration, or is read
+from the terminal if not configured. Tokens are cached in the keychain of the operating system if
+available (macOS, or Linux with libsecret), or otherwise in ~/.concierge/tokens.json, readable only by
+the current user. For example:
+concierge auth login --remote-addr concierge.example.nhs.uk:9090 --remote-user epr
+`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindRemoteFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, err := remoteOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if opts.ServiceUser == "" {
+			log.Fatal("cmd: you must specify a service account (--remote-user)")
+		}
+		if opts.ServicePassword == "" {
+			if opts.ServicePassword, err = readPassword(fmt.Sprintf("Password for %s: ", opts.ServiceUser)); err != nil {
+				log.Fatal(err)
+			}
+		}
+		c, err := client.New(opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		token, err := c.Token(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		t := cachedToken{User: opts.ServiceUser, Token: token}
+		if err := openTokenStore().save(opts.Addr, t); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "logged in to %s as '%s'; token expires %s\n", opts.Addr, opts.ServiceUser, t.expires().Format(time.RFC3339))
+	},
+}
+
+// authTokenCmd prints a valid token for a running server
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print a valid token for a running concierge, for use with other tools",
+	Long: `Print a valid token for a running concierge, for use with other tools such as curl and grpcurl,
+using the token cached by 'auth login', refreshing it if it expires soon. If the cached token has expired,
+a new token is obtained if the secret 'remote-password' is configured, and otherwise you must login again.
+For example:
+curl -H "Authorization: Bearer $(concierge auth token)" http://localhost:8080/v1/identifiers/systems
+`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindRemoteFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := remoteClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		token, err := c.Token(ctx)
+		if errors.Is(err, client.ErrTokenExpired) || (err == nil && token == "") {
+			log.Fatalf("cmd: no valid token for %s: use 'concierge auth login'", viper.GetString("remote-addr"))
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := saveToken(ctx, c); err != nil {
+			log.Printf("cmd: failed to cache token: %s", err)
+		}
+		fmt.Println(token)
+	},
+}
+
+// authLogoutCmd revokes and removes the cached token for a running server
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Logout from a running concierge, revoking and removing the cached token",
+	Args:  cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindRemoteFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		addr := viper.GetString("remote-addr")
+		store := openTokenStore()
+		cached, ok := store.load(addr)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "not logged in to %s\n", addr)
+			return
+		}
+		if cached.expires().After(time.Now()) {
+			opts, err := remoteOptions()
+			if err != nil {
+				log.Fatal(err)
+			}
+			opts.ServiceUser, opts.ServicePassword, opts.Token = "", "", cached.Token
+			c, err := client.New(opts)
+			if err != nil {
+				log.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if _, err := c.Auth.Logout(ctx, &apiv1.LogoutRequest{}); err != nil {
+				log.Printf("cmd: failed to revoke token: %s", err)
+			}
+			cancel()
+			c.Close()
+		}
+		if err := store.remove(addr); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "logged out from %s\n", addr)
+	},
+}
+
+// readPassword reads a password from the terminal, without echo
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return "", errors.New("cmd: no password: configure the secret 'remote-password' or run from a terminal")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authTokenCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	addRemoteFlags(authLoginCmd)
+	addRemoteFlags(authTokenCmd)
+	addRemoteFlags(authLogoutCmd)
+}
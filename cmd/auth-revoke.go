@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/server"
+)
+
+// authRevokeCmd revokes tokens, for incident response
+var authRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke authentication tokens",
+	Long: `Revoke authentication tokens before their expiry, for incident response.
+
+Revocations are recorded in the authentication database (--auth-db) and take effect immediately
+for all servers using that database. Specify either a single token, or a user for whom all
+tokens issued until now should be revoked. For example:
+concierge auth revoke --user https://concierge.eldrix.com/Id/service-user|my-service
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		db := viper.GetString("auth-db")
+		if cmd.Flags().Changed("auth-db") {
+			db, _ = cmd.Flags().GetString("auth-db")
+		}
+		if db == "" {
+			log.Fatal("cmd: you must specify an authentication database (--auth-db)")
+		}
+		token, _ := cmd.Flags().GetString("token")
+		user, _ := cmd.Flags().GetString("user")
+		if (token == "") == (user == "") {
+			log.Fatal("cmd: you must specify either --token or --user")
+		}
+		rl, err := server.NewDatabaseRevocationList(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if token != "" {
+			if err := server.RevokeToken(rl, token); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println("token revoked")
+			return
+		}
+		if err := rl.RevokeSubject(user, time.Now()); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("all tokens for '%s' revoked\n", user)
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authRevokeCmd)
+	authRevokeCmd.Flags().String("auth-db", "", "Auth database connection string (e.g. 'dbname=concierge sslmode=disable')")
+	authRevokeCmd.Flags().String("token", "", "Token to revoke")
+	authRevokeCmd.Flags().String("user", "", "User (system|value) for whom all tokens should be revoked")
+}
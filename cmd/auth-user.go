@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/audit"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// authUserCmd manages service accounts in the authentication database
+var authUserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage service accounts in the authentication database",
+	Long: `Manage service accounts in the authentication database (--auth-db), rather than using SQL.
+
+Passwords are generated, and are printed only when an account is created or its password rotated,
+as only their bcrypt hashes are stored. Changes are logged, and recorded in the audit trail if one
+is configured (--audit-sink and --audit-addr). Accounts can also be managed using the API of a running
+server, by an account with the scope 'auth:admin'. For example:
+concierge auth user create epr --auth-db 'dbname=concierge' --role service --expires 2021-12-31
+concierge auth user rotate epr --auth-db 'dbname=concierge'
+concierge auth user disable epr --auth-db 'dbname=concierge'
+`,
+}
+
+var authUserShowCmd = &cobra.Command{
+	Use:   "show <username>",
+	Short: "Show a service account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		um, id := openUserManager(cmd, args[0])
+		sa, err := um.User(id)
+		if err != nil {
+			log.Fatalf("cmd: %s: %s", args[0], err)
+		}
+		printServiceAccount(sa)
+	},
+}
+
+var authUserCreateCmd = &cobra.Command{
+	Use:   "create <username>",
+	Short: "Create a service account with a generated password",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		um, id := openUserManager(cmd, args[0])
+		roles, _ := cmd.Flags().GetStringSlice("role")
+		expires, err := parseAuditTime(cmd, "expires")
+		if err != nil {
+			log.Fatal(err)
+		}
+		policy := server.DefaultPolicy
+		if filename := viper.GetString("auth-policy"); filename != "" {
+			if policy, err = server.LoadPolicy(filename); err != nil {
+				log.Fatal(err)
+			}
+		}
+		sa, err := server.CreateServiceAccount(um, policy, id, roles, expires)
+		auditAccount(cmd, id, err)
+		if err != nil {
+			log.Fatalf("cmd: %s: %s", args[0], err)
+		}
+		log.Printf("cmd: service account '%s' created (roles: %v, expires: %v)", args[0], roles, expires)
+		printServiceAccount(sa)
+	},
+}
+
+var authUserDisableCmd = &cobra.Command{
+	Use:   "disable <username>",
+	Short: "Disable a service account, revoking all tokens issued to it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		um, id := openUserManager(cmd, args[0])
+		rl, err := server.NewDatabaseRevocationList(authDB(cmd))
+		if err != nil {
+			log.Fatal(err)
+		}
+		sa, err := server.DisableServiceAccount(um, rl, id)
+		auditAccount(cmd, id, err)
+		if err != nil {
+			log.Fatalf("cmd: %s: %s", args[0], err)
+		}
+		log.Printf("cmd: service account '%s' disabled", args[0])
+		printServiceAccount(sa)
+	},
+}
+
+var authUserRotateCmd = &cobra.Command{
+	Use:   "rotate <username>",
+	Short: "Replace the password of a service account with a generated password",
+	Long: `Replace the password of a service account with a generated password, and optionally its expiry.
+Tokens already issued remain valid until they expire, so that services can change to the new password
+without interruption; use 'auth revoke' to revoke them if the old password may have been compromised.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		um, id := openUserManager(cmd, args[0])
+		expires, err := parseAuditTime(cmd, "expires")
+		if err != nil {
+			log.Fatal(err)
+		}
+		sa, err := server.RotateServiceAccountSecret(um, id, expires)
+		auditAccount(cmd, id, err)
+		if err != nil {
+			log.Fatalf("cmd: %s: %s", args[0], err)
+		}
+		log.Printf("cmd: password of service account '%s' rotated", args[0])
+		printServiceAccount(sa)
+	},
+}
+
+// authDB returns the configured authentication database
+func authDB(cmd *cobra.Command) string {
+	db := viper.GetString("auth-db")
+	if cmd.Flags().Changed("auth-db") {
+		db, _ = cmd.Flags().GetString("auth-db")
+	}
+	if db == "" {
+		log.Fatal("cmd: you must specify an authentication database (--auth-db)")
+	}
+	return db
+}
+
+// openUserManager opens the authentication database, returning it and the service account with the username specified
+func openUserManager(cmd *cobra.Command, username string) (server.UserManager, *apiv1.Identifier) {
+	um, err := server.NewDatabaseUserManager(authDB(cmd))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return um, &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: username}
+}
+
+// auditAccount records a change to a service account in the audit trail, if one is configured
+func auditAccount(cmd *cobra.Command, id *apiv1.Identifier, err error) {
+	kind := viper.GetString("audit-sink")
+	if kind == "" {
+		return
+	}
+	sink, serr := audit.NewSink(kind, viper.GetString("audit-addr"))
+	if serr != nil {
+		log.Printf("cmd: failed to open audit trail: %s", serr)
+		return
+	}
+	defer sink.Close()
+	r := &audit.Record{
+		Time:      time.Now().UTC(),
+		Operation: cmd.CommandPath(),
+		Subjects:  []string{id.GetSystem() + "|" + id.GetValue()},
+		Outcome:   "OK",
+		Peer:      "local",
+	}
+	if u, uerr := user.Current(); uerr == nil {
+		r.User = "local|" + u.Username // the operating system user running the command
+	}
+	if err != nil {
+		r.Outcome, r.Error = "Error", err.Error()
+	}
+	if err := sink.Write(context.Background(), r); err != nil {
+		log.Printf("cmd: failed to write audit record: %s", err)
+	}
+}
+
+func printServiceAccount(sa *apiv1.ServiceAccount) {
+	fmt.Printf("user     : %s|%s\n", sa.GetUser().GetSystem(), sa.GetUser().GetValue())
+	fmt.Printf("roles    : %s\n", strings.Join(sa.GetRoles(), ", "))
+	fmt.Printf("disabled : %t\n", sa.GetDisabled())
+	fmt.Printf("created  : %s\n", formatTimestamp(sa.GetCreated()))
+	fmt.Printf("expires  : %s\n", formatTimestamp(sa.GetExpires()))
+	fmt.Printf("rotated  : %s\n", formatTimestamp(sa.GetRotated()))
+	if sa.GetPassword() != "" {
+		fmt.Printf("password : %s\n", sa.GetPassword())
+	}
+}
+
+// formatTimestamp formats a timestamp in local time, or as '-' if there is none
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts.CheckValid() != nil {
+		return "-"
+	}
+	return ts.AsTime().Local().Format(time.RFC3339)
+}
+
+func init() {
+	authCmd.AddCommand(authUserCmd)
+	authUserCmd.PersistentFlags().String("auth-db", "", "Auth database connection string (e.g. 'dbname=concierge sslmode=disable')")
+	authUserCmd.AddCommand(authUserShowCmd)
+	authUserCmd.AddCommand(authUserCreateCmd)
+	authUserCreateCmd.Flags().StringSlice("role", nil, "Role(s) granting the scopes permitted to the account; the default roles if none")
+	authUserCreateCmd.Flags().String("expires", "", "Date (YYYY-MM-DD) or time (RFC 3339) at which the account expires; never if empty")
+	authUserCmd.AddCommand(authUserDisableCmd)
+	authUserCmd.AddCommand(authUserRotateCmd)
+	authUserRotateCmd.Flags().String("expires", "", "New date (YYYY-MM-DD) or time (RFC 3339) at which the account expires; unchanged if empty")
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// callCmd represents the call command
+var callCmd = &cobra.Command{
+	Use:   "call <method>",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Call a method of the API of a running concierge, printing the result",
+	Long: `Call a method of the gRPC API of a running concierge, with the request given as JSON, printing
+each response as JSON. A method may be named in full (e.g. apiv1.PatientDirectory/GetPatient), or by
+its service and name, or by its name alone if no other service has a method with that name. Use --list
+to list the methods available. For methods streaming requests, give a sequence of JSON requests.
+
+The token from the last login to each server (see 'auth login') is cached, and is used until it
+expires, being refreshed when used shortly before it expires. If a service account is given (--remote-user), with its password as the secret
+'remote-password', a new token is obtained when required. For example:
+concierge call --remote-insecure GetPatient --data '{"system":"https://fhir.nhs.uk/Id/nhs-number","value":"1111111111"}'
+concierge call --remote-addr concierge.example.nhs.uk:9090 --remote-user epr PatientDirectory/SearchPatient --data @search.json
+`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindRemoteFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if list, _ := cmd.Flags().GetBool("list"); list || len(args) == 0 {
+			for _, md := range apiMethods() {
+				fmt.Println(methodPath(md)[1:] + streaming(md))
+			}
+			return
+		}
+		md, err := findMethod(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		data, _ := cmd.Flags().GetString("data")
+		reqs, err := parseRequests(md, data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		c, err := remoteClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err = invoke(ctx, c.Conn(), md, reqs, func(m proto.Message) {
+			fmt.Println(protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Format(m))
+		})
+		if err := saveToken(ctx, c); err != nil {
+			log.Printf("cmd: failed to cache token: %s", err)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// apiMethods returns the methods of the services of the API, sorted by name
+func apiMethods() []protoreflect.MethodDescriptor {
+	var result []protoreflect.MethodDescriptor
+	protoregistry.GlobalFiles.RangeFilesByPackage("apiv1", func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			methods := fd.Services().Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				result = append(result, methods.Get(j))
+			}
+		}
+		return true
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].FullName() < result[j].FullName() })
+	return result
+}
+
+// findMethod finds the method with the name specified, which may be qualified by its service and package
+func findMethod(name string) (protoreflect.MethodDescriptor, error) {
+	name = strings.ReplaceAll(strings.TrimPrefix(name, "/"), "/", ".")
+	var matches []protoreflect.MethodDescriptor
+	for _, md := range apiMethods() {
+		if full := string(md.FullName()); full == name || strings.HasSuffix(full, "."+name) {
+			matches = append(matches, md)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("cmd: no method '%s': use --list to list the methods available", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, md := range matches {
+		names[i] = methodPath(md)[1:]
+	}
+	return nil, fmt.Errorf("cmd: ambiguous method '%s': specify one of %s", name, strings.Join(names, ", "))
+}
+
+// methodPath returns the path of the method used by gRPC, e.g. /apiv1.PatientDirectory/GetPatient
+func methodPath(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+func streaming(md protoreflect.MethodDescriptor) string {
+	switch {
+	case md.IsStreamingClient() && md.IsStreamingServer():
+		return " (bidirectional streaming)"
+	case md.IsStreamingClient():
+		return " (client streaming)"
+	case md.IsStreamingServer():
+		return " (server streaming)"
+	}
+	return ""
+}
+
+// parseRequests parses the requests for the method from JSON, given directly, or read from a file (@filename)
+// or stdin (-). Methods streaming requests may be given a sequence of requests; others are given one request,
+// which is empty if no data is given.
+func parseRequests(md protoreflect.MethodDescriptor, data string) ([]proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader
+	switch {
+	case data == "-":
+		r = os.Stdin
+	case strings.HasPrefix(data, "@"):
+		f, err := os.Open(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	default:
+		r = strings.NewReader(data)
+	}
+	var result []proto.Message
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("cmd: invalid JSON for %s: %w", md.Input().FullName(), err)
+		}
+		m := mt.New().Interface()
+		if err := protojson.Unmarshal(raw, m); err != nil {
+			return nil, fmt.Errorf("cmd: invalid %s: %w", md.Input().FullName(), err)
+		}
+		result = append(result, m)
+	}
+	if len(result) == 0 {
+		result = append(result, mt.New().Interface())
+	}
+	if len(result) > 1 && !md.IsStreamingClient() {
+		return nil, fmt.Errorf("cmd: %s takes a single request, but %d given", md.Name(), len(result))
+	}
+	return result, nil
+}
+
+// invoke calls the method with the requests specified, calling f with each response
+func invoke(ctx context.Context, conn *grpc.ClientConn, md protoreflect.MethodDescriptor, reqs []proto.Message, f func(proto.Message)) error {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return err
+	}
+	if !md.IsStreamingClient() && !md.IsStreamingServer() {
+		resp := mt.New().Interface()
+		if err := conn.Invoke(ctx, methodPath(md), reqs[0], resp); err != nil {
+			return err
+		}
+		f(resp)
+		return nil
+	}
+	desc := &grpc.StreamDesc{StreamName: string(md.Name()), ClientStreams: md.IsStreamingClient(), ServerStreams: md.IsStreamingServer()}
+	stream, err := conn.NewStream(ctx, desc, methodPath(md))
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if err := stream.SendMsg(req); err != nil {
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		resp := mt.New().Interface()
+		if err := stream.RecvMsg(resp); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		f(resp)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(callCmd)
+	callCmd.Flags().String("data", "", "Request as JSON, or @filename to read from a file, or - to read from stdin")
+	callCmd.Flags().Duration("timeout", 30*time.Second, "Time permitted for the call")
+	callCmd.Flags().Bool("list", false, "List the methods available")
+	addRemoteFlags(callCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/transport"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Diagnostic checks of connections to backend services",
+}
+
+// checkTLSCmd represents the check tls command
+var checkTLSCmd = &cobra.Command{
+	Use:   "tls <endpoint>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Check the TLS connection to a backend endpoint",
+	Long: `Connect to a backend endpoint using the TLS configuration and proxy of a backend, reporting the
+negotiated TLS version and cipher suite, and the certificate chain presented by the server together
+with any verification error, such as a certificate issued by a CA that is not trusted.
+
+The endpoint may be the name of a backend with a configured URL (empi or wcrs), a URL or host:port.
+For example:
+concierge check tls empi
+concierge check tls https://cavpmswsi.cymru.nhs.uk --backend cav --cav-tls-ca nhs-wales-ca.pem
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		backend, _ := cmd.Flags().GetString("backend")
+		endpoint := args[0]
+		if u := viper.GetString(endpoint + "-url"); u != "" && !strings.Contains(endpoint, ":") {
+			backend, endpoint = endpoint, u
+		}
+		addr, serverName, err := tlsAddress(endpoint)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		var proxy *transport.Proxy
+		if backend != "" {
+			cfg, proxy = backendTLS(backend), backendProxy(backend)
+		}
+		cfg.ServerName = serverName
+		if proxy != nil {
+			fmt.Printf("connecting to %s via %s\n", addr, proxy)
+		} else {
+			fmt.Printf("connecting to %s\n", addr)
+		}
+		state, err := tlsHandshake(addr, proxy, cfg)
+		if err != nil {
+			fmt.Printf("verification failed: %s\n", err)
+			cfg.InsecureSkipVerify = true // only so that the certificate chain presented can be reported
+			if state, err = tlsHandshake(addr, proxy, cfg); err != nil {
+				log.Fatal(err)
+			}
+			printTLSState(state)
+			os.Exit(1)
+		}
+		printTLSState(state)
+		fmt.Printf("certificate chain verified for %s\n", serverName)
+	},
+}
+
+// tlsAddress returns the address and server name for the endpoint specified as a URL or host:port,
+// using port 443 if not specified
+func tlsAddress(endpoint string) (string, string, error) {
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid endpoint '%s': %w", endpoint, err)
+		}
+		host = u.Host
+	}
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = host, "443"
+	}
+	if hostname == "" {
+		return "", "", fmt.Errorf("invalid endpoint '%s': no host specified", endpoint)
+	}
+	return net.JoinHostPort(hostname, port), hostname, nil
+}
+
+// tlsHandshake connects to the address via the proxy specified, if any, returning the state of the connection
+func tlsHandshake(addr string, proxy *transport.Proxy, cfg *tls.Config) (tls.ConnectionState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn, err := proxy.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.Handshake(); err != nil {
+		return tls.ConnectionState{}, err
+	}
+	return tlsConn.ConnectionState(), nil
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+func printTLSState(state tls.ConnectionState) {
+	fmt.Printf("version: %s\ncipher suite: %s\ncertificates:\n", tlsVersionNames[state.Version], tls.CipherSuiteName(state.CipherSuite))
+	for i, cert := range state.PeerCertificates {
+		fmt.Printf("  %d: subject: %s\n     issuer:  %s\n     expires: %s (%d days)\n", i, cert.Subject, cert.Issuer, cert.NotAfter.Format(time.RFC3339), int(time.Until(cert.NotAfter).Hours()/24))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.AddCommand(checkTLSCmd)
+	checkTLSCmd.Flags().String("backend", "", "Backend whose TLS configuration and proxy should be used (empi, cav or wcrs)")
+}
@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/tenant"
+	"github.com/wardle/concierge/transport"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration utilities",
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate configuration",
+	Long: `Validate the configuration from the configuration file and environment, as used by 'serve',
+reporting unknown keys in the configuration file, such as misspelt keys that would otherwise be
+silently ignored, conflicting or missing authentication options, invalid URLs and addresses, and
+ports used by more than one server. Exits with a non-zero status if the configuration is invalid.
+For example:
+concierge config validate --config concierge.yaml
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if file := viper.ConfigFileUsed(); file != "" {
+			fmt.Printf("validating %s\n", file)
+		}
+		problems := validateConfig()
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p)
+		}
+		if invalid(problems) {
+			os.Exit(1)
+		}
+		fmt.Println("configuration valid")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+// configProblem is a problem with the configuration; the server will not start unless it is only a warning
+type configProblem struct {
+	key     string
+	message string
+	warning bool
+}
+
+func (p configProblem) String() string {
+	if p.warning {
+		return fmt.Sprintf("warning: %s: %s", p.key, p.message)
+	}
+	return fmt.Sprintf("error: %s: %s", p.key, p.message)
+}
+
+// invalid returns whether any of the problems specified is an error, rather than a warning
+func invalid(problems []configProblem) bool {
+	for _, p := range problems {
+		if !p.warning {
+			return true
+		}
+	}
+	return false
+}
+
+// configOnlyKeys are keys permitted in configuration that have no corresponding flag, such as secrets
+var configOnlyKeys = []string{"auth-secret", "doc-email-password", "doc-pkb-token", "remote-password"}
+
+// validateConfig validates the effective configuration, returning any problems
+func validateConfig() []configProblem {
+	var problems []configProblem
+	problems = append(problems, validateKeys()...)
+	problems = append(problems, validateAuth()...)
+	problems = append(problems, validateTLS()...)
+	problems = append(problems, validateAddresses()...)
+	problems = append(problems, validatePorts()...)
+	problems = append(problems, validateSpecs()...)
+	problems = append(problems, validateTenants()...)
+	return problems
+}
+
+// knownKeys returns the keys of all flags of all commands, and keys permitted only in configuration
+func knownKeys() map[string]bool {
+	result := make(map[string]bool)
+	for _, key := range configOnlyKeys {
+		result[key] = true
+	}
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		add := func(f *pflag.Flag) { result[f.Name] = true }
+		cmd.PersistentFlags().VisitAll(add)
+		cmd.Flags().VisitAll(add)
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+	visit(rootCmd)
+	if filename := viper.GetString("tenants"); filename != "" {
+		if tenants, err := tenant.LoadConfig(filename); err == nil {
+			for _, t := range tenants.List() {
+				if t.CAVPasswordSecret != "" {
+					result[t.CAVPasswordSecret] = true
+				}
+			}
+		}
+	}
+	return result
+}
+
+// validateKeys checks for unknown keys in the configuration file, which viper would otherwise silently ignore,
+// suggesting the closest known key
+func validateKeys() []configProblem {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return nil
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return []configProblem{{key: "config", message: fmt.Sprintf("couldn't read '%s': %s", file, err)}}
+	}
+	known := knownKeys()
+	var problems []configProblem
+	reported := make(map[string]bool)
+	for _, key := range v.AllKeys() {
+		key = strings.SplitN(key, ".", 2)[0] // nested keys are values of a map, such as doc-max-sensitivity
+		if known[key] || reported[key] {
+			continue
+		}
+		reported[key] = true
+		message := "unknown key in " + file
+		if suggestion := closestKey(key, known); suggestion != "" {
+			message += fmt.Sprintf(": did you mean '%s'?", suggestion)
+		}
+		problems = append(problems, configProblem{key: key, message: message})
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].key < problems[j].key })
+	return problems
+}
+
+// closestKey returns the known key most similar to the key specified, if sufficiently similar to be a likely typo
+func closestKey(key string, known map[string]bool) string {
+	best, bestDistance := "", len(key)/3+1
+	for k := range known {
+		if d := editDistance(key, k); d < bestDistance || (d == bestDistance && best != "" && k < best) {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(prev[j]+1, current[j-1]+1), prev[j-1]+cost)
+		}
+		prev = current
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// validateAuth checks that exactly one approach to authentication is configured, as required by 'serve'
+func validateAuth() []configProblem {
+	authDB, authSecret := viper.GetString("auth-db"), viper.GetString("auth-secret")
+	if viper.GetBool("no-auth") {
+		var problems []configProblem
+		for _, key := range []string{"jwt-key", "auth-db", "auth-secret", "auth-policy"} {
+			if viper.GetString(key) != "" {
+				problems = append(problems, configProblem{key: key, message: "conflicts with no-auth: remove one or the other"})
+			}
+		}
+		if len(viper.GetStringSlice("jwt-verify-keys")) > 0 {
+			problems = append(problems, configProblem{key: "jwt-verify-keys", message: "conflicts with no-auth: remove one or the other"})
+		}
+		return problems
+	}
+	var problems []configProblem
+	switch {
+	case authDB != "" && authSecret != "":
+		problems = append(problems, configProblem{key: "auth-secret", message: "ignored as auth-db is specified: remove one or the other"})
+	case authDB == "" && authSecret == "" && viper.GetString("secrets-provider") == "":
+		problems = append(problems, configProblem{key: "auth-db", message: "no authentication provider: specify auth-db or auth-secret, or no-auth explicitly"})
+	}
+	if viper.GetString("jwt-key") == "" {
+		problems = append(problems, configProblem{key: "jwt-key", message: "not specified: tokens will be signed using a temporary key and invalidated on restart", warning: true})
+	}
+	for _, filename := range viper.GetStringSlice("jwt-verify-keys") {
+		if _, err := os.Stat(filename); err != nil {
+			problems = append(problems, configProblem{key: "jwt-verify-keys", message: err.Error()})
+		}
+	}
+	for _, key := range []string{"login-max-failures", "login-max-address-failures"} {
+		if viper.GetInt(key) < 0 {
+			problems = append(problems, configProblem{key: key, message: "must not be negative"})
+		}
+	}
+	if viper.GetDuration("login-lockout") < viper.GetDuration("login-backoff") {
+		problems = append(problems, configProblem{key: "login-lockout", message: "must be at least login-backoff"})
+	}
+	return problems
+}
+
+// validateTLS checks the server certificate options are consistent
+func validateTLS() []configProblem {
+	var problems []configProblem
+	cert, key := viper.GetString("cert"), viper.GetString("key")
+	if (cert == "") != (key == "") {
+		problems = append(problems, configProblem{key: "cert", message: "cert and key must be specified together"})
+	}
+	if len(viper.GetStringSlice("acme-hosts")) > 0 && cert != "" {
+		problems = append(problems, configProblem{key: "acme-hosts", message: "conflicts with cert and key: use either ACME or certificate files"})
+	}
+	if viper.GetBool("client-cert-required") && viper.GetString("client-ca") == "" {
+		problems = append(problems, configProblem{key: "client-cert-required", message: "requires client-ca"})
+	}
+	if viper.GetString("client-ca") != "" && cert == "" && len(viper.GetStringSlice("acme-hosts")) == 0 {
+		problems = append(problems, configProblem{key: "client-ca", message: "client certificate authentication requires a server certificate: specify cert and key"})
+	}
+	return problems
+}
+
+// urlKeys are the keys of URLs of backend services, with the schemes permitted
+var urlKeys = map[string][]string{
+	"empi-url":       {"http", "https"},
+	"wcrs-url":       {"http", "https"},
+	"mesh-url":       {"https"},
+	"ods-url":        {"http", "https"},
+	"doc-pkb-url":    {"http", "https"},
+	"acme-directory": {"https"},
+	"vault-addr":     {"http", "https"},
+	"sds-addr":       {"ldap", "ldaps"},
+}
+
+// addrKeys are the keys of addresses in the form host:port
+var addrKeys = []string{"terminology-addr", "empi-update-addr", "doc-mdm-addr", "hl7-addr"}
+
+// validateAddresses checks the format of URLs and addresses of backend services and proxies
+func validateAddresses() []configProblem {
+	var problems []configProblem
+	keys := make([]string, 0, len(urlKeys))
+	for key := range urlKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := viper.GetString(key)
+		if s == "" {
+			continue
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" || !contains(urlKeys[key], u.Scheme) {
+			problems = append(problems, configProblem{key: key, message: fmt.Sprintf("invalid URL '%s': expected %s://host[:port][/path]", s, strings.Join(urlKeys[key], " or "))})
+		}
+	}
+	for _, key := range addrKeys {
+		if s := viper.GetString(key); s != "" {
+			if _, _, err := net.SplitHostPort(s); err != nil {
+				problems = append(problems, configProblem{key: key, message: fmt.Sprintf("invalid address '%s': expected host:port", s)})
+			}
+		}
+	}
+	for _, backend := range []string{"empi", "cav", "nadex", "terminology"} {
+		key := backend + "-proxy"
+		if _, err := transport.ParseProxy(viper.GetString(key), ""); err != nil {
+			problems = append(problems, configProblem{key: key, message: err.Error()})
+		}
+	}
+	return problems
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePorts checks that ports are in range, and that no port is used by more than one server
+func validatePorts() []configProblem {
+	var problems []configProblem
+	used := make(map[int]string)
+	check := func(key string, port int) {
+		if port < 0 || port > 65535 {
+			problems = append(problems, configProblem{key: key, message: fmt.Sprintf("invalid port %d", port)})
+			return
+		}
+		if port == 0 {
+			return
+		}
+		if other, clash := used[port]; clash {
+			problems = append(problems, configProblem{key: key, message: fmt.Sprintf("port %d already used by %s", port, other)})
+			return
+		}
+		used[port] = key
+	}
+	for _, key := range []string{"port-grpc", "port-http", "port-grpcweb"} {
+		check(key, viper.GetInt(key))
+	}
+	if viper.GetInt("port-grpc") == 0 {
+		problems = append(problems, configProblem{key: "port-grpc", message: "a port must be specified for the gRPC server"})
+	}
+	if _, port, err := net.SplitHostPort(viper.GetString("hl7-addr")); err == nil {
+		if n, err := strconv.Atoi(port); err == nil {
+			check("hl7-addr", n)
+		}
+	}
+	return problems
+}
+
+// validateSpecs checks values that are parsed on startup, such as sizes and route overrides
+func validateSpecs() []configProblem {
+	var problems []configProblem
+	if _, err := server.ParseSize(viper.GetString("http-max-request-size")); err != nil {
+		problems = append(problems, configProblem{key: "http-max-request-size", message: err.Error()})
+	}
+	for _, spec := range viper.GetStringSlice("http-routes") {
+		if _, err := server.ParseRoute(spec); err != nil {
+			problems = append(problems, configProblem{key: "http-routes", message: err.Error()})
+		}
+	}
+	for _, spec := range viper.GetStringSlice("maintenance") {
+		if _, err := maintenance.Parse(spec); err != nil {
+			problems = append(problems, configProblem{key: "maintenance", message: err.Error()})
+		}
+	}
+	return problems
+}
+
+// validateTenants checks the configuration of tenants, and the files to which it refers
+func validateTenants() []configProblem {
+	filename := viper.GetString("tenants")
+	if filename == "" {
+		return nil
+	}
+	tenants, err := tenant.LoadConfig(filename)
+	if err != nil {
+		return []configProblem{{key: "tenants", message: err.Error()}}
+	}
+	var problems []configProblem
+	for _, t := range tenants.List() {
+		for _, f := range []string{t.DocRules, t.RateLimits} {
+			if f == "" {
+				continue
+			}
+			if _, err := os.Stat(f); err != nil {
+				problems = append(problems, configProblem{key: "tenants", message: fmt.Sprintf("tenant '%s': %s", t.Name, err)})
+			}
+		}
+	}
+	return problems
+}
+
+// mustValidateConfig logs any problems with the configuration, exiting if the configuration is invalid
+func mustValidateConfig() {
+	problems := validateConfig()
+	for _, p := range problems {
+		log.Printf("cmd: config: %s", p)
+	}
+	if invalid(problems) {
+		log.Fatalf("cmd: invalid configuration: see 'concierge config validate'")
+	}
+}
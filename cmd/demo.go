@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/simulator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// demoCmd represents the demo command
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Run a local demonstration server, with all backend services simulated",
+	Long: `Run a local demonstration server, with all backend services simulated using an in-memory
+dataset of patients, practitioners, clinics and documents, and print example commands with which
+to experiment using curl and grpcurl.
+
+The server runs without authentication, unless a password is given, in which case a service
+account 'demo' must login with that password. Other configuration, such as ports, is as per the
+serve command. For example:
+concierge demo
+concierge demo --patients 500 --password secret --port-http 8081
+`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.Set("fake", true)
+		viper.Set("cav-environment", "fake")
+		viper.Set("cert", "")
+		viper.Set("key", "")
+		viper.Set("acme-hosts", nil)
+		viper.Set("client-ca", "")
+		viper.Set("secrets-provider", "")
+		viper.Set("auth-db", "")
+		viper.Set("doc-retry", true)
+		for _, name := range []string{"port-http", "port-grpc"} {
+			if cmd.Flags().Changed(name) {
+				port, _ := cmd.Flags().GetInt(name)
+				viper.Set(name, port)
+			}
+		}
+		if password, _ := cmd.Flags().GetString("password"); password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+			if err != nil {
+				log.Fatal(err)
+			}
+			viper.Set("no-auth", false)
+			viper.Set("auth-secret", string(hash))
+		} else {
+			viper.Set("no-auth", true)
+		}
+		if n, _ := cmd.Flags().GetInt("patients"); n > 0 {
+			seed := viper.GetInt64("simulator-seed")
+			if seed == 0 {
+				seed = 1
+			}
+			viper.Set("simulator-seed", seed)
+			viper.Set("simulator-patients", n)
+		}
+		configureSimulator()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		my := createServers()
+		password, _ := cmd.Flags().GetString("password")
+		printDemo(viper.GetInt("port-http"), viper.GetInt("port-grpc"), password)
+		my.run()
+	},
+}
+
+// demoPatients is the number of patients from the simulated dataset listed on startup
+const demoPatients = 5
+
+// printDemo prints the simulated patients and documents, and example commands to use the demonstration server
+func printDemo(httpPort, grpcPort int, password string) {
+	ds := simulator.Current()
+	base := fmt.Sprintf("http://localhost:%d", httpPort)
+	grpcAddr := fmt.Sprintf("localhost:%d", grpcPort)
+	var curlAuth, grpcAuth string
+	fmt.Printf("\nConcierge demonstration server\n  HTTP: %s (OpenAPI: %s%s)\n  gRPC: %s\n", base, base, viper.GetString("openapi-path"), grpcAddr)
+	if password == "" {
+		fmt.Printf("  Authentication: none\n")
+	} else {
+		login := fmt.Sprintf(`{"user":{"system":"%s","value":"demo"},"password":"%s"}`, identifiers.ConciergeServiceUser, password)
+		fmt.Printf("  Authentication: service account 'demo'; login to obtain a token:\n")
+		fmt.Printf("    TOKEN=$(curl -s -d '%s' %s/v1/login | sed 's/.*\"token\":\"\\([^\"]*\\)\".*/\\1/')\n", login, base)
+		curlAuth = ` -H "Authorization: Bearer $TOKEN"`
+		grpcAuth = ` -H "authorization: Bearer $TOKEN"`
+	}
+
+	fmt.Printf("\nSimulated patients (%d in total):\n", len(ds.Patients))
+	var nnn, crn string
+	for i, p := range ds.Patients {
+		if i == demoPatients {
+			fmt.Printf("  ...\n")
+			break
+		}
+		pt := p.Patient
+		ids := make([]string, 0, len(pt.GetIdentifiers()))
+		for _, id := range pt.GetIdentifiers() {
+			switch id.GetSystem() {
+			case identifiers.NHSNumber:
+				ids = append(ids, "NHS number "+id.GetValue())
+				if nnn == "" {
+					nnn = id.GetValue()
+				}
+			case identifiers.CardiffAndValeCRN:
+				ids = append(ids, "CAV CRN "+id.GetValue())
+				if crn == "" {
+					crn = id.GetValue()
+				}
+			}
+		}
+		fmt.Printf("  %s %s %s: %s\n", pt.GetTitle(), pt.GetFirstnames(), pt.GetLastname(), strings.Join(ids, ", "))
+	}
+	if len(ds.Documents) > 0 {
+		fmt.Printf("\nSimulated documents (%d in total), e.g. %s for %s|%s\n", len(ds.Documents), ds.Documents[0].ID, ds.Documents[0].Patient.GetSystem(), ds.Documents[0].Patient.GetValue())
+	}
+
+	fmt.Printf("\nExamples:\n")
+	if nnn != "" {
+		query := url.Values{"system": {identifiers.NHSNumber}, "value": {nnn}}.Encode()
+		fmt.Printf("  # fetch a patient by NHS number\n  curl%s '%s/v1/patient?%s'\n", curlAuth, base, query)
+		fmt.Printf("  grpcurl -plaintext%s -d '{\"system\":\"%s\",\"value\":\"%s\"}' %s apiv1.PatientDirectory/GetPatient\n", grpcAuth, identifiers.NHSNumber, nnn, grpcAddr)
+		fmt.Printf("  # check an identifier against the rules of its system\n  curl%s '%s/v1/identifiers/validate?%s'\n", curlAuth, base, query)
+	}
+	if crn != "" {
+		query := url.Values{"system": {identifiers.CardiffAndValeCRN}, "value": {crn}}.Encode()
+		fmt.Printf("  # fetch a patient by Cardiff and Vale case record number\n  curl%s '%s/v1/patient?%s'\n", curlAuth, base, query)
+	}
+	query := url.Values{"system": {identifiers.CompositionStatus}, "value": {"final"}, "target_uri": {identifiers.SNOMEDCT}}.Encode()
+	fmt.Printf("  # map an identifier to another system\n  curl%s '%s/v1/map?%s'\n", curlAuth, base, query)
+	if len(ds.Patients) > 0 {
+		pt := ds.Patients[0].Patient
+		query := url.Values{"lastname": {pt.GetLastname()}, "gender": {pt.GetGender().String()}}.Encode()
+		fmt.Printf("  # search for patients by name\n  curl%s '%s/v1/patient/search?%s'\n", curlAuth, base, query)
+	}
+	fmt.Printf("  # list the identifier systems supported\n  curl%s '%s/v1/identifiers/systems'\n", curlAuth, base)
+	fmt.Printf("  grpcurl -plaintext%s %s list\n\n", grpcAuth, grpcAddr)
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.Flags().Int("patients", 0, "Number of patients to generate for the simulated dataset; the built-in dataset if zero")
+	demoCmd.Flags().String("password", "", "Password for the service account 'demo'; no authentication if empty")
+	demoCmd.Flags().Int("port-http", 8080, "Port to run HTTP server")
+	demoCmd.Flags().Int("port-grpc", 9090, "Port to run gRPC server")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/doc"
+	"github.com/wardle/concierge/doc/rules"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var docCmd = &cobra.Command{
+	Use:   "doc",
+	Short: "Document publication utilities",
+}
+
+var docRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Document routing rule utilities",
+}
+
+var docRulesValidateCmd = &cobra.Command{
+	Use:   "validate <rules-file>",
+	Short: "Validate a document routing rule file (YAML or JSON) before deployment",
+	Long: `Validate a document routing rule file (YAML or JSON) before deployment.
+
+Optionally, specify one or more sample documents (JSON, as per the Document message) to
+report which rule, and which repository, would be used for each document. For example:
+concierge doc rules validate rules.yaml --document letter.json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rs, err := rules.Load(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if errs := rs.Validate([]string{doc.CAV, doc.MESH, doc.WCRS}); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d rules valid\n", args[0], len(rs.Rules))
+		docs, _ := cmd.Flags().GetStringSlice("document")
+		for _, filename := range docs {
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				log.Fatal(err)
+			}
+			d := new(apiv1.Document)
+			if err := protojson.Unmarshal(b, d); err != nil {
+				log.Fatalf("invalid document '%s': %s", filename, err)
+			}
+			if rule := rs.Match(d, nil); rule != nil {
+				fmt.Printf("%s: rule '%s' -> repository '%s'\n", filename, rule.Name, rule.Repository)
+			} else {
+				fmt.Printf("%s: no matching rule\n", filename)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docCmd)
+	docCmd.AddCommand(docRulesCmd)
+	docRulesCmd.AddCommand(docRulesValidateCmd)
+	docRulesValidateCmd.Flags().StringSlice("document", nil, "Sample document(s) (JSON) to test against the rules")
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/events"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Event notification utilities",
+}
+
+var eventsSubscribeCmd = &cobra.Command{
+	Use:   "subscribe <url>",
+	Short: "Subscribe a webhook URL to events",
+	Long: `Subscribe a webhook URL to events, generating a secret used to sign payloads.
+
+Each event is sent as a JSON payload using HTTP POST, with a Concierge-Signature header
+of the form "t=<unix time>,v1=<signature>", where the signature is the hex-encoded HMAC-SHA256
+of "<unix time>.<payload>" using the subscription secret. For example:
+concierge events subscribe https://epr.example.com/hooks/concierge --type document-published --file webhooks.json
+
+A running server reloads the subscriptions file automatically when it changes.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filename := webhooksFile(cmd)
+		names, _ := cmd.Flags().GetStringSlice("type")
+		types := make([]events.Type, 0, len(names))
+		for _, name := range names {
+			types = append(types, events.Type(name))
+		}
+		sub, err := events.NewSubscription(args[0], types)
+		if err != nil {
+			log.Fatal(err)
+		}
+		subs, err := events.LoadSubscriptions(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := events.SaveSubscriptions(filename, append(subs, sub)); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("id     : %s\n", sub.ID)
+		fmt.Printf("secret : %s\n", sub.Secret)
+	},
+}
+
+var eventsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook subscriptions",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		subs, err := events.LoadSubscriptions(webhooksFile(cmd))
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tURL\tTYPES\tCREATED")
+		for _, sub := range subs {
+			types := "*"
+			if len(sub.Types) > 0 {
+				names := make([]string, 0, len(sub.Types))
+				for _, t := range sub.Types {
+					names = append(names, string(t))
+				}
+				types = strings.Join(names, ",")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", sub.ID, sub.URL, types, sub.Created.Format("2006-01-02 15:04"))
+		}
+		w.Flush()
+	},
+}
+
+var eventsUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <id>",
+	Short: "Remove a webhook subscription",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filename := webhooksFile(cmd)
+		subs, err := events.LoadSubscriptions(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result := make([]*events.Subscription, 0, len(subs))
+		for _, sub := range subs {
+			if sub.ID != args[0] {
+				result = append(result, sub)
+			}
+		}
+		if len(result) == len(subs) {
+			log.Fatalf("no subscription with id '%s'", args[0])
+		}
+		if err := events.SaveSubscriptions(filename, result); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// webhooksFile returns the webhook subscriptions file from the command line, or from configuration
+func webhooksFile(cmd *cobra.Command) string {
+	filename, _ := cmd.Flags().GetString("file")
+	if filename == "" {
+		filename = viper.GetString("events-webhooks")
+	}
+	if filename == "" {
+		log.Fatal("no webhook subscriptions file specified")
+	}
+	return filename
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.AddCommand(eventsSubscribeCmd)
+	eventsCmd.AddCommand(eventsListCmd)
+	eventsCmd.AddCommand(eventsUnsubscribeCmd)
+	eventsCmd.PersistentFlags().String("file", "", "Webhook subscriptions file (JSON); defaults to configured 'events-webhooks'")
+	eventsSubscribeCmd.Flags().StringSlice("type", nil, "Type(s) of event to deliver (patient-updated, patient-merged, patient-deceased, document-published, delivery-failed); all if omitted")
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Eldrix Ltd and Mark Wardle (mark@wardle.org)
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/england/mesh"
+)
+
+var invokeMeshCmd = &cobra.Command{
+	Use:   "mesh",
+	Short: "Invoke tests on the NHS England MESH service, using the configured mailbox",
+}
+
+var invokeMeshInboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Authenticate and poll the inbox, optionally downloading and acknowledging messages",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		client, err := meshClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := client.Authenticate(ctx); err != nil {
+			log.Fatal(err)
+		}
+		ids, err := client.Inbox(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d message(s) in inbox for mailbox %s\n", len(ids), client.Mailbox())
+		download, _ := cmd.Flags().GetBool("download")
+		ack, _ := cmd.Flags().GetBool("ack")
+		for _, id := range ids {
+			fmt.Println(id)
+			if !download {
+				continue
+			}
+			m, err := client.Download(ctx, id)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := ioutil.WriteFile(id, m.Data, 0600); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("  from: %s workflow: %s subject: %s (%d bytes written to %s)\n", m.From, m.WorkflowID, m.Subject, len(m.Data), id)
+			if ack {
+				if err := client.Acknowledge(ctx, id); err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("  acknowledged\n")
+			}
+		}
+	},
+}
+
+// meshClient creates a MESH client using the runtime configuration
+func meshClient() (*mesh.Client, error) {
+	tlsConfig := &tls.Config{}
+	if certFile, keyFile := viper.GetString("mesh-cert"), viper.GetString("mesh-key"); certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MESH client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if caFile := viper.GetString("mesh-ca"); caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MESH CA certificate(s): %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse MESH CA certificate(s) from '%s'", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client := mesh.NewClient(viper.GetString("mesh-url"), viper.GetString("mesh-mailbox"), "", viper.GetString("mesh-shared-key"), tlsConfig)
+	client.SetPassword(secret("mesh-password"))
+	return client, nil
+}
+
+func init() {
+	invokeCmd.AddCommand(invokeMeshCmd)
+	invokeMeshCmd.AddCommand(invokeMeshInboxCmd)
+	invokeMeshInboxCmd.Flags().Bool("download", false, "Download messages into the current directory")
+	invokeMeshInboxCmd.Flags().Bool("ack", false, "Acknowledge messages once downloaded")
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wardle/concierge/apiv1"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/secrets"
 	"github.com/wardle/concierge/wales/nadex"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -36,7 +37,7 @@ var invokeNadexCmd = &cobra.Command{
 		fmt.Println("testNadex called")
 		n := nadex.App{
 			Username: args[0],
-			Password: args[1],
+			Password: secrets.Static(args[1]),
 			Fake:     false,
 		}
 		// Attempt a simple authentication
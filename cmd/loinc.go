@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/loinc"
+)
+
+var loincCmd = &cobra.Command{
+	Use:   "loinc",
+	Short: "LOINC utilities",
+}
+
+var loincImportCmd = &cobra.Command{
+	Use:   "import <Loinc.csv>",
+	Short: "Import the LOINC table from the LOINC CSV release into the LOINC store (--loinc-db)",
+	Long: `Import the LOINC table from the LOINC CSV release (LoincTable/Loinc.csv) into the LOINC store,
+so that LOINC codes can be resolved by 'concierge serve --loinc-db'. Codes already in the store are replaced.
+
+For example:
+concierge loinc import --loinc-db loinc.db Loinc_2.68/LoincTable/Loinc.csv
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filename := viper.GetString("loinc-db")
+		if filename == "" {
+			log.Fatal("cmd: missing LOINC store filename (--loinc-db)")
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		st, err := loinc.Open(filename, false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer st.Close()
+		count, err := st.Import(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: imported %d LOINC codes into %s", count, filename)
+	},
+}
+
+func init() {
+	loincCmd.AddCommand(loincImportCmd)
+	rootCmd.AddCommand(loincCmd)
+}
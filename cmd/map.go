@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// mapCmd represents the map command
+var mapCmd = &cobra.Command{
+	Use:   "map <system> <value> <target-system>",
+	Args:  cobra.ExactArgs(3),
+	Short: "Map an identifier defined by a tuple of system (uri) and value to identifiers in another system",
+	Long: `Map an identifier to identifiers in another system, as per the /v1/map endpoint,
+printing the systems used to map each result. Mappers are chained if necessary.
+
+For example, to test mapping using the terminology server (--terminology-addr):
+concierge map http://snomed.info/sct 24700007 http://read.info/readv2
+concierge map http://read.info/readv2 F20.. http://snomed.info/sct
+concierge map http://read.info/ctv3 F20.. http://read.info/readv2
+`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.Set("no-auth", true)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		createServers()
+		id := &apiv1.Identifier{System: args[0], Value: args[1]}
+		count := 0
+		err := identifiers.MapWithPath(context.Background(), id, args[2], func(result *apiv1.Identifier, path []string) error {
+			count++
+			fmt.Printf("%s|%s\t(%s)\n", result.GetSystem(), result.GetValue(), strings.Join(path, " -> "))
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if count == 0 {
+			log.Printf("no results mapping %s|%s to %s", args[0], args[1], args[2])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mapCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/client"
+)
+
+// remoteFlags are the flags configuring a connection to a running concierge server
+var remoteFlags = []string{"remote-addr", "remote-insecure", "remote-ca", "remote-user", "token-store"}
+
+// addRemoteFlags adds flags configuring a connection to a running concierge server to the command specified.
+// The password of the service account is the secret 'remote-password', from the secrets provider or configuration.
+func addRemoteFlags(cmd *cobra.Command) {
+	cmd.Flags().String("remote-addr", "localhost:9090", "Address of the gRPC server of a running concierge")
+	cmd.Flags().Bool("remote-insecure", false, "Connect without TLS; for development only")
+	cmd.Flags().String("remote-ca", "", "CA certificate(s) (PEM) to verify the server's certificate, if not issued by a public CA")
+	cmd.Flags().String("remote-user", "", "Service account with which to login; the password is the secret 'remote-password'")
+	cmd.Flags().String("token-store", "auto", "Where to cache tokens: file, keychain, or auto to use the OS keychain if available")
+}
+
+// bindRemoteFlags binds the remote flags of the command being run to configuration. Flags are bound when
+// run, rather than on initialisation, as more than one command has the same flags.
+func bindRemoteFlags(cmd *cobra.Command) {
+	for _, name := range remoteFlags {
+		viper.BindPFlag(name, cmd.Flags().Lookup(name))
+	}
+}
+
+// remoteClient returns a client for the configured running concierge server, using a cached token if
+// there is one for the server and service account
+func remoteClient() (*client.Client, error) {
+	opts, err := remoteOptions()
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := openTokenStore().load(opts.Addr); ok && (opts.ServiceUser == "" || opts.ServiceUser == cached.User) {
+		opts.Token = cached.Token
+	}
+	if opts.ServicePassword == "" {
+		opts.ServiceUser = "" // use the cached token, if any, without login
+	}
+	return client.New(opts)
+}
+
+// remoteOptions returns the options for a client of the configured running concierge server
+func remoteOptions() (client.Options, error) {
+	opts := client.Options{
+		Addr:            viper.GetString("remote-addr"),
+		Insecure:        viper.GetBool("remote-insecure"),
+		ServiceUser:     viper.GetString("remote-user"),
+		ServicePassword: secret("remote-password").Value(),
+		NoLogout:        true,
+	}
+	if filename := viper.GetString("remote-ca"); filename != "" {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return opts, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return opts, fmt.Errorf("cmd: no certificates found in '%s'", filename)
+		}
+		opts.TLSConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return opts, nil
+}
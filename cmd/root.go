@@ -18,14 +18,28 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
+	"github.com/wardle/concierge/england/mesh"
+	"github.com/wardle/concierge/england/sds"
+	"github.com/wardle/concierge/ods"
+	"github.com/wardle/concierge/secrets"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/terminology"
+	"github.com/wardle/concierge/transport"
+	"github.com/wardle/concierge/wales/nadex"
 )
 
 var cfgFile string
@@ -44,7 +58,16 @@ the local health and care ecosystem.
 	
 See https://github.com/wardle/concierge`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		warnIfHTTPProxy()
+		transport.Configure(transport.Options{
+			MaxRetries:       viper.GetInt("transport-retries"),
+			BaseDelay:        viper.GetDuration("transport-retry-delay"),
+			MaxDelay:         transport.DefaultOptions.MaxDelay,
+			FailureThreshold: viper.GetInt("transport-breaker-threshold"),
+			ResetTimeout:     viper.GetDuration("transport-breaker-reset"),
+		})
+		for _, backend := range []string{"empi", "cav", "nadex", "terminology"} {
+			transport.SetMinDeadline(backend, viper.GetDuration(backend+"-min-deadline"))
+		}
 		if logfile := viper.GetString("log"); logfile != "" {
 			f, err := os.OpenFile(logfile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
 			if err != nil {
@@ -53,9 +76,35 @@ See https://github.com/wardle/concierge`,
 			log.SetOutput(f)
 			log.SetFlags(log.LstdFlags | log.Lshortfile)
 		}
+		if viper.GetBool("fake") {
+			configureSimulator()
+		}
+		if dir := viper.GetString("replay-dir"); dir != "" {
+			transport.SetMode(transport.Replay, dir)
+			log.Printf("transport: replaying responses from backend services from %s", dir)
+		} else if dir := viper.GetString("record-dir"); dir != "" {
+			transport.SetMode(transport.Record, dir)
+			log.Printf("transport: recording responses from backend services to %s", dir)
+		}
 	},
 }
 
+// configureSimulator sets the dataset used by backend services in fake mode, loaded from fixtures or generated from a seed
+func configureSimulator() {
+	if filename := viper.GetString("simulator-data"); filename != "" {
+		ds, err := simulator.Load(filename)
+		if err != nil {
+			log.Fatalf("fatal error: couldn't load simulator data ('%s'): %s", filename, err)
+		}
+		simulator.Set(ds)
+		log.Printf("simulator: loaded %d patients from %s", len(ds.Patients), filename)
+	} else if seed := viper.GetInt64("simulator-seed"); seed != 0 {
+		ds := simulator.Generate(seed, viper.GetInt("simulator-patients"))
+		simulator.Set(ds)
+		log.Printf("simulator: generated %d patients from seed %d", len(ds.Patients), seed)
+	}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -74,8 +123,69 @@ func init() {
 	rootCmd.PersistentFlags().String("log", "", "Log file to use")
 	viper.BindPFlag("log", rootCmd.PersistentFlags().Lookup("log"))
 
-	rootCmd.PersistentFlags().Bool("fake", false, "Run with fake results")
+	rootCmd.PersistentFlags().Bool("fake", false, "Run with fake results from an in-memory simulator of backend services")
 	viper.BindPFlag("fake", rootCmd.PersistentFlags().Lookup("fake"))
+	rootCmd.PersistentFlags().String("simulator-data", "", "JSON fixtures to use in fake mode, in place of the built-in dataset")
+	viper.BindPFlag("simulator-data", rootCmd.PersistentFlags().Lookup("simulator-data"))
+	rootCmd.PersistentFlags().Int64("simulator-seed", 0, "Seed from which to generate a dataset to use in fake mode, 0=built-in dataset")
+	viper.BindPFlag("simulator-seed", rootCmd.PersistentFlags().Lookup("simulator-seed"))
+	rootCmd.PersistentFlags().Int("simulator-patients", 100, "Number of patients to generate, when using a generated dataset in fake mode")
+	viper.BindPFlag("simulator-patients", rootCmd.PersistentFlags().Lookup("simulator-patients"))
+
+	// recording and replay of responses from backend services
+	rootCmd.PersistentFlags().String("record-dir", "", "Directory to which responses from backend services are recorded, redacted of patient identifiable data")
+	viper.BindPFlag("record-dir", rootCmd.PersistentFlags().Lookup("record-dir"))
+	rootCmd.PersistentFlags().String("replay-dir", "", "Directory from which recorded responses are replayed, in place of requests to backend services")
+	viper.BindPFlag("replay-dir", rootCmd.PersistentFlags().Lookup("replay-dir"))
+
+	// resilience of outbound calls to backend services
+	rootCmd.PersistentFlags().Int("transport-retries", transport.DefaultOptions.MaxRetries, "Maximum number of retries for failed calls to backend services")
+	viper.BindPFlag("transport-retries", rootCmd.PersistentFlags().Lookup("transport-retries"))
+	rootCmd.PersistentFlags().Duration("transport-retry-delay", transport.DefaultOptions.BaseDelay, "Base delay before retrying a failed call to a backend service")
+	viper.BindPFlag("transport-retry-delay", rootCmd.PersistentFlags().Lookup("transport-retry-delay"))
+	rootCmd.PersistentFlags().Int("transport-breaker-threshold", transport.DefaultOptions.FailureThreshold, "Consecutive failures before a backend's circuit breaker opens, 0=no circuit breaker")
+	viper.BindPFlag("transport-breaker-threshold", rootCmd.PersistentFlags().Lookup("transport-breaker-threshold"))
+	rootCmd.PersistentFlags().Duration("transport-breaker-reset", transport.DefaultOptions.ResetTimeout, "Time for which an open circuit breaker rejects calls before trying again")
+	viper.BindPFlag("transport-breaker-reset", rootCmd.PersistentFlags().Lookup("transport-breaker-reset"))
+	for _, backend := range []string{"empi", "cav", "nadex", "terminology"} {
+		rootCmd.PersistentFlags().Duration(backend+"-min-deadline", 0, "Minimum time remaining before the caller's deadline for a call to "+backend+" to be attempted, failing fast otherwise; 0=always attempt")
+		viper.BindPFlag(backend+"-min-deadline", rootCmd.PersistentFlags().Lookup(backend+"-min-deadline"))
+	}
+
+	// outbound proxies for backend services; the proxy defined by the environment is used if not specified
+	rootCmd.PersistentFlags().String("no-proxy", "", "Comma-separated hosts, domains (e.g. .cymru.nhs.uk) or CIDR ranges connected to directly rather than via a configured proxy; defaults to NO_PROXY")
+	viper.BindPFlag("no-proxy", rootCmd.PersistentFlags().Lookup("no-proxy"))
+	for _, backend := range []string{"empi", "cav", "nadex", "terminology"} {
+		rootCmd.PersistentFlags().String(backend+"-proxy", "", "Proxy for connections to "+backend+" (http://, https:// or socks5://[user:password@]host:port)")
+		viper.BindPFlag(backend+"-proxy", rootCmd.PersistentFlags().Lookup(backend+"-proxy"))
+	}
+
+	// secrets provider for credentials such as cav-pms-password, nadex-password and auth-secret,
+	// which override any values from configuration
+	rootCmd.PersistentFlags().String("secrets-provider", "", "Provider of secrets: env-file, dir (e.g. mounted Kubernetes secrets) or vault")
+	viper.BindPFlag("secrets-provider", rootCmd.PersistentFlags().Lookup("secrets-provider"))
+	rootCmd.PersistentFlags().String("secrets-path", "", "Environment file, directory or Vault API path (e.g. secret/data/concierge) of secrets")
+	viper.BindPFlag("secrets-path", rootCmd.PersistentFlags().Lookup("secrets-path"))
+	rootCmd.PersistentFlags().Duration("secrets-refresh", secrets.DefaultRefresh, "Interval at which secrets are refreshed, so that rotated secrets are used without restart")
+	viper.BindPFlag("secrets-refresh", rootCmd.PersistentFlags().Lookup("secrets-refresh"))
+	rootCmd.PersistentFlags().String("vault-addr", os.Getenv("VAULT_ADDR"), "Address of Vault server (default from VAULT_ADDR)")
+	viper.BindPFlag("vault-addr", rootCmd.PersistentFlags().Lookup("vault-addr"))
+	rootCmd.PersistentFlags().String("vault-token-file", "", "File containing Vault token, re-read on each refresh (otherwise VAULT_TOKEN is used)")
+	viper.BindPFlag("vault-token-file", rootCmd.PersistentFlags().Lookup("vault-token-file"))
+	rootCmd.PersistentFlags().String("vault-namespace", os.Getenv("VAULT_NAMESPACE"), "Vault Enterprise namespace (default from VAULT_NAMESPACE)")
+	viper.BindPFlag("vault-namespace", rootCmd.PersistentFlags().Lookup("vault-namespace"))
+
+	// TLS configuration for backend services; server certificates are always verified
+	for _, backend := range []string{"empi", "cav", "wcrs", "vault", "webhook"} {
+		rootCmd.PersistentFlags().String(backend+"-tls-ca", "", "PEM bundle of CA certificates trusted for "+backend+", in addition to the system roots")
+		viper.BindPFlag(backend+"-tls-ca", rootCmd.PersistentFlags().Lookup(backend+"-tls-ca"))
+		rootCmd.PersistentFlags().String(backend+"-tls-cert", "", "PEM client certificate for "+backend+", if mutual TLS is required")
+		viper.BindPFlag(backend+"-tls-cert", rootCmd.PersistentFlags().Lookup(backend+"-tls-cert"))
+		rootCmd.PersistentFlags().String(backend+"-tls-key", "", "PEM private key of the client certificate for "+backend)
+		viper.BindPFlag(backend+"-tls-key", rootCmd.PersistentFlags().Lookup(backend+"-tls-key"))
+		rootCmd.PersistentFlags().String(backend+"-tls-min-version", "1.2", "Minimum TLS version for "+backend+" (1.2 or 1.3)")
+		viper.BindPFlag(backend+"-tls-min-version", rootCmd.PersistentFlags().Lookup(backend+"-tls-min-version"))
+	}
 
 	// empi configuration
 	rootCmd.PersistentFlags().String("empi-url", "", "URL for EMPI endpoint")
@@ -84,24 +194,108 @@ func init() {
 	viper.BindPFlag("empi-processing-id", rootCmd.PersistentFlags().Lookup("empi-processing-id"))
 	rootCmd.PersistentFlags().Int("empi-timeout-seconds", 2, "Timeout for calls to EMPI backend server endpoint(s)")
 	viper.BindPFlag("empi-timeout-seconds", rootCmd.PersistentFlags().Lookup("empi-timeout-seconds"))
+	rootCmd.PersistentFlags().Int("empi-max-idle-conns", 16, "Maximum idle (keep-alive) connections retained to the EMPI endpoint")
+	viper.BindPFlag("empi-max-idle-conns", rootCmd.PersistentFlags().Lookup("empi-max-idle-conns"))
+	rootCmd.PersistentFlags().Duration("empi-idle-conn-timeout", 90*time.Second, "Time after which an idle connection to the EMPI endpoint is closed")
+	viper.BindPFlag("empi-idle-conn-timeout", rootCmd.PersistentFlags().Lookup("empi-idle-conn-timeout"))
+	rootCmd.PersistentFlags().Bool("dedupe-requests", true, "Share the result of concurrent identical patient lookups (EMPI and CAV PMS) rather than each making an upstream call")
+	viper.BindPFlag("dedupe-requests", rootCmd.PersistentFlags().Lookup("dedupe-requests"))
 	rootCmd.PersistentFlags().Int("empi-cache-minutes", 5, "EMPI cache expiration in minutes, 0=no cache")
 	viper.BindPFlag("empi-cache-minutes", rootCmd.PersistentFlags().Lookup("empi-cache-minutes"))
+	rootCmd.PersistentFlags().String("empi-cache-backend", "memory", "EMPI cache backend (memory or redis)")
+	viper.BindPFlag("empi-cache-backend", rootCmd.PersistentFlags().Lookup("empi-cache-backend"))
+	rootCmd.PersistentFlags().String("empi-cache-addr", "localhost:6379", "Address of redis server, if using redis EMPI cache backend")
+	viper.BindPFlag("empi-cache-addr", rootCmd.PersistentFlags().Lookup("empi-cache-addr"))
+	rootCmd.PersistentFlags().String("empi-update-addr", "", "MLLP address of the EMPI update interface, for demographic updates (ADT^A08)")
+	viper.BindPFlag("empi-update-addr", rootCmd.PersistentFlags().Lookup("empi-update-addr"))
 
 	// cav configuration
 	rootCmd.PersistentFlags().String("cav-pms-username", "", "Username for CAV PMS")
 	viper.BindPFlag("cav-pms-username", rootCmd.PersistentFlags().Lookup("cav-pms-username"))
 	rootCmd.PersistentFlags().String("cav-pms-password", "", "Password for CAV PMS")
 	viper.BindPFlag("cav-pms-password", rootCmd.PersistentFlags().Lookup("cav-pms-password"))
+	rootCmd.PersistentFlags().Duration("cav-timeout", 10*time.Second, "Maximum time permitted for each call to CAV PMS")
+	viper.BindPFlag("cav-timeout", rootCmd.PersistentFlags().Lookup("cav-timeout"))
 
 	// nadex configuration
 	rootCmd.PersistentFlags().String("nadex-username", "", "Username for directory lookups")
 	viper.BindPFlag("nadex-username", rootCmd.PersistentFlags().Lookup("nadex-username"))
 	rootCmd.PersistentFlags().String("nadex-password", "", "Password for directory lookups")
 	viper.BindPFlag("nadex-password", rootCmd.PersistentFlags().Lookup("nadex-password"))
+	rootCmd.PersistentFlags().Int("nadex-max-results", nadex.DefaultMaxResults, "Maximum number of results from a practitioner search")
+	viper.BindPFlag("nadex-max-results", rootCmd.PersistentFlags().Lookup("nadex-max-results"))
+	rootCmd.PersistentFlags().Int("nadex-pool-size", nadex.DefaultPoolSize, "Maximum number of connections to the directory")
+	viper.BindPFlag("nadex-pool-size", rootCmd.PersistentFlags().Lookup("nadex-pool-size"))
+	rootCmd.PersistentFlags().Duration("nadex-idle-timeout", nadex.DefaultIdleTimeout, "Time after which an idle connection to the directory is closed")
+	viper.BindPFlag("nadex-idle-timeout", rootCmd.PersistentFlags().Lookup("nadex-idle-timeout"))
+
+	// NHS England SDS configuration
+	rootCmd.PersistentFlags().String("sds-addr", sds.DefaultAddr, "LDAP URL for NHS England Spine Directory Service")
+	viper.BindPFlag("sds-addr", rootCmd.PersistentFlags().Lookup("sds-addr"))
+	rootCmd.PersistentFlags().Int("sds-max-results", sds.DefaultMaxResults, "Maximum number of results from an SDS practitioner search")
+	viper.BindPFlag("sds-max-results", rootCmd.PersistentFlags().Lookup("sds-max-results"))
+
+	// NHS England PDS configuration
+	rootCmd.PersistentFlags().String("pds-env", "", "NHS England PDS FHIR API environment (sandbox, int or prod); PDS not used if empty")
+	viper.BindPFlag("pds-env", rootCmd.PersistentFlags().Lookup("pds-env"))
+	rootCmd.PersistentFlags().String("pds-api-key", "", "API key for NHS England PDS FHIR API")
+	viper.BindPFlag("pds-api-key", rootCmd.PersistentFlags().Lookup("pds-api-key"))
+	rootCmd.PersistentFlags().String("pds-key-id", "", "Identifier of the public key registered for the NHS England PDS FHIR API")
+	viper.BindPFlag("pds-key-id", rootCmd.PersistentFlags().Lookup("pds-key-id"))
+	rootCmd.PersistentFlags().String("pds-private-key", "", "Filename of PEM encoded private key used to authenticate with the NHS England PDS FHIR API")
+	viper.BindPFlag("pds-private-key", rootCmd.PersistentFlags().Lookup("pds-private-key"))
+
+	// NHS Digital ODS configuration
+	rootCmd.PersistentFlags().String("ods-url", ods.DefaultURL, "URL for NHS Digital ODS ORD API")
+	viper.BindPFlag("ods-url", rootCmd.PersistentFlags().Lookup("ods-url"))
+	rootCmd.PersistentFlags().Duration("ods-refresh", ods.DefaultRefreshInterval, "Interval at which cached organisations are refreshed from ODS")
+	viper.BindPFlag("ods-refresh", rootCmd.PersistentFlags().Lookup("ods-refresh"))
+
+	// NHS England MESH configuration
+	rootCmd.PersistentFlags().String("mesh-url", "https://msg.intspineservices.nhs.uk", "URL for NHS England MESH service")
+	viper.BindPFlag("mesh-url", rootCmd.PersistentFlags().Lookup("mesh-url"))
+	rootCmd.PersistentFlags().String("mesh-mailbox", "", "MESH mailbox identifier; documents will not be sent via MESH if empty")
+	viper.BindPFlag("mesh-mailbox", rootCmd.PersistentFlags().Lookup("mesh-mailbox"))
+	rootCmd.PersistentFlags().String("mesh-password", "", "MESH mailbox password")
+	viper.BindPFlag("mesh-password", rootCmd.PersistentFlags().Lookup("mesh-password"))
+	rootCmd.PersistentFlags().String("mesh-shared-key", "", "MESH environment shared key, used for authentication")
+	viper.BindPFlag("mesh-shared-key", rootCmd.PersistentFlags().Lookup("mesh-shared-key"))
+	rootCmd.PersistentFlags().String("mesh-cert", "", "MESH client certificate file")
+	viper.BindPFlag("mesh-cert", rootCmd.PersistentFlags().Lookup("mesh-cert"))
+	rootCmd.PersistentFlags().String("mesh-key", "", "MESH client certificate key file")
+	viper.BindPFlag("mesh-key", rootCmd.PersistentFlags().Lookup("mesh-key"))
+	rootCmd.PersistentFlags().String("mesh-ca", "", "MESH certificate authority file(s)")
+	viper.BindPFlag("mesh-ca", rootCmd.PersistentFlags().Lookup("mesh-ca"))
+	rootCmd.PersistentFlags().String("mesh-workflow-id", "", "MESH workflow identifier to use when sending documents to general practices")
+	viper.BindPFlag("mesh-workflow-id", rootCmd.PersistentFlags().Lookup("mesh-workflow-id"))
+	rootCmd.PersistentFlags().String("mesh-gp-workflow-id", mesh.GPConnectWorkflowID, "MESH workflow identifier to use when sending copies of documents to general practices")
+	viper.BindPFlag("mesh-gp-workflow-id", rootCmd.PersistentFlags().Lookup("mesh-gp-workflow-id"))
+	rootCmd.PersistentFlags().String("mesh-mailboxes", "", "MESH mailbox overrides for general practices (e.g. A81001=X26HC001,...)")
+	viper.BindPFlag("mesh-mailboxes", rootCmd.PersistentFlags().Lookup("mesh-mailboxes"))
+
+	// Welsh Care Records Service configuration
+	rootCmd.PersistentFlags().String("wcrs-url", "", "URL for WCRS document repository; documents will not be stored in WCRS if empty")
+	viper.BindPFlag("wcrs-url", rootCmd.PersistentFlags().Lookup("wcrs-url"))
+	rootCmd.PersistentFlags().String("wcrs-username", "", "Username for WCRS")
+	viper.BindPFlag("wcrs-username", rootCmd.PersistentFlags().Lookup("wcrs-username"))
+	rootCmd.PersistentFlags().String("wcrs-password", "", "Password for WCRS")
+	viper.BindPFlag("wcrs-password", rootCmd.PersistentFlags().Lookup("wcrs-password"))
+	rootCmd.PersistentFlags().String("wcrs-organisation", "", "ODS code of the organisation submitting documents to WCRS")
+	viper.BindPFlag("wcrs-organisation", rootCmd.PersistentFlags().Lookup("wcrs-organisation"))
 
 	// SNOMED terminology server integration
 	rootCmd.PersistentFlags().String("terminology-addr", "", "gRPC address of terminology server (e.g. localhost:8081")
 	viper.BindPFlag("terminology-addr", rootCmd.PersistentFlags().Lookup("terminology-addr"))
+	rootCmd.PersistentFlags().Int("terminology-cache-size", terminology.DefaultCacheSize, "Maximum number of SNOMED CT concepts to cache, 0=no cache")
+	viper.BindPFlag("terminology-cache-size", rootCmd.PersistentFlags().Lookup("terminology-cache-size"))
+	rootCmd.PersistentFlags().Duration("terminology-cache-ttl", terminology.DefaultCacheTTL, "Time for which cached SNOMED CT concepts are retained, 0=no expiry")
+	viper.BindPFlag("terminology-cache-ttl", rootCmd.PersistentFlags().Lookup("terminology-cache-ttl"))
+	rootCmd.PersistentFlags().Duration("terminology-timeout", terminology.DefaultTimeout, "Maximum time permitted for each call to the terminology server")
+	viper.BindPFlag("terminology-timeout", rootCmd.PersistentFlags().Lookup("terminology-timeout"))
+
+	// LOINC
+	rootCmd.PersistentFlags().String("loinc-db", "", "Filename of LOINC store, created using 'concierge loinc import'")
+	viper.BindPFlag("loinc-db", rootCmd.PersistentFlags().Lookup("loinc-db"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -127,19 +321,116 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
 
-// Log some important configuration variables which can cause live service failings.
-// Directly use an environmental variable lookup, rather than viper, as that looks for upper case versions of the requested variable
-func warnIfHTTPProxy() {
-	httpProxy, exists := os.LookupEnv("http_proxy") // give warning if proxy set, to help debug connection errors in live
-	if exists {
-		log.Printf("warning: http proxy set to %s\n", httpProxy)
+// stringMap returns the configuration value for the specified key as a map, supporting either a map
+// (e.g. from a configuration file) or a comma-separated list of key=value pairs (e.g. from a flag).
+func stringMap(key string) map[string]string {
+	if s, ok := viper.Get(key).(string); ok {
+		result := make(map[string]string)
+		for _, kv := range strings.Split(s, ",") {
+			if kv = strings.TrimSpace(kv); kv == "" {
+				continue
+			}
+			if i := strings.Index(kv, "="); i > 0 {
+				result[strings.TrimSpace(kv[:i])] = strings.TrimSpace(kv[i+1:])
+			} else {
+				log.Printf("warning: invalid key=value pair for '%s': '%s'", key, kv)
+			}
+		}
+		return result
+	}
+	return viper.GetStringMapString(key)
+}
+
+// secret returns the named credential, such as "cav-pms-password", from the configured secrets provider,
+// falling back to the value from configuration if there is no provider or the provider has no such secret
+func secret(name string) secrets.Secret {
+	secretStoreOnce.Do(func() {
+		secretStore = openSecrets()
+	})
+	return secretStore.Secret(name, viper.GetString(name))
+}
+
+var secretStore *secrets.Store
+var secretStoreOnce sync.Once
+
+// openSecrets opens the configured secrets provider, returning nil if none is configured
+func openSecrets() *secrets.Store {
+	var provider secrets.Provider
+	path := viper.GetString("secrets-path")
+	switch p := viper.GetString("secrets-provider"); p {
+	case "":
+		return nil
+	case "env-file":
+		provider = secrets.EnvFile(path)
+	case "dir":
+		provider = secrets.Dir(path)
+	case "vault":
+		provider = &secrets.Vault{
+			Addr:      viper.GetString("vault-addr"),
+			Path:      path,
+			Token:     os.Getenv("VAULT_TOKEN"),
+			TokenFile: viper.GetString("vault-token-file"),
+			Namespace: viper.GetString("vault-namespace"),
+			Client: &http.Client{
+				Timeout:   30 * time.Second,
+				Transport: transport.NewHTTPTransport(transport.HTTPOptions{TLSConfig: backendTLS("vault")}),
+			},
+		}
+	default:
+		log.Fatalf("invalid secrets provider '%s': expected env-file, dir or vault", p)
+	}
+	if path == "" {
+		log.Fatalf("no path specified for secrets provider: use --secrets-path")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	st, err := secrets.NewStore(ctx, provider, viper.GetDuration("secrets-refresh"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("cmd: using %s secrets provider ('%s')", viper.GetString("secrets-provider"), path)
+	return st
+}
+
+// backendTLS returns the TLS configuration for the named backend
+func backendTLS(backend string) *tls.Config {
+	cfg, err := backendTLSOptions(backend).TLSConfig()
+	if err != nil {
+		log.Fatalf("invalid TLS configuration for %s: %s", backend, err)
+	}
+	return cfg
+}
+
+// backendTLSOptions returns the TLS options configured for the named backend
+func backendTLSOptions(backend string) transport.TLSOptions {
+	return transport.TLSOptions{
+		CAFile:     viper.GetString(backend + "-tls-ca"),
+		CertFile:   viper.GetString(backend + "-tls-cert"),
+		KeyFile:    viper.GetString(backend + "-tls-key"),
+		MinVersion: viper.GetString(backend + "-tls-min-version"),
+	}
+}
+
+// backendProxy returns the outbound proxy configured for the named backend, or nil if the proxy defined by
+// the environment, if any, should be used. Hosts excluded from proxying default to those in NO_PROXY.
+func backendProxy(backend string) *transport.Proxy {
+	noProxy := viper.GetString("no-proxy")
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+	}
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	p, err := transport.ParseProxy(viper.GetString(backend+"-proxy"), noProxy)
+	if err != nil {
+		log.Fatalf("invalid proxy for %s: %s", backend, err)
 	}
-	httpsProxy, exists := os.LookupEnv("https_proxy")
-	if exists {
-		log.Printf("warning: https proxy set to %s\n", httpsProxy)
+	if p != nil {
+		log.Printf("cmd: using proxy %s for %s (no proxy: '%s')", p, backend, noProxy)
 	}
+	return p
 }
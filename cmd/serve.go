@@ -1,18 +1,47 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
+	"net/http"
 	"time"
 
-	"github.com/patrickmn/go-cache"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/wardle/concierge/admin"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/audit"
+	"github.com/wardle/concierge/doc"
+	"github.com/wardle/concierge/doc/cda"
+	"github.com/wardle/concierge/doc/channels"
+	"github.com/wardle/concierge/doc/render"
+	"github.com/wardle/concierge/doc/rules"
+	"github.com/wardle/concierge/doc/validation"
+	"github.com/wardle/concierge/england/mesh"
+	"github.com/wardle/concierge/england/pds"
+	"github.com/wardle/concierge/england/sds"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/fhir/rest"
+	"github.com/wardle/concierge/hl7v2"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/identifiers/mappings"
+	"github.com/wardle/concierge/loinc"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/ods"
+	"github.com/wardle/concierge/patients"
+	"github.com/wardle/concierge/practitioners"
+	"github.com/wardle/concierge/ratelimit"
+	"github.com/wardle/concierge/scheduler"
 	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/subscriptions"
+	"github.com/wardle/concierge/tenant"
 	"github.com/wardle/concierge/terminology"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
 	"github.com/wardle/concierge/wales/cav"
 	"github.com/wardle/concierge/wales/empi"
 	"github.com/wardle/concierge/wales/nadex"
+	"github.com/wardle/concierge/wales/wcrs"
 )
 
 // serveCmd represents the serve command
@@ -22,14 +51,8 @@ var serveCmd = &cobra.Command{
 	Long:  `Starts a server (gRPC and REST)`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Printf("========== starting concierge v%s ==========", rootCmd.Version)
-		my := createServers()
-
-		// start server
-		log.Printf("cmd: starting server: rpc-port:%d http-port:%d", my.sv.Options.RPCPort, my.sv.Options.RESTPort)
-		if err := my.sv.RunServer(); err != nil {
-			log.Fatal(err)
-		}
-		my.sv.Close()
+		mustValidateConfig()
+		createServers().run()
 	},
 }
 
@@ -38,57 +61,496 @@ type myServer struct {
 	// services
 	identifiers *identifiers.Server // an identifier service
 	nadex       *nadex.App
+	sds         *sds.App
 	empi        *empi.App
+	pds         *pds.App
+	ods         *ods.App
+	hl7         *hl7v2.Server
 	cav         *cav.PMSService
 	term        *terminology.Terminology
+	loinc       *loinc.Store
+	docs        *doc.DocumentService
+	patients    *patients.Directory
+	jobs        *scheduler.Scheduler
+	practs      *practitioners.Directory
+	audit       *audit.Auditor
+	tenants     *tenant.Config // tenants with their own configuration of backend services; may be nil
 }
 
 // createServers creates a gRPC/HTTP server and plugs-in modular providers based on runtime configuration
 func createServers() *myServer {
 	sv := server.New(server.Options{
-		RESTPort: viper.GetInt("port-http"),
-		RPCPort:  viper.GetInt("port-grpc"),
-		CertFile: viper.GetString("cert"),
-		KeyFile:  viper.GetString("key"),
+		RESTPort:    viper.GetInt("port-http"),
+		RPCPort:     viper.GetInt("port-grpc"),
+		GRPCWebPort: viper.GetInt("port-grpcweb"),
+		MetricsPath: viper.GetString("metrics-path"),
+		OpenAPIPath: viper.GetString("openapi-path"),
+		Reflection:  viper.GetBool("grpc-reflection"),
+		Version:     rootCmd.Version,
+		CertFile:    viper.GetString("cert"),
+		KeyFile:     viper.GetString("key"),
+
+		LivenessPath:     viper.GetString("liveness-path"),
+		ReadinessPath:    viper.GetString("readiness-path"),
+		CriticalServices: viper.GetStringSlice("readiness-services"),
+		DrainTimeout:     viper.GetDuration("drain-timeout"),
+
+		CertReloadInterval: viper.GetDuration("cert-reload-interval"),
+		ACMEHosts:          viper.GetStringSlice("acme-hosts"),
+		ACMEEmail:          viper.GetString("acme-email"),
+		ACMECacheDir:       viper.GetString("acme-cache-dir"),
+		ACMEDirectoryURL:   viper.GetString("acme-directory"),
+
+		ClientCAFile:       viper.GetString("client-ca"),
+		ClientCertRequired: viper.GetBool("client-cert-required"),
+
+		CORSOrigins: viper.GetStringSlice("cors-origins"),
+		CORSHeaders: viper.GetStringSlice("cors-headers"),
+
+		ReadTimeout:     viper.GetDuration("http-read-timeout"),
+		WriteTimeout:    viper.GetDuration("http-write-timeout"),
+		MaxRequestBytes: size("http-max-request-size"),
+		Routes:          routes("http-routes"),
 	})
 	my := &myServer{
 		sv: sv,
 	}
+	admin.FilterLog()
+	// distributed tracing
+	if exporter := viper.GetString("tracing-exporter"); exporter != "" {
+		if err := tracing.Start(tracing.Options{
+			Exporter:    exporter,
+			Addr:        viper.GetString("tracing-addr"),
+			Insecure:    viper.GetBool("tracing-insecure"),
+			ServiceName: "concierge",
+			SampleRatio: viper.GetFloat64("tracing-sample-ratio"),
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// audit trail of access to patient data
+	if kind := viper.GetString("audit-sink"); kind != "" {
+		sink, err := audit.NewSink(kind, viper.GetString("audit-addr"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: recording audit trail using %s", kind)
+		my.audit = audit.New(sink)
+		my.sv.RegisterInterceptor(my.audit.UnaryServerInterceptor(), my.audit.StreamServerInterceptor())
+		my.sv.RegisterAuditor(my.audit)
+	}
+
+	// tenants, with the tenant of each authenticated account available to interceptors and providers
+	if filename := viper.GetString("tenants"); filename != "" {
+		var err error
+		if my.tenants, err = tenant.LoadConfig(filename); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: using %d tenants from %s", len(my.tenants.Tenants), filename)
+		my.sv.RegisterInterceptor(my.tenants.UnaryServerInterceptor(), my.tenants.StreamServerInterceptor())
+	}
+
+	// rate limiting of each user or service account
+	if limiter := rateLimiter(my.tenants); limiter != nil {
+		my.sv.RegisterInterceptor(limiter.UnaryServerInterceptor(), limiter.StreamServerInterceptor())
+	}
+
 	// generic servers: these are high-level and distinct from underlying implementations
 	my.identifiers = &identifiers.Server{}
 	my.sv.Register("identifier", my.identifiers)
+	identifiers.SetMaxHops(viper.GetInt("identifiers-max-hops"))
+	if db := viper.GetString("identifiers-db"); db != "" {
+		store, err := mappings.NewDatabaseStore(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: using postgresql for identifier mappings")
+		my.sv.Register("identifier-admin", mappings.NewServer(store))
+	} else if viper.GetBool("fake") {
+		my.sv.Register("identifier-admin", mappings.NewServer(mappings.NewMemoryStore()))
+	}
+	my.sv.Register("fhir", &rest.Server{}) // FHIR R4 REST facade
+	my.sv.Register("maintenance", &maintenance.Server{})
+	for _, spec := range viper.GetStringSlice("maintenance") {
+		m, err := maintenance.Parse(spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		maintenance.Set(m.GetBackend(), m.GetMode(), m.GetReason())
+	}
+	my.sv.SetDegradedFunc(func(service string) string { // backends in planned maintenance do not make the server unready
+		m := maintenance.Get(service)
+		if m == nil {
+			return ""
+		}
+		if m.GetReason() == "" {
+			return fmt.Sprintf("in maintenance: %s", m.GetMode())
+		}
+		return fmt.Sprintf("in maintenance: %s (%s)", m.GetMode(), m.GetReason())
+	})
 
 	// specific servers: these provide an abstraction over a specific back-end service.
 	// in the future, these endpoints will be deprecated in favour of complete abstraction,
 	// but we will still need to support identifier resolution and mapping using this mechanism
 	my.nadex = nadexServer()
-	my.sv.Register("nadex", my.nadex)
+	my.sv.RegisterHealthCheck("nadex", my.nadex.Check)
 	identifiers.RegisterResolver(identifiers.CymruUserID, my.nadex.ResolvePractitioner)
 
+	// NHS England Spine Directory Service
+	my.sds = &sds.App{
+		Addr:       viper.GetString("sds-addr"),
+		MaxResults: viper.GetInt("sds-max-results"),
+		Fake:       viper.GetBool("fake"),
+	}
+	my.sv.RegisterHealthCheck("sds", my.sds.Check)
+	identifiers.RegisterResolver(identifiers.SDSUserID, my.sds.ResolvePractitioner)
+
+	// practitioner directory, routing to the directory for each identifier system
+	my.practs = &practitioners.Directory{}
+	my.practs.Register("nadex", my.nadex, identifiers.CymruUserID)
+	my.practs.Register("sds", my.sds, identifiers.SDSUserID)
+	my.sv.Register("practitioners", my.practs)
+
 	my.empi = walesEmpiServer()
-	//my.empi.Register("wales-empi", ep) 		-- temporarily unnecessary as can use identifier lookup instead
-	identifiers.RegisterResolver(identifiers.NHSNumber, my.empi.ResolveIdentifier)
+	for _, t := range my.tenantList() {
+		if t.EMPIProcessingID != "" {
+			if my.empi.TenantIDs == nil {
+				my.empi.TenantIDs = make(map[string]string)
+			}
+			my.empi.TenantIDs[t.Name] = t.EMPIProcessingID
+			log.Printf("cmd: using EMPI processing ID '%s' for tenant '%s'", t.EMPIProcessingID, t.Name)
+		}
+	}
+	my.sv.RegisterHealthCheck("wales-empi", my.empi.HealthCheck)
+	if env := viper.GetString("pds-env"); env != "" || viper.GetBool("fake") {
+		var err error
+		if my.pds, err = pds.New(env, viper.GetString("pds-api-key"), viper.GetString("pds-key-id"), viper.GetString("pds-private-key"), viper.GetBool("fake")); err != nil {
+			log.Fatal(err)
+		}
+		// NHS England's PDS is used for patients not found in the NHS Wales' EMPI
+		identifiers.RegisterResolver(identifiers.NHSNumber, identifiers.Fallback(my.empi.ResolveIdentifier, my.pds.ResolveIdentifier))
+	} else {
+		identifiers.RegisterResolver(identifiers.NHSNumber, my.empi.ResolveIdentifier)
+	}
 	identifiers.RegisterResolver(identifiers.AneurinBevanCRN, my.empi.ResolveIdentifier)
 	identifiers.RegisterResolver(identifiers.CwmTafCRN, my.empi.ResolveIdentifier)
 	identifiers.RegisterResolver(identifiers.SwanseaBayCRN, my.empi.ResolveIdentifier)
 
 	// Cardiff and Vale PMS
-	my.cav = cav.NewPMSService(viper.GetString("cav-pms-username"), viper.GetString("cav-pms-password"), 10*time.Second, viper.GetBool("fake"))
+	cav.SetProxy(backendProxy("cav"))
+	cav.SetTLSConfig(backendTLS("cav"))
+	cavEnv := viper.GetString("cav-environment")
+	my.cav = cav.NewPMSService(viper.GetString("cav-pms-username"), secret("cav-pms-password").Value(), viper.GetDuration("cav-timeout"), viper.GetBool("fake") || cavEnv == "fake")
+	my.cav.SetPassword(secret("cav-pms-password"))
+	for _, t := range my.tenantList() {
+		if t.CAVUsername != "" {
+			my.cav.SetTenantCredentials(t.Name, t.CAVUsername, secret(t.CAVPasswordSecret))
+		}
+	}
+	if filename := viper.GetString("cav-environments"); filename != "" && cavEnv != "fake" {
+		envs, err := cav.LoadEnvironments(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		env, ok := envs[cavEnv]
+		if !ok {
+			log.Fatalf("cmd: CAV PMS environment '%s' not found in '%s'", cavEnv, filename)
+		}
+		log.Printf("cmd: using CAV PMS environment '%s' (%s)", cavEnv, env.URL)
+		my.cav.SetEnvironment(env)
+	} else if cavEnv != "live" && cavEnv != "fake" {
+		log.Fatalf("cmd: CAV PMS environment '%s' requires an environments file", cavEnv)
+	}
+	if backend := viper.GetString("cav-token-backend"); backend != "" {
+		store, err := cav.NewTokenStore(backend, viper.GetString("cav-token-addr"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.cav.SetTokenStore(store)
+	}
+	if filename := viper.GetString("cav-content-types"); filename != "" {
+		types, err := cav.LoadContentTypes(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.cav.SetContentTypes(types)
+		log.Printf("cmd: using CAV content types from '%s'", filename)
+	}
+	if viper.GetBool("dedupe-requests") {
+		my.cav.SetDedupe(transport.NewGroup("cav"))
+	}
 	identifiers.RegisterResolver(identifiers.CardiffAndValeCRN, my.cav.ResolveIdentifier)
+	identifiers.RegisterResolver(identifiers.GMPNumber, my.cav.ResolveGeneralPractitioner)
+	identifiers.RegisterResolver(identifiers.CardiffAndValeClinicCode, my.cav.ResolveClinic)
+	my.sv.Register("cav", my.cav)
+
+	// NHS Digital Organisation Data Service
+	my.ods = ods.New(viper.GetString("ods-url"), viper.GetDuration("ods-refresh"), viper.GetBool("fake"))
+	identifiers.RegisterResolver(identifiers.ODSCode, my.ods.ResolveIdentifier)
+	identifiers.RegisterResolver(identifiers.ODSSiteCode, my.ods.ResolveIdentifier)
+
+	// patient directory, merging patient data from all back-ends
+	my.patients = &patients.Directory{}
+	my.patients.Register("empi", my.empi, empi.Systems()...)
+	my.patients.Register("cav", my.cav, identifiers.CardiffAndValeCRN)
+	my.patients.SetPrefetchWorkers(viper.GetInt("patients-prefetch-workers"))
+	if db := viper.GetString("patients-links-db"); db != "" {
+		store, err := patients.NewDatabaseLinkStore(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: using postgresql for patient link history")
+		my.patients.SetLinkStore(store)
+	} else {
+		my.patients.SetLinkStore(patients.NewMemoryLinkStore())
+	}
+	my.sv.Register("patients", my.patients)
+
+	// scheduled jobs, such as warming caches ahead of clinics
+	my.jobs = scheduler.New()
+	if codes := viper.GetStringSlice("warm-clinics"); len(codes) > 0 {
+		clinics := make([]*apiv1.Identifier, 0, len(codes))
+		for _, code := range codes {
+			clinics = append(clinics, &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: code})
+		}
+		if err := my.jobs.Add(scheduler.Job{
+			Name:     "warm-clinics",
+			Schedule: viper.GetString("warm-clinics-schedule"),
+			Run:      my.patients.WarmClinics(my.cav, clinics),
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// subscriptions to changes in patient records
+	my.sv.Register("subscriptions", subscriptions.New(my.patients, subscriptions.Options{
+		JournalSize:     viper.GetInt("subscriptions-journal-size"),
+		RefreshInterval: viper.GetDuration("subscriptions-refresh"),
+	}))
+
+	// document publication
+	my.docs = doc.NewDocumentService(my.cav, my.empi)
+	my.docs.SetParallelism(viper.GetInt("doc-parallelism"))
+	my.docs.SetAllowUnverifiedNHSNumber(viper.GetBool("doc-allow-unverified-nhs-number"))
+	my.sv.Register("document", my.docs)
+	if viper.GetString("mesh-mailbox") != "" {
+		client, err := meshClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.docs.RegisterRepository(doc.MESH, mesh.NewRepository(client, viper.GetString("mesh-workflow-id"), stringMap("mesh-mailboxes")))
+		if viper.GetBool("doc-send-to-gp") {
+			my.docs.RegisterGPSender(mesh.NewRepository(client, viper.GetString("mesh-gp-workflow-id"), stringMap("mesh-mailboxes")))
+		}
+	} else if viper.GetBool("doc-send-to-gp") {
+		log.Fatal("cmd: sending documents to general practices requires MESH: specify a MESH mailbox")
+	}
+	if url := viper.GetString("wcrs-url"); url != "" {
+		repo := wcrs.NewRepository(url, viper.GetString("wcrs-username"), viper.GetString("wcrs-password"), viper.GetString("wcrs-organisation"))
+		repo.SetPassword(secret("wcrs-password"))
+		repo.SetTLSConfig(backendTLS("wcrs"))
+		my.docs.RegisterRepository(doc.WCRS, repo)
+	}
+	if filename := viper.GetString("doc-rules"); filename != "" {
+		rs, err := rules.Load(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := my.docs.SetRules(rs); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: using document routing rules from '%s'", filename)
+	}
+	if _, err := my.loadTenantRules(); err != nil {
+		log.Fatal(err)
+	}
+	adminOpts := admin.Options{Server: my.sv, Settings: settings, Scheduler: my.jobs}
+	if filename := viper.GetString("doc-rules"); filename != "" || len(my.tenantList()) > 0 {
+		adminOpts.ReloadRules = func() (int, error) {
+			n := 0
+			if filename != "" {
+				rs, err := rules.Load(filename)
+				if err != nil {
+					return 0, err
+				}
+				if err := my.docs.SetRules(rs); err != nil {
+					return 0, err
+				}
+				n = len(rs.Rules)
+			}
+			count, err := my.loadTenantRules()
+			return n + count, err
+		}
+	}
+	my.sv.Register("admin", admin.New(adminOpts))
+	if err := my.docs.SetDeceasedPolicy(doc.DeceasedPolicy(viper.GetString("doc-deceased-policy")), viper.GetString("doc-deceased-repository")); err != nil {
+		log.Fatal(err)
+	}
+	if err := my.docs.SetMatchThreshold(viper.GetFloat64("doc-match-threshold")); err != nil {
+		log.Fatal(err)
+	}
+	if custodian := viper.GetString("doc-cda-custodian"); custodian != "" {
+		my.docs.SetCDAOptions(cda.Options{
+			Custodian:     &apiv1.Identifier{System: identifiers.ODSCode, Value: custodian},
+			CustodianName: viper.GetString("doc-cda-custodian-name"),
+		})
+	}
+	switch renderer := viper.GetString("doc-renderer"); renderer {
+	case "":
+	case "text":
+		my.docs.SetRenderer(render.Text{})
+	case "command":
+		r, err := render.NewCommand(viper.GetString("doc-renderer-command"), viper.GetStringSlice("doc-renderer-content-types"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.docs.SetRenderer(r)
+	default:
+		log.Fatalf("cmd: unknown document renderer: '%s'. available: text, command", renderer)
+	}
+	my.docs.SetPDFRepositories(viper.GetStringSlice("doc-pdf-repositories"))
+	v := validation.New(validation.Options{
+		MaxSize:     viper.GetInt64("doc-max-size"),
+		ValidatePDF: viper.GetBool("doc-validate-pdf"),
+		RequirePDFA: viper.GetBool("doc-require-pdfa"),
+	})
+	if addr := viper.GetString("doc-clamd"); addr != "" {
+		v.RegisterScanner("clamd", validation.NewClamdScanner(addr, viper.GetDuration("doc-clamd-timeout")))
+	}
+	my.docs.SetValidator(v)
+	if viper.GetBool("doc-retry") {
+		q := doc.NewMemoryQueue()
+		if db := viper.GetString("doc-queue-db"); db != "" {
+			var err error
+			if q, err = doc.NewDatabaseQueue(db); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("cmd: using postgresql for document retry queue")
+		} else {
+			log.Printf("cmd: warning: using in-memory document retry queue; queued documents will be lost on restart")
+		}
+		my.docs.SetRetryQueue(q, doc.RetryOptions{MaxAttempts: viper.GetInt("doc-retry-max-attempts"), Interval: viper.GetDuration("doc-retry-interval")})
+		my.docs.SetDrainTimeout(viper.GetDuration("drain-timeout"))
+	}
+	if m := stringMap("doc-max-sensitivity"); len(m) > 0 {
+		policy, err := doc.ParseSensitivityPolicy(m)
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.docs.RegisterConsentChecker("sensitivity", policy)
+	}
+	if viper.GetBool("doc-detect-duplicates") {
+		rs := doc.NewMemoryReceiptStore()
+		if db := viper.GetString("doc-receipts-db"); db != "" {
+			var err error
+			if rs, err = doc.NewDatabaseReceiptStore(db); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("cmd: using postgresql for document receipts")
+		} else {
+			log.Printf("cmd: warning: using in-memory document receipts; re-submitted documents will not be detected after restart")
+		}
+		my.docs.SetReceiptStore(rs)
+	}
+	if addr := viper.GetString("doc-email-smtp"); addr != "" {
+		c, err := channels.NewEmail(channels.EmailOptions{
+			Addr:     addr,
+			Username: viper.GetString("doc-email-username"),
+			Password: secret("doc-email-password").Value(),
+			From:     viper.GetString("doc-email-from"),
+			Subject:  viper.GetString("doc-email-subject"),
+			Link:     viper.GetString("doc-email-link"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.docs.RegisterPatientChannel("email", c)
+	}
+	if url := viper.GetString("doc-pkb-url"); url != "" {
+		c, err := channels.NewPKB(channels.PKBOptions{URL: url, Token: secret("doc-pkb-token").Value()})
+		if err != nil {
+			log.Fatal(err)
+		}
+		my.docs.RegisterPatientChannel("pkb", c)
+	}
+	if addr := viper.GetString("doc-mdm-addr"); addr != "" {
+		my.docs.RegisterNotifier("mdm", hl7v2.NewMDMNotifier(addr, hl7v2.MDMOptions{
+			ReceivingApplication: viper.GetString("doc-mdm-receiving-app"),
+			ReceivingFacility:    viper.GetString("doc-mdm-receiving-facility"),
+			Authority:            empi.AuthorityForSystem,
+		}))
+	}
+
+	// event publication
+	if broker := viper.GetString("events-broker"); broker != "" {
+		p, err := events.NewPublisher(broker, viper.GetString("events-addr"), viper.GetString("events-topic"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		events.Register(broker, p)
+	}
+	if filename := viper.GetString("events-webhooks"); filename != "" {
+		client := &http.Client{Transport: transport.NewHTTPTransport(transport.HTTPOptions{TLSConfig: backendTLS("webhook")})}
+		p, err := events.NewWebhookPublisher(filename, client)
+		if err != nil {
+			log.Fatal(err)
+		}
+		events.Register("webhook", p)
+	}
+
+	// inbound HL7 v2 ADT feed
+	if addr := viper.GetString("hl7-addr"); addr != "" {
+		my.hl7 = hl7v2.NewServer(func(authority string) string {
+			if system := empi.SystemForAuthority(authority); system != "" {
+				return system
+			}
+			return hl7v2.DefaultSystem(authority)
+		})
+		if err := my.hl7.ListenAndServe(addr); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// terminology server
 	if addr := viper.GetString("terminology-addr"); addr != "" {
 		var err error
-		my.term, err = terminology.NewTerminology(addr)
+		my.term, err = terminology.NewTerminology(addr, backendProxy("terminology"))
 		if err != nil {
 			log.Fatal(err)
 		}
+		my.term.EnableCache(viper.GetInt("terminology-cache-size"), viper.GetDuration("terminology-cache-ttl"))
+		my.term.SetTimeout(viper.GetDuration("terminology-timeout"))
+		log.Printf("terminology configuration: cache:%d (ttl %s) endpoint:%s", viper.GetInt("terminology-cache-size"), viper.GetDuration("terminology-cache-ttl"), addr)
 		identifiers.RegisterResolver(identifiers.SNOMEDCT, my.term.Resolve)
 		identifiers.RegisterMapper(identifiers.ReadV2, identifiers.SNOMEDCT, my.term.ReadV2toSNOMEDCT)
 		identifiers.RegisterMapper(identifiers.SNOMEDCT, identifiers.ReadV2, my.term.SNOMEDCTtoReadV2)
+		identifiers.RegisterMapper(identifiers.ReadV3, identifiers.SNOMEDCT, my.term.CTV3toSNOMEDCT)
+		identifiers.RegisterMapper(identifiers.SNOMEDCT, identifiers.ReadV3, my.term.SNOMEDCTtoCTV3)
+		identifiers.RegisterResolver(identifiers.ReadV2, my.term.ResolveReadCode)
+		identifiers.RegisterResolver(identifiers.ReadV3, my.term.ResolveReadCode)
+		my.sv.Register("terminology", my.term)
+		if ecl := viper.GetString("doc-type-constraint"); ecl != "" {
+			validator, err := my.term.DocumentTypeValidator(ecl)
+			if err != nil {
+				log.Fatal(err)
+			}
+			my.docs.SetTypeValidator(validator)
+		}
+	} else if viper.GetString("doc-type-constraint") != "" {
+		log.Fatalf("cmd: validation of document types (--doc-type-constraint) requires a terminology server (--terminology-addr)")
 	} else {
 		log.Printf("warning: running without terminology server")
 	}
+	// LOINC
+	if filename := viper.GetString("loinc-db"); filename != "" {
+		var err error
+		my.loinc, err = loinc.Open(filename, true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		identifiers.RegisterResolver(identifiers.LOINC, my.loinc.Resolve)
+	}
 	// authentication
 	var auth *server.Auth
 	if viper.GetBool("no-auth") {
@@ -105,7 +567,26 @@ func createServers() *myServer {
 		if err != nil {
 			log.Fatalf("cmd: failed to start authentication server: %s", err)
 		}
+		for _, filename := range viper.GetStringSlice("jwt-verify-keys") {
+			if err := auth.AddVerificationKey(filename); err != nil {
+				log.Fatal(err)
+			}
+		}
 		my.sv.RegisterAuthenticator(auth)
+		auth.SetLoginLimits(server.LoginLimits{
+			MaxFailures:        viper.GetInt("login-max-failures"),
+			MaxAddressFailures: viper.GetInt("login-max-address-failures"),
+			Backoff:            viper.GetDuration("login-backoff"),
+			Lockout:            viper.GetDuration("login-lockout"),
+		})
+		if filename := viper.GetString("auth-policy"); filename != "" {
+			policy, err := server.LoadPolicy(filename)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("cmd: using access control policy from '%s'", filename)
+			auth.SetPolicy(policy)
+		}
 		if db := viper.GetString("auth-db"); db != "" {
 			ap, err := server.NewDatabaseAuthProvider(db)
 			if err != nil {
@@ -113,23 +594,149 @@ func createServers() *myServer {
 			}
 			log.Printf("cmd: using postgresql ('%s') for service user authentication", db)
 			auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "postgresql", ap, true)
-		} else if hash := viper.GetString("auth-secret"); hash != "" {
+			rl, err := server.NewDatabaseRevocationList(db)
+			if err != nil {
+				log.Fatal(err)
+			}
+			auth.SetRevocationList(rl)
+		} else if hash := secret("auth-secret"); hash.Value() != "" {
 			log.Printf("cmd: using explicitly defined single secret for service user authentication")
-			auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "single", server.NewSingleAuthProvider(hash), true)
+			auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "single", server.NewSecretAuthProvider(hash), true)
 		} else {
 			log.Fatalf("cmd: you must specify a authentication provider (--auth-db or --auth-secret) or specify --no-auth explicitly")
 		}
 		auth.RegisterAuthProvider(identifiers.CymruUserID, "nadex", my.nadex, false)
+		my.practs.SetAccountGuard(auth)
 		my.sv.Register("auth", auth)
 	}
 	return my
 }
 
+// run starts the server, blocking until it is shut down, and then closes the services
+func (my *myServer) run() {
+	log.Printf("cmd: starting server: rpc-port:%d http-port:%d grpcweb-port:%d", my.sv.Options.RPCPort, my.sv.Options.RESTPort, my.sv.Options.GRPCWebPort)
+	my.jobs.Start()
+	if err := my.sv.RunServer(); err != nil {
+		log.Fatal(err)
+	}
+	my.jobs.Close()
+	my.sv.Close()
+	if my.audit != nil {
+		my.audit.Close()
+	}
+	my.ods.Close()
+	if my.loinc != nil {
+		my.loinc.Close()
+	}
+	if my.hl7 != nil {
+		my.hl7.Close()
+	}
+	events.Close()
+	tracing.Stop()
+}
+
+// settings returns the effective configuration
+func settings() map[string]string {
+	result := make(map[string]string)
+	for _, key := range viper.AllKeys() {
+		result[key] = fmt.Sprint(viper.Get(key))
+	}
+	return result
+}
+
+// tenantList returns the configured tenants, if any, sorted by name
+func (my *myServer) tenantList() []*tenant.Tenant {
+	if my.tenants == nil {
+		return nil
+	}
+	return my.tenants.List()
+}
+
+// loadTenantRules loads the document routing rules for each tenant with its own rules, returning the
+// total number of rules loaded
+func (my *myServer) loadTenantRules() (int, error) {
+	n := 0
+	for _, t := range my.tenantList() {
+		if t.DocRules == "" {
+			continue
+		}
+		rs, err := rules.Load(t.DocRules)
+		if err != nil {
+			return 0, err
+		}
+		if err := my.docs.SetTenantRules(t.Name, rs); err != nil {
+			return 0, err
+		}
+		log.Printf("cmd: using document routing rules from '%s' for tenant '%s'", t.DocRules, t.Name)
+		n += len(rs.Rules)
+	}
+	return n, nil
+}
+
+// rateLimiter returns a limiter using the configured rate limits, and those of each tenant, or nil if there are none
+func rateLimiter(tenants *tenant.Config) *ratelimit.Limiter {
+	config := &ratelimit.Config{}
+	configured := false
+	if filename := viper.GetString("rate-limits"); filename != "" {
+		var err error
+		if config, err = ratelimit.LoadConfig(filename); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("cmd: rate limiting using %s", filename)
+		configured = true
+	}
+	limiter := ratelimit.New(config)
+	if tenants != nil {
+		for _, t := range tenants.List() {
+			if t.RateLimits == "" {
+				continue
+			}
+			c, err := ratelimit.LoadConfig(t.RateLimits)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("cmd: rate limiting tenant '%s' using %s", t.Name, t.RateLimits)
+			limiter.SetTenantConfig(t.Name, c)
+			configured = true
+		}
+	}
+	if !configured {
+		return nil
+	}
+	return limiter
+}
+
+// size returns the configured size in bytes, e.g. "4MB"
+func size(key string) int64 {
+	n, err := server.ParseSize(viper.GetString(key))
+	if err != nil {
+		log.Fatalf("cmd: invalid %s: %s", key, err)
+	}
+	return n
+}
+
+// routes returns the configured overrides of timeouts and request sizes for HTTP routes
+func routes(key string) []server.Route {
+	var result []server.Route
+	for _, spec := range viper.GetStringSlice(key) {
+		r, err := server.ParseRoute(spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
 func nadexServer() *nadex.App {
 	nadexApp := new(nadex.App)
 	nadexApp.Username = viper.GetString("nadex-username") // this will be fallback username/password to use
-	nadexApp.Password = viper.GetString("nadex-password")
+	nadexApp.Password = secret("nadex-password")
+	nadexApp.MaxResults = viper.GetInt("nadex-max-results")
+	nadexApp.PoolSize = viper.GetInt("nadex-pool-size")
+	nadexApp.IdleTimeout = viper.GetDuration("nadex-idle-timeout")
 	nadexApp.Fake = viper.GetBool("fake")
+	nadexApp.Proxy = backendProxy("nadex")
 	return nadexApp
 }
 
@@ -139,12 +746,28 @@ func walesEmpiServer() *empi.App {
 		ProcessingID:   viper.GetString("empi-processing-id"),
 		Fake:           viper.GetBool("fake"),
 		TimeoutSeconds: viper.GetInt("empi-timeout-seconds"),
+		UpdateAddr:     viper.GetString("empi-update-addr"),
+		Transport: transport.NewHTTPTransport(transport.HTTPOptions{
+			MaxIdleConnsPerHost: viper.GetInt("empi-max-idle-conns"),
+			IdleConnTimeout:     viper.GetDuration("empi-idle-conn-timeout"),
+			Proxy:               backendProxy("empi").ProxyFunc(),
+			TLSConfig:           backendTLS("empi"),
+		}),
+	}
+	if viper.GetBool("dedupe-requests") {
+		empiApp.Dedupe = transport.NewGroup("empi")
 	}
 	cacheMinutes := viper.GetInt("empi-cache-minutes")
+	cacheBackend := viper.GetString("empi-cache-backend")
 	if cacheMinutes != 0 {
-		empiApp.Cache = cache.New(time.Duration(cacheMinutes)*time.Minute, time.Duration(cacheMinutes*2)*time.Minute)
+		var err error
+		empiApp.Cache, err = empi.NewCache(cacheBackend, viper.GetString("empi-cache-addr"), time.Duration(cacheMinutes)*time.Minute)
+		if err != nil {
+			log.Fatal(err)
+		}
+		empiApp.InvalidateOnEvents()
 	}
-	log.Printf("empi configuration: cache:%dm timeout:%ds endpoint:%s", cacheMinutes, empiApp.TimeoutSeconds, empiApp.EndpointURL)
+	log.Printf("empi configuration: cache:%dm (%s) timeout:%ds dedupe:%t endpoint:%s", cacheMinutes, cacheBackend, empiApp.TimeoutSeconds, empiApp.Dedupe != nil, empiApp.EndpointURL)
 	return empiApp
 }
 
@@ -156,21 +779,223 @@ func init() {
 	viper.BindPFlag("port-http", serveCmd.PersistentFlags().Lookup("port-http"))
 	serveCmd.PersistentFlags().Int("port-grpc", 9090, "Port to run gRPC server")
 	viper.BindPFlag("port-grpc", serveCmd.PersistentFlags().Lookup("port-grpc"))
+	serveCmd.PersistentFlags().Int("port-grpcweb", 0, "Port to run gRPC-Web server, for browser clients; not run if zero")
+	viper.BindPFlag("port-grpcweb", serveCmd.PersistentFlags().Lookup("port-grpcweb"))
+	serveCmd.PersistentFlags().StringSlice("cors-origins", nil, "Origins permitted to make cross-origin requests to the HTTP and gRPC-Web servers, e.g. 'https://app.example.nhs.uk'; all origins if empty")
+	viper.BindPFlag("cors-origins", serveCmd.PersistentFlags().Lookup("cors-origins"))
+	serveCmd.PersistentFlags().StringSlice("cors-headers", nil, "Additional request headers permitted in cross-origin requests, when origins are configured")
+	viper.BindPFlag("cors-headers", serveCmd.PersistentFlags().Lookup("cors-headers"))
+	serveCmd.PersistentFlags().Duration("http-read-timeout", server.DefaultReadTimeout, "Time permitted to read a request to the HTTP server")
+	viper.BindPFlag("http-read-timeout", serveCmd.PersistentFlags().Lookup("http-read-timeout"))
+	serveCmd.PersistentFlags().Duration("http-write-timeout", server.DefaultWriteTimeout, "Time permitted to process a request to the HTTP server and write the response")
+	viper.BindPFlag("http-write-timeout", serveCmd.PersistentFlags().Lookup("http-write-timeout"))
+	serveCmd.PersistentFlags().String("http-max-request-size", "4MB", "Maximum size of a request body to the HTTP server, e.g. '4MB'")
+	viper.BindPFlag("http-max-request-size", serveCmd.PersistentFlags().Lookup("http-max-request-size"))
+	serveCmd.PersistentFlags().StringSlice("http-routes", nil, "Timeout and maximum request size for HTTP routes by path prefix, as prefix=timeout[:size], e.g. '/v1/document/upload=5m:50MB'")
+	viper.BindPFlag("http-routes", serveCmd.PersistentFlags().Lookup("http-routes"))
+	serveCmd.PersistentFlags().String("metrics-path", "/metrics", "Path on HTTP server for prometheus metrics; no metrics if empty")
+	viper.BindPFlag("metrics-path", serveCmd.PersistentFlags().Lookup("metrics-path"))
+	serveCmd.PersistentFlags().String("openapi-path", "/openapi", "Path on HTTP server for the OpenAPI (swagger.json) definition and Swagger UI; not served if empty")
+	viper.BindPFlag("openapi-path", serveCmd.PersistentFlags().Lookup("openapi-path"))
+	serveCmd.PersistentFlags().String("liveness-path", "/healthz", "Path on HTTP server for a liveness probe; not served if empty")
+	viper.BindPFlag("liveness-path", serveCmd.PersistentFlags().Lookup("liveness-path"))
+	serveCmd.PersistentFlags().String("readiness-path", "/readyz", "Path on HTTP server for a readiness probe; not served if empty")
+	viper.BindPFlag("readiness-path", serveCmd.PersistentFlags().Lookup("readiness-path"))
+	serveCmd.PersistentFlags().StringSlice("readiness-services", nil, "Critical services, as named for health checks (e.g. 'empi'), that must be available for the server to be ready")
+	viper.BindPFlag("readiness-services", serveCmd.PersistentFlags().Lookup("readiness-services"))
+	serveCmd.PersistentFlags().Duration("drain-timeout", server.DefaultDrainTimeout, "Time permitted on shutdown for in-flight requests and queued document publications to complete")
+	viper.BindPFlag("drain-timeout", serveCmd.PersistentFlags().Lookup("drain-timeout"))
+	serveCmd.PersistentFlags().Bool("grpc-reflection", true, "Enable gRPC server reflection, so that clients such as grpcurl can discover the API")
+	viper.BindPFlag("grpc-reflection", serveCmd.PersistentFlags().Lookup("grpc-reflection"))
 
 	// SSL certificate configuration
 	serveCmd.PersistentFlags().String("cert", "", "SSL certificate file (.cert)")
 	viper.BindPFlag("cert", serveCmd.PersistentFlags().Lookup("cert"))
 	serveCmd.PersistentFlags().String("key", "", "SSL certificate key file (.key)")
 	viper.BindPFlag("key", serveCmd.PersistentFlags().Lookup("key"))
+	serveCmd.PersistentFlags().Duration("cert-reload-interval", server.DefaultCertReloadInterval, "Interval at which the certificate and key files are checked for renewal, and reloaded without a restart")
+	viper.BindPFlag("cert-reload-interval", serveCmd.PersistentFlags().Lookup("cert-reload-interval"))
+	serveCmd.PersistentFlags().StringSlice("acme-hosts", nil, "Host name(s) for which to obtain certificates automatically using ACME (e.g. Let's Encrypt), instead of --cert and --key; the HTTPS port must be reachable from the internet on port 443")
+	viper.BindPFlag("acme-hosts", serveCmd.PersistentFlags().Lookup("acme-hosts"))
+	serveCmd.PersistentFlags().String("acme-email", "", "Contact email address for the ACME account")
+	viper.BindPFlag("acme-email", serveCmd.PersistentFlags().Lookup("acme-email"))
+	serveCmd.PersistentFlags().String("acme-cache-dir", "", "Directory in which to cache certificates obtained using ACME")
+	viper.BindPFlag("acme-cache-dir", serveCmd.PersistentFlags().Lookup("acme-cache-dir"))
+	serveCmd.PersistentFlags().String("acme-directory", "", "ACME directory URL; Let's Encrypt if empty")
+	viper.BindPFlag("acme-directory", serveCmd.PersistentFlags().Lookup("acme-directory"))
+	serveCmd.PersistentFlags().String("client-ca", "", "CA certificate(s) (PEM) to verify client certificates on the gRPC port, authenticating service accounts by certificate")
+	viper.BindPFlag("client-ca", serveCmd.PersistentFlags().Lookup("client-ca"))
+	serveCmd.PersistentFlags().Bool("client-cert-required", false, "Require a client certificate on the gRPC port; the server certificate must also be issued by the client CA, as it is used by the HTTP gateway")
+	viper.BindPFlag("client-cert-required", serveCmd.PersistentFlags().Lookup("client-cert-required"))
 
 	// authentication configuration.
 	serveCmd.PersistentFlags().Bool("no-auth", false, "Turn off API authentication: all API endpoints will be unprotected")
 	viper.BindPFlag("no-auth", serveCmd.PersistentFlags().Lookup("no-auth"))
 	serveCmd.PersistentFlags().String("jwt-key", "", "RSA key to use for signing and validating JWTs")
 	viper.BindPFlag("jwt-key", serveCmd.PersistentFlags().Lookup("jwt-key"))
+	serveCmd.PersistentFlags().StringSlice("jwt-verify-keys", nil, "Additional RSA keys (PEM) with which to validate JWTs, such as the previous or next jwt-key when rotating keys")
+	viper.BindPFlag("jwt-verify-keys", serveCmd.PersistentFlags().Lookup("jwt-verify-keys"))
+	serveCmd.PersistentFlags().Int("login-max-failures", server.DefaultLoginLimits.MaxFailures, "Failed logins permitted for a user before further attempts are delayed; no limit if zero")
+	viper.BindPFlag("login-max-failures", serveCmd.PersistentFlags().Lookup("login-max-failures"))
+	serveCmd.PersistentFlags().Int("login-max-address-failures", server.DefaultLoginLimits.MaxAddressFailures, "Failed logins permitted from a network address, for any user, before further attempts are delayed; no limit if zero")
+	viper.BindPFlag("login-max-address-failures", serveCmd.PersistentFlags().Lookup("login-max-address-failures"))
+	serveCmd.PersistentFlags().Duration("login-backoff", server.DefaultLoginLimits.Backoff, "Delay after the permitted failed logins, doubling with each subsequent failure")
+	viper.BindPFlag("login-backoff", serveCmd.PersistentFlags().Lookup("login-backoff"))
+	serveCmd.PersistentFlags().Duration("login-lockout", server.DefaultLoginLimits.Lockout, "Maximum delay after failed logins, after which failures are forgotten if there are no more")
+	viper.BindPFlag("login-lockout", serveCmd.PersistentFlags().Lookup("login-lockout"))
+	serveCmd.PersistentFlags().StringSlice("maintenance", nil, "Backend(s) in maintenance at startup as backend=mode[:reason], with mode read-only or offline, e.g. 'cav=read-only:PAS upgrade'")
+	viper.BindPFlag("maintenance", serveCmd.PersistentFlags().Lookup("maintenance"))
+	serveCmd.PersistentFlags().String("rate-limits", "", "Rate limits file (YAML or JSON) defining the permitted rate of calls by each user for each method; no rate limiting if empty")
+	viper.BindPFlag("rate-limits", serveCmd.PersistentFlags().Lookup("rate-limits"))
+	serveCmd.PersistentFlags().String("tenants", "", "Tenants file (YAML or JSON) mapping accounts to tenants with their own EMPI processing ID, CAV credentials, routing rules and rate limits; no tenants if empty")
+	viper.BindPFlag("tenants", serveCmd.PersistentFlags().Lookup("tenants"))
+	serveCmd.PersistentFlags().String("auth-policy", "", "Access control policy file (YAML or JSON) defining the scopes required for each method; default policy used if empty")
+	viper.BindPFlag("auth-policy", serveCmd.PersistentFlags().Lookup("auth-policy"))
 
 	// database authentication server options
 	serveCmd.PersistentFlags().String("auth-db", "", "Auth database connection string (e.g. 'dbname=concierge sslmode=disable'")
 	viper.BindPFlag("auth-db", serveCmd.PersistentFlags().Lookup("auth-db"))
 
+	// identifier mappings
+	serveCmd.PersistentFlags().String("identifiers-db", "", "Identifier mappings database connection string (e.g. 'dbname=concierge sslmode=disable'); no stored mappings if empty")
+	viper.BindPFlag("identifiers-db", serveCmd.PersistentFlags().Lookup("identifiers-db"))
+	serveCmd.PersistentFlags().Int("identifiers-max-hops", identifiers.DefaultMaxHops, "Maximum number of mappers chained to map an identifier from one system to another")
+	viper.BindPFlag("identifiers-max-hops", serveCmd.PersistentFlags().Lookup("identifiers-max-hops"))
+
+	// patient directory
+	serveCmd.PersistentFlags().String("patients-links-db", "", "Patient link history database connection string (e.g. 'dbname=concierge sslmode=disable'); in-memory history if empty")
+	viper.BindPFlag("patients-links-db", serveCmd.PersistentFlags().Lookup("patients-links-db"))
+	serveCmd.PersistentFlags().Int("patients-prefetch-workers", patients.DefaultPrefetchWorkers, "Maximum number of patients fetched concurrently when prefetching, such as ahead of clinics")
+	viper.BindPFlag("patients-prefetch-workers", serveCmd.PersistentFlags().Lookup("patients-prefetch-workers"))
+	serveCmd.PersistentFlags().StringSlice("warm-clinics", nil, "CAV clinic code(s) whose patients for the following day are fetched on schedule, to warm caches ahead of clinics")
+	viper.BindPFlag("warm-clinics", serveCmd.PersistentFlags().Lookup("warm-clinics"))
+	serveCmd.PersistentFlags().String("warm-clinics-schedule", "0 19 * * *", "Cron-style schedule (minute hour day-of-month month day-of-week) on which to warm caches for the following day's clinics")
+	viper.BindPFlag("warm-clinics-schedule", serveCmd.PersistentFlags().Lookup("warm-clinics-schedule"))
+
+	// subscriptions
+	serveCmd.PersistentFlags().Int("subscriptions-journal-size", subscriptions.DefaultJournalSize, "Number of recent changes to patient records retained, so that subscriptions can be resumed")
+	viper.BindPFlag("subscriptions-journal-size", serveCmd.PersistentFlags().Lookup("subscriptions-journal-size"))
+	serveCmd.PersistentFlags().Duration("subscriptions-refresh", subscriptions.DefaultRefreshInterval, "Interval at which patients with active subscriptions are re-queried to detect changes; 0 to disable")
+	viper.BindPFlag("subscriptions-refresh", serveCmd.PersistentFlags().Lookup("subscriptions-refresh"))
+
+	// event publication
+	serveCmd.PersistentFlags().String("events-broker", "", "Broker for event publication (nats, kafka or log); no events published if empty")
+	viper.BindPFlag("events-broker", serveCmd.PersistentFlags().Lookup("events-broker"))
+	serveCmd.PersistentFlags().String("events-addr", "", "Address(es) of event broker (e.g. nats://localhost:4222 or a comma-separated list of kafka brokers)")
+	viper.BindPFlag("events-addr", serveCmd.PersistentFlags().Lookup("events-addr"))
+	serveCmd.PersistentFlags().String("events-topic", "concierge", "Topic (kafka) or subject prefix (nats) for published events")
+	viper.BindPFlag("events-topic", serveCmd.PersistentFlags().Lookup("events-topic"))
+	serveCmd.PersistentFlags().String("events-webhooks", "", "Webhook subscriptions file (JSON), managed using 'concierge events subscribe'; no webhooks if empty")
+	viper.BindPFlag("events-webhooks", serveCmd.PersistentFlags().Lookup("events-webhooks"))
+
+	// inbound HL7 v2
+	serveCmd.PersistentFlags().String("hl7-addr", "", "Address on which to listen for HL7 v2 ADT messages using MLLP (e.g. ':2575'); not listening if empty")
+	viper.BindPFlag("hl7-addr", serveCmd.PersistentFlags().Lookup("hl7-addr"))
+
+	// audit trail
+	serveCmd.PersistentFlags().String("audit-sink", "", "Sink for audit trail of patient data access (file, postgres or syslog); no audit trail if empty")
+	viper.BindPFlag("audit-sink", serveCmd.PersistentFlags().Lookup("audit-sink"))
+	serveCmd.PersistentFlags().String("audit-addr", "", "Audit file name, database connection string or syslog address (e.g. udp://localhost:514; local syslog if empty)")
+	viper.BindPFlag("audit-addr", serveCmd.PersistentFlags().Lookup("audit-addr"))
+
+	// distributed tracing
+	serveCmd.PersistentFlags().String("tracing-exporter", "", "Exporter for distributed tracing (otlp or stdout); no tracing if empty")
+	viper.BindPFlag("tracing-exporter", serveCmd.PersistentFlags().Lookup("tracing-exporter"))
+	serveCmd.PersistentFlags().String("tracing-addr", "localhost:55680", "Address of OpenTelemetry (OTLP) collector")
+	viper.BindPFlag("tracing-addr", serveCmd.PersistentFlags().Lookup("tracing-addr"))
+	serveCmd.PersistentFlags().Bool("tracing-insecure", false, "Connect to OpenTelemetry collector without TLS")
+	viper.BindPFlag("tracing-insecure", serveCmd.PersistentFlags().Lookup("tracing-insecure"))
+	serveCmd.PersistentFlags().Float64("tracing-sample-ratio", 1.0, "Fraction of requests to trace, unless the client has requested a trace")
+	viper.BindPFlag("tracing-sample-ratio", serveCmd.PersistentFlags().Lookup("tracing-sample-ratio"))
+
+	// Cardiff and Vale PMS
+	cav.SetProxy(backendProxy("cav"))
+	cav.SetTLSConfig(backendTLS("cav"))
+	serveCmd.PersistentFlags().String("cav-environment", "live", "CAV PMS environment: live, fake (simulated, for use outside the CAV network) or the name of an environment in the environments file")
+	viper.BindPFlag("cav-environment", serveCmd.PersistentFlags().Lookup("cav-environment"))
+	serveCmd.PersistentFlags().String("cav-environments", "", "CAV PMS environments file (YAML or JSON) configuring the URL, database, user string and content types of each environment, such as test")
+	viper.BindPFlag("cav-environments", serveCmd.PersistentFlags().Lookup("cav-environments"))
+	serveCmd.PersistentFlags().String("cav-content-types", "", "CAV PMS content types file (YAML or JSON) configuring file types and permitted document keys; defaults used if empty")
+	viper.BindPFlag("cav-content-types", serveCmd.PersistentFlags().Lookup("cav-content-types"))
+	serveCmd.PersistentFlags().String("cav-token-backend", "memory", "CAV PMS authentication token store (memory or redis); use redis to share the token between instances")
+	viper.BindPFlag("cav-token-backend", serveCmd.PersistentFlags().Lookup("cav-token-backend"))
+	serveCmd.PersistentFlags().String("cav-token-addr", "", "Address of the CAV PMS token store, for redis (e.g. localhost:6379)")
+	viper.BindPFlag("cav-token-addr", serveCmd.PersistentFlags().Lookup("cav-token-addr"))
+
+	// document routing
+	serveCmd.PersistentFlags().String("doc-rules", "", "Document routing rules file (YAML or JSON); default rules used if empty")
+	viper.BindPFlag("doc-rules", serveCmd.PersistentFlags().Lookup("doc-rules"))
+	serveCmd.PersistentFlags().Int("doc-parallelism", doc.DefaultParallelism, "Maximum number of documents published concurrently when publishing in batch")
+	viper.BindPFlag("doc-parallelism", serveCmd.PersistentFlags().Lookup("doc-parallelism"))
+	serveCmd.PersistentFlags().Bool("doc-send-to-gp", false, "Send a copy of published documents to the patient's general practice via MESH")
+	viper.BindPFlag("doc-send-to-gp", serveCmd.PersistentFlags().Lookup("doc-send-to-gp"))
+	serveCmd.PersistentFlags().Bool("doc-allow-unverified-nhs-number", false, "Permit publication to national repositories (WCRS, MESH) for patients whose NHS number is known not to have been verified")
+	viper.BindPFlag("doc-allow-unverified-nhs-number", serveCmd.PersistentFlags().Lookup("doc-allow-unverified-nhs-number"))
+	serveCmd.PersistentFlags().Bool("doc-retry", false, "Queue documents that cannot be published because a repository is unavailable, and retry in the background; required for asynchronous publication")
+	viper.BindPFlag("doc-retry", serveCmd.PersistentFlags().Lookup("doc-retry"))
+	serveCmd.PersistentFlags().String("doc-queue-db", "", "Document retry queue database connection string (e.g. 'dbname=concierge sslmode=disable'); in-memory queue if empty")
+	viper.BindPFlag("doc-queue-db", serveCmd.PersistentFlags().Lookup("doc-queue-db"))
+	serveCmd.PersistentFlags().String("doc-max-sensitivity", "", "Maximum sensitivity of documents published to each repository, as repository=sensitivity pairs (e.g. 'wcrs=restricted,gp=normal'); unrestricted if empty")
+	viper.BindPFlag("doc-max-sensitivity", serveCmd.PersistentFlags().Lookup("doc-max-sensitivity"))
+	serveCmd.PersistentFlags().Bool("doc-detect-duplicates", true, "Record published documents, so that a document re-submitted with the same identifier returns the original response unless forced, and so that documents may be replaced by amended versions")
+	viper.BindPFlag("doc-detect-duplicates", serveCmd.PersistentFlags().Lookup("doc-detect-duplicates"))
+	serveCmd.PersistentFlags().String("doc-receipts-db", "", "Document receipts database connection string (e.g. 'dbname=concierge sslmode=disable'); in-memory receipts if empty")
+	viper.BindPFlag("doc-receipts-db", serveCmd.PersistentFlags().Lookup("doc-receipts-db"))
+	serveCmd.PersistentFlags().Int("doc-retry-max-attempts", doc.DefaultMaxAttempts, "Maximum attempts to publish a queued document before it is abandoned as a dead letter")
+	viper.BindPFlag("doc-retry-max-attempts", serveCmd.PersistentFlags().Lookup("doc-retry-max-attempts"))
+	serveCmd.PersistentFlags().Duration("doc-retry-interval", doc.DefaultRetryInterval, "Delay before retrying a queued document, doubled after each failed attempt")
+	viper.BindPFlag("doc-retry-interval", serveCmd.PersistentFlags().Lookup("doc-retry-interval"))
+	serveCmd.PersistentFlags().String("doc-type-constraint", "", "SNOMED CT expression constraint (ECL) that document types must satisfy, e.g. '<< 371525003' (requires terminology server)")
+	viper.BindPFlag("doc-type-constraint", serveCmd.PersistentFlags().Lookup("doc-type-constraint"))
+	serveCmd.PersistentFlags().String("doc-deceased-policy", string(doc.DeceasedAllow), "Behaviour when publishing documents for deceased patients (allow, block, require-flag or route)")
+	viper.BindPFlag("doc-deceased-policy", serveCmd.PersistentFlags().Lookup("doc-deceased-policy"))
+	serveCmd.PersistentFlags().String("doc-deceased-repository", "", "Repository to which documents for deceased patients are published, for deceased policy 'route'")
+	viper.BindPFlag("doc-deceased-repository", serveCmd.PersistentFlags().Lookup("doc-deceased-repository"))
+	serveCmd.PersistentFlags().Float64("doc-match-threshold", apiv1.DefaultMatchThreshold, "Minimum score, from 0 to 1, for patient demographics in a document to match those from the EMPI before publication")
+	viper.BindPFlag("doc-match-threshold", serveCmd.PersistentFlags().Lookup("doc-match-threshold"))
+	serveCmd.PersistentFlags().String("doc-cda-custodian", "", "ODS code of the custodian organisation of documents published as CDA, for routing rules with format 'cda'")
+	viper.BindPFlag("doc-cda-custodian", serveCmd.PersistentFlags().Lookup("doc-cda-custodian"))
+	serveCmd.PersistentFlags().String("doc-cda-custodian-name", "", "Name of the custodian organisation of documents published as CDA")
+	viper.BindPFlag("doc-cda-custodian-name", serveCmd.PersistentFlags().Lookup("doc-cda-custodian-name"))
+	serveCmd.PersistentFlags().String("doc-renderer", "", "Renderer used to render content as PDF, for routing rules with format 'pdf' and PDF-only repositories (text or command); not rendered if empty")
+	viper.BindPFlag("doc-renderer", serveCmd.PersistentFlags().Lookup("doc-renderer"))
+	serveCmd.PersistentFlags().String("doc-renderer-command", "", "Command reading content from stdin and writing PDF to stdout, for renderer 'command', e.g. 'wkhtmltopdf --quiet - -'")
+	viper.BindPFlag("doc-renderer-command", serveCmd.PersistentFlags().Lookup("doc-renderer-command"))
+	serveCmd.PersistentFlags().StringSlice("doc-renderer-content-types", []string{"text/html"}, "Content types rendered by the command, for renderer 'command'")
+	viper.BindPFlag("doc-renderer-content-types", serveCmd.PersistentFlags().Lookup("doc-renderer-content-types"))
+	serveCmd.PersistentFlags().StringSlice("doc-pdf-repositories", nil, "Repositories accepting only PDF, to which content is rendered as PDF before publication, e.g. 'wcrs,gp'")
+	viper.BindPFlag("doc-pdf-repositories", serveCmd.PersistentFlags().Lookup("doc-pdf-repositories"))
+	serveCmd.PersistentFlags().Int64("doc-max-size", validation.DefaultMaxSize, "Maximum size of document content in bytes; 0 for no limit")
+	viper.BindPFlag("doc-max-size", serveCmd.PersistentFlags().Lookup("doc-max-size"))
+	serveCmd.PersistentFlags().Bool("doc-validate-pdf", true, "Reject PDF documents that are not structurally valid")
+	viper.BindPFlag("doc-validate-pdf", serveCmd.PersistentFlags().Lookup("doc-validate-pdf"))
+	serveCmd.PersistentFlags().Bool("doc-require-pdfa", false, "Reject PDF documents that do not declare PDF/A conformance")
+	viper.BindPFlag("doc-require-pdfa", serveCmd.PersistentFlags().Lookup("doc-require-pdfa"))
+	serveCmd.PersistentFlags().String("doc-clamd", "", "Address of clamd used to scan documents for malware (e.g. 'localhost:3310' or '/var/run/clamav/clamd.ctl'); not scanned if empty")
+	viper.BindPFlag("doc-clamd", serveCmd.PersistentFlags().Lookup("doc-clamd"))
+	serveCmd.PersistentFlags().Duration("doc-clamd-timeout", validation.DefaultClamdTimeout, "Timeout for scanning a document using clamd")
+	viper.BindPFlag("doc-clamd-timeout", serveCmd.PersistentFlags().Lookup("doc-clamd-timeout"))
+	serveCmd.PersistentFlags().String("doc-email-smtp", "", "Address of SMTP server used to send patients a secure link to documents published with patient consent (e.g. 'smtp.example.com:587'); not sent if empty")
+	viper.BindPFlag("doc-email-smtp", serveCmd.PersistentFlags().Lookup("doc-email-smtp"))
+	serveCmd.PersistentFlags().String("doc-email-username", "", "Username for SMTP authentication")
+	viper.BindPFlag("doc-email-username", serveCmd.PersistentFlags().Lookup("doc-email-username"))
+	serveCmd.PersistentFlags().String("doc-email-password", "", "Password for SMTP authentication")
+	viper.BindPFlag("doc-email-password", serveCmd.PersistentFlags().Lookup("doc-email-password"))
+	serveCmd.PersistentFlags().String("doc-email-from", "", "Sender of emails to patients e.g. 'noreply@example.com'")
+	viper.BindPFlag("doc-email-from", serveCmd.PersistentFlags().Lookup("doc-email-from"))
+	serveCmd.PersistentFlags().String("doc-email-subject", channels.DefaultEmailSubject, "Subject of emails to patients")
+	viper.BindPFlag("doc-email-subject", serveCmd.PersistentFlags().Lookup("doc-email-subject"))
+	serveCmd.PersistentFlags().String("doc-email-link", "", "Link to documents in a secure patient portal, with {id} replaced by the document identifier e.g. 'https://portal.example.com/documents/{id}'")
+	viper.BindPFlag("doc-email-link", serveCmd.PersistentFlags().Lookup("doc-email-link"))
+	serveCmd.PersistentFlags().String("doc-pkb-url", "", "URL of Patients Know Best document upload endpoint, to which documents published with patient consent are sent; not sent if empty")
+	viper.BindPFlag("doc-pkb-url", serveCmd.PersistentFlags().Lookup("doc-pkb-url"))
+	serveCmd.PersistentFlags().String("doc-pkb-token", "", "Bearer token used to authenticate with Patients Know Best")
+	viper.BindPFlag("doc-pkb-token", serveCmd.PersistentFlags().Lookup("doc-pkb-token"))
+	serveCmd.PersistentFlags().String("doc-mdm-addr", "", "Address of an HL7 v2 MLLP endpoint to which MDM^T02 notifications are sent after publication (e.g. 'epr:2575'); not sent if empty")
+	viper.BindPFlag("doc-mdm-addr", serveCmd.PersistentFlags().Lookup("doc-mdm-addr"))
+	serveCmd.PersistentFlags().String("doc-mdm-receiving-app", "", "Receiving application (MSH-5) for MDM^T02 notifications")
+	viper.BindPFlag("doc-mdm-receiving-app", serveCmd.PersistentFlags().Lookup("doc-mdm-receiving-app"))
+	serveCmd.PersistentFlags().String("doc-mdm-receiving-facility", "", "Receiving facility (MSH-6) for MDM^T02 notifications")
+	viper.BindPFlag("doc-mdm-receiving-facility", serveCmd.PersistentFlags().Lookup("doc-mdm-receiving-facility"))
+
 }
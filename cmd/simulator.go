@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/simulator"
+)
+
+// simulatorCmd represents the simulator command
+var simulatorCmd = &cobra.Command{
+	Use:   "simulator",
+	Short: "Manage the datasets used to simulate backend services in fake mode",
+}
+
+// simulatorGenerateCmd represents the simulator generate command
+var simulatorGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write JSON fixtures for a simulated dataset",
+	Long: `Write JSON fixtures for a simulated dataset to stdout, which may be edited and then used by
+running in fake mode with --simulator-data. The dataset is generated from --simulator-seed, so
+that the same seed always generates the same dataset, or is the built-in dataset if no seed is given.
+For example:
+concierge simulator generate --simulator-seed 42 --simulator-patients 500 > fixtures.json
+concierge serve --fake --simulator-data fixtures.json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ds := simulator.Default()
+		if seed := viper.GetInt64("simulator-seed"); seed != 0 {
+			ds = simulator.Generate(seed, viper.GetInt("simulator-patients"))
+		}
+		if err := ds.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(simulatorCmd)
+	simulatorCmd.AddCommand(simulatorGenerateCmd)
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/client"
+)
+
+// cachedToken is a token cached for a server
+type cachedToken struct {
+	User  string `json:"user,omitempty"`
+	Token string `json:"token"`
+}
+
+// expires returns the expiry of the token, from its claims, or the zero time if it has no expiry.
+// The token is not verified, as it is verified by the server on each call.
+func (t cachedToken) expires() time.Time {
+	claims := &jwt.StandardClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(t.Token, claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
+
+// tokenStore caches tokens, by server address, so that they can be reused by subsequent commands
+type tokenStore interface {
+	load(addr string) (cachedToken, bool)
+	save(addr string, t cachedToken) error
+	remove(addr string) error
+}
+
+// openTokenStore returns the configured token store: "file", "keychain", or "auto" to use the keychain
+// of the operating system if available, and a file readable only by the current user otherwise
+func openTokenStore() tokenStore {
+	switch s := viper.GetString("token-store"); s {
+	case "file":
+		return fileTokenStore{}
+	case "keychain":
+		if !keychainAvailable() {
+			log.Fatalf("cmd: no keychain available on %s: use --token-store file", runtime.GOOS)
+		}
+		return keychainTokenStore{}
+	case "", "auto":
+		if keychainAvailable() {
+			return keychainTokenStore{}
+		}
+		return fileTokenStore{}
+	default:
+		log.Fatalf("cmd: invalid token store '%s': must be one of file, keychain or auto", s)
+	}
+	return nil
+}
+
+// saveToken caches the current token of the client for the configured server, logging in or refreshing
+// the token if required
+func saveToken(ctx context.Context, c *client.Client) error {
+	token, err := c.Token(ctx)
+	if err != nil || token == "" {
+		return err
+	}
+	addr := viper.GetString("remote-addr")
+	store := openTokenStore()
+	cached, _ := store.load(addr)
+	if cached.Token == token {
+		return nil
+	}
+	if user := viper.GetString("remote-user"); user != "" {
+		cached.User = user
+	}
+	cached.Token = token
+	return store.save(addr, cached)
+}
+
+// fileTokenStore caches tokens in ~/.concierge/tokens.json, readable only by the current user
+type fileTokenStore struct{}
+
+// filename returns the name of the file in which tokens are cached
+func (fileTokenStore) filename() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".concierge", "tokens.json"), nil
+}
+
+// all returns the cached tokens, by server address
+func (s fileTokenStore) all() map[string]cachedToken {
+	result := make(map[string]cachedToken)
+	filename, err := s.filename()
+	if err != nil {
+		return result
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return result
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		log.Printf("cmd: ignoring invalid token cache '%s': %s", filename, err)
+	}
+	return result
+}
+
+func (s fileTokenStore) load(addr string) (cachedToken, bool) {
+	t, ok := s.all()[addr]
+	return t, ok
+}
+
+func (s fileTokenStore) save(addr string, t cachedToken) error {
+	tokens := s.all()
+	tokens[addr] = t
+	return s.write(tokens)
+}
+
+func (s fileTokenStore) remove(addr string) error {
+	tokens := s.all()
+	if _, ok := tokens[addr]; !ok {
+		return nil
+	}
+	delete(tokens, addr)
+	return s.write(tokens)
+}
+
+// write replaces the cached tokens, writing to a temporary file first so that the cache is never partially written
+func (s fileTokenStore) write(tokens map[string]cachedToken) error {
+	filename, err := s.filename()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(filename), "tokens") // created readable only by the current user
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+// keychainService is the name under which tokens are stored in the keychain
+const keychainService = "concierge"
+
+// keychainTokenStore caches tokens in the keychain of the operating system, using the 'security' tool on macOS,
+// and the 'secret-tool' tool of libsecret (e.g. GNOME Keyring) on Linux. Tokens are passed to the tools using
+// stdin rather than as arguments, so that they are not visible to other processes.
+type keychainTokenStore struct{}
+
+// keychainAvailable returns whether a keychain is available
+func keychainAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux":
+		_, err := exec.LookPath("secret-tool")
+		return err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+	}
+	return false
+}
+
+func (keychainTokenStore) load(addr string) (cachedToken, bool) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", addr, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "server", addr)
+	}
+	b, err := cmd.Output()
+	if err != nil {
+		return cachedToken{}, false // not found
+	}
+	var t cachedToken
+	if err := decodeToken(strings.TrimSpace(string(b)), &t); err != nil {
+		log.Printf("cmd: ignoring invalid token in keychain for '%s': %s", addr, err)
+		return cachedToken{}, false
+	}
+	return t, true
+}
+
+func (keychainTokenStore) save(addr string, t cachedToken) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	value := base64.StdEncoding.EncodeToString(b)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %q -w %s\n", keychainService, addr, value))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", "concierge token for "+addr, "service", keychainService, "server", addr)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	return runKeychain(cmd)
+}
+
+func (keychainTokenStore) remove(addr string) error {
+	if _, ok := (keychainTokenStore{}).load(addr); !ok {
+		return nil
+	}
+	if runtime.GOOS == "darwin" {
+		return runKeychain(exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", addr))
+	}
+	return runKeychain(exec.Command("secret-tool", "clear", "service", keychainService, "server", addr))
+}
+
+// runKeychain runs a keychain command, returning an error including its output if it fails
+func runKeychain(cmd *exec.Cmd) error {
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cmd: keychain: %s: %s", err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+func decodeToken(value string, t *cachedToken) error {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, t)
+}
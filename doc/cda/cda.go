@@ -0,0 +1,281 @@
+// Package cda generates HL7 CDA R2 wrappers for documents, as preferred by many national repositories.
+// The original content, typically a PDF, is included as an unstructured (nonXMLBody) body, base64 encoded,
+// with the header populated from the patient, authors and type of the document and the custodian configured.
+//
+// Identifiers are converted to CDA instance identifiers using the OIDs of well-known systems, or, for
+// other systems, using a name-based UUID derived from the system URI so that identifiers remain stable.
+package cda
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ContentType is the MIME type of a CDA document
+const ContentType = "application/hl7-cda+xml"
+
+// Default document type, used if a document does not have a SNOMED CT document type
+const (
+	DefaultTypeCode    = "371531000"
+	DefaultTypeDisplay = "Report of clinical encounter"
+)
+
+// OIDs for code systems and well-known identifier systems
+const (
+	oidSNOMEDCT        = "2.16.840.1.113883.6.96"
+	oidGender          = "2.16.840.1.113883.5.1"
+	oidConfidentiality = "2.16.840.1.113883.5.25"
+	oidNHSNumber       = "2.16.840.1.113883.2.1.4.1"
+	oidODSCode         = "2.16.840.1.113883.2.1.3.2.4.19.1"
+	oidSDSUserID       = "1.2.826.0.1285.0.2.0.65"
+)
+
+var oids = map[string]string{
+	identifiers.NHSNumber:   oidNHSNumber,
+	identifiers.ODSCode:     oidODSCode,
+	identifiers.ODSSiteCode: oidODSCode,
+	identifiers.SDSUserID:   oidSDSUserID,
+}
+
+// Options configures the generation of CDA documents
+type Options struct {
+	Custodian     *apiv1.Identifier // organisation responsible for the document, e.g. an ODS code
+	CustodianName string
+}
+
+// Wrap returns a copy of the document with its content wrapped in a CDA document.
+// Documents that are already CDA documents are returned unchanged.
+func Wrap(d *apiv1.Document, opts Options) (*apiv1.Document, error) {
+	if d.GetData().GetContentType() == ContentType {
+		return d, nil
+	}
+	b, err := Generate(d, opts)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := proto.Clone(d).(*apiv1.Document)
+	wrapped.Data = &apiv1.Attachment{
+		ContentType: ContentType,
+		Language:    d.GetData().GetLanguage(),
+		Data:        b,
+		Size:        uint64(len(b)),
+		Title:       d.GetData().GetTitle(),
+		Created:     d.GetData().GetCreated(),
+	}
+	return wrapped, nil
+}
+
+// Generate generates a CDA document for the document specified
+func Generate(d *apiv1.Document, opts Options) ([]byte, error) {
+	if d.GetId().GetValue() == "" {
+		return nil, errors.New("cda: document has no identifier")
+	}
+	if len(d.GetData().GetData()) == 0 {
+		return nil, errors.New("cda: document has no content")
+	}
+	effective := firstTime(d.GetDateTime(), d.GetSignedDateTime(), d.GetTypedDateTime())
+	doc := &clinicalDocument{
+		XMLNS:               "urn:hl7-org:v3",
+		TypeID:              ii{Root: "2.16.840.1.113883.1.3", Extension: "POCD_HD000040"},
+		ID:                  toII(d.GetId()),
+		Code:                documentType(d.GetType()),
+		Title:               d.GetTitle(),
+		EffectiveTime:       ts{Value: effective},
+		ConfidentialityCode: cd{Code: d.GetSensitivity().Code(), CodeSystem: oidConfidentiality},
+		LanguageCode:        language(d.GetData().GetLanguage()),
+		RecordTarget:        recordTarget(d.GetPatient()),
+		Custodian: custodian{Organisation: organisation{
+			ID:   toII(opts.Custodian),
+			Name: opts.CustodianName,
+		}},
+		Body: component{NonXMLBody: nonXMLBody{Text: text{
+			MediaType:      d.GetData().GetContentType(),
+			Representation: "B64",
+			Value:          base64.StdEncoding.EncodeToString(d.GetData().GetData()),
+		}}},
+	}
+	authored := firstTime(d.GetTypedDateTime(), d.GetSignedDateTime(), d.GetDateTime())
+	for _, id := range d.GetAuthors() {
+		doc.Authors = append(doc.Authors, author{Time: ts{Value: authored}, AssignedAuthor: assignedAuthor{ID: toII(id)}})
+	}
+	if len(doc.Authors) == 0 { // at least one author is mandatory
+		doc.Authors = append(doc.Authors, author{Time: ts{Value: authored}, AssignedAuthor: assignedAuthor{ID: ii{NullFlavor: "UNK"}}})
+	}
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// toII converts an identifier into a CDA instance identifier
+func toII(id *apiv1.Identifier) ii {
+	switch {
+	case id.GetValue() == "":
+		return ii{NullFlavor: "UNK"}
+	case id.GetSystem() == identifiers.UUID:
+		return ii{Root: strings.ToUpper(id.GetValue())}
+	case id.GetSystem() == identifiers.OID:
+		return ii{Root: id.GetValue()}
+	}
+	if oid, ok := oids[id.GetSystem()]; ok {
+		return ii{Root: oid, Extension: id.GetValue()}
+	}
+	return ii{Root: strings.ToUpper(uuid.NewSHA1(uuid.NameSpaceURL, []byte(id.GetSystem())).String()), Extension: id.GetValue()}
+}
+
+// documentType returns the CDA document type code, using a SNOMED CT document type if available
+func documentType(id *apiv1.Identifier) cd {
+	if id.GetSystem() == identifiers.SNOMEDCT && id.GetValue() != "" {
+		return cd{Code: id.GetValue(), CodeSystem: oidSNOMEDCT, CodeSystemName: "SNOMED CT"}
+	}
+	return cd{Code: DefaultTypeCode, CodeSystem: oidSNOMEDCT, CodeSystemName: "SNOMED CT", DisplayName: DefaultTypeDisplay}
+}
+
+func recordTarget(pt *apiv1.Patient) patientRole {
+	role := patientRole{}
+	for _, id := range pt.GetIdentifiers() {
+		role.IDs = append(role.IDs, toII(id))
+	}
+	if len(role.IDs) == 0 {
+		role.IDs = append(role.IDs, ii{NullFlavor: "UNK"})
+	}
+	for _, a := range pt.GetAddresses() {
+		if a.GetPeriod().GetEnd() != nil {
+			continue // only current addresses
+		}
+		role.Addresses = append(role.Addresses, addr{Lines: a.Lines(), PostalCode: a.GetPostcode(), Country: a.GetCountry()})
+	}
+	role.Patient.Name = name{Given: strings.Fields(pt.GetFirstnames()), Family: pt.GetLastname(), Prefix: pt.GetTitle()}
+	switch pt.GetGender() {
+	case apiv1.Gender_MALE:
+		role.Patient.Gender = cd{Code: "M", CodeSystem: oidGender}
+	case apiv1.Gender_FEMALE:
+		role.Patient.Gender = cd{Code: "F", CodeSystem: oidGender}
+	default:
+		role.Patient.Gender = cd{Code: "UN", CodeSystem: oidGender}
+	}
+	if dob := pt.GetBirthDate().Format("20060102"); dob != "" {
+		role.Patient.BirthTime = &ts{Value: dob}
+	}
+	return role
+}
+
+// firstTime returns the first valid timestamp, formatted as a CDA timestamp, or the current time if none
+func firstTime(tss ...*timestamppb.Timestamp) string {
+	for _, t := range tss {
+		if t == nil {
+			continue
+		}
+		if t.CheckValid() == nil {
+			return t.AsTime().Format("20060102150405-0700")
+		}
+	}
+	return time.Now().Format("20060102150405-0700")
+}
+
+func language(lang string) cd {
+	if lang == "" {
+		lang = "en-GB"
+	}
+	return cd{Code: lang}
+}
+
+type clinicalDocument struct {
+	XMLName             xml.Name    `xml:"ClinicalDocument"`
+	XMLNS               string      `xml:"xmlns,attr"`
+	TypeID              ii          `xml:"typeId"`
+	ID                  ii          `xml:"id"`
+	Code                cd          `xml:"code"`
+	Title               string      `xml:"title,omitempty"`
+	EffectiveTime       ts          `xml:"effectiveTime"`
+	ConfidentialityCode cd          `xml:"confidentialityCode"`
+	LanguageCode        cd          `xml:"languageCode"`
+	RecordTarget        patientRole `xml:"recordTarget>patientRole"`
+	Authors             []author    `xml:"author"`
+	Custodian           custodian   `xml:"custodian"`
+	Body                component   `xml:"component"`
+}
+
+// ii is a CDA instance identifier
+type ii struct {
+	Root       string `xml:"root,attr,omitempty"`
+	Extension  string `xml:"extension,attr,omitempty"`
+	NullFlavor string `xml:"nullFlavor,attr,omitempty"`
+}
+
+// cd is a CDA coded value
+type cd struct {
+	Code           string `xml:"code,attr"`
+	CodeSystem     string `xml:"codeSystem,attr,omitempty"`
+	CodeSystemName string `xml:"codeSystemName,attr,omitempty"`
+	DisplayName    string `xml:"displayName,attr,omitempty"`
+}
+
+// ts is a CDA timestamp
+type ts struct {
+	Value string `xml:"value,attr"`
+}
+
+type patientRole struct {
+	IDs       []ii   `xml:"id"`
+	Addresses []addr `xml:"addr"`
+	Patient   struct {
+		Name      name `xml:"name"`
+		Gender    cd   `xml:"administrativeGenderCode"`
+		BirthTime *ts  `xml:"birthTime,omitempty"`
+	} `xml:"patient"`
+}
+
+type addr struct {
+	Lines      []string `xml:"streetAddressLine"`
+	PostalCode string   `xml:"postalCode,omitempty"`
+	Country    string   `xml:"country,omitempty"`
+}
+
+type name struct {
+	Prefix string   `xml:"prefix,omitempty"`
+	Given  []string `xml:"given"`
+	Family string   `xml:"family"`
+}
+
+type author struct {
+	Time           ts             `xml:"time"`
+	AssignedAuthor assignedAuthor `xml:"assignedAuthor"`
+}
+
+type assignedAuthor struct {
+	ID ii `xml:"id"`
+}
+
+type custodian struct {
+	Organisation organisation `xml:"assignedCustodian>representedCustodianOrganization"`
+}
+
+type organisation struct {
+	ID   ii     `xml:"id"`
+	Name string `xml:"name,omitempty"`
+}
+
+type component struct {
+	NonXMLBody nonXMLBody `xml:"nonXMLBody"`
+}
+
+type nonXMLBody struct {
+	Text text `xml:"text"`
+}
+
+type text struct {
+	MediaType      string `xml:"mediaType,attr"`
+	Representation string `xml:"representation,attr"`
+	Value          string `xml:",chardata"`
+}
@@ -0,0 +1,66 @@
+package cda
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestWrap(t *testing.T) {
+	pdf := []byte("%PDF-1.4 test")
+	d := &apiv1.Document{
+		Id:    &apiv1.Identifier{System: identifiers.UUID, Value: "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1"},
+		Title: "Clinic letter",
+		Type:  &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "823691000000103"},
+		Patient: &apiv1.Patient{
+			Lastname:    "DUMMY",
+			Firstnames:  "ALBERT JOHN",
+			Gender:      apiv1.Gender_MALE,
+			BirthDate:   &apiv1.Date{Year: 1980, Month: 3, Day: 1},
+			Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}},
+		},
+		Authors: []*apiv1.Identifier{{System: identifiers.GMCNumber, Value: "4624000"}},
+		Data:    &apiv1.Attachment{ContentType: "application/pdf", Data: pdf},
+	}
+	wrapped, err := Wrap(d, Options{Custodian: &apiv1.Identifier{System: identifiers.ODSCode, Value: "7A4"}, CustodianName: "CARDIFF AND VALE UHB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrapped.GetData().GetContentType() != ContentType || d.GetData().GetContentType() != "application/pdf" {
+		t.Fatalf("expected a CDA copy of the document, got %s", wrapped.GetData().GetContentType())
+	}
+	var result clinicalDocument
+	if err := xml.Unmarshal(wrapped.GetData().GetData(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ID.Root != "C9A4A3C5-4B5E-4D52-9D43-2B1B4CB3C8A1" || result.Code.Code != "823691000000103" || result.Code.CodeSystem != oidSNOMEDCT {
+		t.Errorf("unexpected document header: %+v", result)
+	}
+	role := result.RecordTarget
+	if len(role.IDs) != 1 || role.IDs[0].Root != oidNHSNumber || role.IDs[0].Extension != "1111111111" {
+		t.Errorf("unexpected patient identifiers: %+v", role.IDs)
+	}
+	if role.Patient.Name.Family != "DUMMY" || len(role.Patient.Name.Given) != 2 || role.Patient.Gender.Code != "M" || role.Patient.BirthTime.Value != "19800301" {
+		t.Errorf("unexpected patient: %+v", role.Patient)
+	}
+	if len(result.Authors) != 1 || result.Authors[0].AssignedAuthor.ID.Extension != "4624000" || result.Authors[0].AssignedAuthor.ID.Root == "" {
+		t.Errorf("unexpected authors: %+v", result.Authors)
+	}
+	if result.Custodian.Organisation.ID.Root != oidODSCode || result.Custodian.Organisation.ID.Extension != "7A4" {
+		t.Errorf("unexpected custodian: %+v", result.Custodian)
+	}
+	body := result.Body.NonXMLBody.Text
+	if data, err := base64.StdEncoding.DecodeString(body.Value); err != nil || !bytes.Equal(data, pdf) || body.MediaType != "application/pdf" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+	if again, err := Wrap(wrapped, Options{}); err != nil || again != wrapped {
+		t.Errorf("expected CDA document to be unchanged")
+	}
+	if _, err := Wrap(&apiv1.Document{Data: &apiv1.Attachment{Data: pdf}}, Options{}); err == nil {
+		t.Errorf("expected error for document without identifier")
+	}
+}
@@ -0,0 +1,146 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testSMTP runs a minimal SMTP server accepting a single message, returning its address and a channel
+// receiving the recipients and message
+func testSMTP(t *testing.T) (string, chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make(chan []string, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+		reply("220 localhost ESMTP")
+		var rcpts []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); {
+			case cmd == "EHLO" || cmd == "HELO":
+				reply("250 localhost")
+			case cmd == "RCPT":
+				rcpts = append(rcpts, line)
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var msg strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					msg.WriteString(line)
+				}
+				result <- append(rcpts, msg.String())
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return l.Addr().String(), result
+}
+
+func TestEmail(t *testing.T) {
+	if _, err := NewEmail(EmailOptions{Addr: "localhost:25", From: "noreply@example.com", Link: "https://example.com"}); err == nil {
+		t.Fatal("expected link without {id} to be rejected")
+	}
+	addr, result := testSMTP(t)
+	e, err := NewEmail(EmailOptions{Addr: addr, From: "noreply@example.com", Link: "https://portal.example.com/documents/{id}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &apiv1.Document{
+		Id:      &apiv1.Identifier{System: identifiers.UUID, Value: "1234"},
+		Title:   "Sexual health clinic letter",
+		Patient: &apiv1.Patient{Firstnames: "Wendy", Lastname: "Smith"},
+	}
+	if _, _, err := e.SendToPatient(context.Background(), d); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected error for patient without email address, got %v", err)
+	}
+	d.Patient.Emails = []string{"wendy@example.com"}
+	recipient, id, err := e.SendToPatient(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipient != "wendy@example.com" || id.GetSystem() != identifiers.EmailMessageID || !strings.HasSuffix(id.GetValue(), "@example.com") {
+		t.Fatalf("unexpected recipient or message identifier: %s %v", recipient, id)
+	}
+	sent := <-result
+	if len(sent) != 2 || !strings.Contains(sent[0], "<wendy@example.com>") {
+		t.Fatalf("unexpected recipients: %v", sent)
+	}
+	if msg := sent[1]; !strings.Contains(msg, "https://portal.example.com/documents/1234") || !strings.Contains(msg, "Dear Wendy Smith") || strings.Contains(msg, d.GetTitle()) {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestPKB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var doc pkbDocument
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if doc.NHSNumber != "1111111111" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "pkb-%s"}`, string(doc.Data))
+	}))
+	defer server.Close()
+	p, err := NewPKB(PKBOptions{URL: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &apiv1.Document{
+		Id:      &apiv1.Identifier{System: identifiers.UUID, Value: "1234"},
+		Patient: &apiv1.Patient{},
+		Data:    &apiv1.Attachment{ContentType: "text/plain", Data: []byte("hello")},
+	}
+	if _, _, err := p.SendToPatient(context.Background(), d); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected error for patient without NHS number, got %v", err)
+	}
+	d.Patient.Identifiers = []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "2222222222"}}
+	if _, _, err := p.SendToPatient(context.Background(), d); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected error for patient not registered with PKB, got %v", err)
+	}
+	d.Patient.Identifiers[0].Value = "1111111111"
+	recipient, id, err := p.SendToPatient(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipient != "1111111111" || id.GetSystem() != identifiers.PKBDocumentID || id.GetValue() != "pkb-hello" {
+		t.Fatalf("unexpected recipient or document identifier: %s %v", recipient, id)
+	}
+}
@@ -0,0 +1,151 @@
+// Package channels provides channels for delivering published documents directly to patients, such as
+// by secure email link or via the Patients Know Best (PKB) patient portal.
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultEmailSubject is the default subject of emails sent to patients
+const DefaultEmailSubject = "A new document is available for you to view"
+
+// DefaultEmailTimeout is the default timeout for sending an email
+const DefaultEmailTimeout = 30 * time.Second
+
+// EmailOptions configures delivery of documents to patients by secure email link
+type EmailOptions struct {
+	Addr     string        // address of SMTP server e.g. smtp.example.com:587
+	Username string        // optional, username for SMTP authentication
+	Password string        // optional, password for SMTP authentication
+	From     string        // sender e.g. noreply@example.com
+	Subject  string        // subject; DefaultEmailSubject if empty
+	Link     string        // link to the document in a secure portal, with {id} replaced by the document identifier e.g. https://portal.example.com/documents/{id}
+	Timeout  time.Duration // timeout for sending an email; DefaultEmailTimeout if zero
+}
+
+// Email delivers a notification of a published document to the patient by email, containing a link to the
+// document in a secure portal, using the email addresses for the patient, such as those from the EMPI.
+// The email does not contain the document, nor its title, which may itself be sensitive.
+type Email struct {
+	opts EmailOptions
+}
+
+// NewEmail creates a channel delivering documents to patients by secure email link
+func NewEmail(opts EmailOptions) (*Email, error) {
+	if opts.Addr == "" || opts.From == "" {
+		return nil, fmt.Errorf("channels: email: missing SMTP server address or sender")
+	}
+	if !strings.Contains(opts.Link, "{id}") {
+		return nil, fmt.Errorf("channels: email: link must contain {id}: '%s'", opts.Link)
+	}
+	if opts.Subject == "" {
+		opts.Subject = DefaultEmailSubject
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultEmailTimeout
+	}
+	return &Email{opts: opts}, nil
+}
+
+// SendToPatient sends an email to the patient's email addresses, containing a link to the document
+func (e *Email) SendToPatient(ctx context.Context, d *apiv1.Document) (string, *apiv1.Identifier, error) {
+	to := d.GetPatient().GetEmails()
+	if len(to) == 0 {
+		return "", nil, status.Errorf(codes.FailedPrecondition, "no email address known for patient")
+	}
+	recipient := strings.Join(to, ", ")
+	id := &apiv1.Identifier{System: identifiers.EmailMessageID, Value: uuid.New().String() + "@" + domain(e.opts.From)}
+	if err := e.send(ctx, to, e.message(d, to, id.GetValue())); err != nil {
+		return recipient, nil, err
+	}
+	return recipient, id, nil
+}
+
+// message returns the email message for the document
+func (e *Email) message(d *apiv1.Document, to []string, messageID string) []byte {
+	link := strings.ReplaceAll(e.opts.Link, "{id}", url.PathEscape(d.GetId().GetValue()))
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", e.opts.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", e.opts.Subject))
+	fmt.Fprintf(&b, "Message-ID: <%s>\r\n", messageID)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	if name := strings.TrimSpace(d.GetPatient().GetFirstnames() + " " + d.GetPatient().GetLastname()); name != "" {
+		fmt.Fprintf(&b, "Dear %s,\r\n\r\n", name)
+	}
+	fmt.Fprintf(&b, "A new document is available for you to view securely at:\r\n\r\n%s\r\n\r\n", link)
+	b.WriteString("This email does not contain any clinical information. Please do not reply to this email.\r\n")
+	return b.Bytes()
+}
+
+// send sends the message using the configured SMTP server, using STARTTLS if supported
+func (e *Email) send(ctx context.Context, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", e.opts.Addr)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "email: unable to connect to SMTP server: %s", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	host, _, _ := net.SplitHostPort(e.opts.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return status.Errorf(codes.Unavailable, "email: %s", err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return status.Errorf(codes.Unavailable, "email: %s", err)
+		}
+	}
+	if e.opts.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.opts.Username, e.opts.Password, host)); err != nil {
+			return status.Errorf(codes.Unavailable, "email: %s", err)
+		}
+	}
+	if err := c.Mail(e.opts.From); err != nil {
+		return status.Errorf(codes.Unavailable, "email: %s", err)
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return status.Errorf(codes.FailedPrecondition, "email: recipient '%s' refused: %s", addr, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "email: %s", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return status.Errorf(codes.Unavailable, "email: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return status.Errorf(codes.Unavailable, "email: %s", err)
+	}
+	return c.Quit()
+}
+
+// domain returns the domain of an email address, used to generate message identifiers
+func domain(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return strings.Trim(addr[i+1:], "> ")
+	}
+	return "localhost"
+}
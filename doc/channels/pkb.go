@@ -0,0 +1,110 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPKBTimeout is the default timeout for requests to Patients Know Best
+const DefaultPKBTimeout = 30 * time.Second
+
+// PKBOptions configures delivery of documents to patients via Patients Know Best (PKB)
+type PKBOptions struct {
+	URL     string        // URL of the PKB document upload endpoint
+	Token   string        // bearer token used to authenticate with PKB
+	Timeout time.Duration // timeout for each request; DefaultPKBTimeout if zero
+}
+
+// PKB delivers documents to the patient's Patients Know Best (PKB) record, identified by NHS number.
+// Unlike email, the document itself is sent, as PKB authenticates patients before they can view documents.
+type PKB struct {
+	opts   PKBOptions
+	client *http.Client
+}
+
+// NewPKB creates a channel delivering documents to patients via Patients Know Best
+func NewPKB(opts PKBOptions) (*PKB, error) {
+	if !strings.HasPrefix(opts.URL, "https://") && !strings.HasPrefix(opts.URL, "http://") {
+		return nil, fmt.Errorf("channels: pkb: invalid url: '%s'", opts.URL)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultPKBTimeout
+	}
+	return &PKB{opts: opts, client: &http.Client{Timeout: opts.Timeout}}, nil
+}
+
+// pkbDocument is a document uploaded to PKB
+type pkbDocument struct {
+	NHSNumber   string `json:"nhsNumber"`
+	SourceID    string `json:"sourceId"` // identifier of the document, as system|value
+	Title       string `json:"title,omitempty"`
+	Date        string `json:"date,omitempty"` // RFC 3339
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"` // base64 encoded
+}
+
+// SendToPatient uploads the document to the patient's PKB record
+func (p *PKB) SendToPatient(ctx context.Context, d *apiv1.Document) (string, *apiv1.Identifier, error) {
+	nhsIDs, found := d.GetPatient().GetIdentifiersForSystem(identifiers.NHSNumber)
+	if !found {
+		return "", nil, status.Errorf(codes.FailedPrecondition, "pkb: no NHS number for patient")
+	}
+	nnn := nhsIDs[0].GetValue()
+	doc := pkbDocument{
+		NHSNumber:   nnn,
+		SourceID:    d.GetId().GetSystem() + "|" + d.GetId().GetValue(),
+		Title:       d.GetTitle(),
+		ContentType: d.GetData().GetContentType(),
+		Data:        d.GetData().GetData(),
+	}
+	if d.GetDateTime().CheckValid() == nil {
+		doc.Date = d.GetDateTime().AsTime().Format(time.RFC3339)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nnn, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.opts.URL, bytes.NewReader(b))
+	if err != nil {
+		return nnn, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.opts.Token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nnn, nil, status.Errorf(codes.Unavailable, "pkb: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nnn, nil, status.Errorf(codes.Unavailable, "pkb: %s", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nnn, nil, status.Errorf(codes.FailedPrecondition, "pkb: patient %s not registered with Patients Know Best", nnn)
+	case resp.StatusCode >= 500:
+		return nnn, nil, status.Errorf(codes.Unavailable, "pkb: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	case resp.StatusCode >= 300:
+		return nnn, nil, status.Errorf(codes.InvalidArgument, "pkb: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.ID == "" {
+		return nnn, nil, status.Errorf(codes.Internal, "pkb: invalid response: %s", strings.TrimSpace(string(body)))
+	}
+	return nnn, &apiv1.Identifier{System: identifiers.PKBDocumentID, Value: result.ID}, nil
+}
@@ -0,0 +1,63 @@
+package doc
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+)
+
+// ConsentChecker determines whether a document may be published to a repository, such as to prevent
+// sensitive documents (e.g. sexual health or safeguarding) being forwarded to national repositories
+// or general practices, returning a PermissionDenied error if not
+type ConsentChecker interface {
+	CheckConsent(ctx context.Context, d *apiv1.Document, repository string) error
+}
+
+// RegisterConsentChecker registers a named consent checker, which must permit publication of each document to a repository,
+// including copies sent to a patient's general practice.
+// This should not be called once server is running.
+func (ds *DocumentService) RegisterConsentChecker(name string, c ConsentChecker) {
+	ds.consent[name] = c
+	log.Printf("doc: registered consent checker: '%s'", name)
+}
+
+// checkConsent returns an error if any registered consent checker does not permit publication of the document to the repository
+func (ds *DocumentService) checkConsent(ctx context.Context, d *apiv1.Document, repository string) error {
+	for name, c := range ds.consent {
+		if err := c.CheckConsent(ctx, d, repository); err != nil {
+			log.Printf("doc: publication of document %s|%s to '%s' refused by '%s': %s", d.GetId().GetSystem(), d.GetId().GetValue(), repository, name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// SensitivityPolicy is a consent checker limiting the sensitivity of documents that may be published to each
+// repository, keyed by repository name. Repositories not listed are unrestricted.
+type SensitivityPolicy map[string]apiv1.Document_Sensitivity
+
+// ParseSensitivityPolicy parses the maximum sensitivity permitted for each repository, by name or HL7 confidentiality code
+// e.g. {"wcrs": "restricted", "gp": "N"}
+func ParseSensitivityPolicy(m map[string]string) (SensitivityPolicy, error) {
+	result := make(SensitivityPolicy)
+	for repository, s := range m {
+		sensitivity, ok := apiv1.ParseSensitivity(s)
+		if !ok {
+			return nil, fmt.Errorf("doc: invalid sensitivity for repository '%s': '%s'. available: normal, restricted, very-restricted", repository, s)
+		}
+		result[repository] = sensitivity
+	}
+	return result, nil
+}
+
+// CheckConsent returns an error if the document is more sensitive than permitted for the repository
+func (sp SensitivityPolicy) CheckConsent(ctx context.Context, d *apiv1.Document, repository string) error {
+	if max, ok := sp[repository]; ok && d.GetSensitivity() > max {
+		return i18n.Errorf(ctx, codes.PermissionDenied, "document sensitivity %s not permitted for repository '%s'", d.GetSensitivity(), repository)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package doc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSensitivityPolicy(t *testing.T) {
+	if _, err := ParseSensitivityPolicy(map[string]string{WCRS: "secret"}); err == nil {
+		t.Fatal("expected invalid sensitivity to be rejected")
+	}
+	policy, err := ParseSensitivityPolicy(map[string]string{WCRS: "restricted", GP: "N"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	gp := &testRepository{}
+	ds.RegisterGPSender(gp)
+	ds.RegisterConsentChecker("sensitivity", policy)
+	publish := func(sensitivity apiv1.Document_Sensitivity) (*apiv1.PublishDocumentResponse, error) {
+		return ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:          &apiv1.Identifier{System: identifiers.UUID, Value: sensitivity.String()},
+			Patient:     &apiv1.Patient{Surgery: identifiers.New(identifiers.ODSCode, "W95010")},
+			Sensitivity: sensitivity,
+		}})
+	}
+	response, err := publish(apiv1.Document_NORMAL)
+	if err != nil || len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_SENT {
+		t.Fatalf("expected normal document to be published and sent to general practice, got %v (%v)", response, err)
+	}
+	response, err = publish(apiv1.Document_RESTRICTED)
+	if err != nil || len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_FAILED {
+		t.Fatalf("expected restricted document to be published, but not sent to general practice, got %v (%v)", response, err)
+	}
+	if _, err := publish(apiv1.Document_VERY_RESTRICTED); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected very restricted document to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package doc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// deliveryTTL is the time for which the status of onward deliveries is retained
+const deliveryTTL = 7 * 24 * time.Hour
+
+// Tracker is a repository that can report the delivery status of a message it has sent
+type Tracker interface {
+	Track(ctx context.Context, messageID *apiv1.Identifier) (apiv1.Delivery_Status, error)
+}
+
+// RegisterGPSender registers a repository used to send a copy of each published document to the
+// patient's registered general practice, unless the document was published to the practice directly.
+// This should not be called once server is running.
+func (ds *DocumentService) RegisterGPSender(repo Repository) {
+	ds.gp = repo
+	log.Printf("doc: registered general practice sender")
+}
+
+// sendToGP sends a copy of the document to the patient's registered general practice, if a sender has
+// been registered, returning the delivery or nil if no delivery was attempted.
+// Failure to deliver does not fail publication, but is recorded and an event published.
+func (ds *DocumentService) sendToGP(ctx context.Context, r *apiv1.PublishDocumentRequest, repository string) *apiv1.Delivery {
+	surgery := r.GetDocument().GetPatient().GetSurgery().GetValue()
+	if ds.gp == nil || surgery == "" || repository == MESH { // MESH publishes to the practice directly
+		return nil
+	}
+	d := &apiv1.Delivery{Recipient: surgery, Repository: GP, Status: apiv1.Delivery_PENDING, Updated: timestamppb.Now()}
+	var response *apiv1.PublishDocumentResponse
+	err := ds.verifyNHSNumber(ctx, r.GetDocument().GetPatient(), GP)
+	if err == nil {
+		err = ds.checkConsent(ctx, r.GetDocument(), GP)
+	}
+	sent := r
+	if err == nil && ds.pdfOnly[GP] {
+		sent, err = ds.renderPDF(ctx, r)
+	}
+	if err == nil {
+		response, err = ds.gp.PublishDocument(ctx, sent)
+	}
+	if err != nil {
+		log.Printf("doc: failed to send document %s|%s to general practice '%s': %s", r.GetDocument().GetId().GetSystem(), r.GetDocument().GetId().GetValue(), surgery, err)
+		d.Status = apiv1.Delivery_FAILED
+		d.Error = status.Convert(err).Message()
+		events.Publish(&events.Event{Type: events.DeliveryFailed, Subject: r.GetDocument().GetId(), Error: d.Error})
+	} else {
+		d.Status = apiv1.Delivery_SENT
+		d.MessageId = response.GetId()
+	}
+	ds.recordDelivery(r.GetDocument().GetId(), d)
+	return proto.Clone(d).(*apiv1.Delivery)
+}
+
+func deliveryKey(id *apiv1.Identifier) string {
+	return id.GetSystem() + "|" + id.GetValue()
+}
+
+func (ds *DocumentService) recordDelivery(id *apiv1.Identifier, d *apiv1.Delivery) {
+	ds.deliveriesMu.Lock()
+	defer ds.deliveriesMu.Unlock()
+	var deliveries []*apiv1.Delivery
+	if o, found := ds.deliveries.Get(deliveryKey(id)); found {
+		deliveries = o.([]*apiv1.Delivery)
+	}
+	ds.deliveries.SetDefault(deliveryKey(id), append(deliveries, d))
+}
+
+// GetDeliveryStatus returns the status of onward deliveries of a published document, updating the
+// status of sent messages from the repository used, if it supports tracking.
+func (ds *DocumentService) GetDeliveryStatus(ctx context.Context, id *apiv1.Identifier) (*apiv1.DeliveryStatus, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	ds.deliveriesMu.Lock()
+	defer ds.deliveriesMu.Unlock()
+	o, found := ds.deliveries.Get(deliveryKey(id))
+	if !found {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "no deliveries found for document: %s|%s", id.GetSystem(), id.GetValue())
+	}
+	result := &apiv1.DeliveryStatus{DocumentId: id}
+	for _, d := range o.([]*apiv1.Delivery) {
+		if d.GetStatus() == apiv1.Delivery_SENT {
+			ds.track(ctx, d)
+		}
+		result.Deliveries = append(result.Deliveries, proto.Clone(d).(*apiv1.Delivery))
+	}
+	return result, nil
+}
+
+// track updates the status of a sent delivery. Caller must hold lock.
+func (ds *DocumentService) track(ctx context.Context, d *apiv1.Delivery) {
+	var repo interface{} = ds.repositories[d.GetRepository()]
+	if d.GetRepository() == GP {
+		repo = ds.gp
+	} else if c, ok := ds.patients[d.GetRepository()]; ok {
+		repo = c
+	}
+	tracker, ok := repo.(Tracker)
+	if !ok {
+		return
+	}
+	s, err := tracker.Track(ctx, d.GetMessageId())
+	if err != nil {
+		log.Printf("doc: failed to track message %s|%s: %s", d.GetMessageId().GetSystem(), d.GetMessageId().GetValue(), err)
+		return
+	}
+	if s != d.GetStatus() {
+		d.Status = s
+		d.Updated = timestamppb.Now()
+	}
+}
@@ -0,0 +1,229 @@
+package doc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/doc/render"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type testRepository struct {
+	err    error
+	status apiv1.Delivery_Status
+}
+
+func (repo *testRepository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	if repo.err != nil {
+		return nil, repo.err
+	}
+	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.MESHMessageID, Value: "msg-" + r.GetDocument().GetId().GetValue()}}, nil
+}
+
+func (repo *testRepository) Track(ctx context.Context, messageID *apiv1.Identifier) (apiv1.Delivery_Status, error) {
+	return repo.status, nil
+}
+
+func TestSendToGP(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	gp := &testRepository{status: apiv1.Delivery_SENT}
+	ds.RegisterGPSender(gp)
+	publish := func(id string, surgery string) *apiv1.PublishDocumentResponse {
+		response, err := ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:      &apiv1.Identifier{System: identifiers.UUID, Value: id},
+			Patient: &apiv1.Patient{Lastname: "DUMMY", Surgery: identifiers.New(identifiers.ODSCode, surgery)},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	if response := publish("1", ""); len(response.GetDeliveries()) != 0 {
+		t.Fatalf("expected no delivery to general practice without registered practice, got %v", response.GetDeliveries())
+	}
+	response := publish("2", "W95010")
+	if len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_SENT || response.GetDeliveries()[0].GetRecipient() != "W95010" {
+		t.Fatalf("expected document to be sent to general practice, got %v", response.GetDeliveries())
+	}
+	gp.status = apiv1.Delivery_ACKNOWLEDGED
+	result, err := ds.GetDeliveryStatus(context.Background(), &apiv1.Identifier{System: identifiers.UUID, Value: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.GetDeliveries()) != 1 || result.GetDeliveries()[0].GetStatus() != apiv1.Delivery_ACKNOWLEDGED {
+		t.Fatalf("expected delivery to be acknowledged, got %v", result.GetDeliveries())
+	}
+	gp.err = errors.New("no mailbox")
+	response = publish("3", "W95010") // failure to send to practice does not fail publication
+	if len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_FAILED || response.GetDeliveries()[0].GetError() == "" {
+		t.Fatalf("expected delivery to general practice to fail, got %v", response.GetDeliveries())
+	}
+}
+
+func TestUnverifiedNHSNumber(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	publish := func(status apiv1.NHSNumberVerificationStatus) error {
+		_, err := ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id: &apiv1.Identifier{System: identifiers.UUID, Value: "1"},
+			Patient: &apiv1.Patient{
+				Lastname:                    "DUMMY",
+				Identifiers:                 []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}},
+				NhsNumberVerificationStatus: status,
+			},
+		}})
+		return err
+	}
+	if err := publish(apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(apiv1.NHSNumberVerificationStatus_NHS_NUMBER_TRACE_REQUIRED); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected unverified NHS number to be rejected, got: %v", err)
+	}
+	ds.SetAllowUnverifiedNHSNumber(true)
+	if err := publish(apiv1.NHSNumberVerificationStatus_NHS_NUMBER_TRACE_REQUIRED); err != nil {
+		t.Fatalf("expected unverified NHS number to be permitted, got: %v", err)
+	}
+}
+
+func TestDeceasedPolicy(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	ds.RegisterRepository(CAV, &testRepository{err: errors.New("unavailable")})
+	dod := timestamppb.New(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC))
+	publish := func(allow bool) error {
+		_, err := ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{AllowDeceased: allow, Document: &apiv1.Document{
+			Id:      &apiv1.Identifier{System: identifiers.UUID, Value: "1"},
+			Patient: &apiv1.Patient{Lastname: "DUMMY", Deceased: &apiv1.Patient_DeceasedDate{DeceasedDate: dod}},
+		}})
+		return err
+	}
+	if err := publish(false); err != nil {
+		t.Fatalf("expected publication to be permitted by default, got: %v", err)
+	}
+	if err := ds.SetDeceasedPolicy("unknown", ""); err == nil {
+		t.Errorf("expected error for unknown policy")
+	}
+	if err := ds.SetDeceasedPolicy(DeceasedRoute, "unknown"); err == nil {
+		t.Errorf("expected error for unknown repository")
+	}
+	ds.SetDeceasedPolicy(DeceasedBlock, "")
+	if err := publish(true); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected publication to be blocked, got: %v", err)
+	}
+	ds.SetDeceasedPolicy(DeceasedRequireFlag, "")
+	if err := publish(false); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected publication without flag to be refused, got: %v", err)
+	}
+	if err := publish(true); err != nil {
+		t.Errorf("expected publication with flag to be permitted, got: %v", err)
+	}
+	ds.SetDeceasedPolicy(DeceasedRoute, CAV)
+	if err := publish(false); err == nil || err.Error() != "unavailable" {
+		t.Errorf("expected publication to be routed to alternative repository, got: %v", err)
+	}
+}
+
+// recordingRepository records the content of the last document published
+type recordingRepository struct {
+	testRepository
+	data *apiv1.Attachment
+}
+
+func (repo *recordingRepository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	repo.data = r.GetDocument().GetData()
+	return repo.testRepository.PublishDocument(ctx, r)
+}
+
+func TestPDFRepositories(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	wcrs := &recordingRepository{}
+	ds.RegisterRepository(WCRS, wcrs)
+	gp := &recordingRepository{}
+	ds.RegisterGPSender(gp)
+	ds.SetPDFRepositories([]string{GP})
+	publish := func(id string) *apiv1.PublishDocumentResponse {
+		response, err := ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:      &apiv1.Identifier{System: identifiers.UUID, Value: id},
+			Patient: &apiv1.Patient{Lastname: "DUMMY", Surgery: identifiers.New(identifiers.ODSCode, "W95010")},
+			Data:    &apiv1.Attachment{ContentType: "text/html", Data: []byte("<p>Dear Dr Smith</p>")},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	response := publish("1") // without a renderer, content cannot be sent to a PDF-only repository
+	if len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_FAILED {
+		t.Fatalf("expected delivery to general practice to fail without renderer, got %v", response.GetDeliveries())
+	}
+	ds.SetRenderer(render.Text{})
+	response = publish("2")
+	if len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_SENT {
+		t.Fatalf("expected document to be sent to general practice, got %v", response.GetDeliveries())
+	}
+	if wcrs.data.GetContentType() != "text/html" {
+		t.Fatalf("expected content to be published as supplied, got %s", wcrs.data.GetContentType())
+	}
+	if gp.data.GetContentType() != render.ContentTypePDF || !bytes.HasPrefix(gp.data.GetData(), []byte("%PDF")) {
+		t.Fatalf("expected content rendered as PDF to be sent to general practice, got %s", gp.data.GetContentType())
+	}
+}
+
+// testChannel is a patient delivery channel recording the documents sent
+type testChannel struct {
+	sent []*apiv1.Document
+}
+
+func (c *testChannel) SendToPatient(ctx context.Context, d *apiv1.Document) (string, *apiv1.Identifier, error) {
+	if len(d.GetPatient().GetEmails()) == 0 {
+		return "", nil, status.Error(codes.FailedPrecondition, "no email address known for patient")
+	}
+	c.sent = append(c.sent, d)
+	return d.GetPatient().GetEmails()[0], &apiv1.Identifier{System: identifiers.EmailMessageID, Value: d.GetId().GetValue()}, nil
+}
+
+func TestSendToPatient(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	email := &testChannel{}
+	ds.RegisterPatientChannel("email", email)
+	ds.RegisterConsentChecker("sensitivity", SensitivityPolicy{"email": apiv1.Document_NORMAL})
+	publish := func(id string, consent bool, sensitivity apiv1.Document_Sensitivity, emails ...string) *apiv1.PublishDocumentResponse {
+		response, err := ds.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:              &apiv1.Identifier{System: identifiers.UUID, Value: id},
+			Patient:         &apiv1.Patient{Lastname: "DUMMY", Emails: emails},
+			PatientDelivery: consent,
+			Sensitivity:     sensitivity,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	if response := publish("1", false, apiv1.Document_NORMAL, "test@example.com"); len(response.GetDeliveries()) != 0 || len(email.sent) != 0 {
+		t.Fatalf("expected no delivery to patient without consent, got %v", response.GetDeliveries())
+	}
+	response := publish("2", true, apiv1.Document_NORMAL, "test@example.com")
+	if len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_SENT || response.GetDeliveries()[0].GetRecipient() != "test@example.com" || len(email.sent) != 1 {
+		t.Fatalf("expected document to be sent to patient, got %v", response.GetDeliveries())
+	}
+	if response := publish("3", true, apiv1.Document_RESTRICTED, "test@example.com"); len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_FAILED || len(email.sent) != 1 {
+		t.Fatalf("expected restricted document not to be sent to patient, got %v", response.GetDeliveries())
+	}
+	if response := publish("4", true, apiv1.Document_NORMAL); len(response.GetDeliveries()) != 1 || response.GetDeliveries()[0].GetStatus() != apiv1.Delivery_FAILED {
+		t.Fatalf("expected delivery to patient without email address to fail, got %v", response.GetDeliveries())
+	}
+	result, err := ds.GetDeliveryStatus(context.Background(), &apiv1.Identifier{System: identifiers.UUID, Value: "2"})
+	if err != nil || len(result.GetDeliveries()) != 1 || result.GetDeliveries()[0].GetRepository() != "email" {
+		t.Fatalf("expected delivery to patient to be recorded, got %v (%v)", result, err)
+	}
+}
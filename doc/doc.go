@@ -0,0 +1,685 @@
+// Package doc provides a document publication service, routing documents to the most appropriate repositories.
+package doc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/doc/cda"
+	"github.com/wardle/concierge/doc/render"
+	"github.com/wardle/concierge/doc/rules"
+	"github.com/wardle/concierge/doc/validation"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/tenant"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/wales/cav"
+	"github.com/wardle/concierge/wales/empi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DocumentService is a document publication service; it currently publishes to Cardiff and Vale, to
+// general practices in England via MESH, and to the Welsh Care Records Service as a fallback, but is
+// easily extendable to publish documents to other providers as well.
+// The repository used for any given document is chosen using configurable routing rules.
+type DocumentService struct {
+	empi         *empi.App
+	repositories map[string]Repository
+	rulesMu      sync.RWMutex
+	rules        *rules.RuleSet
+	tenantRules  map[string]*rules.RuleSet // routing rules for each tenant, in place of the default rules
+	parallelism  int
+	gp           Repository                // optional, used to send copies of documents to general practices
+	patients     map[string]PatientChannel // optional, used to deliver documents to consenting patients
+	validator    *validation.Validator     // optional, used to validate content before publication
+	validateType TypeValidator             // optional, used to validate document type before publication
+	cda          cda.Options               // used to generate CDA documents for rules requiring CDA
+	renderer     render.Renderer           // optional, used to render content as PDF for rules and repositories requiring PDF
+	pdfOnly      map[string]bool           // repositories accepting only PDF
+	notifiers    map[string]Notifier       // optional, notified of documents once published
+	consent      map[string]ConsentChecker // optional, must permit publication of each document to a repository
+	unverified   bool                      // permit publication to national repositories for unverified NHS numbers
+	threshold    float64                   // minimum score for demographics in a document to match those from the EMPI
+	deceased     DeceasedPolicy            // behaviour when publishing documents for deceased patients
+	deceasedRepo string                    // repository used for deceased patients, for DeceasedRoute
+
+	deliveriesMu sync.Mutex
+	deliveries   *cache.Cache // document system|value -> []*apiv1.Delivery
+
+	receipts     ReceiptStore // optional, used to detect re-submission of documents already published
+	publishingMu sync.Mutex
+	publishing   map[string]chan struct{} // documents being published, keyed by identifier, closed once published
+
+	queue   Queue // optional, used to retry failed publications and for asynchronous publication
+	retry   RetryOptions
+	done    chan struct{}
+	wake    chan struct{}      // signals the worker that a document has been queued for immediate publication
+	stopped chan struct{}      // closed once the worker has stopped
+	cancel  context.CancelFunc // cancels publications in progress by the worker
+	drain   time.Duration      // time permitted on close for publications in progress by the worker to complete
+}
+
+// Repository is a document repository to which documents can be published
+type Repository interface {
+	PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error)
+}
+
+// TypeValidator validates the type of a document before publication, such as checking that a SNOMED CT
+// expression is valid, and within an expected hierarchy, returning an InvalidArgument error if not
+type TypeValidator func(ctx context.Context, typ *apiv1.Identifier) error
+
+// Notifier is notified of documents that have been successfully published, such as to let
+// legacy systems index a new document without polling
+type Notifier interface {
+	NotifyPublished(ctx context.Context, d *apiv1.Document, response *apiv1.PublishDocumentResponse) error
+}
+
+// notifyTimeout is the maximum time permitted for a notifier to be notified of a published document
+const notifyTimeout = 30 * time.Second
+
+// Names of repositories
+const (
+	CAV  = "cav"  // Cardiff and Vale PMS, which automatically propagates documents to the national repository
+	MESH = "mesh" // NHS England MESH, for general practices in England
+	WCRS = "wcrs" // Welsh Care Records Service, the fallback repository for patients in Wales
+	GP   = "gp"   // general practice sender, used for copies of documents sent to a patient's general practice
+)
+
+// DeceasedPolicy determines the behaviour when publishing a document for a patient who has died
+type DeceasedPolicy string
+
+// Deceased patient policies
+const (
+	DeceasedAllow       DeceasedPolicy = "allow"        // publish as normal
+	DeceasedBlock       DeceasedPolicy = "block"        // refuse publication
+	DeceasedRequireFlag DeceasedPolicy = "require-flag" // refuse publication unless the request sets allow_deceased
+	DeceasedRoute       DeceasedPolicy = "route"        // publish to a specific repository, rather than using the routing rules
+)
+
+// nationalRepositories are repositories that identify patients by NHS number, and so require a verified NHS number
+var nationalRepositories = map[string]bool{MESH: true, WCRS: true, GP: true}
+
+// DefaultParallelism is the default number of documents published concurrently in a batch
+const DefaultParallelism = 4
+
+// DefaultRules returns the default routing rules:
+// 1. patients with a Cardiff and Vale identifier are published to Cardiff and Vale PMS,
+// 2. patients registered with a general practice in England are published via MESH,
+// 3. otherwise, documents are published to the Welsh Care Records Service.
+// Rules for repositories that have not been registered are skipped.
+func DefaultRules() *rules.RuleSet {
+	return &rules.RuleSet{Rules: []*rules.Rule{
+		{Name: "cardiff-and-vale", IdentifierSystems: []string{identifiers.CardiffAndValeCRN}, Repository: CAV},
+		{Name: "english-general-practice", Practices: []string{"*"}, ExcludePractices: []string{"W*"}, Repository: MESH},
+		{Name: "fallback", Repository: WCRS},
+	}}
+}
+
+// NewDocumentService creates a new document service publishing to Cardiff and Vale, using the default routing rules
+func NewDocumentService(cavpms *cav.PMSService, empi *empi.App) *DocumentService {
+	ds := &DocumentService{
+		empi:         empi,
+		repositories: make(map[string]Repository),
+		notifiers:    make(map[string]Notifier),
+		consent:      make(map[string]ConsentChecker),
+		patients:     make(map[string]PatientChannel),
+		rules:        DefaultRules(),
+		parallelism:  DefaultParallelism,
+		threshold:    apiv1.DefaultMatchThreshold,
+		deliveries:   cache.New(deliveryTTL, time.Hour),
+	}
+	if cavpms != nil {
+		ds.RegisterRepository(CAV, cavpms)
+	}
+	return ds
+}
+
+// SetParallelism sets the maximum number of documents published concurrently in a batch.
+// This should not be called once server is running.
+func (ds *DocumentService) SetParallelism(n int) {
+	if n < 1 {
+		n = 1
+	}
+	ds.parallelism = n
+}
+
+// Repositories returns the names of the registered repositories
+func (ds *DocumentService) Repositories() []string {
+	names := make([]string, 0, len(ds.repositories))
+	for name := range ds.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetRules sets the routing rules used to choose a repository for a document, validating that
+// the rules only use registered repositories. Rules may be replaced while the server is running.
+func (ds *DocumentService) SetRules(rs *rules.RuleSet) error {
+	if errs := rs.Validate(ds.Repositories()); len(errs) > 0 {
+		return errs[0]
+	}
+	ds.rulesMu.Lock()
+	defer ds.rulesMu.Unlock()
+	ds.rules = rs
+	return nil
+}
+
+// SetTenantRules sets the routing rules used for documents published on behalf of the named tenant, in place
+// of the default rules, validating that the rules only use registered repositories. Rules may be replaced
+// while the server is running.
+func (ds *DocumentService) SetTenantRules(name string, rs *rules.RuleSet) error {
+	if errs := rs.Validate(ds.Repositories()); len(errs) > 0 {
+		return fmt.Errorf("doc: invalid rules for tenant '%s': %w", name, errs[0])
+	}
+	ds.rulesMu.Lock()
+	defer ds.rulesMu.Unlock()
+	if ds.tenantRules == nil {
+		ds.tenantRules = make(map[string]*rules.RuleSet)
+	}
+	ds.tenantRules[name] = rs
+	return nil
+}
+
+// rulesFor returns the routing rules for documents published on behalf of the tenant in the context specified
+func (ds *DocumentService) rulesFor(ctx context.Context) *rules.RuleSet {
+	ds.rulesMu.RLock()
+	defer ds.rulesMu.RUnlock()
+	if rs, ok := ds.tenantRules[tenant.FromContext(ctx)]; ok {
+		return rs
+	}
+	return ds.rules
+}
+
+// RegisterRepository registers an optional named repository
+// This should not be called once server is running.
+func (ds *DocumentService) RegisterRepository(name string, repo Repository) {
+	ds.repositories[name] = repo
+	log.Printf("doc: registered repository: '%s'", name)
+}
+
+// SetValidator sets the validator used to validate document content before publication
+// This should not be called once server is running.
+func (ds *DocumentService) SetValidator(v *validation.Validator) {
+	ds.validator = v
+}
+
+// SetTypeValidator sets the function used to validate document types before publication
+// This should not be called once server is running.
+func (ds *DocumentService) SetTypeValidator(v TypeValidator) {
+	ds.validateType = v
+}
+
+// RegisterNotifier registers a named notifier, notified of each document once published
+// This should not be called once server is running.
+func (ds *DocumentService) RegisterNotifier(name string, n Notifier) {
+	ds.notifiers[name] = n
+	log.Printf("doc: registered notifier: '%s'", name)
+}
+
+// SetAllowUnverifiedNHSNumber sets whether documents for patients with an unverified NHS number may be published to
+// national repositories. By default, such documents are rejected.
+// This should not be called once server is running.
+func (ds *DocumentService) SetAllowUnverifiedNHSNumber(allow bool) {
+	ds.unverified = allow
+}
+
+// SetDeceasedPolicy sets the behaviour when publishing documents for deceased patients. A repository must
+// be specified for DeceasedRoute, and is otherwise ignored.
+// This should not be called once server is running.
+func (ds *DocumentService) SetDeceasedPolicy(policy DeceasedPolicy, repository string) error {
+	switch policy {
+	case DeceasedAllow, DeceasedBlock, DeceasedRequireFlag:
+	case DeceasedRoute:
+		if _, ok := ds.repositories[repository]; !ok {
+			return fmt.Errorf("doc: unknown repository for deceased patients: '%s'. available: %s", repository, strings.Join(ds.Repositories(), ", "))
+		}
+	default:
+		return fmt.Errorf("doc: unknown deceased patient policy: '%s'. available: allow, block, require-flag, route", policy)
+	}
+	ds.deceased, ds.deceasedRepo = policy, repository
+	log.Printf("doc: deceased patient policy: %s %s", policy, repository)
+	return nil
+}
+
+// SetMatchThreshold sets the minimum score, from 0 to 1, for the patient demographics in a document to be considered
+// a match for those from the EMPI; documents for patients not matching are refused.
+// This should not be called once server is running.
+func (ds *DocumentService) SetMatchThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("doc: invalid match threshold: %v. must be between 0 and 1", threshold)
+	}
+	ds.threshold = threshold
+	return nil
+}
+
+// SetCDAOptions sets the options used to wrap documents in CDA documents, for routing rules requiring CDA
+// This should not be called once server is running.
+func (ds *DocumentService) SetCDAOptions(opts cda.Options) {
+	ds.cda = opts
+}
+
+// SetRenderer sets the renderer used to render content as PDF, for routing rules and repositories requiring PDF.
+// This should not be called once server is running.
+func (ds *DocumentService) SetRenderer(r render.Renderer) {
+	ds.renderer = r
+}
+
+// SetPDFRepositories sets the repositories that accept only PDF, including the general practice sender ("gp");
+// content is rendered as PDF before publication to these repositories.
+// This should not be called once server is running.
+func (ds *DocumentService) SetPDFRepositories(repositories []string) {
+	ds.pdfOnly = make(map[string]bool)
+	for _, repo := range repositories {
+		ds.pdfOnly[repo] = true
+	}
+}
+
+// renderPDF returns a copy of the request with its content rendered as PDF, or the request itself if already PDF
+func (ds *DocumentService) renderPDF(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentRequest, error) {
+	data, err := render.ToPDF(ctx, ds.renderer, r.GetDocument().GetData())
+	if err != nil {
+		return nil, err
+	}
+	if data == r.GetDocument().GetData() {
+		return r, nil
+	}
+	r2 := proto.Clone(r).(*apiv1.PublishDocumentRequest)
+	r2.Document.Data = data
+	return r2, nil
+}
+
+var _ apiv1.DocumentServiceServer = (*DocumentService)(nil)
+
+// RegisterServer registers this server
+func (ds *DocumentService) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterDocumentServiceServer(s, ds)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (ds *DocumentService) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	client := apiv1.NewDocumentServiceClient(conn)
+	registerUpload(mux, client)
+	return apiv1.RegisterDocumentServiceHandlerClient(ctx, mux, client)
+}
+
+// Close closes any linked resources, stopping the retry of queued documents. Publications of queued documents
+// already in progress are permitted to complete, up to the drain timeout, after which they are cancelled and
+// returned to the queue.
+func (ds *DocumentService) Close() error {
+	if ds.receipts != nil {
+		if err := ds.receipts.Close(); err != nil {
+			log.Printf("doc: failed to close receipt store: %s", err)
+		}
+	}
+	if ds.queue == nil {
+		return nil
+	}
+	close(ds.done)
+	select {
+	case <-ds.stopped:
+	case <-time.After(ds.drainTimeout()):
+		log.Printf("doc: timed out after %s draining queued publications: cancelling", ds.drainTimeout())
+		ds.cancel()
+		<-ds.stopped
+	}
+	ds.cancel()
+	return ds.queue.Close()
+}
+
+// matchingIdentifiers gives a list of identifiers that will be matched before a document is accepted.
+var matchingIdentifiers = []string{
+	identifiers.NHSNumber,
+	identifiers.CardiffAndValeCRN,
+	identifiers.CwmTafCRN,
+	identifiers.AneurinBevanCRN,
+}
+
+// PublishDocument is the single abstract end-point for publishing documents via concierge.
+// This endpoint will try to *do the right thing* based on the context, using the routing rules
+// configured to choose the repository, and sending a copy to the patient's general practice if configured.
+// If a retry queue is configured, documents that cannot be published because a repository is unavailable
+// are queued for retry, and the response marked as queued.
+// Asynchronous requests are queued for publication immediately, and the response includes a receipt
+// that can be used to poll for the status of publication using GetPublicationStatus.
+// If a receipt store is configured, a document re-submitted with the same identifier is not published again,
+// unless forced, and the original response is returned. A document may replace a previously published document,
+// such as an amended version, in which case the previous versions are passed to the repository.
+func (ds *DocumentService) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	id := r.GetDocument().GetId()
+	if ds.receipts != nil && id.GetValue() != "" {
+		unlock, err := ds.lockDocument(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		if !r.GetForce() {
+			if response, err := ds.duplicate(ctx, r); err != nil || response != nil {
+				return response, err
+			}
+		}
+	}
+	if r.GetReplaces() != nil {
+		previous, err := ds.previousVersions(ctx, id, r.GetReplaces())
+		if err != nil {
+			return nil, err
+		}
+		r = proto.Clone(r).(*apiv1.PublishDocumentRequest)
+		r.PreviousVersions = previous
+	}
+	if r.GetAsync() {
+		return ds.publishAsync(ctx, r)
+	}
+	response, err := ds.publishDocument(ctx, r)
+	if err != nil && ds.queue != nil && retryable(err) && id.GetValue() != "" {
+		queued, qerr := ds.enqueue(ctx, r, err)
+		if qerr == nil {
+			return queued, nil
+		}
+		log.Printf("doc: failed to queue document for retry: %s", qerr)
+	}
+	if err != nil {
+		events.Publish(&events.Event{Type: events.DeliveryFailed, Subject: id, Error: err.Error()})
+		return nil, err
+	}
+	ds.recordReceipt(ctx, r, response)
+	ds.published(r.GetDocument(), response)
+	return response, nil
+}
+
+// published publishes an event for a successfully published document, and notifies any
+// registered notifiers in the background, so that a failed notification does not fail publication
+func (ds *DocumentService) published(d *apiv1.Document, response *apiv1.PublishDocumentResponse) {
+	events.Publish(&events.Event{Type: events.DocumentPublished, Subject: d.GetId(), Patient: d.GetPatient().GetIdentifiers(), Data: response})
+	for name, n := range ds.notifiers {
+		go func(name string, n Notifier) {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			if err := n.NotifyPublished(ctx, d, response); err != nil {
+				log.Printf("doc: failed to notify '%s' of document %s|%s: %s", name, d.GetId().GetSystem(), d.GetId().GetValue(), err)
+			}
+		}(name, n)
+	}
+}
+
+// publishAsync queues a document for publication, returning a receipt without waiting for the repository
+func (ds *DocumentService) publishAsync(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	if ds.queue == nil {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "asynchronous publication requires a document queue")
+	}
+	if r.GetDocument() == nil {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "no document specified")
+	}
+	if r.GetDocument().GetId().GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "asynchronous publication requires a document identifier")
+	}
+	if err := ds.validate(ctx, r.GetDocument()); err != nil {
+		return nil, err
+	}
+	return ds.enqueue(ctx, r, nil)
+}
+
+// PublishDocuments publishes a stream of documents, with up to the configured number of documents
+// published concurrently. A response is sent for each document as soon as it is processed, so
+// responses may not be in the order requested; each response includes the identifier of the
+// document from the request so that clients can correlate results.
+// Failure to publish a single document is reported in its response and does not end the stream.
+func (ds *DocumentService) PublishDocuments(stream apiv1.DocumentService_PublishDocumentsServer) error {
+	ctx := stream.Context()
+	sem := make(chan struct{}, ds.parallelism)
+	var wg sync.WaitGroup
+	var sendMu sync.Mutex
+	var sendErr error
+	send := func(response *apiv1.PublishDocumentResponse) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(response)
+		}
+	}
+	var recvErr error
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			recvErr = ctx.Err()
+		}
+		if recvErr != nil {
+			break
+		}
+		wg.Add(1)
+		go func(r *apiv1.PublishDocumentRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			response, err := ds.PublishDocument(ctx, r)
+			if err != nil {
+				response = &apiv1.PublishDocumentResponse{ErrorCode: int32(status.Code(err)), Error: status.Convert(err).Message()}
+			}
+			response.DocumentId = r.GetDocument().GetId()
+			send(response)
+		}(r)
+	}
+	wg.Wait()
+	if recvErr != nil {
+		return recvErr
+	}
+	return sendErr
+}
+
+func (ds *DocumentService) publishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (response *apiv1.PublishDocumentResponse, err error) {
+	if r.GetDocument() == nil {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "no document specified")
+	}
+	ctx, span := tracing.StartSpan(ctx, "doc.publish")
+	defer tracing.End(ctx, span, &err)
+	if err := ds.validate(ctx, r.GetDocument()); err != nil {
+		return nil, err
+	}
+	r, err = ds.enrich(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := ds.applyDeceasedPolicy(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		rule = ds.rulesFor(ctx).Match(r.GetDocument(), func(repo string) bool {
+			_, ok := ds.repositories[repo]
+			return ok
+		})
+	}
+	if rule == nil {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "Unable to publish document: no repository found to support patient with these identifiers")
+	}
+	if err := ds.verifyNHSNumber(ctx, r.GetDocument().GetPatient(), rule.Repository); err != nil {
+		return nil, err
+	}
+	if err := ds.checkConsent(ctx, r.GetDocument(), rule.Repository); err != nil {
+		return nil, err
+	}
+	log.Printf("doc: publishing document %s|%s to '%s' (rule: '%s')", r.GetDocument().GetId().GetSystem(), r.GetDocument().GetId().GetValue(), rule.Repository, rule.Name)
+	span.SetAttributes(tracing.String("doc.repository", rule.Repository), tracing.String("doc.rule", rule.Name))
+	published := r
+	if rule.Format == rules.FormatPDF || ds.pdfOnly[rule.Repository] {
+		if published, err = ds.renderPDF(ctx, published); err != nil {
+			return nil, err
+		}
+	}
+	if rule.Format == rules.FormatCDA {
+		d, err := cda.Wrap(published.GetDocument(), ds.cda)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to generate CDA document: %s", err)
+		}
+		published = &apiv1.PublishDocumentRequest{Document: d, Async: r.GetAsync(), Replaces: r.GetReplaces(), PreviousVersions: r.GetPreviousVersions()}
+	}
+	response, err = ds.repositories[rule.Repository].PublishDocument(ctx, published)
+	if err != nil {
+		return nil, err
+	}
+	if d := ds.sendToGP(ctx, r, rule.Repository); d != nil {
+		response.Deliveries = append(response.Deliveries, d)
+	}
+	response.Deliveries = append(response.Deliveries, ds.sendToPatient(ctx, r)...)
+	return response, nil
+}
+
+// validate validates the type and content of the document, if validators are configured
+func (ds *DocumentService) validate(ctx context.Context, d *apiv1.Document) error {
+	if ds.validateType != nil && d.GetType().GetValue() != "" {
+		if err := ds.validateType(ctx, d.GetType()); err != nil {
+			return err
+		}
+	}
+	if ds.validator == nil {
+		return nil
+	}
+	return ds.validator.Validate(ctx, d.GetData())
+}
+
+// applyDeceasedPolicy applies the deceased patient policy, returning an error if publication is not permitted
+// for a deceased patient, or a rule to be used in place of the routing rules, if any.
+// A patient is deceased if the document, or the EMPI, records a death.
+func (ds *DocumentService) applyDeceasedPolicy(ctx context.Context, r *apiv1.PublishDocumentRequest) (*rules.Rule, error) {
+	if ds.deceased == "" || ds.deceased == DeceasedAllow {
+		return nil, nil
+	}
+	deceased, date := ds.isDeceased(ctx, r.GetDocument().GetPatient())
+	if !deceased {
+		return nil, nil
+	}
+	switch ds.deceased {
+	case DeceasedBlock:
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "patient is deceased (%s): publication not permitted", date)
+	case DeceasedRequireFlag:
+		if !r.GetAllowDeceased() {
+			return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "patient is deceased (%s): set allow_deceased to publish", date)
+		}
+	case DeceasedRoute:
+		return &rules.Rule{Name: "deceased", Repository: ds.deceasedRepo}, nil
+	}
+	return nil, nil
+}
+
+// isDeceased determines whether the patient is deceased, returning the date of death, if known
+func (ds *DocumentService) isDeceased(ctx context.Context, pt *apiv1.Patient) (bool, string) {
+	deceased := func(pt *apiv1.Patient) (bool, string) {
+		if pt.GetDeceased() == nil {
+			return false, ""
+		}
+		if pt.GetDeceasedDate().CheckValid() == nil {
+			return true, pt.GetDeceasedDate().AsTime().Format("2006-01-02")
+		}
+		return true, "date unknown"
+	}
+	if ok, date := deceased(pt); ok {
+		return ok, date
+	}
+	if nhsIDs, found := pt.GetIdentifiersForSystem(identifiers.NHSNumber); found && ds.empi != nil {
+		if npt, err := ds.empi.GetEMPIRequest(ctx, nhsIDs[0]); err == nil {
+			return deceased(npt)
+		}
+	}
+	return false, ""
+}
+
+// verifyNHSNumber returns an error if the document is to be published to a national repository, and the patient's
+// NHS number is known not to have been verified, unless unverified NHS numbers are permitted. The verification status
+// from the EMPI takes precedence over that in the document, if available.
+func (ds *DocumentService) verifyNHSNumber(ctx context.Context, pt *apiv1.Patient, repository string) error {
+	if ds.unverified || !nationalRepositories[repository] {
+		return nil
+	}
+	nhsIDs, found := pt.GetIdentifiersForSystem(identifiers.NHSNumber)
+	if !found {
+		return nil
+	}
+	status := pt.GetNhsNumberVerificationStatus()
+	if ds.empi != nil {
+		if npt, err := ds.empi.GetEMPIRequest(ctx, nhsIDs[0]); err == nil && npt.GetNhsNumberVerificationStatus() != apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN {
+			status = npt.GetNhsNumberVerificationStatus()
+		}
+	}
+	if status == apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN || status == apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED {
+		return nil
+	}
+	log.Printf("doc: refusing publication to '%s' for unverified NHS number %s: %s", repository, nhsIDs[0].GetValue(), status)
+	return i18n.Errorf(ctx, codes.FailedPrecondition, "NHS number %s has not been verified (%s): cannot publish to national repository '%s'", nhsIDs[0].GetValue(), status, repository)
+}
+
+// enrich supplements the patient details in the request using the national EMPI, if our client
+// failed to provide a Cardiff and Vale identifier, if the general practice is needed to send a copy,
+// or if email addresses are needed to deliver the document to the patient, so that routing rules can make
+// use of any Cardiff and Vale registration and the patient's current general practice.
+// The original request is returned unchanged if no enrichment is possible.
+func (ds *DocumentService) enrich(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentRequest, error) {
+	doc := r.GetDocument()
+	if _, found := doc.GetPatient().GetIdentifiersForSystem(identifiers.CardiffAndValeCRN); found && (ds.gp == nil || doc.GetPatient().GetSurgery() != nil) && !ds.needsEmails(doc) {
+		return r, nil
+	}
+	nhsIDs, found := doc.GetPatient().GetIdentifiersForSystem(identifiers.NHSNumber)
+	if !found || ds.empi == nil {
+		return r, nil
+	}
+	npt, err := ds.empi.GetEMPIRequest(ctx, nhsIDs[0])
+	if err != nil {
+		return r, nil
+	}
+	match := doc.GetPatient().MatchScore(npt, matchingIdentifiers)
+	if match.Score < ds.threshold {
+		log.Printf("doc: fatal error when publishing document for patient: mismatched patient compared to EMPI: score %s", match)
+		log.Printf("doc: from doc : %s", protojson.MarshalOptions{}.Format(doc.GetPatient()))
+		log.Printf("doc: from empi: %s", protojson.MarshalOptions{}.Format(npt))
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "could not publish document: mismatched demographics between Cardiff and Vale and EMPI")
+	}
+	if match.Score < 1 {
+		log.Printf("doc: patient demographics in document %s|%s partially match EMPI: score %s", doc.GetId().GetSystem(), doc.GetId().GetValue(), match)
+	}
+	r2 := proto.Clone(r).(*apiv1.PublishDocumentRequest) // make a copy
+	pt := r2.GetDocument().GetPatient()
+	if _, found := pt.GetIdentifiersForSystem(identifiers.CardiffAndValeCRN); !found {
+		if cavIDs, found := npt.GetIdentifiersForSystem(identifiers.CardiffAndValeCRN); found {
+			pt.Identifiers = append(pt.Identifiers, &apiv1.Identifier{
+				System: identifiers.CardiffAndValeCRN,
+				Value:  cavIDs[0].GetValue(),
+			})
+		}
+	}
+	if npt.GetSurgery() != nil {
+		pt.Surgery = npt.GetSurgery()
+	}
+	if len(pt.GetEmails()) == 0 {
+		pt.Emails = npt.GetEmails()
+	}
+	return r2, nil
+}
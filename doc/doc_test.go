@@ -0,0 +1,119 @@
+package doc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slowRepository fails documents with identifiers beginning "fail", and delays publication of
+// documents with identifiers beginning "slow", recording the maximum number published concurrently
+type slowRepository struct {
+	active, max int32
+}
+
+func (repo *slowRepository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	n := atomic.AddInt32(&repo.active, 1)
+	defer atomic.AddInt32(&repo.active, -1)
+	for {
+		max := atomic.LoadInt32(&repo.max)
+		if n <= max || atomic.CompareAndSwapInt32(&repo.max, max, n) {
+			break
+		}
+	}
+	id := r.GetDocument().GetId().GetValue()
+	delay := 20 * time.Millisecond
+	if strings.HasPrefix(id, "slow") {
+		delay = 200 * time.Millisecond
+	}
+	time.Sleep(delay)
+	if strings.HasPrefix(id, "fail") {
+		return nil, status.Errorf(codes.Unavailable, "repository unavailable")
+	}
+	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.WCRSDocumentID, Value: "wcrs-" + id}}, nil
+}
+
+// publishStream is a stream of requests to publish documents, recording the responses
+type publishStream struct {
+	grpc.ServerStream
+	mu        sync.Mutex
+	requests  []*apiv1.PublishDocumentRequest
+	responses []*apiv1.PublishDocumentResponse
+}
+
+func (s *publishStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *publishStream) Recv() (*apiv1.PublishDocumentRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	r := s.requests[0]
+	s.requests = s.requests[1:]
+	return r, nil
+}
+
+func (s *publishStream) Send(r *apiv1.PublishDocumentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func TestPublishDocuments(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	repo := &slowRepository{}
+	ds.RegisterRepository(WCRS, repo)
+	ds.SetParallelism(3)
+	ids := []string{"slow-1", "ok-2", "fail-3", "ok-4", "fail-5", "ok-6", "ok-7", "ok-8"}
+	stream := &publishStream{}
+	for _, id := range ids {
+		stream.requests = append(stream.requests, &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:      &apiv1.Identifier{System: identifiers.UUID, Value: id},
+			Patient: &apiv1.Patient{Lastname: "DUMMY"},
+		}})
+	}
+	if err := ds.PublishDocuments(stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.responses) != len(ids) {
+		t.Fatalf("expected a response for each of %d documents, got %d", len(ids), len(stream.responses))
+	}
+	if max := atomic.LoadInt32(&repo.max); max < 2 || max > 3 {
+		t.Errorf("expected documents published concurrently, up to the configured parallelism of 3. got: %d", max)
+	}
+	if stream.responses[0].GetDocumentId().GetValue() == "slow-1" {
+		t.Errorf("expected responses to be sent as each document is published, not in the order requested")
+	}
+	seen := make(map[string]bool)
+	for _, response := range stream.responses {
+		id := response.GetDocumentId().GetValue()
+		if seen[id] {
+			t.Errorf("duplicate response for document '%s'", id)
+		}
+		seen[id] = true
+		if strings.HasPrefix(id, "fail") {
+			if codes.Code(response.GetErrorCode()) != codes.Unavailable || response.GetError() == "" || response.GetId() != nil {
+				t.Errorf("expected failure to be reported for document '%s', got %v", id, response)
+			}
+		} else if response.GetErrorCode() != 0 || response.GetId().GetValue() != "wcrs-"+id {
+			t.Errorf("expected document '%s' to be published, got %v", id, response)
+		}
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			t.Errorf("no response for document '%s'", id)
+		}
+	}
+}
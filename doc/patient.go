@@ -0,0 +1,73 @@
+package doc
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PatientChannel delivers a published document, or a notification of its publication, directly to the
+// patient, such as by secure email link or via a patient portal
+type PatientChannel interface {
+	// SendToPatient sends the document to the patient, returning the recipient (e.g. email address) and the
+	// identifier of the message sent
+	SendToPatient(ctx context.Context, d *apiv1.Document) (recipient string, messageID *apiv1.Identifier, err error)
+}
+
+// RegisterPatientChannel registers a named channel used to deliver published documents to patients.
+// Documents are only delivered to patients if they have explicitly consented, as recorded in the document,
+// and if permitted by any registered consent checkers, using the channel name as the repository.
+// This should not be called once server is running.
+func (ds *DocumentService) RegisterPatientChannel(name string, c PatientChannel) {
+	ds.patients[name] = c
+	log.Printf("doc: registered patient delivery channel: '%s'", name)
+}
+
+// sendToPatient delivers the document to the patient using each registered channel, if the patient has
+// consented, returning the deliveries attempted.
+// Failure to deliver does not fail publication, but is recorded and an event published.
+func (ds *DocumentService) sendToPatient(ctx context.Context, r *apiv1.PublishDocumentRequest) []*apiv1.Delivery {
+	if !r.GetDocument().GetPatientDelivery() || len(ds.patients) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(ds.patients))
+	for name := range ds.patients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result []*apiv1.Delivery
+	for _, name := range names {
+		d := &apiv1.Delivery{Repository: name, Status: apiv1.Delivery_PENDING, Updated: timestamppb.Now()}
+		err := ds.checkConsent(ctx, r.GetDocument(), name)
+		sent := r
+		if err == nil && ds.pdfOnly[name] {
+			sent, err = ds.renderPDF(ctx, r)
+		}
+		if err == nil {
+			d.Recipient, d.MessageId, err = ds.patients[name].SendToPatient(ctx, sent.GetDocument())
+		}
+		if err != nil {
+			log.Printf("doc: failed to send document %s|%s to patient via '%s': %s", r.GetDocument().GetId().GetSystem(), r.GetDocument().GetId().GetValue(), name, err)
+			d.Status = apiv1.Delivery_FAILED
+			d.Error = status.Convert(err).Message()
+			events.Publish(&events.Event{Type: events.DeliveryFailed, Subject: r.GetDocument().GetId(), Error: d.Error})
+		} else {
+			d.Status = apiv1.Delivery_SENT
+		}
+		ds.recordDelivery(r.GetDocument().GetId(), d)
+		result = append(result, proto.Clone(d).(*apiv1.Delivery))
+	}
+	return result
+}
+
+// needsEmails returns whether the patient's email addresses are needed for delivery to the patient,
+// but not included in the document
+func (ds *DocumentService) needsEmails(d *apiv1.Document) bool {
+	return d.GetPatientDelivery() && len(ds.patients) > 0 && len(d.GetPatient().GetEmails()) == 0
+}
@@ -0,0 +1,168 @@
+package doc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq" // postgresql driver
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type dbQueue struct {
+	db *sql.DB
+}
+
+// createQueueTable creates the table of queued documents, if it does not already exist.
+// The original request, and the response once published, are stored as serialised protobuf messages.
+const createQueueTable = `CREATE TABLE IF NOT EXISTS document_queue (
+	id text PRIMARY KEY,
+	request bytea NOT NULL,
+	attempts integer NOT NULL,
+	created timestamptz NOT NULL,
+	next_attempt timestamptz NOT NULL,
+	last_error text NOT NULL,
+	dead_letter boolean NOT NULL DEFAULT false,
+	receipt text NOT NULL DEFAULT '',
+	response bytea,
+	tenant text NOT NULL DEFAULT ''
+);
+ALTER TABLE document_queue ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS document_queue_receipt_idx ON document_queue (receipt)`
+
+// NewDatabaseQueue creates a durable queue of documents in a PostgreSQL database. The queue
+// may be shared by more than one server; each queued document is retried by only one server at a time.
+func NewDatabaseQueue(connStr string) (Queue, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createQueueTable); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &dbQueue{db: db}, nil
+}
+
+func (dq *dbQueue) Enqueue(ctx context.Context, pd *apiv1.PendingDocument) error {
+	b, err := proto.Marshal(pd.GetRequest())
+	if err != nil {
+		return err
+	}
+	if err := pd.GetCreated().CheckValid(); err != nil {
+		return err
+	}
+	if err := pd.GetNextAttempt().CheckValid(); err != nil {
+		return err
+	}
+	created, next := pd.GetCreated().AsTime(), pd.GetNextAttempt().AsTime()
+	_, err = dq.db.ExecContext(ctx, `INSERT INTO document_queue (id, request, attempts, created, next_attempt, last_error, dead_letter, receipt, tenant)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET request=EXCLUDED.request, attempts=EXCLUDED.attempts, created=EXCLUDED.created,
+		next_attempt=EXCLUDED.next_attempt, last_error=EXCLUDED.last_error, dead_letter=EXCLUDED.dead_letter,
+		receipt=EXCLUDED.receipt, response=NULL, tenant=EXCLUDED.tenant`,
+		deliveryKey(pd.GetDocumentId()), b, pd.GetAttempts(), created, next, pd.GetLastError(), pd.GetDeadLetter(), pd.GetReceipt().GetValue(), pd.GetTenant())
+	return err
+}
+
+func (dq *dbQueue) Claim(ctx context.Context, max int, lease time.Duration) ([]*apiv1.PendingDocument, error) {
+	if _, err := dq.db.ExecContext(ctx, `DELETE FROM document_queue WHERE response IS NOT NULL
+		AND next_attempt < now() - $1 * interval '1 second'`, int(completedTTL.Seconds())); err != nil {
+		return nil, err
+	}
+	rows, err := dq.db.QueryContext(ctx, `UPDATE document_queue SET next_attempt = now() + $2 * interval '1 second'
+		WHERE id IN (SELECT id FROM document_queue WHERE NOT dead_letter AND response IS NULL AND next_attempt <= now()
+			ORDER BY next_attempt LIMIT $1 FOR UPDATE SKIP LOCKED)
+		RETURNING `+pendingDocumentColumns, max, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return scanPendingDocuments(rows)
+}
+
+func (dq *dbQueue) Update(ctx context.Context, pd *apiv1.PendingDocument) error {
+	if err := pd.GetNextAttempt().CheckValid(); err != nil {
+		return err
+	}
+	next := pd.GetNextAttempt().AsTime()
+	_, err := dq.db.ExecContext(ctx, "UPDATE document_queue SET attempts=$2, next_attempt=$3, last_error=$4, dead_letter=$5 WHERE id=$1",
+		deliveryKey(pd.GetDocumentId()), pd.GetAttempts(), next, pd.GetLastError(), pd.GetDeadLetter())
+	return err
+}
+
+func (dq *dbQueue) Complete(ctx context.Context, pd *apiv1.PendingDocument) error {
+	b, err := proto.Marshal(pd.GetResponse())
+	if err != nil {
+		return err
+	}
+	if err := pd.GetNextAttempt().CheckValid(); err != nil {
+		return err
+	}
+	next := pd.GetNextAttempt().AsTime()
+	_, err = dq.db.ExecContext(ctx, "UPDATE document_queue SET attempts=$2, next_attempt=$3, last_error='', response=$4 WHERE id=$1",
+		deliveryKey(pd.GetDocumentId()), pd.GetAttempts(), next, b)
+	return err
+}
+
+func (dq *dbQueue) Get(ctx context.Context, receipt *apiv1.Identifier) (*apiv1.PendingDocument, error) {
+	rows, err := dq.db.QueryContext(ctx, "SELECT "+pendingDocumentColumns+" FROM document_queue WHERE receipt=$1", receipt.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	pds, err := scanPendingDocuments(rows)
+	if err != nil || len(pds) == 0 {
+		return nil, err
+	}
+	return pds[0], nil
+}
+
+func (dq *dbQueue) List(ctx context.Context, deadLettersOnly bool) ([]*apiv1.PendingDocument, error) {
+	rows, err := dq.db.QueryContext(ctx, "SELECT "+pendingDocumentColumns+` FROM document_queue
+		WHERE response IS NULL AND (dead_letter OR NOT $1) ORDER BY created`, deadLettersOnly)
+	if err != nil {
+		return nil, err
+	}
+	return scanPendingDocuments(rows)
+}
+
+func (dq *dbQueue) Close() error {
+	return dq.db.Close()
+}
+
+// pendingDocumentColumns are the columns read by scanPendingDocuments
+const pendingDocumentColumns = "request, attempts, created, next_attempt, last_error, dead_letter, receipt, response, tenant"
+
+// scanPendingDocuments reads queued documents from the rows specified, closing the rows
+func scanPendingDocuments(rows *sql.Rows) ([]*apiv1.PendingDocument, error) {
+	defer rows.Close()
+	result := make([]*apiv1.PendingDocument, 0)
+	for rows.Next() {
+		var b, response []byte
+		var receipt string
+		var created, next time.Time
+		pd := &apiv1.PendingDocument{Request: &apiv1.PublishDocumentRequest{}}
+		if err := rows.Scan(&b, &pd.Attempts, &created, &next, &pd.LastError, &pd.DeadLetter, &receipt, &response, &pd.Tenant); err != nil {
+			return nil, err
+		}
+		if err := proto.Unmarshal(b, pd.Request); err != nil {
+			return nil, err
+		}
+		if response != nil {
+			pd.Response = &apiv1.PublishDocumentResponse{}
+			if err := proto.Unmarshal(response, pd.Response); err != nil {
+				return nil, err
+			}
+		}
+		if receipt != "" {
+			pd.Receipt = &apiv1.Identifier{System: identifiers.ConciergeReceipt, Value: receipt}
+		}
+		pd.DocumentId = pd.GetRequest().GetDocument().GetId()
+		pd.Created = timestamppb.New(created)
+		pd.NextAttempt = timestamppb.New(next)
+		result = append(result, pd)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,378 @@
+package doc
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/paging"
+	"github.com/wardle/concierge/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Queue is a durable queue of documents awaiting retry after failed publication
+type Queue interface {
+	// Enqueue adds a document to the queue, replacing any existing entry for the same document
+	Enqueue(ctx context.Context, pd *apiv1.PendingDocument) error
+	// Claim returns up to max documents due for retry, deferring their next attempt by the lease
+	// specified so that they are not claimed again while being processed
+	Claim(ctx context.Context, max int, lease time.Duration) ([]*apiv1.PendingDocument, error)
+	// Update updates the attempts, next attempt, error and dead letter status of a queued document
+	Update(ctx context.Context, pd *apiv1.PendingDocument) error
+	// Complete records that a queued document has been published, using the response in the document specified.
+	// Published documents are no longer claimed or listed, but are retained for a time so their status can be polled.
+	Complete(ctx context.Context, pd *apiv1.PendingDocument) error
+	// Get returns the queued or published document with the receipt specified, or nil if not found
+	Get(ctx context.Context, receipt *apiv1.Identifier) (*apiv1.PendingDocument, error)
+	// List returns queued documents that have not been published, in order of creation
+	List(ctx context.Context, deadLettersOnly bool) ([]*apiv1.PendingDocument, error)
+	Close() error
+}
+
+// RetryOptions configures the retry of failed publications
+type RetryOptions struct {
+	MaxAttempts int           // attempts before a document is abandoned as a dead letter
+	Interval    time.Duration // delay before the first retry, doubled with each subsequent attempt
+}
+
+// Defaults for the retry of failed publications
+const (
+	DefaultMaxAttempts   = 10
+	DefaultRetryInterval = time.Minute
+	maxRetryInterval     = 6 * time.Hour   // maximum delay between attempts
+	retryLease           = 5 * time.Minute // time allowed to retry a batch before documents may be claimed again
+	retryBatchSize       = 20
+	completedTTL         = 7 * 24 * time.Hour // time for which the status of published documents is retained
+	DefaultDrainTimeout  = 30 * time.Second   // time permitted on close for queued publications in progress to complete
+)
+
+// SetRetryQueue configures the retry of failed publications using the queue specified, and starts
+// a background worker to retry queued documents. Documents that fail publication because a repository
+// is unavailable are queued, and the caller is told the document has been queued, rather than given an error.
+// This should not be called once server is running.
+func (ds *DocumentService) SetRetryQueue(q Queue, opts RetryOptions) {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultRetryInterval
+	}
+	ds.queue = q
+	ds.retry = opts
+	ds.done = make(chan struct{})
+	ds.wake = make(chan struct{}, 1)
+	ds.stopped = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	ds.cancel = cancel
+	go ds.retryEvery(ctx, opts.Interval)
+	log.Printf("doc: retrying failed publications up to %d times", opts.MaxAttempts)
+}
+
+// SetDrainTimeout sets the time permitted on close for publications of queued documents already in progress
+// to complete, before they are cancelled and returned to the queue. DefaultDrainTimeout if zero.
+// This should not be called once server is running.
+func (ds *DocumentService) SetDrainTimeout(d time.Duration) {
+	ds.drain = d
+}
+
+func (ds *DocumentService) drainTimeout() time.Duration {
+	if ds.drain > 0 {
+		return ds.drain
+	}
+	return DefaultDrainTimeout
+}
+
+// retryable returns whether a publication that failed with the error specified may succeed if retried
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// enqueue queues a document for publication, returning a response with a receipt that can be used to poll for its status.
+// If an error is specified, publication has already been attempted and failed, so the document is queued for retry,
+// otherwise the document is published as soon as possible.
+func (ds *DocumentService) enqueue(ctx context.Context, r *apiv1.PublishDocumentRequest, err error) (*apiv1.PublishDocumentResponse, error) {
+	now := time.Now()
+	created := timestamppb.New(now)
+	pd := &apiv1.PendingDocument{
+		DocumentId:  r.GetDocument().GetId(),
+		Request:     r,
+		Created:     created,
+		NextAttempt: created,
+		Receipt:     &apiv1.Identifier{System: identifiers.ConciergeReceipt, Value: uuid.New().String()},
+		Tenant:      tenant.FromContext(ctx),
+	}
+	if err != nil {
+		pd.Attempts = 1
+		pd.LastError = status.Convert(err).Message()
+		pd.NextAttempt = timestamppb.New(now.Add(ds.retry.Interval))
+	}
+	if err := ds.queue.Enqueue(ctx, pd); err != nil {
+		return nil, err
+	}
+	response := &apiv1.PublishDocumentResponse{DocumentId: pd.GetDocumentId(), Queued: true, Receipt: pd.GetReceipt()}
+	ds.recordReceipt(ctx, r, response)
+	if err != nil {
+		log.Printf("doc: queued document %s|%s for retry: %s", pd.GetDocumentId().GetSystem(), pd.GetDocumentId().GetValue(), pd.GetLastError())
+	} else {
+		log.Printf("doc: queued document %s|%s for publication", pd.GetDocumentId().GetSystem(), pd.GetDocumentId().GetValue())
+		select {
+		case ds.wake <- struct{}{}:
+		default: // worker already due to run
+		}
+	}
+	return response, nil
+}
+
+// GetPublicationStatus returns the status of publication of a queued document, using its receipt
+func (ds *DocumentService) GetPublicationStatus(ctx context.Context, receipt *apiv1.Identifier) (*apiv1.PublicationStatus, error) {
+	if receipt.GetSystem() != identifiers.ConciergeReceipt || receipt.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "invalid publication receipt: %s|%s", receipt.GetSystem(), receipt.GetValue())
+	}
+	var pd *apiv1.PendingDocument
+	var err error
+	if ds.queue != nil {
+		if pd, err = ds.queue.Get(ctx, receipt); err != nil {
+			return nil, err
+		}
+	}
+	if pd == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "publication receipt not found: %s|%s", receipt.GetSystem(), receipt.GetValue())
+	}
+	result := &apiv1.PublicationStatus{
+		Receipt:    pd.GetReceipt(),
+		DocumentId: pd.GetDocumentId(),
+		Attempts:   pd.GetAttempts(),
+		Response:   pd.GetResponse(),
+		Updated:    pd.GetNextAttempt(),
+	}
+	switch {
+	case pd.GetResponse() != nil:
+		result.Status = apiv1.PublicationStatus_PUBLISHED
+	case pd.GetDeadLetter():
+		result.Status = apiv1.PublicationStatus_FAILED
+		result.Error = pd.GetLastError()
+	default:
+		result.Status = apiv1.PublicationStatus_PENDING
+		result.Error = pd.GetLastError()
+	}
+	return result, nil
+}
+
+// ListPendingDocuments returns the page requested of the documents queued for retry, in order of creation, without their content
+func (ds *DocumentService) ListPendingDocuments(ctx context.Context, r *apiv1.ListPendingDocumentsRequest) (*apiv1.PendingDocuments, error) {
+	page, err := paging.Parse(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if ds.queue == nil {
+		return &apiv1.PendingDocuments{}, nil
+	}
+	pds, err := ds.queue.List(ctx, r.GetDeadLettersOnly())
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.Bounds(len(pds))
+	result := &apiv1.PendingDocuments{Documents: pds[start:end], NextPageToken: page.NextToken(len(pds))}
+	for _, pd := range result.Documents {
+		pd.Request = nil
+	}
+	return result, nil
+}
+
+// retryEvery retries due documents at the interval specified, until closed
+func (ds *DocumentService) retryEvery(ctx context.Context, interval time.Duration) {
+	defer close(ds.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ds.done:
+			return
+		case <-ticker.C:
+			ds.retryDue(ctx)
+		case <-ds.wake:
+			ds.retryDue(ctx)
+		}
+	}
+}
+
+// retryDue retries publication of the documents that are due
+func (ds *DocumentService) retryDue(ctx context.Context) {
+	pds, err := ds.queue.Claim(ctx, retryBatchSize, retryLease)
+	if err != nil {
+		log.Printf("doc: failed to fetch queued documents: %s", err)
+		return
+	}
+	for _, pd := range pds {
+		ds.retryDocument(ctx, pd)
+	}
+}
+
+// retryDocument retries publication of a single queued document, on behalf of the tenant that published it,
+// recording its publication on success, or scheduling another attempt with exponential backoff until the
+// maximum attempts are exceeded
+func (ds *DocumentService) retryDocument(ctx context.Context, pd *apiv1.PendingDocument) {
+	id := pd.GetDocumentId()
+	ctx = tenant.NewContext(ctx, pd.GetTenant())
+	if ctx.Err() != nil {
+		ds.release(pd)
+		return
+	}
+	response, err := ds.publishDocument(ctx, pd.GetRequest())
+	if err != nil && ctx.Err() != nil { // cancelled on shutdown, rather than failed
+		ds.release(pd)
+		return
+	}
+	pd.Attempts++
+	if err == nil {
+		log.Printf("doc: published queued document %s|%s after %d attempts", id.GetSystem(), id.GetValue(), pd.GetAttempts())
+		ds.recordReceipt(ctx, pd.GetRequest(), response)
+		ds.published(pd.GetRequest().GetDocument(), response)
+		pd.Response = response
+		pd.LastError = ""
+		pd.NextAttempt = timestamppb.Now()
+		if err := ds.queue.Complete(ctx, pd); err != nil {
+			log.Printf("doc: failed to record publication of queued document %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+		}
+		return
+	}
+	pd.LastError = status.Convert(err).Message()
+	if !retryable(err) || int(pd.GetAttempts()) >= ds.retry.MaxAttempts {
+		pd.DeadLetter = true
+		ds.forgetReceipt(ctx, pd.GetRequest())
+		log.Printf("doc: abandoned publication of document %s|%s after %d attempts: %s", id.GetSystem(), id.GetValue(), pd.GetAttempts(), pd.GetLastError())
+		events.Publish(&events.Event{Type: events.DeliveryFailed, Subject: id, Error: pd.GetLastError()})
+	} else {
+		pd.NextAttempt = timestamppb.New(time.Now().Add(backoff(ds.retry.Interval, int(pd.GetAttempts()))))
+	}
+	if err := ds.queue.Update(ctx, pd); err != nil {
+		log.Printf("doc: failed to update queued document %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+	}
+}
+
+// release returns a claimed document to the queue without counting an attempt, so that it is due again
+// immediately, such as when its publication is cancelled on shutdown
+func (ds *DocumentService) release(pd *apiv1.PendingDocument) {
+	id := pd.GetDocumentId()
+	pd.NextAttempt = timestamppb.Now()
+	if err := ds.queue.Update(context.Background(), pd); err != nil {
+		log.Printf("doc: failed to return document %s|%s to queue: %s", id.GetSystem(), id.GetValue(), err)
+		return
+	}
+	log.Printf("doc: returned document %s|%s to queue on shutdown", id.GetSystem(), id.GetValue())
+}
+
+// backoff returns the delay before the next attempt, doubling the interval for each attempt made
+func backoff(interval time.Duration, attempts int) time.Duration {
+	d := interval
+	for i := 1; i < attempts && d < maxRetryInterval; i++ {
+		d *= 2
+	}
+	if d > maxRetryInterval {
+		d = maxRetryInterval
+	}
+	return d
+}
+
+// memoryQueue is a non-durable queue, useful in testing or when no database is available
+type memoryQueue struct {
+	mu    sync.Mutex
+	items map[string]*apiv1.PendingDocument
+}
+
+// NewMemoryQueue creates a queue that keeps documents in memory; these are lost on restart
+func NewMemoryQueue() Queue {
+	return &memoryQueue{items: make(map[string]*apiv1.PendingDocument)}
+}
+
+func (mq *memoryQueue) Enqueue(ctx context.Context, pd *apiv1.PendingDocument) error {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	mq.items[deliveryKey(pd.GetDocumentId())] = proto.Clone(pd).(*apiv1.PendingDocument)
+	return nil
+}
+
+func (mq *memoryQueue) Claim(ctx context.Context, max int, lease time.Duration) ([]*apiv1.PendingDocument, error) {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	now := time.Now()
+	next := timestamppb.New(now.Add(lease))
+	result := make([]*apiv1.PendingDocument, 0)
+	for _, pd := range mq.sorted() {
+		valid, t := pd.GetNextAttempt().CheckValid() == nil, pd.GetNextAttempt().AsTime()
+		if pd.GetResponse() != nil && valid && now.Sub(t) > completedTTL {
+			delete(mq.items, deliveryKey(pd.GetDocumentId()))
+			continue
+		}
+		if len(result) >= max || pd.GetDeadLetter() || pd.GetResponse() != nil || (valid && t.After(now)) {
+			continue
+		}
+		pd.NextAttempt = next
+		result = append(result, proto.Clone(pd).(*apiv1.PendingDocument))
+	}
+	return result, nil
+}
+
+func (mq *memoryQueue) Update(ctx context.Context, pd *apiv1.PendingDocument) error {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	if _, found := mq.items[deliveryKey(pd.GetDocumentId())]; found {
+		mq.items[deliveryKey(pd.GetDocumentId())] = proto.Clone(pd).(*apiv1.PendingDocument)
+	}
+	return nil
+}
+
+func (mq *memoryQueue) Complete(ctx context.Context, pd *apiv1.PendingDocument) error {
+	return mq.Update(ctx, pd)
+}
+
+func (mq *memoryQueue) Get(ctx context.Context, receipt *apiv1.Identifier) (*apiv1.PendingDocument, error) {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	for _, pd := range mq.items {
+		if pd.GetReceipt().GetValue() == receipt.GetValue() {
+			return proto.Clone(pd).(*apiv1.PendingDocument), nil
+		}
+	}
+	return nil, nil
+}
+
+func (mq *memoryQueue) List(ctx context.Context, deadLettersOnly bool) ([]*apiv1.PendingDocument, error) {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	result := make([]*apiv1.PendingDocument, 0, len(mq.items))
+	for _, pd := range mq.sorted() {
+		if pd.GetResponse() == nil && (!deadLettersOnly || pd.GetDeadLetter()) {
+			result = append(result, proto.Clone(pd).(*apiv1.PendingDocument))
+		}
+	}
+	return result, nil
+}
+
+// sorted returns queued documents in order of creation. Caller must hold lock.
+func (mq *memoryQueue) sorted() []*apiv1.PendingDocument {
+	result := make([]*apiv1.PendingDocument, 0, len(mq.items))
+	for _, pd := range mq.items {
+		result = append(result, pd)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetCreated().AsTime().Before(result[j].GetCreated().AsTime())
+	})
+	return result
+}
+
+func (mq *memoryQueue) Close() error { return nil }
@@ -0,0 +1,183 @@
+package doc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryQueue(t *testing.T) {
+	ctx := context.Background()
+	repo := &testRepository{err: status.Error(codes.Unavailable, "repository unavailable")}
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, repo)
+	ds.SetRetryQueue(NewMemoryQueue(), RetryOptions{MaxAttempts: 3, Interval: time.Hour})
+	defer ds.Close()
+	publish := func(id string) *apiv1.PublishDocumentResponse {
+		response, err := ds.PublishDocument(ctx, &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: &apiv1.Identifier{System: identifiers.UUID, Value: id}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	pending := func(deadLettersOnly bool) []*apiv1.PendingDocument {
+		pds, err := ds.queue.List(ctx, deadLettersOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pds
+	}
+	if response := publish("doc1"); !response.GetQueued() || response.GetDocumentId().GetValue() != "doc1" {
+		t.Fatalf("expected document to be queued, got %v", response)
+	}
+	listed, err := ds.ListPendingDocuments(ctx, &apiv1.ListPendingDocumentsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.GetDocuments()) != 1 || listed.GetDocuments()[0].GetRequest() != nil || listed.GetDocuments()[0].GetAttempts() != 1 {
+		t.Fatalf("expected a single pending document without content, got %v", listed)
+	}
+	// retries until the maximum number of attempts, then abandoned as a dead letter
+	for i := 0; i < 2; i++ {
+		ds.retryDocument(ctx, pending(false)[0])
+	}
+	if dead := pending(true); len(dead) != 1 || dead[0].GetAttempts() != 3 || dead[0].GetLastError() != "repository unavailable" {
+		t.Fatalf("expected document to be a dead letter after 3 attempts, got %v", dead)
+	}
+	if claimed, err := ds.queue.Claim(ctx, 10, time.Minute); err != nil || len(claimed) != 0 {
+		t.Fatalf("expected no documents to be due for retry, got %v (%v)", claimed, err)
+	}
+	// successful retry removes document from pending documents
+	publish("doc2")
+	repo.err = nil
+	for _, pd := range pending(false) {
+		if pd.GetDocumentId().GetValue() == "doc2" {
+			ds.retryDocument(ctx, pd)
+		}
+	}
+	if pds := pending(false); len(pds) != 1 || pds[0].GetDocumentId().GetValue() != "doc1" {
+		t.Fatalf("expected published document to be removed from queue, got %v", pds)
+	}
+	// non-retryable errors are returned to the caller
+	repo.err = status.Error(codes.InvalidArgument, "invalid document")
+	if _, err := ds.PublishDocument(ctx, &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: &apiv1.Identifier{System: identifiers.UUID, Value: "doc3"}}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+}
+
+func TestAsyncPublication(t *testing.T) {
+	ctx := context.Background()
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &testRepository{})
+	r := &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: &apiv1.Identifier{System: identifiers.UUID, Value: "doc1"}}, Async: true}
+	if _, err := ds.PublishDocument(ctx, r); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected asynchronous publication to fail without a queue, got %v", err)
+	}
+	ds.SetRetryQueue(NewMemoryQueue(), RetryOptions{Interval: time.Hour})
+	defer ds.Close()
+	response, err := ds.PublishDocument(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.GetQueued() || response.GetReceipt().GetSystem() != identifiers.ConciergeReceipt || response.GetReceipt().GetValue() == "" {
+		t.Fatalf("expected document to be queued with a receipt, got %v", response)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ps, err := ds.GetPublicationStatus(ctx, response.GetReceipt())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ps.GetStatus() == apiv1.PublicationStatus_PUBLISHED {
+			if ps.GetResponse().GetId().GetValue() != "msg-doc1" || ps.GetAttempts() != 1 {
+				t.Fatalf("unexpected publication status: %v", ps)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("document not published: %v", ps)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if pds, err := ds.ListPendingDocuments(ctx, &apiv1.ListPendingDocumentsRequest{}); err != nil || len(pds.GetDocuments()) != 0 {
+		t.Fatalf("expected no pending documents, got %v (%v)", pds, err)
+	}
+	if _, err := ds.GetPublicationStatus(ctx, &apiv1.Identifier{System: identifiers.ConciergeReceipt, Value: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found for unknown receipt, got %v", err)
+	}
+}
+
+// blockingRepository is a repository that does not complete publication until released, or cancelled
+type blockingRepository struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (repo *blockingRepository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	repo.started <- struct{}{}
+	select {
+	case <-repo.release:
+		return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.MESHMessageID, Value: "msg-" + r.GetDocument().GetId().GetValue()}}, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func TestDrainOnClose(t *testing.T) {
+	ctx := context.Background()
+	for _, completes := range []bool{true, false} {
+		repo := &blockingRepository{started: make(chan struct{}, 1), release: make(chan struct{})}
+		ds := NewDocumentService(nil, nil)
+		ds.RegisterRepository(WCRS, repo)
+		q := NewMemoryQueue()
+		ds.SetRetryQueue(q, RetryOptions{Interval: time.Hour})
+		ds.SetDrainTimeout(100 * time.Millisecond)
+		r := &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: &apiv1.Identifier{System: identifiers.UUID, Value: "doc1"}}, Async: true}
+		response, err := ds.PublishDocument(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		<-repo.started // publication by the worker now in progress
+		if completes {
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				close(repo.release)
+			}()
+		}
+		if err := ds.Close(); err != nil {
+			t.Fatal(err)
+		}
+		pd, err := q.Get(ctx, response.GetReceipt())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if completes && pd.GetResponse().GetId().GetValue() != "msg-doc1" {
+			t.Fatalf("expected publication in progress to complete before close, got %v", pd)
+		}
+		if !completes && (pd.GetResponse() != nil || pd.GetAttempts() != 0 || pd.GetDeadLetter()) {
+			t.Fatalf("expected cancelled publication to be returned to the queue without counting an attempt, got %v", pd)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{20, maxRetryInterval},
+	}
+	for _, test := range tests {
+		if d := backoff(time.Minute, test.attempts); d != test.expected {
+			t.Errorf("backoff after %d attempts: expected %s, got %s", test.attempts, test.expected, d)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package doc
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/lib/pq" // postgresql driver
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/proto"
+)
+
+type dbReceipts struct {
+	db *sql.DB
+}
+
+// createReceiptsTable creates the table of publication receipts, if it does not already exist.
+// Each document version, including the response, is stored as a serialised protobuf message.
+const createReceiptsTable = `CREATE TABLE IF NOT EXISTS document_receipts (
+	id text PRIMARY KEY,
+	version bytea NOT NULL,
+	updated timestamptz NOT NULL DEFAULT now()
+)`
+
+// NewDatabaseReceiptStore creates a durable receipt store in a PostgreSQL database, which may be shared
+// by more than one server, so that re-submitted documents are detected whichever server receives them.
+func NewDatabaseReceiptStore(connStr string) (ReceiptStore, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createReceiptsTable); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &dbReceipts{db: db}, nil
+}
+
+func (dr *dbReceipts) Get(ctx context.Context, id *apiv1.Identifier) (*apiv1.DocumentVersion, error) {
+	var b []byte
+	err := dr.db.QueryRowContext(ctx, "SELECT version FROM document_receipts WHERE id=$1", deliveryKey(id)).Scan(&b)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v := &apiv1.DocumentVersion{}
+	if err := proto.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (dr *dbReceipts) Put(ctx context.Context, v *apiv1.DocumentVersion) error {
+	b, err := proto.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = dr.db.ExecContext(ctx, `INSERT INTO document_receipts (id, version) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET version=EXCLUDED.version, updated=now()`, deliveryKey(v.GetDocumentId()), b)
+	return err
+}
+
+func (dr *dbReceipts) Delete(ctx context.Context, id *apiv1.Identifier) error {
+	_, err := dr.db.ExecContext(ctx, "DELETE FROM document_receipts WHERE id=$1", deliveryKey(id))
+	return err
+}
+
+func (dr *dbReceipts) Close() error {
+	return dr.db.Close()
+}
@@ -0,0 +1,226 @@
+package doc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReceiptStore stores the publication of each document, including the response and its place in the history
+// of versions of the document, keyed by document identifier, so that a document re-submitted with the same
+// identifier is not published again, and so that documents may be superseded by amended versions
+type ReceiptStore interface {
+	// Get returns the version recorded for the document with the identifier specified, or nil if not found
+	Get(ctx context.Context, id *apiv1.Identifier) (*apiv1.DocumentVersion, error)
+	// Put records the version of the document, replacing any existing record for the same document identifier
+	Put(ctx context.Context, v *apiv1.DocumentVersion) error
+	// Delete removes the record for the document with the identifier specified, if any
+	Delete(ctx context.Context, id *apiv1.Identifier) error
+	Close() error
+}
+
+// receiptTTL is the time for which receipts are retained by the in-memory receipt store
+const receiptTTL = 30 * 24 * time.Hour
+
+// maxVersions is the maximum number of versions of a document, limiting the history traversed
+const maxVersions = 100
+
+// SetReceiptStore sets the store used to record the response to publication of each document. A document that
+// is re-submitted with the same identifier is not published again, unless forced, and the original response is
+// returned instead, so that clients may safely retry requests without creating duplicates in repositories.
+// This should not be called once server is running.
+func (ds *DocumentService) SetReceiptStore(rs ReceiptStore) {
+	ds.receipts = rs
+	ds.publishing = make(map[string]chan struct{})
+}
+
+// lockDocument waits until no other request is publishing the document with the identifier specified,
+// returning a function to release the lock, so that concurrent duplicate requests are published only once
+func (ds *DocumentService) lockDocument(ctx context.Context, id *apiv1.Identifier) (func(), error) {
+	key := deliveryKey(id)
+	for {
+		ds.publishingMu.Lock()
+		wait, busy := ds.publishing[key]
+		if !busy {
+			done := make(chan struct{})
+			ds.publishing[key] = done
+			ds.publishingMu.Unlock()
+			return func() {
+				ds.publishingMu.Lock()
+				delete(ds.publishing, key)
+				ds.publishingMu.Unlock()
+				close(done)
+			}, nil
+		}
+		ds.publishingMu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// duplicate returns the original response if the document in the request has already been published, or nil if not
+func (ds *DocumentService) duplicate(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	id := r.GetDocument().GetId()
+	v, err := ds.receipts.Get(ctx, id)
+	if err != nil || v.GetResponse() == nil {
+		return nil, err
+	}
+	log.Printf("doc: document %s|%s already published; returning original response", id.GetSystem(), id.GetValue())
+	response := v.GetResponse()
+	response.DocumentId = id
+	response.Duplicate = true
+	return response, nil
+}
+
+// previousVersions returns the previous versions of a document replacing the document specified, most recent first.
+// An error is returned if the document replaced has not been published, or has already been replaced by another document.
+func (ds *DocumentService) previousVersions(ctx context.Context, id *apiv1.Identifier, replaces *apiv1.Identifier) ([]*apiv1.DocumentVersion, error) {
+	if ds.receipts == nil {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "replacing a document requires a receipt store")
+	}
+	previous, err := ds.receipts.Get(ctx, replaces)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "document not found: %s|%s", replaces.GetSystem(), replaces.GetValue())
+	}
+	if next := previous.GetReplacedBy(); next != nil && deliveryKey(next) != deliveryKey(id) {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "document %s|%s has already been replaced by %s|%s", replaces.GetSystem(), replaces.GetValue(), next.GetSystem(), next.GetValue())
+	}
+	result := []*apiv1.DocumentVersion{previous}
+	for v := previous; v.GetReplaces() != nil && len(result) < maxVersions; {
+		if v, err = ds.receipts.Get(ctx, v.GetReplaces()); err != nil || v == nil {
+			return result, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// recordReceipt records the publication of a document, and that it replaces any previous version, logging any failure,
+// as the document has already been published
+func (ds *DocumentService) recordReceipt(ctx context.Context, r *apiv1.PublishDocumentRequest, response *apiv1.PublishDocumentResponse) {
+	id := r.GetDocument().GetId()
+	if ds.receipts == nil || id.GetValue() == "" {
+		return
+	}
+	v := &apiv1.DocumentVersion{
+		DocumentId: id,
+		Version:    int32(len(r.GetPreviousVersions()) + 1),
+		Replaces:   r.GetReplaces(),
+		Published:  timestamppb.Now(),
+		Response:   response,
+	}
+	if existing, err := ds.receipts.Get(ctx, id); err == nil && existing != nil {
+		v.ReplacedBy = existing.GetReplacedBy()
+	}
+	if err := ds.receipts.Put(ctx, v); err != nil {
+		log.Printf("doc: failed to record receipt for document %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+	}
+	if len(r.GetPreviousVersions()) > 0 {
+		previous := r.GetPreviousVersions()[0]
+		previous.ReplacedBy = id
+		if err := ds.receipts.Put(ctx, previous); err != nil {
+			log.Printf("doc: failed to record replacement of document %s|%s: %s", r.GetReplaces().GetSystem(), r.GetReplaces().GetValue(), err)
+		}
+	}
+}
+
+// forgetReceipt removes the record of a document that could not be published, so that it may be re-submitted,
+// and so that any previous version it was to replace may be replaced by another document
+func (ds *DocumentService) forgetReceipt(ctx context.Context, r *apiv1.PublishDocumentRequest) {
+	id := r.GetDocument().GetId()
+	if ds.receipts == nil || id.GetValue() == "" {
+		return
+	}
+	if err := ds.receipts.Delete(ctx, id); err != nil {
+		log.Printf("doc: failed to remove receipt for document %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+	}
+	if previous, err := ds.receipts.Get(ctx, r.GetReplaces()); err == nil && previous != nil && deliveryKey(previous.GetReplacedBy()) == deliveryKey(id) {
+		previous.ReplacedBy = nil
+		if err := ds.receipts.Put(ctx, previous); err != nil {
+			log.Printf("doc: failed to update document %s|%s: %s", r.GetReplaces().GetSystem(), r.GetReplaces().GetValue(), err)
+		}
+	}
+}
+
+// GetDocumentHistory returns the versions of the document with the identifier specified, including any versions
+// it replaced and by which it has been replaced, oldest first
+func (ds *DocumentService) GetDocumentHistory(ctx context.Context, id *apiv1.Identifier) (*apiv1.DocumentHistory, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	if ds.receipts == nil {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "document history requires a receipt store")
+	}
+	v, err := ds.receipts.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "document not found: %s|%s", id.GetSystem(), id.GetValue())
+	}
+	versions := []*apiv1.DocumentVersion{v}
+	for first := v; first.GetReplaces() != nil && len(versions) < maxVersions; {
+		if first, err = ds.receipts.Get(ctx, first.GetReplaces()); err != nil {
+			return nil, err
+		}
+		if first == nil {
+			break
+		}
+		versions = append([]*apiv1.DocumentVersion{first}, versions...)
+	}
+	for last := v; last.GetReplacedBy() != nil && len(versions) < maxVersions; {
+		if last, err = ds.receipts.Get(ctx, last.GetReplacedBy()); err != nil {
+			return nil, err
+		}
+		if last == nil {
+			break
+		}
+		versions = append(versions, last)
+	}
+	return &apiv1.DocumentHistory{Versions: versions}, nil
+}
+
+// memoryReceipts is a non-durable receipt store, useful in testing or when no database is available
+type memoryReceipts struct {
+	receipts *cache.Cache
+}
+
+// NewMemoryReceiptStore creates a receipt store that keeps receipts in memory, for a limited time; these are lost on restart
+func NewMemoryReceiptStore() ReceiptStore {
+	return &memoryReceipts{receipts: cache.New(receiptTTL, time.Hour)}
+}
+
+func (mr *memoryReceipts) Get(ctx context.Context, id *apiv1.Identifier) (*apiv1.DocumentVersion, error) {
+	if o, found := mr.receipts.Get(deliveryKey(id)); found {
+		return proto.Clone(o.(*apiv1.DocumentVersion)).(*apiv1.DocumentVersion), nil
+	}
+	return nil, nil
+}
+
+func (mr *memoryReceipts) Put(ctx context.Context, v *apiv1.DocumentVersion) error {
+	mr.receipts.SetDefault(deliveryKey(v.GetDocumentId()), proto.Clone(v))
+	return nil
+}
+
+func (mr *memoryReceipts) Delete(ctx context.Context, id *apiv1.Identifier) error {
+	mr.receipts.Delete(deliveryKey(id))
+	return nil
+}
+
+func (mr *memoryReceipts) Close() error {
+	return nil
+}
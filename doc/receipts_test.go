@@ -0,0 +1,124 @@
+package doc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type countingRepository struct {
+	count int32
+}
+
+func (repo *countingRepository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	atomic.AddInt32(&repo.count, 1)
+	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.WCRSDocumentID, Value: "wcrs-" + r.GetDocument().GetId().GetValue()}}, nil
+}
+
+func TestDuplicateDocuments(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingRepository{}
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, repo)
+	ds.SetReceiptStore(NewMemoryReceiptStore())
+	defer ds.Close()
+	request := func(id string, force bool) *apiv1.PublishDocumentRequest {
+		return &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: &apiv1.Identifier{System: identifiers.UUID, Value: id}}, Force: force}
+	}
+	original, err := ds.PublishDocument(ctx, request("doc1", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original.GetDuplicate() || original.GetId().GetValue() != "wcrs-doc1" {
+		t.Fatalf("unexpected response: %v", original)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := ds.PublishDocument(ctx, request("doc1", false))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !response.GetDuplicate() || response.GetId().GetValue() != "wcrs-doc1" || response.GetDocumentId().GetValue() != "doc1" {
+				t.Errorf("expected original response for duplicate, got %v", response)
+			}
+		}()
+	}
+	wg.Wait()
+	if repo.count != 1 {
+		t.Fatalf("expected document to be published once, published %d times", repo.count)
+	}
+	forced, err := ds.PublishDocument(ctx, request("doc1", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forced.GetDuplicate() || repo.count != 2 {
+		t.Fatalf("expected forced publication, got %v (published %d times)", forced, repo.count)
+	}
+	if _, err := ds.PublishDocument(ctx, request("doc2", false)); err != nil || repo.count != 3 {
+		t.Fatalf("expected different document to be published, got %v (published %d times)", err, repo.count)
+	}
+	if _, err := ds.PublishDocument(ctx, &apiv1.PublishDocumentRequest{Document: &apiv1.Document{}}); err != nil || repo.count != 4 {
+		t.Fatalf("expected document without identifier to be published, got %v (published %d times)", err, repo.count)
+	}
+}
+
+func TestDocumentHistory(t *testing.T) {
+	ctx := context.Background()
+	ds := NewDocumentService(nil, nil)
+	ds.RegisterRepository(WCRS, &countingRepository{})
+	id := func(value string) *apiv1.Identifier {
+		return &apiv1.Identifier{System: identifiers.UUID, Value: value}
+	}
+	request := func(value string, replaces string) *apiv1.PublishDocumentRequest {
+		r := &apiv1.PublishDocumentRequest{Document: &apiv1.Document{Id: id(value)}}
+		if replaces != "" {
+			r.Replaces = id(replaces)
+		}
+		return r
+	}
+	if _, err := ds.PublishDocument(ctx, request("v2", "v1")); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected replacement to require a receipt store, got %v", err)
+	}
+	ds.SetReceiptStore(NewMemoryReceiptStore())
+	if _, err := ds.PublishDocument(ctx, request("v2", "v1")); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected replacement of unknown document to fail, got %v", err)
+	}
+	for _, r := range []*apiv1.PublishDocumentRequest{request("v1", ""), request("v2", "v1"), request("v3", "v2")} {
+		if _, err := ds.PublishDocument(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ds.PublishDocument(ctx, request("other", "v2")); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected replacement of superseded document to fail, got %v", err)
+	}
+	history, err := ds.GetDocumentHistory(ctx, id("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := history.GetVersions()
+	if len(versions) != 3 {
+		t.Fatalf("expected 3 versions, got %v", versions)
+	}
+	for i, v := range versions {
+		if v.GetVersion() != int32(i+1) || v.GetDocumentId().GetValue() != fmt.Sprintf("v%d", i+1) || v.GetResponse().GetId().GetValue() != fmt.Sprintf("wcrs-v%d", i+1) {
+			t.Errorf("unexpected version %d: %v", i+1, v)
+		}
+	}
+	if versions[0].GetReplaces() != nil || versions[0].GetReplacedBy().GetValue() != "v2" || versions[2].GetReplaces().GetValue() != "v2" || versions[2].GetReplacedBy() != nil {
+		t.Errorf("unexpected version history: %v", versions)
+	}
+	if _, err := ds.GetDocumentHistory(ctx, id("unknown")); status.Code(err) != codes.NotFound {
+		t.Errorf("expected unknown document to be not found, got %v", err)
+	}
+}
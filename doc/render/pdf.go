@@ -0,0 +1,148 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Page layout, in points, for A4 paper
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 56
+	fontSize   = 11
+	leading    = 14
+)
+
+// linesPerPage is the number of lines of text on each page
+const linesPerPage = (pageHeight - 2*margin) / leading
+
+// helveticaWidths are the widths of printable ASCII characters (32-126) in the standard Helvetica font,
+// in thousandths of the font size; other characters are assumed to be the width of a digit
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// textWidth returns the width of the text, in points
+func textWidth(s string) float64 {
+	w := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			w += helveticaWidths[r-32]
+		} else {
+			w += 556
+		}
+	}
+	return float64(w*fontSize) / 1000
+}
+
+// wrap wraps a line of text to fit the width of the page, breaking words that are too long for a single line
+func wrap(line string) []string {
+	const width = pageWidth - 2*margin
+	indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+	var result []string
+	current := indent
+	for _, word := range strings.Fields(line) {
+		candidate := current + word
+		if current != indent {
+			candidate = current + " " + word
+		}
+		if textWidth(candidate) <= width {
+			current = candidate
+			continue
+		}
+		if current != indent {
+			result = append(result, current)
+			current = indent
+		}
+		for textWidth(indent+word) > width { // break long words
+			runes := []rune(word)
+			n := len(runes)
+			for n > 1 && textWidth(indent+string(runes[:n])) > width {
+				n--
+			}
+			result = append(result, indent+string(runes[:n]))
+			word = string(runes[n:])
+		}
+		current = indent + word
+	}
+	return append(result, current)
+}
+
+// encode encodes text as a PDF string literal using WinAnsiEncoding, replacing characters that cannot be encoded
+func encode(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		if c < 32 || c > 126 {
+			fmt.Fprintf(&b, "\\%03o", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// writePDF writes a PDF document containing the lines of text specified, wrapped and paginated as required.
+// The document is deterministic, having no creation date or unique identifier.
+func writePDF(title string, lines []string) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrap(strings.ReplaceAll(line, "\t", "    "))...)
+	}
+	var pages [][]string
+	for len(wrapped) > linesPerPage {
+		pages = append(pages, wrapped[:linesPerPage])
+		wrapped = wrapped[linesPerPage:]
+	}
+	pages = append(pages, wrapped)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (concierge) >>", encode(title)))
+	for i, page := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin-fontSize)
+		for _, line := range page {
+			fmt.Fprintf(&content, "%s Tj T*\n", encode(line))
+		}
+		content.WriteString("ET")
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,108 @@
+// Package render converts formatted document content, such as HTML or Markdown, to PDF, for repositories
+// that only accept PDF. Renderers are pluggable: a simple built-in renderer produces plain text PDF documents
+// without external dependencies, with deterministic output suitable for testing, while an external command
+// (e.g. wkhtmltopdf) may be used for higher fidelity rendering.
+package render
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"mime"
+	"os/exec"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContentTypePDF is the content type of rendered documents
+const ContentTypePDF = "application/pdf"
+
+// Renderer renders document content as PDF
+type Renderer interface {
+	// Supports returns whether the renderer can render content of the type specified
+	Supports(contentType string) bool
+	// Render renders the content as PDF
+	Render(ctx context.Context, a *apiv1.Attachment) ([]byte, error)
+}
+
+// ToPDF renders the attachment as PDF using the renderer specified, returning the attachment unchanged if already PDF.
+// An InvalidArgument error is returned if the content type cannot be rendered.
+func ToPDF(ctx context.Context, r Renderer, a *apiv1.Attachment) (*apiv1.Attachment, error) {
+	contentType := mediaType(a.GetContentType())
+	if contentType == ContentTypePDF {
+		return a, nil
+	}
+	if r == nil || !r.Supports(contentType) {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to render content type '%s' as PDF", a.GetContentType())
+	}
+	b, err := r.Render(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha1.Sum(b)
+	return &apiv1.Attachment{
+		ContentType: ContentTypePDF,
+		Language:    a.GetLanguage(),
+		Data:        b,
+		Size:        uint64(len(b)),
+		Hash:        hash[:],
+		Title:       a.GetTitle(),
+		Created:     a.GetCreated(),
+	}, nil
+}
+
+// mediaType returns the media type, without parameters such as charset
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// Command renders content using an external command, which reads content from standard input and
+// writes PDF to standard output, such as "wkhtmltopdf --quiet - -"
+type Command struct {
+	name         string
+	args         []string
+	contentTypes map[string]bool
+}
+
+// NewCommand creates a renderer using the command line specified, for the content types specified
+func NewCommand(commandLine string, contentTypes []string) (*Command, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("render: missing command")
+	}
+	c := &Command{name: fields[0], args: fields[1:], contentTypes: make(map[string]bool)}
+	for _, ct := range contentTypes {
+		c.contentTypes[mediaType(ct)] = true
+	}
+	return c, nil
+}
+
+// Supports returns whether the command renders the content type specified
+func (c *Command) Supports(contentType string) bool {
+	return c.contentTypes[mediaType(contentType)]
+}
+
+// Render runs the command to render the content, which is stopped if the context is cancelled
+func (c *Command) Render(ctx context.Context, a *apiv1.Attachment) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stdin = bytes.NewReader(a.GetData())
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Errorf(codes.Internal, "render: '%s' failed: %s: %s", c.name, err, strings.TrimSpace(stderr.String()))
+	}
+	if !bytes.HasPrefix(stdout.Bytes(), []byte("%PDF")) {
+		return nil, status.Errorf(codes.Internal, "render: '%s' did not generate a PDF document", c.name)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/doc/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTMLLines(t *testing.T) {
+	html := `<html><head><title>Ignored</title><style>p { color: red }</style></head><body>
+<h1>Clinic letter</h1><p>Dear Dr   Smith,</p><p>Thank you for <b>referring</b> this patient.<br>Seen today.</p>
+<ul><li>Aspirin</li><li>Ramipril</li></ul><pre>  BP 120/80
+  HR 72</pre></body></html>`
+	expected := []string{"Clinic letter", "", "Dear Dr Smith,", "", "Thank you for referring this patient.", "Seen today.", "• Aspirin", "• Ramipril", "", "  BP 120/80", "  HR 72"}
+	if lines := htmlLines([]byte(html)); !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestMarkdownLines(t *testing.T) {
+	md := "# Clinic letter\nDear Dr Smith,\n\nThank you for **referring** this [patient](https://example.com).\n\n- Aspirin\n* Ramipril\n\n---\n```\n# not a heading\n```\n"
+	expected := []string{"Clinic letter", "", "Dear Dr Smith,", "", "Thank you for referring this patient (https://example.com).", "", "• Aspirin", "• Ramipril", "", "# not a heading"}
+	if lines := markdownLines(md); !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	line := "  " + strings.Repeat("word ", 40) + strings.Repeat("x", 200)
+	lines := wrap(line)
+	if len(lines) < 3 {
+		t.Fatalf("expected long line to be wrapped, got %q", lines)
+	}
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "  ") || textWidth(l) > pageWidth-2*margin {
+			t.Fatalf("invalid wrapped line: %q", l)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	ctx := context.Background()
+	v := validation.New(validation.Options{ValidatePDF: true})
+	for _, ct := range []string{"text/plain", "text/html; charset=utf-8", "text/markdown"} {
+		a := &apiv1.Attachment{ContentType: ct, Title: "Clinic letter (draft)", Data: []byte("Dear Dr Smith, café \\ (test)")}
+		pdf, err := ToPDF(ctx, Text{}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pdf.GetContentType() != ContentTypePDF || pdf.GetSize() != uint64(len(pdf.GetData())) || pdf.GetTitle() != a.GetTitle() {
+			t.Fatalf("invalid attachment for rendered document: %v", pdf)
+		}
+		if err := v.Validate(ctx, pdf); err != nil {
+			t.Fatalf("invalid PDF rendered from %s: %s", ct, err)
+		}
+		if !bytes.Contains(pdf.GetData(), []byte(`(Dear Dr Smith, caf\351 \\ \(test\)) Tj`)) {
+			t.Fatalf("expected content to be rendered from %s, got:\n%s", ct, pdf.GetData())
+		}
+		checkXref(t, pdf.GetData())
+		pdf2, err := ToPDF(ctx, Text{}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pdf.GetData(), pdf2.GetData()) {
+			t.Fatalf("expected rendering to be deterministic for %s", ct)
+		}
+	}
+}
+
+func TestPagination(t *testing.T) {
+	var lines []string
+	for i := 0; i < linesPerPage+1; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	b := writePDF("", lines)
+	if !bytes.Contains(b, []byte("/Count 2")) {
+		t.Fatalf("expected two pages, got:\n%s", b)
+	}
+	checkXref(t, b)
+}
+
+// checkXref checks that each entry in the cross-reference table refers to the offset of its object
+func checkXref(t *testing.T, b []byte) {
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(b, -1)
+	if len(entries) == 0 {
+		t.Fatal("missing cross-reference table")
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !bytes.HasPrefix(b[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Fatalf("invalid cross-reference offset for object %d: %d", i+1, offset)
+		}
+	}
+}
+
+func TestToPDF(t *testing.T) {
+	ctx := context.Background()
+	pdf := &apiv1.Attachment{ContentType: ContentTypePDF, Data: []byte("%PDF-1.4")}
+	if a, err := ToPDF(ctx, nil, pdf); err != nil || a != pdf {
+		t.Fatalf("expected PDF to be returned unchanged, got %v (%v)", a, err)
+	}
+	if _, err := ToPDF(ctx, Text{}, &apiv1.Attachment{ContentType: "image/png"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected unsupported content type to be rejected, got %v", err)
+	}
+	if _, err := ToPDF(ctx, nil, &apiv1.Attachment{ContentType: "text/html"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected content to be rejected without a renderer, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"golang.org/x/net/html"
+)
+
+// Text is a built-in renderer producing plain text PDF documents from plain text, HTML or Markdown.
+// Formatting is limited to paragraphs, headings, lists and line breaks, but output is deterministic,
+// and so suitable for testing.
+type Text struct{}
+
+// textContentTypes are the content types supported by the built-in renderer
+var textContentTypes = map[string]bool{
+	"text/plain":      true,
+	"text/html":       true,
+	"text/markdown":   true,
+	"text/x-markdown": true,
+}
+
+// Supports returns whether the content type is plain text, HTML or Markdown
+func (t Text) Supports(contentType string) bool {
+	return textContentTypes[mediaType(contentType)]
+}
+
+// Render renders the content as a plain text PDF document
+func (t Text) Render(ctx context.Context, a *apiv1.Attachment) ([]byte, error) {
+	var lines []string
+	switch mediaType(a.GetContentType()) {
+	case "text/html":
+		lines = htmlLines(a.GetData())
+	case "text/markdown", "text/x-markdown":
+		lines = markdownLines(string(a.GetData()))
+	default:
+		lines = strings.Split(strings.ReplaceAll(string(a.GetData()), "\r\n", "\n"), "\n")
+	}
+	return writePDF(a.GetTitle(), lines), nil
+}
+
+// htmlBlocks are HTML elements that start a new line
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "table": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "blockquote": true, "pre": true,
+	"section": true, "article": true, "header": true, "footer": true, "hr": true, "dl": true, "dt": true, "dd": true,
+}
+
+// htmlSkipped are HTML elements whose content is not rendered
+var htmlSkipped = map[string]bool{"head": true, "script": true, "style": true, "title": true}
+
+// htmlLines converts HTML to lines of text, collapsing whitespace except within preformatted text
+func htmlLines(b []byte) []string {
+	var lines []string
+	var line strings.Builder
+	skip, pre := 0, 0
+	flush := func() { // ends the current line, if any
+		s := line.String()
+		if pre == 0 {
+			s = strings.TrimSpace(s)
+		}
+		if s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+	z := html.NewTokenizer(bytes.NewReader(b))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken: // end of input, or malformed HTML, in which case content so far is rendered
+			flush()
+			return trimBlankLines(lines)
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			depth := 0
+			switch tt {
+			case html.StartTagToken:
+				depth = 1
+			case html.EndTagToken:
+				depth = -1
+			}
+			if htmlSkipped[tag] {
+				skip += depth
+				continue
+			}
+			if tag == "br" && line.Len() == 0 {
+				lines = append(lines, "")
+			}
+			if htmlBlocks[tag] {
+				flush()
+				if tt != html.EndTagToken && (tag == "p" || tag == "pre" || tag == "hr" || isHeading(tag)) {
+					lines = append(lines, "")
+				}
+				if tt == html.EndTagToken && isHeading(tag) {
+					lines = append(lines, "")
+				}
+			}
+			if tag == "pre" {
+				pre += depth
+			}
+			if tt == html.StartTagToken && tag == "li" {
+				line.WriteString("• ")
+			}
+			if tt == html.StartTagToken && (tag == "td" || tag == "th") && line.Len() > 0 {
+				line.WriteString("  ")
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if pre > 0 {
+				for i, s := range strings.Split(text, "\n") {
+					if i > 0 {
+						lines = append(lines, line.String())
+						line.Reset()
+					}
+					line.WriteString(s)
+				}
+				continue
+			}
+			if fields := strings.Fields(text); len(fields) > 0 {
+				if line.Len() > 0 && (text[0] == ' ' || text[0] == '\n' || text[0] == '\t') && !strings.HasSuffix(line.String(), " ") {
+					line.WriteByte(' ')
+				}
+				line.WriteString(strings.Join(fields, " "))
+				if last := text[len(text)-1]; last == ' ' || last == '\n' || last == '\t' {
+					line.WriteByte(' ')
+				}
+			}
+		}
+	}
+}
+
+// isHeading returns whether the HTML element is a heading (h1 to h6)
+func isHeading(tag string) bool {
+	return len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6'
+}
+
+var (
+	mdHeading  = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+	mdBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdEmphasis = regexp.MustCompile("\\*\\*|__|`")
+	mdRule     = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+)
+
+// markdownLines converts Markdown to lines of text, removing markup other than that for lists
+func markdownLines(s string) []string {
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			lines = append(lines, line)
+			continue
+		}
+		if mdRule.MatchString(line) {
+			lines = append(lines, "")
+			continue
+		}
+		heading := mdHeading.MatchString(line)
+		line = mdHeading.ReplaceAllString(line, "")
+		line = mdBullet.ReplaceAllString(line, "$1• ")
+		line = mdImage.ReplaceAllString(line, "$1")
+		line = mdLink.ReplaceAllString(line, "$1 ($2)")
+		line = mdEmphasis.ReplaceAllString(line, "")
+		if heading && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+		if heading {
+			lines = append(lines, "")
+		}
+	}
+	return trimBlankLines(lines)
+}
+
+// trimBlankLines removes leading and trailing blank lines, and repeated blank lines
+func trimBlankLines(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line == "" && (len(result) == 0 || result[len(result)-1] == "") {
+			continue
+		}
+		result = append(result, line)
+	}
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+// Package rules provides a simple rule engine to determine the repository to which a document
+// should be published. Rules are evaluated in order, and the first matching rule wins.
+//
+// Rules can be loaded from a YAML or JSON file, for example:
+//
+//	rules:
+//	  - name: cardiff-and-vale
+//	    identifier_systems: ["https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier"]
+//	    repository: cav
+//	  - name: english-general-practice
+//	    practices: ["*"]
+//	    exclude_practices: ["W*"]
+//	    repository: mesh
+//	  - name: fallback
+//	    repository: wcrs
+//	    format: cda
+//
+// All specified conditions within a rule must match; an empty condition matches anything.
+// A rule may also specify the format in which content is published, such as rendered as PDF or wrapped in a CDA document.
+package rules
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule defines the conditions under which a document should be published to a specific repository
+type Rule struct {
+	Name              string   `yaml:"name" json:"name"`
+	IdentifierSystems []string `yaml:"identifier_systems,omitempty" json:"identifier_systems,omitempty"` // patient has an identifier in any of these systems
+	DocumentTypes     []string `yaml:"document_types,omitempty" json:"document_types,omitempty"`         // document type as system|value or value
+	Specialties       []string `yaml:"specialties,omitempty" json:"specialties,omitempty"`               // specialty as system|value or value
+	Practices         []string `yaml:"practices,omitempty" json:"practices,omitempty"`                   // GP practice ODS code patterns, e.g. W95010 or A*
+	ExcludePractices  []string `yaml:"exclude_practices,omitempty" json:"exclude_practices,omitempty"`   // GP practice ODS code patterns to exclude
+	Repository        string   `yaml:"repository" json:"repository"`                                     // name of the repository to use
+	Format            string   `yaml:"format,omitempty" json:"format,omitempty"`                         // format of published content; see FormatCDA and FormatPDF
+}
+
+// Formats of published content
+const (
+	FormatAsIs = ""    // content is published as supplied
+	FormatCDA  = "cda" // content is wrapped in an HL7 CDA R2 document
+	FormatPDF  = "pdf" // content is rendered as PDF, if not already PDF
+)
+
+// RuleSet is an ordered list of rules
+type RuleSet struct {
+	Rules []*Rule `yaml:"rules" json:"rules"`
+}
+
+// Load loads a rule set from the YAML or JSON file specified
+func Load(filename string) (*RuleSet, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses a rule set from YAML or JSON
+func Parse(b []byte) (*RuleSet, error) {
+	rs := new(RuleSet)
+	if err := yaml.UnmarshalStrict(b, rs); err != nil {
+		return nil, fmt.Errorf("rules: invalid rule file: %w", err)
+	}
+	return rs, nil
+}
+
+// Validate checks that the rule set is valid, using the list of repositories available.
+// All errors are returned, rather than only the first.
+func (rs *RuleSet) Validate(repositories []string) []error {
+	errs := make([]error, 0)
+	if len(rs.Rules) == 0 {
+		errs = append(errs, fmt.Errorf("rules: no rules defined"))
+	}
+	known := make(map[string]struct{})
+	for _, repo := range repositories {
+		known[repo] = struct{}{}
+	}
+	names := make(map[string]struct{})
+	for i, rule := range rs.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+			errs = append(errs, fmt.Errorf("rules: rule %s: missing name", name))
+		} else if _, dup := names[name]; dup {
+			errs = append(errs, fmt.Errorf("rules: rule '%s': duplicate name", name))
+		}
+		names[name] = struct{}{}
+		if rule.Repository == "" {
+			errs = append(errs, fmt.Errorf("rules: rule '%s': missing repository", name))
+		} else if _, ok := known[rule.Repository]; !ok {
+			errs = append(errs, fmt.Errorf("rules: rule '%s': unknown repository '%s'. available: %s", name, rule.Repository, strings.Join(repositories, ", ")))
+		}
+		if rule.Format != FormatAsIs && rule.Format != FormatCDA && rule.Format != FormatPDF {
+			errs = append(errs, fmt.Errorf("rules: rule '%s': unknown format '%s'. available: %s, %s", name, rule.Format, FormatCDA, FormatPDF))
+		}
+		for _, pattern := range append(append([]string{}, rule.Practices...), rule.ExcludePractices...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("rules: rule '%s': invalid practice pattern '%s': %w", name, pattern, err))
+			}
+		}
+	}
+	return errs
+}
+
+// Match returns the first rule that matches the document, or nil.
+// If available is not nil, rules for repositories that are not available are skipped.
+func (rs *RuleSet) Match(d *apiv1.Document, available func(repository string) bool) *Rule {
+	if rs == nil {
+		return nil
+	}
+	for _, rule := range rs.Rules {
+		if available != nil && !available(rule.Repository) {
+			continue
+		}
+		if rule.Matches(d) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Matches determines whether this rule matches the document specified
+func (r *Rule) Matches(d *apiv1.Document) bool {
+	if len(r.IdentifierSystems) > 0 && !matchIdentifierSystems(d.GetPatient(), r.IdentifierSystems) {
+		return false
+	}
+	if len(r.DocumentTypes) > 0 && !matchIdentifier(d.GetType(), r.DocumentTypes) {
+		return false
+	}
+	if len(r.Specialties) > 0 && !matchIdentifier(d.GetSpecialty(), r.Specialties) {
+		return false
+	}
+	surgery := strings.ToUpper(d.GetPatient().GetSurgery().GetValue())
+	if len(r.Practices) > 0 && (surgery == "" || !matchPatterns(surgery, r.Practices)) {
+		return false
+	}
+	if len(r.ExcludePractices) > 0 && matchPatterns(surgery, r.ExcludePractices) {
+		return false
+	}
+	return true
+}
+
+func matchIdentifierSystems(pt *apiv1.Patient, systems []string) bool {
+	for _, system := range systems {
+		if _, found := pt.GetIdentifiersForSystem(system); found {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIdentifier matches an identifier against a list of values of the form system|value or value
+func matchIdentifier(id *apiv1.Identifier, values []string) bool {
+	if id == nil {
+		return false
+	}
+	for _, v := range values {
+		if v == id.GetValue() || v == id.GetSystem()+"|"+id.GetValue() {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPatterns(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), s); matched {
+			return true
+		}
+	}
+	return false
+}
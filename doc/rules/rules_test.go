@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+const testRules = `
+rules:
+  - name: cardiff-and-vale
+    identifier_systems: ["https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier"]
+    repository: cav
+  - name: neurology-england
+    specialties: ["http://snomed.info/sct|394591006"]
+    practices: ["A*", "B*"]
+    repository: mesh
+  - name: fallback
+    exclude_practices: ["Z*"]
+    repository: wcrs
+    format: cda
+`
+
+func TestRules(t *testing.T) {
+	rs, err := Parse([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := rs.Validate([]string{"cav", "mesh", "wcrs"}); len(errs) > 0 {
+		t.Fatalf("unexpected validation errors: %v", errs)
+	}
+	if errs := rs.Validate([]string{"cav"}); len(errs) != 2 {
+		t.Fatalf("expected two validation errors for unknown repositories, got: %v", errs)
+	}
+	neuro := &apiv1.Identifier{System: "http://snomed.info/sct", Value: "394591006"}
+	tests := []struct {
+		doc      *apiv1.Document
+		expected string
+	}{
+		{&apiv1.Document{Patient: &apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier", Value: "A999998"}}}}, "cardiff-and-vale"},
+		{&apiv1.Document{Specialty: neuro, Patient: &apiv1.Patient{Surgery: &apiv1.Identifier{System: identifiers.ODSCode, Value: "a81001"}}}, "neurology-england"},
+		{&apiv1.Document{Specialty: neuro, Patient: &apiv1.Patient{Surgery: &apiv1.Identifier{System: identifiers.ODSCode, Value: "W95010"}}}, "fallback"},
+		{&apiv1.Document{Patient: &apiv1.Patient{Surgery: &apiv1.Identifier{System: identifiers.ODSCode, Value: "A81001"}}}, "fallback"},
+		{&apiv1.Document{Patient: &apiv1.Patient{Surgery: &apiv1.Identifier{System: identifiers.ODSCode, Value: "Z00001"}}}, ""},
+	}
+	for _, test := range tests {
+		rule := rs.Match(test.doc, nil)
+		if (rule == nil && test.expected != "") || (rule != nil && rule.Name != test.expected) {
+			t.Errorf("unexpected rule for %v: got %v, expected '%s'", test.doc, rule, test.expected)
+		}
+	}
+	if rule := rs.Match(tests[0].doc, func(repo string) bool { return repo != "cav" }); rule == nil || rule.Name != "fallback" || rule.Format != FormatCDA {
+		t.Errorf("expected fallback rule when cav unavailable, got: %v", rule)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	if _, err := Parse([]byte("rules:\n  - name: x\n    unknown: y\n")); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	rs, err := Parse([]byte("rules:\n  - name: x\n    practices: ['[']\n    repository: wcrs\n    format: docx\n  - name: x\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := rs.Validate([]string{"wcrs"}); len(errs) != 4 {
+		t.Fatalf("expected four validation errors, got: %v", errs)
+	}
+}
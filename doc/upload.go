@@ -0,0 +1,126 @@
+package doc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/utilities"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadChunkSize is the size of each chunk of a document uploaded over HTTP
+const uploadChunkSize = 64 * 1024
+
+// UploadDocument publishes a single document streamed in chunks, for documents larger than the maximum
+// gRPC message size, assembling the content before publishing as for PublishDocument.
+func (ds *DocumentService) UploadDocument(stream apiv1.DocumentService_UploadDocumentServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err == io.EOF || (err == nil && first.GetRequest() == nil) {
+		return i18n.Errorf(ctx, codes.InvalidArgument, "upload must start with a publish request")
+	}
+	if err != nil {
+		return err
+	}
+	r := first.GetRequest()
+	if r.GetDocument() == nil {
+		return i18n.Errorf(ctx, codes.InvalidArgument, "no document specified")
+	}
+	if r.Document.Data == nil {
+		r.Document.Data = &apiv1.Attachment{}
+	}
+	var content bytes.Buffer
+	content.Write(r.Document.Data.Data)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if msg.GetRequest() != nil {
+			return i18n.Errorf(ctx, codes.InvalidArgument, "upload must contain only one publish request")
+		}
+		content.Write(msg.GetChunk())
+	}
+	r.Document.Data.Data = content.Bytes()
+	response, err := ds.PublishDocument(ctx, r)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(response)
+}
+
+// patternUploadDocument is the HTTP route for uploading a document, with the content as the request body
+var patternUploadDocument = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "document", "upload"}, "", runtime.AssumeColonVerbOpt(true)))
+
+// registerUpload registers an HTTP handler for uploading a document, streaming the request body to UploadDocument
+// rather than reading it into memory first, as for the gateway's handlers. The remainder of the request is taken
+// from query parameters, as for /v1/document/publish, with the content type defaulting to that of the request.
+func registerUpload(mux *runtime.ServeMux, client apiv1.DocumentServiceClient) {
+	mux.Handle(http.MethodPost, patternUploadDocument, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		rctx, err := runtime.AnnotateContext(ctx, mux, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		r := &apiv1.PublishDocumentRequest{}
+		if err := runtime.PopulateQueryParameters(r, req.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		if r.Document == nil {
+			r.Document = &apiv1.Document{}
+		}
+		if r.Document.Data == nil {
+			r.Document.Data = &apiv1.Attachment{}
+		}
+		if r.Document.Data.ContentType == "" {
+			r.Document.Data.ContentType = req.Header.Get("Content-Type")
+		}
+		response, err := upload(rctx, client, r, req.Body)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		runtime.ForwardResponseMessage(ctx, mux, outboundMarshaler, w, req, response)
+	})
+}
+
+// upload streams the request, and then the content read, to UploadDocument
+func upload(ctx context.Context, client apiv1.DocumentServiceClient, r *apiv1.PublishDocumentRequest, content io.Reader) (*apiv1.PublishDocumentResponse, error) {
+	stream, err := client.UploadDocument(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&apiv1.UploadDocumentRequest{Value: &apiv1.UploadDocumentRequest_Request{Request: r}}); err != nil {
+		return stream.CloseAndRecv() // the server's error, if it has failed
+	}
+	buf := make([]byte, uploadChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			if err := stream.Send(&apiv1.UploadDocumentRequest{Value: &apiv1.UploadDocumentRequest_Chunk{Chunk: chunk}}); err != nil {
+				return stream.CloseAndRecv()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to read document: %s", err)
+		}
+	}
+	return stream.CloseAndRecv()
+}
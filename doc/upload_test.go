@@ -0,0 +1,77 @@
+package doc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+)
+
+func TestUploadDocument(t *testing.T) {
+	ds := NewDocumentService(nil, nil)
+	wcrs := &recordingRepository{}
+	ds.RegisterRepository(WCRS, wcrs)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	ds.RegisterServer(grpcServer)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mux := runtime.NewServeMux()
+	if err := ds.RegisterHTTPProxy(ctx, mux, lis.Addr().String(), []grpc.DialOption{grpc.WithInsecure()}); err != nil {
+		t.Fatal(err)
+	}
+
+	content := bytes.Repeat([]byte("%PDF-1.4 "), 3*uploadChunkSize/9+1) // spanning several chunks
+	params := url.Values{
+		"document.id.system":              {identifiers.UUID},
+		"document.id.value":               {"1"},
+		"document.patient.lastname":       {"DUMMY"},
+		"document.patient.surgery.system": {identifiers.ODSCode},
+		"document.patient.surgery.value":  {"W95010"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/document/upload?"+params.Encode(), bytes.NewReader(content))
+	req.Header.Set("Content-Type", "application/pdf")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("upload failed: %d: %s", w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response) == 0 {
+		t.Fatalf("invalid response: %s", w.Body.String())
+	}
+	if !bytes.Equal(wcrs.data.GetData(), content) || wcrs.data.GetContentType() != "application/pdf" {
+		t.Fatalf("expected document to be published with the uploaded content: %d bytes (%s)", len(wcrs.data.GetData()), wcrs.data.GetContentType())
+	}
+
+	// the first message must be the request
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	stream, err := apiv1.NewDocumentServiceClient(conn).UploadDocument(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&apiv1.UploadDocumentRequest{Value: &apiv1.UploadDocumentRequest_Chunk{Chunk: content}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.CloseAndRecv(); err == nil {
+		t.Fatal("expected upload without a request to fail")
+	}
+}
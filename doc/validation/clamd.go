@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clamdChunkSize is the size of chunks streamed to clamd, which must be less than its StreamMaxLength
+const clamdChunkSize = 64 * 1024
+
+// DefaultClamdTimeout is the default timeout for scanning content using clamd
+const DefaultClamdTimeout = 30 * time.Second
+
+// ClamdScanner scans content for malware using a ClamAV daemon (clamd), streaming content using the INSTREAM command.
+// See https://linux.die.net/man/8/clamd
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner creates a scanner using the clamd daemon at the address specified; this is either
+// a TCP address (e.g. "localhost:3310") or the path of a unix socket (e.g. "/var/run/clamav/clamd.ctl").
+func NewClamdScanner(address string, timeout time.Duration) *ClamdScanner {
+	if timeout <= 0 {
+		timeout = DefaultClamdTimeout
+	}
+	network := "tcp"
+	if strings.HasPrefix(address, "/") {
+		network = "unix"
+	}
+	return &ClamdScanner{network: network, address: address, timeout: timeout}
+}
+
+// Scan scans the content for malware, returning the name of any signature found
+func (cs *ClamdScanner) Scan(ctx context.Context, data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, cs.network, cs.address)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "clamd: %s", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", status.Errorf(codes.Unavailable, "clamd: %s", err)
+	}
+	size := make([]byte, 4)
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > clamdChunkSize {
+			chunk = chunk[:clamdChunkSize]
+		}
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(append(size, chunk...)); err != nil {
+			return "", status.Errorf(codes.Unavailable, "clamd: %s", err)
+		}
+		data = data[len(chunk):]
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return "", status.Errorf(codes.Unavailable, "clamd: %s", err)
+	}
+	b, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "clamd: %s", err)
+	}
+	return parseClamdResponse(string(bytes.TrimRight(b, "\x00\n")))
+}
+
+// parseClamdResponse parses a response from clamd, such as "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseClamdResponse(response string) (string, error) {
+	result := strings.TrimPrefix(response, "stream: ")
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return "malware detected: " + strings.TrimSuffix(result, " FOUND"), nil
+	}
+	return "", status.Errorf(codes.Unavailable, "clamd: unexpected response: %s", response)
+}
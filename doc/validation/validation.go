@@ -0,0 +1,162 @@
+// Package validation checks the content of documents before they are published to a repository.
+// Content is checked against a maximum size, PDF documents are checked for structural validity and,
+// optionally, declared PDF/A conformance, and content may be scanned by pluggable scanners,
+// such as a clamd malware scanner.
+//
+// Rejected content results in an InvalidArgument error, with the reasons for rejection
+// returned as a PreconditionFailure error detail, with one violation for each reason.
+package validation
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxSize is the default maximum size of document content, in bytes
+const DefaultMaxSize = 20 * 1024 * 1024
+
+// Types of violation, returned in the PreconditionFailure error detail of rejected content
+const (
+	ViolationSize = "SIZE" // content exceeds the maximum size
+	ViolationPDF  = "PDF"  // content is not a structurally valid PDF document
+	ViolationPDFA = "PDFA" // content does not declare PDF/A conformance
+	ViolationScan = "SCAN" // content rejected by a scanner
+)
+
+// subject is the subject of violations in the PreconditionFailure error detail
+const subject = "document.data"
+
+// Options configures the validation of document content
+type Options struct {
+	MaxSize     int64 // maximum size of content in bytes, or zero for no limit
+	ValidatePDF bool  // check the structure of PDF documents
+	RequirePDFA bool  // require PDF documents to declare PDF/A conformance
+}
+
+// Scanner scans document content, such as for malware
+type Scanner interface {
+	// Scan scans the content specified, returning a reason if the content should be rejected,
+	// or an empty string if the content is acceptable
+	Scan(ctx context.Context, data []byte) (string, error)
+}
+
+// Validator validates document content. This is thread-safe once configured.
+type Validator struct {
+	opts     Options
+	names    []string
+	scanners []Scanner
+}
+
+// New creates a new validator with the options specified
+func New(opts Options) *Validator {
+	return &Validator{opts: opts}
+}
+
+// RegisterScanner registers a named scanner, used to scan all content
+// This should not be called once server is running.
+func (v *Validator) RegisterScanner(name string, s Scanner) {
+	v.names = append(v.names, name)
+	v.scanners = append(v.scanners, s)
+	log.Printf("validation: registered scanner: '%s'", name)
+}
+
+// Validate validates the content of the attachment specified, returning an InvalidArgument error
+// if the content should be rejected. Attachments without content are not validated.
+// An error is also returned if a scanner fails, so that content is never published unscanned.
+func (v *Validator) Validate(ctx context.Context, att *apiv1.Attachment) error {
+	data := att.GetData()
+	if len(data) == 0 {
+		return nil
+	}
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0)
+	violate := func(t string, format string, a ...interface{}) {
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{Type: t, Subject: subject, Description: i18n.Sprintf(ctx, format, a...)})
+	}
+	if v.opts.MaxSize > 0 && int64(len(data)) > v.opts.MaxSize {
+		violate(ViolationSize, "document exceeds maximum size of %d bytes", v.opts.MaxSize)
+	}
+	if isPDF(att) && (v.opts.ValidatePDF || v.opts.RequirePDFA) {
+		if reason := validatePDF(data); reason != "" {
+			violate(ViolationPDF, "invalid PDF document: %s", reason)
+		} else if v.opts.RequirePDFA && !declaresPDFA(data) {
+			violate(ViolationPDFA, "PDF document does not declare PDF/A conformance")
+		}
+	}
+	for i, s := range v.scanners {
+		reason, err := s.Scan(ctx, data)
+		if err != nil {
+			log.Printf("validation: scanner '%s' failed: %s", v.names[i], err)
+			return err
+		}
+		if reason != "" {
+			log.Printf("validation: scanner '%s' rejected content: %s", v.names[i], reason)
+			violate(ViolationScan, "content rejected by %s: %s", v.names[i], reason)
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, i18n.Sprintf(ctx, "document content rejected: %s", violations[0].GetDescription()))
+	if detailed, err := st.WithDetails(&errdetails.PreconditionFailure{Violations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// isPDF returns whether the attachment is a PDF document, using its content type or, if none, its content
+func isPDF(att *apiv1.Attachment) bool {
+	if att.GetContentType() != "" {
+		return att.GetContentType() == "application/pdf"
+	}
+	return bytes.HasPrefix(att.GetData(), []byte("%PDF-"))
+}
+
+var (
+	pdfHeader = regexp.MustCompile(`^%PDF-(1\.[0-7]|2\.0)`)
+	startXref = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF`)
+	xrefStart = regexp.MustCompile(`^(xref|\d+\s+\d+\s+obj)`)
+	pdfaPart  = regexp.MustCompile(`pdfaid:part(="|>)\s*[1-4]`)
+)
+
+// trailerSize is the number of bytes at the end of a PDF document searched for the trailer
+const trailerSize = 1024
+
+// validatePDF checks the structure of a PDF document, returning a reason if invalid.
+// This checks the header, and that the trailer points to a cross-reference table or stream,
+// which is sufficient to detect truncated, corrupt or mislabelled content.
+func validatePDF(data []byte) string {
+	if !pdfHeader.Match(data) {
+		return "missing or unsupported PDF header"
+	}
+	tail := data
+	if len(tail) > trailerSize {
+		tail = tail[len(tail)-trailerSize:]
+	}
+	matches := startXref.FindAllSubmatch(tail, -1)
+	if len(matches) == 0 {
+		return "missing end-of-file marker; document may be truncated"
+	}
+	offset, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil || offset >= len(data) || !xrefStart.Match(data[offset:]) {
+		return "invalid cross-reference offset"
+	}
+	if !bytes.Contains(data, []byte("/Root")) {
+		return "missing document catalog"
+	}
+	return ""
+}
+
+// declaresPDFA returns whether a PDF document declares PDF/A conformance in its XMP metadata.
+// PDF/A requires that metadata is not compressed, so this does not need to decode streams.
+func declaresPDFA(data []byte) bool {
+	return pdfaPart.Match(data) && !bytes.Contains(data, []byte("/Encrypt"))
+}
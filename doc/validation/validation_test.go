@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testPDF returns a minimal PDF document with a valid cross-reference offset, including the metadata specified
+func testPDF(metadata string) []byte {
+	body := "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" + metadata
+	return []byte(fmt.Sprintf("%sxref\n0 1\n0000000000 65535 f \ntrailer\n<< /Size 1 /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", body, len(body)))
+}
+
+type testScanner string
+
+func (s testScanner) Scan(ctx context.Context, data []byte) (string, error) {
+	if strings.Contains(string(data), string(s)) {
+		return "malware detected: " + string(s), nil
+	}
+	return "", nil
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	pdfa := testPDF("<x:xmpmeta><pdfaid:part>2</pdfaid:part></x:xmpmeta>\n")
+	tests := []struct {
+		name      string
+		opts      Options
+		att       *apiv1.Attachment
+		violation string
+	}{
+		{"valid", Options{ValidatePDF: true}, &apiv1.Attachment{ContentType: "application/pdf", Data: testPDF("")}, ""},
+		{"empty", Options{ValidatePDF: true, MaxSize: 1}, &apiv1.Attachment{ContentType: "application/pdf"}, ""},
+		{"too large", Options{MaxSize: 10}, &apiv1.Attachment{ContentType: "application/pdf", Data: testPDF("")}, ViolationSize},
+		{"truncated", Options{ValidatePDF: true}, &apiv1.Attachment{ContentType: "application/pdf", Data: testPDF("")[:60]}, ViolationPDF},
+		{"not a pdf", Options{ValidatePDF: true}, &apiv1.Attachment{ContentType: "application/pdf", Data: []byte("hello world")}, ViolationPDF},
+		{"sniffed", Options{ValidatePDF: true}, &apiv1.Attachment{Data: []byte("%PDF-1.4 truncated")}, ViolationPDF},
+		{"other content type", Options{ValidatePDF: true}, &apiv1.Attachment{ContentType: "text/plain", Data: []byte("hello world")}, ""},
+		{"not pdf/a", Options{RequirePDFA: true}, &apiv1.Attachment{ContentType: "application/pdf", Data: testPDF("")}, ViolationPDFA},
+		{"pdf/a", Options{RequirePDFA: true}, &apiv1.Attachment{ContentType: "application/pdf", Data: pdfa}, ""},
+		{"malware", Options{}, &apiv1.Attachment{ContentType: "text/plain", Data: []byte("EICAR")}, ViolationScan},
+	}
+	for _, test := range tests {
+		v := New(test.opts)
+		v.RegisterScanner("test", testScanner("EICAR"))
+		err := v.Validate(ctx, test.att)
+		if test.violation == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			}
+			continue
+		}
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+			t.Errorf("%s: expected invalid argument with details, got %v", test.name, err)
+			continue
+		}
+		pf, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+		if !ok || len(pf.GetViolations()) != 1 || pf.GetViolations()[0].GetType() != test.violation {
+			t.Errorf("%s: expected %s violation, got %v", test.name, test.violation, st.Details())
+		}
+	}
+}
+
+func TestClamdScanner(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+					return
+				}
+				var content []byte
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					chunk := make([]byte, size)
+					if _, err := io.ReadFull(r, chunk); err != nil {
+						return
+					}
+					content = append(content, chunk...)
+				}
+				if strings.Contains(string(content), "EICAR") {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+				} else {
+					conn.Write([]byte("stream: OK\x00"))
+				}
+			}(conn)
+		}
+	}()
+	cs := NewClamdScanner(l.Addr().String(), 0)
+	if reason, err := cs.Scan(context.Background(), make([]byte, 3*clamdChunkSize)); err != nil || reason != "" {
+		t.Fatalf("expected clean content, got '%s' (%v)", reason, err)
+	}
+	if reason, err := cs.Scan(context.Background(), []byte("X5O!P%@AP EICAR")); err != nil || reason != "malware detected: Eicar-Test-Signature" {
+		t.Fatalf("expected malware to be detected, got '%s' (%v)", reason, err)
+	}
+	if _, err := parseClamdResponse("INSTREAM size limit exceeded. ERROR"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected error for unexpected response, got %v", err)
+	}
+}
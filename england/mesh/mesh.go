@@ -0,0 +1,227 @@
+// Package mesh provides a client for the NHS England Message Exchange for Social Care and Health (MESH)
+// REST API, permitting documents to be sent to, and received from, other organisations' mailboxes.
+// See https://digital.nhs.uk/developer/api-catalogue/message-exchange-for-social-care-and-health-api
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/secrets"
+)
+
+// Client is a client for a single MESH mailbox. This is thread-safe.
+type Client struct {
+	url        string         // base URL e.g. https://msg.intspineservices.nhs.uk
+	mailbox    string         // mailbox identifier
+	password   secrets.Secret // mailbox password, which may be rotated while running
+	sharedKey  string         // environment-specific shared key used for HMAC authentication
+	client     *http.Client
+	nonceCount uint64
+}
+
+// NewClient creates a new MESH client for the specified mailbox.
+// MESH requires mutual TLS, so a TLS configuration containing a client certificate should be provided.
+func NewClient(url string, mailbox string, password string, sharedKey string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		url:       strings.TrimSuffix(url, "/"),
+		mailbox:   mailbox,
+		password:  secrets.Static(password),
+		sharedKey: sharedKey,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}
+}
+
+// SetPassword sets the mailbox password, which may be rotated while running.
+// This should not be called once the client is in use.
+func (c *Client) SetPassword(password secrets.Secret) {
+	c.password = password
+}
+
+// Mailbox returns the mailbox identifier for this client
+func (c *Client) Mailbox() string {
+	return c.mailbox
+}
+
+// Message is a MESH message
+type Message struct {
+	ID          string // message identifier, assigned by MESH
+	From        string // sending mailbox
+	To          string // recipient mailbox
+	WorkflowID  string // workflow identifier, agreed between sender and recipient
+	Subject     string // optional subject
+	LocalID     string // optional local identifier for tracking
+	Filename    string // filename
+	ContentType string // content type of data
+	Data        []byte // message data
+}
+
+// authorization generates the NHSMESH authorization header value.
+// The format is NHSMESH mailbox:nonce:nonce_count:timestamp:hmac where the HMAC-SHA256 is
+// calculated over mailbox:nonce:nonce_count:password:timestamp using the shared key.
+func (c *Client) authorization() string {
+	nonce := uuid.New().String()
+	count := strconv.FormatUint(atomic.AddUint64(&c.nonceCount, 1), 10)
+	timestamp := time.Now().UTC().Format("200601021504")
+	mac := hmac.New(sha256.New, []byte(c.sharedKey))
+	mac.Write([]byte(strings.Join([]string{c.mailbox, nonce, count, c.password.Value(), timestamp}, ":")))
+	return "NHSMESH " + strings.Join([]string{c.mailbox, nonce, count, timestamp, hex.EncodeToString(mac.Sum(nil))}, ":")
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Authorization", c.authorization())
+	req.Header.Set("Mex-ClientVersion", "concierge")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Printf("mesh: %s %s failed: %s: %s", method, path, resp.Status, string(data))
+		return nil, nil, fmt.Errorf("mesh: %s %s failed: %s", method, path, resp.Status)
+	}
+	return data, resp.Header, nil
+}
+
+// Authenticate performs a handshake with the MESH server, validating the mailbox credentials.
+func (c *Client) Authenticate(ctx context.Context) error {
+	_, _, err := c.do(ctx, http.MethodPost, "/messageexchange/"+c.mailbox, nil, nil)
+	return err
+}
+
+// Send sends a message to another mailbox, returning the message identifier
+func (c *Client) Send(ctx context.Context, m *Message) (string, error) {
+	contentType := m.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	headers := map[string]string{
+		"Content-Type":     contentType,
+		"Mex-From":         c.mailbox,
+		"Mex-To":           m.To,
+		"Mex-WorkflowID":   m.WorkflowID,
+		"Mex-FileName":     m.Filename,
+		"Mex-MessageType":  "DATA",
+		"Mex-Version":      "1.0",
+		"Mex-Subject":      m.Subject,
+		"Mex-LocalID":      m.LocalID,
+		"Mex-Content-Type": contentType,
+	}
+	data, _, err := c.do(ctx, http.MethodPost, "/messageexchange/"+c.mailbox+"/outbox", m.Data, headers)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		MessageID string `json:"messageID"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("mesh: invalid response to send: %w", err)
+	}
+	log.Printf("mesh: sent message %s from %s to %s (workflow: %s)", result.MessageID, c.mailbox, m.To, m.WorkflowID)
+	return result.MessageID, nil
+}
+
+// Inbox polls the inbox, returning the identifiers of messages waiting to be downloaded
+func (c *Client) Inbox(ctx context.Context) ([]string, error) {
+	data, _, err := c.do(ctx, http.MethodGet, "/messageexchange/"+c.mailbox+"/inbox", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Messages []string `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("mesh: invalid response to inbox poll: %w", err)
+	}
+	return result.Messages, nil
+}
+
+// Download downloads the specified message from the inbox.
+// Messages should be acknowledged once processed, otherwise they will remain in the inbox.
+func (c *Client) Download(ctx context.Context, messageID string) (*Message, error) {
+	data, header, err := c.do(ctx, http.MethodGet, "/messageexchange/"+c.mailbox+"/inbox/"+messageID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID:          messageID,
+		From:        header.Get("Mex-From"),
+		To:          header.Get("Mex-To"),
+		WorkflowID:  header.Get("Mex-WorkflowID"),
+		Subject:     header.Get("Mex-Subject"),
+		LocalID:     header.Get("Mex-LocalID"),
+		Filename:    header.Get("Mex-FileName"),
+		ContentType: header.Get("Content-Type"),
+		Data:        data,
+	}, nil
+}
+
+// Acknowledge acknowledges receipt of a message, removing it from the inbox
+func (c *Client) Acknowledge(ctx context.Context, messageID string) error {
+	_, _, err := c.do(ctx, http.MethodPut, "/messageexchange/"+c.mailbox+"/inbox/"+messageID+"/status/acknowledged", nil, nil)
+	return err
+}
+
+// LookupMailbox looks up the mailbox registered for the specified ODS code and workflow
+func (c *Client) LookupMailbox(ctx context.Context, odsCode string, workflowID string) (string, error) {
+	data, _, err := c.do(ctx, http.MethodGet, "/endpointlookup/mesh/"+odsCode+"/"+workflowID, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Results []struct {
+			Address     string `json:"address"`
+			Description string `json:"description"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("mesh: invalid response to endpoint lookup: %w", err)
+	}
+	if len(result.Results) == 0 {
+		return "", fmt.Errorf("mesh: no mailbox registered for '%s' for workflow '%s'", odsCode, workflowID)
+	}
+	return result.Results[0].Address, nil
+}
+
+// Track returns the tracking status of a message sent from this mailbox, such as "Accepted",
+// "Acknowledged" or "Undeliverable"
+func (c *Client) Track(ctx context.Context, messageID string) (string, error) {
+	data, _, err := c.do(ctx, http.MethodGet, "/messageexchange/"+c.mailbox+"/outbox/tracking/"+messageID, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("mesh: invalid response to tracking request: %w", err)
+	}
+	return result.Status, nil
+}
@@ -0,0 +1,81 @@
+package mesh
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestAuthorization(t *testing.T) {
+	c := NewClient("https://localhost", "X26HC005", "password", "BackBone", nil)
+	auth := c.authorization()
+	if !strings.HasPrefix(auth, "NHSMESH ") {
+		t.Fatalf("invalid authorization header: %s", auth)
+	}
+	parts := strings.Split(strings.TrimPrefix(auth, "NHSMESH "), ":")
+	if len(parts) != 5 || parts[0] != "X26HC005" || parts[2] != "1" {
+		t.Fatalf("invalid authorization header: %s", auth)
+	}
+	mac := hmac.New(sha256.New, []byte("BackBone"))
+	mac.Write([]byte(strings.Join([]string{parts[0], parts[1], parts[2], "password", parts[3]}, ":")))
+	if hex.EncodeToString(mac.Sum(nil)) != parts[4] {
+		t.Fatalf("invalid hmac in authorization header: %s", auth)
+	}
+}
+
+func TestPublishDocument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "NHSMESH X26HC005:") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/endpointlookup/mesh/A81001/TEST_WORKFLOW":
+			w.Write([]byte(`{"results": [{"address": "X26HC006", "description": "test practice"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/messageexchange/X26HC005/outbox":
+			b, _ := ioutil.ReadAll(r.Body)
+			if r.Header.Get("Mex-To") != "X26HC006" || r.Header.Get("Mex-WorkflowID") != "TEST_WORKFLOW" || string(b) != "%PDF" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"messageID": "20200529155357895317_3573F8"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/messageexchange/X26HC005/outbox/tracking/20200529155357895317_3573F8":
+			w.Write([]byte(`{"messageID": "20200529155357895317_3573F8", "status": "Acknowledged"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	repo := NewRepository(NewClient(ts.URL, "X26HC005", "password", "BackBone", nil), "TEST_WORKFLOW", nil)
+	response, err := repo.PublishDocument(context.Background(), &apiv1.PublishDocumentRequest{
+		Document: &apiv1.Document{
+			Id:      &apiv1.Identifier{System: identifiers.UUID, Value: "3a0b8e6c-1cbc-4d5a-8a53-1f0f1b5e4c71"},
+			Patient: &apiv1.Patient{Lastname: "DUMMY", Surgery: &apiv1.Identifier{System: identifiers.ODSCode, Value: "A81001"}},
+			Data:    &apiv1.Attachment{ContentType: "application/pdf", Data: []byte("%PDF")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetId().GetSystem() != identifiers.MESHMessageID || response.GetId().GetValue() != "20200529155357895317_3573F8" {
+		t.Fatalf("unexpected response: %v", response)
+	}
+	if s, err := repo.Track(context.Background(), response.GetId()); err != nil || s != apiv1.Delivery_ACKNOWLEDGED {
+		t.Fatalf("expected message to be acknowledged, got %s (%v)", s, err)
+	}
+}
+
+func TestIsEnglishPractice(t *testing.T) {
+	if IsEnglishPractice("W95010") || !IsEnglishPractice("A81001") || IsEnglishPractice("") {
+		t.Fatal("incorrect determination of English general practice")
+	}
+}
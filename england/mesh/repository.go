@@ -0,0 +1,107 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GPConnectWorkflowID is the MESH workflow used by GP Connect Send Document to send documents,
+// such as discharge summaries and clinic letters, to general practices
+const GPConnectWorkflowID = "GPFED_CONSULT_REPORT"
+
+// Repository publishes documents to general practices using MESH.
+// The recipient mailbox for a practice is taken from an explicit mapping of ODS code to mailbox
+// identifier, if configured, or otherwise looked up using the MESH endpoint lookup service.
+type Repository struct {
+	client     *Client
+	workflowID string
+	mailboxes  map[string]string // ODS code -> mailbox identifier
+}
+
+// NewRepository creates a new MESH document repository using the workflow and mailbox mappings specified
+func NewRepository(client *Client, workflowID string, mailboxes map[string]string) *Repository {
+	m := make(map[string]string)
+	for ods, mailbox := range mailboxes {
+		m[strings.ToUpper(ods)] = mailbox
+	}
+	return &Repository{client: client, workflowID: workflowID, mailboxes: m}
+}
+
+// MailboxForPractice returns the MESH mailbox for the specified general practice ODS code
+func (repo *Repository) MailboxForPractice(ctx context.Context, odsCode string) (string, error) {
+	if mailbox, ok := repo.mailboxes[strings.ToUpper(odsCode)]; ok {
+		return mailbox, nil
+	}
+	return repo.client.LookupMailbox(ctx, odsCode, repo.workflowID)
+}
+
+// PublishDocument sends the document to the mailbox for the patient's registered general practice
+func (repo *Repository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	d := r.GetDocument()
+	surgery := d.GetPatient().GetSurgery().GetValue()
+	if surgery == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to send document '%s|%s' via MESH: no registered general practice", d.GetId().GetSystem(), d.GetId().GetValue())
+	}
+	mailbox, err := repo.MailboxForPractice(ctx, surgery)
+	if err != nil {
+		log.Printf("mesh: unable to determine mailbox for practice '%s': %s", surgery, err)
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to determine MESH mailbox for practice '%s'", surgery)
+	}
+	messageID, err := repo.client.Send(ctx, &Message{
+		To:          mailbox,
+		WorkflowID:  repo.workflowID,
+		Subject:     d.GetTitle(),
+		LocalID:     d.GetId().GetValue(),
+		Filename:    filename(d),
+		ContentType: d.GetData().GetContentType(),
+		Data:        d.GetData().GetData(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.MESHMessageID, Value: messageID}}, nil
+}
+
+// Track returns the delivery status of a message sent by this repository
+func (repo *Repository) Track(ctx context.Context, messageID *apiv1.Identifier) (apiv1.Delivery_Status, error) {
+	if messageID.GetSystem() != identifiers.MESHMessageID {
+		return apiv1.Delivery_UNKNOWN, status.Errorf(codes.InvalidArgument, "unsupported message identifier: %s", messageID.GetSystem())
+	}
+	s, err := repo.client.Track(ctx, messageID.GetValue())
+	if err != nil {
+		return apiv1.Delivery_UNKNOWN, err
+	}
+	switch strings.ToLower(s) {
+	case "acknowledged":
+		return apiv1.Delivery_ACKNOWLEDGED, nil
+	case "undeliverable", "error", "expired":
+		return apiv1.Delivery_FAILED, nil
+	}
+	return apiv1.Delivery_SENT, nil
+}
+
+func filename(d *apiv1.Document) string {
+	ext := ""
+	switch d.GetData().GetContentType() {
+	case "application/pdf":
+		ext = ".pdf"
+	case "text/plain":
+		ext = ".txt"
+	case "application/xml", "text/xml":
+		ext = ".xml"
+	}
+	return fmt.Sprintf("%s%s", d.GetId().GetValue(), ext)
+}
+
+// IsEnglishPractice returns whether the ODS code specified is likely to represent a general practice in England.
+// General practices in Wales have ODS codes prefixed with 'W'.
+func IsEnglishPractice(odsCode string) bool {
+	return len(odsCode) > 0 && !strings.HasPrefix(strings.ToUpper(odsCode), "W")
+}
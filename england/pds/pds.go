@@ -0,0 +1,349 @@
+// Package pds provides a client for the NHS England Personal Demographics Service (PDS) FHIR API,
+// permitting retrieval of patients registered in England by NHS number.
+// See https://digital.nhs.uk/developer/api-catalogue/personal-demographics-service-fhir
+//
+// Access uses application-restricted authentication: a JWT, signed using a private key whose public key
+// has been registered with NHS Digital, is exchanged for a short-lived access token.
+package pds
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/identifiers/validate"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Environments available for the PDS FHIR API
+var Environments = map[string]string{
+	"sandbox": "https://sandbox.api.service.nhs.uk", // no authentication, fixed test data
+	"int":     "https://int.api.service.nhs.uk",     // integration testing
+	"prod":    "https://api.service.nhs.uk",         // production
+}
+
+const (
+	pdsPath   = "/personal-demographics/FHIR/R4"
+	tokenPath = "/oauth2/token"
+)
+
+// App is a client for the PDS FHIR API. This is thread-safe.
+type App struct {
+	Environment string          // one of the keys of Environments
+	BaseURL     string          // overrides the URL for the environment, if set
+	APIKey      string          // API key of the application registered with NHS Digital
+	KeyID       string          // identifier of the registered public key
+	PrivateKey  *rsa.PrivateKey // private key used to sign authentication requests
+	Fake        bool
+
+	client      *http.Client
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// New creates a new PDS client for the environment specified, using the API key, key identifier and
+// private key (a PEM encoded file) for authentication. A private key is not needed for the sandbox.
+func New(env string, apiKey string, keyID string, privateKeyFile string, fake bool) (*App, error) {
+	if _, ok := Environments[env]; !ok && !fake {
+		return nil, fmt.Errorf("pds: unknown environment '%s'", env)
+	}
+	app := &App{Environment: env, APIKey: apiKey, KeyID: keyID, Fake: fake, client: transport.NewClient("pds", nil, true)}
+	if privateKeyFile != "" {
+		b, err := ioutil.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if app.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM(b); err != nil {
+			return nil, fmt.Errorf("pds: invalid private key: %w", err)
+		}
+	}
+	if env != "sandbox" && !fake && (apiKey == "" || keyID == "" || app.PrivateKey == nil) {
+		return nil, fmt.Errorf("pds: environment '%s' requires an API key, key id and private key", env)
+	}
+	return app, nil
+}
+
+// ResolveIdentifier provides identifier resolution for NHS numbers (see identifiers.NHSNumber)
+func (app *App) ResolveIdentifier(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return app.GetPatient(ctx, id)
+}
+
+// GetPatient returns the patient with the specified NHS number
+func (app *App) GetPatient(ctx context.Context, id *apiv1.Identifier) (pt *apiv1.Patient, err error) {
+	defer metrics.Observe("pds", "fetch", time.Now(), &err)
+	if id.GetSystem() != identifiers.NHSNumber {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported identifier system: %s. supported: %s", id.GetSystem(), identifiers.NHSNumber)
+	}
+	nnn, err := validate.NHSNumber(id.GetValue())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid NHS number: %s", id.GetValue())
+	}
+	if app.Fake {
+		log.Printf("pds: returning fake result for %s", nnn)
+		if pt, found := simulator.Current().Patient(simulator.PDS, &apiv1.Identifier{System: identifiers.NHSNumber, Value: nnn}); found {
+			return pt, nil
+		}
+		return nil, i18n.Errorf(ctx, codes.NotFound, "patient %s/%s not found", id.GetSystem(), nnn)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.baseURL()+pdsPath+"/Patient/"+nnn, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/fhir+json")
+	req.Header.Set("X-Request-ID", uuid.New().String())
+	if app.Environment != "sandbox" {
+		token, err := app.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := app.client.Do(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "pds: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, i18n.Errorf(ctx, codes.NotFound, "patient %s/%s not found", id.GetSystem(), nnn)
+	case http.StatusBadRequest:
+		return nil, status.Errorf(codes.InvalidArgument, "pds: invalid request for %s: %s", nnn, body)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		log.Printf("pds: access denied: %s", body)
+		return nil, status.Errorf(codes.Unavailable, "pds: access denied (%d)", resp.StatusCode)
+	default:
+		return nil, status.Errorf(codes.Unavailable, "pds: unexpected response (%d): %s", resp.StatusCode, body)
+	}
+	var p patient
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, status.Errorf(codes.Internal, "pds: invalid response: %s", err)
+	}
+	return p.toPatient(), nil
+}
+
+func (app *App) baseURL() string {
+	if app.BaseURL != "" {
+		return app.BaseURL
+	}
+	return Environments[app.Environment]
+}
+
+// accessToken returns an access token, requesting a new token if there is no token or it has expired
+func (app *App) accessToken(ctx context.Context) (string, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	if app.token != "" && time.Now().Before(app.tokenExpiry) {
+		return app.token, nil
+	}
+	assertion, err := app.clientAssertion()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, app.baseURL()+tokenPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := app.client.Do(req)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "pds: authentication failed: %s", err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"` // seconds, as a string
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		log.Printf("pds: authentication failed (%d): %s", resp.StatusCode, body)
+		return "", status.Errorf(codes.Unavailable, "pds: authentication failed (%d)", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", status.Errorf(codes.Unavailable, "pds: invalid authentication response: %s", err)
+	}
+	expiresIn, _ := strconv.Atoi(result.ExpiresIn)
+	app.token = result.AccessToken
+	app.tokenExpiry = time.Now().Add(time.Duration(expiresIn)*time.Second - 30*time.Second) // renew before expiry
+	return app.token, nil
+}
+
+// clientAssertion returns a signed JWT used to authenticate this application
+func (app *App) clientAssertion() (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS512, &jwt.StandardClaims{
+		Issuer:    app.APIKey,
+		Subject:   app.APIKey,
+		Audience:  app.baseURL() + tokenPath,
+		Id:        uuid.New().String(),
+		ExpiresAt: now.Add(5 * time.Minute).Unix(), // maximum permitted
+	})
+	token.Header["kid"] = app.KeyID
+	return token.SignedString(app.PrivateKey)
+}
+
+// patient is the subset of a FHIR R4 Patient resource returned by PDS used by concierge
+type patient struct {
+	Identifier []struct {
+		System string `json:"system"`
+		Value  string `json:"value"`
+	} `json:"identifier"`
+	Name []struct {
+		Use    string   `json:"use"`
+		Family string   `json:"family"`
+		Given  []string `json:"given"`
+		Prefix []string `json:"prefix"`
+	} `json:"name"`
+	Gender           string `json:"gender"`
+	BirthDate        string `json:"birthDate"`
+	DeceasedDateTime string `json:"deceasedDateTime"`
+	Address          []struct {
+		Use        string   `json:"use"`
+		Line       []string `json:"line"`
+		PostalCode string   `json:"postalCode"`
+		Period     period   `json:"period"`
+	} `json:"address"`
+	Telecom []struct {
+		System string `json:"system"`
+		Value  string `json:"value"`
+		Use    string `json:"use"`
+	} `json:"telecom"`
+	GeneralPractitioner []struct {
+		Identifier struct {
+			System string `json:"system"`
+			Value  string `json:"value"`
+		} `json:"identifier"`
+	} `json:"generalPractitioner"`
+}
+
+type period struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// toPatient converts a FHIR patient to a concierge patient
+// addressUses maps the FHIR address-use value set to address use
+var addressUses = map[string]apiv1.Address_Use{
+	"home": apiv1.Address_HOME,
+	"temp": apiv1.Address_TEMPORARY,
+	"work": apiv1.Address_WORK,
+	"old":  apiv1.Address_OLD,
+}
+
+// telephoneUses maps the FHIR contact-point-use value set to telephone use
+var telephoneUses = map[string]apiv1.Telephone_Use{
+	"home":   apiv1.Telephone_HOME,
+	"work":   apiv1.Telephone_WORK,
+	"mobile": apiv1.Telephone_MOBILE,
+}
+
+func (p *patient) toPatient() *apiv1.Patient {
+	pt := &apiv1.Patient{}
+	for _, id := range p.Identifier {
+		pt.Identifiers = append(pt.Identifiers, &apiv1.Identifier{System: id.System, Value: id.Value})
+	}
+	for _, name := range p.Name {
+		if name.Use == "usual" || pt.Lastname == "" {
+			pt.Lastname = name.Family
+			pt.Firstnames = strings.Join(name.Given, " ")
+			pt.Title = ""
+			if len(name.Prefix) > 0 {
+				pt.Title = name.Prefix[0]
+			}
+		}
+	}
+	switch p.Gender {
+	case "male":
+		pt.Gender = apiv1.Gender_MALE
+	case "female":
+		pt.Gender = apiv1.Gender_FEMALE
+	}
+	if dt, err := parseDate(p.BirthDate); err == nil {
+		pt.BirthDate = apiv1.NewDate(dt)
+	}
+	if dt, err := parseDate(p.DeceasedDateTime); err == nil {
+		if ts := timestamppb.New(dt); ts.CheckValid() == nil {
+			pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: ts}
+		}
+	}
+	for _, a := range p.Address {
+		address := &apiv1.Address{Postcode: a.PostalCode, Use: addressUses[a.Use], Period: a.Period.toPeriod()}
+		for i, line := range a.Line {
+			switch i {
+			case 0:
+				address.Address1 = line
+			case 1:
+				address.Address2 = line
+			case 2:
+				address.Address3 = line
+			default:
+				address.Address3 += ", " + line
+			}
+		}
+		pt.Addresses = append(pt.Addresses, address)
+	}
+	for _, t := range p.Telecom {
+		switch t.System {
+		case "phone":
+			pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: t.Value, Description: t.Use, Use: telephoneUses[t.Use]})
+		case "email":
+			pt.Emails = append(pt.Emails, t.Value)
+		}
+	}
+	if len(p.GeneralPractitioner) > 0 {
+		pt.Surgery = identifiers.New(identifiers.ODSCode, p.GeneralPractitioner[0].Identifier.Value)
+	}
+	return pt
+}
+
+func (p period) toPeriod() *apiv1.Period {
+	if p.Start == "" && p.End == "" {
+		return nil
+	}
+	result := &apiv1.Period{}
+	if dt, err := parseDate(p.Start); err == nil {
+		result.Start = timestamppb.New(dt)
+	}
+	if dt, err := parseDate(p.End); err == nil {
+		result.End = timestamppb.New(dt)
+	}
+	return result
+}
+
+// parseDate parses a FHIR date or dateTime
+func parseDate(s string) (time.Time, error) {
+	if len(s) == len("2006-01-02") {
+		return time.Parse("2006-01-02", s)
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,90 @@
+package pds
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPatient = `{
+  "resourceType": "Patient",
+  "id": "9000000009",
+  "identifier": [{"system": "https://fhir.nhs.uk/Id/nhs-number", "value": "9000000009"}],
+  "name": [
+    {"use": "usual", "family": "Smith", "given": ["Jane", "Elizabeth"], "prefix": ["Mrs"]},
+    {"use": "old", "family": "Jones", "given": ["Jane"]}
+  ],
+  "gender": "female",
+  "birthDate": "2010-10-22",
+  "deceasedDateTime": "2010-10-22T00:00:00+00:00",
+  "address": [{"use": "home", "line": ["1 Trevelyan Square", "Boar Lane", "City Centre", "Leeds", "West Yorkshire"], "postalCode": "LS1 6AE", "period": {"start": "2020-01-01"}}],
+  "telecom": [{"system": "phone", "value": "01632960587", "use": "home"}, {"system": "email", "value": "jane.smith@example.com", "use": "home"}],
+  "generalPractitioner": [{"type": "Organization", "identifier": {"system": "https://fhir.nhs.uk/Id/ods-organization-code", "value": "Y12345"}}]
+}`
+
+func TestGetPatient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case tokenPath:
+			tokenRequests++
+			assertion, err := jwt.Parse(r.FormValue("client_assertion"), func(token *jwt.Token) (interface{}, error) {
+				if token.Header["kid"] != "test-1" {
+					return nil, fmt.Errorf("unexpected kid: %v", token.Header["kid"])
+				}
+				return &key.PublicKey, nil
+			})
+			if err != nil || assertion.Claims.(jwt.MapClaims)["iss"] != "api-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": "599", "token_type": "Bearer"}`)
+		case pdsPath + "/Patient/9000000009":
+			if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("X-Request-ID") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, testPatient)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	app := &App{Environment: "int", BaseURL: ts.URL, APIKey: "api-key", KeyID: "test-1", PrivateKey: key, client: ts.Client()}
+	for i := 0; i < 2; i++ {
+		pt, err := app.GetPatient(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "900 000 0009"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.GetLastname() != "Smith" || pt.GetFirstnames() != "Jane Elizabeth" || pt.GetTitle() != "Mrs" || pt.GetGender() != apiv1.Gender_FEMALE || pt.GetBirthDate().GetYear() != 2010 {
+			t.Fatalf("unexpected patient: %v", pt)
+		}
+		if pt.GetDeceasedDate() == nil || pt.GetSurgery().GetValue() != "Y12345" || len(pt.GetEmails()) != 1 || len(pt.GetTelephones()) != 1 {
+			t.Fatalf("unexpected patient: %v", pt)
+		}
+		if addr := pt.GetAddresses()[0]; addr.GetAddress3() != "City Centre, Leeds, West Yorkshire" || addr.GetPostcode() != "LS1 6AE" {
+			t.Fatalf("unexpected address: %v", addr)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected access token to be reused, but requested %d times", tokenRequests)
+	}
+	_, err = app.GetPatient(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9449305552"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
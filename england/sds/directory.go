@@ -0,0 +1,302 @@
+package sds
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	ldap "gopkg.in/ldap.v3"
+)
+
+// DefaultAddr is the address of the national SDS LDAP service, available from the Health and Social Care Network (HSCN)
+const DefaultAddr = "ldap://ldap.nis.national.ncrs.nhs.uk:389"
+
+// DefaultMaxResults is the default maximum number of results returned by a practitioner search
+const DefaultMaxResults = 50
+
+// requestTimeout is the maximum time to wait for a response to a single directory request
+const requestTimeout = 10 * time.Second
+
+// baseDN is the base of the people in the directory
+const baseDN = "ou=People,o=nhs"
+
+// attributes are the directory attributes fetched for each person
+var attributes = []string{
+	"uniqueIdentifier", // SDS user id
+	"personalTitle",
+	"givenName",
+	"sn",
+	"mail",
+	"telephoneNumber",
+	"mobile",
+}
+
+// App provides practitioner lookup using the NHS England Spine Directory Service (SDS)
+type App struct {
+	Addr       string // LDAP URL of the SDS service; DefaultAddr if empty
+	MaxResults int    // maximum number of results from a practitioner search; DefaultMaxResults if zero
+	Fake       bool
+
+	apiv1.UnimplementedPractitionerDirectoryServer // SDS does not provide photographs
+}
+
+var _ apiv1.PractitionerDirectoryServer = (*App)(nil)
+
+// ResolvePractitioner provides identifier resolution for the SDS user namespace (see identifiers.SDSUserID)
+func (app *App) ResolvePractitioner(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return app.GetPractitioner(ctx, id)
+}
+
+// GetPractitioner returns the specified practitioner, including their current job roles
+func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (p *apiv1.Practitioner, err error) {
+	defer metrics.Observe("sds", "fetch", time.Now(), &err)
+	if r.GetSystem() != identifiers.SDSUserID {
+		return nil, fmt.Errorf("unsupported identifier system: %s. supported: %s", r.GetSystem(), identifiers.SDSUserID)
+	}
+	log.Printf("sds: request for %s|%s", r.GetSystem(), r.GetValue())
+	var entries []*ldap.Entry
+	var roles []*ldap.Entry
+	if app.Fake {
+		entries, roles = fakeUser(r.GetValue())
+	} else {
+		conn, err := app.connect()
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		if entries, err = search(conn, baseDN, ldap.ScopeWholeSubtree, userFilter(r.GetValue()), attributes, 2); err != nil {
+			return nil, err
+		}
+		if len(entries) == 1 {
+			if roles, err = search(conn, entries[0].DN, ldap.ScopeSingleLevel, "(objectClass=nhsOrgPersonRole)", []string{"nhsJobRoleCode"}, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(entries) == 0 {
+		log.Printf("sds: user %s|%s not found", r.GetSystem(), r.GetValue())
+		return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", r.GetSystem(), r.GetValue())
+	}
+	if len(entries) > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "more than one match for user id %s", r.GetValue())
+	}
+	p = practitionerFromEntry(entries[0])
+	for _, role := range roles {
+		if pr := practitionerRole(role.GetAttributeValue("nhsJobRoleCode")); pr != nil {
+			p.Roles = append(p.Roles, pr)
+		}
+	}
+	return p, nil
+}
+
+// SearchPractitioner permits a search for a practitioner by user id, or by name.
+func (app *App) SearchPractitioner(r *apiv1.PractitionerSearchRequest, s apiv1.PractitionerDirectory_SearchPractitionerServer) (err error) {
+	defer metrics.Observe("sds", "search", time.Now(), &err)
+	if r.GetSystem() != identifiers.SDSUserID {
+		return status.Errorf(codes.InvalidArgument, "practitioner search for namespace '%s' not supported", r.GetSystem())
+	}
+	if r.GetFirstName() == "" && r.GetLastName() == "" {
+		if r.GetUsername() != "" {
+			p, err := app.GetPractitioner(s.Context(), &apiv1.Identifier{System: r.GetSystem(), Value: r.GetUsername()})
+			if err != nil {
+				return err
+			}
+			return s.Send(p)
+		}
+		return status.Errorf(codes.InvalidArgument, "no search parameters specified: SDS supports search by name")
+	}
+	log.Printf("sds: search for first:'%s' last:'%s'", r.GetFirstName(), r.GetLastName())
+	var entries []*ldap.Entry
+	if app.Fake {
+		entries = fakeSearch(r)
+	} else {
+		conn, err := app.connect()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if entries, err = search(conn, baseDN, ldap.ScopeWholeSubtree, searchFilter(r), attributes, app.maxResults()); err != nil {
+			return err
+		}
+	}
+	sortEntries(entries)
+	for i, entry := range entries {
+		if i >= app.maxResults() {
+			break
+		}
+		if err := s.Send(practitionerFromEntry(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortEntries sorts entries by surname, given name and then user id, so that results are in a consistent order
+func sortEntries(entries []*ldap.Entry) {
+	key := func(e *ldap.Entry) string {
+		return strings.ToLower(e.GetAttributeValue("sn")) + "\x00" + strings.ToLower(e.GetAttributeValue("givenName")) + "\x00" + e.GetAttributeValue("uniqueIdentifier")
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return key(entries[i]) < key(entries[j])
+	})
+}
+
+// Check checks that the directory can be reached
+func (app *App) Check(ctx context.Context) error {
+	if app.Fake {
+		return nil
+	}
+	conn, err := app.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = search(conn, "", ldap.ScopeBaseObject, "(objectClass=*)", []string{"namingContexts"}, 1)
+	return err
+}
+
+func (app *App) maxResults() int {
+	if app.MaxResults > 0 {
+		return app.MaxResults
+	}
+	return DefaultMaxResults
+}
+
+// connect connects to the directory. The SDS service permits anonymous access from within the HSCN.
+func (app *App) connect() (*ldap.Conn, error) {
+	addr := app.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	conn, err := ldap.DialURL(addr)
+	if err != nil {
+		log.Printf("sds: failed to connect to %s: %s", addr, err)
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to SDS: %s", err)
+	}
+	conn.SetTimeout(requestTimeout)
+	return conn, nil
+}
+
+// search searches the directory, returning at most max entries, or all entries if max is zero
+func search(conn *ldap.Conn, base string, scope int, filter string, attributes []string, max int) ([]*ldap.Entry, error) {
+	req := ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases, max, int(requestTimeout.Seconds()), false, filter, attributes, nil)
+	sr, err := conn.Search(req)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, err
+	}
+	if sr == nil {
+		return []*ldap.Entry{}, nil
+	}
+	return sr.Entries, nil
+}
+
+// userFilter returns an LDAP filter for the person with the SDS user id specified
+func userFilter(id string) string {
+	return fmt.Sprintf("(&(objectClass=nhsPerson)(uniqueIdentifier=%s))", ldap.EscapeFilter(id))
+}
+
+// searchFilter returns an LDAP filter for a search by name
+func searchFilter(r *apiv1.PractitionerSearchRequest) string {
+	var terms strings.Builder
+	if v := strings.TrimSpace(r.GetLastName()); v != "" {
+		fmt.Fprintf(&terms, "(sn=%s*)", ldap.EscapeFilter(v))
+	}
+	if v := strings.TrimSpace(r.GetFirstName()); v != "" {
+		fmt.Fprintf(&terms, "(givenName=%s*)", ldap.EscapeFilter(v))
+	}
+	return fmt.Sprintf("(&(objectClass=nhsPerson)%s)", terms.String())
+}
+
+// practitionerFromEntry creates a practitioner from a directory entry
+func practitionerFromEntry(entry *ldap.Entry) *apiv1.Practitioner {
+	name := &apiv1.HumanName{
+		Given:  entry.GetAttributeValue("givenName"),
+		Family: entry.GetAttributeValue("sn"),
+		Use:    apiv1.HumanName_OFFICIAL,
+	}
+	if title := entry.GetAttributeValue("personalTitle"); title != "" {
+		name.Prefixes = []string{title}
+	}
+	phones := make([]*apiv1.Telephone, 0)
+	if n := entry.GetAttributeValue("mobile"); n != "" {
+		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Mobile", Use: apiv1.Telephone_MOBILE})
+	}
+	if n := entry.GetAttributeValue("telephoneNumber"); n != "" {
+		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Office", Use: apiv1.Telephone_WORK})
+	}
+	p := &apiv1.Practitioner{
+		Active: true,
+		Identifiers: []*apiv1.Identifier{
+			{System: identifiers.SDSUserID, Value: entry.GetAttributeValue("uniqueIdentifier")},
+		},
+		Names:      []*apiv1.HumanName{name},
+		Telephones: phones,
+	}
+	if mail := entry.GetAttributeValue("mail"); mail != "" {
+		p.Emails = []string{mail}
+	}
+	return p
+}
+
+// practitionerRole creates a role from an SDS job role code, which is of the form
+// "S0010:G0020:R0260" (staff group, sub-group and job role name), or nil if there is no job role name.
+func practitionerRole(code string) *apiv1.PractitionerRole {
+	parts := strings.Split(code, ":")
+	name := parts[len(parts)-1]
+	if !strings.HasPrefix(name, "R") {
+		return nil
+	}
+	role := &apiv1.Role{Identifier: &apiv1.Identifier{System: identifiers.SDSJobRoleNameURI, Value: name}}
+	if r, ok := jobRoles[name]; ok {
+		role.JobTitle = r.GetJobTitle()
+		role.Deprecated = r.GetDeprecated()
+	}
+	return &apiv1.PractitionerRole{Role: role}
+}
+
+// fakeUsers are directory entries used in fake mode
+var fakeUsers = []*ldap.Entry{
+	ldap.NewEntry("uniqueIdentifier=555021935107,ou=People,o=nhs", map[string][]string{"uniqueIdentifier": {"555021935107"}, "personalTitle": {"Dr"}, "givenName": {"Fred"}, "sn": {"Flintstone"}, "mail": {"fred.flintstone@nhs.net"}}),
+	ldap.NewEntry("uniqueIdentifier=555021936108,ou=People,o=nhs", map[string][]string{"uniqueIdentifier": {"555021936108"}, "personalTitle": {"Mrs"}, "givenName": {"Wilma"}, "sn": {"Flintstone"}, "mail": {"wilma.flintstone@nhs.net"}}),
+}
+
+// fakeRoles are the job roles of the fake users
+var fakeRoles = map[string][]string{
+	"555021935107": {"S8000:G8000:R8000", "S0010:G0020:R0260"},
+	"555021936108": {"S8001:G8005:R8008"},
+}
+
+// fakeUser returns the fake user with the id specified, and their roles
+func fakeUser(id string) (entries []*ldap.Entry, roles []*ldap.Entry) {
+	for _, e := range fakeUsers {
+		if e.GetAttributeValue("uniqueIdentifier") == id {
+			for _, code := range fakeRoles[id] {
+				roles = append(roles, ldap.NewEntry("", map[string][]string{"nhsJobRoleCode": {code}}))
+			}
+			return []*ldap.Entry{e}, roles
+		}
+	}
+	return nil, nil
+}
+
+// fakeSearch returns the fake users matching the search, as per searchFilter
+func fakeSearch(r *apiv1.PractitionerSearchRequest) []*ldap.Entry {
+	hasPrefix := func(s, prefix string) bool { return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix)) }
+	result := make([]*ldap.Entry, 0)
+	for _, e := range fakeUsers {
+		if hasPrefix(e.GetAttributeValue("sn"), r.GetLastName()) && hasPrefix(e.GetAttributeValue("givenName"), r.GetFirstName()) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
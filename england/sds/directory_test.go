@@ -0,0 +1,52 @@
+package sds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestPractitionerRole(t *testing.T) {
+	tests := []struct {
+		code     string
+		role     string
+		jobTitle string
+	}{
+		{"S0010:G0020:R0260", "R0260", "General Medical Practitioner"},
+		{"R0050", "R0050", "Consultant"},
+		{"S8000:G8000", "", ""},
+	}
+	for _, test := range tests {
+		pr := practitionerRole(test.code)
+		if test.role == "" {
+			if pr != nil {
+				t.Errorf("expected no role for %s, got %v", test.code, pr)
+			}
+			continue
+		}
+		if pr.GetRole().GetIdentifier().GetValue() != test.role || pr.GetRole().GetJobTitle() != test.jobTitle {
+			t.Errorf("role for %s: expected %s (%s), got %v", test.code, test.role, test.jobTitle, pr)
+		}
+	}
+}
+
+func TestSearchFilter(t *testing.T) {
+	r := &apiv1.PractitionerSearchRequest{System: identifiers.SDSUserID, FirstName: "fred", LastName: "o*)(x=y"}
+	expected := "(&(objectClass=nhsPerson)(sn=o\\2a\\29\\28x=y*)(givenName=fred*))"
+	if got := searchFilter(r); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestFakePractitioner(t *testing.T) {
+	app := &App{Fake: true}
+	p, err := app.GetPractitioner(context.Background(), &apiv1.Identifier{System: identifiers.SDSUserID, Value: "555021935107"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.GetRoles()) != 2 || p.GetNames()[0].GetFamily() != "Flintstone" {
+		t.Fatalf("unexpected practitioner: %v", p)
+	}
+}
@@ -22,7 +22,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-var codes = make(map[string]*apiv1.Role)
+var jobRoles = make(map[string]*apiv1.Role)
 var jobTitles = make(map[string]string)
 
 func init() {
@@ -41,7 +41,7 @@ func init() {
 			deprecated = true
 		}
 		jobTitle := strings.Join(words[1:], " ")
-		codes[code] = &apiv1.Role{
+		jobRoles[code] = &apiv1.Role{
 			JobTitle:   jobTitle,
 			Deprecated: deprecated,
 		}
@@ -58,7 +58,7 @@ func init() {
 
 // roleResolver provides a resolution service for the SDS role value set
 func roleResolver(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
-	if role, ok := codes[id.Value]; ok {
+	if role, ok := jobRoles[id.Value]; ok {
 		log.Printf("sds: resolving %s|%s to %+v", id.System, id.Value, role)
 		return role, nil
 	}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	nats "github.com/nats-io/nats.go"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// NewPublisher creates a publisher for the named broker ("nats", "kafka" or "log").
+// For NATS, events are published to the subject <topic>.<event-type>.
+// For Kafka, events are published to the topic specified, keyed by the event subject.
+func NewPublisher(broker string, addr string, topic string) (Publisher, error) {
+	if topic == "" {
+		topic = "concierge"
+	}
+	switch broker {
+	case "nats":
+		return NewNATSPublisher(addr, topic)
+	case "kafka":
+		return NewKafkaPublisher(strings.Split(addr, ","), topic), nil
+	case "log":
+		return &logPublisher{}, nil
+	}
+	return nil, fmt.Errorf("events: unsupported broker: '%s'. supported: nats, kafka, log", broker)
+}
+
+type natsPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSPublisher creates a publisher that publishes events to a NATS server
+// using the subject <prefix>.<event-type> e.g. concierge.document-published
+func NewNATSPublisher(url string, prefix string) (Publisher, error) {
+	if url == "" {
+		url = nats.DefaultURL
+	}
+	conn, err := nats.Connect(url, nats.Name("concierge"), nats.Timeout(5*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to connect to nats server '%s': %w", url, err)
+	}
+	return &natsPublisher{conn: conn, prefix: prefix}, nil
+}
+
+func (np *natsPublisher) Publish(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return np.conn.Publish(np.prefix+"."+string(e.Type), b)
+}
+
+func (np *natsPublisher) Close() error {
+	if err := np.conn.Flush(); err != nil {
+		return err
+	}
+	np.conn.Close()
+	return nil
+}
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher that publishes events to the specified Kafka topic.
+// Messages are keyed using the event subject so that events for a single subject are ordered.
+func NewKafkaPublisher(brokers []string, topic string) Publisher {
+	return &kafkaPublisher{
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:  brokers,
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		}),
+	}
+}
+
+func (kp *kafkaPublisher) Publish(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return kp.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(e.Subject.GetSystem() + "|" + e.Subject.GetValue()),
+		Value:   b,
+		Time:    e.Time,
+		Headers: []kafka.Header{{Key: "type", Value: []byte(e.Type)}},
+	})
+}
+
+func (kp *kafkaPublisher) Close() error {
+	return kp.writer.Close()
+}
+
+// logPublisher simply logs events, useful for testing
+type logPublisher struct{}
+
+func (lp *logPublisher) Publish(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	log.Printf("events: %s", b)
+	return nil
+}
+
+func (lp *logPublisher) Close() error { return nil }
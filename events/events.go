@@ -0,0 +1,233 @@
+// Package events provides publication of structured events (e.g. patient updated, document published)
+// to one or more configurable brokers so that downstream systems can react asynchronously
+// rather than polling concierge APIs.
+//
+// Events may also be delivered to webhook URLs, registered using 'concierge events subscribe', with
+// each payload signed using a secret shared with the subscriber.
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Type represents the type of event
+type Type string
+
+// The types of event published by concierge
+const (
+	PatientUpdated    Type = "patient-updated"    // demographics for a patient have changed since last seen
+	DocumentPublished Type = "document-published" // a document has been successfully published to a repository
+	DeliveryFailed    Type = "delivery-failed"    // a document could not be delivered
+	PatientMerged     Type = "patient-merged"     // a patient record has been merged into another; data is the identifier of the merged record
+	PatientDeceased   Type = "patient-deceased"   // a patient has died; data is the patient
+)
+
+// Event is a structured event published by concierge
+type Event struct {
+	ID      string              // unique identifier for this event
+	Type    Type                // type of event
+	Time    time.Time           // time at which the event occurred
+	Subject *apiv1.Identifier   // subject of the event, e.g. patient or document identifier
+	Patient []*apiv1.Identifier // optional identifiers of the patient, if the subject is not the patient e.g. for documents
+	Data    proto.Message       // optional payload
+	Error   string              // optional error message, e.g. for failed deliveries
+}
+
+// MarshalJSON serialises an event into JSON, using the protobuf JSON mapping for any payload.
+func (e *Event) MarshalJSON() ([]byte, error) {
+	var data json.RawMessage
+	if e.Data != nil {
+		b, err := protojson.Marshal(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+	var subject *jsonIdentifier
+	if e.Subject != nil {
+		subject = &jsonIdentifier{System: e.Subject.GetSystem(), Value: e.Subject.GetValue()}
+	}
+	var patient []*jsonIdentifier
+	for _, id := range e.Patient {
+		patient = append(patient, &jsonIdentifier{System: id.GetSystem(), Value: id.GetValue()})
+	}
+	return json.Marshal(&jsonEvent{
+		ID:      e.ID,
+		Type:    e.Type,
+		Time:    e.Time,
+		Subject: subject,
+		Patient: patient,
+		Data:    data,
+		Error:   e.Error,
+	})
+}
+
+type jsonIdentifier struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type jsonEvent struct {
+	ID      string            `json:"id"`
+	Type    Type              `json:"type"`
+	Time    time.Time         `json:"time"`
+	Subject *jsonIdentifier   `json:"subject,omitempty"`
+	Patient []*jsonIdentifier `json:"patient,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// Publisher publishes events to a broker
+type Publisher interface {
+	// Publish publishes the event
+	Publish(ctx context.Context, e *Event) error
+	// Close closes any resources associated with this publisher
+	Close() error
+}
+
+var (
+	publishersMu sync.RWMutex
+	publishers   = make(map[string]Publisher)
+	subscribers  []func(*Event)
+)
+
+// publishTimeout is the maximum time permitted for a single publisher to publish an event
+const publishTimeout = 10 * time.Second
+
+// Register registers a publisher by name
+func Register(name string, p Publisher) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+	if _, dup := publishers[name]; dup {
+		panic("events: register called twice for publisher " + name)
+	}
+	publishers[name] = p
+	log.Printf("events: registered publisher: '%s'", name)
+}
+
+// Close closes all registered publishers
+func Close() error {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+	for name, p := range publishers {
+		if err := p.Close(); err != nil {
+			return err
+		}
+		delete(publishers, name)
+	}
+	return nil
+}
+
+// Subscribe registers a function to be called for every event published within this process, such as
+// to invalidate cached data. Subscribers are called synchronously, in order of registration, and must not block.
+func Subscribe(f func(*Event)) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+	subscribers = append(subscribers, f)
+}
+
+// Publish asynchronously publishes an event to all registered publishers, assigning an identifier
+// and time if not already set. Errors are logged, but not returned, as event publication must not
+// fail the originating operation. Subscribers within this process are notified before Publish returns.
+func Publish(e *Event) {
+	publishersMu.RLock()
+	defer publishersMu.RUnlock()
+	if len(publishers) == 0 && len(subscribers) == 0 {
+		return
+	}
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, f := range subscribers {
+		f(e)
+	}
+	for name, p := range publishers {
+		go func(name string, p Publisher) {
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			defer cancel()
+			if err := p.Publish(ctx, e); err != nil {
+				log.Printf("events: failed to publish '%s' event %s via '%s': %s", e.Type, e.ID, name, err)
+			}
+		}(name, p)
+	}
+}
+
+// seen records the patient data last seen for a given key, so we can detect changes
+var seen = cache.New(24*time.Hour, time.Hour)
+
+// seenPatient records a digest of patient data, and whether the patient was deceased
+type seenPatient struct {
+	digest   [sha256.Size]byte
+	deceased bool
+}
+
+// PublishPatientIfChanged publishes a patient-updated event if the patient's data differ from
+// those last seen for the specified key (e.g. authority and identifier), and a patient-deceased
+// event if the patient was last seen alive. Nothing is published the first time a patient is seen.
+func PublishPatientIfChanged(key string, subject *apiv1.Identifier, pt *apiv1.Patient) {
+	current, ok := observe(key, pt)
+	if !ok {
+		return
+	}
+	previous, found := seen.Get(key)
+	seen.SetDefault(key, current)
+	if found && previous.(seenPatient).digest != current.digest {
+		Publish(&Event{Type: PatientUpdated, Subject: subject, Data: pt})
+		if current.deceased && !previous.(seenPatient).deceased {
+			Publish(&Event{Type: PatientDeceased, Subject: subject, Data: pt})
+		}
+	}
+}
+
+// PublishPatientUpdated publishes a patient-updated event for a patient known to have changed,
+// such as from an inbound ADT feed, and a patient-deceased event if the patient was not already
+// known to be deceased for the specified key.
+func PublishPatientUpdated(key string, subject *apiv1.Identifier, pt *apiv1.Patient) {
+	current, ok := observe(key, pt)
+	if !ok {
+		return
+	}
+	previous, found := seen.Get(key)
+	seen.SetDefault(key, current)
+	Publish(&Event{Type: PatientUpdated, Subject: subject, Data: pt})
+	if current.deceased && (!found || !previous.(seenPatient).deceased) {
+		Publish(&Event{Type: PatientDeceased, Subject: subject, Data: pt})
+	}
+}
+
+// PublishMergeIfNew publishes a patient-merged event, unless the same merge has been recently published,
+// for use when merges are inferred from responses to queries that may be repeated.
+func PublishMergeIfNew(subject *apiv1.Identifier, merged *apiv1.Identifier) {
+	key := "merge/" + merged.GetSystem() + "|" + merged.GetValue() + "/" + subject.GetSystem() + "|" + subject.GetValue()
+	if err := seen.Add(key, struct{}{}, cache.DefaultExpiration); err != nil {
+		return // already published
+	}
+	Publish(&Event{Type: PatientMerged, Subject: subject, Data: merged})
+}
+
+// observe returns a record of the patient data specified
+func observe(key string, pt *apiv1.Patient) (seenPatient, bool) {
+	if pt == nil {
+		return seenPatient{}, false
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(pt)
+	if err != nil {
+		log.Printf("events: failed to marshal patient %s: %s", key, err)
+		return seenPatient{}, false
+	}
+	return seenPatient{digest: sha256.Sum256(b), deceased: pt.GetDeceased() != nil}, true
+}
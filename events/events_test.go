@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+type testPublisher struct {
+	events chan *Event
+}
+
+func (tp *testPublisher) Publish(ctx context.Context, e *Event) error {
+	tp.events <- e
+	return nil
+}
+
+func (tp *testPublisher) Close() error { return nil }
+
+func TestPatientUpdated(t *testing.T) {
+	tp := &testPublisher{events: make(chan *Event, 10)}
+	Register("test", tp)
+	defer Close()
+	t.Cleanup(seen.Flush) // forget the patient data seen, so that the test can be run repeatedly
+	id := &apiv1.Identifier{System: "https://fhir.nhs.uk/Id/nhs-number", Value: "1111111111"}
+	pt := &apiv1.Patient{Lastname: "DUMMY", Firstnames: "ALBERT", Identifiers: []*apiv1.Identifier{id}}
+	PublishPatientIfChanged("test/1111111111", id, pt)
+	PublishPatientIfChanged("test/1111111111", id, pt)
+	pt2 := &apiv1.Patient{Lastname: "DUMMY", Firstnames: "ALBERTO", Identifiers: []*apiv1.Identifier{id}}
+	PublishPatientIfChanged("test/1111111111", id, pt2)
+	select {
+	case e := <-tp.events:
+		if e.Type != PatientUpdated {
+			t.Fatalf("expected event type %s, got: %s", PatientUpdated, e.Type)
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(b, &result); err != nil {
+			t.Fatal(err)
+		}
+		if result["type"] != string(PatientUpdated) || result["data"].(map[string]interface{})["firstnames"] != "ALBERTO" {
+			t.Fatalf("unexpected json for event: %s", b)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive patient-updated event")
+	}
+	select {
+	case e := <-tp.events:
+		t.Fatalf("unexpected additional event: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
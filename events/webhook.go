@@ -0,0 +1,281 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Headers sent with each webhook request
+const (
+	HeaderEventID   = "Concierge-Event-Id"
+	HeaderEventType = "Concierge-Event-Type"
+	HeaderSignature = "Concierge-Signature"
+)
+
+// Subscription is a registration of a URL to which events are delivered
+type Subscription struct {
+	ID      string    `json:"id"`
+	URL     string    `json:"url"`
+	Types   []Type    `json:"types,omitempty"` // types of event to be delivered; all events if empty
+	Secret  string    `json:"secret"`          // shared secret used to sign payloads
+	Created time.Time `json:"created"`
+}
+
+// Matches determines whether the event type should be delivered to this subscription
+func (s *Subscription) Matches(t Type) bool {
+	if len(s.Types) == 0 {
+		return true
+	}
+	for _, st := range s.Types {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSubscription creates a new subscription for the URL and types specified, with a random secret.
+// The URL must use https, unless it is for the loopback interface.
+func NewSubscription(u string, types []Type) (*Subscription, error) {
+	if err := validURL(u); err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		if !t.valid() {
+			return nil, fmt.Errorf("events: unsupported event type: '%s'", t)
+		}
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		ID:      uuid.New().String(),
+		URL:     u,
+		Types:   types,
+		Secret:  hex.EncodeToString(secret),
+		Created: time.Now(),
+	}, nil
+}
+
+// validURL checks that the URL is suitable for webhooks: as payloads include patient data, they are only
+// sent without TLS to the loopback interface, such as to a local agent that forwards them securely.
+func validURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("events: invalid webhook url: '%s'", u)
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if host := parsed.Hostname(); host == "localhost" || net.ParseIP(host).IsLoopback() {
+			return nil
+		}
+		return fmt.Errorf("events: webhook url must use https: '%s'", u)
+	}
+	return fmt.Errorf("events: invalid webhook url: '%s'", u)
+}
+
+// Types returns all the types of event published by concierge
+func Types() []Type {
+	return []Type{PatientUpdated, PatientMerged, PatientDeceased, DocumentPublished, DeliveryFailed}
+}
+
+func (t Type) valid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSubscriptions loads webhook subscriptions from the JSON file specified.
+// A file that does not exist is treated as having no subscriptions.
+func LoadSubscriptions(filename string) ([]*Subscription, error) {
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var subs []*Subscription
+	if err := json.Unmarshal(b, &subs); err != nil {
+		return nil, fmt.Errorf("events: invalid webhook subscriptions file '%s': %w", filename, err)
+	}
+	return subs, nil
+}
+
+// SaveSubscriptions saves webhook subscriptions to the JSON file specified. As the file contains
+// the secrets used to sign payloads, it is readable only by the current user.
+func SaveSubscriptions(filename string, subs []*Subscription) error {
+	b, err := json.MarshalIndent(subs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0600)
+}
+
+// Sign returns the signature for a payload sent at the time specified, as used in the Concierge-Signature
+// header: "t=<unix time>,v1=<hex HMAC-SHA256 of '<unix time>.<payload>' using the subscription secret>".
+// Including the time permits recipients to reject replayed requests.
+func Sign(secret string, t time.Time, payload []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, payload))
+}
+
+// VerifySignature verifies a Concierge-Signature header for the payload specified, rejecting
+// signatures older than the tolerance specified, if non-zero.
+func VerifySignature(secret string, header string, payload []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return errors.New("events: invalid signature header")
+	}
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return errors.New("events: signature has expired")
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(expected, mac(secret, ts, payload)) {
+		return errors.New("events: invalid signature")
+	}
+	return nil
+}
+
+func mac(secret string, ts string, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// webhookPublisher delivers events to subscribed URLs, reloading subscriptions whenever the
+// subscriptions file changes, so that subscriptions can be managed without a restart.
+type webhookPublisher struct {
+	filename string
+	client   *http.Client
+
+	mu      sync.Mutex
+	modTime time.Time
+	subs    []*Subscription
+}
+
+// NewWebhookPublisher creates a publisher that delivers events to the webhook subscriptions
+// in the file specified, signing each payload using the secret for the subscription.
+// The client should use the TLS configuration required for subscribers; a default client is used if nil.
+func NewWebhookPublisher(filename string, client *http.Client) (Publisher, error) {
+	if client == nil {
+		client = &http.Client{Timeout: publishTimeout}
+	}
+	wp := &webhookPublisher{filename: filename, client: client}
+	if _, err := wp.subscriptions(); err != nil {
+		return nil, err
+	}
+	return wp, nil
+}
+
+// subscriptions returns the current subscriptions, reloading from file if changed
+func (wp *webhookPublisher) subscriptions() ([]*Subscription, error) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	var modTime time.Time
+	if fi, err := os.Stat(wp.filename); err == nil {
+		modTime = fi.ModTime()
+	}
+	if wp.subs != nil && modTime.Equal(wp.modTime) {
+		return wp.subs, nil
+	}
+	subs, err := LoadSubscriptions(wp.filename)
+	if err != nil {
+		return wp.subs, err
+	}
+	if subs == nil {
+		subs = make([]*Subscription, 0)
+	}
+	wp.subs, wp.modTime = subs, modTime
+	return subs, nil
+}
+
+func (wp *webhookPublisher) Publish(ctx context.Context, e *Event) error {
+	subs, err := wp.subscriptions()
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	var errs []string
+	for _, sub := range subs {
+		if !sub.Matches(e.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				return err
+			}
+		}
+		if err := wp.deliver(ctx, sub, e, payload); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (wp *webhookPublisher) deliver(ctx context.Context, sub *Subscription, e *Event, payload []byte) error {
+	if err := validURL(sub.URL); err != nil { // the subscriptions file may have been edited by hand
+		return fmt.Errorf("webhook %s: %w", sub.ID, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, e.ID)
+	req.Header.Set(HeaderEventType, string(e.Type))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, time.Now(), payload))
+	resp, err := wp.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", sub.ID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: %s returned status %s", sub.ID, sub.URL, resp.Status)
+	}
+	return nil
+}
+
+func (wp *webhookPublisher) Close() error { return nil }
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+func TestWebhook(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		received <- r
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "webhooks.json")
+	p, err := NewWebhookPublisher(filename, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Event{ID: "1", Type: DocumentPublished, Subject: &apiv1.Identifier{System: "https://example.com/Id/document", Value: "1"}}
+	if err := p.Publish(context.Background(), e); err != nil || len(received) != 0 {
+		t.Fatalf("expected no delivery without subscriptions: %v", err)
+	}
+	if _, err := NewSubscription("ftp://example.com", nil); err == nil {
+		t.Errorf("expected error for invalid url")
+	}
+	if _, err := NewSubscription("http://example.com/webhook", nil); err == nil {
+		t.Errorf("expected error for url without https")
+	}
+	if _, err := NewSubscription("https://example.com/webhook", nil); err != nil {
+		t.Errorf("expected https url to be accepted: %s", err)
+	}
+	if _, err := NewSubscription(ts.URL, []Type{"unknown"}); err == nil {
+		t.Errorf("expected error for unknown event type")
+	}
+	sub, err := NewSubscription(ts.URL, []Type{DocumentPublished})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSubscriptions(filename, []*Subscription{sub}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond) // ensure modification time changes
+	if err := p.Publish(context.Background(), &Event{ID: "2", Type: PatientUpdated}); err != nil || len(received) != 0 {
+		t.Fatalf("expected no delivery for unsubscribed event type: %v", err)
+	}
+	if err := p.Publish(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	r := <-received
+	if r.Header.Get(HeaderEventType) != string(DocumentPublished) || r.Header.Get(HeaderEventID) != "1" {
+		t.Errorf("unexpected headers: %v", r.Header)
+	}
+	if err := VerifySignature(sub.Secret, r.Header.Get(HeaderSignature), body, time.Minute); err != nil {
+		t.Errorf("invalid signature: %s", err)
+	}
+	if err := VerifySignature("wrong", r.Header.Get(HeaderSignature), body, time.Minute); err == nil {
+		t.Errorf("expected signature verification to fail with incorrect secret")
+	}
+	old := Sign(sub.Secret, time.Now().Add(-time.Hour), body)
+	if err := VerifySignature(sub.Secret, old, body, time.Minute); err == nil {
+		t.Errorf("expected expired signature to be rejected")
+	}
+}
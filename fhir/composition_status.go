@@ -8,9 +8,9 @@ import (
 	"strconv"
 
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
 	"github.com/wardle/concierge/identifiers"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -106,6 +106,11 @@ func (cs CompositionStatus) Title() string {
 	return compositionalStatusTitles[cs]
 }
 
+// LocalTitle returns the human-readable title for this composition status in the language preferred for the context
+func (cs CompositionStatus) LocalTitle(ctx context.Context) string {
+	return i18n.Translate(i18n.Language(ctx), cs.Title())
+}
+
 var compositionalStatusTitles = [...]string{
 	"Unknown",
 	"Preliminary",
@@ -147,7 +152,7 @@ func compositionStatusResolver(ctx context.Context, id *apiv1.Identifier) (proto
 			Value:  cs.ToConcierge().Enum().String(),
 		}, nil
 	}
-	return nil, status.Errorf(codes.NotFound, "no composition status found matching code: '%s'", id.GetValue())
+	return nil, i18n.Errorf(ctx, codes.NotFound, "no composition status found matching code: '%s'", id.GetValue())
 }
 
 func mapCompositionStatusToSNOMED(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
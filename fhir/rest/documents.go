@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/fhir"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DocumentReference is a FHIR R4 DocumentReference resource
+// See https://www.hl7.org/fhir/R4/documentreference.html
+type DocumentReference struct {
+	ResourceType     string                     `json:"resourceType"`
+	ID               string                     `json:"id,omitempty"`
+	MasterIdentifier *Identifier                `json:"masterIdentifier,omitempty"`
+	Identifier       []Identifier               `json:"identifier,omitempty"`
+	Status           string                     `json:"status,omitempty"`    // current | superseded | entered-in-error
+	DocStatus        string                     `json:"docStatus,omitempty"` // preliminary | final | amended | entered-in-error
+	Type             *CodeableConcept           `json:"type,omitempty"`
+	Subject          *Reference                 `json:"subject,omitempty"`
+	Date             string                     `json:"date,omitempty"`
+	Author           []Reference                `json:"author,omitempty"`
+	Authenticator    *Reference                 `json:"authenticator,omitempty"`
+	Description      string                     `json:"description,omitempty"`
+	Content          []DocumentReferenceContent `json:"content,omitempty"`
+	Context          *DocumentReferenceContext  `json:"context,omitempty"`
+}
+
+// DocumentReferenceContent is the content of a DocumentReference
+type DocumentReferenceContent struct {
+	Attachment Attachment `json:"attachment"`
+}
+
+// DocumentReferenceContext is the clinical context of a DocumentReference
+type DocumentReferenceContext struct {
+	Encounter       []Reference      `json:"encounter,omitempty"`
+	PracticeSetting *CodeableConcept `json:"practiceSetting,omitempty"`
+}
+
+// Binary is a FHIR R4 Binary resource
+type Binary struct {
+	ResourceType string `json:"resourceType"`
+	ID           string `json:"id,omitempty"`
+	ContentType  string `json:"contentType"`
+	Data         []byte `json:"data,omitempty"`
+}
+
+// inputBundle is a bundle submitted by a client, with resources left unparsed until their type is known
+type inputBundle struct {
+	ResourceType string `json:"resourceType"`
+	Type         string `json:"type"`
+	Entry        []struct {
+		FullURL  string          `json:"fullUrl"`
+		Resource json.RawMessage `json:"resource"`
+	} `json:"entry"`
+}
+
+// parseDocumentBundle parses a bundle containing a single DocumentReference and any Binary resources
+// to which the DocumentReference's attachment may refer, by fullUrl or as Binary/{id}.
+func parseDocumentBundle(b []byte) (*DocumentReference, map[string]*Binary, error) {
+	var bundle inputBundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid bundle: %s", err)
+	}
+	if bundle.ResourceType != "Bundle" {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "expected resource type 'Bundle', got '%s'", bundle.ResourceType)
+	}
+	var docRef *DocumentReference
+	binaries := make(map[string]*Binary)
+	for _, entry := range bundle.Entry {
+		var rt struct {
+			ResourceType string `json:"resourceType"`
+		}
+		if err := json.Unmarshal(entry.Resource, &rt); err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid bundle entry: %s", err)
+		}
+		switch rt.ResourceType {
+		case "DocumentReference":
+			if docRef != nil {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "bundle must contain a single DocumentReference")
+			}
+			docRef = new(DocumentReference)
+			if err := json.Unmarshal(entry.Resource, docRef); err != nil {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "invalid DocumentReference: %s", err)
+			}
+		case "Binary":
+			binary := new(Binary)
+			if err := json.Unmarshal(entry.Resource, binary); err != nil {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "invalid Binary: %s", err)
+			}
+			if entry.FullURL != "" {
+				binaries[entry.FullURL] = binary
+			}
+			if binary.ID != "" {
+				binaries["Binary/"+binary.ID] = binary
+			}
+		default:
+			return nil, nil, status.Errorf(codes.InvalidArgument, "unsupported resource type in bundle: '%s'", rt.ResourceType)
+		}
+	}
+	if docRef == nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "bundle does not contain a DocumentReference")
+	}
+	return docRef, binaries, nil
+}
+
+// toPublishDocumentRequest translates a DocumentReference into a request to publish a document.
+// The subject must be a logical reference using an identifier, which is resolved to a patient using
+// the resolve function specified. The document data may be inline, or refer to a Binary resource.
+func toPublishDocumentRequest(ctx context.Context, dr *DocumentReference, binaries map[string]*Binary, resolve func(context.Context, *apiv1.Identifier) (*apiv1.Patient, error)) (*apiv1.PublishDocumentRequest, error) {
+	doc := new(apiv1.Document)
+	if id := dr.MasterIdentifier; id != nil {
+		doc.Id = &apiv1.Identifier{System: id.System, Value: id.Value}
+	} else if len(dr.Identifier) > 0 {
+		doc.Id = &apiv1.Identifier{System: dr.Identifier[0].System, Value: dr.Identifier[0].Value}
+	} else {
+		return nil, status.Errorf(codes.InvalidArgument, "DocumentReference must have a masterIdentifier or identifier")
+	}
+	subject := dr.Subject.identifier()
+	if subject == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "DocumentReference subject must be a logical reference with an identifier (system and value)")
+	}
+	pt, err := resolve(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	doc.Patient = pt
+	if dr.Status == "entered-in-error" {
+		doc.Status = apiv1.Document_IN_ERROR
+	} else {
+		doc.Status = fhir.LookupCompositionStatus(dr.DocStatus).ToConcierge()
+	}
+	doc.Type = dr.Type.identifier()
+	if dr.Context != nil {
+		doc.Specialty = dr.Context.PracticeSetting.identifier()
+		for _, enc := range dr.Context.Encounter {
+			if id := enc.identifier(); id != nil {
+				doc.Encounter = id
+				break
+			}
+		}
+	}
+	for _, author := range dr.Author {
+		if id := author.identifier(); id != nil {
+			doc.Authors = append(doc.Authors, id)
+		}
+	}
+	if id := dr.Authenticator.identifier(); id != nil {
+		doc.SignedBy = append(doc.SignedBy, id)
+	}
+	if dr.Date != "" {
+		t, err := time.Parse(time.RFC3339, dr.Date)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date '%s': %s", dr.Date, err)
+		}
+		doc.DateTime = timestamppb.New(t)
+		if err := doc.DateTime.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date '%s': %s", dr.Date, err)
+		}
+	}
+	if len(dr.Content) != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "DocumentReference must have a single content attachment, got %d", len(dr.Content))
+	}
+	att := dr.Content[0].Attachment
+	data, contentType := att.Data, att.ContentType
+	if len(data) == 0 && att.URL != "" {
+		binary, ok := binaries[att.URL]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "attachment refers to '%s' which is not in the bundle", att.URL)
+		}
+		data = binary.Data
+		if contentType == "" {
+			contentType = binary.ContentType
+		}
+	}
+	if len(data) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "DocumentReference has no attachment data")
+	}
+	doc.Data = &apiv1.Attachment{ContentType: contentType, Data: data, Title: att.Title}
+	doc.Title = dr.Description
+	if doc.Title == "" {
+		doc.Title = att.Title
+	}
+	return &apiv1.PublishDocumentRequest{Document: doc}, nil
+}
+
+// identifier returns the identifier from a logical reference, or nil
+func (r *Reference) identifier() *apiv1.Identifier {
+	if r == nil || r.Identifier == nil || r.Identifier.System == "" || r.Identifier.Value == "" {
+		return nil
+	}
+	return &apiv1.Identifier{System: r.Identifier.System, Value: r.Identifier.Value}
+}
+
+// identifier returns the first coding from a codeable concept as an identifier, or nil
+func (cc *CodeableConcept) identifier() *apiv1.Identifier {
+	if cc == nil {
+		return nil
+	}
+	for _, c := range cc.Coding {
+		if c.System != "" && c.Code != "" {
+			return &apiv1.Identifier{System: c.System, Value: c.Code}
+		}
+	}
+	return nil
+}
+
+// isBundle determines whether the JSON specified is a Bundle, rather than a DocumentReference
+func isBundle(b []byte) bool {
+	var rt struct {
+		ResourceType string `json:"resourceType"`
+	}
+	return json.Unmarshal(b, &rt) == nil && strings.EqualFold(rt.ResourceType, "Bundle")
+}
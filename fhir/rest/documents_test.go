@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+const testBundle = `{
+  "resourceType": "Bundle",
+  "type": "transaction",
+  "entry": [
+    {
+      "fullUrl": "urn:uuid:9b1d8f2e-0000-4000-8000-000000000001",
+      "resource": {"resourceType": "Binary", "contentType": "application/pdf", "data": "JVBERi0xLjQ="}
+    },
+    {
+      "resource": {
+        "resourceType": "DocumentReference",
+        "masterIdentifier": {"system": "urn:uuid", "value": "5cb3b4f0-0000-4000-8000-000000000002"},
+        "status": "current",
+        "docStatus": "final",
+        "type": {"coding": [{"system": "http://snomed.info/sct", "code": "371531000"}]},
+        "subject": {"identifier": {"system": "https://fhir.nhs.uk/Id/nhs-number", "value": "1111111111"}},
+        "date": "2020-05-01T10:30:00Z",
+        "description": "Clinic letter",
+        "content": [{"attachment": {"url": "urn:uuid:9b1d8f2e-0000-4000-8000-000000000001"}}],
+        "context": {"practiceSetting": {"coding": [{"system": "http://snomed.info/sct", "code": "394591006"}]}}
+      }
+    }
+  ]
+}`
+
+func TestDocumentBundle(t *testing.T) {
+	if !isBundle([]byte(testBundle)) {
+		t.Fatal("failed to detect bundle")
+	}
+	dr, binaries, err := parseDocumentBundle([]byte(testBundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resolved *apiv1.Identifier
+	req, err := toPublishDocumentRequest(context.Background(), dr, binaries, func(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+		resolved = id
+		return &apiv1.Patient{Lastname: "DUMMY", Identifiers: []*apiv1.Identifier{id}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := req.GetDocument()
+	if resolved.GetSystem() != identifiers.NHSNumber || doc.GetPatient().GetLastname() != "DUMMY" {
+		t.Fatalf("subject not resolved: %v", resolved)
+	}
+	if doc.GetStatus() != apiv1.Document_FINAL || doc.GetTitle() != "Clinic letter" || doc.GetType().GetValue() != "371531000" || doc.GetSpecialty().GetValue() != "394591006" {
+		t.Fatalf("invalid document: %v", doc)
+	}
+	if doc.GetData().GetContentType() != "application/pdf" || string(doc.GetData().GetData()) != "%PDF-1.4" {
+		t.Fatalf("invalid document data: %v", doc.GetData())
+	}
+	if doc.GetDateTime().GetSeconds() == 0 {
+		t.Fatal("missing document date")
+	}
+}
+
+func TestInvalidDocumentReference(t *testing.T) {
+	resolve := func(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) { return &apiv1.Patient{}, nil }
+	dr := &DocumentReference{ResourceType: "DocumentReference", MasterIdentifier: &Identifier{System: "urn:uuid", Value: "1"}}
+	if _, err := toPublishDocumentRequest(context.Background(), dr, nil, resolve); err == nil {
+		t.Fatal("expected error for missing subject")
+	}
+	dr.Subject = &Reference{Identifier: &Identifier{System: identifiers.NHSNumber, Value: "1111111111"}}
+	dr.Content = []DocumentReferenceContent{{Attachment: Attachment{URL: "Binary/missing"}}}
+	if _, err := toPublishDocumentRequest(context.Background(), dr, nil, resolve); err == nil {
+		t.Fatal("expected error for missing binary")
+	}
+}
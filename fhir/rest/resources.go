@@ -0,0 +1,359 @@
+package rest
+
+import (
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Patient is a FHIR R4 Patient resource
+// See https://www.hl7.org/fhir/R4/patient.html
+type Patient struct {
+	ResourceType         string           `json:"resourceType"`
+	ID                   string           `json:"id,omitempty"`
+	Extension            []Extension      `json:"extension,omitempty"`
+	Identifier           []Identifier     `json:"identifier,omitempty"`
+	Name                 []HumanName      `json:"name,omitempty"`
+	Telecom              []ContactPoint   `json:"telecom,omitempty"`
+	Gender               string           `json:"gender,omitempty"`
+	BirthDate            string           `json:"birthDate,omitempty"`
+	DeceasedBoolean      *bool            `json:"deceasedBoolean,omitempty"`
+	DeceasedDateTime     string           `json:"deceasedDateTime,omitempty"`
+	Address              []Address        `json:"address,omitempty"`
+	MaritalStatus        *CodeableConcept `json:"maritalStatus,omitempty"`
+	Communication        []Communication  `json:"communication,omitempty"`
+	GeneralPractitioner  []Reference      `json:"generalPractitioner,omitempty"`
+	ManagingOrganization *Reference       `json:"managingOrganization,omitempty"`
+}
+
+// Communication is a language which may be used to communicate with a patient
+type Communication struct {
+	Language  CodeableConcept `json:"language"`
+	Preferred bool            `json:"preferred,omitempty"`
+}
+
+// Practitioner is a FHIR R4 Practitioner resource
+// See https://www.hl7.org/fhir/R4/practitioner.html
+type Practitioner struct {
+	ResourceType  string          `json:"resourceType"`
+	ID            string          `json:"id,omitempty"`
+	Identifier    []Identifier    `json:"identifier,omitempty"`
+	Active        bool            `json:"active"`
+	Name          []HumanName     `json:"name,omitempty"`
+	Telecom       []ContactPoint  `json:"telecom,omitempty"`
+	Address       []Address       `json:"address,omitempty"`
+	Gender        string          `json:"gender,omitempty"`
+	BirthDate     string          `json:"birthDate,omitempty"`
+	Photo         []Attachment    `json:"photo,omitempty"`
+	Qualification []Qualification `json:"qualification,omitempty"`
+}
+
+// Extension is a FHIR R4 Extension, with the value types that are used
+type Extension struct {
+	URL                  string           `json:"url"`
+	ValueCodeableConcept *CodeableConcept `json:"valueCodeableConcept,omitempty"`
+	ValueAddress         *Address         `json:"valueAddress,omitempty"`
+}
+
+// Extensions used in resources
+const (
+	ethnicCategoryExtension = "https://fhir.hl7.org.uk/StructureDefinition/Extension-UKCore-EthnicCategory"
+	birthPlaceExtension     = "http://hl7.org/fhir/StructureDefinition/patient-birthPlace"
+)
+
+// Identifier is a FHIR R4 Identifier datatype
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// HumanName is a FHIR R4 HumanName datatype
+type HumanName struct {
+	Use    string   `json:"use,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+	Prefix []string `json:"prefix,omitempty"`
+	Suffix []string `json:"suffix,omitempty"`
+	Period *Period  `json:"period,omitempty"`
+}
+
+// ContactPoint is a FHIR R4 ContactPoint datatype
+type ContactPoint struct {
+	System string `json:"system,omitempty"` // phone | fax | email | pager | url | sms | other
+	Value  string `json:"value,omitempty"`
+	Use    string `json:"use,omitempty"` // home | work | temp | old | mobile
+}
+
+// Address is a FHIR R4 Address datatype
+type Address struct {
+	Use        string   `json:"use,omitempty"` // home | work | temp | old | billing
+	Line       []string `json:"line,omitempty"`
+	City       string   `json:"city,omitempty"`
+	District   string   `json:"district,omitempty"`
+	PostalCode string   `json:"postalCode,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Period     *Period  `json:"period,omitempty"`
+}
+
+// Period is a FHIR R4 Period datatype
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// Reference is a FHIR R4 Reference datatype
+type Reference struct {
+	Identifier *Identifier `json:"identifier,omitempty"`
+	Display    string      `json:"display,omitempty"`
+}
+
+// Attachment is a FHIR R4 Attachment datatype
+type Attachment struct {
+	ContentType string `json:"contentType,omitempty"`
+	Data        []byte `json:"data,omitempty"` // base64 encoded by encoding/json
+	URL         string `json:"url,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
+
+// CodeableConcept is a FHIR R4 CodeableConcept datatype
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Coding is a FHIR R4 Coding datatype
+type Coding struct {
+	System string `json:"system,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+// Qualification is a practitioner qualification, or in our case, role
+type Qualification struct {
+	Code   CodeableConcept `json:"code"`
+	Period *Period         `json:"period,omitempty"`
+}
+
+// Bundle is a FHIR R4 Bundle resource, used for search results
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Total        int           `json:"total"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// BundleEntry is an entry in a Bundle
+type BundleEntry struct {
+	Resource interface{} `json:"resource"`
+	Search   *struct {
+		Mode string `json:"mode"`
+	} `json:"search,omitempty"`
+	Response *BundleResponse `json:"response,omitempty"`
+}
+
+// BundleResponse is the result of processing an entry in a transaction
+type BundleResponse struct {
+	Status string `json:"status"`
+}
+
+// OperationOutcome is a FHIR R4 OperationOutcome resource, used to report errors
+type OperationOutcome struct {
+	ResourceType string  `json:"resourceType"`
+	Issue        []Issue `json:"issue"`
+}
+
+// Issue is an issue within an OperationOutcome
+type Issue struct {
+	Severity    string `json:"severity"` // fatal | error | warning | information
+	Code        string `json:"code"`     // e.g. not-found, invalid, exception
+	Diagnostics string `json:"diagnostics,omitempty"`
+}
+
+// NewSearchSet creates a search result bundle from the resources specified
+func NewSearchSet(resources ...interface{}) *Bundle {
+	b := &Bundle{ResourceType: "Bundle", Type: "searchset", Total: len(resources)}
+	for _, r := range resources {
+		e := BundleEntry{Resource: r}
+		e.Search = &struct {
+			Mode string `json:"mode"`
+		}{Mode: "match"}
+		b.Entry = append(b.Entry, e)
+	}
+	return b
+}
+
+// FromPatient converts a patient into a FHIR R4 Patient resource
+func FromPatient(pt *apiv1.Patient) *Patient {
+	result := &Patient{
+		ResourceType: "Patient",
+		Identifier:   fromIdentifiers(pt.GetIdentifiers()),
+		Gender:       fromGender(pt.GetGender()),
+		BirthDate:    pt.GetBirthDate().Format("2006-01-02"),
+	}
+	name := HumanName{Use: "official", Family: pt.GetLastname(), Given: strings.Fields(pt.GetFirstnames())}
+	if pt.GetTitle() != "" {
+		name.Prefix = []string{pt.GetTitle()}
+	}
+	if name.Family != "" || len(name.Given) > 0 {
+		result.Name = []HumanName{name}
+	}
+	switch d := pt.GetDeceased().(type) {
+	case *apiv1.Patient_DeceasedDate:
+		result.DeceasedDateTime = fromDate(d.DeceasedDate)
+	case *apiv1.Patient_DeceasedBoolean:
+		result.DeceasedBoolean = &d.DeceasedBoolean
+	}
+	for _, t := range pt.GetTelephones() {
+		result.Telecom = append(result.Telecom, fromTelephone(t))
+	}
+	for _, email := range pt.GetEmails() {
+		result.Telecom = append(result.Telecom, ContactPoint{System: "email", Value: email})
+	}
+	for _, a := range pt.GetAddresses() {
+		result.Address = append(result.Address, fromAddress(a))
+	}
+	if ec := pt.GetEthnicCategory(); ec != nil {
+		result.Extension = append(result.Extension, Extension{URL: ethnicCategoryExtension, ValueCodeableConcept: &CodeableConcept{Coding: []Coding{{System: ec.GetSystem(), Code: ec.GetValue()}}}})
+	}
+	if country := pt.GetCountryOfBirth(); country != "" {
+		result.Extension = append(result.Extension, Extension{URL: birthPlaceExtension, ValueAddress: &Address{Country: country}})
+	}
+	if ms := pt.GetMaritalStatus(); ms != nil {
+		result.MaritalStatus = &CodeableConcept{Coding: []Coding{{System: ms.GetSystem(), Code: ms.GetValue()}}}
+	}
+	if lang := pt.GetPreferredLanguage(); lang != "" {
+		result.Communication = []Communication{{Language: CodeableConcept{Coding: []Coding{{System: "urn:ietf:bcp:47", Code: lang}}}, Preferred: true}}
+	}
+	if gp := pt.GetGeneralPractitioner(); gp != nil {
+		result.GeneralPractitioner = append(result.GeneralPractitioner, Reference{Identifier: &Identifier{System: gp.GetSystem(), Value: gp.GetValue()}})
+	}
+	if surgery := pt.GetSurgery(); surgery != nil {
+		result.GeneralPractitioner = append(result.GeneralPractitioner, Reference{Identifier: &Identifier{System: surgery.GetSystem(), Value: surgery.GetValue()}})
+	}
+	return result
+}
+
+// FromPractitioner converts a practitioner into a FHIR R4 Practitioner resource
+func FromPractitioner(id string, p *apiv1.Practitioner) *Practitioner {
+	result := &Practitioner{
+		ResourceType: "Practitioner",
+		ID:           id,
+		Identifier:   fromIdentifiers(p.GetIdentifiers()),
+		Active:       p.GetActive(),
+		Gender:       fromGender(p.GetGender()),
+		BirthDate:    p.GetBirthDate().Format("2006-01-02"),
+	}
+	for _, n := range p.GetNames() {
+		name := HumanName{
+			Family: n.GetFamily(),
+			Given:  strings.Fields(n.GetGiven()),
+			Prefix: n.GetPrefixes(),
+			Suffix: n.GetSuffices(),
+			Period: fromPeriod(n.GetPeriod()),
+		}
+		if n.GetUse() != apiv1.HumanName_UNKNOWN {
+			name.Use = strings.ToLower(n.GetUse().String())
+		}
+		result.Name = append(result.Name, name)
+	}
+	for _, t := range p.GetTelephones() {
+		cp := fromTelephone(t)
+		cp.Use = "work"
+		result.Telecom = append(result.Telecom, cp)
+	}
+	for _, email := range p.GetEmails() {
+		result.Telecom = append(result.Telecom, ContactPoint{System: "email", Value: email, Use: "work"})
+	}
+	for _, a := range p.GetWorkAddresses() {
+		result.Address = append(result.Address, fromAddress(a))
+	}
+	for _, photo := range p.GetPhotos() {
+		result.Photo = append(result.Photo, Attachment{ContentType: photo.GetContentType(), Data: photo.GetData(), URL: photo.GetUrl(), Title: photo.GetTitle()})
+	}
+	for _, r := range p.GetRoles() {
+		role := r.GetRole()
+		q := Qualification{Code: CodeableConcept{Text: role.GetJobTitle()}, Period: fromPeriod(r.GetPeriod())}
+		if id := role.GetIdentifier(); id != nil {
+			q.Code.Coding = []Coding{{System: id.GetSystem(), Code: id.GetValue()}}
+		}
+		result.Qualification = append(result.Qualification, q)
+	}
+	return result
+}
+
+func fromIdentifiers(ids []*apiv1.Identifier) []Identifier {
+	result := make([]Identifier, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, Identifier{System: id.GetSystem(), Value: id.GetValue()})
+	}
+	return result
+}
+
+func fromGender(g apiv1.Gender) string {
+	switch g {
+	case apiv1.Gender_MALE:
+		return "male"
+	case apiv1.Gender_FEMALE:
+		return "female"
+	}
+	return "unknown"
+}
+
+func fromDate(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	if ts.CheckValid() != nil {
+		return ""
+	}
+	return ts.AsTime().Format("2006-01-02")
+}
+
+func fromPeriod(p *apiv1.Period) *Period {
+	if p.GetStart() == nil && p.GetEnd() == nil {
+		return nil
+	}
+	return &Period{Start: fromDate(p.GetStart()), End: fromDate(p.GetEnd())}
+}
+
+func fromAddress(a *apiv1.Address) Address {
+	result := Address{Use: addressUses[a.GetUse()], District: a.GetCounty(), PostalCode: a.GetPostcode(), Country: a.GetCountry(), Period: fromPeriod(a.GetPeriod())}
+	for _, line := range []string{a.GetAddress1(), a.GetAddress2(), a.GetAddress3()} {
+		if line != "" {
+			result.Line = append(result.Line, line)
+		}
+	}
+	return result
+}
+
+// addressUses maps address use to the FHIR address-use value set; FHIR has no correspondence address, which is
+// usually the home address, so this is left unspecified
+var addressUses = map[apiv1.Address_Use]string{
+	apiv1.Address_HOME:      "home",
+	apiv1.Address_TEMPORARY: "temp",
+	apiv1.Address_WORK:      "work",
+	apiv1.Address_OLD:       "old",
+}
+
+// fromTelephone converts a telephone, falling back to the description to determine its use if unknown.
+// The description may have been translated into Welsh.
+func fromTelephone(t *apiv1.Telephone) ContactPoint {
+	cp := ContactPoint{System: "phone", Value: t.GetNumber()}
+	desc := strings.ToLower(t.GetDescription())
+	switch {
+	case t.GetUse() == apiv1.Telephone_MOBILE:
+		cp.Use = "mobile"
+	case t.GetUse() == apiv1.Telephone_HOME:
+		cp.Use = "home"
+	case t.GetUse() == apiv1.Telephone_WORK:
+		cp.Use = "work"
+	case strings.Contains(desc, "mobile") || strings.Contains(desc, "symudol"):
+		cp.Use = "mobile"
+	case strings.Contains(desc, "home") || strings.Contains(desc, "cartref"):
+		cp.Use = "home"
+	case strings.Contains(desc, "work") || strings.Contains(desc, "business") || strings.Contains(desc, "gwaith") || strings.Contains(desc, "swyddfa"):
+		cp.Use = "work"
+	}
+	return cp
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestFromPatient(t *testing.T) {
+	pt := &apiv1.Patient{
+		Lastname:          "DUMMY",
+		Firstnames:        "ALBERT JAMES",
+		Title:             "DR",
+		Gender:            apiv1.Gender_MALE,
+		BirthDate:         &apiv1.Date{Year: 1960, Month: 1, Day: 1},
+		Surgery:           &apiv1.Identifier{System: identifiers.ODSCode, Value: "W95010"},
+		Identifiers:       []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}},
+		Telephones:        []*apiv1.Telephone{{Number: "07700 900000", Description: "Mobile"}},
+		Addresses:         []*apiv1.Address{{Address1: "1 Street", Address3: "Cardiff", Postcode: "CF14 4XW"}},
+		PreferredLanguage: "cy",
+		EthnicCategory:    &apiv1.Identifier{System: identifiers.CareConnectEthnicCategory, Value: "A"},
+		MaritalStatus:     &apiv1.Identifier{System: identifiers.MaritalStatus, Value: "M"},
+	}
+	b, err := json.Marshal(NewSearchSet(FromPatient(pt)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		ResourceType string
+		Total        int
+		Entry        []struct {
+			Resource struct {
+				ResourceType  string
+				Name          []HumanName
+				Gender        string
+				BirthDate     string
+				Telecom       []ContactPoint
+				Address       []Address
+				Communication []Communication
+				Extension     []Extension
+				MaritalStatus *CodeableConcept
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ResourceType != "Bundle" || result.Total != 1 || len(result.Entry) != 1 {
+		t.Fatalf("invalid bundle: %s", b)
+	}
+	r := result.Entry[0].Resource
+	if r.ResourceType != "Patient" || r.Gender != "male" || r.BirthDate != "1960-01-01" || r.Name[0].Family != "DUMMY" || len(r.Name[0].Given) != 2 {
+		t.Fatalf("invalid patient: %s", b)
+	}
+	if r.Telecom[0].Use != "mobile" || len(r.Address[0].Line) != 2 || r.Address[0].PostalCode != "CF14 4XW" {
+		t.Fatalf("invalid contact details: %s", b)
+	}
+	if len(r.Communication) != 1 || r.Communication[0].Language.Coding[0].Code != "cy" || !r.Communication[0].Preferred {
+		t.Fatalf("invalid communication: %s", b)
+	}
+	if len(r.Extension) != 1 || r.Extension[0].ValueCodeableConcept.Coding[0].Code != "A" || r.MaritalStatus.Coding[0].Code != "M" {
+		t.Fatalf("invalid ethnic category or marital status: %s", b)
+	}
+}
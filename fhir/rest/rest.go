@@ -0,0 +1,224 @@
+// Package rest provides a FHIR R4 REST facade for concierge, so that existing FHIR clients can
+// consume concierge services without speaking gRPC.
+//
+// Supported endpoints:
+//
+//	GET /fhir/Patient?identifier=system|value     - search for a patient by identifier, returning a Bundle
+//	GET /fhir/Practitioner/{id}                   - fetch a practitioner by their NHS Wales' user identifier
+//	POST /fhir/DocumentReference                  - publish a document, as a DocumentReference with inline data
+//	POST /fhir                                    - publish a document, as a Bundle of a DocumentReference and Binary
+//
+// Requests are made via the concierge gRPC API, so the usual authentication applies.
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// contentType is the FHIR JSON mime type
+const contentType = "application/fhir+json"
+
+var (
+	patternPatient      = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"fhir", "Patient"}, ""))
+	patternPractitioner = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2}, []string{"fhir", "Practitioner", "id"}, ""))
+	patternDocument     = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"fhir", "DocumentReference"}, ""))
+	patternBase         = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0}, []string{"fhir"}, ""))
+)
+
+// maxDocumentSize is the maximum size of a document submission
+const maxDocumentSize = 32 << 20
+
+// Server provides a FHIR R4 REST facade
+type Server struct {
+	conn *grpc.ClientConn
+}
+
+// RegisterServer registers this server; the FHIR facade has no gRPC services of its own.
+func (sv *Server) RegisterServer(s *grpc.Server) {}
+
+// RegisterHTTPProxy registers the FHIR REST endpoints
+func (sv *Server) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return err
+	}
+	sv.conn = conn
+	client := apiv1.NewIdentifiersClient(conn)
+	docs := apiv1.NewDocumentServiceClient(conn)
+	mux.Handle(http.MethodGet, patternPatient, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		sv.handle(mux, w, r, http.StatusOK, func(ctx context.Context) (interface{}, error) {
+			return searchPatient(ctx, client, r.URL.Query().Get("identifier"))
+		})
+	})
+	mux.Handle(http.MethodGet, patternPractitioner, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		sv.handle(mux, w, r, http.StatusOK, func(ctx context.Context) (interface{}, error) {
+			return getPractitioner(ctx, client, pathParams["id"])
+		})
+	})
+	publish := func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		sv.handle(mux, w, r, http.StatusCreated, func(ctx context.Context) (interface{}, error) {
+			return publishDocument(ctx, client, docs, r)
+		})
+	}
+	mux.Handle(http.MethodPost, patternDocument, publish)
+	mux.Handle(http.MethodPost, patternBase, publish)
+	return nil
+}
+
+// Close closes any linked resources
+func (sv *Server) Close() error {
+	if sv.conn != nil {
+		return sv.conn.Close()
+	}
+	return nil
+}
+
+// handle runs the function specified, passing through request headers (e.g. authorization) as
+// gRPC metadata, and writes the result, or an OperationOutcome on error, as FHIR JSON.
+func (sv *Server) handle(mux *runtime.ServeMux, w http.ResponseWriter, r *http.Request, code int, f func(ctx context.Context) (interface{}, error)) {
+	ctx, err := runtime.AnnotateContext(r.Context(), mux, r)
+	if err == nil {
+		var result interface{}
+		if result, err = f(ctx); err == nil {
+			writeJSON(w, code, result)
+			return
+		}
+	}
+	st := status.Convert(err)
+	writeJSON(w, runtime.HTTPStatusFromCode(st.Code()), &OperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue:        []Issue{{Severity: "error", Code: issueCode(st.Code()), Diagnostics: st.Message()}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("fhir: failed to write response: %s", err)
+	}
+}
+
+// searchPatient returns a search set bundle containing the patient matching the identifier,
+// specified as system|value as per the FHIR token search parameter.
+// An empty bundle is returned if no patient is found.
+func searchPatient(ctx context.Context, client apiv1.IdentifiersClient, token string) (*Bundle, error) {
+	i := strings.Index(token, "|")
+	if i <= 0 || i == len(token)-1 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid identifier search parameter: expected system|value, got '%s'", token)
+	}
+	result, err := client.GetIdentifier(ctx, &apiv1.Identifier{System: token[:i], Value: token[i+1:]})
+	if status.Code(err) == codes.NotFound {
+		return NewSearchSet(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pt := new(apiv1.Patient)
+	if err := result.UnmarshalTo(pt); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "identifier '%s' does not refer to a patient", token)
+	}
+	return NewSearchSet(FromPatient(pt)), nil
+}
+
+// publishDocument publishes a document submitted either as a DocumentReference, or as a Bundle
+// containing a DocumentReference and Binary, returning the DocumentReference with the repository's
+// identifier for the published document. For a Bundle, a transaction-response Bundle is returned.
+func publishDocument(ctx context.Context, client apiv1.IdentifiersClient, docs apiv1.DocumentServiceClient, r *http.Request) (interface{}, error) {
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxDocumentSize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+	}
+	bundle := isBundle(b)
+	var dr *DocumentReference
+	var binaries map[string]*Binary
+	if bundle {
+		if dr, binaries, err = parseDocumentBundle(b); err != nil {
+			return nil, err
+		}
+	} else {
+		dr = new(DocumentReference)
+		if err := json.Unmarshal(b, dr); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid DocumentReference: %s", err)
+		}
+		if dr.ResourceType != "DocumentReference" {
+			return nil, status.Errorf(codes.InvalidArgument, "expected resource type 'DocumentReference' or 'Bundle', got '%s'", dr.ResourceType)
+		}
+	}
+	req, err := toPublishDocumentRequest(ctx, dr, binaries, func(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+		result, err := client.GetIdentifier(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		pt := new(apiv1.Patient)
+		if err := result.UnmarshalTo(pt); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "subject '%s|%s' does not refer to a patient", id.GetSystem(), id.GetValue())
+		}
+		return pt, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := docs.PublishDocument(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if id := response.GetId(); id != nil {
+		dr.Identifier = append(dr.Identifier, Identifier{System: id.GetSystem(), Value: id.GetValue()})
+	}
+	dr.Status = "current"
+	for i := range dr.Content { // don't echo document data back to the client
+		dr.Content[i].Attachment.Data = nil
+	}
+	if !bundle {
+		return dr, nil
+	}
+	return &Bundle{ResourceType: "Bundle", Type: "transaction-response", Total: 1, Entry: []BundleEntry{
+		{Resource: dr, Response: &BundleResponse{Status: "201 Created"}},
+	}}, nil
+}
+
+// getPractitioner returns the practitioner with the specified NHS Wales' user identifier
+func getPractitioner(ctx context.Context, client apiv1.IdentifiersClient, id string) (*Practitioner, error) {
+	result, err := client.GetIdentifier(ctx, &apiv1.Identifier{System: identifiers.CymruUserID, Value: id})
+	if err != nil {
+		return nil, err
+	}
+	p := new(apiv1.Practitioner)
+	if err := result.UnmarshalTo(p); err != nil {
+		return nil, status.Errorf(codes.Internal, "unexpected result for practitioner '%s'", id)
+	}
+	return FromPractitioner(id, p), nil
+}
+
+// issueCode maps a gRPC status code to a FHIR issue type
+// See https://www.hl7.org/fhir/R4/valueset-issue-type.html
+func issueCode(code codes.Code) string {
+	switch code {
+	case codes.NotFound:
+		return "not-found"
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return "invalid"
+	case codes.Unauthenticated:
+		return "login"
+	case codes.PermissionDenied:
+		return "forbidden"
+	case codes.DeadlineExceeded:
+		return "timeout"
+	case codes.Unimplemented:
+		return "not-supported"
+	}
+	return "exception"
+}
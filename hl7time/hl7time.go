@@ -0,0 +1,211 @@
+// Package hl7time parses and formats HL7 dates and timestamps (the v2 DT, DTM and TS types) of the form
+// YYYY[MM[DD[HH[MM[SS[.S[S[S[S]]]]]]]]][+/-ZZZZ], retaining their precision, so that a year of birth
+// is not mistaken for the first of January, nor a date for midnight.
+package hl7time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Precision is the precision of a date or timestamp
+type Precision int
+
+// Precisions, in increasing order
+const (
+	None Precision = iota
+	Year
+	Month
+	Day
+	Hour
+	Minute
+	Second
+	Fraction // fractions of a second, to ten-thousandths
+)
+
+var precisionNames = [...]string{"none", "year", "month", "day", "hour", "minute", "second", "fraction"}
+
+func (p Precision) String() string {
+	if p < None || p > Fraction {
+		return strconv.Itoa(int(p))
+	}
+	return precisionNames[p]
+}
+
+// layouts for each precision, without fractions of a second or offset
+var layouts = map[Precision]string{
+	Year:   "2006",
+	Month:  "200601",
+	Day:    "20060102",
+	Hour:   "2006010215",
+	Minute: "200601021504",
+	Second: "20060102150405",
+}
+
+// Time is a date or timestamp, with its precision
+type Time struct {
+	time.Time
+	Precision Precision
+}
+
+// Parse parses an HL7 date or timestamp, interpreting timestamps without an offset as UTC.
+// An empty string is not an error, but returns a zero Time with precision None.
+func Parse(s string) (Time, error) {
+	return ParseInLocation(s, time.UTC)
+}
+
+// ParseInLocation parses an HL7 date or timestamp, interpreting timestamps without an offset in the location specified.
+// Dates, with precision of a day or less, are always midnight UTC, so that a date is the same wherever it is used.
+// An empty string is not an error, but returns a zero Time with precision None.
+func ParseInLocation(s string, loc *time.Location) (Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Time{}, nil
+	}
+	value, offset := s, ""
+	if i := strings.IndexAny(s, "+-"); i >= 0 {
+		value, offset = s[:i], s[i:]
+	}
+	fraction := ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value, fraction = value[:i], value[i+1:]
+	}
+	var precision Precision
+	switch len(value) {
+	case 4:
+		precision = Year
+	case 6:
+		precision = Month
+	case 8:
+		precision = Day
+	case 10:
+		precision = Hour
+	case 12:
+		precision = Minute
+	case 14:
+		precision = Second
+	default:
+		return Time{}, fmt.Errorf("hl7time: invalid date/time: '%s'", s)
+	}
+	if fraction != "" {
+		if precision != Second || len(fraction) > 4 || !digits(fraction) {
+			return Time{}, fmt.Errorf("hl7time: invalid fraction of second: '%s'", s)
+		}
+		precision = Fraction
+	}
+	if !digits(value) {
+		return Time{}, fmt.Errorf("hl7time: invalid date/time: '%s'", s)
+	}
+	if precision <= Day {
+		if offset != "" {
+			return Time{}, fmt.Errorf("hl7time: offset not permitted for date: '%s'", s)
+		}
+		loc = time.UTC
+	}
+	if offset != "" {
+		var err error
+		if loc, err = parseOffset(offset); err != nil {
+			return Time{}, fmt.Errorf("hl7time: invalid offset: '%s'", s)
+		}
+	}
+	t, err := time.ParseInLocation(layouts[minPrecision(precision, Second)], value, loc)
+	if err != nil {
+		return Time{}, fmt.Errorf("hl7time: invalid date/time: '%s': %w", s, err)
+	}
+	if fraction != "" {
+		n, _ := strconv.Atoi((fraction + "000")[:4])
+		t = t.Add(time.Duration(n) * 100 * time.Microsecond)
+	}
+	return Time{Time: t, Precision: precision}, nil
+}
+
+// parseOffset parses an offset from UTC of the form +HHMM or -HHMM
+func parseOffset(s string) (*time.Location, error) {
+	if len(s) != 5 || !digits(s[1:]) {
+		return nil, fmt.Errorf("invalid offset")
+	}
+	hours, _ := strconv.Atoi(s[1:3])
+	minutes, _ := strconv.Atoi(s[3:])
+	if hours > 14 || minutes > 59 {
+		return nil, fmt.Errorf("invalid offset")
+	}
+	seconds := hours*3600 + minutes*60
+	if s[0] == '-' {
+		seconds = -seconds
+	}
+	return time.FixedZone(s, seconds), nil
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func minPrecision(a, b Precision) Precision {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Date returns the calendar date of a timestamp, in its own offset, as midnight UTC with a precision of no more than a day,
+// as for dates of birth and death recorded as timestamps.
+func (t Time) Date() Time {
+	if t.IsZero() {
+		return t
+	}
+	y, m, d := t.Time.Date()
+	return Time{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Precision: minPrecision(t.Precision, Day)}
+}
+
+// String formats the date or timestamp in HL7 format, to its precision, including the offset for timestamps
+func (t Time) String() string {
+	return Format(t.Time, t.Precision)
+}
+
+// Timestamp returns the date or timestamp as a protobuf timestamp, or nil if zero or invalid
+func (t Time) Timestamp() *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	ts := timestamppb.New(t.Time)
+	if ts.CheckValid() != nil {
+		return nil
+	}
+	return ts
+}
+
+// Format formats the time in HL7 format to the precision specified, including the offset for timestamps,
+// or an empty string if the time is zero. Dates, with precision of a day or less, are formatted using UTC,
+// consistent with ParseInLocation.
+func Format(t time.Time, precision Precision) string {
+	if t.IsZero() || precision <= None {
+		return ""
+	}
+	if precision <= Day {
+		return t.UTC().Format(layouts[precision])
+	}
+	if precision >= Fraction {
+		return t.Format("20060102150405.0000-0700")
+	}
+	return t.Format(layouts[precision] + "-0700")
+}
+
+// FormatTimestamp formats a protobuf timestamp in HL7 format to the precision specified, or an empty string if nil or invalid
+func FormatTimestamp(ts *timestamppb.Timestamp, precision Precision) string {
+	if ts == nil {
+		return ""
+	}
+	if ts.CheckValid() != nil {
+		return ""
+	}
+	return Format(ts.AsTime(), precision)
+}
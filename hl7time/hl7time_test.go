@@ -0,0 +1,60 @@
+package hl7time
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s         string
+		precision Precision
+		expected  time.Time
+		formatted string
+	}{
+		{"", None, time.Time{}, ""},
+		{"1980", Year, time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), "1980"},
+		{"198004", Month, time.Date(1980, 4, 1, 0, 0, 0, 0, time.UTC), "198004"},
+		{"19800403", Day, time.Date(1980, 4, 3, 0, 0, 0, 0, time.UTC), "19800403"},
+		{"2020061512", Hour, time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC), "2020061512+0000"},
+		{"202006151230+0100", Minute, time.Date(2020, 6, 15, 11, 30, 0, 0, time.UTC), "202006151230+0100"},
+		{"20200615123045-0500", Second, time.Date(2020, 6, 15, 17, 30, 45, 0, time.UTC), "20200615123045-0500"},
+		{"20200615123045.12", Fraction, time.Date(2020, 6, 15, 12, 30, 45, 120000000, time.UTC), "20200615123045.1200+0000"},
+	}
+	for _, test := range tests {
+		result, err := Parse(test.s)
+		if err != nil {
+			t.Errorf("failed to parse '%s': %s", test.s, err)
+			continue
+		}
+		if result.Precision != test.precision || !result.Time.Equal(test.expected) {
+			t.Errorf("'%s': expected %s (%s), got %s (%s)", test.s, test.expected, test.precision, result.Time, result.Precision)
+		}
+		if s := result.String(); s != test.formatted {
+			t.Errorf("'%s': expected to format as '%s', got '%s'", test.s, test.formatted, s)
+		}
+	}
+	for _, s := range []string{"198", "1980-04-03", "19800230", "19800403+0100", "20200615123045+25", "20200615.5", "20200615123045.12345", "abcdefgh"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected error parsing '%s'", s)
+		}
+	}
+}
+
+func TestParseInLocation(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	result, err := ParseInLocation("202006151230", london)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Time.Equal(time.Date(2020, 6, 15, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected local time to be interpreted as BST, got %s", result.Time.UTC())
+	}
+	date, err := ParseInLocation("20200615", london)
+	if err != nil || date.Location() != time.UTC || date.Hour() != 0 {
+		t.Errorf("expected date to be midnight UTC, got %s (%v)", date.Time, err)
+	}
+}
@@ -0,0 +1,93 @@
+package hl7v2
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ADTOptions configures the generation of ADT messages
+type ADTOptions struct {
+	SendingApplication   string
+	SendingFacility      string
+	ReceivingApplication string
+	ReceivingFacility    string
+	ProcessingID         string        // MSH-11: P production, U testing, T development; default P
+	Authority            AuthorityFunc // used to determine the assigning authority of patient identifiers
+}
+
+// NewADTA08 generates an ADT^A08 (update patient information) message for the patient specified.
+// The operator is the user responsible for the change, recorded in EVN-5 for audit by the recipient.
+func NewADTA08(pt *apiv1.Patient, operator *apiv1.Identifier, opts ADTOptions) []byte {
+	if opts.Authority == nil {
+		opts.Authority = DefaultAuthority
+	}
+	if opts.SendingApplication == "" {
+		opts.SendingApplication = "CONCIERGE"
+	}
+	if opts.ProcessingID == "" {
+		opts.ProcessingID = "P"
+	}
+	now := time.Now().Format("20060102150405")
+	var operatorID string
+	if operator != nil {
+		operatorID = authors([]*apiv1.Identifier{operator})
+	}
+	segments := []string{
+		fields("MSH", "^~\\&", escape(opts.SendingApplication), escape(opts.SendingFacility), escape(opts.ReceivingApplication), escape(opts.ReceivingFacility),
+			now, "", "ADT^A08^ADT_A01", uuid.New().String(), escape(opts.ProcessingID), "2.5"),
+		fields("EVN", "A08", now, "", "", operatorID),
+		fields("PID", "1", "", patientIdentifiers(pt.GetIdentifiers(), opts.Authority), "",
+			escape(pt.GetLastname())+"^"+escape(pt.GetFirstnames())+"^^^"+escape(pt.GetTitle()), "",
+			pt.GetBirthDate().Format("20060102"), gender(pt.GetGender()), "", "", addresses(pt.GetAddresses()), "",
+			contacts(pt.GetTelephones(), pt.GetEmails())),
+		fields("PV1", "1", "N"),
+	}
+	return []byte(strings.Join(segments, "\r") + "\r")
+}
+
+// SendADT sends an ADT message to the MLLP endpoint at the address specified, returning an error
+// unless the recipient accepts the message
+func SendADT(ctx context.Context, addr string, msg []byte) error {
+	ack, err := Send(ctx, addr, msg)
+	if err != nil {
+		return err
+	}
+	if code := ack.Get("MSA", 1, 1); code != "AA" && code != "CA" {
+		return status.Errorf(codes.FailedPrecondition, "hl7v2: patient update rejected (%s): %s", code, ack.Get("MSA", 3, 1))
+	}
+	return nil
+}
+
+// addresses returns addresses (XAD), using XAD-4 for county, as used by the Welsh EMPI. Addresses of unknown use are sent as home addresses.
+func addresses(addresses []*apiv1.Address) string {
+	result := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		use := a.GetUse().Code()
+		if use == "" {
+			use = apiv1.Address_HOME.Code()
+		}
+		result = append(result, escape(a.GetAddress1())+"^"+escape(a.GetAddress2())+"^"+escape(a.GetAddress3())+"^"+
+			escape(a.GetCounty())+"^"+escape(a.GetPostcode())+"^"+escape(a.GetCountry())+"^"+use+"^^^^^^"+
+			formatDate(a.GetPeriod().GetStart(), "20060102")+"^"+formatDate(a.GetPeriod().GetEnd(), "20060102"))
+	}
+	return strings.Join(result, "~")
+}
+
+// contacts returns telephone numbers and email addresses (XTN) for PID-13 (home contact details)
+func contacts(telephones []*apiv1.Telephone, emails []string) string {
+	result := make([]string, 0, len(telephones)+len(emails))
+	for _, t := range telephones {
+		use, equipment := t.GetUse().Codes()
+		result = append(result, escape(t.GetNumber())+"^"+use+"^"+equipment)
+	}
+	for _, email := range emails {
+		result = append(result, "^NET^Internet^"+escape(email))
+	}
+	return strings.Join(result, "~")
+}
@@ -0,0 +1,235 @@
+package hl7v2
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testA31 = "MSH|^~\\&|EMPI|NHSWALES|CONCIERGE|ELDRIX|20200401120000||ADT^A31^ADT_A05|MSG0001|P|2.5\r" +
+	"EVN|A31|20200401120000\r" +
+	"PID|1||1111111111^^^NHS^NH~A999998^^^140^PI||DUMMY^ALBERT^JOHN^^DR||19600101|M|||1 Station Road^Heath^Cardiff^South Glamorgan^CF14 4XW||02920747747^^^test@example.com||||||||||||||||N|||NSTS01\r" +
+	"PD1|||CASTLE GATE^^W95010|G9342400^SMITH^JOHN\r"
+
+const testA40 = "MSH|^~\\&|EMPI|NHSWALES|CONCIERGE|ELDRIX|20200401120000||ADT^A40|MSG0002|P|2.5\r" +
+	"PID|1||1111111111^^^NHS^NH||DUMMY^ALBERT\r" +
+	"MRG|A999997^^^140^PI\r"
+
+func testSystem(authority string) string {
+	if authority == "140" {
+		return identifiers.CardiffAndValeCRN
+	}
+	return DefaultSystem(authority)
+}
+
+func TestParsePatient(t *testing.T) {
+	msg, err := Parse([]byte(testA31))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgType, event := msg.Type(); msgType != "ADT" || event != "A31" || msg.ControlID() != "MSG0001" {
+		t.Fatalf("unexpected message type: %s^%s (%s)", msgType, event, msg.ControlID())
+	}
+	pt := msg.Patient(testSystem)
+	if pt.GetLastname() != "DUMMY" || pt.GetFirstnames() != "ALBERT JOHN" || pt.GetTitle() != "DR" || pt.GetGender() != apiv1.Gender_MALE || pt.GetBirthDate().Format("2006-01-02") != "1960-01-01" {
+		t.Errorf("unexpected patient: %v", pt)
+	}
+	if ids := pt.GetIdentifiers(); len(ids) != 2 || ids[0].GetSystem() != identifiers.NHSNumber || ids[1].GetSystem() != identifiers.CardiffAndValeCRN || ids[1].GetValue() != "A999998" {
+		t.Errorf("unexpected identifiers: %v", ids)
+	}
+	if len(pt.GetAddresses()) != 1 || pt.GetAddresses()[0].GetPostcode() != "CF14 4XW" || pt.GetAddresses()[0].GetAddress3() != "Cardiff" || pt.GetAddresses()[0].GetCounty() != "South Glamorgan" || pt.GetAddresses()[0].GetCountry() != "" {
+		t.Errorf("unexpected addresses: %v", pt.GetAddresses())
+	}
+	if pt.GetNhsNumberVerificationStatus() != apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED {
+		t.Errorf("unexpected NHS number verification status: %s", pt.GetNhsNumberVerificationStatus())
+	}
+	if len(pt.GetTelephones()) != 1 || len(pt.GetEmails()) != 1 || pt.GetSurgery().GetValue() != "W95010" || pt.GetGeneralPractitioner().GetValue() != "G9342400" {
+		t.Errorf("unexpected contact or general practice details: %v", pt)
+	}
+	if msg, err := Parse([]byte(`MSH|^~\&|A|B||||||||2.5` + "\rPID|1||X\\F\\Y^^^NHS")); err != nil || msg.Get("PID", 3, 1) != "X|Y" {
+		t.Errorf("expected escaped field separator to be unescaped, got %v (%v)", msg, err)
+	}
+	if _, err := Parse([]byte("PID|1")); err == nil {
+		t.Errorf("expected error for message without MSH segment")
+	}
+}
+
+func TestServer(t *testing.T) {
+	received := make(chan *events.Event, 10)
+	events.Subscribe(func(e *events.Event) {
+		if e.Type == events.PatientUpdated || e.Type == events.PatientMerged {
+			received <- e
+		}
+	})
+	sv := NewServer(testSystem)
+	if err := sv.ListenAndServe("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	defer sv.Close()
+	conn, err := net.Dial("tcp", sv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	send := func(msg string) string {
+		if _, err := conn.Write(frame([]byte(msg))); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		b, err := readFrame(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	if ack := send(testA31); !strings.Contains(ack, "MSA|AA|MSG0001") {
+		t.Fatalf("expected acknowledgement, got %s", ack)
+	}
+	if e := <-received; e.Type != events.PatientUpdated || e.Subject.GetValue() != "1111111111" || e.Data.(*apiv1.Patient).GetLastname() != "DUMMY" {
+		t.Errorf("unexpected event: %v", e)
+	}
+	if ack := send(testA40); !strings.Contains(ack, "MSA|AA|MSG0002") {
+		t.Fatalf("expected acknowledgement, got %s", ack)
+	}
+	if e := <-received; e.Type != events.PatientMerged || e.Subject.GetValue() != "1111111111" || e.Data.(*apiv1.Identifier).GetValue() != "A999997" {
+		t.Errorf("unexpected event: %v", e)
+	}
+	if ack := send(strings.Replace(testA31, "ADT^A31", "ORU^R01", 1)); !strings.Contains(ack, "MSA|AR|MSG0001") {
+		t.Fatalf("expected rejection of unsupported message, got %s", ack)
+	}
+}
+
+func TestMDMNotifier(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	received := make(chan *Message, 2)
+	go func() {
+		for _, code := range []string{"AA", "AE"} {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := readFrame(bufio.NewReader(conn))
+			if err == nil {
+				msg, _ := Parse(b)
+				received <- msg
+				conn.Write(frame(ack(msg, code, "")))
+			}
+			conn.Close()
+		}
+	}()
+	dt := timestamppb.New(time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC))
+	d := &apiv1.Document{
+		Id:       &apiv1.Identifier{System: "https://example.com/Id/document", Value: "doc|1"},
+		Status:   apiv1.Document_FINAL,
+		Title:    "Clinic letter",
+		DateTime: dt,
+		Patient: &apiv1.Patient{
+			Lastname:    "DUMMY",
+			Firstnames:  "ALBERT",
+			Gender:      apiv1.Gender_MALE,
+			Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}, {System: "https://example.com/Id/unknown", Value: "X1"}},
+		},
+		Data: &apiv1.Attachment{ContentType: "application/pdf", Data: []byte("%PDF-1.4")},
+	}
+	response := &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: "https://example.com/Id/repository", Value: "R1"}}
+	mn := NewMDMNotifier(l.Addr().String(), MDMOptions{ReceivingApplication: "EPR"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := mn.NotifyPublished(ctx, d, response); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-received
+	if msgType, event := msg.Type(); msgType != "MDM" || event != "T02" || msg.Get("MSH", 5, 1) != "EPR" {
+		t.Errorf("unexpected message header: %v", msg.Segment("MSH"))
+	}
+	if pid := msg.Segment("PID"); len(msg.Repetitions(pid, 3)) != 1 || msg.Get("PID", 3, 1) != "1111111111" || msg.Get("PID", 3, 4) != "NHS" || msg.Get("PID", 5, 1) != "DUMMY" || msg.Get("PID", 8, 1) != "M" {
+		t.Errorf("unexpected patient: %v", pid)
+	}
+	if msg.Get("TXA", 12, 1) != "doc|1" || msg.Get("TXA", 16, 1) != "R1" || msg.Get("TXA", 17, 1) != "AU" || msg.Get("TXA", 19, 1) != "AV" || msg.Get("TXA", 4, 1) != "20200401120000" {
+		t.Errorf("unexpected document details: %v", msg.Segment("TXA"))
+	}
+	if msg.Get("OBX", 5, 3) != "pdf" || msg.Get("OBX", 5, 5) != "JVBERi0xLjQ=" {
+		t.Errorf("unexpected document content: %v", msg.Segment("OBX"))
+	}
+	if err := mn.NotifyPublished(ctx, d, response); err == nil {
+		t.Errorf("expected error when notification rejected")
+	}
+	<-received
+}
+
+func TestADTA08(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	received := make(chan *Message, 2)
+	go func() {
+		for _, code := range []string{"AA", "AR"} {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := readFrame(bufio.NewReader(conn))
+			if err == nil {
+				msg, _ := Parse(b)
+				received <- msg
+				conn.Write(frame(ack(msg, code, "")))
+			}
+			conn.Close()
+		}
+	}()
+	pt := &apiv1.Patient{
+		Lastname:    "DUMMY",
+		Firstnames:  "ALBERT",
+		Gender:      apiv1.Gender_MALE,
+		Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}},
+		Addresses:   []*apiv1.Address{{Address1: "1 Station Road", Address2: "Heath", Address3: "Cardiff", Postcode: "CF14 4XW"}},
+		Telephones:  []*apiv1.Telephone{{Number: "02920747747"}, {Number: "07700900000", Use: apiv1.Telephone_MOBILE}},
+		Emails:      []string{"test@example.com"},
+	}
+	operator := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	msg := NewADTA08(pt, operator, ADTOptions{ReceivingApplication: "EMPI", ProcessingID: "T"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := SendADT(ctx, l.Addr().String(), msg); err != nil {
+		t.Fatal(err)
+	}
+	m := <-received
+	if msgType, event := m.Type(); msgType != "ADT" || event != "A08" || m.Get("MSH", 11, 1) != "T" {
+		t.Errorf("unexpected message header: %v", m.Segment("MSH"))
+	}
+	if m.Get("EVN", 5, 1) != "ma090906" {
+		t.Errorf("expected operator in EVN-5, got: %v", m.Segment("EVN"))
+	}
+	updated := m.Patient(DefaultSystem)
+	if len(updated.GetAddresses()) != 1 || updated.GetAddresses()[0].GetPostcode() != "CF14 4XW" || updated.GetAddresses()[0].GetAddress3() != "Cardiff" || updated.GetAddresses()[0].GetUse() != apiv1.Address_HOME {
+		t.Errorf("unexpected addresses: %v", updated.GetAddresses())
+	}
+	if len(updated.GetTelephones()) != 2 || updated.GetTelephones()[1].GetNumber() != "07700900000" || updated.GetTelephones()[1].GetUse() != apiv1.Telephone_MOBILE || m.Get("PID", 13, 3) != "PH" {
+		t.Errorf("unexpected telephones: %v", updated.GetTelephones())
+	}
+	if len(updated.GetEmails()) != 1 || updated.GetEmails()[0] != "test@example.com" {
+		t.Errorf("unexpected emails: %v", updated.GetEmails())
+	}
+	if updated.GetLastname() != "DUMMY" || len(updated.GetIdentifiers()) != 1 || updated.GetIdentifiers()[0].GetValue() != "1111111111" {
+		t.Errorf("unexpected patient: %v", updated)
+	}
+	if err := SendADT(ctx, l.Addr().String(), msg); err == nil {
+		t.Errorf("expected error when update rejected")
+	}
+	<-received
+}
@@ -0,0 +1,201 @@
+package hl7v2
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthorityFunc returns the assigning authority (CX-4) code for an identifier system (URI),
+// or an empty string if the system has no known authority
+type AuthorityFunc func(system string) string
+
+// DefaultAuthority maps NHS numbers to the assigning authority "NHS"
+func DefaultAuthority(system string) string {
+	if system == identifiers.NHSNumber {
+		return "NHS"
+	}
+	return ""
+}
+
+// MDMOptions configures the generation of MDM^T02 messages
+type MDMOptions struct {
+	ReceivingApplication string
+	ReceivingFacility    string
+	Authority            AuthorityFunc // used to determine the assigning authority of patient identifiers
+}
+
+// MDMNotifier sends an MDM^T02 (original document notification and content) message over MLLP
+// for each published document, so that legacy electronic patient records can index new documents
+// without polling.
+type MDMNotifier struct {
+	addr string
+	opts MDMOptions
+}
+
+// NewMDMNotifier creates a notifier sending messages to the MLLP endpoint at the address specified (e.g. "epr:2575")
+func NewMDMNotifier(addr string, opts MDMOptions) *MDMNotifier {
+	if opts.Authority == nil {
+		opts.Authority = DefaultAuthority
+	}
+	return &MDMNotifier{addr: addr, opts: opts}
+}
+
+// NotifyPublished sends an MDM^T02 message for the published document
+func (mn *MDMNotifier) NotifyPublished(ctx context.Context, d *apiv1.Document, response *apiv1.PublishDocumentResponse) error {
+	msg := NewMDMT02(d, response, mn.opts)
+	ack, err := Send(ctx, mn.addr, msg)
+	if err != nil {
+		return err
+	}
+	if code := ack.Get("MSA", 1, 1); code != "AA" && code != "CA" {
+		return status.Errorf(codes.Unavailable, "hl7v2: document notification rejected (%s): %s", code, ack.Get("MSA", 3, 1))
+	}
+	log.Printf("hl7v2: sent document notification for %s|%s to %s", d.GetId().GetSystem(), d.GetId().GetValue(), mn.addr)
+	return nil
+}
+
+// NewMDMT02 generates an MDM^T02 message for a document, published with the response specified
+func NewMDMT02(d *apiv1.Document, response *apiv1.PublishDocumentResponse, opts MDMOptions) []byte {
+	if opts.Authority == nil {
+		opts.Authority = DefaultAuthority
+	}
+	now := time.Now().Format("20060102150405")
+	pt := d.GetPatient()
+	segments := []string{
+		fields("MSH", "^~\\&", "CONCIERGE", "", escape(opts.ReceivingApplication), escape(opts.ReceivingFacility), now, "", "MDM^T02^MDM_T02", uuid.New().String(), "P", "2.5"),
+		fields("EVN", "T02", now),
+		fields("PID", "1", "", patientIdentifiers(pt.GetIdentifiers(), opts.Authority), "",
+			escape(pt.GetLastname())+"^"+escape(pt.GetFirstnames())+"^^^"+escape(pt.GetTitle()), "",
+			pt.GetBirthDate().Format("20060102"), gender(pt.GetGender())),
+		fields("PV1", "1", "N"),
+		fields("TXA", "1", codedValue(d.GetType(), d.GetTitle()), "AP",
+			formatDate(d.GetDateTime(), "20060102150405"), authors(d.GetAuthors()), "",
+			formatDate(d.GetTypedDateTime(), "20060102150405"), "", "", "", "",
+			escape(d.GetId().GetValue())+"^"+escape(d.GetId().GetSystem()), "", "", "",
+			escape(response.GetId().GetValue()), completionStatus(d.GetStatus()), "", availabilityStatus(d.GetStatus())),
+	}
+	if data := d.GetData(); len(data.GetData()) > 0 {
+		typ, subtype := "application", "octet-stream"
+		if parts := strings.SplitN(data.GetContentType(), "/", 2); len(parts) == 2 {
+			typ, subtype = parts[0], parts[1]
+		}
+		segments = append(segments, fields("OBX", "1", "ED", codedValue(d.GetType(), d.GetTitle()), "",
+			"^"+escape(typ)+"^"+escape(subtype)+"^Base64^"+base64.StdEncoding.EncodeToString(data.GetData()), "", "", "", "", "", "F"))
+	}
+	return []byte(strings.Join(segments, "\r") + "\r")
+}
+
+// Send sends a message to the MLLP endpoint at the address specified, returning the acknowledgement
+func Send(ctx context.Context, addr string, msg []byte) (*Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "hl7v2: %s", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(frame(msg)); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "hl7v2: %s", err)
+	}
+	b, err := readFrame(bufio.NewReader(conn))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "hl7v2: no acknowledgement: %s", err)
+	}
+	return Parse(b)
+}
+
+// fields joins fields into a segment
+func fields(name string, values ...string) string {
+	return name + "|" + strings.Join(values, "|")
+}
+
+// patientIdentifiers returns identifiers (CX) for those identifiers with a known assigning authority
+func patientIdentifiers(ids []*apiv1.Identifier, authority AuthorityFunc) string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		code := authority(id.GetSystem())
+		if code == "" || id.GetValue() == "" {
+			continue
+		}
+		typeCode := "PI"
+		if id.GetSystem() == identifiers.NHSNumber {
+			typeCode = "NH"
+		}
+		result = append(result, escape(id.GetValue())+"^^^"+escape(code)+"^"+typeCode)
+	}
+	return strings.Join(result, "~")
+}
+
+// authors returns the authors of the document as extended composite IDs (XCN)
+func authors(ids []*apiv1.Identifier) string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, escape(id.GetValue())+"^^^^^^^^"+escape(id.GetSystem()))
+	}
+	return strings.Join(result, "~")
+}
+
+// codedValue returns a coded element (CE) for the identifier specified, using the text as description
+func codedValue(id *apiv1.Identifier, text string) string {
+	if id.GetValue() == "" {
+		return "^" + escape(text)
+	}
+	system := id.GetSystem()
+	if system == identifiers.SNOMEDCT {
+		system = "SCT"
+	}
+	return escape(id.GetValue()) + "^" + escape(text) + "^" + escape(system)
+}
+
+func gender(g apiv1.Gender) string {
+	switch g {
+	case apiv1.Gender_MALE:
+		return "M"
+	case apiv1.Gender_FEMALE:
+		return "F"
+	}
+	return "U"
+}
+
+// completionStatus returns the document completion status (TXA-17)
+func completionStatus(s apiv1.Document_Status) string {
+	switch s {
+	case apiv1.Document_DRAFT:
+		return "IP" // in progress
+	case apiv1.Document_FINAL, apiv1.Document_AMENDED:
+		return "AU" // authenticated
+	}
+	return "DO" // documented
+}
+
+// availabilityStatus returns the document availability status (TXA-19)
+func availabilityStatus(s apiv1.Document_Status) string {
+	if s == apiv1.Document_IN_ERROR {
+		return "CA" // cancelled
+	}
+	return "AV" // available
+}
+
+func formatDate(ts *timestamppb.Timestamp, layout string) string {
+	if ts == nil {
+		return ""
+	}
+	if ts.CheckValid() != nil {
+		return ""
+	}
+	return ts.AsTime().Format(layout)
+}
@@ -0,0 +1,132 @@
+// Package hl7v2 provides an HL7 v2 MLLP listener for inbound ADT feeds, so that concierge is notified
+// of changes to patient demographics and of merges, rather than relying only upon on-demand queries.
+//
+// Supported messages are ADT^A28 (add person), ADT^A31 (update person) and ADT^A40 (merge patient).
+// Patient details are parsed from the PID and PD1 segments, and published as patient-updated and
+// patient-merged events, which are delivered to both external brokers and internal subscribers,
+// such as caches that need to be invalidated.
+//
+// Outbound MDM^T02 (original document notification and content) messages may also be sent to
+// a legacy electronic patient record after successful publication of a document.
+package hl7v2
+
+import (
+	"errors"
+	"strings"
+)
+
+// Message is a parsed HL7 v2 message
+type Message struct {
+	Segments []Segment
+	enc      encoding
+}
+
+// Segment is a single segment of a message; fields are numbered from 1, as in the HL7 specification,
+// with the segment name held at index 0. For MSH segments, MSH-1 is the field separator.
+type Segment []string
+
+// encoding represents the encoding characters for a message
+type encoding struct {
+	field, component, repetition, escape, subcomponent byte
+}
+
+// Parse parses an HL7 v2 message. Segments may be separated by carriage returns or newlines.
+func Parse(b []byte) (*Message, error) {
+	s := strings.TrimSpace(strings.ReplaceAll(string(b), "\n", "\r"))
+	if !strings.HasPrefix(s, "MSH") || len(s) < 8 {
+		return nil, errors.New("hl7v2: message does not start with MSH segment")
+	}
+	enc := encoding{field: s[3], component: s[4], repetition: s[5], escape: s[6], subcomponent: s[7]}
+	msg := &Message{enc: enc}
+	for _, line := range strings.Split(s, "\r") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, string(enc.field))
+		if fields[0] == "MSH" { // MSH-1 is the field separator itself, so shift fields to keep numbering
+			fields = append([]string{"MSH", string(enc.field)}, fields[1:]...)
+		}
+		msg.Segments = append(msg.Segments, Segment(fields))
+	}
+	return msg, nil
+}
+
+// Segment returns the first segment with the name specified, or nil
+func (m *Message) Segment(name string) Segment {
+	for _, seg := range m.Segments {
+		if seg[0] == name {
+			return seg
+		}
+	}
+	return nil
+}
+
+// Field returns the raw value of the field specified, or an empty string
+func (s Segment) Field(n int) string {
+	if n < len(s) {
+		return s[n]
+	}
+	return ""
+}
+
+// Repetitions returns the repetitions of the field specified
+func (m *Message) Repetitions(s Segment, n int) []string {
+	v := s.Field(n)
+	if v == "" {
+		return nil
+	}
+	if s[0] == "MSH" && n == 2 {
+		return []string{v}
+	}
+	return strings.Split(v, string(m.enc.repetition))
+}
+
+// Component returns the unescaped value of a component (numbered from 1) of a field value,
+// ignoring any subcomponents
+func (m *Message) Component(v string, n int) string {
+	components := strings.Split(v, string(m.enc.component))
+	if n < 1 || n > len(components) {
+		return ""
+	}
+	c := components[n-1]
+	if i := strings.IndexByte(c, m.enc.subcomponent); i >= 0 {
+		c = c[:i]
+	}
+	return m.unescape(c)
+}
+
+// Get returns the unescaped value of the first repetition of the component of a field of the first
+// segment with the name specified, e.g. Get("PID", 5, 1) returns the family name of the patient.
+func (m *Message) Get(segment string, field int, component int) string {
+	reps := m.Repetitions(m.Segment(segment), field)
+	if len(reps) == 0 {
+		return ""
+	}
+	return m.Component(reps[0], component)
+}
+
+// Type returns the message type and trigger event, e.g. "ADT" and "A31"
+func (m *Message) Type() (string, string) {
+	return m.Get("MSH", 9, 1), m.Get("MSH", 9, 2)
+}
+
+// ControlID returns the message control identifier (MSH-10)
+func (m *Message) ControlID() string {
+	return m.Get("MSH", 10, 1)
+}
+
+// unescape replaces the standard escape sequences for the encoding characters
+func (m *Message) unescape(s string) string {
+	if strings.IndexByte(s, m.enc.escape) < 0 {
+		return s
+	}
+	esc := string(m.enc.escape)
+	return strings.NewReplacer(
+		esc+"F"+esc, string(m.enc.field),
+		esc+"S"+esc, string(m.enc.component),
+		esc+"R"+esc, string(m.enc.repetition),
+		esc+"T"+esc, string(m.enc.subcomponent),
+		esc+"E"+esc, esc,
+		esc+".br"+esc, "\n",
+	).Replace(s)
+}
@@ -0,0 +1,246 @@
+package hl7v2
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// MLLP framing characters
+const (
+	startBlock = 0x0b
+	endBlock   = 0x1c
+)
+
+// readTimeout is the time after which an idle connection is closed
+const readTimeout = 10 * time.Minute
+
+// Server is an MLLP server receiving HL7 v2 ADT messages. This is thread-safe.
+type Server struct {
+	system SystemFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a new server using the function specified to determine the system of patient identifiers
+func NewServer(system SystemFunc) *Server {
+	if system == nil {
+		system = DefaultSystem
+	}
+	return &Server{system: system, conns: make(map[net.Conn]struct{})}
+}
+
+// ListenAndServe listens on the TCP address specified (e.g. ":2575") and handles messages in the background
+func (sv *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	sv.mu.Lock()
+	sv.listener = l
+	sv.mu.Unlock()
+	log.Printf("hl7v2: listening for MLLP connections on %s", l.Addr())
+	sv.wg.Add(1)
+	go sv.serve(l)
+	return nil
+}
+
+// Addr returns the address on which the server is listening, or nil if not listening
+func (sv *Server) Addr() net.Addr {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.listener == nil {
+		return nil
+	}
+	return sv.listener.Addr()
+}
+
+// Close stops listening, and closes any open connections
+func (sv *Server) Close() error {
+	sv.mu.Lock()
+	var err error
+	if sv.listener != nil {
+		err = sv.listener.Close()
+	}
+	for conn := range sv.conns {
+		conn.Close()
+	}
+	sv.mu.Unlock()
+	sv.wg.Wait()
+	return err
+}
+
+func (sv *Server) serve(l net.Listener) {
+	defer sv.wg.Done()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		sv.mu.Lock()
+		sv.conns[conn] = struct{}{}
+		sv.mu.Unlock()
+		sv.wg.Add(1)
+		go sv.handleConn(conn)
+	}
+}
+
+// handleConn reads framed messages from the connection, replying with an acknowledgement for each
+func (sv *Server) handleConn(conn net.Conn) {
+	defer sv.wg.Done()
+	defer func() {
+		sv.mu.Lock()
+		delete(sv.conns, conn)
+		sv.mu.Unlock()
+		conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		b, err := readFrame(r)
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("hl7v2: connection from %s closed: %s", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		ack := sv.handle(b)
+		if _, err := conn.Write(frame(ack)); err != nil {
+			log.Printf("hl7v2: failed to send acknowledgement to %s: %s", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// readFrame reads a single MLLP framed message
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	if _, err := r.ReadBytes(startBlock); err != nil {
+		return nil, err
+	}
+	b, err := r.ReadBytes(endBlock)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.ReadByte(); err != nil { // trailing carriage return
+		return nil, err
+	}
+	return b[:len(b)-1], nil
+}
+
+// frame wraps a message in MLLP framing
+func frame(b []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(startBlock)
+	buf.Write(b)
+	buf.WriteByte(endBlock)
+	buf.WriteByte('\r')
+	return buf.Bytes()
+}
+
+// handle processes a single message, returning an acknowledgement
+func (sv *Server) handle(b []byte) []byte {
+	msg, err := Parse(b)
+	if err != nil {
+		log.Printf("hl7v2: invalid message: %s", err)
+		return ack(nil, "AR", err.Error())
+	}
+	if err := sv.process(msg); err != nil {
+		log.Printf("hl7v2: rejected message %s: %s", msg.ControlID(), err)
+		return ack(msg, "AR", err.Error())
+	}
+	return ack(msg, "AA", "")
+}
+
+// process publishes events for a supported ADT message
+func (sv *Server) process(msg *Message) error {
+	msgType, event := msg.Type()
+	if msgType != "ADT" {
+		return fmt.Errorf("unsupported message type: %s", msgType)
+	}
+	switch event {
+	case "A28", "A31":
+		pt := msg.Patient(sv.system)
+		subject := subjectIdentifier(pt.GetIdentifiers())
+		if subject == nil {
+			return fmt.Errorf("no patient identifier in PID-3")
+		}
+		log.Printf("hl7v2: received %s for %s|%s", event, subject.GetSystem(), subject.GetValue())
+		events.PublishPatientUpdated("hl7v2/"+subject.GetSystem()+"|"+subject.GetValue(), subject, pt)
+	case "A40":
+		pt := msg.Patient(sv.system)
+		subject := subjectIdentifier(pt.GetIdentifiers())
+		merged := msg.MergedIdentifiers(sv.system)
+		if subject == nil || len(merged) == 0 {
+			return fmt.Errorf("merge requires patient (PID-3) and prior patient (MRG-1) identifiers")
+		}
+		for _, id := range merged {
+			log.Printf("hl7v2: received merge of %s|%s into %s|%s", id.GetSystem(), id.GetValue(), subject.GetSystem(), subject.GetValue())
+			events.Publish(&events.Event{Type: events.PatientMerged, Subject: subject, Data: id})
+		}
+	default:
+		return fmt.Errorf("unsupported trigger event: %s", event)
+	}
+	return nil
+}
+
+// subjectIdentifier returns the identifier to be used as the subject of events, preferring the NHS number
+func subjectIdentifier(ids []*apiv1.Identifier) *apiv1.Identifier {
+	for _, id := range ids {
+		if id.GetSystem() == identifiers.NHSNumber {
+			return id
+		}
+	}
+	if len(ids) > 0 {
+		return ids[0]
+	}
+	return nil
+}
+
+// ack returns an acknowledgement for the message specified, which may be nil if it could not be parsed
+func ack(msg *Message, code string, text string) []byte {
+	var sendingApp, sendingFacility, controlID, version string
+	if msg != nil {
+		sendingApp, sendingFacility = msg.Get("MSH", 3, 1), msg.Get("MSH", 4, 1)
+		controlID, version = msg.ControlID(), msg.Get("MSH", 12, 1)
+	}
+	if version == "" {
+		version = "2.5"
+	}
+	now := time.Now().Format("20060102150405")
+	return []byte(fmt.Sprintf("MSH|^~\\&|CONCIERGE||%s|%s|%s||ACK|%s|P|%s\rMSA|%s|%s|%s\r",
+		sendingApp, sendingFacility, now, controlID+"-ACK", version, code, controlID, escape(text)))
+}
+
+// escape escapes text for inclusion in an acknowledgement using the default encoding characters
+func escape(s string) string {
+	var sb bytes.Buffer
+	for _, c := range []byte(s) {
+		switch c {
+		case '\\':
+			sb.WriteString(`\E\`)
+		case '|':
+			sb.WriteString(`\F\`)
+		case '^':
+			sb.WriteString(`\S\`)
+		case '&':
+			sb.WriteString(`\T\`)
+		case '~':
+			sb.WriteString(`\R\`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
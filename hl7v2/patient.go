@@ -0,0 +1,134 @@
+package hl7v2
+
+import (
+	"log"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/hl7time"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SystemFunc returns the identifier system (URI) for an assigning authority (CX-4) code,
+// or an empty string if the authority is unknown
+type SystemFunc func(authority string) string
+
+// DefaultSystem maps the assigning authority "NHS" to NHS numbers
+func DefaultSystem(authority string) string {
+	if authority == "NHS" {
+		return identifiers.NHSNumber
+	}
+	return ""
+}
+
+// Patient returns the patient from the PID and PD1 segments of the message, using the function specified
+// to determine the system of each patient identifier. Identifiers from unknown authorities use the authority code
+// as system, as for identifiers returned from the Welsh EMPI.
+func (m *Message) Patient(system SystemFunc) *apiv1.Patient {
+	pid := m.Segment("PID")
+	if pid == nil {
+		return nil
+	}
+	pt := &apiv1.Patient{
+		Lastname:    m.Get("PID", 5, 1),
+		Firstnames:  strings.TrimSpace(m.Get("PID", 5, 2) + " " + m.Get("PID", 5, 3)),
+		Title:       m.Get("PID", 5, 5),
+		Identifiers: m.identifiers(pid, 3, system),
+		BirthDate:   parseBirthDate(m.Get("PID", 7, 1)),
+	}
+	switch m.Get("PID", 8, 1) {
+	case "M":
+		pt.Gender = apiv1.Gender_MALE
+	case "F":
+		pt.Gender = apiv1.Gender_FEMALE
+	}
+	if dd := parseDate(m.Get("PID", 29, 1)); dd != nil {
+		pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: dd}
+	} else if m.Get("PID", 30, 1) == "Y" {
+		pt.Deceased = &apiv1.Patient_DeceasedBoolean{DeceasedBoolean: true}
+	}
+	for _, xad := range m.Repetitions(pid, 11) {
+		pt.Addresses = append(pt.Addresses, &apiv1.Address{
+			Address1: m.Component(xad, 1),
+			Address2: m.Component(xad, 2),
+			Address3: m.Component(xad, 3),
+			County:   m.Component(xad, 4), // as used by the Welsh EMPI, which uses XAD-4 for county
+			Postcode: m.Component(xad, 5),
+			Country:  m.Component(xad, 6),
+			Use:      apiv1.ParseAddressUse(m.Component(xad, 7)),
+			Period:   &apiv1.Period{Start: parseDate(m.Component(xad, 13)), End: parseDate(m.Component(xad, 14))},
+		})
+	}
+	telephoneFieldUses := map[int]apiv1.Telephone_Use{13: apiv1.Telephone_HOME, 14: apiv1.Telephone_WORK} // PID-13 home, PID-14 business
+	for _, field := range []int{13, 14} {
+		for _, xtn := range m.Repetitions(pid, field) {
+			if number := m.Component(xtn, 1); number != "" {
+				use := apiv1.ParseTelephoneUse(m.Component(xtn, 2), m.Component(xtn, 3))
+				if use == apiv1.Telephone_UNKNOWN {
+					use = telephoneFieldUses[field]
+				}
+				pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: number, Use: use})
+			}
+			if email := m.Component(xtn, 4); email != "" {
+				pt.Emails = append(pt.Emails, email)
+			}
+		}
+	}
+	pt.NhsNumberVerificationStatus = apiv1.ParseNHSNumberVerificationStatus(m.Get("PID", 32, 1)) // identity reliability code
+	pt.Surgery = identifiers.New(identifiers.ODSCode, m.Get("PD1", 3, 3))
+	pt.GeneralPractitioner = identifiers.New(identifiers.GMPNumber, m.Get("PD1", 4, 1))
+	return pt
+}
+
+// MergedIdentifiers returns the identifiers of the record merged into the patient, from MRG-1
+func (m *Message) MergedIdentifiers(system SystemFunc) []*apiv1.Identifier {
+	mrg := m.Segment("MRG")
+	if mrg == nil {
+		return nil
+	}
+	return m.identifiers(mrg, 1, system)
+}
+
+// identifiers returns the identifiers (CX) from the field specified
+func (m *Message) identifiers(s Segment, field int, system SystemFunc) []*apiv1.Identifier {
+	if system == nil {
+		system = DefaultSystem
+	}
+	result := make([]*apiv1.Identifier, 0)
+	for _, cx := range m.Repetitions(s, field) {
+		value, authority := m.Component(cx, 1), m.Component(cx, 4)
+		if value == "" || authority == "" {
+			continue
+		}
+		uri := system(authority)
+		if uri == "" {
+			uri = authority
+		}
+		result = append(result, &apiv1.Identifier{System: uri, Value: value})
+	}
+	return result
+}
+
+// parseBirthDate parses an HL7 v2 date or timestamp as a calendar date, returning nil if absent or invalid.
+// Invalid dates are logged, rather than causing the message to be rejected.
+func parseBirthDate(d string) *apiv1.Date {
+	t, err := hl7time.Parse(d)
+	if err != nil {
+		log.Printf("hl7v2: %s", err)
+		return nil
+	}
+	return apiv1.NewDate(t.Date().Time)
+}
+
+// parseDate parses an HL7 v2 date or timestamp, returning nil if absent or invalid.
+// Only the date is used, as for dates of death and address periods. Invalid dates are logged, rather than
+// causing the message to be rejected.
+func parseDate(d string) *timestamppb.Timestamp {
+	t, err := hl7time.Parse(d)
+	if err != nil {
+		log.Printf("hl7v2: %s", err)
+		return nil
+	}
+	return t.Date().Timestamp()
+}
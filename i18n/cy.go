@@ -0,0 +1,135 @@
+package i18n
+
+// welsh is the Welsh (Cymraeg) message catalogue, keyed by the English text.
+var welsh = map[string]string{
+	// document and composition statuses
+	"Unknown":          "Anhysbys",
+	"Draft":            "Drafft",
+	"Preliminary":      "Rhagarweiniol",
+	"Final":            "Terfynol",
+	"Amended":          "Diwygiedig",
+	"Entered in error": "Cofnodwyd mewn camgymeriad",
+
+	// telephone descriptions
+	"Home":   "Cartref",
+	"Work":   "Gwaith",
+	"Mobile": "Ffôn symudol",
+	"Office": "Swyddfa",
+	"home":   "cartref",
+	"work":   "gwaith",
+	"mobile": "ffôn symudol",
+	"temp":   "dros dro",
+
+	// job roles
+	"Consultant":                   "Meddyg Ymgynghorol",
+	"General Medical Practitioner": "Meddyg Teulu",
+	"Nurse Consultant":             "Nyrs Ymgynghorol",
+	"Sister/Charge Nurse":          "Chwaer/Nyrs Gyfrifol",
+	"Staff Nurse":                  "Nyrs Staff",
+	"Midwife":                      "Bydwraig",
+	"Dietitian":                    "Deietegydd",
+	"Occupational Therapist":       "Therapydd Galwedigaethol",
+	"Physiotherapist":              "Ffisiotherapydd",
+	"Paramedic":                    "Parafeddyg",
+	"Pharmacist":                   "Fferyllydd",
+	"Healthcare Assistant":         "Cynorthwyydd Gofal Iechyd",
+	"Receptionist":                 "Derbynnydd",
+	"Medical Secretary":            "Ysgrifennydd Meddygol",
+
+	// errors surfaced to end users
+	"no document specified": "dim dogfen wedi'i nodi",
+	"Unable to publish document: no repository found to support patient with these identifiers": "Methu cyhoeddi'r ddogfen: ni chanfuwyd storfa i gefnogi claf gyda'r dynodwyr hyn",
+	"could not publish document: mismatched demographics between Cardiff and Vale and EMPI":     "methu cyhoeddi'r ddogfen: demograffeg anghyson rhwng Caerdydd a'r Fro a'r EMPI",
+	"identifier: missing parameter: system":                                                     "dynodwr: paramedr ar goll: system",
+	"unable to resolve '%s|%s': no resolver for uri":                                            "methu datrys '%s|%s': dim datryswr ar gyfer uri",
+	"unable to map from '%s' to '%s': no mapper for uri":                                        "methu mapio o '%s' i '%s': dim mapiwr ar gyfer uri",
+	"invalid credentials":    "manylion mewngofnodi annilys",
+	"failed to authenticate": "methwyd dilysu",
+	"need service account login before logging in using normal user account": "angen mewngofnodi gyda chyfrif gwasanaeth cyn mewngofnodi gyda chyfrif defnyddiwr arferol",
+	"patient %s/%s not found":                                                                "claf %s/%s heb ei ganfod",
+	"terminology: search text required":                                                      "terminoleg: angen testun chwilio",
+	"terminology: unsupported expression constraint: %s":                                     "terminoleg: cyfyngiad mynegiant heb ei gefnogi: %s",
+	"invalid SNOMED CT document type: %s":                                                    "math o ddogfen SNOMED CT annilys: %s",
+	"document type '%s' does not satisfy constraint '%s'":                                    "nid yw'r math o ddogfen '%s' yn bodloni'r cyfyngiad '%s'",
+	"invalid LOINC code: %s":                                                                 "cod LOINC annilys: %s",
+	"backend in maintenance: %s":                                                             "gwasanaeth yn cael ei gynnal a'i gadw: %s",
+	"backend in maintenance: %s (%s)":                                                        "gwasanaeth yn cael ei gynnal a'i gadw: %s (%s)",
+	"rate limit exceeded: retry after %d seconds":                                            "wedi mynd y tu hwnt i'r terfyn cyfradd: ceisiwch eto ar ôl %d eiliad",
+	"maintenance: missing parameter: backend":                                                "cynnal a chadw: paramedr ar goll: gwasanaeth",
+	"admin: no document routing rules file configured":                                       "gweinyddu: dim ffeil rheolau llwybro dogfennau wedi'i ffurfweddu",
+	"patient search requires a last name":                                                    "mae chwilio am glaf yn gofyn am gyfenw",
+	"demographic updates require an authenticated user":                                      "mae diweddariadau demograffig yn gofyn am ddefnyddiwr wedi'i ddilysu",
+	"no demographic changes specified":                                                       "dim newidiadau demograffig wedi eu nodi",
+	"invalid email address: %s":                                                              "cyfeiriad e-bost annilys: %s",
+	"NHS Wales' EMPI update interface not configured":                                        "rhyngwyneb diweddaru EMPI GIG Cymru heb ei ffurfweddu",
+	"patient search requires at least one of first names, date of birth, gender or postcode": "mae chwilio am glaf yn gofyn am o leiaf un o enwau cyntaf, dyddiad geni, rhyw neu god post",
+	"invalid date of birth: %d-%02d-%02d":                                                    "dyddiad geni annilys: %d-%02d-%02d",
+	"user not found: %s|%s":                                                                  "defnyddiwr heb ei ganfod: %s|%s",
+	"username, current password and new password required":                                   "angen enw defnyddiwr, cyfrinair presennol a chyfrinair newydd",
+	"new password must be different from current password":                                   "rhaid i'r cyfrinair newydd fod yn wahanol i'r cyfrinair presennol",
+	"new password does not meet the password policy":                                         "nid yw'r cyfrinair newydd yn bodloni'r polisi cyfrineiriau",
+	"no photograph found for user: %s|%s":                                                    "dim ffotograff wedi ei ganfod ar gyfer defnyddiwr: %s|%s",
+	"practitioner directory for namespace '%s' not supported":                                "nid yw cyfeiriadur ymarferwyr ar gyfer y gofod enw '%s' yn cael ei gefnogi",
+	"no deliveries found for document: %s|%s":                                                "dim danfoniadau wedi eu canfod ar gyfer dogfen: %s|%s",
+	"identifier mapping requires two identifiers, each with a system and value":              "mae mapio dynodwyr yn gofyn am ddau ddynodwr, pob un gyda system a gwerth",
+	"identifier mapping requires two different identifiers":                                  "mae mapio dynodwyr yn gofyn am ddau ddynodwr gwahanol",
+	"mapping not found: %s|%s <-> %s|%s":                                                     "mapiad heb ei ganfod: %s|%s <-> %s|%s",
+	"asynchronous publication requires a document queue":                                     "mae cyhoeddi anghydamserol yn gofyn am giw dogfennau",
+	"asynchronous publication requires a document identifier":                                "mae cyhoeddi anghydamserol yn gofyn am ddynodwr dogfen",
+	"invalid publication receipt: %s|%s":                                                     "derbynneb cyhoeddi annilys: %s|%s",
+	"publication receipt not found: %s|%s":                                                   "derbynneb cyhoeddi heb ei chanfod: %s|%s",
+	"document content rejected: %s":                                                          "cynnwys y ddogfen wedi'i wrthod: %s",
+	"document exceeds maximum size of %d bytes":                                              "mae'r ddogfen yn fwy na'r maint mwyaf o %d beit",
+	"invalid PDF document: %s":                                                               "dogfen PDF annilys: %s",
+	"PDF document does not declare PDF/A conformance":                                        "nid yw'r ddogfen PDF yn datgan cydymffurfiaeth PDF/A",
+	"content rejected by %s: %s":                                                             "cynnwys wedi'i wrthod gan %s: %s",
+	"document not found: %s|%s":                                                              "dogfen heb ei chanfod: %s|%s",
+	"organisation not found: %s|%s":                                                          "sefydliad heb ei ganfod: %s|%s",
+	"NHS Wales' EMPI service did not respond within deadline (%d sec)":                       "Ni wnaeth gwasanaeth EMPI GIG Cymru ymateb o fewn y terfyn amser (%d eiliad)",
+	"no composition status found matching code: '%s'":                                        "dim statws cyfansoddiad yn cyfateb i'r cod: '%s'",
+	"permission denied: requires scope '%s'":                                                 "caniatâd wedi'i wrthod: angen cwmpas '%s'",
+	"unknown role '%s': expected one of %v":                                                  "rôl anhysbys '%s': disgwylir un o %v",
+	"missing user":                                                                           "defnyddiwr ar goll",
+	"roles cannot be managed for namespace '%s'":                                             "ni ellir rheoli rolau ar gyfer y gofod enw '%s'",
+	"subscriptions: at least one identifier required":                                        "tanysgrifiadau: angen o leiaf un dynodwr",
+	"subscriptions: too many identifiers (maximum %d)":                                       "tanysgrifiadau: gormod o ddynodwyr (uchafswm %d)",
+	"subscriptions: invalid identifier: %s|%s":                                               "tanysgrifiadau: dynodwr annilys: %s|%s",
+	"subscriptions: invalid cursor: %s":                                                      "tanysgrifiadau: cyrchwr annilys: %s",
+	"subscriptions: cursor has expired; resubscribe without a cursor":                        "tanysgrifiadau: mae'r cyrchwr wedi dod i ben; tanysgrifiwch eto heb gyrchwr",
+	"patient link history not available":                                                     "nid yw hanes cysylltiadau cleifion ar gael",
+	"NHS number %s has not been verified (%s): cannot publish to national repository '%s'":   "nid yw rhif GIG %s wedi'i wirio (%s): ni ellir cyhoeddi i'r storfa genedlaethol '%s'",
+	"patient is deceased (%s): publication not permitted":                                    "mae'r claf wedi marw (%s): ni chaniateir cyhoeddi",
+	"patient is deceased (%s): set allow_deceased to publish":                                "mae'r claf wedi marw (%s): gosodwch allow_deceased i gyhoeddi",
+	"invalid page size: %d":                                                                  "maint tudalen annilys: %d",
+	"invalid page token":                                                                     "tocyn tudalen annilys",
+	"page token does not match request":                                                      "nid yw'r tocyn tudalen yn cyfateb i'r cais",
+	"scheduler: job not found: %s":                                                           "amserlennydd: tasg heb ei chanfod: %s",
+	"scheduler: job already running: %s":                                                     "amserlennydd: mae'r dasg eisoes yn rhedeg: %s",
+	"admin: missing parameter: name":                                                         "gweinyddu: paramedr ar goll: enw",
+	"replacing a document requires a receipt store":                                          "mae angen storfa derbynebau i ddisodli dogfen",
+	"document %s|%s has already been replaced by %s|%s":                                      "mae dogfen %s|%s eisoes wedi'i disodli gan %s|%s",
+	"document history requires a receipt store":                                              "mae angen storfa derbynebau ar gyfer hanes dogfen",
+	"document sensitivity %s not permitted for repository '%s'":                              "ni chaniateir sensitifrwydd dogfen %s ar gyfer storfa '%s'",
+	"upload must start with a publish request":                                               "rhaid i'r uwchlwythiad ddechrau gyda chais cyhoeddi",
+	"upload must contain only one publish request":                                           "rhaid i'r uwchlwythiad gynnwys un cais cyhoeddi yn unig",
+	"invalid identifier '%s|%s': %s":                                                         "dynodwr annilys '%s|%s': %s",
+	"missing value":                                                                          "gwerth ar goll",
+	"must contain only digits":                                                               "rhaid cynnwys digidau yn unig",
+	"incorrect length":                                                                       "hyd anghywir",
+	"incorrect check digit":                                                                  "digid gwirio anghywir",
+	"must not begin with zero":                                                               "rhaid peidio â dechrau gyda sero",
+	"invalid partition identifier":                                                           "dynodwr rhaniad annilys",
+	"invalid format":                                                                         "fformat annilys",
+	"service accounts must be in namespace '%s'":                                             "rhaid i gyfrifon gwasanaeth fod yn y gofod enw '%s'",
+	"service accounts cannot be managed for namespace '%s'":                                  "ni ellir rheoli cyfrifon gwasanaeth ar gyfer y gofod enw '%s'",
+	"no service account '%s'":                                                                "dim cyfrif gwasanaeth '%s'",
+	"service account '%s' already exists":                                                    "mae'r cyfrif gwasanaeth '%s' eisoes yn bodoli",
+	"invalid username: use only letters, digits, '.', '_' and '-'":                           "enw defnyddiwr annilys: defnyddiwch lythrennau, digidau, '.', '_' a '-' yn unig",
+	"expiry must be in the future":                                                           "rhaid i'r dyddiad dod i ben fod yn y dyfodol",
+	"too many failed login attempts: retry after %d seconds":                                 "gormod o ymdrechion mewngofnodi aflwyddiannus: ceisiwch eto ar ôl %d eiliad",
+	"account not active":                                                                     "nid yw'r cyfrif yn weithredol",
+}
+
+func init() {
+	Register(Welsh, welsh)
+}
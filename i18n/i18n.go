@@ -0,0 +1,91 @@
+// Package i18n provides localisation of human-readable strings produced by concierge, such as
+// value-set display names and error messages surfaced to end users.
+// The preferred language is determined from the "accept-language" metadata of a request, which is
+// passed through from the HTTP gateway, and falls back to English when no translation is available.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Supported languages
+var (
+	English = language.BritishEnglish
+	Welsh   = language.MustParse("cy")
+)
+
+var (
+	matcher      = language.NewMatcher([]language.Tag{English, Welsh}) // first is the default
+	cataloguesMu sync.RWMutex
+	catalogues   = make(map[language.Base]map[string]string)
+)
+
+// Register registers translations for the specified language, keyed by the English text.
+func Register(tag language.Tag, messages map[string]string) {
+	base, _ := tag.Base()
+	cataloguesMu.Lock()
+	defer cataloguesMu.Unlock()
+	catalogue, ok := catalogues[base]
+	if !ok {
+		catalogue = make(map[string]string)
+		catalogues[base] = catalogue
+	}
+	for k, v := range messages {
+		catalogue[k] = v
+	}
+}
+
+// Language returns the preferred supported language for the given context, using the
+// "accept-language" incoming metadata, defaulting to English.
+func Language(ctx context.Context) language.Tag {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		return Match(md.Get("accept-language")...)
+	}
+	return English
+}
+
+// Match returns the best supported language for the specified Accept-Language values
+func Match(acceptLanguage ...string) language.Tag {
+	for _, al := range acceptLanguage {
+		tags, _, err := language.ParseAcceptLanguage(al)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		_, index, confidence := matcher.Match(tags...)
+		if confidence > language.No && index == 1 {
+			return Welsh
+		}
+		return English
+	}
+	return English
+}
+
+// Translate returns the translation of the English text in the specified language, falling back to English.
+func Translate(tag language.Tag, text string) string {
+	base, _ := tag.Base()
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+	if catalogue, ok := catalogues[base]; ok {
+		if s, ok := catalogue[text]; ok {
+			return s
+		}
+	}
+	return text
+}
+
+// Sprintf formats the (English) format string translated into the language preferred for the context
+func Sprintf(ctx context.Context, format string, a ...interface{}) string {
+	return fmt.Sprintf(Translate(Language(ctx), format), a...)
+}
+
+// Errorf returns a gRPC status error with a message translated into the language preferred for the context
+func Errorf(ctx context.Context, c codes.Code, format string, a ...interface{}) error {
+	return status.Error(c, Sprintf(ctx, format, a...))
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		welsh          bool
+	}{
+		{"cy", true},
+		{"cy-GB", true},
+		{"cy;q=0.9, en;q=0.8", true},
+		{"en-GB, cy;q=0.5", false},
+		{"fr", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if welsh := Match(test.acceptLanguage) == Welsh; welsh != test.welsh {
+			t.Errorf("accept-language '%s': expected welsh: %v, got: %v", test.acceptLanguage, test.welsh, welsh)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "cy"))
+	if s := Sprintf(ctx, "patient %s/%s not found", "140", "1234567890"); s != "claf 140/1234567890 heb ei ganfod" {
+		t.Errorf("did not translate into Welsh. got: %s", s)
+	}
+	if s := Sprintf(context.Background(), "patient %s/%s not found", "140", "1234567890"); s != "patient 140/1234567890 not found" {
+		t.Errorf("did not fallback to English. got: %s", s)
+	}
+	if s := Sprintf(ctx, "an untranslated message"); s != "an untranslated message" {
+		t.Errorf("did not fallback to English for missing translation. got: %s", s)
+	}
+}
+
+func TestLocalise(t *testing.T) {
+	pt := &apiv1.Patient{Lastname: "Dummy", Telephones: []*apiv1.Telephone{{Number: "02920 747747", Description: "Home"}}}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "cy"))
+	localised := Localise(ctx, pt).(*apiv1.Patient)
+	if d := localised.GetTelephones()[0].GetDescription(); d != "Cartref" {
+		t.Errorf("did not translate telephone description into Welsh. got: %s", d)
+	}
+	if pt.GetTelephones()[0].GetDescription() != "Home" {
+		t.Errorf("original message modified")
+	}
+	if Localise(context.Background(), pt) != pt {
+		t.Errorf("expected message to be unchanged for English")
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := map[string]string{"cy": "cy", "CY": "cy", "cym": "cy", "wel": "cy", "ENG": "en", "q1": "", "": ""}
+	for code, expected := range tests {
+		if got := ParseLanguage(code); got != expected {
+			t.Errorf("language '%s': expected '%s', got '%s'", code, expected, got)
+		}
+	}
+}
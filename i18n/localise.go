@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// localisedFields are the string fields of responses with human-readable values that are translated,
+// such as telephone descriptions and job titles.
+var localisedFields = map[protoreflect.FullName]bool{
+	"apiv1.Telephone.description": true,
+	"apiv1.Role.job_title":        true,
+}
+
+// Localise returns the message with human-readable values translated into the language preferred
+// for the context. The message is not modified; a copy is returned if any values are translated.
+func Localise(ctx context.Context, m proto.Message) proto.Message {
+	tag := Language(ctx)
+	if tag == English || m == nil || !localise(tag, m.ProtoReflect(), false) {
+		return m
+	}
+	m = proto.Clone(m)
+	localise(tag, m.ProtoReflect(), true)
+	return m
+}
+
+// localise translates the localised fields of the message, returning whether any were translated.
+// The message is only modified if update is true.
+func localise(tag language.Tag, m protoreflect.Message, update bool) bool {
+	var translated bool
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case localisedFields[fd.FullName()] && fd.Kind() == protoreflect.StringKind && !fd.IsList():
+			if s := Translate(tag, v.String()); s != v.String() {
+				translated = true
+				if update {
+					m.Set(fd, protoreflect.ValueOfString(s))
+				}
+			}
+		case fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind:
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				translated = localise(tag, list.Get(i).Message(), update) || translated
+			}
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					translated = localise(tag, mv.Message(), update) || translated
+					return true
+				})
+			}
+		default:
+			translated = localise(tag, v.Message(), update) || translated
+		}
+		return update || !translated // stop at the first translation when only checking
+	})
+	return translated
+}
+
+// UnaryServerInterceptor localises the responses of unary calls
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if m, ok := resp.(proto.Message); ok && err == nil {
+		return Localise(ctx, m), nil
+	}
+	return resp, err
+}
+
+// StreamServerInterceptor localises the messages sent by streaming calls
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if Language(ss.Context()) == English {
+		return handler(srv, ss)
+	}
+	return handler(srv, &localisedStream{ServerStream: ss})
+}
+
+type localisedStream struct {
+	grpc.ServerStream
+}
+
+func (ls *localisedStream) SendMsg(m interface{}) error {
+	if msg, ok := m.(proto.Message); ok {
+		m = Localise(ls.Context(), msg)
+	}
+	return ls.ServerStream.SendMsg(m)
+}
+
+// bibliographicCodes maps ISO 639-2/B codes, as used by some systems, to their ISO 639 equivalents
+var bibliographicCodes = map[string]string{
+	"wel": "cy",
+	"fre": "fr",
+	"ger": "de",
+	"dut": "nl",
+	"chi": "zh",
+	"per": "fa",
+	"cze": "cs",
+	"gre": "el",
+}
+
+// ParseLanguage parses a language code, such as the ISO 639 codes recorded in patient administration
+// systems (e.g. "cy", "cym", "wel" or "CY"), returning the BCP 47 language tag, or an empty string
+// if the code is not a recognised language.
+func ParseLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if c, ok := bibliographicCodes[code]; ok {
+		code = c
+	}
+	base, err := language.ParseBase(code)
+	if err != nil {
+		return ""
+	}
+	return base.String()
+}
@@ -7,12 +7,16 @@ import (
 	"errors"
 	"log"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers/validate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -20,13 +24,18 @@ import (
 
 var (
 	systemsMu   sync.RWMutex
-	systems     = make(map[string]apiv1.System)
+	systems     = make(map[string]*apiv1.System)
 	resolversMu sync.RWMutex
 	resolvers   = make(map[string]func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error))
 	mappersMu   sync.RWMutex
 	mappers     = make(map[mapKey]func(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error)
+	store       MappingStore
+	maxHops     = DefaultMaxHops
 )
 
+// DefaultMaxHops is the default maximum number of mappers chained to map an identifier
+const DefaultMaxHops = 3
+
 // ErrNoResolver is an error for when a valid resolver is not registered for the specified URI
 var ErrNoResolver = errors.New("no resolver for uri")
 
@@ -40,7 +49,7 @@ var ErrNotFound = errors.New("identifier not found")
 func Register(name string, uri string) {
 	systemsMu.Lock()
 	defer systemsMu.Unlock()
-	systems[uri] = apiv1.System{Name: name, Uri: uri}
+	systems[uri] = &apiv1.System{Name: name, Uri: uri}
 }
 
 // RegisterResolver registers a handler to resolve the value for the system/identifier tuple
@@ -53,23 +62,45 @@ func RegisterResolver(uri string, f func(ctx context.Context, id *apiv1.Identifi
 	resolvers[uri] = f
 }
 
-// Resolve attempts to resolve the specified system/value tuple
+// Resolve attempts to resolve the specified system/value tuple.
+// The identifier is first checked against the syntax rules of its system, if any (see package validate), so that
+// the resolver is passed only valid identifiers, in canonical form.
 func Resolve(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
 	resolversMu.RLock()
 	resolver, ok := resolvers[id.GetSystem()]
 	resolversMu.RUnlock()
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "unable to resolve '%s|%s': %w", id.GetSystem(), id.GetValue(), ErrNoResolver)
+		return nil, i18n.Errorf(ctx, codes.NotFound, "unable to resolve '%s|%s': no resolver for uri", id.GetSystem(), id.GetValue())
+	}
+	id, err := validate.Validate(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 	return resolver(ctx, id)
 }
 
+// Fallback returns a resolver that tries each resolver in turn, until one does not report that the
+// identifier is not found. This permits, for example, a national service to be used when a local service
+// does not know about an identifier.
+func Fallback(resolvers ...func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error)) func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return func(ctx context.Context, id *apiv1.Identifier) (result proto.Message, err error) {
+		for _, resolver := range resolvers {
+			result, err = resolver(ctx, id)
+			if !errors.Is(err, ErrNotFound) && status.Code(err) != codes.NotFound {
+				return result, err
+			}
+		}
+		return result, err
+	}
+}
+
 type mapKey struct {
 	fromURI string
 	toURI   string
 }
 
-// RegisterMapper registers a handler to map a value from one system to another
+// RegisterMapper registers a handler to map a value from one system to another. As mappings may be
+// one-to-many, such as crossmaps from SNOMED CT to Read codes, the handler calls f for each result.
 func RegisterMapper(fromURI string, toURI string, f func(context.Context, *apiv1.Identifier, func(*apiv1.Identifier) error) error) {
 	mappersMu.Lock()
 	defer mappersMu.Unlock()
@@ -80,6 +111,31 @@ func RegisterMapper(fromURI string, toURI string, f func(context.Context, *apiv1
 	mappers[key] = f
 }
 
+// MappingStore is a store of identifier equivalences, such as those recorded by an administrator
+// for merged patient records. A mapping store is consulted before any registered mappers.
+type MappingStore interface {
+	// Equivalents returns the identifiers recorded as equivalent to the identifier specified
+	Equivalents(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.Identifier, error)
+}
+
+// RegisterMappingStore registers the store of identifier equivalences consulted by Map
+func RegisterMappingStore(s MappingStore) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	store = s
+}
+
+// SetMaxHops sets the maximum number of mappers chained to map an identifier from one system to another.
+// A value of one permits only direct mapping.
+func SetMaxHops(n int) {
+	if n < 1 {
+		n = 1
+	}
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	maxHops = n
+}
+
 // Server is the identifier service that offers resolution and mapping of identifiers based on system/value tuples
 type Server struct{}
 
@@ -108,7 +164,7 @@ func (svc *Server) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux,
 // GetIdentifier resolves an identifier
 func (svc *Server) GetIdentifier(ctx context.Context, id *apiv1.Identifier) (*anypb.Any, error) {
 	if id.GetSystem() == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "identifier: missing parameter: system")
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
 	}
 	o, err := Resolve(ctx, id)
 	if err != nil {
@@ -126,31 +182,226 @@ func (svc *Server) GetIdentifier(ctx context.Context, id *apiv1.Identifier) (*an
 	}, nil
 }
 
-// MapIdentifier resolves an identifier
+// MapIdentifier maps an identifier to another system, streaming each result
 func (svc *Server) MapIdentifier(r *apiv1.IdentifierMapRequest, stream apiv1.Identifiers_MapIdentifierServer) error {
 	id := &apiv1.Identifier{
 		System: r.GetSystem(),
 		Value:  r.GetValue(),
 	}
 	log.Printf("identifiers: mapping '%s|%s' to %s", r.GetSystem(), r.GetValue(), r.GetTargetUri())
-	return Map(stream.Context(), id, r.GetTargetUri(), func(result *apiv1.Identifier) error {
+	first := true
+	return MapWithPath(stream.Context(), id, r.GetTargetUri(), func(result *apiv1.Identifier, path []string) error {
+		if first { // the path of the first result is returned in the response header
+			first = false
+			if err := stream.SetHeader(metadata.Pairs(MappingPathHeader, strings.Join(path, " "))); err != nil {
+				return err
+			}
+		}
 		return stream.Send(result)
 	})
 }
 
-// Map attempts to map an identifier from one code system to another
+// ListSystems returns the identifier systems supported, including those with a registered resolver or mapper
+// that have not been registered, and whether identifiers in each system can be resolved or mapped
+func (svc *Server) ListSystems(ctx context.Context, r *apiv1.ListSystemsRequest) (*apiv1.ListSystemsResponse, error) {
+	uris := make(map[string]struct{})
+	for _, uri := range Systems() {
+		uris[uri] = struct{}{}
+	}
+	for _, uri := range Resolvers() {
+		uris[uri] = struct{}{}
+	}
+	for _, uri := range validate.Systems() {
+		uris[uri] = struct{}{}
+	}
+	mappersMu.RLock()
+	for key := range mappers {
+		uris[key.fromURI] = struct{}{}
+		uris[key.toURI] = struct{}{}
+	}
+	mappersMu.RUnlock()
+	sorted := make([]string, 0, len(uris))
+	for uri := range uris {
+		sorted = append(sorted, uri)
+	}
+	sort.Strings(sorted)
+	result := &apiv1.ListSystemsResponse{Systems: make([]*apiv1.SystemCapabilities, 0, len(sorted))}
+	for _, uri := range sorted {
+		system, ok := Lookup(uri)
+		if !ok {
+			system = &apiv1.System{Uri: uri}
+		}
+		resolversMu.RLock()
+		_, resolvable := resolvers[uri]
+		resolversMu.RUnlock()
+		result.Systems = append(result.Systems, &apiv1.SystemCapabilities{
+			System:     system,
+			Resolvable: resolvable,
+			MappableTo: reachable(uri),
+			Validated:  validate.Has(uri),
+		})
+	}
+	return result, nil
+}
+
+// ValidateIdentifier checks an identifier against the syntax rules of its system
+func (svc *Server) ValidateIdentifier(ctx context.Context, id *apiv1.Identifier) (*apiv1.IdentifierValidation, error) {
+	if id.GetSystem() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	return validate.Check(ctx, id), nil
+}
+
+// MappingPathHeader is the response header (metadata) containing the space-separated identifier systems used
+// to map an identifier, for traceability. For HTTP clients, this is returned as 'Grpc-Metadata-Concierge-Mapping-Path'.
+const MappingPathHeader = "concierge-mapping-path"
+
+// Map attempts to map an identifier from one code system to another.
+// Equivalences in the registered mapping store take precedence over registered mappers.
+// If there is no mapper directly between the two systems, registered mappers are chained, using the
+// shortest chain of no more than the configured maximum number of hops (see SetMaxHops).
 func Map(ctx context.Context, id *apiv1.Identifier, uri string, f func(*apiv1.Identifier) error) error {
+	return MapWithPath(ctx, id, uri, func(result *apiv1.Identifier, path []string) error {
+		return f(result)
+	})
+}
+
+// MapWithPath maps an identifier as per Map, also providing the path of identifier systems used to map
+// each result, for traceability.
+func MapWithPath(ctx context.Context, id *apiv1.Identifier, uri string, f func(result *apiv1.Identifier, path []string) error) error {
 	if id.System == uri {
-		return f(id)
+		return f(id, []string{uri})
+	}
+	direct := []string{id.System, uri}
+	found, err := mapStored(ctx, id, uri, func(result *apiv1.Identifier) error { return f(result, direct) })
+	if err != nil || found {
+		return err
+	}
+	path := shortestPath(id.System, uri)
+	if path == nil {
+		return i18n.Errorf(ctx, codes.NotFound, "unable to map from '%s' to '%s': no mapper for uri", id.System, uri)
 	}
-	key := mapKey{id.System, uri}
+	if len(path) > 2 {
+		log.Printf("identifiers: mapping %s|%s to %s via %s", id.GetSystem(), id.GetValue(), uri, strings.Join(path, " -> "))
+	}
+	seen := make(map[string]struct{}) // chained mappers may produce the same result by more than one route
+	return mapPath(ctx, id, path[1:], func(result *apiv1.Identifier) error {
+		key := result.GetSystem() + "|" + result.GetValue()
+		if _, dup := seen[key]; dup {
+			return nil
+		}
+		seen[key] = struct{}{}
+		return f(result, path)
+	})
+}
+
+// mapStored maps an identifier using equivalences in the registered mapping store, returning whether any were found
+func mapStored(ctx context.Context, id *apiv1.Identifier, uri string, f func(*apiv1.Identifier) error) (bool, error) {
 	mappersMu.RLock()
-	mapper, ok := mappers[key]
+	s := store
 	mappersMu.RUnlock()
-	if !ok {
-		return status.Errorf(codes.NotFound, "unable to map from '%s' to '%s':%s", id.System, uri, ErrNoMapper)
+	if s == nil {
+		return false, nil
+	}
+	equivalents, err := s.Equivalents(ctx, id)
+	if err != nil {
+		log.Printf("identifiers: failed to fetch stored mappings for %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+		return false, status.Errorf(codes.Unavailable, "unable to map from '%s' to '%s': %s", id.System, uri, err)
+	}
+	found := false
+	for _, equivalent := range equivalents {
+		if equivalent.GetSystem() == uri {
+			found = true
+			if err := f(equivalent); err != nil {
+				return found, err
+			}
+		}
+	}
+	return found, nil
+}
+
+// mapPath maps an identifier through each of the systems in the path, using registered mappers
+func mapPath(ctx context.Context, id *apiv1.Identifier, path []string, f func(*apiv1.Identifier) error) error {
+	mappersMu.RLock()
+	mapper, ok := mappers[mapKey{id.GetSystem(), path[0]}]
+	mappersMu.RUnlock()
+	if !ok { // only possible if mappers are registered while mapping
+		return i18n.Errorf(ctx, codes.NotFound, "unable to map from '%s' to '%s': no mapper for uri", id.GetSystem(), path[0])
+	}
+	if len(path) == 1 {
+		return mapper(ctx, id, f)
 	}
-	return mapper(ctx, id, f)
+	return mapper(ctx, id, func(next *apiv1.Identifier) error {
+		return mapPath(ctx, next, path[1:], f)
+	})
+}
+
+// shortestPath returns the shortest chain of systems from one system to another using registered mappers,
+// using a breadth-first search, or nil if there is no chain within the maximum number of hops.
+func shortestPath(fromURI string, toURI string) []string {
+	mappersMu.RLock()
+	defer mappersMu.RUnlock()
+	edges := mapperGraph()
+	previous := map[string]string{fromURI: ""} // system -> previous system in path; also records visited systems
+	frontier := []string{fromURI}
+	for hops := 0; hops < maxHops && len(frontier) > 0; hops++ {
+		var next []string
+		for _, uri := range frontier {
+			targets := edges[uri]
+			sort.Strings(targets) // for a deterministic path
+			for _, target := range targets {
+				if _, visited := previous[target]; visited {
+					continue
+				}
+				previous[target] = uri
+				if target == toURI {
+					path := []string{toURI}
+					for uri := uri; uri != ""; uri = previous[uri] {
+						path = append([]string{uri}, path...)
+					}
+					return path
+				}
+				next = append(next, target)
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
+
+// reachable returns the systems to which identifiers can be mapped from the system specified, by chaining
+// no more than the maximum number of mappers
+func reachable(fromURI string) []string {
+	mappersMu.RLock()
+	defer mappersMu.RUnlock()
+	edges := mapperGraph()
+	visited := map[string]struct{}{fromURI: {}}
+	result := make([]string, 0)
+	frontier := []string{fromURI}
+	for hops := 0; hops < maxHops && len(frontier) > 0; hops++ {
+		var next []string
+		for _, uri := range frontier {
+			for _, target := range edges[uri] {
+				if _, found := visited[target]; !found {
+					visited[target] = struct{}{}
+					result = append(result, target)
+					next = append(next, target)
+				}
+			}
+		}
+		frontier = next
+	}
+	sort.Strings(result)
+	return result
+}
+
+// mapperGraph returns the systems to which each system can be directly mapped. Caller must hold lock.
+func mapperGraph() map[string][]string {
+	edges := make(map[string][]string)
+	for key := range mappers {
+		edges[key.fromURI] = append(edges[key.fromURI], key.toURI)
+	}
+	return edges
 }
 
 // Systems returns a list of the supported identifier systems
@@ -188,12 +439,20 @@ func Mappers() []string {
 	return list
 }
 
+// New returns an identifier with the system and value specified, or nil if the value is empty
+func New(system, value string) *apiv1.Identifier {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return &apiv1.Identifier{System: system, Value: value}
+}
+
 // Lookup returns the system for the specified uri
 func Lookup(uri string) (*apiv1.System, bool) {
 	systemsMu.RLock()
 	defer systemsMu.RUnlock()
 	val, ok := systems[uri]
-	return &val, ok
+	return val, ok
 }
 
 func init() {
@@ -217,6 +476,10 @@ func init() {
 	Register("ODS code", ODSCode)
 	// Organisational data services code for an organisational site
 	Register("ODS site code", ODSSiteCode)
+	// Organisational data services role for an organisation
+	Register("ODS organisation role", ODSOrganisationRole)
 	// NHS number verification status - should be SNOMED CT and not a (semi-)proprietary value set
 	Register("NHS number verification status", NHSNumberVerificationStatus)
+	// General medical practitioner national code ("G" code)
+	Register("GMP - General medical practitioner", GMPNumber)
 }
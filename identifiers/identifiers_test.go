@@ -0,0 +1,141 @@
+package identifiers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// suffix returns a mapper that maps to the target system by appending a suffix to the value
+func suffix(uri string, s ...string) func(context.Context, *apiv1.Identifier, func(*apiv1.Identifier) error) error {
+	return func(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
+		for _, v := range s {
+			if err := f(&apiv1.Identifier{System: uri, Value: id.GetValue() + v}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// registerMapper registers a mapper for the duration of the test
+func registerMapper(t *testing.T, fromURI string, toURI string, f func(context.Context, *apiv1.Identifier, func(*apiv1.Identifier) error) error) {
+	RegisterMapper(fromURI, toURI, f)
+	t.Cleanup(func() {
+		mappersMu.Lock()
+		defer mappersMu.Unlock()
+		delete(mappers, mapKey{fromURI, toURI})
+	})
+}
+
+// registerResolver registers a resolver for the duration of the test
+func registerResolver(t *testing.T, uri string, f func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error)) {
+	RegisterResolver(uri, f)
+	t.Cleanup(func() {
+		resolversMu.Lock()
+		defer resolversMu.Unlock()
+		delete(resolvers, uri)
+	})
+}
+
+func TestChainedMapping(t *testing.T) {
+	registerMapper(t, "a", "b", suffix("b", "-b"))
+	registerMapper(t, "b", "a", suffix("a", "-a"))
+	registerMapper(t, "b", "c", suffix("c", "-c1", "-c2"))
+	registerMapper(t, "c", "d", suffix("d", "-d"))
+	registerMapper(t, "a", "e", suffix("e", "-e"))
+	registerMapper(t, "e", "d", suffix("d", "-d"))
+	defer SetMaxHops(DefaultMaxHops)
+	tests := []struct {
+		to      string
+		maxHops int
+		path    []string
+		values  []string
+	}{
+		{to: "b", maxHops: 1, path: []string{"a", "b"}, values: []string{"1-b"}},
+		{to: "c", maxHops: 1},
+		{to: "c", maxHops: 2, path: []string{"a", "b", "c"}, values: []string{"1-b-c1", "1-b-c2"}},
+		{to: "d", maxHops: 2, path: []string{"a", "e", "d"}, values: []string{"1-e-d"}},
+		{to: "f", maxHops: 10},
+	}
+	for _, test := range tests {
+		SetMaxHops(test.maxHops)
+		var values []string
+		var path []string
+		err := MapWithPath(context.Background(), &apiv1.Identifier{System: "a", Value: "1"}, test.to, func(result *apiv1.Identifier, p []string) error {
+			values = append(values, result.GetValue())
+			path = p
+			return nil
+		})
+		if test.path == nil {
+			if status.Code(err) != codes.NotFound {
+				t.Errorf("a -> %s (max hops %d): expected not found, got %v", test.to, test.maxHops, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(path, test.path) || !reflect.DeepEqual(values, test.values) {
+			t.Errorf("a -> %s: expected %s via %s, got %s via %s", test.to, test.values, strings.Join(test.path, "->"), values, strings.Join(path, "->"))
+		}
+	}
+}
+
+func TestListSystems(t *testing.T) {
+	Register("Test system", "test-a")
+	t.Cleanup(func() {
+		systemsMu.Lock()
+		defer systemsMu.Unlock()
+		delete(systems, "test-a")
+	})
+	registerResolver(t, "test-a", func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) { return id, nil })
+	registerMapper(t, "test-a", "test-b", suffix("test-b", "-b"))
+	registerMapper(t, "test-b", "test-c", suffix("test-c", "-c"))
+	result, err := (&Server{}).ListSystems(context.Background(), &apiv1.ListSystemsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, s := range result.GetSystems() {
+		switch s.GetSystem().GetUri() {
+		case "test-a":
+			found++
+			if s.GetSystem().GetName() != "Test system" || !s.GetResolvable() || !reflect.DeepEqual(s.GetMappableTo(), []string{"test-b", "test-c"}) {
+				t.Errorf("unexpected capabilities for test-a: %v", s)
+			}
+		case "test-c":
+			found++
+			if s.GetResolvable() || len(s.GetMappableTo()) != 0 {
+				t.Errorf("unexpected capabilities for test-c: %v", s)
+			}
+		}
+	}
+	if found != 2 {
+		t.Fatalf("expected unregistered systems with mappers to be listed: %v", result)
+	}
+}
+
+func TestResolveValidates(t *testing.T) {
+	var resolved []string
+	registerResolver(t, NHSNumber, func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+		resolved = append(resolved, id.GetValue())
+		return id, nil
+	})
+	ctx := context.Background()
+	if _, err := Resolve(ctx, &apiv1.Identifier{System: NHSNumber, Value: "632 879 7967"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid NHS number to be rejected, got %v", err)
+	}
+	if _, err := Resolve(ctx, &apiv1.Identifier{System: NHSNumber, Value: "632 879 7966"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resolved, []string{"6328797966"}) {
+		t.Fatalf("expected resolver to be called only with valid NHS number in canonical form, got %v", resolved)
+	}
+}
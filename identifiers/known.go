@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers/validate"
 )
 
 // list of built-in supported systems (although extendable at runtime and by importing other packages)
@@ -21,7 +22,8 @@ const (
 	ReadV2      = "http://read.info/readv2"
 	ReadV3      = "http://read.info/ctv3"
 	GMCNumber   = "https://fhir.hl7.org.uk/Id/gmc-number"
-	NMCPIN      = "https://fhir.hl7.org.uk/Id/nmc-pin" // TODO: has anyone decided URIs for other authorities in UK?
+	GMPNumber   = "https://fhir.hl7.org.uk/Id/gmp-number" // general medical practitioner ("G") national code
+	NMCPIN      = "https://fhir.hl7.org.uk/Id/nmc-pin"    // TODO: has anyone decided URIs for other authorities in UK?
 	SDSUserID   = "https://fhir.nhs.uk/Id/sds-user-id"
 	NHSNumber   = "https://fhir.nhs.uk/Id/nhs-number"
 	ODSCode     = "https://fhir.nhs.uk/Id/ods-organization-code"
@@ -30,7 +32,10 @@ const (
 	// NHS UK / NHS Digital URIs for specific value sets  (arguably all better as SCT identifiers)
 	NHSNumberVerificationStatus = "https://fhir.hl7.org.uk/CareConnect-NHSNumberVerificationStatus-1"
 	SDSJobRoleNameURI           = "https://fhir.nhs.uk/STU3/CodeSystem/CareConnect-SDSJobRoleName-1"
+	ODSOrganisationRole         = "https://directory.spineservices.nhs.uk/STU3/CodeSystem/ODSAPI-OrganizationRole-1"
 	CareConnectEthnicCategory   = "https://fhir.hl7.org.uk/CareConnect-EthnicCategory-1"
+	MaritalStatus               = "http://terminology.hl7.org/CodeSystem/v3-MaritalStatus"
+	NHSSpecialty                = "https://fhir.nhs.uk/CodeSystem/Specialty" // NHS data dictionary main specialty code e.g. 400 (neurology)
 
 	// NHS Wales identifiers - I have made these up in the absence of any other published standard
 	CymruUserID       = "https://fhir.nhs.uk/Id/cymru-user-id"
@@ -45,8 +50,15 @@ const (
 	BetsiWestCRN      = "https://fhir.betsiwest.wales.nhs.uk/Id/pas-identifier"
 
 	// Document repository identifiers
-	CardiffAndValeDocID      = "https://fhir.cardiff.wales.nhs.uk/Id/document-identifier" // internal document identifier from CAV PMS
-	CardiffAndValeClinicCode = "https://fhir.cardiff.wales.nhs.uk/Id/clinic-code"
+	CardiffAndValeDocID         = "https://fhir.cardiff.wales.nhs.uk/Id/document-identifier" // internal document identifier from CAV PMS
+	CardiffAndValeClinicCode    = "https://fhir.cardiff.wales.nhs.uk/Id/clinic-code"
+	CardiffAndValeAppointmentID = "https://fhir.cardiff.wales.nhs.uk/Id/appointment-identifier" // booked slot identifier from CAV PMS
+	CardiffAndValeAdmissionID   = "https://fhir.cardiff.wales.nhs.uk/Id/admission-identifier"   // hospital spell identifier from CAV PMS
+	CardiffAndValeDocumentKey   = "https://fhir.cardiff.wales.nhs.uk/Id/document-key"           // document key used by CAV PMS e.g. "GENERAL LETTER"
+	MESHMessageID               = "https://fhir.nhs.uk/Id/mesh-message-id"                      // message identifier from NHS England MESH
+	WCRSDocumentID              = "https://fhir.wales.nhs.uk/Id/wcrs-document-identifier"       // document identifier from the Welsh Care Records Service
+	PKBDocumentID               = "https://patientsknowbest.com/Id/document-identifier"         // document identifier from Patients Know Best
+	EmailMessageID              = "https://concierge.eldrix.com/Id/email-message-id"            // Message-ID of an email sent to a patient
 
 	// Specific FHIR value sets
 	CompositionStatus = "http://hl7.org/fhir/composition-status" // see https://www.hl7.org/fhir/valueset-composition-status.html
@@ -54,11 +66,19 @@ const (
 	// Concierge service user
 	ConciergeServiceUser    = "https://concierge.eldrix.com/Id/service-user"
 	ConciergeDocumentStatus = "https://concierge.eldrix.com/Id/document-status"
+	ConciergeReceipt        = "https://concierge.eldrix.com/Id/publication-receipt" // receipt for a document queued for publication
 	PatientCare             = "https://patientcare.eldrix.com/Id/patientcare-application"
 )
 
 func init() {
 	RegisterMapper(URI, ODSSiteCode, mapURItoODSSiteCode)
+	validate.Register(NHSNumber, validate.NHSNumber)
+	validate.Register(GMCNumber, validate.GMCNumber)
+	validate.Register(UUID, validate.UUID)
+	validate.Register(SNOMEDCT, validate.SNOMEDCT)
+	for _, uri := range []string{CardiffAndValeCRN, SwanseaBayCRN, CwmTafCRN, AneurinBevanCRN, HywelDdaCRN, BetsiCentralCRN, BetsiMaelorCRN, BetsiWestCRN} {
+		validate.Register(uri, validate.CRN)
+	}
 }
 
 var uriToODSSiteCodeMap = map[string]string{
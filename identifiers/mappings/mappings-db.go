@@ -0,0 +1,111 @@
+package mappings
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq" // postgresql driver
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+type dbStore struct {
+	db *sql.DB
+}
+
+// createMappingsTable creates the table of identifier mappings, if it does not already exist.
+// Each mapping is stored once, and is queried in both directions.
+const createMappingsTable = `CREATE TABLE IF NOT EXISTS identifier_mappings (
+	from_system text NOT NULL,
+	from_value text NOT NULL,
+	to_system text NOT NULL,
+	to_value text NOT NULL,
+	created_by text NOT NULL,
+	created timestamptz NOT NULL,
+	PRIMARY KEY (from_system, from_value, to_system, to_value)
+);
+CREATE INDEX IF NOT EXISTS identifier_mappings_to ON identifier_mappings (to_system, to_value)`
+
+// selectMappings selects the mappings for an identifier, in either direction, with that identifier as 'from'
+const selectMappings = `SELECT to_system, to_value, created_by, created FROM identifier_mappings WHERE from_system=$1 AND from_value=$2
+	UNION SELECT from_system, from_value, created_by, created FROM identifier_mappings WHERE to_system=$1 AND to_value=$2
+	ORDER BY 1, 2`
+
+// NewDatabaseStore creates a store of identifier mappings in a PostgreSQL database
+func NewDatabaseStore(connStr string) (Store, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createMappingsTable); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &dbStore{db: db}, nil
+}
+
+func (ds *dbStore) Equivalents(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.Identifier, error) {
+	mappings, err := ds.Mappings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*apiv1.Identifier, 0, len(mappings))
+	for _, m := range mappings {
+		result = append(result, m.GetTo())
+	}
+	return result, nil
+}
+
+func (ds *dbStore) Mappings(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.IdentifierMapping, error) {
+	rows, err := ds.db.QueryContext(ctx, selectMappings, id.GetSystem(), id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*apiv1.IdentifierMapping, 0)
+	for rows.Next() {
+		to := &apiv1.Identifier{}
+		m := &apiv1.IdentifierMapping{From: id, To: to}
+		var created time.Time
+		if err := rows.Scan(&to.System, &to.Value, &m.CreatedBy, &created); err != nil {
+			return nil, err
+		}
+		m.Created = timestamppb.New(created)
+		if err := m.Created.CheckValid(); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+func (ds *dbStore) Create(ctx context.Context, m *apiv1.IdentifierMapping) error {
+	created := time.Now()
+	if m.GetCreated().CheckValid() == nil {
+		created = m.GetCreated().AsTime()
+	}
+	_, err := ds.db.ExecContext(ctx, `INSERT INTO identifier_mappings (from_system, from_value, to_system, to_value, created_by, created)
+		SELECT $1, $2, $3, $4, $5, $6 WHERE NOT EXISTS
+		(SELECT 1 FROM identifier_mappings WHERE to_system=$1 AND to_value=$2 AND from_system=$3 AND from_value=$4)
+		ON CONFLICT DO NOTHING`,
+		m.GetFrom().GetSystem(), m.GetFrom().GetValue(), m.GetTo().GetSystem(), m.GetTo().GetValue(), m.GetCreatedBy(), created)
+	return err
+}
+
+func (ds *dbStore) Delete(ctx context.Context, m *apiv1.IdentifierMapping) (bool, error) {
+	result, err := ds.db.ExecContext(ctx, `DELETE FROM identifier_mappings
+		WHERE (from_system=$1 AND from_value=$2 AND to_system=$3 AND to_value=$4)
+		OR (from_system=$3 AND from_value=$4 AND to_system=$1 AND to_value=$2)`,
+		m.GetFrom().GetSystem(), m.GetFrom().GetValue(), m.GetTo().GetSystem(), m.GetTo().GetValue())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (ds *dbStore) Close() error {
+	return ds.db.Close()
+}
@@ -0,0 +1,199 @@
+// Package mappings provides a persistent store of identifier equivalences, such as the hospital numbers
+// of merged patient records, and an administrative service to manage them.
+// Stored mappings are symmetric, and are consulted by identifiers.Map before any registered mappers.
+package mappings
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Store is a persistent store of identifier mappings
+type Store interface {
+	identifiers.MappingStore
+	// Mappings returns the mappings for the identifier specified, with that identifier as 'from'
+	Mappings(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.IdentifierMapping, error)
+	// Create records a mapping, doing nothing if the identifiers are already mapped
+	Create(ctx context.Context, m *apiv1.IdentifierMapping) error
+	// Delete removes a mapping, in either direction, returning false if there was no such mapping
+	Delete(ctx context.Context, m *apiv1.IdentifierMapping) (bool, error)
+	Close() error
+}
+
+// Server provides administration of stored identifier mappings
+type Server struct {
+	store Store
+}
+
+var _ apiv1.IdentifierAdminServer = (*Server)(nil)
+
+// NewServer creates a new administrative server for the store specified, and registers the store
+// so that it is consulted when identifiers are mapped.
+func NewServer(store Store) *Server {
+	identifiers.RegisterMappingStore(store)
+	return &Server{store: store}
+}
+
+// RegisterServer registers this server
+func (svc *Server) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterIdentifierAdminServer(s, svc)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (svc *Server) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterIdentifierAdminHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes the underlying store
+func (svc *Server) Close() error {
+	return svc.store.Close()
+}
+
+// GetMappings returns the stored mappings for the identifier specified
+func (svc *Server) GetMappings(ctx context.Context, id *apiv1.Identifier) (*apiv1.IdentifierMappings, error) {
+	if id.GetSystem() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	mappings, err := svc.store.Mappings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.IdentifierMappings{Identifier: id, Mappings: mappings}, nil
+}
+
+// CreateMapping records that two identifiers are equivalent
+func (svc *Server) CreateMapping(ctx context.Context, m *apiv1.IdentifierMapping) (*apiv1.IdentifierMappings, error) {
+	if err := validate(ctx, m); err != nil {
+		return nil, err
+	}
+	m = &apiv1.IdentifierMapping{
+		From:      m.GetFrom(),
+		To:        m.GetTo(),
+		CreatedBy: userName(ctx),
+		Created:   timestamppb.Now(),
+	}
+	if err := svc.store.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	log.Printf("mappings: %s created mapping %s|%s <-> %s|%s", m.GetCreatedBy(), m.GetFrom().GetSystem(), m.GetFrom().GetValue(), m.GetTo().GetSystem(), m.GetTo().GetValue())
+	return svc.GetMappings(ctx, m.GetFrom())
+}
+
+// DeleteMapping removes a mapping between two identifiers
+func (svc *Server) DeleteMapping(ctx context.Context, m *apiv1.IdentifierMapping) (*apiv1.IdentifierMappings, error) {
+	if err := validate(ctx, m); err != nil {
+		return nil, err
+	}
+	deleted, err := svc.store.Delete(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "mapping not found: %s|%s <-> %s|%s", m.GetFrom().GetSystem(), m.GetFrom().GetValue(), m.GetTo().GetSystem(), m.GetTo().GetValue())
+	}
+	log.Printf("mappings: %s deleted mapping %s|%s <-> %s|%s", userName(ctx), m.GetFrom().GetSystem(), m.GetFrom().GetValue(), m.GetTo().GetSystem(), m.GetTo().GetValue())
+	return svc.GetMappings(ctx, m.GetFrom())
+}
+
+// validate checks that a mapping is between two different, fully specified identifiers
+func validate(ctx context.Context, m *apiv1.IdentifierMapping) error {
+	for _, id := range []*apiv1.Identifier{m.GetFrom(), m.GetTo()} {
+		if id.GetSystem() == "" || id.GetValue() == "" {
+			return i18n.Errorf(ctx, codes.InvalidArgument, "identifier mapping requires two identifiers, each with a system and value")
+		}
+	}
+	if proto.Equal(m.GetFrom(), m.GetTo()) {
+		return i18n.Errorf(ctx, codes.InvalidArgument, "identifier mapping requires two different identifiers")
+	}
+	return nil
+}
+
+// userName returns the name of the authenticated user, if any
+func userName(ctx context.Context) string {
+	if user := server.GetContextData(ctx).GetAuthenticatedUser(); user != nil {
+		return user.GetSystem() + "|" + user.GetValue()
+	}
+	return ""
+}
+
+// memoryStore is a non-persistent store of mappings, useful in testing without a database
+type memoryStore struct {
+	mu       sync.RWMutex
+	mappings []*apiv1.IdentifierMapping
+}
+
+// NewMemoryStore creates a store that keeps mappings in memory; these are lost on restart
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+func (ms *memoryStore) Equivalents(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.Identifier, error) {
+	mappings, err := ms.Mappings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*apiv1.Identifier, 0, len(mappings))
+	for _, m := range mappings {
+		result = append(result, m.GetTo())
+	}
+	return result, nil
+}
+
+func (ms *memoryStore) Mappings(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.IdentifierMapping, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	result := make([]*apiv1.IdentifierMapping, 0)
+	for _, m := range ms.mappings {
+		if proto.Equal(m.GetFrom(), id) {
+			result = append(result, proto.Clone(m).(*apiv1.IdentifierMapping))
+		} else if proto.Equal(m.GetTo(), id) {
+			result = append(result, &apiv1.IdentifierMapping{From: m.GetTo(), To: m.GetFrom(), CreatedBy: m.GetCreatedBy(), Created: m.GetCreated()})
+		}
+	}
+	return result, nil
+}
+
+func (ms *memoryStore) Create(ctx context.Context, m *apiv1.IdentifierMapping) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.find(m) >= 0 {
+		return nil
+	}
+	ms.mappings = append(ms.mappings, proto.Clone(m).(*apiv1.IdentifierMapping))
+	return nil
+}
+
+func (ms *memoryStore) Delete(ctx context.Context, m *apiv1.IdentifierMapping) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	i := ms.find(m)
+	if i < 0 {
+		return false, nil
+	}
+	ms.mappings = append(ms.mappings[:i], ms.mappings[i+1:]...)
+	return true, nil
+}
+
+// find returns the index of the mapping between the identifiers specified, in either direction, or -1. Caller must hold lock.
+func (ms *memoryStore) find(m *apiv1.IdentifierMapping) int {
+	for i, o := range ms.mappings {
+		if (proto.Equal(o.GetFrom(), m.GetFrom()) && proto.Equal(o.GetTo(), m.GetTo())) ||
+			(proto.Equal(o.GetFrom(), m.GetTo()) && proto.Equal(o.GetTo(), m.GetFrom())) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ms *memoryStore) Close() error { return nil }
@@ -0,0 +1,55 @@
+package mappings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMappings(t *testing.T) {
+	ctx := context.Background()
+	svc := NewServer(NewMemoryStore())
+	defer identifiers.RegisterMappingStore(nil)
+	cav := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	ctm := &apiv1.Identifier{System: identifiers.CwmTafCRN, Value: "M1147907"}
+	if _, err := svc.CreateMapping(ctx, &apiv1.IdentifierMapping{From: cav, To: cav}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for mapping to self, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		result, err := svc.CreateMapping(ctx, &apiv1.IdentifierMapping{From: cav, To: ctm})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.GetMappings()) != 1 {
+			t.Fatalf("expected a single mapping, got %v", result)
+		}
+	}
+	// mappings are symmetric, and are used by identifiers.Map
+	var mapped []*apiv1.Identifier
+	if err := identifiers.Map(ctx, ctm, identifiers.CardiffAndValeCRN, func(id *apiv1.Identifier) error {
+		mapped = append(mapped, id)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mapped) != 1 || mapped[0].GetValue() != cav.GetValue() {
+		t.Fatalf("expected mapping to %v, got %v", cav, mapped)
+	}
+	result, err := svc.DeleteMapping(ctx, &apiv1.IdentifierMapping{From: ctm, To: cav})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.GetMappings()) != 0 {
+		t.Fatalf("expected no mappings after deletion, got %v", result)
+	}
+	if _, err := svc.DeleteMapping(ctx, &apiv1.IdentifierMapping{From: ctm, To: cav}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := identifiers.Map(ctx, ctm, identifiers.CardiffAndValeCRN, func(*apiv1.Identifier) error { return nil }); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected no mapping once deleted, got %v", err)
+	}
+}
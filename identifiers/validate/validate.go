@@ -0,0 +1,234 @@
+// Package validate provides checks of identifiers against the syntax rules of their system, such as
+// the check digit of an NHS number, so that invalid identifiers are rejected before any backend service
+// is called.
+//
+// Each system registers a validator, which returns the value in canonical form, such as an NHS number
+// without spaces. Identifiers in systems without a registered validator are always valid.
+package validate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+)
+
+// Func validates an identifier value, returning the value in canonical form, or an error explaining why
+// it is invalid. Errors should have fixed messages, so that they can be translated.
+type Func func(value string) (string, error)
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[string]Func)
+)
+
+// Register registers a validator for the identifier system specified
+func Register(uri string, f Func) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	if _, dup := validators[uri]; dup {
+		panic("validate: register validator called twice for URI " + uri)
+	}
+	validators[uri] = f
+}
+
+// Has returns whether a validator is registered for the identifier system specified
+func Has(uri string) bool {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	_, ok := validators[uri]
+	return ok
+}
+
+// Systems returns the identifier systems with a registered validator
+func Systems() []string {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	result := make([]string, 0, len(validators))
+	for uri := range validators {
+		result = append(result, uri)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Check checks an identifier against the syntax rules of its system
+func Check(ctx context.Context, id *apiv1.Identifier) *apiv1.IdentifierValidation {
+	validatorsMu.RLock()
+	f, ok := validators[id.GetSystem()]
+	validatorsMu.RUnlock()
+	if !ok {
+		return &apiv1.IdentifierValidation{Valid: true, Identifier: id}
+	}
+	value, err := f(id.GetValue())
+	if err != nil {
+		return &apiv1.IdentifierValidation{Checked: true, Reason: i18n.Translate(i18n.Language(ctx), err.Error())}
+	}
+	return &apiv1.IdentifierValidation{Valid: true, Checked: true, Identifier: &apiv1.Identifier{System: id.GetSystem(), Value: value}}
+}
+
+// Validate checks an identifier against the syntax rules of its system, returning the identifier in canonical
+// form, or an InvalidArgument error if it is invalid
+func Validate(ctx context.Context, id *apiv1.Identifier) (*apiv1.Identifier, error) {
+	result := Check(ctx, id)
+	if !result.GetValid() {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "invalid identifier '%s|%s': %s", id.GetSystem(), id.GetValue(), result.GetReason())
+	}
+	return result.GetIdentifier(), nil
+}
+
+// errors returned by the built-in validators
+var (
+	ErrEmpty         = errors.New("missing value")
+	ErrNotNumeric    = errors.New("must contain only digits")
+	ErrLength        = errors.New("incorrect length")
+	ErrCheckDigit    = errors.New("incorrect check digit")
+	ErrLeadingZero   = errors.New("must not begin with zero")
+	ErrPartition     = errors.New("invalid partition identifier")
+	ErrInvalidFormat = errors.New("invalid format")
+)
+
+func digits(value string) error {
+	if value == "" {
+		return ErrEmpty
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return ErrNotNumeric
+		}
+	}
+	return nil
+}
+
+// NHSNumber validates an NHS number, which has ten digits, the last of which is a modulus 11 check digit.
+// Spaces are permitted, such as in the formatted 012 345 6789, but are removed.
+// Note: This does not check for repeated (and supposedly invalid) NHS numbers such as 4444444444 and 6666666666
+// This is only an issue for NHS number generation and not the validation we have here.
+func NHSNumber(value string) (string, error) {
+	nnn := strings.ReplaceAll(value, " ", "")
+	if err := digits(nnn); err != nil {
+		return "", err
+	}
+	if len(nnn) != 10 {
+		return "", ErrLength
+	}
+	sum := 0
+	for i, c := range nnn[:9] {
+		sum += int(c-'0') * (10 - i)
+	}
+	cd := 11 - (sum % 11)
+	if cd == 11 {
+		cd = 0
+	}
+	if cd == 10 || cd != int(nnn[9]-'0') {
+		return "", ErrCheckDigit
+	}
+	return nnn, nil
+}
+
+// GMCNumber validates a General Medical Council reference number, which has seven digits
+func GMCNumber(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if err := digits(value); err != nil {
+		return "", err
+	}
+	if len(value) != 7 {
+		return "", ErrLength
+	}
+	return value, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// UUID validates a UUID as per https://tools.ietf.org/html/rfc4122 in its string form, returning it in lowercase
+func UUID(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", ErrEmpty
+	}
+	if !uuidPattern.MatchString(value) {
+		return "", ErrInvalidFormat
+	}
+	return value, nil
+}
+
+var crnPattern = regexp.MustCompile(`^[A-Z][0-9]{6,7}$`)
+
+// CRN validates a hospital case record number (e.g. A999998), being a letter followed by six or seven digits,
+// returning it in uppercase
+func CRN(value string) (string, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return "", ErrEmpty
+	}
+	if !crnPattern.MatchString(value) {
+		return "", ErrInvalidFormat
+	}
+	return value, nil
+}
+
+// SNOMEDCT validates a SNOMED CT identifier, which has between six and eighteen digits, of which the last is a
+// Verhoeff check digit and the two before it are the partition identifier
+func SNOMEDCT(value string) (string, error) {
+	if err := digits(value); err != nil {
+		return "", err
+	}
+	if len(value) < 6 || len(value) > 18 {
+		return "", ErrLength
+	}
+	if value[0] == '0' {
+		return "", ErrLeadingZero
+	}
+	switch value[len(value)-3 : len(value)-1] {
+	case "00", "01", "02", "10", "11", "12":
+	default:
+		return "", ErrPartition
+	}
+	if !Verhoeff(value) {
+		return "", ErrCheckDigit
+	}
+	return value, nil
+}
+
+var verhoeffD = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffP = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// Verhoeff returns whether the string of digits specified has a valid Verhoeff check digit as its last digit
+func Verhoeff(value string) bool {
+	c := 0
+	for i := 0; i < len(value); i++ {
+		d := value[len(value)-1-i]
+		if d < '0' || d > '9' {
+			return false
+		}
+		c = verhoeffD[c][verhoeffP[i%8][d-'0']]
+	}
+	return value != "" && c == 0
+}
@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        Func
+		value    string
+		expected string // canonical value, or empty if invalid
+	}{
+		{"nhs", NHSNumber, "1111111111", "1111111111"},
+		{"nhs", NHSNumber, "482 391 7286", "4823917286"},
+		{"nhs", NHSNumber, "4865447041", ""},
+		{"nhs", NHSNumber, "1234567890", ""},
+		{"nhs", NHSNumber, "a4785", ""},
+		{"nhs", NHSNumber, " ", ""},
+		{"gmc", GMCNumber, "4616734", "4616734"},
+		{"gmc", GMCNumber, "461673", ""},
+		{"gmc", GMCNumber, "461673A", ""},
+		{"uuid", UUID, "C9A4A3C5-4B5E-4D52-9D43-2B1B4CB3C8A1", "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1"},
+		{"uuid", UUID, "doc3", ""},
+		{"crn", CRN, "a999998", "A999998"},
+		{"crn", CRN, "M1147907", "M1147907"},
+		{"crn", CRN, "999998", ""},
+		{"sct", SNOMEDCT, "24700007", "24700007"},
+		{"sct", SNOMEDCT, "900000000000207008", "900000000000207008"},
+		{"sct", SNOMEDCT, "24700008", ""},  // check digit
+		{"sct", SNOMEDCT, "24700037", ""},  // partition
+		{"sct", SNOMEDCT, "024700007", ""}, // leading zero
+		{"sct", SNOMEDCT, "12345", ""},
+	}
+	for _, test := range tests {
+		value, err := test.f(test.value)
+		if value != test.expected || (err == nil) != (test.expected != "") {
+			t.Errorf("%s: '%s': expected '%s', got '%s' (%v)", test.name, test.value, test.expected, value, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	Register("test-nhs", NHSNumber)
+	t.Cleanup(func() {
+		validatorsMu.Lock()
+		defer validatorsMu.Unlock()
+		delete(validators, "test-nhs")
+	})
+	ctx := context.Background()
+	id, err := Validate(ctx, &apiv1.Identifier{System: "test-nhs", Value: "632 879 7966"})
+	if err != nil || id.GetValue() != "6328797966" {
+		t.Fatalf("expected canonical NHS number, got %v (%v)", id, err)
+	}
+	if _, err := Validate(ctx, &apiv1.Identifier{System: "test-nhs", Value: "6328797967"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if result := Check(ctx, &apiv1.Identifier{System: "test-other", Value: "anything"}); !result.GetValid() || result.GetChecked() {
+		t.Fatalf("expected identifier without validator to be valid but unchecked: %v", result)
+	}
+	i18n.Register(i18n.Welsh, map[string]string{"incorrect check digit": "digid gwirio anghywir"})
+	cy := metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", "cy"))
+	if result := Check(cy, &apiv1.Identifier{System: "test-nhs", Value: "6328797967"}); result.GetValid() || result.GetReason() != "digid gwirio anghywir" {
+		t.Fatalf("expected reason in Welsh: %v", result)
+	}
+}
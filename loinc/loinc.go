@@ -0,0 +1,202 @@
+// Package loinc provides resolution of LOINC (Logical Observation Identifiers Names and Codes) codes
+// using a local copy of the LOINC table, imported from the LOINC CSV release (LoincTable/Loinc.csv)
+// into an embedded key-value store. See https://loinc.org/downloads/loinc-table/
+package loinc
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/identifiers/validate"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var bucket = []byte("loinc")
+
+func init() {
+	validate.Register(identifiers.LOINC, func(code string) (string, error) {
+		if code = strings.TrimSpace(code); !Valid(code) {
+			return "", validate.ErrInvalidFormat
+		}
+		return code, nil
+	})
+}
+
+// importBatchSize is the number of codes written in a single transaction during import
+const importBatchSize = 10000
+
+// Store provides lookup of LOINC codes from a local copy of the LOINC table. This is thread-safe.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the store in the file specified, creating it unless opened read-only.
+// A store opened read-only may be shared by multiple processes, but cannot be imported into.
+func Open(filename string, readOnly bool) (*Store, error) {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("loinc: could not open store '%s': %w", filename, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the store
+func (st *Store) Close() error {
+	return st.db.Close()
+}
+
+// Import imports the LOINC table from the CSV release, replacing any existing codes with the same
+// LOINC number, returning the number of codes imported. Columns are identified using the header row.
+func (st *Store) Import(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\ufeff" {
+		br.Discard(3) // the LOINC table may be saved with a UTF-8 byte order mark
+	}
+	cr := csv.NewReader(br)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return 0, fmt.Errorf("loinc: could not read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"LOINC_NUM", "LONG_COMMON_NAME", "PROPERTY", "STATUS"} {
+		if _, ok := columns[required]; !ok {
+			return 0, fmt.Errorf("loinc: invalid LOINC table: missing column %s", required)
+		}
+	}
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	count := 0
+	batch := make([]*apiv1.LoincCode, 0, importBatchSize)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("loinc: could not read LOINC table: %w", err)
+		}
+		batch = append(batch, &apiv1.LoincCode{
+			Code:           column(record, "LOINC_NUM"),
+			LongCommonName: column(record, "LONG_COMMON_NAME"),
+			Component:      column(record, "COMPONENT"),
+			Property:       column(record, "PROPERTY"),
+			TimeAspect:     column(record, "TIME_ASPCT"),
+			System:         column(record, "SYSTEM"),
+			Scale:          column(record, "SCALE_TYP"),
+			Method:         column(record, "METHOD_TYP"),
+			Class:          column(record, "CLASS"),
+			Status:         column(record, "STATUS"),
+		})
+		if len(batch) == importBatchSize {
+			if err := st.put(batch); err != nil {
+				return count, err
+			}
+			count += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := st.put(batch); err != nil {
+		return count, err
+	}
+	return count + len(batch), nil
+}
+
+// put writes the codes specified in a single transaction
+func (st *Store) put(items []*apiv1.LoincCode) error {
+	return st.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return err
+		}
+		for _, code := range items {
+			data, err := proto.Marshal(code)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(code.GetCode()), data); err != nil {
+				return fmt.Errorf("loinc: could not store '%s': %w", code.GetCode(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// Get returns the LOINC code specified, or nil if not found
+func (st *Store) Get(code string) (*apiv1.LoincCode, error) {
+	var result *apiv1.LoincCode
+	err := st.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(code))
+		if data == nil {
+			return nil
+		}
+		result = new(apiv1.LoincCode)
+		return proto.Unmarshal(data, result)
+	})
+	return result, err
+}
+
+// Resolve provides a resolution service for LOINC codes
+func (st *Store) Resolve(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	code := strings.TrimSpace(id.GetValue())
+	if !Valid(code) {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "invalid LOINC code: %s", id.GetValue())
+	}
+	result, err := st.Get(code)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "loinc: could not fetch '%s': %s", code, err)
+	}
+	if result == nil {
+		return nil, status.Errorf(codes.NotFound, "loinc: code not found: %s", code)
+	}
+	return result, nil
+}
+
+// Valid determines whether the code is a syntactically valid LOINC code, including its check digit,
+// which is calculated using the Mod 10 algorithm. e.g. "2160-0"
+func Valid(code string) bool {
+	i := strings.IndexByte(code, '-')
+	if i < 1 || i != len(code)-2 {
+		return false
+	}
+	sum := 0
+	double := true
+	for j := i - 1; j >= 0; j-- {
+		d := int(code[j] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	check := int(code[i+1] - '0')
+	return check == (10-sum%10)%10
+}
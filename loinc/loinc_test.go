@@ -0,0 +1,76 @@
+package loinc
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const table = "\ufeff" + `"LOINC_NUM","COMPONENT","PROPERTY","TIME_ASPCT","SYSTEM","SCALE_TYP","METHOD_TYP","CLASS","VersionLastChanged","CHNG_TYPE","STATUS","LONG_COMMON_NAME"
+"2160-0","Creatinine","MCnc","Pt","Ser/Plas","Qn","","CHEM","2.68","MIN","ACTIVE","Creatinine [Mass/volume] in Serum or Plasma"
+"2345-7","Glucose","MCnc","Pt","Ser/Plas","Qn","","CHEM","2.68","MIN","ACTIVE","Glucose [Mass/volume] in Serum or Plasma"
+`
+
+func TestValid(t *testing.T) {
+	for code, valid := range map[string]bool{
+		"2160-0":  true,
+		"2345-7":  true,
+		"10331-7": true,
+		"2345-8":  false,
+		"2345":    false,
+		"-7":      false,
+		"23a5-7":  false,
+		"2345-77": false,
+	} {
+		if Valid(code) != valid {
+			t.Errorf("%s: expected valid=%v", code, valid)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "loinc.db")
+	st, err := Open(filename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := st.Import(strings.NewReader(table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 codes imported, got %d", count)
+	}
+	if _, err := st.Import(strings.NewReader("LOINC_NUM,COMPONENT\n")); err == nil {
+		t.Error("expected error importing invalid LOINC table")
+	}
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+	st, err = Open(filename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	ctx := context.Background()
+	result, err := st.Resolve(ctx, &apiv1.Identifier{System: identifiers.LOINC, Value: "2160-0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := result.(*apiv1.LoincCode)
+	if code.GetLongCommonName() != "Creatinine [Mass/volume] in Serum or Plasma" || code.GetProperty() != "MCnc" || code.GetStatus() != "ACTIVE" || code.GetSystem() != "Ser/Plas" {
+		t.Errorf("unexpected result: %v", code)
+	}
+	if _, err := st.Resolve(ctx, &apiv1.Identifier{System: identifiers.LOINC, Value: "10331-7"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found, got: %v", err)
+	}
+	if _, err := st.Resolve(ctx, &apiv1.Identifier{System: identifiers.LOINC, Value: "2160-1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument, got: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Package maintenance permits backend services to be placed into maintenance at runtime, such as during
+// planned downtime of a patient administration system, so that requests fail fast with a clear status
+// rather than timing out.
+//
+// A backend may be read-only, in which case reads are permitted but writes, such as publishing documents,
+// are rejected, or offline, in which case all requests are rejected. Backends check their own status
+// using CheckRead and CheckWrite, using the same name as used for their health check.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	mu       sync.RWMutex
+	backends = make(map[string]*apiv1.BackendMaintenance)
+)
+
+// Set sets the maintenance mode of the named backend, with an optional reason reported to clients
+func Set(backend string, mode apiv1.BackendMaintenance_Mode, reason string) *apiv1.BackendMaintenance {
+	mu.Lock()
+	defer mu.Unlock()
+	if mode == apiv1.BackendMaintenance_AVAILABLE {
+		delete(backends, backend)
+		log.Printf("maintenance: '%s' available", backend)
+		return &apiv1.BackendMaintenance{Backend: backend, Mode: mode}
+	}
+	m := &apiv1.BackendMaintenance{Backend: backend, Mode: mode, Reason: reason, Since: timestamppb.Now()}
+	if current, ok := backends[backend]; ok {
+		m.Since = current.GetSince()
+	}
+	backends[backend] = m
+	log.Printf("maintenance: '%s' %s: %s", backend, mode, reason)
+	return proto.Clone(m).(*apiv1.BackendMaintenance)
+}
+
+// List returns the backends currently in maintenance, sorted by name
+func List() []*apiv1.BackendMaintenance {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := make([]*apiv1.BackendMaintenance, 0, len(backends))
+	for _, m := range backends {
+		result = append(result, proto.Clone(m).(*apiv1.BackendMaintenance))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GetBackend() < result[j].GetBackend() })
+	return result
+}
+
+// Get returns the maintenance status of the named backend, or nil if it is available
+func Get(backend string) *apiv1.BackendMaintenance {
+	mu.RLock()
+	defer mu.RUnlock()
+	if m, ok := backends[backend]; ok {
+		return proto.Clone(m).(*apiv1.BackendMaintenance)
+	}
+	return nil
+}
+
+// CheckRead returns an error if the named backend is offline
+func CheckRead(ctx context.Context, backend string) error {
+	return check(ctx, backend, apiv1.BackendMaintenance_OFFLINE)
+}
+
+// CheckWrite returns an error if the named backend is offline or read-only
+func CheckWrite(ctx context.Context, backend string) error {
+	return check(ctx, backend, apiv1.BackendMaintenance_READ_ONLY)
+}
+
+// check returns an error if the named backend is in the maintenance mode specified, or a more restrictive mode
+func check(ctx context.Context, backend string, mode apiv1.BackendMaintenance_Mode) error {
+	mu.RLock()
+	m, ok := backends[backend]
+	mu.RUnlock()
+	if !ok || m.GetMode() < mode {
+		return nil
+	}
+	if m.GetReason() == "" {
+		return i18n.Errorf(ctx, codes.Unavailable, "backend in maintenance: %s", backend)
+	}
+	return i18n.Errorf(ctx, codes.Unavailable, "backend in maintenance: %s (%s)", backend, m.GetReason())
+}
+
+// Parse parses a maintenance specification in the form backend=mode or backend=mode:reason,
+// with mode one of available, read-only or offline, e.g. "cav-pms=read-only:PAS upgrade"
+func Parse(s string) (*apiv1.BackendMaintenance, error) {
+	i := strings.IndexByte(s, '=')
+	if i < 1 {
+		return nil, fmt.Errorf("maintenance: invalid specification '%s': expected backend=mode[:reason]", s)
+	}
+	m := &apiv1.BackendMaintenance{Backend: strings.TrimSpace(s[:i])}
+	mode := s[i+1:]
+	if j := strings.IndexByte(mode, ':'); j >= 0 {
+		mode, m.Reason = mode[:j], strings.TrimSpace(mode[j+1:])
+	}
+	value, ok := apiv1.BackendMaintenance_Mode_value[strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(mode)), "-", "_")]
+	if !ok {
+		return nil, fmt.Errorf("maintenance: invalid mode '%s' for '%s': expected available, read-only or offline", mode, m.Backend)
+	}
+	m.Mode = apiv1.BackendMaintenance_Mode(value)
+	return m, nil
+}
+
+// Server provides the maintenance service, permitting backends to be placed into maintenance at runtime
+type Server struct{}
+
+var _ apiv1.MaintenanceServer = (*Server)(nil)
+
+// RegisterServer registers this server
+func (svc *Server) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterMaintenanceServer(s, svc)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (svc *Server) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterMaintenanceHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (svc *Server) Close() error { return nil }
+
+// SetMaintenance sets the maintenance mode of a backend
+func (svc *Server) SetMaintenance(ctx context.Context, r *apiv1.BackendMaintenance) (*apiv1.BackendMaintenance, error) {
+	if r.GetBackend() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "maintenance: missing parameter: backend")
+	}
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("maintenance: '%s|%s' setting '%s' %s", user.GetSystem(), user.GetValue(), r.GetBackend(), r.GetMode())
+	return Set(r.GetBackend(), r.GetMode(), r.GetReason()), nil
+}
+
+// ListMaintenance returns the backends currently in maintenance
+func (svc *Server) ListMaintenance(ctx context.Context, r *apiv1.ListMaintenanceRequest) (*apiv1.ListMaintenanceResponse, error) {
+	return &apiv1.ListMaintenanceResponse{Backends: List()}, nil
+}
@@ -0,0 +1,74 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMaintenance(t *testing.T) {
+	ctx := context.Background()
+	defer Set("test", apiv1.BackendMaintenance_AVAILABLE, "")
+	if err := CheckWrite(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	Set("test", apiv1.BackendMaintenance_READ_ONLY, "upgrade")
+	if err := CheckRead(ctx, "test"); err != nil {
+		t.Errorf("expected reads to be permitted when read-only, got: %v", err)
+	}
+	if err := CheckWrite(ctx, "test"); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected writes to be unavailable when read-only, got: %v", err)
+	}
+	if err := CheckWrite(ctx, "other"); err != nil {
+		t.Errorf("expected other backends to be unaffected, got: %v", err)
+	}
+	since := List()[0].GetSince()
+	Set("test", apiv1.BackendMaintenance_OFFLINE, "upgrade")
+	if err := CheckRead(ctx, "test"); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected reads to be unavailable when offline, got: %v", err)
+	}
+	backends := List()
+	if len(backends) != 1 || backends[0].GetMode() != apiv1.BackendMaintenance_OFFLINE || !proto.Equal(backends[0].GetSince(), since) {
+		t.Errorf("unexpected backends in maintenance: %v", backends)
+	}
+	svc := &Server{}
+	if _, err := svc.SetMaintenance(ctx, &apiv1.BackendMaintenance{Backend: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckWrite(ctx, "test"); err != nil {
+		t.Errorf("expected backend to be available, got: %v", err)
+	}
+	if r, err := svc.ListMaintenance(ctx, &apiv1.ListMaintenanceRequest{}); err != nil || len(r.GetBackends()) != 0 {
+		t.Errorf("expected no backends in maintenance, got: %v (%v)", r, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec   string
+		valid  bool
+		mode   apiv1.BackendMaintenance_Mode
+		reason string
+	}{
+		{"cav=read-only", true, apiv1.BackendMaintenance_READ_ONLY, ""},
+		{"cav=offline:PAS upgrade: until 18:00", true, apiv1.BackendMaintenance_OFFLINE, "PAS upgrade: until 18:00"},
+		{"cav=available", true, apiv1.BackendMaintenance_AVAILABLE, ""},
+		{"cav=wibble", false, 0, ""},
+		{"=offline", false, 0, ""},
+		{"cav", false, 0, ""},
+	}
+	for _, test := range tests {
+		m, err := Parse(test.spec)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid=%v, got: %v", test.spec, test.valid, err)
+			continue
+		}
+		if err == nil && (m.GetBackend() != "cav" || m.GetMode() != test.mode || m.GetReason() != test.reason) {
+			t.Errorf("%s: unexpected result: %v", test.spec, m)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package metrics provides prometheus instrumentation of calls to backend services, so that
+// degraded upstream services can be identified and alerted upon.
+//
+// Metrics are exposed in the prometheus text format using Handler(), usually at /metrics.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "concierge"
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "backend_requests_total",
+		Help:      "Number of requests made to backend services, by backend, operation and result code.",
+	}, []string{"backend", "operation", "code"})
+
+	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "backend_request_duration_seconds",
+		Help:      "Latency of requests made to backend services, by backend and operation.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"backend", "operation"})
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "endpoint_requests_total",
+		Help:      "Number of HTTP requests made to backend endpoints, including retries, by endpoint and HTTP status code.",
+	}, []string{"endpoint", "code"})
+
+	httpLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "endpoint_request_duration_seconds",
+		Help:      "Latency of HTTP requests made to backend endpoints, including retries, by endpoint.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"endpoint"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Number of cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	soapFaults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "soap_faults_total",
+		Help:      "Number of SOAP faults returned by backend endpoints.",
+	}, []string{"endpoint"})
+
+	breakerOpens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_opens_total",
+		Help:      "Number of times the circuit breaker for a backend endpoint has opened.",
+	}, []string{"endpoint"})
+
+	breakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "State of the circuit breaker for a backend endpoint: 0 closed, 1 open, 2 half-open.",
+	}, []string{"endpoint"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_logins_total",
+		Help:      "Number of login attempts, by identifier system and result (success, failure, error or locked).",
+	}, []string{"system", "result"})
+
+	lockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_lockouts_total",
+		Help:      "Number of times logins were delayed after repeated failures, by scope (user or address).",
+	}, []string{"scope"})
+
+	deduplicated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deduplicated_requests_total",
+		Help:      "Number of requests to backend services that shared the result of an identical request already in flight, by backend.",
+	}, []string{"backend"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Number of calls rejected by the rate limiter, by method.",
+	}, []string{"method"})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Number of runs of scheduled jobs, by job and result code.",
+	}, []string{"job", "code"})
+
+	jobDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_run_duration_seconds",
+		Help:      "Duration of the most recent run of a scheduled job, by job.",
+	}, []string{"job"})
+
+	jobLastRun = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_run_timestamp_seconds",
+		Help:      "Time of completion of the most recent run of a scheduled job, by job and result code.",
+	}, []string{"job", "code"})
+
+	jobItems = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_items_total",
+		Help:      "Number of items processed by scheduled jobs, by job and result (success or failure).",
+	}, []string{"job", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(requests, latency, httpRequests, httpLatency, cacheLookups, soapFaults, breakerOpens, breakerState, logins, lockouts, deduplicated, rateLimited, jobRuns, jobDuration, jobLastRun, jobItems)
+}
+
+// Handler returns a HTTP handler that exposes the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Observe records the result and latency of an operation performed by the named backend service,
+// started at the time specified. Use with defer, passing a pointer to the named error result:
+//
+//	defer metrics.Observe("empi", "fetch", time.Now(), &err)
+func Observe(backend string, operation string, start time.Time, err *error) {
+	code := codes.OK
+	if err != nil && *err != nil {
+		code = status.Code(*err)
+	}
+	requests.WithLabelValues(backend, operation, code.String()).Inc()
+	latency.WithLabelValues(backend, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveHTTP records the result and latency of a single HTTP request to a backend endpoint.
+// A status code of zero denotes that no response was received.
+func ObserveHTTP(endpoint string, statusCode int, duration time.Duration) {
+	code := "error"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	httpRequests.WithLabelValues(endpoint, code).Inc()
+	httpLatency.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// CacheLookup records a hit or miss for the named cache
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// SOAPFault records a SOAP fault returned by a backend endpoint
+func SOAPFault(endpoint string) {
+	soapFaults.WithLabelValues(endpoint).Inc()
+}
+
+// BreakerState records a change in the state of the circuit breaker for an endpoint, counting the
+// number of times it opens. State is 0 closed, 1 open and 2 half-open.
+func BreakerState(endpoint string, state int, opened bool) {
+	breakerState.WithLabelValues(endpoint).Set(float64(state))
+	if opened {
+		breakerOpens.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// Login records the result of a login attempt for an identifier system; result should be one of
+// "success", "failure", "error" or "locked"
+func Login(system string, result string) {
+	logins.WithLabelValues(system, result).Inc()
+}
+
+// Lockout records that logins for a user, or from a network address, are delayed after repeated failures;
+// scope should be one of "user" or "address"
+func Lockout(scope string) {
+	lockouts.WithLabelValues(scope).Inc()
+}
+
+// Deduplicated records a request to the named backend that shared the result of an identical request
+func Deduplicated(backend string) {
+	deduplicated.WithLabelValues(backend).Inc()
+}
+
+// RateLimited records a call to the method specified rejected by the rate limiter
+func RateLimited(method string) {
+	rateLimited.WithLabelValues(method).Inc()
+}
+
+// JobRun records the result and duration of a run of a scheduled job, and the number of items processed,
+// including those that failed
+func JobRun(job string, err error, duration time.Duration, processed int, failed int) {
+	code := status.Code(err).String()
+	jobRuns.WithLabelValues(job, code).Inc()
+	jobDuration.WithLabelValues(job).Set(duration.Seconds())
+	jobLastRun.WithLabelValues(job, code).SetToCurrentTime()
+	jobItems.WithLabelValues(job, "success").Add(float64(processed - failed))
+	jobItems.WithLabelValues(job, "failure").Add(float64(failed))
+}
+
+// UnaryClientInterceptor returns a gRPC client interceptor recording the result and latency of unary
+// calls made to the named backend service, using the method name as the operation.
+func UnaryClientInterceptor(backend string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
+		defer Observe(backend, path.Base(method), time.Now(), &err)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC client interceptor recording the result and latency of
+// establishing streaming calls made to the named backend service, using the method name as the operation.
+func StreamClientInterceptor(backend string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (cs grpc.ClientStream, err error) {
+		defer Observe(backend, path.Base(method), time.Now(), &err)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
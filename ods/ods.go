@@ -0,0 +1,338 @@
+// Package ods provides organisation lookup using the NHS Digital Organisation Data Service (ODS)
+// ORD API, resolving ODS codes for organisations and sites to apiv1.Organisation.
+// See https://digital.nhs.uk/services/organisation-data-service/guidance-for-developers/organisation-endpoint
+//
+// Organisations are cached locally, and cached organisations are periodically refreshed so that
+// changes, such as closure, are reflected without waiting for the cache to expire.
+package ods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultURL is the URL of the ODS ORD API
+const DefaultURL = "https://directory.spineservices.nhs.uk/ORD/2-0-0"
+
+// DefaultRefreshInterval is the default interval at which cached organisations are refreshed
+const DefaultRefreshInterval = 24 * time.Hour
+
+// App provides organisation lookup using the ODS ORD API. This is thread-safe.
+type App struct {
+	url    string
+	fake   bool
+	client *http.Client
+
+	mu    sync.RWMutex
+	orgs  map[string]*apiv1.Organisation // ODS code -> organisation
+	roles map[string]string              // role code -> role name
+	done  chan struct{}
+}
+
+// New creates a new ODS lookup service using the API at the URL specified, refreshing cached
+// organisations at the interval specified
+func New(url string, refresh time.Duration, fake bool) *App {
+	if url == "" {
+		url = DefaultURL
+	}
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	app := &App{
+		url:    strings.TrimSuffix(url, "/"),
+		fake:   fake,
+		client: transport.NewClient("ods", nil, true),
+		orgs:   make(map[string]*apiv1.Organisation),
+		done:   make(chan struct{}),
+	}
+	go app.refreshEvery(refresh)
+	return app
+}
+
+// Close stops the periodic refresh of cached organisations
+func (app *App) Close() error {
+	close(app.done)
+	return nil
+}
+
+// ResolveIdentifier provides identifier resolution for ODS codes (see identifiers.ODSCode and identifiers.ODSSiteCode)
+func (app *App) ResolveIdentifier(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return app.GetOrganisation(ctx, id)
+}
+
+// GetOrganisation returns the organisation or site with the ODS code specified
+func (app *App) GetOrganisation(ctx context.Context, id *apiv1.Identifier) (org *apiv1.Organisation, err error) {
+	if id.GetSystem() != identifiers.ODSCode && id.GetSystem() != identifiers.ODSSiteCode {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported identifier system: %s. supported: %s, %s", id.GetSystem(), identifiers.ODSCode, identifiers.ODSSiteCode)
+	}
+	code := strings.ToUpper(strings.TrimSpace(id.GetValue()))
+	if code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no ODS code specified")
+	}
+	app.mu.RLock()
+	org, found := app.orgs[code]
+	app.mu.RUnlock()
+	metrics.CacheLookup("ods", found)
+	if found {
+		return org, nil
+	}
+	if org, err = app.fetch(ctx, code); err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "organisation not found: %s|%s", id.GetSystem(), code)
+	}
+	app.mu.Lock()
+	app.orgs[code] = org
+	app.mu.Unlock()
+	return org, nil
+}
+
+// refreshEvery refreshes cached organisations at the interval specified, until closed
+func (app *App) refreshEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-app.done:
+			return
+		case <-ticker.C:
+			app.refresh(context.Background())
+		}
+	}
+}
+
+// refresh refreshes all cached organisations, removing those no longer known to ODS.
+// Organisations that cannot be fetched because of an error are left unchanged.
+func (app *App) refresh(ctx context.Context) {
+	app.mu.RLock()
+	cached := make([]string, 0, len(app.orgs))
+	for code := range app.orgs {
+		cached = append(cached, code)
+	}
+	app.mu.RUnlock()
+	refreshed, removed := 0, 0
+	for _, code := range cached {
+		org, err := app.fetch(ctx, code)
+		if err != nil {
+			log.Printf("ods: failed to refresh %s: %s", code, err)
+			continue
+		}
+		app.mu.Lock()
+		if org == nil {
+			delete(app.orgs, code)
+			removed++
+		} else {
+			app.orgs[code] = org
+			refreshed++
+		}
+		app.mu.Unlock()
+	}
+	log.Printf("ods: refreshed %d cached organisations (%d removed)", refreshed, removed)
+}
+
+// fetch fetches the organisation from the ODS API, returning nil if not found
+func (app *App) fetch(ctx context.Context, code string) (org *apiv1.Organisation, err error) {
+	defer metrics.Observe("ods", "fetch", time.Now(), &err)
+	if app.fake {
+		return fakeOrganisation(code), nil
+	}
+	var result struct {
+		Organisation *organisation `json:"Organisation"`
+	}
+	found, err := app.get(ctx, "/organisations/"+url.PathEscape(code), &result)
+	if err != nil || !found || result.Organisation == nil {
+		return nil, err
+	}
+	roles, err := app.roleNames(ctx)
+	if err != nil {
+		log.Printf("ods: failed to fetch role names: %s", err) // not fatal, as roles have codes
+	}
+	return result.Organisation.toOrganisation(roles), nil
+}
+
+// roleNames returns the names of organisation roles, fetching them if necessary
+func (app *App) roleNames(ctx context.Context) (map[string]string, error) {
+	app.mu.RLock()
+	roles := app.roles
+	app.mu.RUnlock()
+	if roles != nil {
+		return roles, nil
+	}
+	var result struct {
+		Roles []struct {
+			Code        string `json:"code"`
+			DisplayName string `json:"displayName"`
+		} `json:"Roles"`
+	}
+	if _, err := app.get(ctx, "/roles", &result); err != nil {
+		return nil, err
+	}
+	roles = make(map[string]string, len(result.Roles))
+	for _, role := range result.Roles {
+		roles[role.Code] = role.DisplayName
+	}
+	app.mu.Lock()
+	app.roles = roles
+	app.mu.Unlock()
+	return roles, nil
+}
+
+// get performs a GET request to the ODS API, decoding the result. It returns false if the resource does not exist.
+func (app *App) get(ctx context.Context, path string, result interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.url+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := app.client.Do(req)
+	if err != nil {
+		return false, status.Errorf(codes.Unavailable, "ods: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, status.Errorf(codes.Unavailable, "ods: unexpected response (%d): %s", resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return false, fmt.Errorf("ods: invalid response: %w", err)
+	}
+	return true, nil
+}
+
+// organisation is the subset of an ORD organisation used by concierge
+type organisation struct {
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+	Date   []date `json:"Date"`
+	OrgID  struct {
+		Extension string `json:"extension"`
+	} `json:"OrgId"`
+	GeoLoc struct {
+		Location struct {
+			AddrLn1  string `json:"AddrLn1"`
+			AddrLn2  string `json:"AddrLn2"`
+			AddrLn3  string `json:"AddrLn3"`
+			Town     string `json:"Town"`
+			County   string `json:"County"`
+			PostCode string `json:"PostCode"`
+			Country  string `json:"Country"`
+		} `json:"Location"`
+	} `json:"GeoLoc"`
+	Contacts struct {
+		Contact []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"Contact"`
+	} `json:"Contacts"`
+	Roles struct {
+		Role []struct {
+			ID          string `json:"id"`
+			PrimaryRole bool   `json:"primaryRole"`
+			Status      string `json:"Status"`
+			Date        []date `json:"Date"`
+		} `json:"Role"`
+	} `json:"Roles"`
+}
+
+type date struct {
+	Type  string `json:"Type"`
+	Start string `json:"Start"`
+	End   string `json:"End"`
+}
+
+// operational returns the operational period from the dates specified
+func operational(dates []date) *apiv1.Period {
+	for _, d := range dates {
+		if d.Type != "Operational" {
+			continue
+		}
+		p := &apiv1.Period{}
+		if t, err := time.Parse("2006-01-02", d.Start); err == nil {
+			p.Start = timestamppb.New(t)
+		}
+		if t, err := time.Parse("2006-01-02", d.End); err == nil {
+			p.End = timestamppb.New(t)
+		}
+		return p
+	}
+	return nil
+}
+
+// toOrganisation converts an ORD organisation to a concierge organisation, using the role names specified
+func (o *organisation) toOrganisation(roles map[string]string) *apiv1.Organisation {
+	loc := o.GeoLoc.Location
+	address3 := make([]string, 0)
+	for _, s := range []string{loc.AddrLn3, loc.Town, loc.County} {
+		if s != "" {
+			address3 = append(address3, s)
+		}
+	}
+	org := &apiv1.Organisation{
+		Identifiers: []*apiv1.Identifier{{System: identifiers.ODSCode, Value: o.OrgID.Extension}},
+		Name:        o.Name,
+		Active:      o.Status == "Active",
+		Period:      operational(o.Date),
+		Addresses: []*apiv1.Address{{
+			Address1: loc.AddrLn1,
+			Address2: loc.AddrLn2,
+			Address3: strings.Join(address3, ", "),
+			Postcode: loc.PostCode,
+			Country:  loc.Country,
+		}},
+	}
+	for _, c := range o.Contacts.Contact {
+		if c.Type == "tel" {
+			org.Telephones = append(org.Telephones, &apiv1.Telephone{Number: c.Value})
+		}
+	}
+	for _, r := range o.Roles.Role {
+		org.Roles = append(org.Roles, &apiv1.OrganisationRole{
+			Identifier: &apiv1.Identifier{System: identifiers.ODSOrganisationRole, Value: r.ID},
+			Name:       roles[r.ID],
+			Primary:    r.PrimaryRole,
+			Active:     r.Status == "Active",
+			Period:     operational(r.Date),
+		})
+	}
+	return org
+}
+
+// fakeOrganisation returns a fake organisation, useful in testing without a live backend service
+func fakeOrganisation(code string) *apiv1.Organisation {
+	return &apiv1.Organisation{
+		Identifiers: []*apiv1.Identifier{{System: identifiers.ODSCode, Value: code}},
+		Name:        "CASTLE GATE MEDICAL PRACTICE",
+		Active:      true,
+		Addresses:   []*apiv1.Address{{Address1: "CASTLE GATE", Address3: "MONMOUTH", Postcode: "NP25 3AB", Country: "WALES"}},
+		Roles: []*apiv1.OrganisationRole{
+			{Identifier: &apiv1.Identifier{System: identifiers.ODSOrganisationRole, Value: "RO177"}, Name: "PRESCRIBING COST CENTRE", Primary: true, Active: true},
+			{Identifier: &apiv1.Identifier{System: identifiers.ODSOrganisationRole, Value: "RO76"}, Name: "GP PRACTICE", Active: true},
+		},
+	}
+}
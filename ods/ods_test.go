@@ -0,0 +1,78 @@
+package ods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testOrganisation = `{"Organisation": {
+  "Name": "CASTLE GATE MEDICAL PRACTICE",
+  "Date": [{"Type": "Operational", "Start": "1974-04-01"}],
+  "OrgId": {"root": "2.16.840.1.113883.2.1.3.2.4.18.48", "assigningAuthorityName": "HSCIC", "extension": "W93036"},
+  "Status": "%s",
+  "GeoLoc": {"Location": {"AddrLn1": "CASTLE GATE", "Town": "MONMOUTH", "County": "GWENT", "PostCode": "NP25 3AB", "Country": "WALES"}},
+  "Contacts": {"Contact": [{"type": "tel", "value": "01600 713811"}]},
+  "Roles": {"Role": [
+    {"id": "RO177", "primaryRole": true, "Date": [{"Type": "Operational", "Start": "1974-04-01"}], "Status": "Active"},
+    {"id": "RO76", "Date": [{"Type": "Operational", "Start": "2014-04-15"}], "Status": "Active"}
+  ]}
+}}`
+
+func TestGetOrganisation(t *testing.T) {
+	var fetches int32
+	var active int32 = 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/roles":
+			fmt.Fprint(w, `{"Roles": [{"code": "RO177", "displayName": "PRESCRIBING COST CENTRE"}, {"code": "RO76", "displayName": "GP PRACTICE"}]}`)
+		case "/organisations/W93036":
+			atomic.AddInt32(&fetches, 1)
+			status := "Inactive"
+			if atomic.LoadInt32(&active) == 1 {
+				status = "Active"
+			}
+			fmt.Fprintf(w, testOrganisation, status)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	app := New(ts.URL, 0, false)
+	defer app.Close()
+	for i := 0; i < 2; i++ {
+		org, err := app.GetOrganisation(context.Background(), &apiv1.Identifier{System: identifiers.ODSCode, Value: "w93036"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if org.GetName() != "CASTLE GATE MEDICAL PRACTICE" || !org.GetActive() || org.GetAddresses()[0].GetAddress3() != "MONMOUTH, GWENT" || len(org.GetTelephones()) != 1 {
+			t.Fatalf("unexpected organisation: %v", org)
+		}
+		if len(org.GetRoles()) != 2 || org.GetRoles()[1].GetName() != "GP PRACTICE" || !org.GetRoles()[0].GetPrimary() {
+			t.Fatalf("unexpected roles: %v", org.GetRoles())
+		}
+	}
+	if atomic.LoadInt32(&fetches) != 1 {
+		t.Fatalf("expected organisation to be cached, but fetched %d times", fetches)
+	}
+	atomic.StoreInt32(&active, 0)
+	app.refresh(context.Background())
+	org, err := app.GetOrganisation(context.Background(), &apiv1.Identifier{System: identifiers.ODSCode, Value: "W93036"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if org.GetActive() || atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("expected refreshed organisation to be inactive, got %v after %d fetches", org, fetches)
+	}
+	if _, err := app.GetOrganisation(context.Background(), &apiv1.Identifier{System: identifiers.ODSSiteCode, Value: "X99999"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package paging provides the conventions for paginated list and stream endpoints.
+//
+// A paginated request has a page_size, the maximum number of results to return, and a page_token,
+// returned by a previous request, to fetch the next page. A page size of zero returns all remaining
+// results, so that clients not using pagination are unaffected. Results must be returned in a
+// consistent order, so that a client paging through results sees each result once.
+//
+// A page token is opaque to clients. It records the offset of the next page, and a fingerprint of
+// the request, so that a token cannot be used with a request with different parameters.
+package paging
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Request is a paginated request
+type Request interface {
+	proto.Message
+	GetPageSize() int32
+	GetPageToken() string
+}
+
+// TrailerKey is the key of the trailer metadata used by streaming endpoints to return the token for the next page
+const TrailerKey = "next-page-token"
+
+// Page is the page of results requested
+type Page struct {
+	Offset      int    // offset of the first result
+	Size        int    // maximum results, or zero for all remaining results
+	fingerprint uint64 // fingerprint of the request
+}
+
+// Parse returns the page requested, returning an error if the page size or token is invalid
+func Parse(ctx context.Context, r Request) (Page, error) {
+	if r.GetPageSize() < 0 {
+		return Page{}, i18n.Errorf(ctx, codes.InvalidArgument, "invalid page size: %d", r.GetPageSize())
+	}
+	p := Page{Size: int(r.GetPageSize()), fingerprint: fingerprint(r)}
+	if r.GetPageToken() == "" {
+		return p, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(r.GetPageToken())
+	if err != nil {
+		return Page{}, i18n.Errorf(ctx, codes.InvalidArgument, "invalid page token")
+	}
+	parts := strings.SplitN(string(b), ".", 2)
+	if len(parts) != 2 {
+		return Page{}, i18n.Errorf(ctx, codes.InvalidArgument, "invalid page token")
+	}
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return Page{}, i18n.Errorf(ctx, codes.InvalidArgument, "invalid page token")
+	}
+	if parts[1] != strconv.FormatUint(p.fingerprint, 36) {
+		return Page{}, i18n.Errorf(ctx, codes.InvalidArgument, "page token does not match request")
+	}
+	p.Offset = offset
+	return p, nil
+}
+
+// Bounds returns the bounds of this page within n results, for use in slicing the results
+func (p Page) Bounds(n int) (start, end int) {
+	start, end = p.Offset, n
+	if start > n {
+		start = n
+	}
+	if p.Size > 0 && start+p.Size < end {
+		end = start + p.Size
+	}
+	return start, end
+}
+
+// NextToken returns the token for the page following this page within n results, or an empty
+// string if there are no more results
+func (p Page) NextToken(n int) string {
+	_, end := p.Bounds(n)
+	if end >= n {
+		return ""
+	}
+	return p.token(end)
+}
+
+// token returns the token for the page starting at the offset specified
+func (p Page) token(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%s", offset, strconv.FormatUint(p.fingerprint, 36))))
+}
+
+// Stream limits the results sent to a stream to a single page
+type Stream struct {
+	Page
+	n int // results seen
+}
+
+// Include returns whether the next result in the stream is within the page, and whether
+// the page is complete, such that no further results need be sent.
+func (s *Stream) Include() (include bool, done bool) {
+	i := s.n
+	s.n++
+	if i < s.Offset {
+		return false, false
+	}
+	if s.Size > 0 && i >= s.Offset+s.Size {
+		return false, true
+	}
+	return true, false
+}
+
+// NextToken returns the token for the next page, if the stream had more results than the page
+func (s *Stream) NextToken() string {
+	if s.Size > 0 && s.n > s.Offset+s.Size {
+		return s.token(s.Offset + s.Size)
+	}
+	return ""
+}
+
+// fingerprint returns a hash of the request, excluding the page size and token
+func fingerprint(r Request) uint64 {
+	m := proto.Clone(r)
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for _, name := range []protoreflect.Name{"page_size", "page_token"} {
+		if fd := fields.ByName(name); fd != nil {
+			m.ProtoReflect().Clear(fd)
+		}
+	}
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
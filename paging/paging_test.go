@@ -0,0 +1,97 @@
+package paging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPages(t *testing.T) {
+	ctx := context.Background()
+	r := &apiv1.ListPendingDocumentsRequest{PageSize: 3}
+	var seen int
+	for pages := 0; ; pages++ {
+		if pages > 4 {
+			t.Fatal("too many pages")
+		}
+		page, err := Parse(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, end := page.Bounds(10)
+		if start != seen {
+			t.Fatalf("expected page to start at %d, got %d", seen, start)
+		}
+		seen = end
+		if r.PageToken = page.NextToken(10); r.PageToken == "" {
+			break
+		}
+	}
+	if seen != 10 {
+		t.Fatalf("expected 10 results, got %d", seen)
+	}
+}
+
+func TestAll(t *testing.T) {
+	page, err := Parse(context.Background(), &apiv1.ListPendingDocumentsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start, end := page.Bounds(10); start != 0 || end != 10 || page.NextToken(10) != "" {
+		t.Fatalf("expected all results, got %d-%d", start, end)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	ctx := context.Background()
+	page, _ := Parse(ctx, &apiv1.ListPendingDocumentsRequest{PageSize: 1})
+	token := page.NextToken(2)
+	tests := []*apiv1.ListPendingDocumentsRequest{
+		{PageSize: -1},
+		{PageToken: "invalid"},
+		{PageSize: 1, PageToken: token, DeadLettersOnly: true},
+	}
+	for _, r := range tests {
+		if _, err := Parse(ctx, r); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected invalid argument for %v, got: %v", r, err)
+		}
+	}
+	if _, err := Parse(ctx, &apiv1.ListPendingDocumentsRequest{PageSize: 5, PageToken: token}); err != nil {
+		t.Errorf("expected page size to be changeable between pages, got: %v", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	page, _ := Parse(context.Background(), &apiv1.PractitionerSearchRequest{PageSize: 2})
+	s := &Stream{Page: page}
+	var sent int
+	for i := 0; i < 5; i++ {
+		include, done := s.Include()
+		if done {
+			break
+		}
+		if include {
+			sent++
+		}
+	}
+	if sent != 2 || s.NextToken() == "" {
+		t.Fatalf("expected two results and a next page, got %d", sent)
+	}
+	next, err := Parse(context.Background(), &apiv1.PractitionerSearchRequest{PageSize: 2, PageToken: s.NextToken()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = &Stream{Page: next}
+	sent = 0
+	for i := 0; i < 3; i++ {
+		if include, _ := s.Include(); include {
+			sent++
+		}
+	}
+	if sent != 1 || s.NextToken() != "" {
+		t.Fatalf("expected a single result and no next page, got %d", sent)
+	}
+}
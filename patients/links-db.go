@@ -0,0 +1,79 @@
+package patients
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq" // postgresql driver
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+type dbLinkStore struct {
+	db *sql.DB
+}
+
+// createLinksTable creates the table of links between patient records, if it does not already exist
+const createLinksTable = `CREATE TABLE IF NOT EXISTS patient_links (
+	superseded_system text NOT NULL,
+	superseded_value text NOT NULL,
+	current_system text NOT NULL,
+	current_value text NOT NULL,
+	created timestamptz NOT NULL,
+	PRIMARY KEY (superseded_system, superseded_value, current_system, current_value)
+);
+CREATE INDEX IF NOT EXISTS patient_links_current ON patient_links (current_system, current_value)`
+
+// NewDatabaseLinkStore creates a store of links between patient records in a PostgreSQL database
+func NewDatabaseLinkStore(connStr string) (LinkStore, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createLinksTable); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &dbLinkStore{db: db}, nil
+}
+
+func (ds *dbLinkStore) Link(ctx context.Context, link *apiv1.PatientLink) error {
+	created := time.Now()
+	if link.GetDateTime().CheckValid() == nil {
+		created = link.GetDateTime().AsTime()
+	}
+	_, err := ds.db.ExecContext(ctx, `INSERT INTO patient_links (superseded_system, superseded_value, current_system, current_value, created)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		link.GetSuperseded().GetSystem(), link.GetSuperseded().GetValue(), link.GetCurrent().GetSystem(), link.GetCurrent().GetValue(), created)
+	return err
+}
+
+func (ds *dbLinkStore) Links(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.PatientLink, error) {
+	rows, err := ds.db.QueryContext(ctx, `SELECT superseded_system, superseded_value, current_system, current_value, created FROM patient_links
+		WHERE (superseded_system=$1 AND superseded_value=$2) OR (current_system=$1 AND current_value=$2)
+		ORDER BY created`, id.GetSystem(), id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*apiv1.PatientLink, 0)
+	for rows.Next() {
+		superseded, current := &apiv1.Identifier{}, &apiv1.Identifier{}
+		var created time.Time
+		if err := rows.Scan(&superseded.System, &superseded.Value, &current.System, &current.Value, &created); err != nil {
+			return nil, err
+		}
+		ts := timestamppb.New(created)
+		if err := ts.CheckValid(); err != nil {
+			return nil, err
+		}
+		result = append(result, &apiv1.PatientLink{Superseded: superseded, Current: current, DateTime: ts})
+	}
+	return result, rows.Err()
+}
+
+func (ds *dbLinkStore) Close() error {
+	return ds.db.Close()
+}
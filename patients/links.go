@@ -0,0 +1,155 @@
+package patients
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxLinkDepth is the maximum number of successive merges followed when returning links
+const maxLinkDepth = 10
+
+// linkTimeout is the maximum time permitted to record a link
+const linkTimeout = 10 * time.Second
+
+// LinkStore is a persistent store of the history of links between patient records
+type LinkStore interface {
+	// Link records that one record has been superseded by another, doing nothing if already recorded
+	Link(ctx context.Context, link *apiv1.PatientLink) error
+	// Links returns the links in which the identifier specified is either the superseded or current record
+	Links(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.PatientLink, error)
+	Close() error
+}
+
+// SetLinkStore sets the store used to record links between patient records, and records links from
+// patient-merged events, such as from inbound ADT A40 messages or EMPI responses.
+// This should not be called once server is running.
+func (d *Directory) SetLinkStore(store LinkStore) {
+	d.links = store
+	events.Subscribe(d.recordLink)
+}
+
+// recordLink records a link from a patient-merged event in the background, as subscribers must not block
+func (d *Directory) recordLink(e *events.Event) {
+	merged, ok := e.Data.(*apiv1.Identifier)
+	if e.Type != events.PatientMerged || !ok || e.Subject == nil {
+		return
+	}
+	ts := timestamppb.New(e.Time)
+	if ts.CheckValid() != nil {
+		ts = timestamppb.Now()
+	}
+	link := &apiv1.PatientLink{Superseded: merged, Current: e.Subject, DateTime: ts}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), linkTimeout)
+		defer cancel()
+		if err := d.links.Link(ctx, link); err != nil {
+			log.Printf("patients: failed to record link %s|%s -> %s|%s: %s", merged.GetSystem(), merged.GetValue(), e.Subject.GetSystem(), e.Subject.GetValue(), err)
+		}
+	}()
+}
+
+// GetPatientLinks returns the links between the record with the identifier specified and other records,
+// following successive merges, so that all prior identifiers superseded by the current record are returned.
+func (d *Directory) GetPatientLinks(ctx context.Context, id *apiv1.Identifier) (*apiv1.PatientLinks, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	if d.links == nil {
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "patient link history not available")
+	}
+	result := &apiv1.PatientLinks{Identifier: id}
+	seen := map[string]bool{key(id): true}
+	current := id
+	// follow links forward to the current record
+	for i := 0; i < maxLinkDepth; i++ {
+		links, err := d.links.Links(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		next := current
+		for _, link := range links {
+			if proto.Equal(link.GetSuperseded(), current) && !seen[key(link.GetCurrent())] {
+				next = link.GetCurrent()
+				result.Current = next
+				result.Links = append(result.Links, link)
+				seen[key(next)] = true
+				break
+			}
+		}
+		if next == current {
+			break
+		}
+		current = next
+	}
+	// follow links backward from the current record to all records it supersedes
+	pending := []*apiv1.Identifier{current}
+	for depth := 0; depth < maxLinkDepth && len(pending) > 0; depth++ {
+		var superseded []*apiv1.Identifier
+		for _, c := range pending {
+			links, err := d.links.Links(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			for _, link := range links {
+				if proto.Equal(link.GetCurrent(), c) && !seen[key(link.GetSuperseded())] {
+					seen[key(link.GetSuperseded())] = true
+					result.Links = append(result.Links, link)
+					superseded = append(superseded, link.GetSuperseded())
+				}
+			}
+		}
+		pending = superseded
+	}
+	return result, nil
+}
+
+// key returns a key for an identifier
+func key(id *apiv1.Identifier) string {
+	return id.GetSystem() + "|" + id.GetValue()
+}
+
+// memoryLinkStore is a non-persistent store of links, useful in testing without a database
+type memoryLinkStore struct {
+	mu    sync.RWMutex
+	links []*apiv1.PatientLink
+}
+
+// NewMemoryLinkStore creates a store that keeps links in memory; these are lost on restart
+func NewMemoryLinkStore() LinkStore {
+	return &memoryLinkStore{}
+}
+
+func (ms *memoryLinkStore) Link(ctx context.Context, link *apiv1.PatientLink) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, l := range ms.links {
+		if proto.Equal(l.GetSuperseded(), link.GetSuperseded()) && proto.Equal(l.GetCurrent(), link.GetCurrent()) {
+			return nil
+		}
+	}
+	ms.links = append(ms.links, proto.Clone(link).(*apiv1.PatientLink))
+	return nil
+}
+
+func (ms *memoryLinkStore) Links(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.PatientLink, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	result := make([]*apiv1.PatientLink, 0)
+	for _, l := range ms.links {
+		if proto.Equal(l.GetSuperseded(), id) || proto.Equal(l.GetCurrent(), id) {
+			result = append(result, proto.Clone(l).(*apiv1.PatientLink))
+		}
+	}
+	return result, nil
+}
+
+func (ms *memoryLinkStore) Close() error { return nil }
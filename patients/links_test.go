@@ -0,0 +1,49 @@
+package patients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPatientLinks(t *testing.T) {
+	d := &Directory{}
+	ctx := context.Background()
+	if _, err := d.GetPatientLinks(ctx, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999997"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected failed precondition without link store, got: %v", err)
+	}
+	d.SetLinkStore(NewMemoryLinkStore())
+	a := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999996"}
+	b := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999997"}
+	c := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	other := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999999"}
+	events.Publish(&events.Event{Type: events.PatientMerged, Subject: b, Data: a})
+	events.Publish(&events.Event{Type: events.PatientMerged, Subject: c, Data: b})
+	events.PublishMergeIfNew(c, b)    // duplicate
+	time.Sleep(50 * time.Millisecond) // links are recorded in the background
+
+	links, err := d.GetPatientLinks(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links.GetLinks()) != 2 || links.GetCurrent() != nil || links.GetLinks()[0].GetSuperseded().GetValue() != b.GetValue() || links.GetLinks()[1].GetSuperseded().GetValue() != a.GetValue() {
+		t.Errorf("expected both prior identifiers superseded by current record, got: %v", links)
+	}
+	links, err = d.GetPatientLinks(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links.GetLinks()) != 2 || links.GetCurrent().GetValue() != c.GetValue() {
+		t.Errorf("expected current record for superseded identifier, got: %v", links)
+	}
+	links, err = d.GetPatientLinks(ctx, other)
+	if err != nil || len(links.GetLinks()) != 0 {
+		t.Errorf("expected no links for unmerged record, got: %v (%v)", links, err)
+	}
+}
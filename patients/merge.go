@@ -0,0 +1,63 @@
+package patients
+
+import (
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/proto"
+)
+
+// uniqueSystems are identifier systems that should uniquely identify a patient, so that different
+// values from different back-ends indicate that the results do not refer to the same patient.
+var uniqueSystems = []string{
+	identifiers.NHSNumber,
+}
+
+// merge merges patient results, in order of priority. Each field is taken from the first result with a
+// non-empty value, and identifiers are combined. The source of each field is recorded as provenance.
+// The results are not modified.
+func merge(results ...result) *apiv1.Patient {
+	if len(results) == 0 {
+		return nil
+	}
+	pt := new(apiv1.Patient)
+	sources := make(map[string]string)
+	set := func(field string, source string, empty bool, f func()) {
+		if _, done := sources[field]; done || empty {
+			return
+		}
+		f()
+		sources[field] = source
+		pt.Provenance = append(pt.Provenance, &apiv1.Provenance{Field: field, Source: source})
+	}
+	seen := make(map[string]struct{})
+	for _, r := range results {
+		src, p := r.source, proto.Clone(r.patient).(*apiv1.Patient)
+		set("lastname", src, p.Lastname == "", func() { pt.Lastname = p.Lastname })
+		set("firstnames", src, p.Firstnames == "", func() { pt.Firstnames = p.Firstnames })
+		set("title", src, p.Title == "", func() { pt.Title = p.Title })
+		set("gender", src, p.Gender == apiv1.Gender_UNKNOWN, func() { pt.Gender = p.Gender })
+		set("birth_date", src, p.BirthDate == nil, func() { pt.BirthDate = p.BirthDate })
+		set("deceased", src, p.Deceased == nil, func() { pt.Deceased = p.Deceased })
+		set("surgery", src, p.Surgery == nil, func() { pt.Surgery = p.Surgery })
+		set("general_practitioner", src, p.GeneralPractitioner == nil, func() { pt.GeneralPractitioner = p.GeneralPractitioner })
+		set("addresses", src, len(p.Addresses) == 0, func() { pt.Addresses = p.Addresses })
+		set("telephones", src, len(p.Telephones) == 0, func() { pt.Telephones = p.Telephones })
+		set("emails", src, len(p.Emails) == 0, func() { pt.Emails = p.Emails })
+		set("nhs_number_verification_status", src, p.NhsNumberVerificationStatus == apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN, func() {
+			pt.NhsNumberVerificationStatus = p.NhsNumberVerificationStatus
+		})
+		set("preferred_language", src, p.PreferredLanguage == "", func() { pt.PreferredLanguage = p.PreferredLanguage })
+		set("ethnic_category", src, p.EthnicCategory == nil, func() { pt.EthnicCategory = p.EthnicCategory })
+		set("marital_status", src, p.MaritalStatus == nil, func() { pt.MaritalStatus = p.MaritalStatus })
+		set("occupation", src, p.Occupation == "", func() { pt.Occupation = p.Occupation })
+		set("country_of_birth", src, p.CountryOfBirth == "", func() { pt.CountryOfBirth = p.CountryOfBirth })
+		for _, id := range p.Identifiers {
+			key := id.GetSystem() + "|" + id.GetValue()
+			if _, dup := seen[key]; !dup {
+				seen[key] = struct{}{}
+				pt.Identifiers = append(pt.Identifiers, id)
+			}
+		}
+	}
+	return pt
+}
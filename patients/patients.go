@@ -0,0 +1,311 @@
+// Package patients provides a patient directory service that abstracts the underlying back-end
+// services (e.g. NHS Wales' EMPI, Cardiff and Vale PMS), so that clients need not know which to use.
+//
+// Requests are fanned out to the registered back-ends that support the identifier system requested.
+// Identifiers returned by one back-end are then used to fetch data from other back-ends, and the
+// results are merged into a single patient record, recording the source of each field.
+package patients
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Backend is a back-end service that can provide patient data
+type Backend interface {
+	// GetPatient returns the patient with the specified identifier
+	GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error)
+}
+
+// Searcher is a back-end service that supports demographic search
+type Searcher interface {
+	// SearchPatient returns patients matching the search request
+	SearchPatient(ctx context.Context, r *apiv1.PatientSearchRequest) ([]*apiv1.Patient, error)
+}
+
+// Updater is a back-end service that supports updating patient demographics
+type Updater interface {
+	// UpdatePatientDemographics submits a change in contact details, returning the updated patient
+	UpdatePatientDemographics(ctx context.Context, r *apiv1.UpdatePatientDemographicsRequest) (*apiv1.Patient, error)
+}
+
+type backend struct {
+	name    string
+	backend Backend
+	systems map[string]struct{}
+}
+
+// Directory is a patient directory service, merging data from multiple back-ends
+type Directory struct {
+	backends []*backend // in order of priority
+	links    LinkStore  // optional, history of links between patient records
+
+	prefetchWorkers int // maximum patients fetched concurrently when prefetching
+}
+
+var _ apiv1.PatientDirectoryServer = (*Directory)(nil)
+
+// Register registers a back-end, in order of priority, that can fetch patients using identifiers from
+// the systems specified. Data from back-ends registered first take precedence when merging.
+// This should not be called once server is running.
+func (d *Directory) Register(name string, b Backend, systems ...string) {
+	be := &backend{name: name, backend: b, systems: make(map[string]struct{})}
+	for _, system := range systems {
+		be.systems[system] = struct{}{}
+	}
+	d.backends = append(d.backends, be)
+	log.Printf("patients: registered backend: '%s'", name)
+}
+
+// RegisterServer registers this server
+func (d *Directory) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterPatientDirectoryServer(s, d)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (d *Directory) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterPatientDirectoryHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (d *Directory) Close() error {
+	if d.links != nil {
+		return d.links.Close()
+	}
+	return nil
+}
+
+// result is a patient returned from a named back-end
+type result struct {
+	source  string
+	patient *apiv1.Patient
+}
+
+// GetPatient returns the patient with the specified identifier, merging results from all back-ends
+// that support the identifier, and from other back-ends using identifiers returned.
+func (d *Directory) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	results, err := d.fetch(ctx, []*apiv1.Identifier{id}, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "patient %s/%s not found", id.GetSystem(), id.GetValue())
+	}
+	return d.reconcile(ctx, results)
+}
+
+// fetch fetches from all back-ends that support any of the identifiers specified, that have not already
+// been queried. Back-ends are queried concurrently. If no back-end supports the identifiers, an error is returned.
+func (d *Directory) fetch(ctx context.Context, ids []*apiv1.Identifier, queried map[string]bool) ([]result, error) {
+	type query struct {
+		be *backend
+		id *apiv1.Identifier
+	}
+	queries := make([]query, 0)
+	for _, be := range d.backends {
+		if queried[be.name] {
+			continue
+		}
+		for _, id := range ids {
+			if _, ok := be.systems[id.GetSystem()]; ok {
+				queries = append(queries, query{be: be, id: id})
+				queried[be.name] = true
+				break
+			}
+		}
+	}
+	if len(queries) == 0 {
+		if len(queried) == 0 {
+			return nil, i18n.Errorf(ctx, codes.InvalidArgument, "unable to resolve '%s|%s': no resolver for uri", ids[0].GetSystem(), ids[0].GetValue())
+		}
+		return nil, nil
+	}
+	results := make([]*result, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q query) {
+			defer wg.Done()
+			pt, err := q.be.backend.GetPatient(ctx, q.id)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = &result{source: q.be.name, patient: pt}
+		}(i, q)
+	}
+	wg.Wait()
+	found := make([]result, 0, len(queries))
+	var firstErr error
+	for i, r := range results {
+		if r != nil && r.patient != nil {
+			found = append(found, *r)
+			continue
+		}
+		if err := errs[i]; err != nil && status.Code(err) != codes.NotFound {
+			log.Printf("patients: backend '%s' failed to fetch %s|%s: %s", queries[i].be.name, queries[i].id.GetSystem(), queries[i].id.GetValue(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, firstErr // nil if simply not found
+	}
+	// and now use any identifiers returned to fetch from other back-ends
+	more := make([]*apiv1.Identifier, 0)
+	for _, r := range found {
+		more = append(more, r.patient.GetIdentifiers()...)
+	}
+	others, err := d.fetch(ctx, more, queried)
+	if err != nil {
+		log.Printf("patients: failed to fetch from other backends: %s", err)
+	}
+	return append(found, others...), nil
+}
+
+// reconcile merges results, ordered by back-end priority, checking that the results refer to the same patient.
+func (d *Directory) reconcile(ctx context.Context, results []result) (*apiv1.Patient, error) {
+	d.prioritise(results)
+	for _, r := range results[1:] {
+		if conflict := conflicting(results[0].patient, r.patient); conflict != "" {
+			log.Printf("patients: mismatched identifier %s between '%s' and '%s'", conflict, results[0].source, r.source)
+			return nil, status.Errorf(codes.FailedPrecondition, "mismatched patient identifiers between '%s' and '%s'", results[0].source, r.source)
+		}
+	}
+	return merge(results...), nil
+}
+
+// prioritise sorts results in order of back-end priority
+func (d *Directory) prioritise(results []result) {
+	priority := make(map[string]int, len(d.backends))
+	for i, be := range d.backends {
+		priority[be.name] = i
+	}
+	for i := 1; i < len(results); i++ { // insertion sort, as usually only a handful of results
+		for j := i; j > 0 && priority[results[j].source] < priority[results[j-1].source]; j-- {
+			results[j], results[j-1] = results[j-1], results[j]
+		}
+	}
+}
+
+// GetPatientStatus returns whether the patient with the specified identifier is alive or deceased
+func (d *Directory) GetPatientStatus(ctx context.Context, id *apiv1.Identifier) (*apiv1.PatientStatus, error) {
+	pt, err := d.GetPatient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &apiv1.PatientStatus{Identifier: id, Status: apiv1.PatientStatus_ALIVE}
+	if pt.GetDeceased() != nil {
+		result.Status = apiv1.PatientStatus_DECEASED
+		result.DeceasedDate = pt.GetDeceasedDate()
+	}
+	return result, nil
+}
+
+// UpdatePatientDemographics submits a change in contact details to the highest priority back-end that
+// supports both the identifier specified and updates. Other back-ends are expected to receive the change
+// from that back-end, so are not updated directly.
+func (d *Directory) UpdatePatientDemographics(ctx context.Context, r *apiv1.UpdatePatientDemographicsRequest) (*apiv1.Patient, error) {
+	id := r.GetIdentifier()
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	for _, be := range d.backends {
+		updater, ok := be.backend.(Updater)
+		if _, supported := be.systems[id.GetSystem()]; !ok || !supported {
+			continue
+		}
+		log.Printf("patients: updating demographics for %s|%s using backend '%s'", id.GetSystem(), id.GetValue(), be.name)
+		return updater.UpdatePatientDemographics(ctx, r)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "no patient directory backend supports demographic updates for '%s'", id.GetSystem())
+}
+
+// SearchPatient performs a demographic search across all back-ends that support search, merging
+// results that share an identifier.
+func (d *Directory) SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error {
+	ctx := s.Context()
+	merged := make([][]result, 0)
+	index := make(map[string]int) // system|value to index in merged
+	searched := false
+	for _, be := range d.backends {
+		searcher, ok := be.backend.(Searcher)
+		if !ok {
+			continue
+		}
+		searched = true
+		pts, err := searcher.SearchPatient(ctx, r)
+		if err != nil {
+			if status.Code(err) == codes.Unimplemented {
+				continue
+			}
+			return err
+		}
+		for _, pt := range pts {
+			i, found := -1, false
+			for _, id := range pt.GetIdentifiers() {
+				if i, found = index[id.GetSystem()+"|"+id.GetValue()]; found {
+					break
+				}
+			}
+			if !found {
+				i = len(merged)
+				merged = append(merged, nil)
+			}
+			merged[i] = append(merged[i], result{source: be.name, patient: pt})
+			for _, id := range pt.GetIdentifiers() {
+				index[id.GetSystem()+"|"+id.GetValue()] = i
+			}
+		}
+	}
+	if !searched {
+		return status.Errorf(codes.Unimplemented, "no patient directory backend supports search")
+	}
+	for _, results := range merged {
+		if err := s.Send(merge(results...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflicting returns a description of the first conflicting identifier, if the patients have different
+// values for any of the specified unique identifier systems, or an empty string.
+func conflicting(pt1 *apiv1.Patient, pt2 *apiv1.Patient) string {
+	for _, system := range uniqueSystems {
+		ids1, found1 := pt1.GetIdentifiersForSystem(system)
+		ids2, found2 := pt2.GetIdentifiersForSystem(system)
+		if !found1 || !found2 {
+			continue
+		}
+		if !overlap(ids1, ids2) {
+			return system
+		}
+	}
+	return ""
+}
+
+func overlap(ids1 []*apiv1.Identifier, ids2 []*apiv1.Identifier) bool {
+	for _, id1 := range ids1 {
+		for _, id2 := range ids2 {
+			if proto.Equal(id1, id2) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package patients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeBackend map[string]*apiv1.Patient
+
+func (fb fakeBackend) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if pt, ok := fb[id.GetSystem()+"|"+id.GetValue()]; ok {
+		return pt, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "not found")
+}
+
+func TestGetPatient(t *testing.T) {
+	nnn := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	crn := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	empi := fakeBackend{
+		identifiers.NHSNumber + "|1111111111": &apiv1.Patient{
+			Lastname:    "Dummy",
+			Firstnames:  "Albert",
+			Identifiers: []*apiv1.Identifier{nnn, crn},
+		},
+	}
+	cav := fakeBackend{
+		identifiers.CardiffAndValeCRN + "|A999998": &apiv1.Patient{
+			Lastname:    "Dumy",
+			Surgery:     &apiv1.Identifier{System: identifiers.ODSCode, Value: "W95010"},
+			Identifiers: []*apiv1.Identifier{crn, nnn},
+		},
+	}
+	d := &Directory{}
+	d.Register("empi", empi, identifiers.NHSNumber) // empi takes priority
+	d.Register("cav", cav, identifiers.CardiffAndValeCRN)
+	for _, id := range []*apiv1.Identifier{nnn, crn} {
+		pt, err := d.GetPatient(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.Lastname != "Dummy" || pt.GetSurgery().GetValue() != "W95010" || len(pt.Identifiers) != 2 {
+			t.Fatalf("incorrectly merged patient: %v", pt)
+		}
+		provenance := make(map[string]string)
+		for _, p := range pt.Provenance {
+			provenance[p.Field] = p.Source
+		}
+		if provenance["lastname"] != "empi" || provenance["surgery"] != "cav" {
+			t.Fatalf("incorrect provenance: %v", pt.Provenance)
+		}
+	}
+	if _, err := d.GetPatient(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "2222222222"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	// a mismatch in NHS number should be reported as an error
+	cav[identifiers.CardiffAndValeCRN+"|A999998"].Identifiers[1] = &apiv1.Identifier{System: identifiers.NHSNumber, Value: "2222222222"}
+	if _, err := d.GetPatient(context.Background(), nnn); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected mismatched identifiers to fail, got: %v", err)
+	}
+}
+
+func TestGetPatientStatus(t *testing.T) {
+	dod := timestamppb.New(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC))
+	empi := fakeBackend{
+		identifiers.NHSNumber + "|1111111111": &apiv1.Patient{Lastname: "Dummy"},
+		identifiers.NHSNumber + "|2222222222": &apiv1.Patient{Lastname: "Dummy", Deceased: &apiv1.Patient_DeceasedDate{DeceasedDate: dod}},
+	}
+	d := &Directory{}
+	d.Register("empi", empi, identifiers.NHSNumber)
+	st, err := d.GetPatientStatus(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetStatus() != apiv1.PatientStatus_ALIVE || st.GetDeceasedDate() != nil {
+		t.Errorf("expected patient to be alive, got: %v", st)
+	}
+	st, err = d.GetPatientStatus(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "2222222222"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetStatus() != apiv1.PatientStatus_DECEASED || !proto.Equal(st.GetDeceasedDate(), dod) {
+		t.Errorf("expected patient to be deceased, got: %v", st)
+	}
+}
+
+type fakeUpdater struct {
+	fakeBackend
+	updated []*apiv1.UpdatePatientDemographicsRequest
+}
+
+func (fu *fakeUpdater) UpdatePatientDemographics(ctx context.Context, r *apiv1.UpdatePatientDemographicsRequest) (*apiv1.Patient, error) {
+	fu.updated = append(fu.updated, r)
+	return &apiv1.Patient{Emails: r.GetEmails()}, nil
+}
+
+func TestUpdatePatientDemographics(t *testing.T) {
+	empi := &fakeUpdater{}
+	d := &Directory{}
+	d.Register("cav", fakeBackend{}, identifiers.NHSNumber, identifiers.CardiffAndValeCRN)
+	d.Register("empi", empi, identifiers.NHSNumber)
+	r := &apiv1.UpdatePatientDemographicsRequest{
+		Identifier: &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"},
+		Emails:     []string{"test@example.com"},
+	}
+	pt, err := d.UpdatePatientDemographics(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empi.updated) != 1 || len(pt.GetEmails()) != 1 {
+		t.Errorf("expected update to be submitted to backend supporting updates, got: %v", empi.updated)
+	}
+	r.Identifier = &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	if _, err := d.UpdatePatientDemographics(context.Background(), r); status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected unimplemented for identifier without backend supporting updates, got: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package patients
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPrefetchWorkers is the default number of patients fetched concurrently when prefetching
+const DefaultPrefetchWorkers = 4
+
+// SetPrefetchWorkers sets the maximum number of patients fetched concurrently when prefetching,
+// limiting the load placed on back-ends such as the EMPI.
+// This should not be called once server is running.
+func (d *Directory) SetPrefetchWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	d.prefetchWorkers = n
+}
+
+// PrefetchPatients fetches the patients with the identifiers streamed, using a bounded pool of workers,
+// so that back-end caches, such as that of the EMPI, are populated before they are needed; for example,
+// for the patients booked into the day's clinics. Progress is sent for each identifier as it is fetched,
+// so may not be in the order requested. Failure to fetch a single patient is reported in its progress
+// and does not end the stream.
+func (d *Directory) PrefetchPatients(stream apiv1.PatientDirectory_PrefetchPatientsServer) error {
+	ctx := stream.Context()
+	start := time.Now()
+	ids := make(chan *apiv1.Identifier)
+	var mu sync.Mutex // protects progress and sending
+	var sendErr error
+	progress := &apiv1.PrefetchProgress{}
+	done := d.prefetch(ctx, ids, func(id *apiv1.Identifier, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Completed++
+		p := &apiv1.PrefetchProgress{Identifier: id, Received: progress.Received, Completed: progress.Completed}
+		if err != nil {
+			progress.Failed++
+			p.ErrorCode, p.Error = int32(status.Code(err)), status.Convert(err).Message()
+		}
+		p.Failed = progress.Failed
+		if sendErr == nil {
+			sendErr = stream.Send(p)
+		}
+	})
+	var recvErr error
+	for recvErr == nil {
+		id, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+		mu.Lock()
+		progress.Received++
+		mu.Unlock()
+		select {
+		case ids <- id:
+		case <-ctx.Done():
+			recvErr = ctx.Err()
+		}
+	}
+	close(ids)
+	<-done
+	log.Printf("patients: prefetched %d patients (%d failed) in %s", progress.Completed, progress.Failed, time.Since(start))
+	if recvErr != nil {
+		return recvErr
+	}
+	return sendErr
+}
+
+// Prefetch fetches the patients with the identifiers specified, using a bounded pool of workers,
+// returning the number that could not be fetched. Fetching stops early if the context is cancelled.
+func (d *Directory) Prefetch(ctx context.Context, ids []*apiv1.Identifier) (failed int, err error) {
+	ch := make(chan *apiv1.Identifier)
+	var mu sync.Mutex
+	done := d.prefetch(ctx, ch, func(id *apiv1.Identifier, err error) {
+		if err != nil {
+			mu.Lock()
+			failed++
+			mu.Unlock()
+		}
+	})
+feed:
+	for _, id := range ids {
+		select {
+		case ch <- id:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(ch)
+	<-done
+	return failed, err
+}
+
+// prefetch starts a pool of workers fetching the patients with the identifiers received from the channel,
+// calling fetched once each has been fetched. The returned channel is closed once the identifiers channel
+// has been closed and all patients fetched.
+func (d *Directory) prefetch(ctx context.Context, ids <-chan *apiv1.Identifier, fetched func(id *apiv1.Identifier, err error)) <-chan struct{} {
+	workers := d.prefetchWorkers
+	if workers < 1 {
+		workers = DefaultPrefetchWorkers
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				_, err := d.GetPatient(ctx, id)
+				fetched(id, err)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
@@ -0,0 +1,98 @@
+package patients
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slowBackend records the maximum number of concurrent requests
+type slowBackend struct {
+	mu      sync.Mutex
+	active  int
+	maximum int
+}
+
+func (sb *slowBackend) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	sb.mu.Lock()
+	sb.active++
+	if sb.active > sb.maximum {
+		sb.maximum = sb.active
+	}
+	sb.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	sb.mu.Lock()
+	sb.active--
+	sb.mu.Unlock()
+	if id.GetValue() == "2222222222" {
+		return nil, status.Errorf(codes.NotFound, "not found")
+	}
+	return &apiv1.Patient{Identifiers: []*apiv1.Identifier{id}}, nil
+}
+
+type prefetchStream struct {
+	grpc.ServerStream
+	ids      []*apiv1.Identifier
+	progress []*apiv1.PrefetchProgress
+}
+
+func (ps *prefetchStream) Context() context.Context { return context.Background() }
+
+func (ps *prefetchStream) Recv() (*apiv1.Identifier, error) {
+	if len(ps.ids) == 0 {
+		return nil, io.EOF
+	}
+	id := ps.ids[0]
+	ps.ids = ps.ids[1:]
+	return id, nil
+}
+
+func (ps *prefetchStream) Send(p *apiv1.PrefetchProgress) error {
+	ps.progress = append(ps.progress, p)
+	return nil
+}
+
+func TestPrefetchPatients(t *testing.T) {
+	backend := &slowBackend{}
+	d := &Directory{}
+	d.Register("empi", backend, identifiers.NHSNumber)
+	d.SetPrefetchWorkers(3)
+	stream := &prefetchStream{}
+	for i := 0; i < 10; i++ {
+		stream.ids = append(stream.ids, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"})
+	}
+	stream.ids = append(stream.ids, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "2222222222"})
+	if err := d.PrefetchPatients(stream); err != nil {
+		t.Fatal(err)
+	}
+	if backend.maximum > 3 || backend.maximum < 2 {
+		t.Errorf("expected up to three concurrent requests, got %d", backend.maximum)
+	}
+	if len(stream.progress) != 11 {
+		t.Fatalf("expected progress for each identifier, got %d", len(stream.progress))
+	}
+	last := stream.progress[10]
+	if last.GetCompleted() != 11 || last.GetFailed() != 1 || last.GetReceived() != 11 {
+		t.Errorf("incorrect final progress: %v", last)
+	}
+	var failed int
+	for _, p := range stream.progress {
+		if p.GetErrorCode() != 0 {
+			failed++
+			if p.GetIdentifier().GetValue() != "2222222222" || codes.Code(p.GetErrorCode()) != codes.NotFound {
+				t.Errorf("unexpected failure: %v", p)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("expected a single failure, got %d", failed)
+	}
+}
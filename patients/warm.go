@@ -0,0 +1,52 @@
+package patients
+
+import (
+	"context"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// ClinicLister is a back-end service that returns the patients booked into clinics, such as CAV PMS
+type ClinicLister interface {
+	// PatientsForClinics returns the patients booked into the clinics specified on the date specified
+	PatientsForClinics(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error)
+}
+
+// WarmClinics returns a scheduled job that fetches the patients booked into the clinics specified on the
+// following day, so that back-end caches are populated before the clinic lists are requested.
+// Patients are fetched by NHS number, if known, so that they are cross-referenced against the EMPI.
+func (d *Directory) WarmClinics(lister ClinicLister, clinics []*apiv1.Identifier) func(ctx context.Context, run *apiv1.JobRun) error {
+	return func(ctx context.Context, run *apiv1.JobRun) error {
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		pts, err := lister.PatientsForClinics(ctx, tomorrow, clinics)
+		if err != nil {
+			return err
+		}
+		ids := make([]*apiv1.Identifier, 0, len(pts))
+		seen := make(map[string]bool)
+		for _, pt := range pts {
+			id := prefetchIdentifier(pt)
+			if id == nil || seen[key(id)] {
+				continue
+			}
+			seen[key(id)] = true
+			ids = append(ids, id)
+		}
+		failed, err := d.Prefetch(ctx, ids)
+		run.Processed, run.Failed = int32(len(ids)), int32(failed)
+		return err
+	}
+}
+
+// prefetchIdentifier returns the identifier used to prefetch the patient, preferring the NHS number
+func prefetchIdentifier(pt *apiv1.Patient) *apiv1.Identifier {
+	if ids, found := pt.GetIdentifiersForSystem(identifiers.NHSNumber); found {
+		return ids[0]
+	}
+	if len(pt.GetIdentifiers()) > 0 {
+		return pt.GetIdentifiers()[0]
+	}
+	return nil
+}
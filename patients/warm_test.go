@@ -0,0 +1,39 @@
+package patients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+type fakeClinicLister []*apiv1.Patient
+
+func (fl fakeClinicLister) PatientsForClinics(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error) {
+	return fl, nil
+}
+
+func TestWarmClinics(t *testing.T) {
+	nnn := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	crn := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	unknown := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999999"}
+	backend := &slowBackend{}
+	d := &Directory{}
+	d.Register("empi", backend, identifiers.NHSNumber)
+	d.Register("cav", fakeBackend{}, identifiers.CardiffAndValeCRN)
+	lister := fakeClinicLister{
+		{Identifiers: []*apiv1.Identifier{crn, nnn}},
+		{Identifiers: []*apiv1.Identifier{crn, nnn}}, // booked into two clinics
+		{Identifiers: []*apiv1.Identifier{unknown}},
+	}
+	run := &apiv1.JobRun{}
+	clinics := []*apiv1.Identifier{{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR01"}}
+	if err := d.WarmClinics(lister, clinics)(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+	if run.GetProcessed() != 2 || run.GetFailed() != 1 {
+		t.Fatalf("expected two patients prefetched with one failure, got: %v", run)
+	}
+}
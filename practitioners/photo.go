@@ -0,0 +1,72 @@
+package practitioners
+
+import (
+	"context"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+)
+
+// photoMaxAge is the time for which clients may cache a photograph
+const photoMaxAge = 3600
+
+var patternPhoto = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "practitioners", "id", "photo"}, ""))
+
+// registerPhotoHandler registers a HTTP handler serving the photograph of a practitioner as an image,
+// rather than as JSON, so that it can be used directly by a browser, with support for conditional
+// requests using an ETag derived from the image data.
+// The identifier system defaults to identifiers.CymruUserID, but may be specified using the 'system' query parameter.
+func registerPhotoHandler(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		if err := conn.Close(); err != nil {
+			log.Printf("practitioners: failed to close connection to %s: %s", endpoint, err)
+		}
+	}()
+	client := apiv1.NewPractitionerDirectoryClient(conn)
+	mux.Handle(http.MethodGet, patternPhoto, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r)
+		if err == nil {
+			var att *apiv1.Attachment
+			if att, err = client.GetPractitionerPhoto(ctx, &apiv1.Identifier{System: photoSystem(r), Value: pathParams["id"]}); err == nil {
+				writePhoto(w, r, att)
+				return
+			}
+		}
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+		runtime.HTTPError(ctx, mux, outbound, w, r, err)
+	})
+	return nil
+}
+
+func writePhoto(w http.ResponseWriter, r *http.Request, att *apiv1.Attachment) {
+	etag := `"` + hex.EncodeToString(att.GetHash()) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(photoMaxAge))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", att.GetContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(att.GetData())))
+	if _, err := w.Write(att.GetData()); err != nil {
+		log.Printf("practitioners: failed to write photograph: %s", err)
+	}
+}
+
+func photoSystem(r *http.Request) string {
+	if system := r.URL.Query().Get("system"); system != "" {
+		return system
+	}
+	return identifiers.CymruUserID
+}
@@ -0,0 +1,47 @@
+package practitioners
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type photoBackend struct {
+	apiv1.UnimplementedPractitionerDirectoryServer
+	data []byte
+}
+
+func (b *photoBackend) GetPractitionerPhoto(ctx context.Context, id *apiv1.Identifier) (*apiv1.Attachment, error) {
+	return &apiv1.Attachment{ContentType: "image/jpeg", Data: b.data, Size: uint64(len(b.data)), Hash: []byte{0xca, 0xfe}}, nil
+}
+
+func TestPhoto(t *testing.T) {
+	d := &Directory{}
+	d.Register("test", &photoBackend{data: []byte("jpeg")}, identifiers.CymruUserID)
+	if _, err := d.GetPractitionerPhoto(context.Background(), &apiv1.Identifier{System: identifiers.SDSUserID, Value: "123"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for unsupported system, got %v", err)
+	}
+	att, err := d.GetPractitionerPhoto(context.Background(), &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	writePhoto(w, httptest.NewRequest(http.MethodGet, "/v1/practitioners/ma090906/photo", nil), att)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag != `"cafe"` || w.Body.String() != "jpeg" {
+		t.Fatalf("unexpected response: %d etag:%s body:%s", w.Code, etag, w.Body.String())
+	}
+	r := httptest.NewRequest(http.MethodGet, "/v1/practitioners/ma090906/photo", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	writePhoto(w, r, att)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected not modified for matching etag, got %d", w.Code)
+	}
+}
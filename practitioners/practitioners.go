@@ -0,0 +1,184 @@
+// Package practitioners provides a practitioner directory service that abstracts the underlying
+// back-end directories (e.g. NHS Wales' NADEX, NHS England's SDS), so that clients need not know which to use.
+//
+// Requests are routed to the back-end registered for the identifier system requested.
+package practitioners
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/paging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Directory is a practitioner directory service, routing requests to back-end directories by identifier system
+type Directory struct {
+	backends map[string]apiv1.PractitionerDirectoryServer // identifier system -> back-end
+	closers  []io.Closer
+	guard    AccountGuard
+}
+
+// AccountGuard protects the accounts of users in back-end directories, determining who may view or change
+// an account, and throttling attempts to verify a user's password, as for login
+type AccountGuard interface {
+	AuthorizeAccount(ctx context.Context, id *apiv1.Identifier) error
+	CheckLogin(ctx context.Context, id *apiv1.Identifier) error
+	LoginFailed(ctx context.Context, id *apiv1.Identifier)
+	LoginSucceeded(id *apiv1.Identifier)
+}
+
+var _ apiv1.PractitionerDirectoryServer = (*Directory)(nil)
+
+// Register registers a back-end directory for practitioners with identifiers from the systems specified.
+// This should not be called once server is running.
+func (d *Directory) Register(name string, b apiv1.PractitionerDirectoryServer, systems ...string) {
+	if d.backends == nil {
+		d.backends = make(map[string]apiv1.PractitionerDirectoryServer)
+	}
+	for _, system := range systems {
+		d.backends[system] = b
+	}
+	if c, ok := b.(io.Closer); ok {
+		d.closers = append(d.closers, c)
+	}
+	log.Printf("practitioners: registered backend: '%s'", name)
+}
+
+// SetAccountGuard sets the guard used to protect accounts. Without a guard, as when running without
+// authentication, any account may be viewed or changed. This should not be called once server is running.
+func (d *Directory) SetAccountGuard(g AccountGuard) {
+	d.guard = g
+}
+
+// RegisterServer registers this server
+func (d *Directory) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterPractitionerDirectoryServer(s, d)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (d *Directory) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	if err := apiv1.RegisterPractitionerDirectoryHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return err
+	}
+	return registerPhotoHandler(ctx, mux, endpoint, opts)
+}
+
+// Close closes any back-ends that have linked resources
+func (d *Directory) Close() error {
+	var result error
+	for _, c := range d.closers {
+		if err := c.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// backend returns the back-end for the identifier system specified
+func (d *Directory) backend(ctx context.Context, system string) (apiv1.PractitionerDirectoryServer, error) {
+	if b, ok := d.backends[system]; ok {
+		return b, nil
+	}
+	return nil, i18n.Errorf(ctx, codes.InvalidArgument, "practitioner directory for namespace '%s' not supported", system)
+}
+
+// SearchPractitioner searches for a practitioner using the back-end for the system requested.
+// Back-ends stream results in a consistent order, and only the page of results requested is returned,
+// with a token for the next page in the trailer if there are more results.
+func (d *Directory) SearchPractitioner(r *apiv1.PractitionerSearchRequest, s apiv1.PractitionerDirectory_SearchPractitionerServer) error {
+	b, err := d.backend(s.Context(), r.GetSystem())
+	if err != nil {
+		return err
+	}
+	page, err := paging.Parse(s.Context(), r)
+	if err != nil {
+		return err
+	}
+	ps := &pagedSearch{PractitionerDirectory_SearchPractitionerServer: s, stream: &paging.Stream{Page: page}}
+	if err := b.SearchPractitioner(r, ps); err != nil && err != errPageComplete {
+		return err
+	}
+	if token := ps.stream.NextToken(); token != "" {
+		s.SetTrailer(metadata.Pairs(paging.TrailerKey, token))
+	}
+	return nil
+}
+
+// errPageComplete is returned to a back-end when a page of results is complete, to stop its search
+var errPageComplete = errors.New("practitioners: page complete")
+
+// pagedSearch sends only the page of search results requested
+type pagedSearch struct {
+	apiv1.PractitionerDirectory_SearchPractitionerServer
+	stream *paging.Stream
+}
+
+func (ps *pagedSearch) Send(p *apiv1.Practitioner) error {
+	include, done := ps.stream.Include()
+	if done {
+		return errPageComplete
+	}
+	if !include {
+		return nil
+	}
+	return ps.PractitionerDirectory_SearchPractitionerServer.Send(p)
+}
+
+// GetPractitionerPhoto returns the photograph of a practitioner using the back-end for the system requested
+func (d *Directory) GetPractitionerPhoto(ctx context.Context, id *apiv1.Identifier) (*apiv1.Attachment, error) {
+	b, err := d.backend(ctx, id.GetSystem())
+	if err != nil {
+		return nil, err
+	}
+	return b.GetPractitionerPhoto(ctx, id)
+}
+
+// GetAccountStatus returns the status of a practitioner's account using the back-end for the system requested
+func (d *Directory) GetAccountStatus(ctx context.Context, id *apiv1.Identifier) (*apiv1.AccountStatus, error) {
+	b, err := d.backend(ctx, id.GetSystem())
+	if err != nil {
+		return nil, err
+	}
+	if d.guard != nil {
+		if err := d.guard.AuthorizeAccount(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return b.GetAccountStatus(ctx, id)
+}
+
+// ChangePassword changes a practitioner's password using the back-end for the system requested.
+// As this verifies the user's current password, failures are recorded and throttled as for login.
+func (d *Directory) ChangePassword(ctx context.Context, r *apiv1.ChangePasswordRequest) (*apiv1.AccountStatus, error) {
+	b, err := d.backend(ctx, r.GetUser().GetSystem())
+	if err != nil {
+		return nil, err
+	}
+	if d.guard == nil {
+		return b.ChangePassword(ctx, r)
+	}
+	if err := d.guard.AuthorizeAccount(ctx, r.GetUser()); err != nil {
+		return nil, err
+	}
+	if err := d.guard.CheckLogin(ctx, r.GetUser()); err != nil {
+		log.Printf("practitioners: password change refused for '%s|%s' after failed logins", r.GetUser().GetSystem(), r.GetUser().GetValue())
+		return nil, err
+	}
+	st, err := b.ChangePassword(ctx, r)
+	switch status.Code(err) {
+	case codes.OK:
+		d.guard.LoginSucceeded(r.GetUser())
+	case codes.Unauthenticated:
+		d.guard.LoginFailed(ctx, r.GetUser())
+	}
+	return st, err
+}
@@ -0,0 +1,89 @@
+package practitioners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type accountBackend struct {
+	apiv1.UnimplementedPractitionerDirectoryServer
+	calls int
+}
+
+func (b *accountBackend) ChangePassword(ctx context.Context, r *apiv1.ChangePasswordRequest) (*apiv1.AccountStatus, error) {
+	b.calls++
+	if r.GetCurrentPassword() != "password" {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
+	}
+	return &apiv1.AccountStatus{User: r.GetUser()}, nil
+}
+
+func (b *accountBackend) GetAccountStatus(ctx context.Context, id *apiv1.Identifier) (*apiv1.AccountStatus, error) {
+	b.calls++
+	return &apiv1.AccountStatus{User: id}, nil
+}
+
+// guard permits access only to the account of its user, and refuses login after a single failure
+type guard struct {
+	user   string
+	failed map[string]bool
+}
+
+func (g *guard) AuthorizeAccount(ctx context.Context, id *apiv1.Identifier) error {
+	if id.GetValue() != g.user {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return nil
+}
+
+func (g *guard) CheckLogin(ctx context.Context, id *apiv1.Identifier) error {
+	if g.failed[id.GetValue()] {
+		return i18n.Errorf(ctx, codes.ResourceExhausted, "too many failed login attempts: retry after %d seconds", 1)
+	}
+	return nil
+}
+
+func (g *guard) LoginFailed(ctx context.Context, id *apiv1.Identifier) {
+	g.failed[id.GetValue()] = true
+}
+
+func (g *guard) LoginSucceeded(id *apiv1.Identifier) {
+	delete(g.failed, id.GetValue())
+}
+
+func TestAccountGuard(t *testing.T) {
+	b := &accountBackend{}
+	d := &Directory{}
+	d.Register("test", b, identifiers.CymruUserID)
+	d.SetAccountGuard(&guard{user: "ma090906", failed: make(map[string]bool)})
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	other := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ru054321"}
+	change := func(id *apiv1.Identifier, password string) codes.Code {
+		_, err := d.ChangePassword(context.Background(), &apiv1.ChangePasswordRequest{User: id, CurrentPassword: password, NewPassword: "new"})
+		return status.Code(err)
+	}
+	if _, err := d.GetAccountStatus(context.Background(), other); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for account of another user. got: %v", err)
+	}
+	if code := change(other, "password"); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for password of another user. got: %s", code)
+	}
+	if b.calls != 0 {
+		t.Fatalf("back-end should not be called for account of another user")
+	}
+	if code := change(user, "wrong"); code != codes.Unauthenticated {
+		t.Fatalf("expected invalid credentials. got: %s", code)
+	}
+	if code := change(user, "password"); code != codes.ResourceExhausted {
+		t.Fatalf("expected password change refused after failure. got: %s", code)
+	}
+	if b.calls != 1 {
+		t.Fatalf("back-end should not be called once throttled. got: %d calls", b.calls)
+	}
+}
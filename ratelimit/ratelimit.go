@@ -0,0 +1,230 @@
+// Package ratelimit provides rate limiting of gRPC calls for each authenticated user or service account,
+// using a token bucket for each user and method, so that a misbehaving client cannot overwhelm backend
+// services such as the EMPI.
+//
+// Limits are configured for each gRPC method (or /service/*), and optionally by default, in a YAML
+// or JSON file, for example:
+//
+//	default: {rate: 20, burst: 40}
+//	methods:
+//	  /apiv1.PatientDirectory/*: {rate: 5, burst: 10}
+//	  /apiv1.Identifiers/GetIdentifier: {rate: 10, burst: 20}
+//
+// Rates are in requests per second. Calls that exceed the limit fail with RESOURCE_EXHAUSTED, with
+// "retry-after" metadata giving the number of seconds after which the call may succeed. Calls made on
+// behalf of a tenant use the limits configured for that tenant, if any.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/yaml.v2"
+)
+
+// RetryAfterHeader is the metadata key giving the number of seconds after which a throttled call may succeed
+const RetryAfterHeader = "retry-after"
+
+// Limit is the rate at which calls are permitted, with a burst permitting short spikes above that rate
+type Limit struct {
+	Rate  float64 `yaml:"rate" json:"rate"`   // sustained requests per second
+	Burst int     `yaml:"burst" json:"burst"` // maximum requests in a burst; defaults to the rate, with a minimum of 1
+}
+
+// Config defines the limits for each gRPC method (or /service/*), and a default limit for other methods.
+// Methods without a limit, when there is no default, are not limited.
+type Config struct {
+	Default *Limit           `yaml:"default" json:"default"`
+	Methods map[string]Limit `yaml:"methods" json:"methods"`
+}
+
+// LoadConfig loads rate limits from the YAML or JSON file specified
+func LoadConfig(filename string) (*Config, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c := new(Config)
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("ratelimit: invalid configuration file: %w", err)
+	}
+	if c.Default != nil && c.Default.Rate <= 0 {
+		return nil, fmt.Errorf("ratelimit: invalid default rate: %v", c.Default.Rate)
+	}
+	for method, l := range c.Methods {
+		if l.Rate <= 0 {
+			return nil, fmt.Errorf("ratelimit: invalid rate for '%s': %v", method, l.Rate)
+		}
+	}
+	return c, nil
+}
+
+// limitFor returns the limit for the method specified, and the method or service pattern to which it
+// applies, so that methods limited as a service share a bucket
+func (c *Config) limitFor(method string) (Limit, string, bool) {
+	if l, ok := c.Methods[method]; ok {
+		return l, method, true
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		if l, ok := c.Methods[method[:i]+"/*"]; ok {
+			return l, method[:i] + "/*", true
+		}
+	}
+	if c.Default != nil {
+		return *c.Default, method, true
+	}
+	return Limit{}, "", false
+}
+
+// bucket is a token bucket, holding up to burst tokens, refilled at the rate of the limit
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // time by which the bucket will have refilled, after which it can be removed
+}
+
+// Limiter limits the rate of calls by each user. This is thread-safe.
+type Limiter struct {
+	config  *Config
+	tenants map[string]*Config // limits for each tenant, in place of the default limits
+	mu      sync.Mutex
+	buckets map[string]*bucket // user and method -> bucket
+	swept   time.Time
+	nowFunc func() time.Time
+}
+
+// New creates a limiter using the configuration specified
+func New(config *Config) *Limiter {
+	return &Limiter{config: config, buckets: make(map[string]*bucket), nowFunc: time.Now}
+}
+
+// SetTenantConfig sets the limits for calls made on behalf of the named tenant, in place of the default limits.
+// This should not be called once server is running.
+func (lim *Limiter) SetTenantConfig(name string, config *Config) {
+	if lim.tenants == nil {
+		lim.tenants = make(map[string]*Config)
+	}
+	lim.tenants[name] = config
+}
+
+// allow determines whether a call by the user, on behalf of the named tenant if any, to the method is
+// permitted, returning the time after which a call would be permitted if not
+func (lim *Limiter) allow(name string, user string, method string) (bool, time.Duration) {
+	config, ok := lim.tenants[name]
+	if !ok {
+		config = lim.config
+	}
+	limit, pattern, ok := config.limitFor(method)
+	if !ok {
+		return true, 0
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = math.Max(1, limit.Rate)
+	}
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	now := lim.nowFunc()
+	lim.sweep(now)
+	key := name + " " + user + " " + pattern
+	b, ok := lim.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		lim.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		b.full = now.Add(time.Duration((burst - b.tokens) / limit.Rate * float64(time.Second)))
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// sweep periodically removes buckets that have refilled, so that buckets for users who have not made
+// recent calls do not accumulate. Buckets that have not refilled are kept, as otherwise they would be
+// replaced by a full bucket, and slow rates would not be enforced.
+func (lim *Limiter) sweep(now time.Time) {
+	if now.Sub(lim.swept) < time.Minute {
+		return
+	}
+	for key, b := range lim.buckets {
+		if !now.Before(b.full) {
+			delete(lim.buckets, key)
+		}
+	}
+	lim.swept = now
+}
+
+// check returns an error if the call is not permitted, with the time after which it may succeed
+func (lim *Limiter) check(ctx context.Context, method string) (time.Duration, error) {
+	user := userKey(ctx)
+	ok, wait := lim.allow(tenant.FromContext(ctx), user, method)
+	if ok {
+		return 0, nil
+	}
+	metrics.RateLimited(method)
+	log.Printf("ratelimit: rate limit exceeded for '%s' calling '%s'", user, method)
+	return wait, i18n.Errorf(ctx, codes.ResourceExhausted, "rate limit exceeded: retry after %d seconds", retryAfter(wait))
+}
+
+// UnaryServerInterceptor returns an interceptor limiting the rate of unary calls.
+// This must run after authentication, so that the authenticated user is known.
+func (lim *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if wait, err := lim.check(ctx, info.FullMethod); err != nil {
+			grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(retryAfter(wait))))
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor limiting the rate of streaming calls.
+// This must run after authentication, so that the authenticated user is known.
+func (lim *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if wait, err := lim.check(ss.Context(), info.FullMethod); err != nil {
+			ss.SetHeader(metadata.Pairs(RetryAfterHeader, strconv.Itoa(retryAfter(wait))))
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// retryAfter returns the number of whole seconds after which a throttled call may succeed
+func retryAfter(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
+
+// userKey returns the key used to limit calls by the authenticated user, or by network address for
+// unauthenticated calls, such as when running without authentication
+func userKey(ctx context.Context) string {
+	if user := server.GetContextData(ctx).GetAuthenticatedUser(); user.GetValue() != "" {
+		return user.GetSystem() + "|" + user.GetValue()
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimitFor(t *testing.T) {
+	c := &Config{
+		Default: &Limit{Rate: 20},
+		Methods: map[string]Limit{
+			"/apiv1.PatientDirectory/*":          {Rate: 5},
+			"/apiv1.PatientDirectory/GetPatient": {Rate: 10},
+		},
+	}
+	tests := []struct {
+		method  string
+		rate    float64
+		pattern string
+	}{
+		{"/apiv1.PatientDirectory/GetPatient", 10, "/apiv1.PatientDirectory/GetPatient"},
+		{"/apiv1.PatientDirectory/Search", 5, "/apiv1.PatientDirectory/*"},
+		{"/apiv1.Identifiers/GetIdentifier", 20, "/apiv1.Identifiers/GetIdentifier"},
+	}
+	for _, test := range tests {
+		l, pattern, ok := c.limitFor(test.method)
+		if !ok || l.Rate != test.rate || pattern != test.pattern {
+			t.Errorf("%s: expected rate %v for '%s', got %v for '%s'", test.method, test.rate, test.pattern, l.Rate, pattern)
+		}
+	}
+	if _, _, ok := (&Config{}).limitFor("/apiv1.Identifiers/GetIdentifier"); ok {
+		t.Errorf("expected no limit without a default")
+	}
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	lim := New(&Config{Methods: map[string]Limit{"/apiv1.PatientDirectory/*": {Rate: 2, Burst: 3}}})
+	lim.nowFunc = func() time.Time { return now }
+	for i := 0; i < 3; i++ {
+		if ok, _ := lim.allow("", "alice", "/apiv1.PatientDirectory/GetPatient"); !ok {
+			t.Fatalf("expected call %d within burst to be permitted", i)
+		}
+	}
+	ok, wait := lim.allow("", "alice", "/apiv1.PatientDirectory/Search")
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("expected call exceeding burst to be throttled for 500ms, got %v %v", ok, wait)
+	}
+	if ok, _ := lim.allow("", "bob", "/apiv1.PatientDirectory/GetPatient"); !ok {
+		t.Errorf("expected other users to be unaffected")
+	}
+	if ok, _ := lim.allow("", "alice", "/apiv1.Identifiers/GetIdentifier"); !ok {
+		t.Errorf("expected unlimited methods to be permitted")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := lim.allow("", "alice", "/apiv1.PatientDirectory/GetPatient"); !ok {
+		t.Errorf("expected call to be permitted after refill")
+	}
+	now = now.Add(2 * time.Minute)
+	lim.allow("", "bob", "/apiv1.PatientDirectory/GetPatient")
+	if len(lim.buckets) != 1 {
+		t.Errorf("expected idle buckets to be removed, got %d", len(lim.buckets))
+	}
+}
+
+func TestSlowLimit(t *testing.T) {
+	now := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	lim := New(&Config{Methods: map[string]Limit{"/apiv1.DocumentService/*": {Rate: 1.0 / 3600, Burst: 2}}}) // 1 per hour
+	lim.nowFunc = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if ok, _ := lim.allow("", "alice", "/apiv1.DocumentService/PublishDocument"); !ok {
+			t.Fatalf("expected call %d within burst to be permitted", i)
+		}
+	}
+	now = now.Add(5 * time.Minute)
+	lim.allow("", "bob", "/apiv1.DocumentService/PublishDocument") // sweeps idle buckets
+	if ok, wait := lim.allow("", "alice", "/apiv1.DocumentService/PublishDocument"); ok || wait != 55*time.Minute {
+		t.Fatalf("expected call to be throttled for 55 minutes after idle buckets swept, got %v %v", ok, wait)
+	}
+	now = now.Add(55 * time.Minute)
+	if ok, _ := lim.allow("", "alice", "/apiv1.DocumentService/PublishDocument"); !ok {
+		t.Fatalf("expected call to be permitted after refill")
+	}
+	now = now.Add(3 * time.Hour)
+	lim.allow("", "bob", "/apiv1.DocumentService/PublishDocument")
+	if _, ok := lim.buckets[" alice /apiv1.DocumentService/*"]; ok {
+		t.Fatalf("expected refilled bucket to be removed")
+	}
+}
+
+func TestTenantLimits(t *testing.T) {
+	now := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	lim := New(&Config{Default: &Limit{Rate: 10}})
+	lim.SetTenantConfig("abuhb", &Config{Methods: map[string]Limit{"/apiv1.PatientDirectory/*": {Rate: 1}}})
+	lim.nowFunc = func() time.Time { return now }
+	if ok, _ := lim.allow("abuhb", "alice", "/apiv1.PatientDirectory/GetPatient"); !ok {
+		t.Fatal("expected first call to be permitted")
+	}
+	if ok, _ := lim.allow("abuhb", "alice", "/apiv1.PatientDirectory/GetPatient"); ok {
+		t.Error("expected tenant's limit to be used in place of the default")
+	}
+	if ok, _ := lim.allow("abuhb", "alice", "/apiv1.Identifiers/GetIdentifier"); !ok {
+		t.Error("expected methods without a limit for the tenant to be permitted")
+	}
+	if ok, _ := lim.allow("", "alice", "/apiv1.PatientDirectory/GetPatient"); !ok {
+		t.Error("expected default limits to be unaffected by a tenant's calls")
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	lim := New(&Config{Default: &Limit{Rate: 1}})
+	interceptor := lim.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/apiv1.PatientDirectory/GetPatient"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	_, err := interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected RESOURCE_EXHAUSTED, got: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ratelimit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "limits.yaml")
+	if err := ioutil.WriteFile(filename, []byte("default: {rate: 20, burst: 40}\nmethods:\n  /apiv1.PatientDirectory/*: {rate: 5}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Default.Burst != 40 || c.Methods["/apiv1.PatientDirectory/*"].Rate != 5 {
+		t.Errorf("unexpected configuration: %+v", c)
+	}
+	if err := ioutil.WriteFile(filename, []byte("methods:\n  /apiv1.PatientDirectory/*: {rate: 0}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(filename); err == nil {
+		t.Errorf("expected error for invalid rate")
+	}
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron-style schedule, with fields for minute, hour, day of month, month and day of week.
+// Each field is "*", a value, a range ("1-5"), a step ("*/15" or "0-30/10"), or a list of these ("1,15").
+// Days of the week are numbered from zero (Sunday). The descriptors @hourly, @daily, @weekly and @monthly
+// are also supported.
+type Schedule struct {
+	spec   string
+	minute uint64 // bitsets of the permitted values of each field
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool // day of month unrestricted
+	anyDow bool // day of week unrestricted
+}
+
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// bounds are the permitted values of each field
+var bounds = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Parse parses a cron-style schedule, e.g. "0 19 * * 1-5" for 7pm on weekdays
+func Parse(spec string) (*Schedule, error) {
+	s := &Schedule{spec: spec}
+	expanded := spec
+	if d, ok := descriptors[strings.TrimSpace(spec)]; ok {
+		expanded = d
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != len(bounds) {
+		return nil, fmt.Errorf("scheduler: invalid schedule '%s': expected %d fields, got %d", spec, len(bounds), len(fields))
+	}
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		v, err := parseField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: invalid schedule '%s': %s: %w", spec, bounds[i].name, err)
+		}
+		values[i] = v
+	}
+	s.minute, s.hour, s.dom, s.month, s.dow = values[0], values[1], values[2], values[3], values[4]
+	s.anyDom, s.anyDow = fields[2] == "*", fields[4] == "*"
+	return s, nil
+}
+
+// parseField parses a single field of a schedule, returning a bitset of the values permitted
+func parseField(field string, min, max int) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step '%s'", part[i+1:])
+			}
+			step, part = n, part[:i]
+		}
+		start, end := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", bounds[0])
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value '%s'", bounds[1])
+				}
+			} else if step > 1 {
+				end = max // e.g. "5/15" from 5 onwards
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("'%s' outside range %d-%d", part, min, max)
+		}
+		for v := start; v <= end; v += step {
+			result |= 1 << uint(v)
+		}
+	}
+	return result, nil
+}
+
+// String returns the schedule as specified
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the next time after the time specified that matches the schedule, in the same location,
+// or the zero time if there is no such time within five years (e.g. for 30th February)
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay returns whether the day matches the schedule. As with cron, if both day of month and day of
+// week are restricted, a day matching either is permitted.
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	}
+	return dom || dow
+}
@@ -0,0 +1,208 @@
+// Package scheduler runs jobs, such as warming caches ahead of the day's clinics, on a cron-style schedule.
+//
+// Each job runs at most once at a time. The report of the most recent run of each job is retained, and
+// the result of each run is recorded in metrics, so that failures can be alerted upon.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultTimeout is the default maximum duration of a single run of a job
+const DefaultTimeout = time.Hour
+
+// Job is a task run on a schedule
+type Job struct {
+	Name     string
+	Schedule string        // cron-style schedule, see Parse
+	Timeout  time.Duration // maximum duration of a run, or DefaultTimeout if zero
+	// Run runs the job, recording the number of items processed and failed in the run specified
+	Run func(ctx context.Context, run *apiv1.JobRun) error
+}
+
+type job struct {
+	Job
+	schedule *Schedule
+	next     time.Time
+	running  bool
+	last     *apiv1.JobRun
+}
+
+// Scheduler runs jobs on their schedules
+type Scheduler struct {
+	mu    sync.Mutex
+	jobs  map[string]*job
+	stop  chan struct{}
+	wg    sync.WaitGroup
+	start sync.Once
+	now   func() time.Time
+}
+
+// New creates a new scheduler. Jobs are not run until the scheduler is started.
+func New() *Scheduler {
+	return &Scheduler{jobs: make(map[string]*job), stop: make(chan struct{}), now: time.Now}
+}
+
+// Add adds a job, returning an error if the schedule is invalid or a job with the same name exists.
+// This should not be called once the scheduler is started.
+func (s *Scheduler) Add(j Job) error {
+	schedule, err := Parse(j.Schedule)
+	if err != nil {
+		return err
+	}
+	if j.Timeout <= 0 {
+		j.Timeout = DefaultTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[j.Name]; exists {
+		return fmt.Errorf("scheduler: job '%s' already exists", j.Name)
+	}
+	s.jobs[j.Name] = &job{Job: j, schedule: schedule}
+	log.Printf("scheduler: added job '%s' (%s)", j.Name, schedule)
+	return nil
+}
+
+// Start starts running jobs on their schedules in the background
+func (s *Scheduler) Start() {
+	s.start.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, j := range s.jobs {
+			s.wg.Add(1)
+			go s.loop(j)
+		}
+	})
+}
+
+// loop runs the job at each scheduled time until the scheduler is closed
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		j.next = j.schedule.Next(s.now())
+		next := j.next
+		s.mu.Unlock()
+		if next.IsZero() {
+			log.Printf("scheduler: job '%s' has no future runs", j.Name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		select { // don't start another run if closed while waiting
+		case <-s.stop:
+			return
+		default:
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-s.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if _, err := s.run(ctx, j); err != nil && status.Code(err) == codes.FailedPrecondition {
+			log.Printf("scheduler: skipped job '%s': %s", j.Name, err)
+		}
+		cancel()
+	}
+}
+
+// Run runs the named job immediately, returning the report of the run once complete.
+// An error is returned if the job is already running, but the failure of the job itself is
+// reported in the run.
+func (s *Scheduler) Run(ctx context.Context, name string) (*apiv1.JobRun, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "scheduler: job not found: %s", name)
+	}
+	return s.run(ctx, j)
+}
+
+// run runs the job, unless already running, recording the result
+func (s *Scheduler) run(ctx context.Context, j *job) (*apiv1.JobRun, error) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "scheduler: job already running: %s", j.Name)
+	}
+	j.running = true
+	s.mu.Unlock()
+	start := s.now()
+	run := &apiv1.JobRun{Job: j.Name, Started: toTimestamp(start)}
+	log.Printf("scheduler: running job '%s'", j.Name)
+	ctx, cancel := context.WithTimeout(ctx, j.Timeout)
+	err := j.Run(ctx, run)
+	cancel()
+	run.Finished = toTimestamp(s.now())
+	if err != nil {
+		run.ErrorCode, run.Error = int32(status.Code(err)), status.Convert(err).Message()
+		log.Printf("scheduler: job '%s' failed after %s: %s", j.Name, time.Since(start), err)
+	} else {
+		log.Printf("scheduler: job '%s' processed %d items (%d failed) in %s", j.Name, run.GetProcessed(), run.GetFailed(), time.Since(start))
+	}
+	metrics.JobRun(j.Name, err, time.Since(start), int(run.GetProcessed()), int(run.GetFailed()))
+	s.mu.Lock()
+	j.running = false
+	j.last = run
+	s.mu.Unlock()
+	return proto.Clone(run).(*apiv1.JobRun), nil
+}
+
+// Jobs returns the jobs, sorted by name, with the report of their most recent run
+func (s *Scheduler) Jobs() []*apiv1.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*apiv1.Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		job := &apiv1.Job{Name: j.Name, Schedule: j.schedule.String(), Running: j.running}
+		if !j.next.IsZero() {
+			job.NextRun = toTimestamp(j.next)
+		}
+		if j.last != nil {
+			job.LastRun = proto.Clone(j.last).(*apiv1.JobRun)
+		}
+		result = append(result, job)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GetName() < result[j].GetName() })
+	return result
+}
+
+// Close stops running jobs on their schedules, cancelling and waiting for any scheduled runs in progress
+func (s *Scheduler) Close() error {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	ts := timestamppb.New(t)
+	return ts
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSchedule(t *testing.T) {
+	from := time.Date(2020, 3, 20, 18, 30, 0, 0, time.UTC) // a Friday
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"0 19 * * *", time.Date(2020, 3, 20, 19, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, 3, 20, 18, 45, 0, 0, time.UTC)},
+		{"0 19 * * 1-5", time.Date(2020, 3, 20, 19, 0, 0, 0, time.UTC)},
+		{"0 7 * * 1", time.Date(2020, 3, 23, 7, 0, 0, 0, time.UTC)},
+		{"30 18 * * *", time.Date(2020, 3, 21, 18, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"0 8,17 * * *", time.Date(2020, 3, 21, 8, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2020, 3, 21, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next := s.Next(from); !next.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", test.spec, test.expected, next)
+		}
+	}
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "0 24 * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected invalid schedule: '%s'", spec)
+		}
+	}
+	if s, _ := Parse("0 0 30 2 *"); !s.Next(from).IsZero() {
+		t.Errorf("expected no next run for an impossible date")
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	block := make(chan struct{})
+	if err := s.Add(Job{Name: "test", Schedule: "@daily", Run: func(ctx context.Context, run *apiv1.JobRun) error {
+		<-block
+		run.Processed, run.Failed = 10, 2
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Job{Name: "test", Schedule: "@daily"}); err == nil {
+		t.Fatal("expected duplicate job to be rejected")
+	}
+	if err := s.Add(Job{Name: "failing", Schedule: "0 19 * * *", Run: func(ctx context.Context, run *apiv1.JobRun) error {
+		return status.Errorf(codes.Unavailable, "backend unavailable")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Run(ctx, "missing"); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	result := make(chan *apiv1.JobRun)
+	go func() {
+		run, _ := s.Run(ctx, "test")
+		result <- run
+	}()
+	for !s.Jobs()[1].GetRunning() {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := s.Run(ctx, "test"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected job already running, got: %v", err)
+	}
+	close(block)
+	if run := <-result; run.GetProcessed() != 10 || run.GetFailed() != 2 || run.GetError() != "" {
+		t.Fatalf("incorrect report of run: %v", run)
+	}
+	run, err := s.Run(ctx, "failing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes.Code(run.GetErrorCode()) != codes.Unavailable || run.GetError() != "backend unavailable" {
+		t.Fatalf("expected failure in report of run, got: %v", run)
+	}
+	jobs := s.Jobs()
+	if len(jobs) != 2 || jobs[0].GetName() != "failing" || jobs[1].GetLastRun().GetProcessed() != 10 || jobs[1].GetRunning() {
+		t.Fatalf("incorrect jobs: %v", jobs)
+	}
+}
+
+func TestStart(t *testing.T) {
+	s := New()
+	now := time.Date(2020, 3, 20, 18, 59, 59, 990000000, time.Local)
+	s.now = func() time.Time { return now }
+	ran := make(chan struct{}, 1)
+	if err := s.Add(Job{Name: "warm", Schedule: "0 19 * * *", Run: func(ctx context.Context, run *apiv1.JobRun) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		<-ctx.Done() // cancelled on close
+		return errors.New("cancelled")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job not run on schedule")
+	}
+	s.Close()
+	if jobs := s.Jobs(); jobs[0].GetLastRun().GetError() != "cancelled" {
+		t.Fatalf("expected run to be cancelled on close, got: %v", jobs[0])
+	}
+}
@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EnvFile is a provider of secrets from an environment file of KEY=VALUE lines, as used by docker
+// and systemd, with blank lines and comments beginning with '#' ignored. Values may be quoted.
+type EnvFile string
+
+var _ Provider = EnvFile("")
+
+// Secrets returns the secrets in the environment file, read each time so that changes are picked up
+func (f EnvFile) Secrets(ctx context.Context) (map[string]string, error) {
+	data, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return nil, fmt.Errorf("secrets: could not read environment file: %w", err)
+	}
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("secrets: invalid line %d in environment file '%s': expected KEY=VALUE", n, f)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			if value[0] == '"' {
+				if value, err = strconv.Unquote(value); err != nil {
+					return nil, fmt.Errorf("secrets: invalid value on line %d in environment file '%s': %w", n, f, err)
+				}
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		}
+		result[key] = value
+	}
+	return result, scanner.Err()
+}
+
+// Dir is a provider of secrets from a directory containing a file for each secret, named for the secret,
+// such as a Kubernetes secret mounted as a volume. Hidden files, such as the '..data' link used by
+// Kubernetes to update mounted secrets atomically, and subdirectories are ignored. A single trailing
+// newline is removed from each value.
+type Dir string
+
+var _ Provider = Dir("")
+
+// Secrets returns the secrets in the directory, read each time so that updated secrets are picked up
+func (d Dir) Secrets(ctx context.Context) (map[string]string, error) {
+	files, err := ioutil.ReadDir(string(d))
+	if err != nil {
+		return nil, fmt.Errorf("secrets: could not read secrets directory: %w", err)
+	}
+	result := make(map[string]string)
+	for _, fi := range files {
+		if strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		filename := filepath.Join(string(d), fi.Name())
+		if fi, err = os.Stat(filename); err != nil || fi.IsDir() { // follow symbolic links
+			continue
+		}
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: could not read secret: %w", err)
+		}
+		value := strings.TrimSuffix(string(data), "\n")
+		result[fi.Name()] = strings.TrimSuffix(value, "\r")
+	}
+	return result, nil
+}
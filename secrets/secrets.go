@@ -0,0 +1,150 @@
+// Package secrets provides credentials, such as the passwords used for backend services, from a secrets
+// provider: HashiCorp Vault, an environment file or a directory of files such as mounted Kubernetes secrets.
+//
+// Secrets are refreshed periodically from the provider, so that credentials rotated in the provider are
+// used without restarting. Secrets are named using the name of the corresponding configuration flag,
+// e.g. "cav-pms-password", with environment-style names (e.g. CAV_PMS_PASSWORD) also accepted.
+package secrets
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefresh is the default interval at which secrets are refreshed from a provider
+const DefaultRefresh = 5 * time.Minute
+
+// Secret is a credential that may be rotated
+type Secret interface {
+	// Value returns the current value of the secret
+	Value() string
+}
+
+// Static is a secret with a fixed value
+type Static string
+
+// Value returns the value of the secret
+func (s Static) Value() string {
+	return string(s)
+}
+
+// Provider provides secrets from a secret store
+type Provider interface {
+	// Secrets returns all available secrets, keyed by name
+	Secrets(ctx context.Context) (map[string]string, error)
+}
+
+// Store holds secrets obtained from a provider, refreshing them periodically. This is thread-safe.
+type Store struct {
+	provider Provider
+	mu       sync.RWMutex
+	values   map[string]string
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewStore creates a store of secrets from the provider, returning an error if the secrets cannot be
+// obtained initially. Secrets are subsequently refreshed at the interval specified, if greater than zero,
+// until the store is closed; if a refresh fails, the secrets previously obtained continue to be used.
+func NewStore(ctx context.Context, provider Provider, refresh time.Duration) (*Store, error) {
+	st := &Store{provider: provider, done: make(chan struct{})}
+	if err := st.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	if refresh > 0 {
+		go st.run(refresh)
+	}
+	return st, nil
+}
+
+// Refresh obtains the current secrets from the provider, logging the names of any that have changed
+func (st *Store) Refresh(ctx context.Context) error {
+	values, err := st.provider.Secrets(ctx)
+	if err != nil {
+		return err
+	}
+	normalised := make(map[string]string, len(values))
+	for k, v := range values {
+		normalised[Name(k)] = v
+	}
+	st.mu.Lock()
+	previous := st.values
+	st.values = normalised
+	st.mu.Unlock()
+	if previous != nil {
+		for k, v := range normalised {
+			if old, ok := previous[k]; !ok || old != v {
+				log.Printf("secrets: secret '%s' rotated", k)
+			}
+		}
+	}
+	return nil
+}
+
+func (st *Store) run(refresh time.Duration) {
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-st.done:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := st.Refresh(ctx); err != nil {
+				log.Printf("secrets: failed to refresh secrets: %s", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Close stops refreshing secrets
+func (st *Store) Close() error {
+	if st != nil {
+		st.once.Do(func() { close(st.done) })
+	}
+	return nil
+}
+
+// Lookup returns the current value of the named secret, and whether it exists
+func (st *Store) Lookup(name string) (string, bool) {
+	if st == nil {
+		return "", false
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	v, ok := st.values[Name(name)]
+	return v, ok
+}
+
+// Secret returns the named secret, using the fallback value if the secret does not exist in the store.
+// The secret returned always provides the current value, so rotated secrets are used without restart.
+// A nil store returns the fallback value.
+func (st *Store) Secret(name string, fallback string) Secret {
+	if st == nil {
+		return Static(fallback)
+	}
+	return &storeSecret{store: st, name: name, fallback: fallback}
+}
+
+type storeSecret struct {
+	store    *Store
+	name     string
+	fallback string
+}
+
+func (s *storeSecret) Value() string {
+	if v, ok := s.store.Lookup(s.name); ok {
+		return v
+	}
+	return s.fallback
+}
+
+// Name returns the canonical name of a secret, converting environment-style names such as
+// CAV_PMS_PASSWORD to the name of the corresponding flag, cav-pms-password.
+func Name(s string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "_", "-")
+}
@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "secrets.env")
+	if err := ioutil.WriteFile(filename, []byte("# credentials\nCAV_PMS_PASSWORD=\"pa ss\\n\"\nexport nadex-password='secret'\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	st, err := NewStore(context.Background(), EnvFile(filename), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	cav := st.Secret("cav-pms-password", "")
+	if v := cav.Value(); v != "pa ss\n" {
+		t.Errorf("unexpected value for cav-pms-password: %q", v)
+	}
+	if v := st.Secret("NADEX_PASSWORD", "").Value(); v != "secret" {
+		t.Errorf("unexpected value for nadex-password: %q", v)
+	}
+	if v := st.Secret("wcrs-password", "fallback").Value(); v != "fallback" {
+		t.Errorf("expected fallback value for missing secret, got: %q", v)
+	}
+	if err := ioutil.WriteFile(filename, []byte("CAV_PMS_PASSWORD=rotated\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if v := cav.Value(); v != "rotated" {
+		t.Errorf("expected rotated secret, got: %q", v)
+	}
+	if err := ioutil.WriteFile(filename, []byte("invalid\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Refresh(context.Background()); err == nil {
+		t.Errorf("expected error for invalid environment file")
+	}
+	if v := cav.Value(); v != "rotated" {
+		t.Errorf("expected previous secret to be retained after failed refresh, got: %q", v)
+	}
+	var nilStore *Store
+	if v := nilStore.Secret("cav-pms-password", "flag").Value(); v != "flag" {
+		t.Errorf("expected fallback from nil store, got: %q", v)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := filepath.Join(dir, "..data")
+	if err := os.Mkdir(data, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(data, "auth-secret"), []byte("$2a$10$hash\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "auth-secret"), filepath.Join(dir, "auth-secret")); err != nil {
+		t.Fatal(err)
+	}
+	secrets, err := Dir(dir).Secrets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets["auth-secret"] != "$2a$10$hash" {
+		t.Errorf("unexpected secrets from directory: %v", secrets)
+	}
+}
+
+func TestVault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/concierge" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"cav-pms-password":"secret","mesh-password":"mesh"},"metadata":{"version":3}}}`))
+	}))
+	defer ts.Close()
+	v := &Vault{Addr: ts.URL + "/", Path: "secret/data/concierge", Token: "s.token"}
+	secrets, err := v.Secrets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 2 || secrets["cav-pms-password"] != "secret" || secrets["mesh-password"] != "mesh" {
+		t.Errorf("unexpected secrets from vault: %v", secrets)
+	}
+	v.Token = "invalid"
+	if _, err := v.Secrets(context.Background()); err == nil {
+		t.Errorf("expected error for invalid token")
+	}
+}
@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Vault is a provider of secrets from a HashiCorp Vault key/value secrets engine, using the HTTP API.
+// Both version 1 and version 2 of the key/value engine are supported; for version 2, the path must
+// include the 'data' segment, e.g. "secret/data/concierge".
+type Vault struct {
+	Addr      string       // address of the Vault server, e.g. https://vault.example.com:8200
+	Path      string       // API path of the secret, e.g. secret/data/concierge
+	Token     string       // token used to authenticate, if TokenFile is not specified
+	TokenFile string       // file containing the token, read on each request, e.g. as written by Vault Agent
+	Namespace string       // namespace, for Vault Enterprise; optional
+	Client    *http.Client // HTTP client; http.DefaultClient if nil
+}
+
+var _ Provider = (*Vault)(nil)
+
+// Secrets returns the key/value pairs stored at the configured path
+func (v *Vault) Secrets(ctx context.Context) (map[string]string, error) {
+	token := v.Token
+	if v.TokenFile != "" {
+		b, err := ioutil.ReadFile(v.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: could not read vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	url := strings.TrimSuffix(v.Addr, "/") + "/v1/" + strings.TrimPrefix(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: invalid vault address: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: could not connect to vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secrets: could not read '%s' from vault: %s", v.Path, resp.Status)
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("secrets: invalid response from vault: %w", err)
+	}
+	data := body.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok { // key/value version 2
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	result := make(map[string]string, len(data))
+	for k, value := range data {
+		if s, ok := value.(string); ok {
+			result[k] = s
+		} else {
+			result[k] = fmt.Sprint(value)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UserManager is a RoleManager that also permits management of the accounts themselves, with passwords
+// stored as bcrypt hashes
+type UserManager interface {
+	RoleManager
+	// User returns the account, or ErrNoSuchUser
+	User(id *apiv1.Identifier) (*apiv1.ServiceAccount, error)
+	// CreateUser creates an account, expiring at the time specified unless zero, or returns ErrUserExists
+	CreateUser(id *apiv1.Identifier, hash string, expires time.Time) error
+	// DisableUser disables an account, or returns ErrNoSuchUser
+	DisableUser(id *apiv1.Identifier) error
+	// SetPassword replaces the password of an account, and its expiry unless zero, or returns ErrNoSuchUser
+	SetPassword(id *apiv1.Identifier, hash string, expires time.Time) error
+}
+
+var (
+	// ErrNoSuchUser means that the account does not exist
+	ErrNoSuchUser = errors.New("no such account")
+	// ErrUserExists means that the account already exists
+	ErrUserExists = errors.New("account already exists")
+	// ErrInvalidUsername means that the username is not permitted
+	ErrInvalidUsername = errors.New("invalid username: use only letters, digits, '.', '_' and '-'")
+	// ErrExpiresInPast means that an expiry was requested that has already passed
+	ErrExpiresInPast = errors.New("expiry must be in the future")
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// CreateServiceAccount creates a service account with a generated password and the roles specified, returning
+// the account including its password, which is not stored and so cannot be retrieved later
+func CreateServiceAccount(um UserManager, p *Policy, id *apiv1.Identifier, roles []string, expires time.Time) (*apiv1.ServiceAccount, error) {
+	if !usernamePattern.MatchString(id.GetValue()) {
+		return nil, ErrInvalidUsername
+	}
+	if !expires.IsZero() && expires.Before(time.Now()) {
+		return nil, ErrExpiresInPast
+	}
+	for _, role := range roles {
+		if !p.IsRole(role) {
+			return nil, fmt.Errorf("unknown role '%s': expected one of %v", role, p.RoleNames())
+		}
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if err := um.CreateUser(id, hash, expires); err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if err := um.AssignRole(id, role); err != nil {
+			return nil, err
+		}
+	}
+	return withPassword(um, id, password)
+}
+
+// DisableServiceAccount disables a service account, revoking all tokens issued to it
+func DisableServiceAccount(um UserManager, rl RevocationList, id *apiv1.Identifier) (*apiv1.ServiceAccount, error) {
+	if err := um.DisableUser(id); err != nil {
+		return nil, err
+	}
+	if err := rl.RevokeSubject(id.GetSystem()+"|"+id.GetValue(), time.Now()); err != nil {
+		return nil, err
+	}
+	return um.User(id)
+}
+
+// RotateServiceAccountSecret replaces the password of a service account with a generated password, and
+// its expiry unless zero, returning the account including its new password. Tokens already issued remain
+// valid until they expire, so that services can change to the new password without interruption.
+func RotateServiceAccountSecret(um UserManager, id *apiv1.Identifier, expires time.Time) (*apiv1.ServiceAccount, error) {
+	if !expires.IsZero() && expires.Before(time.Now()) {
+		return nil, ErrExpiresInPast
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if err := um.SetPassword(id, hash, expires); err != nil {
+		return nil, err
+	}
+	return withPassword(um, id, password)
+}
+
+func withPassword(um UserManager, id *apiv1.Identifier, password string) (*apiv1.ServiceAccount, error) {
+	sa, err := um.User(id)
+	if err != nil {
+		return nil, err
+	}
+	sa.Password = password
+	return sa, nil
+}
+
+// active returns an error if the account is disabled or has expired
+func active(sa *apiv1.ServiceAccount) error {
+	if sa.GetDisabled() {
+		return errors.New("account disabled")
+	}
+	if sa.GetExpires() != nil {
+		if sa.GetExpires().CheckValid() == nil && sa.GetExpires().AsTime().Before(time.Now()) {
+			return errors.New("account expired")
+		}
+	}
+	return nil
+}
+
+// GetServiceAccount returns a service account
+func (auth *Auth) GetServiceAccount(ctx context.Context, id *apiv1.Identifier) (*apiv1.ServiceAccount, error) {
+	um, err := auth.userManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sa, err := um.User(id)
+	if err != nil {
+		return nil, accountError(ctx, id, err)
+	}
+	return sa, nil
+}
+
+// CreateServiceAccount creates a service account, returning its generated password
+func (auth *Auth) CreateServiceAccount(ctx context.Context, r *apiv1.CreateServiceAccountRequest) (*apiv1.ServiceAccount, error) {
+	um, err := auth.userManager(ctx, r.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range r.GetRoles() {
+		if !auth.policy.IsRole(role) {
+			return nil, i18n.Errorf(ctx, codes.InvalidArgument, "unknown role '%s': expected one of %v", role, auth.policy.RoleNames())
+		}
+	}
+	var expires time.Time
+	if r.GetExpires() != nil {
+		if err := r.GetExpires().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expiry: %s", err)
+		}
+		expires = r.GetExpires().AsTime()
+	}
+	sa, err := CreateServiceAccount(um, auth.policy, r.GetUser(), r.GetRoles(), expires)
+	if err != nil {
+		return nil, accountError(ctx, r.GetUser(), err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("auth: service account '%s|%s' created by '%s|%s' (roles: %v, expires: %v)", r.GetUser().GetSystem(), r.GetUser().GetValue(), user.GetSystem(), user.GetValue(), r.GetRoles(), expires)
+	return sa, nil
+}
+
+// DisableServiceAccount disables a service account, revoking all tokens issued to it
+func (auth *Auth) DisableServiceAccount(ctx context.Context, id *apiv1.Identifier) (*apiv1.ServiceAccount, error) {
+	um, err := auth.userManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sa, err := DisableServiceAccount(um, auth.revoked, id)
+	if err != nil {
+		return nil, accountError(ctx, id, err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("auth: service account '%s|%s' disabled by '%s|%s'", id.GetSystem(), id.GetValue(), user.GetSystem(), user.GetValue())
+	return sa, nil
+}
+
+// RotateServiceAccountSecret replaces the password of a service account, returning the new generated password
+func (auth *Auth) RotateServiceAccountSecret(ctx context.Context, r *apiv1.RotateSecretRequest) (*apiv1.ServiceAccount, error) {
+	um, err := auth.userManager(ctx, r.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	var expires time.Time
+	if r.GetExpires() != nil {
+		if err := r.GetExpires().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expiry: %s", err)
+		}
+		expires = r.GetExpires().AsTime()
+	}
+	sa, err := RotateServiceAccountSecret(um, r.GetUser(), expires)
+	if err != nil {
+		return nil, accountError(ctx, r.GetUser(), err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("auth: password of service account '%s|%s' rotated by '%s|%s'", r.GetUser().GetSystem(), r.GetUser().GetValue(), user.GetSystem(), user.GetValue())
+	return sa, nil
+}
+
+// userManager returns the user manager for the namespace of the user specified
+func (auth *Auth) userManager(ctx context.Context, id *apiv1.Identifier) (UserManager, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "missing user")
+	}
+	if id.GetSystem() != identifiers.ConciergeServiceUser {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "service accounts must be in namespace '%s'", identifiers.ConciergeServiceUser)
+	}
+	if um, ok := auth.authProviders[id.GetSystem()].(UserManager); ok {
+		return um, nil
+	}
+	return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "service accounts cannot be managed for namespace '%s'", id.GetSystem())
+}
+
+// accountError returns the gRPC error for an error managing the account specified
+func accountError(ctx context.Context, id *apiv1.Identifier, err error) error {
+	switch {
+	case errors.Is(err, ErrNoSuchUser):
+		return i18n.Errorf(ctx, codes.NotFound, "no service account '%s'", id.GetValue())
+	case errors.Is(err, ErrUserExists):
+		return i18n.Errorf(ctx, codes.AlreadyExists, "service account '%s' already exists", id.GetValue())
+	case errors.Is(err, ErrInvalidUsername), errors.Is(err, ErrExpiresInPast):
+		return status.Error(codes.InvalidArgument, i18n.Translate(i18n.Language(ctx), err.Error()))
+	}
+	log.Printf("auth: failed to manage service account '%s|%s': %s", id.GetSystem(), id.GetValue(), err)
+	return status.Errorf(codes.Internal, "could not manage service account: %s", err)
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// memoryUsers is a simple in-memory UserManager for testing
+type memoryUsers struct {
+	memoryRoles
+	hashes   map[string]string
+	accounts map[string]*apiv1.ServiceAccount
+}
+
+func newMemoryUsers() *memoryUsers {
+	return &memoryUsers{
+		memoryRoles: memoryRoles{roles: make(map[string][]string)},
+		hashes:      make(map[string]string),
+		accounts:    make(map[string]*apiv1.ServiceAccount),
+	}
+}
+
+func (m *memoryUsers) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	if err := bcrypt.CompareHashAndPassword([]byte(m.hashes[id.GetValue()]), []byte(credential)); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *memoryUsers) User(id *apiv1.Identifier) (*apiv1.ServiceAccount, error) {
+	sa, ok := m.accounts[id.GetValue()]
+	if !ok {
+		return nil, ErrNoSuchUser
+	}
+	return &apiv1.ServiceAccount{User: id, Roles: m.roles[id.GetValue()], Disabled: sa.Disabled, Expires: sa.Expires, Created: sa.Created, Rotated: sa.Rotated}, nil
+}
+
+func (m *memoryUsers) CreateUser(id *apiv1.Identifier, hash string, expires time.Time) error {
+	if _, exists := m.accounts[id.GetValue()]; exists {
+		return ErrUserExists
+	}
+	m.hashes[id.GetValue()] = hash
+	m.accounts[id.GetValue()] = &apiv1.ServiceAccount{Created: timestamppb.Now()}
+	if !expires.IsZero() {
+		m.accounts[id.GetValue()].Expires = timestamppb.New(expires)
+	}
+	return nil
+}
+
+func (m *memoryUsers) DisableUser(id *apiv1.Identifier) error {
+	sa, ok := m.accounts[id.GetValue()]
+	if !ok {
+		return ErrNoSuchUser
+	}
+	sa.Disabled = true
+	return nil
+}
+
+func (m *memoryUsers) SetPassword(id *apiv1.Identifier, hash string, expires time.Time) error {
+	sa, ok := m.accounts[id.GetValue()]
+	if !ok {
+		return ErrNoSuchUser
+	}
+	m.hashes[id.GetValue()] = hash
+	sa.Rotated = timestamppb.Now()
+	if !expires.IsZero() {
+		sa.Expires = timestamppb.New(expires)
+	}
+	return nil
+}
+
+func TestServiceAccounts(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	um := newMemoryUsers()
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-users", um, true)
+	admin := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "admin"}
+	sa, err := CreateServiceAccount(um, auth.policy, admin, []string{"admin"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := func(id *apiv1.Identifier, password string) (context.Context, string, error) {
+		r, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: id, Password: password})
+		if err != nil {
+			return nil, "", err
+		}
+		ucd, err := auth.parseToken(r.GetToken())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return context.WithValue(context.Background(), userContextKey, ucd), r.GetToken(), nil
+	}
+	ctx, _, err := login(admin, sa.GetPassword())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.authorize(ctx, "/apiv1.Authenticator/CreateServiceAccount"); err != nil {
+		t.Fatalf("admin should be able to create service accounts: %s", err)
+	}
+
+	epr := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "epr"}
+	if _, err := auth.CreateServiceAccount(ctx, &apiv1.CreateServiceAccountRequest{User: epr, Roles: []string{"invalid"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for unknown role. got: %v", err)
+	}
+	if _, err := auth.CreateServiceAccount(ctx, &apiv1.CreateServiceAccountRequest{User: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "epr user"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for invalid username. got: %v", err)
+	}
+	created, err := auth.CreateServiceAccount(ctx, &apiv1.CreateServiceAccountRequest{User: epr, Roles: []string{"publisher"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetPassword() == "" || len(created.GetRoles()) != 1 || created.GetRoles()[0] != "publisher" {
+		t.Fatalf("expected account with generated password and role. got: %v", created)
+	}
+	if _, err := auth.CreateServiceAccount(ctx, &apiv1.CreateServiceAccountRequest{User: epr}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected already exists for duplicate account. got: %v", err)
+	}
+	eprCtx, token, err := login(epr, created.GetPassword())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.authorize(eprCtx, "/apiv1.Authenticator/CreateServiceAccount"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("publisher should not be able to create service accounts. got: %v", err)
+	}
+
+	rotated, err := auth.RotateServiceAccountSecret(ctx, &apiv1.RotateSecretRequest{User: epr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.GetPassword() == created.GetPassword() || rotated.GetRotated() == nil {
+		t.Fatalf("expected new password after rotation. got: %v", rotated)
+	}
+	if _, _, err := login(epr, created.GetPassword()); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("old password should not authenticate after rotation. got: %v", err)
+	}
+	if _, _, err := login(epr, rotated.GetPassword()); err != nil {
+		t.Fatalf("new password should authenticate after rotation: %s", err)
+	}
+	if _, err := auth.parseToken(token); err != nil {
+		t.Fatalf("tokens should remain valid after rotation: %s", err)
+	}
+
+	disabled, err := auth.DisableServiceAccount(ctx, epr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !disabled.GetDisabled() {
+		t.Fatalf("expected disabled account. got: %v", disabled)
+	}
+	if _, _, err := login(epr, rotated.GetPassword()); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("disabled account should not authenticate. got: %v", err)
+	}
+	if _, err := auth.parseToken(token); err != ErrRevokedToken {
+		t.Fatalf("expected tokens revoked when account disabled. got: %v", err)
+	}
+	if _, err := auth.DisableServiceAccount(ctx, &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found for unknown account. got: %v", err)
+	}
+
+	expiring := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "expiring"}
+	if _, err := CreateServiceAccount(um, auth.policy, expiring, nil, time.Now().Add(-time.Hour)); err != ErrExpiresInPast {
+		t.Fatalf("expected error for expiry in the past. got: %v", err)
+	}
+	sa, err = CreateServiceAccount(um, auth.policy, expiring, nil, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _, err = login(expiring, sa.GetPassword())
+	if err != nil {
+		t.Fatalf("account should authenticate before expiry: %s", err)
+	}
+	if _, err := auth.Refresh(ctx, &apiv1.TokenRefreshRequest{}); err != nil {
+		t.Fatalf("token should refresh before expiry: %s", err)
+	}
+	um.accounts["expiring"].Expires = timestamppb.New(time.Now().Add(-time.Minute))
+	if _, _, err := login(expiring, sa.GetPassword()); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expired account should not authenticate. got: %v", err)
+	}
+	if _, err := auth.Refresh(ctx, &apiv1.TokenRefreshRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("token for expired account should not refresh. got: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// certificateUser returns user data for a service account authenticated using a verified client
+// certificate, or nil if there is no such certificate in the context.
+// The certificate is mapped to a service account using its first URI or DNS subject alternative name,
+// or its subject common name, in that order. As for a service account login, the account must exist and be
+// active if the provider manages accounts, and roles are determined in the same way.
+func (auth *Auth) certificateUser(ctx context.Context) *UserContextData {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if auth.gatewayCertificate != nil && auth.gatewayCertificate(cert.Raw) {
+		return nil // the gateway's own certificate does not authenticate requests made on behalf of others
+	}
+	value := certificateIdentity(cert)
+	if value == "" {
+		log.Printf("auth: client certificate '%s' has no name to map to a service account", cert.Subject)
+		return nil
+	}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: value}
+	revoked, err := auth.revoked.IsRevoked("", id.GetSystem()+"|"+id.GetValue(), cert.NotBefore)
+	if err != nil || revoked {
+		log.Printf("auth: client certificate for '%s|%s' rejected (revoked: %t, error: %v)", id.GetSystem(), id.GetValue(), revoked, err)
+		return nil
+	}
+	ap := auth.authProviders[identifiers.ConciergeServiceUser]
+	if um, ok := ap.(UserManager); ok {
+		sa, err := um.User(id)
+		if err == nil {
+			err = active(sa)
+		}
+		if err != nil {
+			log.Printf("auth: client certificate for '%s|%s' rejected: %s", id.GetSystem(), id.GetValue(), err)
+			return nil
+		}
+	}
+	roles, err := auth.roles(id, ap)
+	if err != nil {
+		log.Printf("auth: failed to determine roles for '%s|%s': %s", id.GetSystem(), id.GetValue(), err)
+		return nil
+	}
+	return &UserContextData{authenticatedUser: id, roles: roles}
+}
+
+// certificateIdentity returns the name used to identify the subject of a certificate
+func certificateIdentity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return cert.Subject.CommonName
+}
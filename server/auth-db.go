@@ -5,17 +5,38 @@ import (
 	"log"
 	"time"
 
-	_ "github.com/lib/pq"
-
+	"github.com/lib/pq"
 	"github.com/wardle/concierge/apiv1"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type dbAuthProvider struct {
 	db *sql.DB
 }
 
+var _ UserManager = (*dbAuthProvider)(nil)
+
+// createRolesTable creates the table of role assignments, if it does not already exist
+const createRolesTable = `CREATE TABLE IF NOT EXISTS user_roles (
+	username text NOT NULL,
+	role text NOT NULL,
+	PRIMARY KEY (username, role)
+)`
+
+// createUsersTable creates the table of users, if it does not already exist, adding the columns used to
+// manage accounts to tables created before accounts could be managed
+const createUsersTable = `CREATE TABLE IF NOT EXISTS users (
+	username text PRIMARY KEY,
+	password text NOT NULL
+);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled boolean NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE users ADD COLUMN IF NOT EXISTS rotated_at timestamptz`
+
 // NewDatabaseAuthProvider is an auth provider that uses a PostgreSQL database to validate credentials
+// and to store role assignments.
 func NewDatabaseAuthProvider(connStr string) (AuthProvider, error) {
 	for {
 		db, err := sql.Open("postgres", connStr)
@@ -26,9 +47,7 @@ func NewDatabaseAuthProvider(connStr string) (AuthProvider, error) {
 		if err != nil {
 			goto dberror
 		}
-		return &dbAuthProvider{
-			db: db,
-		}, nil
+		return newDatabaseAuthProvider(db)
 	dberror:
 		log.Println(err)
 		log.Println("auth: error connecting to the authentication database, retrying in 5 secs.")
@@ -36,6 +55,26 @@ func NewDatabaseAuthProvider(connStr string) (AuthProvider, error) {
 	}
 }
 
+// NewDatabaseUserManager opens a PostgreSQL authentication database in order to manage accounts, such as
+// from the command line, without retrying if the database is unavailable
+func NewDatabaseUserManager(connStr string) (UserManager, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	return newDatabaseAuthProvider(db)
+}
+
+func newDatabaseAuthProvider(db *sql.DB) (*dbAuthProvider, error) {
+	for _, stmt := range []string{createRolesTable, createUsersTable} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &dbAuthProvider{db: db}, nil
+}
+
 func (dba *dbAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
 	rows, err := dba.db.Query("SELECT password FROM users WHERE username=$1", id.GetValue())
 	if err != nil {
@@ -48,6 +87,9 @@ func (dba *dbAuthProvider) Authenticate(id *apiv1.Identifier, credential string)
 			return false, err
 		}
 		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(credential)); err != nil {
+			if err == bcrypt.ErrMismatchedHashAndPassword {
+				return false, nil
+			}
 			return false, err
 		}
 		return true, nil
@@ -58,3 +100,146 @@ func (dba *dbAuthProvider) Authenticate(id *apiv1.Identifier, credential string)
 	log.Printf("auth: no user found matching %s|%s", id.GetSystem(), id.GetValue())
 	return false, nil
 }
+
+// Roles returns the roles assigned to the user
+func (dba *dbAuthProvider) Roles(id *apiv1.Identifier) ([]string, error) {
+	rows, err := dba.db.Query("SELECT role FROM user_roles WHERE username=$1 ORDER BY role", id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// AssignRole assigns a role to the user
+func (dba *dbAuthProvider) AssignRole(id *apiv1.Identifier, role string) error {
+	_, err := dba.db.Exec("INSERT INTO user_roles (username, role) VALUES ($1, $2) ON CONFLICT DO NOTHING", id.GetValue(), role)
+	return err
+}
+
+// RevokeRole revokes a role from the user
+func (dba *dbAuthProvider) RevokeRole(id *apiv1.Identifier, role string) error {
+	_, err := dba.db.Exec("DELETE FROM user_roles WHERE username=$1 AND role=$2", id.GetValue(), role)
+	return err
+}
+
+// User returns the account
+func (dba *dbAuthProvider) User(id *apiv1.Identifier) (*apiv1.ServiceAccount, error) {
+	var disabled bool
+	var created time.Time
+	var expires, rotated pq.NullTime
+	err := dba.db.QueryRow("SELECT disabled, created_at, expires_at, rotated_at FROM users WHERE username=$1", id.GetValue()).Scan(&disabled, &created, &expires, &rotated)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoSuchUser
+	}
+	if err != nil {
+		return nil, err
+	}
+	roles, err := dba.Roles(id)
+	if err != nil {
+		return nil, err
+	}
+	sa := &apiv1.ServiceAccount{User: id, Roles: roles, Disabled: disabled}
+	sa.Created = timestamppb.New(created)
+	if expires.Valid {
+		sa.Expires = timestamppb.New(expires.Time)
+	}
+	if rotated.Valid {
+		sa.Rotated = timestamppb.New(rotated.Time)
+	}
+	return sa, nil
+}
+
+// CreateUser creates an account
+func (dba *dbAuthProvider) CreateUser(id *apiv1.Identifier, hash string, expires time.Time) error {
+	result, err := dba.db.Exec(`INSERT INTO users (username, password, expires_at) SELECT $1::text, $2::text, $3::timestamptz
+		WHERE NOT EXISTS (SELECT 1 FROM users WHERE username=$1)`, id.GetValue(), hash, nullTime(expires))
+	return affected(result, err, ErrUserExists)
+}
+
+// DisableUser disables an account
+func (dba *dbAuthProvider) DisableUser(id *apiv1.Identifier) error {
+	result, err := dba.db.Exec("UPDATE users SET disabled=true WHERE username=$1", id.GetValue())
+	return affected(result, err, ErrNoSuchUser)
+}
+
+// SetPassword replaces the password of an account, and its expiry unless zero
+func (dba *dbAuthProvider) SetPassword(id *apiv1.Identifier, hash string, expires time.Time) error {
+	result, err := dba.db.Exec("UPDATE users SET password=$2, rotated_at=now(), expires_at=COALESCE($3::timestamptz, expires_at) WHERE username=$1",
+		id.GetValue(), hash, nullTime(expires))
+	return affected(result, err, ErrNoSuchUser)
+}
+
+// affected returns the error specified if no rows were affected by a statement
+func affected(result sql.Result, err error, none error) error {
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return none
+	}
+	return nil
+}
+
+func nullTime(t time.Time) pq.NullTime {
+	return pq.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+type dbRevocationList struct {
+	db *sql.DB
+}
+
+// createRevocationTables creates the tables of revoked tokens and subjects, if they do not already exist
+const createRevocationTables = `CREATE TABLE IF NOT EXISTS revoked_tokens (
+	id text PRIMARY KEY,
+	expires_at timestamptz NOT NULL
+);
+CREATE TABLE IF NOT EXISTS revoked_subjects (
+	subject text PRIMARY KEY,
+	revoked_at timestamptz NOT NULL
+)`
+
+// NewDatabaseRevocationList creates a revocation list stored in a PostgreSQL database, so that
+// revocations persist across restarts and are shared by all server instances using the same database.
+func NewDatabaseRevocationList(connStr string) (RevocationList, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createRevocationTables); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &dbRevocationList{db: db}, nil
+}
+
+func (rl *dbRevocationList) RevokeToken(id string, expiresAt time.Time) error {
+	if _, err := rl.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < now()"); err != nil {
+		return err
+	}
+	_, err := rl.db.Exec("INSERT INTO revoked_tokens (id, expires_at) VALUES ($1, $2) ON CONFLICT DO NOTHING", id, expiresAt)
+	return err
+}
+
+func (rl *dbRevocationList) RevokeSubject(subject string, issuedBefore time.Time) error {
+	_, err := rl.db.Exec(`INSERT INTO revoked_subjects (subject, revoked_at) VALUES ($1, $2)
+		ON CONFLICT (subject) DO UPDATE SET revoked_at = GREATEST(revoked_subjects.revoked_at, EXCLUDED.revoked_at)`, subjectKey(subject), issuedBefore)
+	return err
+}
+
+func (rl *dbRevocationList) IsRevoked(id string, subject string, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	err := rl.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE id=$1 AND id<>'')
+		OR EXISTS(SELECT 1 FROM revoked_subjects WHERE subject=$2 AND revoked_at >= $3)`, id, subjectKey(subject), issuedAt).Scan(&revoked)
+	return revoked, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -15,7 +16,10 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/sethvargo/go-password/password"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/secrets"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -29,13 +33,20 @@ const serviceAccountTokenDuration = 72 * time.Hour
 var (
 	// ErrInvalidToken means that there was an invalid or missing authorization token
 	ErrInvalidToken = errors.New("invalid authorization token")
+	// ErrRevokedToken means that the authorization token has been revoked
+	ErrRevokedToken = errors.New("authorization token revoked")
 )
 
 // Auth is an authentication server
 type Auth struct {
-	jwtPrivatekey   *rsa.PrivateKey
+	keys            *keySet
 	authProviders   map[string]AuthProvider
 	serviceAccounts map[string]struct{}
+	policy          *Policy
+	revoked         RevocationList
+	failures        *loginFailures
+
+	gatewayCertificate func(raw []byte) bool // whether a client certificate is that used by the HTTP gateway, which is not mapped to a service account
 }
 
 // AuthProvider is a mechanism for plugging in modular authentication schemes
@@ -44,6 +55,19 @@ type AuthProvider interface {
 	Authenticate(id *apiv1.Identifier, credential string) (bool, error)
 }
 
+// RoleProvider is an AuthProvider that can also provide the roles assigned to a user.
+// Users without assigned roles are given the default roles defined by the access control policy.
+type RoleProvider interface {
+	Roles(id *apiv1.Identifier) ([]string, error)
+}
+
+// RoleManager is a RoleProvider that also permits management of role assignments
+type RoleManager interface {
+	RoleProvider
+	AssignRole(id *apiv1.Identifier, role string) error
+	RevokeRole(id *apiv1.Identifier, role string) error
+}
+
 // NewAuthenticationServer creates a new authentication server that can issue JWT tokens
 func NewAuthenticationServer(rsaPrivateKey string) (*Auth, error) {
 	key, err := ioutil.ReadFile(rsaPrivateKey)
@@ -54,19 +78,27 @@ func NewAuthenticationServer(rsaPrivateKey string) (*Auth, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing jwt private key: %w", err)
 	}
+	log.Printf("auth: signing tokens using key '%s' from '%s'", keyID(&parsedKey.PublicKey), rsaPrivateKey)
 	return &Auth{
-		jwtPrivatekey: parsedKey,
-		authProviders: make(map[string]AuthProvider),
+		keys:            newKeySet(parsedKey),
+		authProviders:   make(map[string]AuthProvider),
+		serviceAccounts: make(map[string]struct{}),
+		policy:          DefaultPolicy,
+		revoked:         NewMemoryRevocationList(),
+		failures:        newLoginFailures(DefaultLoginLimits),
 	}, nil
 }
 
 // NewAuthenticationServerWithTemporaryKey creates a new authentication server using an emphemeral private/public key pair
 func NewAuthenticationServerWithTemporaryKey() (*Auth, error) {
 	auth := new(Auth)
-	var err error
-	auth.jwtPrivatekey, err = rsa.GenerateKey(rand.Reader, 2048)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	auth.keys = newKeySet(key)
 	auth.authProviders = make(map[string]AuthProvider)
 	auth.serviceAccounts = make(map[string]struct{})
+	auth.policy = DefaultPolicy
+	auth.revoked = NewMemoryRevocationList()
+	auth.failures = newLoginFailures(DefaultLoginLimits)
 	return auth, err
 }
 
@@ -85,6 +117,16 @@ func (auth *Auth) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux,
 // Close closes any linked resources
 func (auth *Auth) Close() error { return nil }
 
+// SetPolicy sets the access control policy, determining the scopes required for each method
+func (auth *Auth) SetPolicy(p *Policy) {
+	auth.policy = p
+}
+
+// SetRevocationList sets the list used to record and check revoked tokens
+func (auth *Auth) SetRevocationList(rl RevocationList) {
+	auth.revoked = rl
+}
+
 // RegisterAuthProvider registers an authentication provider for the given
 func (auth *Auth) RegisterAuthProvider(uri string, name string, ap AuthProvider, service bool) {
 	if _, exists := auth.authProviders[uri]; exists {
@@ -102,11 +144,12 @@ func (auth *Auth) RegisterAuthProvider(uri string, name string, ap AuthProvider,
 // A service user login is currently performed using a user key and secret key, but could itself be from a third-party
 // token in the future, depending on the namespace chosen.
 func (auth *Auth) Login(ctx context.Context, r *apiv1.LoginRequest) (*apiv1.LoginResponse, error) {
-	if auth.jwtPrivatekey == nil {
+	if key, _ := auth.keys.signingKey(); key == nil {
 		return nil, status.Errorf(codes.Internal, "no private key specified for signing jwt token")
 	}
 	if _, found := auth.authProviders[r.GetUser().GetSystem()]; !found {
 		log.Printf("auth: failed login attempt: unsupported namespace: '%s|%s'", r.GetUser().GetSystem(), r.GetUser().GetValue())
+		metrics.Login("unsupported", "failure") // don't use requested system, as unbounded
 		return nil, status.Errorf(codes.Unauthenticated, "auth: unable to provide authentication for namespace uri '%s'", r.GetUser().GetSystem())
 	}
 	ap := auth.authProviders[r.GetUser().GetSystem()]
@@ -115,24 +158,55 @@ func (auth *Auth) Login(ctx context.Context, r *apiv1.LoginRequest) (*apiv1.Logi
 		ucd := GetContextData(ctx) // if ucd is nil, the next statement will still return false
 		if _, isService = auth.serviceAccounts[ucd.GetAuthenticatedUser().GetSystem()]; !isService {
 			log.Printf("auth: attempt to login without service account")
-			return nil, status.Errorf(codes.Unauthenticated, "need service account login before logging in using normal user account")
+			return nil, i18n.Errorf(ctx, codes.Unauthenticated, "need service account login before logging in using normal user account")
 		}
 	}
+	if err := auth.CheckLogin(ctx, r.GetUser()); err != nil {
+		log.Printf("auth: login refused for '%s|%s' after failed logins: %s", r.GetUser().GetSystem(), r.GetUser().GetValue(), err)
+		metrics.Login(r.GetUser().GetSystem(), "locked")
+		return nil, err
+	}
 	success, err := ap.Authenticate(r.GetUser(), r.GetPassword())
 	if err != nil {
-		log.Printf("auth: failed to authenticate: %s", err)
-		return nil, status.Errorf(codes.Unauthenticated, "failed to authenticate: %s", err)
+		// providers such as NADEX cannot always distinguish invalid credentials from other errors, so this is
+		// counted as a failed login, and the error, which may reveal whether the account exists, is not returned
+		log.Printf("auth: failed to authenticate '%s|%s': %s", r.GetUser().GetSystem(), r.GetUser().GetValue(), err)
+		metrics.Login(r.GetUser().GetSystem(), "error")
+		auth.LoginFailed(ctx, r.GetUser())
+		return nil, i18n.Errorf(ctx, codes.Unauthenticated, "failed to authenticate")
 	}
 	if !success {
 		log.Printf("auth: invalid credentials for '%s|%s'", r.GetUser().GetSystem(), r.GetUser().GetValue())
-		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
+		metrics.Login(r.GetUser().GetSystem(), "failure")
+		auth.LoginFailed(ctx, r.GetUser())
+		return nil, i18n.Errorf(ctx, codes.Unauthenticated, "invalid credentials")
+	}
+	if um, ok := ap.(UserManager); ok {
+		sa, err := um.User(r.GetUser())
+		if err == nil {
+			err = active(sa)
+		}
+		if err != nil {
+			log.Printf("auth: login refused for '%s|%s': %s", r.GetUser().GetSystem(), r.GetUser().GetValue(), err)
+			metrics.Login(r.GetUser().GetSystem(), "failure")
+			auth.LoginFailed(ctx, r.GetUser())
+			return nil, i18n.Errorf(ctx, codes.Unauthenticated, "invalid credentials")
+		}
+	}
+	metrics.Login(r.GetUser().GetSystem(), "success")
+	auth.LoginSucceeded(r.GetUser())
+	roles, err := auth.roles(r.GetUser(), ap)
+	if err != nil {
+		log.Printf("auth: failed to determine roles for '%s|%s': %s", r.GetUser().GetSystem(), r.GetUser().GetValue(), err)
+		return nil, status.Errorf(codes.Internal, "could not determine roles: %s", err)
 	}
 	tokenDuration := defaultTokenDuration
 	if r.GetUser().GetSystem() == identifiers.ConciergeServiceUser {
 		tokenDuration = serviceAccountTokenDuration
 	}
-	log.Printf("auth: generated authentication token for %s|%s: %v", r.GetUser().GetSystem(), r.GetUser().GetValue(), tokenDuration)
-	ss, err := auth.generateToken(r.GetUser(), tokenDuration)
+	practitioner := practitionerClaims(ctx, r.GetUser(), ap)
+	log.Printf("auth: generated authentication token for %s|%s: %v (roles: %v, practitioner: %+v)", r.GetUser().GetSystem(), r.GetUser().GetValue(), tokenDuration, roles, practitioner)
+	ss, err := auth.generateToken(r.GetUser(), roles, practitioner, tokenDuration)
 	if err != nil {
 		log.Printf("auth: failed to generate token: %s", err)
 		return nil, status.Errorf(codes.Internal, "could not generate token: %s", err)
@@ -144,6 +218,17 @@ func (auth *Auth) Login(ctx context.Context, r *apiv1.LoginRequest) (*apiv1.Logi
 // Refresh refreshes an authenitcation token
 func (auth *Auth) Refresh(ctx context.Context, r *apiv1.TokenRefreshRequest) (*apiv1.LoginResponse, error) {
 	ucd := GetContextData(ctx)
+	// accounts may have been disabled, or have expired, since the token was issued
+	if um, ok := auth.authProviders[ucd.GetAuthenticatedUser().GetSystem()].(UserManager); ok {
+		sa, err := um.User(ucd.GetAuthenticatedUser())
+		if err == nil {
+			err = active(sa)
+		}
+		if err != nil {
+			log.Printf("auth: refresh refused for '%s|%s': %s", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), err)
+			return nil, i18n.Errorf(ctx, codes.Unauthenticated, "account not active")
+		}
+	}
 	// do we really need to refresh token? send old one back if there is plenty of time
 	remaining := ucd.GetTokenExpiresAt().Sub(time.Now())
 	if remaining > 5*time.Minute {
@@ -154,7 +239,13 @@ func (auth *Auth) Refresh(ctx context.Context, r *apiv1.TokenRefreshRequest) (*a
 	if ucd.authenticatedUser.GetSystem() == identifiers.ConciergeServiceUser {
 		tokenDuration = serviceAccountTokenDuration
 	}
-	ss, err := auth.generateToken(ucd.authenticatedUser, tokenDuration)
+	// roles may have been assigned or revoked since the token was issued
+	roles, err := auth.roles(ucd.authenticatedUser, auth.authProviders[ucd.authenticatedUser.GetSystem()])
+	if err != nil {
+		log.Printf("auth: failed to determine roles for '%s|%s': %s", ucd.authenticatedUser.GetSystem(), ucd.authenticatedUser.GetValue(), err)
+		return nil, status.Errorf(codes.Internal, "could not determine roles: %s", err)
+	}
+	ss, err := auth.generateToken(ucd.authenticatedUser, roles, ucd.practitioner, tokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "could not generate token: %s", err)
 	}
@@ -162,14 +253,142 @@ func (auth *Auth) Refresh(ctx context.Context, r *apiv1.TokenRefreshRequest) (*a
 	return &apiv1.LoginResponse{Token: ss}, nil
 }
 
-func (auth *Auth) generateToken(id *apiv1.Identifier, duration time.Duration) (string, error) {
-	claims := &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(duration).Unix(),
-		IssuedAt:  time.Now().Unix(),
-		Subject:   id.GetSystem() + "|" + id.GetValue(),
+// Logout revokes the current token or, if requested, all tokens issued to the authenticated user
+func (auth *Auth) Logout(ctx context.Context, r *apiv1.LogoutRequest) (*apiv1.LogoutResponse, error) {
+	ucd := GetContextData(ctx)
+	if ucd == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "not logged in")
+	}
+	user := ucd.GetAuthenticatedUser()
+	var err error
+	if r.GetAllSessions() || ucd.tokenID == "" {
+		err = auth.revoked.RevokeSubject(user.GetSystem()+"|"+user.GetValue(), time.Now())
+	}
+	if err == nil && ucd.tokenID != "" {
+		err = auth.revoked.RevokeToken(ucd.tokenID, ucd.tokenExpiresAt)
+	}
+	if err != nil {
+		log.Printf("auth: failed to revoke token for '%s|%s': %s", user.GetSystem(), user.GetValue(), err)
+		return nil, status.Errorf(codes.Internal, "could not logout: %s", err)
+	}
+	log.Printf("auth: logout for '%s|%s' (all sessions: %t)", user.GetSystem(), user.GetValue(), r.GetAllSessions())
+	return &apiv1.LogoutResponse{}, nil
+}
+
+// GetRoles returns the roles assigned to a service account
+func (auth *Auth) GetRoles(ctx context.Context, id *apiv1.Identifier) (*apiv1.RoleAssignments, error) {
+	rm, err := auth.roleManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return auth.roleAssignments(id, rm)
+}
+
+// AssignRole assigns a role to a service account
+func (auth *Auth) AssignRole(ctx context.Context, r *apiv1.RoleAssignment) (*apiv1.RoleAssignments, error) {
+	rm, err := auth.roleManager(ctx, r.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	if !auth.policy.IsRole(r.GetRole()) {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "unknown role '%s': expected one of %v", r.GetRole(), auth.policy.RoleNames())
+	}
+	if err := rm.AssignRole(r.GetUser(), r.GetRole()); err != nil {
+		return nil, status.Errorf(codes.Internal, "could not assign role: %s", err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("auth: role '%s' assigned to '%s|%s' by '%s|%s'", r.GetRole(), r.GetUser().GetSystem(), r.GetUser().GetValue(), user.GetSystem(), user.GetValue())
+	return auth.roleAssignments(r.GetUser(), rm)
+}
+
+// RevokeRole revokes a role from a service account
+func (auth *Auth) RevokeRole(ctx context.Context, r *apiv1.RoleAssignment) (*apiv1.RoleAssignments, error) {
+	rm, err := auth.roleManager(ctx, r.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	if err := rm.RevokeRole(r.GetUser(), r.GetRole()); err != nil {
+		return nil, status.Errorf(codes.Internal, "could not revoke role: %s", err)
+	}
+	// tokens already issued record the role, so revoke them in order that the user must login again
+	if err := auth.revoked.RevokeSubject(r.GetUser().GetSystem()+"|"+r.GetUser().GetValue(), time.Now()); err != nil {
+		return nil, status.Errorf(codes.Internal, "could not revoke tokens: %s", err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("auth: role '%s' revoked from '%s|%s' by '%s|%s'", r.GetRole(), r.GetUser().GetSystem(), r.GetUser().GetValue(), user.GetSystem(), user.GetValue())
+	return auth.roleAssignments(r.GetUser(), rm)
+}
+
+// roleManager returns the role manager for the namespace of the user specified
+func (auth *Auth) roleManager(ctx context.Context, id *apiv1.Identifier) (RoleManager, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "missing user")
+	}
+	if rm, ok := auth.authProviders[id.GetSystem()].(RoleManager); ok {
+		return rm, nil
+	}
+	return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "roles cannot be managed for namespace '%s'", id.GetSystem())
+}
+
+func (auth *Auth) roleAssignments(id *apiv1.Identifier, rm RoleManager) (*apiv1.RoleAssignments, error) {
+	roles, err := rm.Roles(id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not fetch roles: %s", err)
 	}
+	return &apiv1.RoleAssignments{User: id, Roles: roles}, nil
+}
+
+// roles returns the roles for the user, from the authentication provider if it supports roles,
+// or the default roles from the policy otherwise
+func (auth *Auth) roles(id *apiv1.Identifier, ap AuthProvider) ([]string, error) {
+	if rp, ok := ap.(RoleProvider); ok {
+		roles, err := rp.Roles(id)
+		if err != nil || len(roles) > 0 {
+			return roles, err
+		}
+	}
+	return auth.policy.DefaultRoles(id.GetSystem()), nil
+}
+
+// claims are the claims within a JWT token issued by concierge
+type claims struct {
+	jwt.StandardClaims
+	Roles        []string            `json:"roles,omitempty"`
+	Practitioner *PractitionerClaims `json:"practitioner,omitempty"`
+	// IssuedAtMicro is the issue time in microseconds, as iat is only to the second, and so cannot
+	// distinguish tokens issued before a subject's tokens are revoked from those issued after in the same second
+	IssuedAtMicro int64 `json:"iat_us,omitempty"`
+}
+
+// issuedAt returns the time the token was issued
+func (c *claims) issuedAt() time.Time {
+	if c.IssuedAtMicro != 0 {
+		return time.Unix(0, c.IssuedAtMicro*int64(time.Microsecond))
+	}
+	return time.Unix(c.IssuedAt, 0)
+}
+
+func (auth *Auth) generateToken(id *apiv1.Identifier, roles []string, practitioner *PractitionerClaims, duration time.Duration) (string, error) {
+	tokenID := make([]byte, 16)
+	if _, err := rand.Read(tokenID); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        hex.EncodeToString(tokenID),
+			ExpiresAt: now.Add(duration).Unix(),
+			IssuedAt:  now.Unix(),
+			Subject:   id.GetSystem() + "|" + id.GetValue(),
+		},
+		Roles:         roles,
+		Practitioner:  practitioner,
+		IssuedAtMicro: now.UnixNano() / int64(time.Microsecond),
+	}
+	key, kid := auth.keys.signingKey()
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(auth.jwtPrivatekey)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
 }
 
 func (auth *Auth) parseToken(token string) (*UserContextData, error) {
@@ -177,23 +396,41 @@ func (auth *Auth) parseToken(token string) (*UserContextData, error) {
 	if strings.HasPrefix(token, bearerSchema) {
 		token = token[len(bearerSchema):]
 	}
-	jwtToken, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, func(t *jwt.Token) (interface{}, error) {
+	jwtToken, err := jwt.ParseWithClaims(token, &claims{}, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
 			log.Printf("auth: unexpected signing method: %v", t.Header["alg"])
 			return nil, ErrInvalidToken
 		}
-		return &auth.jwtPrivatekey.PublicKey, nil
+		kid, _ := t.Header["kid"].(string)
+		key, ok := auth.keys.verificationKey(kid)
+		if !ok {
+			log.Printf("auth: unknown signing key: %s", kid)
+			return nil, ErrInvalidToken
+		}
+		return key, nil
 	})
 	if err == nil && jwtToken.Valid {
-		claims := jwtToken.Claims.(*jwt.StandardClaims)
+		claims := jwtToken.Claims.(*claims)
 		cd := new(UserContextData)
 		ids := strings.Split(claims.Subject, "|")
 		if len(ids) != 2 {
 			return nil, ErrInvalidToken
 		}
+		revoked, err := auth.revoked.IsRevoked(claims.Id, claims.Subject, claims.issuedAt())
+		if err != nil {
+			log.Printf("auth: failed to check token revocation: %s", err)
+			return nil, err
+		}
+		if revoked {
+			log.Printf("auth: revoked token used for '%s'", claims.Subject)
+			return nil, ErrRevokedToken
+		}
 		cd.authenticatedUser = &apiv1.Identifier{System: ids[0], Value: ids[1]}
+		cd.tokenID = claims.Id
 		cd.token = token
 		cd.tokenExpiresAt = time.Unix(claims.ExpiresAt, 0)
+		cd.roles = claims.Roles
+		cd.practitioner = claims.Practitioner
 		return cd, nil
 	}
 	log.Printf("auth: invalid token: %s", err)
@@ -211,7 +448,10 @@ const (
 type UserContextData struct {
 	authenticatedUser *apiv1.Identifier
 	token             string
+	tokenID           string
 	tokenExpiresAt    time.Time
+	roles             []string
+	practitioner      *PractitionerClaims
 }
 
 // GetAuthenticatedUser returns the authenticated user, guarding against nils
@@ -230,23 +470,75 @@ func (ucd *UserContextData) GetTokenExpiresAt() time.Time {
 	return ucd.tokenExpiresAt
 }
 
+// GetRoles returns the roles of the authenticated user, guarding against nils
+func (ucd *UserContextData) GetRoles() []string {
+	if ucd == nil {
+		return nil
+	}
+	return ucd.roles
+}
+
 // endpoints that do not need authentication
 var noAuthEndpoints = map[string]struct{}{
 	"/apiv1.Authenticator/Login":   struct{}{},
 	"/grpc.health.v1.Health/Check": struct{}{},
+	"/grpc.health.v1.Health/Watch": struct{}{},
+	// the API schema is not protected, as is the case for the OpenAPI definition
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": struct{}{},
 }
 
 // unaryAuthInterceptor provides an interceptor that ensures we have an authenticated user
 func (sv *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	ctx, err := sv.auth.contextWithUserData(ctx)
 	if err == nil {
+		if err := sv.auth.authorize(ctx, info.FullMethod); err != nil {
+			return nil, sv.rejected(ctx, info.FullMethod, req, err)
+		}
 		return handler(ctx, req)
 	}
 	if _, found := noAuthEndpoints[info.FullMethod]; found { // is this endpoint in our list of unprotected endpoints?
 		return handler(ctx, req)
 	}
 	log.Printf("server: unauthenticated call to '%s': %s", info.FullMethod, err)
-	return nil, status.Errorf(codes.Unauthenticated, "unauthenticated: %s", err)
+	return nil, sv.rejected(ctx, info.FullMethod, req, status.Errorf(codes.Unauthenticated, "unauthenticated: %s", err))
+}
+
+// rejected records a call rejected by authentication or authorisation with the registered auditor, if any,
+// returning the error
+func (sv *Server) rejected(ctx context.Context, method string, req interface{}, err error) error {
+	if sv.auditor != nil {
+		sv.auditor.Rejected(ctx, method, req, err)
+	}
+	return err
+}
+
+// authorize checks that the authenticated user has a role granting the scope required for the method
+func (auth *Auth) authorize(ctx context.Context, method string) error {
+	scope := auth.policy.RequiredScope(method)
+	ucd := GetContextData(ctx)
+	if auth.policy.Permitted(ucd.GetRoles(), scope) {
+		return nil
+	}
+	log.Printf("server: permission denied for '%s|%s' calling '%s': requires scope '%s' (roles: %v)",
+		ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), method, scope, ucd.GetRoles())
+	return i18n.Errorf(ctx, codes.PermissionDenied, "permission denied: requires scope '%s'", scope)
+}
+
+// AuthorizeAccount checks that the authenticated user may view or change the account specified in a back-end
+// directory, such as NADEX. Users may only manage their own account, unless they have a role granting the
+// account administration scope.
+func (auth *Auth) AuthorizeAccount(ctx context.Context, id *apiv1.Identifier) error {
+	ucd := GetContextData(ctx)
+	user := ucd.GetAuthenticatedUser()
+	if user.GetValue() != "" && user.GetSystem() == id.GetSystem() && strings.EqualFold(user.GetValue(), id.GetValue()) {
+		return nil
+	}
+	if auth.policy.Permitted(ucd.GetRoles(), ScopeAccountAdmin) {
+		return nil
+	}
+	log.Printf("server: permission denied for '%s|%s' to account '%s|%s': requires scope '%s' (roles: %v)",
+		user.GetSystem(), user.GetValue(), id.GetSystem(), id.GetValue(), ScopeAccountAdmin, ucd.GetRoles())
+	return i18n.Errorf(ctx, codes.PermissionDenied, "permission denied: requires scope '%s'", ScopeAccountAdmin)
 }
 
 // wrappedStream wraps around the embedded grpc.ServerStream, and intercepts the RecvMsg and
@@ -271,10 +563,15 @@ func (w *wrappedStream) SendMsg(m interface{}) error {
 func (sv *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	ctx, err := sv.auth.contextWithUserData(ss.Context())
 	if err != nil {
-		return err
+		if _, found := noAuthEndpoints[info.FullMethod]; found {
+			return handler(srv, ss)
+		}
+		return sv.rejected(ctx, info.FullMethod, nil, err)
+	}
+	if err := sv.auth.authorize(ctx, info.FullMethod); err != nil {
+		return sv.rejected(ctx, info.FullMethod, nil, err)
 	}
 	ucd := GetContextData(ctx)
-	ucd.GetAuthenticatedUser()
 	err = handler(srv, &wrappedStream{ss, ucd})
 	if err != nil {
 		log.Printf("auth: streaming failed with error: %v", err)
@@ -283,14 +580,15 @@ func (sv *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, i
 }
 
 // contextWithUserData returns a new context containing UserContextData specifically
-//  returning the old context in the event of an error
+//
+//	returning the old context in the event of an error
 func (auth *Auth) contextWithUserData(ctx context.Context) (context.Context, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return ctx, fmt.Errorf("invalid token")
-	}
+	md, _ := metadata.FromIncomingContext(ctx)
 	tokenString, ok := md["authorization"]
 	if !ok {
+		if user := auth.certificateUser(ctx); user != nil {
+			return context.WithValue(ctx, userContextKey, user), nil
+		}
 		return ctx, fmt.Errorf("invalid token")
 	}
 	user, err := auth.parseToken(tokenString[0])
@@ -325,16 +623,25 @@ func GenerateCredentials() (string, string, error) {
 }
 
 type singleAuthProvider struct {
-	hash string
+	hash secrets.Secret
 }
 
 // NewSingleAuthProvider creates an authprovider for a static single password
 func NewSingleAuthProvider(hash string) AuthProvider {
+	return NewSecretAuthProvider(secrets.Static(hash))
+}
+
+// NewSecretAuthProvider creates an authprovider for a single password, with a bcrypt hash that may be
+// rotated while running
+func NewSecretAuthProvider(hash secrets.Secret) AuthProvider {
 	return &singleAuthProvider{hash: hash}
 }
 
 func (ap *singleAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
-	if err := bcrypt.CompareHashAndPassword([]byte(ap.hash), []byte(credential)); err != nil {
+	if err := bcrypt.CompareHashAndPassword([]byte(ap.hash.Value()), []byte(credential)); err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
 		return false, err
 	}
 	return true, nil
@@ -1,12 +1,23 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/wardle/concierge/apiv1"
 	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 func TestServiceLogin(t *testing.T) {
@@ -40,3 +51,343 @@ func TestServiceLogin(t *testing.T) {
 		t.Fatalf("did not get correct system/value identifier from token. got: %s|%s", user.authenticatedUser.GetSystem(), user.authenticatedUser.GetValue())
 	}
 }
+
+// memoryRoles is a simple in-memory RoleManager for testing
+type memoryRoles struct {
+	AuthProvider
+	roles map[string][]string
+}
+
+func (m *memoryRoles) Roles(id *apiv1.Identifier) ([]string, error) {
+	return m.roles[id.GetValue()], nil
+}
+
+func (m *memoryRoles) AssignRole(id *apiv1.Identifier, role string) error {
+	m.roles[id.GetValue()] = append(m.roles[id.GetValue()], role)
+	return nil
+}
+
+func (m *memoryRoles) RevokeRole(id *apiv1.Identifier, role string) error {
+	roles := m.roles[id.GetValue()][:0]
+	for _, r := range m.roles[id.GetValue()] {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+	m.roles[id.GetValue()] = roles
+	return nil
+}
+
+func TestRoles(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := &memoryRoles{AuthProvider: NewSingleAuthProvider(hash), roles: map[string][]string{"admin": {"admin"}}}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-roles", rm, true)
+	login := func(username string) context.Context {
+		r, err := auth.Login(context.Background(), &apiv1.LoginRequest{
+			User:     &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: username},
+			Password: password,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ucd, err := auth.parseToken(r.GetToken())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return context.WithValue(context.Background(), userContextKey, ucd)
+	}
+	service := login("service") // no roles assigned, so given default role
+	if err := auth.authorize(service, "/apiv1.PatientDirectory/GetPatient"); err != nil {
+		t.Fatalf("service account should be able to read patients: %s", err)
+	}
+	if err := auth.authorize(service, "/apiv1.Authenticator/AssignRole"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("service account should not be able to assign roles. got: %v", err)
+	}
+	if err := auth.authorize(service, "/apiv1.Authenticator/Refresh"); err != nil {
+		t.Fatalf("any authenticated user should be able to refresh token: %s", err)
+	}
+	admin := login("admin")
+	if err := auth.authorize(admin, "/apiv1.Authenticator/AssignRole"); err != nil {
+		t.Fatalf("admin should be able to assign roles: %s", err)
+	}
+	if _, err := auth.AssignRole(admin, &apiv1.RoleAssignment{User: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "service"}, Role: "invalid"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for unknown role. got: %v", err)
+	}
+	ra, err := auth.AssignRole(admin, &apiv1.RoleAssignment{User: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "service"}, Role: "publisher"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ra.GetRoles()) != 1 || ra.GetRoles()[0] != "publisher" {
+		t.Fatalf("expected role assignment. got: %v", ra.GetRoles())
+	}
+	publisher := login("service")
+	if err := auth.authorize(publisher, "/apiv1.PatientDirectory/GetPatient"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("publisher should not be able to read patients. got: %v", err)
+	}
+	if err := auth.authorize(publisher, "/apiv1.DocumentService/PublishDocument"); err != nil {
+		t.Fatalf("publisher should be able to publish documents: %s", err)
+	}
+	if _, err := auth.RevokeRole(admin, &apiv1.RoleAssignment{User: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "admin"}, Role: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	ucd := GetContextData(admin)
+	if _, err := auth.parseToken(ucd.token); err != ErrRevokedToken {
+		t.Fatalf("expected tokens revoked after role revoked. got: %v", err)
+	}
+	ucd.tokenExpiresAt = time.Now().Add(time.Minute)
+	r, err := auth.Refresh(admin, &apiv1.TokenRefreshRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ucd, err = auth.parseToken(r.GetToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed := context.WithValue(context.Background(), userContextKey, ucd)
+	if err := auth.authorize(refreshed, "/apiv1.Authenticator/AssignRole"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("refreshed token should not have revoked role. got: %v", err)
+	}
+}
+
+func TestAuthorizeAccount(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := func(id *apiv1.Identifier, roles ...string) context.Context {
+		return context.WithValue(context.Background(), userContextKey, &UserContextData{authenticatedUser: id, roles: roles})
+	}
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	other := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ru054321"}
+	if err := auth.AuthorizeAccount(as(user, "clinician"), &apiv1.Identifier{System: identifiers.CymruUserID, Value: "MA090906"}); err != nil {
+		t.Fatalf("user should be able to manage their own account: %s", err)
+	}
+	if err := auth.AuthorizeAccount(as(user, "clinician"), other); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("user should not be able to manage the account of another user. got: %v", err)
+	}
+	if err := auth.AuthorizeAccount(as(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "ma090906"}, "service"), user); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("user from another namespace should not be able to manage account. got: %v", err)
+	}
+	if err := auth.AuthorizeAccount(context.Background(), user); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("unauthenticated caller should not be able to manage account. got: %v", err)
+	}
+	if err := auth.AuthorizeAccount(as(other, "admin"), user); err != nil {
+		t.Fatalf("admin should be able to manage the account of another user: %s", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-single", NewSingleAuthProvider(hash), true)
+	login := func() string {
+		r, err := auth.Login(context.Background(), &apiv1.LoginRequest{
+			User:     &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "a123456789"},
+			Password: password,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.GetToken()
+	}
+	token1, token2 := login(), login()
+	ucd, err := auth.parseToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Logout(context.WithValue(context.Background(), userContextKey, ucd), &apiv1.LogoutRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.parseToken(token1); err != ErrRevokedToken {
+		t.Fatalf("expected revoked token after logout. got: %v", err)
+	}
+	if _, err := auth.parseToken(token2); err != nil {
+		t.Fatalf("other session should remain valid after logout: %s", err)
+	}
+	token3 := login()
+	ucd, err = auth.parseToken(token3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Logout(context.WithValue(context.Background(), userContextKey, ucd), &apiv1.LogoutRequest{AllSessions: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.parseToken(token2); err != ErrRevokedToken {
+		t.Fatalf("expected all sessions revoked. got: %v", err)
+	}
+	if _, err := auth.parseToken(token3); err != ErrRevokedToken {
+		t.Fatalf("expected current session revoked. got: %v", err)
+	}
+	if _, err := auth.parseToken(login()); err != nil {
+		t.Fatalf("login again immediately after logout should be valid: %s", err)
+	}
+}
+
+func TestRevokeSubject(t *testing.T) {
+	rl := NewMemoryRevocationList()
+	revokedAt := time.Now()
+	if err := rl.RevokeSubject(identifiers.CymruUserID+"|MA090906 ", revokedAt); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		subject  string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{identifiers.CymruUserID + "|ma090906", revokedAt.Add(-time.Hour), true},
+		{identifiers.CymruUserID + "|Ma090906", revokedAt.Truncate(time.Second), true}, // issued earlier in the same second
+		{identifiers.CymruUserID + "|MA090906", revokedAt, true},
+		{identifiers.CymruUserID + "|ma090906", revokedAt.Add(time.Microsecond), false},
+		{identifiers.ConciergeServiceUser + "|ma090906", revokedAt.Add(-time.Hour), false},
+	}
+	for _, test := range tests {
+		if revoked, err := rl.IsRevoked("", test.subject, test.issuedAt); err != nil || revoked != test.revoked {
+			t.Errorf("'%s' issued at %v: expected revoked: %t, got: %t (%v)", test.subject, test.issuedAt, test.revoked, revoked, err)
+		}
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := auth.generateToken(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "a123456789"}, nil, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RevokeToken(auth.revoked, "Bearer "+token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.parseToken(token); err != ErrRevokedToken {
+		t.Fatalf("expected revoked token. got: %v", err)
+	}
+}
+
+func TestCertificateUser(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "my-service"}, NotBefore: time.Now().Add(-time.Hour), Raw: []byte("cert")}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+	ctx, err = auth.contextWithUserData(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	if user.GetSystem() != identifiers.ConciergeServiceUser || user.GetValue() != "my-service" {
+		t.Fatalf("client certificate not mapped to service account. got: %s|%s", user.GetSystem(), user.GetValue())
+	}
+	if err := auth.authorize(ctx, "/apiv1.PatientDirectory/GetPatient"); err != nil {
+		t.Fatalf("service account should have default roles: %s", err)
+	}
+	auth.revoked.RevokeSubject(identifiers.ConciergeServiceUser+"|my-service", time.Now())
+	if _, err := auth.contextWithUserData(ctx); err == nil {
+		t.Fatal("revoked client certificate should not authenticate")
+	}
+	auth.gatewayCertificate = func(raw []byte) bool { return bytes.Equal(raw, cert.Raw) }
+	if u := auth.certificateUser(ctx); u != nil {
+		t.Fatal("gateway certificate should not authenticate")
+	}
+}
+
+func TestCertificateUserAccount(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	um := newMemoryUsers()
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-users", um, true)
+	certificate := func(name string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}, NotBefore: time.Now().Add(-time.Hour), Raw: []byte(name)}
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+		})
+	}
+	if u := auth.certificateUser(certificate("unknown")); u != nil {
+		t.Fatal("client certificate for unknown account should not authenticate")
+	}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "my-service"}
+	if _, err := CreateServiceAccount(um, auth.policy, id, nil, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if u := auth.certificateUser(certificate("my-service")); u.GetAuthenticatedUser().GetValue() != "my-service" {
+		t.Fatalf("client certificate for active account should authenticate. got: %v", u.GetAuthenticatedUser())
+	}
+	if err := um.DisableUser(id); err != nil {
+		t.Fatal(err)
+	}
+	if u := auth.certificateUser(certificate("my-service")); u != nil {
+		t.Fatal("client certificate for disabled account should not authenticate")
+	}
+}
+
+// rejection is a call rejected by authentication or authorisation
+type rejection struct {
+	user   *apiv1.Identifier
+	method string
+	code   codes.Code
+}
+
+// rejections is an Auditor recording the calls rejected
+type rejections []rejection
+
+func (r *rejections) Rejected(ctx context.Context, method string, req interface{}, err error) {
+	*r = append(*r, rejection{GetContextData(ctx).GetAuthenticatedUser(), method, status.Code(err)})
+}
+
+func TestAuditRejected(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := New(Options{})
+	sv.RegisterAuthenticator(auth)
+	rejected := new(rejections)
+	sv.RegisterAuditor(rejected)
+	info := &grpc.UnaryServerInfo{FullMethod: "/apiv1.PatientDirectory/GetPatient"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &apiv1.Patient{}, nil
+	}
+	req := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	if _, err := sv.unaryAuthInterceptor(context.Background(), req, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated. got: %v", err)
+	}
+	user := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "epr"}
+	token, err := auth.generateToken(user, []string{"publisher"}, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	if _, err := sv.unaryAuthInterceptor(ctx, req, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied. got: %v", err)
+	}
+	if _, err := sv.unaryAuthInterceptor(context.Background(), &apiv1.LoginRequest{}, &grpc.UnaryServerInfo{FullMethod: "/apiv1.Authenticator/Login"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if len(*rejected) != 2 {
+		t.Fatalf("expected two rejected calls to be audited. got: %d", len(*rejected))
+	}
+	if r := (*rejected)[0]; r.user != nil || r.method != info.FullMethod || r.code != codes.Unauthenticated {
+		t.Errorf("unexpected audit of unauthenticated call: %+v", r)
+	}
+	if r := (*rejected)[1]; r.user.GetValue() != "epr" || r.method != info.FullMethod || r.code != codes.PermissionDenied {
+		t.Errorf("unexpected audit of call without permission: %+v", r)
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultCertReloadInterval is the default interval at which certificate files are checked for renewal
+const DefaultCertReloadInterval = time.Minute
+
+// certReloader provides the server certificate loaded from files, reloading the files when they change,
+// such as on renewal, so that new connections use the new certificate without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	previous *tls.Certificate // the certificate before the last reload, which may still be used by open connections
+	pool     *x509.CertPool   // certificates from the certificate file, trusted by the HTTP gateway
+	modified time.Time        // modification time of the files when last loaded
+}
+
+// newCertReloader creates a reloader for the certificate and key files specified, loading the certificate
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// modTime returns the latest modification time of the certificate and key files
+func (r *certReloader) modTime() (time.Time, error) {
+	var result time.Time
+	for _, filename := range []string{r.certFile, r.keyFile} {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(result) {
+			result = fi.ModTime()
+		}
+	}
+	return result, nil
+}
+
+func (r *certReloader) load() error {
+	modified, err := r.modTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	pool, err := loadCertPool(r.certFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.previous, r.cert, r.pool, r.modified = r.cert, &cert, pool, modified
+	return nil
+}
+
+// reload reloads the certificate if the files have changed since last loaded, returning whether it was reloaded.
+// If the files cannot be loaded, such as when only one has yet been replaced, the current certificate is retained.
+func (r *certReloader) reload() (bool, error) {
+	modified, err := r.modTime()
+	if err != nil {
+		return false, err
+	}
+	r.mu.RLock()
+	unchanged := modified.Equal(r.modified)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// watch reloads the certificate at the interval specified, until the context is cancelled
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := r.reload()
+		if err != nil {
+			log.Printf("server: failed to reload certificate '%s': %s (continuing to use current certificate)", r.certFile, err)
+		} else if reloaded {
+			log.Printf("server: reloaded certificate '%s'", r.certFile)
+		}
+	}
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// isCertificate returns whether the certificate specified is the current or previous certificate
+func (r *certReloader) isCertificate(raw []byte) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, cert := range []*tls.Certificate{r.cert, r.previous} {
+		if cert != nil && bytes.Equal(cert.Certificate[0], raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyServer verifies the server's certificate chain using the certificates from the current certificate file,
+// for the HTTP gateway, which cannot use a fixed pool as the certificate may be reloaded.
+func (r *certReloader) verifyServer(serverName string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("server: no certificate presented by server")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+		r.mu.RLock()
+		opts := x509.VerifyOptions{Roots: r.pool, DNSName: serverName, Intermediates: x509.NewCertPool()}
+		r.mu.RUnlock()
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
+
+// newACMEManager returns a manager obtaining and renewing certificates for the hosts configured automatically
+// using ACME (e.g. Let's Encrypt), using the TLS-ALPN-01 challenge, which requires the HTTPS port to be
+// reachable from the internet on port 443.
+func (sv *Server) newACMEManager() *autocert.Manager {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(sv.Options.ACMEHosts...),
+		Email:      sv.Options.ACMEEmail,
+	}
+	if sv.Options.ACMECacheDir != "" {
+		m.Cache = autocert.DirCache(sv.Options.ACMECacheDir)
+	}
+	if sv.Options.ACMEDirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: sv.Options.ACMEDirectoryURL}
+	}
+	log.Printf("server: using ACME for certificates for %v", sv.Options.ACMEHosts)
+	return m
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a new self-signed certificate for localhost, and its key, to the files specified,
+// with a modification time as specified, returning the certificate
+func writeCertificate(t *testing.T, certFile, keyFile string, modified time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{certFile, keyFile} {
+		if err := os.Chtimes(filename, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return der
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "domain.crt"), filepath.Join(dir, "domain.key")
+	now := time.Now().Truncate(time.Second)
+	old := writeCertificate(t, certFile, keyFile, now.Add(-time.Hour))
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := r.verifyServer("localhost")
+	if err := verify([][]byte{old}, nil); err != nil {
+		t.Fatalf("failed to verify server certificate: %s", err)
+	}
+	if reloaded, err := r.reload(); reloaded || err != nil {
+		t.Fatalf("expected unchanged certificate not to be reloaded: %v", err)
+	}
+	renewed := writeCertificate(t, certFile, keyFile, now)
+	if reloaded, err := r.reload(); !reloaded || err != nil {
+		t.Fatalf("expected renewed certificate to be reloaded: %v", err)
+	}
+	if cert, _ := r.getCertificate(nil); string(cert.Certificate[0]) != string(renewed) {
+		t.Fatal("renewed certificate not used")
+	}
+	if err := verify([][]byte{renewed}, nil); err != nil {
+		t.Fatalf("failed to verify renewed server certificate: %s", err)
+	}
+	if !r.isCertificate(renewed) || !r.isCertificate(old) || r.isCertificate([]byte("other")) {
+		t.Fatal("current and previous certificates should be recognised")
+	}
+	if err := ioutil.WriteFile(keyFile, []byte("invalid"), 0600); err != nil { // e.g. partially replaced
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(keyFile, now.Add(time.Hour), now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reload(); err == nil {
+		t.Fatal("expected invalid key to fail to reload")
+	}
+	if cert, _ := r.getCertificate(nil); string(cert.Certificate[0]) != string(renewed) {
+		t.Fatal("current certificate should be retained if reload fails")
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// PractitionerProvider is an AuthProvider that can also provide details of the practitioner using an account,
+// such as their professional registration, which are included in tokens issued on login
+type PractitionerProvider interface {
+	GetPractitioner(ctx context.Context, id *apiv1.Identifier) (*apiv1.Practitioner, error)
+}
+
+// PractitionerClaims are details of the practitioner to whom a token is issued, included as the claim
+// 'practitioner', so that services relying on tokens issued by concierge need not fetch them from a directory
+type PractitionerClaims struct {
+	GMC          string `json:"gmc,omitempty"`          // General Medical Council reference number
+	JobTitle     string `json:"job_title,omitempty"`    // e.g. "Consultant Neurologist"
+	Organisation string `json:"organisation,omitempty"` // ODS code of the organisation, e.g. 7A4
+}
+
+// practitionerTimeout is the maximum time to fetch details of a practitioner on login
+const practitionerTimeout = 5 * time.Second
+
+// practitionerClaims returns details of the practitioner using the account specified, if the authentication
+// provider can provide them. Login is not prevented if details cannot be fetched, but the token then has none.
+func practitionerClaims(ctx context.Context, id *apiv1.Identifier, ap AuthProvider) *PractitionerClaims {
+	pp, ok := ap.(PractitionerProvider)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, practitionerTimeout)
+	defer cancel()
+	p, err := pp.GetPractitioner(ctx, id)
+	if err != nil {
+		log.Printf("auth: failed to fetch practitioner details for '%s|%s': %s", id.GetSystem(), id.GetValue(), err)
+		return nil
+	}
+	pc := new(PractitionerClaims)
+	for _, pid := range p.GetIdentifiers() {
+		if pid.GetSystem() == identifiers.GMCNumber {
+			pc.GMC = pid.GetValue()
+		}
+	}
+	for _, role := range p.GetRoles() {
+		if pc.JobTitle == "" {
+			pc.JobTitle = role.GetRole().GetJobTitle()
+		}
+		if pc.Organisation == "" && role.GetOrganisation().GetSystem() == identifiers.ODSCode {
+			pc.Organisation = role.GetOrganisation().GetValue()
+		}
+	}
+	if *pc == (PractitionerClaims{}) {
+		return nil
+	}
+	return pc
+}
+
+// GetPractitioner returns details of the practitioner to whom the token was issued, if any, guarding against nils
+func (ucd *UserContextData) GetPractitioner() *PractitionerClaims {
+	if ucd == nil {
+		return nil
+	}
+	return ucd.practitioner
+}
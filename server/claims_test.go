@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// practitioners is a simple AuthProvider and PractitionerProvider for testing
+type practitioners map[string]*apiv1.Practitioner
+
+func (p practitioners) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	return credential == "password", nil
+}
+
+func (p practitioners) GetPractitioner(ctx context.Context, id *apiv1.Identifier) (*apiv1.Practitioner, error) {
+	if pract, ok := p[id.GetValue()]; ok {
+		return pract, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestPractitionerClaims(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-single", NewSingleAuthProvider(hash), true)
+	auth.RegisterAuthProvider(identifiers.CymruUserID, "test-practitioners", practitioners{
+		"ma090906": {
+			Identifiers: []*apiv1.Identifier{{System: identifiers.CymruUserID, Value: "ma090906"}, {System: identifiers.GMCNumber, Value: "4624000"}},
+			Roles: []*apiv1.PractitionerRole{{
+				Role:         &apiv1.Role{JobTitle: "Consultant Neurologist"},
+				Organisation: &apiv1.Identifier{System: identifiers.ODSCode, Value: "7A4"},
+			}},
+		},
+	}, false)
+	login := func(ctx context.Context, id *apiv1.Identifier, password string) *UserContextData {
+		r, err := auth.Login(ctx, &apiv1.LoginRequest{User: id, Password: password})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ucd, err := auth.parseToken(r.GetToken())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ucd
+	}
+	service := login(context.Background(), &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "service"}, password)
+	if service.GetPractitioner() != nil {
+		t.Fatalf("service account should not have practitioner claims. got: %+v", service.GetPractitioner())
+	}
+	ctx := context.WithValue(context.Background(), userContextKey, service)
+	user := login(ctx, &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}, "password")
+	expected := PractitionerClaims{GMC: "4624000", JobTitle: "Consultant Neurologist", Organisation: "7A4"}
+	if user.GetPractitioner() == nil || *user.GetPractitioner() != expected {
+		t.Fatalf("expected practitioner claims %+v. got: %+v", expected, user.GetPractitioner())
+	}
+	unknown := login(ctx, &apiv1.Identifier{System: identifiers.CymruUserID, Value: "unknown"}, "password")
+	if unknown.GetPractitioner() != nil {
+		t.Fatalf("login should succeed without claims if practitioner cannot be fetched. got: %+v", unknown.GetPractitioner())
+	}
+}
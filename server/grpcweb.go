@@ -0,0 +1,81 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+)
+
+// corsMethods are the HTTP methods permitted for cross-origin requests to the REST gateway
+var corsMethods = []string{
+	http.MethodHead,
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// corsHeaders are the request headers always permitted for cross-origin requests, in addition to those configured
+var corsHeaders = []string{"Accept", "Accept-Language", "Authorization", "Content-Type", "Traceparent", "Tracestate", "X-Grpc-Web", "X-User-Agent"}
+
+// allowOrigin returns whether cross-origin requests are permitted from the origin specified.
+// All origins are permitted if none are configured.
+func (sv *Server) allowOrigin(origin string) bool {
+	if len(sv.Options.CORSOrigins) == 0 {
+		return true
+	}
+	for _, o := range sv.Options.CORSOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedHeaders returns the request headers permitted for cross-origin requests
+func (sv *Server) allowedHeaders() []string {
+	if len(sv.Options.CORSOrigins) == 0 {
+		return []string{"*"}
+	}
+	return append(append([]string{}, corsHeaders...), sv.Options.CORSHeaders...)
+}
+
+// corsHandler wraps the handler with the configured CORS policy for the REST gateway
+func (sv *Server) corsHandler(h http.Handler) http.Handler {
+	if len(sv.Options.CORSOrigins) == 0 {
+		log.Printf("server: warning: using CORS 'allow-all' permissions")
+		return cors.New(cors.Options{
+			AllowedOrigins:   []string{"*"},
+			AllowedMethods:   corsMethods,
+			AllowedHeaders:   []string{"*"},
+			ExposedHeaders:   []string{"*"},
+			AllowCredentials: true}).Handler(h)
+	}
+	log.Printf("server: permitting cross-origin requests from: %s", strings.Join(sv.Options.CORSOrigins, ", "))
+	return cors.New(cors.Options{
+		AllowOriginFunc:  sv.allowOrigin,
+		AllowedMethods:   corsMethods,
+		AllowedHeaders:   sv.allowedHeaders(),
+		AllowCredentials: true}).Handler(h)
+}
+
+// newGRPCWebHandler returns a handler serving only gRPC-Web requests, and their CORS pre-flight requests,
+// for the gRPC server specified; all other requests are rejected, as the REST gateway has its own port.
+func (sv *Server) newGRPCWebHandler(grpcServer *grpc.Server) http.Handler {
+	wrapped := grpcweb.WrapServer(grpcServer,
+		grpcweb.WithOriginFunc(sv.allowOrigin),
+		grpcweb.WithAllowedRequestHeaders(sv.allowedHeaders()),
+		grpcweb.WithCorsForRegisteredEndpointsOnly(true))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wrapped.IsGrpcWebRequest(r) || wrapped.IsAcceptableGrpcCorsRequest(r) {
+			wrapped.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, "gRPC-Web requests only", http.StatusNotFound)
+	})
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPCWeb(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	sv := New(Options{CORSOrigins: []string{"https://app.example.com"}})
+	h := sv.newGRPCWebHandler(grpcServer)
+
+	// an empty gRPC-Web frame, for an empty HealthCheckRequest
+	req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("unexpected response to gRPC-Web request: %d %v", w.Code, w.Header())
+	}
+	if status := w.Header().Get("Grpc-Status"); status != "" && status != "0" {
+		t.Fatalf("unexpected gRPC status: %s", status)
+	}
+	if !strings.Contains(w.Body.String(), "grpc-status: 0") {
+		t.Fatalf("expected successful status in trailers: %q", w.Body.String())
+	}
+
+	// REST requests are not served by the gRPC-Web handler
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/patient", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected non gRPC-Web request to be rejected, got %d", w.Code)
+	}
+
+	// pre-flight requests are only permitted from configured origins
+	for origin, allowed := range map[string]bool{"https://app.example.com": true, "https://evil.example.com": false} {
+		req = httptest.NewRequest(http.MethodOptions, "/grpc.health.v1.Health/Check", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web,authorization")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if got := w.Header().Get("Access-Control-Allow-Origin") == origin; got != allowed {
+			t.Errorf("%s: expected allowed: %v, got headers: %v", origin, allowed, w.Header())
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	sv := New(Options{CORSOrigins: []string{"https://app.example.com"}, CORSHeaders: []string{"X-Hospital"}})
+	h := sv.corsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, test := range []struct {
+		origin  string
+		headers string
+		allowed bool
+	}{
+		{"https://app.example.com", "authorization,x-hospital", true},
+		{"https://app.example.com", "x-other", false},
+		{"https://evil.example.com", "authorization", false},
+	} {
+		req := httptest.NewRequest(http.MethodOptions, "/v1/patient", nil)
+		req.Header.Set("Origin", test.origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		req.Header.Set("Access-Control-Request-Headers", test.headers)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if got := w.Header().Get("Access-Control-Allow-Origin") == test.origin; got != test.allowed {
+			t.Errorf("%s (%s): expected allowed: %v, got headers: %v", test.origin, test.headers, test.allowed, w.Header())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthWatchInterval is the interval at which the health of a watched service is checked
+var healthWatchInterval = 10 * time.Second
+
+// Check is a health check, implementing the grpc-health service
+// see https://godoc.org/google.golang.org/grpc/health/grpc_health_v1#HealthServer
+// If a service is specified, then the response reflects the result of the health check registered
+// for that service, or provided by the provider registered with that name. Otherwise, if it is the
+// name of a backend endpoint, then the response reflects the state of that endpoint's circuit breaker.
+// The server itself remains serving even if backend services are unavailable, but any open circuit
+// breakers are logged. Once the server is shutting down, it reports itself as not serving.
+func (sv *Server) Check(ctx context.Context, r *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
+	if r.GetService() == "" {
+		for name, state := range transport.States() {
+			if state != transport.Closed {
+				log.Printf("server: health check: circuit breaker for '%s' is %s", name, state)
+			}
+		}
+	}
+	st, err := sv.servingStatus(ctx, r.GetService())
+	if err != nil {
+		log.Printf("server: health check: '%s' failed: %s", r.GetService(), err)
+	}
+	if st == health.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service: %s", r.GetService())
+	}
+	log.Printf("server: health check received for '%s': %s", r.GetService(), st)
+	return &health.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch is a streaming health check, sending the health of the service specified, and then any changes
+// in its health, which is checked periodically. As per the grpc-health service, a service that is not
+// known is reported as SERVICE_UNKNOWN, rather than returning an error, as it may later be registered.
+func (sv *Server) Watch(r *health.HealthCheckRequest, w health.Health_WatchServer) error {
+	ctx := w.Context()
+	log.Printf("server: health watch started for '%s'", r.GetService())
+	ticker := time.NewTicker(healthWatchInterval)
+	defer ticker.Stop()
+	last := health.HealthCheckResponse_UNKNOWN
+	for {
+		st, err := sv.servingStatus(ctx, r.GetService())
+		if st != last {
+			if err != nil {
+				log.Printf("server: health watch: '%s' failed: %s", r.GetService(), err)
+			}
+			log.Printf("server: health watch: '%s' is %s", r.GetService(), st)
+			if err := w.Send(&health.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("server: health watch ended for '%s'", r.GetService())
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus returns the health of the named service, or of the server itself if no service is specified,
+// with the reason for a service not serving, if any
+func (sv *Server) servingStatus(ctx context.Context, service string) (health.HealthCheckResponse_ServingStatus, error) {
+	if service == "" {
+		if sv.isDraining() {
+			return health.HealthCheckResponse_NOT_SERVING, status.Errorf(codes.Unavailable, "server shutting down")
+		}
+		return health.HealthCheckResponse_SERVING, nil
+	}
+	check, ok := sv.checks[service]
+	if !ok {
+		if hc, isChecker := sv.providers[service].(HealthChecker); isChecker {
+			check, ok = hc.HealthCheck, true
+		}
+	}
+	if ok {
+		if err := check(ctx); err != nil {
+			return health.HealthCheckResponse_NOT_SERVING, err
+		}
+		return health.HealthCheckResponse_SERVING, nil
+	}
+	state, ok := transport.States()[service]
+	if !ok {
+		return health.HealthCheckResponse_SERVICE_UNKNOWN, nil
+	}
+	if state == transport.Open {
+		return health.HealthCheckResponse_NOT_SERVING, status.Errorf(codes.Unavailable, "circuit breaker for '%s' is open", service)
+	}
+	return health.HealthCheckResponse_SERVING, nil
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// checkedProvider is a provider with a health check that fails if unhealthy is set
+type checkedProvider struct {
+	mu        sync.Mutex
+	unhealthy bool
+}
+
+func (cp *checkedProvider) RegisterServer(s *grpc.Server) {}
+func (cp *checkedProvider) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return nil
+}
+func (cp *checkedProvider) Close() error { return nil }
+func (cp *checkedProvider) HealthCheck(ctx context.Context) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.unhealthy {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+func (cp *checkedProvider) setUnhealthy(unhealthy bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.unhealthy = unhealthy
+}
+
+// watchStream records the responses sent to a health watch
+type watchStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses chan health.HealthCheckResponse_ServingStatus
+}
+
+func (ws *watchStream) Context() context.Context { return ws.ctx }
+func (ws *watchStream) Send(r *health.HealthCheckResponse) error {
+	ws.responses <- r.GetStatus()
+	return nil
+}
+
+func TestHealthCheck(t *testing.T) {
+	sv := New(Options{})
+	cp := &checkedProvider{}
+	sv.Register("backend", cp)
+	sv.RegisterHealthCheck("failing", func(ctx context.Context) error { return errors.New("failed") })
+	ctx := context.Background()
+	tests := []struct {
+		service string
+		status  health.HealthCheckResponse_ServingStatus
+	}{
+		{"", health.HealthCheckResponse_SERVING},
+		{"backend", health.HealthCheckResponse_SERVING},
+		{"failing", health.HealthCheckResponse_NOT_SERVING},
+	}
+	for _, test := range tests {
+		r, err := sv.Check(ctx, &health.HealthCheckRequest{Service: test.service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GetStatus() != test.status {
+			t.Errorf("'%s': expected %s, got %s", test.service, test.status, r.GetStatus())
+		}
+	}
+	if _, err := sv.Check(ctx, &health.HealthCheckRequest{Service: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found for unknown service, got: %v", err)
+	}
+}
+
+func TestHealthWatch(t *testing.T) {
+	defer func(interval time.Duration) { healthWatchInterval = interval }(healthWatchInterval)
+	healthWatchInterval = 10 * time.Millisecond
+	sv := New(Options{})
+	cp := &checkedProvider{}
+	sv.Register("backend", cp)
+	ctx, cancel := context.WithCancel(context.Background())
+	ws := &watchStream{ctx: ctx, responses: make(chan health.HealthCheckResponse_ServingStatus, 10)}
+	done := make(chan error)
+	go func() { done <- sv.Watch(&health.HealthCheckRequest{Service: "backend"}, ws) }()
+	expect := func(expected health.HealthCheckResponse_ServingStatus) {
+		select {
+		case st := <-ws.responses:
+			if st != expected {
+				t.Errorf("expected %s, got %s", expected, st)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+	expect(health.HealthCheckResponse_SERVING)
+	cp.setUnhealthy(true)
+	expect(health.HealthCheckResponse_NOT_SERVING)
+	cp.setUnhealthy(false)
+	expect(health.HealthCheckResponse_SERVING)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.responses) != 0 {
+		t.Errorf("expected only changes in status to be sent")
+	}
+
+	ws = &watchStream{ctx: ctx, responses: make(chan health.HealthCheckResponse_ServingStatus, 10)}
+	if err := sv.Watch(&health.HealthCheckRequest{Service: "unknown"}, ws); err != nil {
+		t.Fatal(err)
+	}
+	expect(health.HealthCheckResponse_SERVICE_UNKNOWN)
+}
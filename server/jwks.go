@@ -0,0 +1,159 @@
+package server
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// JWKSPath is the path on the HTTP server of the JSON Web Key Set (RFC 7517) containing the public keys
+// with which tokens issued by concierge can be verified
+const JWKSPath = "/.well-known/jwks.json"
+
+// keySet holds the key used to sign tokens, and the public keys with which tokens are verified, by key id.
+// Keys other than the signing key permit an overlap period while the signing key is rotated: a new key is
+// first published for verification, so that other services can fetch it before it is used, and the previous
+// key remains available for verification until tokens signed using it have expired.
+type keySet struct {
+	mu        sync.RWMutex
+	signing   *rsa.PrivateKey
+	signingID string
+	keys      map[string]*rsa.PublicKey
+}
+
+func newKeySet(signing *rsa.PrivateKey) *keySet {
+	ks := &keySet{keys: make(map[string]*rsa.PublicKey)}
+	ks.setSigningKey(signing)
+	return ks
+}
+
+// setSigningKey sets the key used to sign tokens, which is also used to verify them
+func (ks *keySet) setSigningKey(key *rsa.PrivateKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.signing = key
+	ks.signingID = ""
+	if key != nil {
+		ks.signingID = keyID(&key.PublicKey)
+		ks.keys[ks.signingID] = &key.PublicKey
+	}
+}
+
+// add adds a key with which tokens are verified
+func (ks *keySet) add(key *rsa.PublicKey) string {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	id := keyID(key)
+	ks.keys[id] = key
+	return id
+}
+
+// signingKey returns the key used to sign tokens and its key id
+func (ks *keySet) signingKey() (*rsa.PrivateKey, string) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.signing, ks.signingID
+}
+
+// verificationKey returns the key with the key id specified. Tokens without a key id, as issued
+// before key ids were used, are verified using the signing key.
+func (ks *keySet) verificationKey(id string) (*rsa.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if id == "" {
+		if ks.signing == nil {
+			return nil, false
+		}
+		return &ks.signing.PublicKey, true
+	}
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+// keyID returns the id of the key, being its JWK thumbprint (RFC 7638), so that the same key has the same
+// id on all servers without configuration
+func keyID(key *rsa.PublicKey) string {
+	jwk := newJWK(key, "")
+	b := []byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N))
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// jwk is a JSON Web Key (RFC 7517) for a RSA public key
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func newJWK(key *rsa.PublicKey, id string) jwk {
+	return jwk{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: id,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// jwks returns the public keys as a JSON Web Key Set, with the signing key first
+func (ks *keySet) jwks() map[string][]jwk {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys := make([]jwk, 0, len(ks.keys))
+	for id, key := range ks.keys {
+		keys = append(keys, newJWK(key, id))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i].Kid == ks.signingID) != (keys[j].Kid == ks.signingID) {
+			return keys[i].Kid == ks.signingID
+		}
+		return keys[i].Kid < keys[j].Kid
+	})
+	return map[string][]jwk{"keys": keys}
+}
+
+// AddVerificationKey adds a RSA key (PEM), either a public or private key, or a certificate, with which tokens
+// are verified in addition to the signing key. This permits rotation of the signing key without invalidating
+// tokens signed using the previous key, and publication of the next key before it is used.
+func (auth *Auth) AddVerificationKey(filename string) error {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading jwt verification key: %w", err)
+	}
+	var key *rsa.PublicKey
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(b); err == nil {
+		key = &private.PublicKey
+	} else if key, err = jwt.ParseRSAPublicKeyFromPEM(b); err != nil {
+		return fmt.Errorf("error parsing jwt verification key '%s': %w", filename, err)
+	}
+	id := auth.keys.add(key)
+	log.Printf("auth: verifying tokens using key '%s' from '%s'", id, filename)
+	return nil
+}
+
+// JWKSHandler returns a handler serving the public keys with which tokens are verified, as a JSON Web
+// Key Set, so that other services can verify tokens issued by concierge
+func (auth *Auth) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(auth.keys.jwks()); err != nil {
+			log.Printf("auth: failed to write key set: %s", err)
+		}
+	})
+}
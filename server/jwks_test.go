@@ -0,0 +1,103 @@
+package server
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestKeyRotation(t *testing.T) {
+	previous, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "a123456789"}
+	old, err := previous.generateToken(id, nil, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.parseToken(old); err == nil {
+		t.Fatal("token signed using an unknown key should be invalid")
+	}
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key, _ := previous.keys.signingKey()
+	filename := filepath.Join(dir, "previous.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(filename, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := current.AddVerificationKey(filename); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.parseToken(old); err != nil {
+		t.Fatalf("token signed using previous key should be valid during rotation: %s", err)
+	}
+	token, err := current.generateToken(id, nil, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, kid := current.keys.signingKey()
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != kid {
+		t.Fatalf("expected key id '%s' in token header. got: %v", kid, parsed.Header["kid"])
+	}
+	if _, err := previous.parseToken(token); err == nil {
+		t.Fatal("token signed using the new key should not be valid for a server without that key")
+	}
+
+	// the key set should contain both keys, the signing key first, from which tokens can be verified
+	w := httptest.NewRecorder()
+	current.JWKSHandler().ServeHTTP(w, httptest.NewRequest("GET", JWKSPath, nil))
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&jwks); err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != kid {
+		t.Fatalf("expected two keys, the signing key first. got: %+v", jwks.Keys)
+	}
+	for _, k := range jwks.Keys {
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if keyID(pub) != k.Kid {
+			t.Fatalf("key id does not match key: %s", k.Kid)
+		}
+		if k.Kid == kid {
+			if _, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return pub, nil }); err != nil {
+				t.Fatalf("token should be verifiable using published key: %s", err)
+			}
+		}
+	}
+}
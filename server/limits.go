@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the HTTP gateway, used if not configured
+const (
+	DefaultReadTimeout     = 5 * time.Second
+	DefaultWriteTimeout    = 10 * time.Second
+	DefaultMaxRequestBytes = 4 << 20 // the default maximum size of a gRPC message
+)
+
+// Route overrides the timeout and maximum request size of the HTTP gateway for requests with the path prefix specified,
+// such as to permit large document uploads. Zero values use those of the server.
+type Route struct {
+	Prefix   string        // path prefix e.g. "/v1/document/upload"; the longest matching prefix is used
+	Timeout  time.Duration // time permitted to read the request and write the response
+	MaxBytes int64         // maximum size of the request body
+}
+
+// ParseRoute parses a route override of the form prefix=timeout[:size] e.g. "/v1/document=5m:50MB"
+func ParseRoute(spec string) (Route, error) {
+	kv := strings.SplitN(spec, "=", 2)
+	if len(kv) != 2 || !strings.HasPrefix(kv[0], "/") {
+		return Route{}, fmt.Errorf("server: invalid route '%s': expected prefix=timeout[:size]", spec)
+	}
+	route := Route{Prefix: kv[0]}
+	values := strings.SplitN(kv[1], ":", 2)
+	if values[0] != "" {
+		timeout, err := time.ParseDuration(values[0])
+		if err != nil || timeout < 0 {
+			return Route{}, fmt.Errorf("server: invalid route '%s': invalid timeout", spec)
+		}
+		route.Timeout = timeout
+	}
+	if len(values) == 2 {
+		size, err := ParseSize(values[1])
+		if err != nil {
+			return Route{}, fmt.Errorf("server: invalid route '%s': %w", spec, err)
+		}
+		route.MaxBytes = size
+	}
+	return route, nil
+}
+
+// sizeUnits are the units permitted in sizes, using binary multiples
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// ParseSize parses a size in bytes, with an optional unit of B, KB, MB or GB e.g. "512KB" or "50MB"
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: '%s'", s)
+	}
+	return n * multiplier, nil
+}
+
+// route returns the timeout and maximum request size for the path specified
+func (sv *Server) route(path string) (timeout time.Duration, maxBytes int64) {
+	timeout, maxBytes = sv.writeTimeout(), sv.maxRequestBytes()
+	longest := -1
+	for _, r := range sv.Options.Routes {
+		if strings.HasPrefix(path, r.Prefix) && len(r.Prefix) > longest {
+			longest = len(r.Prefix)
+			timeout, maxBytes = sv.writeTimeout(), sv.maxRequestBytes()
+			if r.Timeout > 0 {
+				timeout = r.Timeout
+			}
+			if r.MaxBytes > 0 {
+				maxBytes = r.MaxBytes
+			}
+		}
+	}
+	return
+}
+
+func (sv *Server) readTimeout() time.Duration {
+	if sv.Options.ReadTimeout > 0 {
+		return sv.Options.ReadTimeout
+	}
+	return DefaultReadTimeout
+}
+
+func (sv *Server) writeTimeout() time.Duration {
+	if sv.Options.WriteTimeout > 0 {
+		return sv.Options.WriteTimeout
+	}
+	return DefaultWriteTimeout
+}
+
+func (sv *Server) maxRequestBytes() int64 {
+	if sv.Options.MaxRequestBytes > 0 {
+		return sv.Options.MaxRequestBytes
+	}
+	return DefaultMaxRequestBytes
+}
+
+// maxTimeouts returns the longest read and write timeouts across the server and its routes, used for the HTTP
+// server itself; timeouts for each route are then enforced using the request context.
+func (sv *Server) maxTimeouts() (read time.Duration, write time.Duration) {
+	read, write = sv.readTimeout(), sv.writeTimeout()
+	for _, r := range sv.Options.Routes {
+		if r.Timeout > read {
+			read = r.Timeout
+		}
+		if r.Timeout > write {
+			write = r.Timeout
+		}
+	}
+	return
+}
+
+// maxMessageBytes returns the largest request size across the server and its routes, used as the maximum
+// size of gRPC messages, so that requests accepted by the gateway can be forwarded to the gRPC server.
+func (sv *Server) maxMessageBytes() int {
+	result := sv.maxRequestBytes()
+	for _, r := range sv.Options.Routes {
+		if r.MaxBytes > result {
+			result = r.MaxBytes
+		}
+	}
+	return int(result)
+}
+
+// limitHandler limits the size of the request body, and the time permitted to process the request, for each route
+func (sv *Server) limitHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout, maxBytes := sv.route(r.URL.Path)
+		if r.ContentLength > maxBytes {
+			http.Error(w, fmt.Sprintf("request too large: maximum %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
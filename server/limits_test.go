@@ -0,0 +1,79 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Route
+		valid    bool
+	}{
+		{"/v1/document/upload=5m:50MB", Route{Prefix: "/v1/document/upload", Timeout: 5 * time.Minute, MaxBytes: 50 << 20}, true},
+		{"/v1/document=30s", Route{Prefix: "/v1/document", Timeout: 30 * time.Second}, true},
+		{"/v1/document=:512kb", Route{Prefix: "/v1/document", MaxBytes: 512 << 10}, true},
+		{"/v1/document=1048576", Route{}, false},
+		{"v1/document=5m", Route{}, false},
+		{"/v1/document=5m:lots", Route{}, false},
+		{"/v1/document", Route{}, false},
+	}
+	for _, test := range tests {
+		route, err := ParseRoute(test.spec)
+		if (err == nil) != test.valid || route != test.expected {
+			t.Errorf("%s: expected %+v (valid: %v), got %+v (%v)", test.spec, test.expected, test.valid, route, err)
+		}
+	}
+}
+
+func TestLimitHandler(t *testing.T) {
+	sv := New(Options{MaxRequestBytes: 16, Routes: []Route{
+		{Prefix: "/v1/document", Timeout: time.Minute},
+		{Prefix: "/v1/document/upload", MaxBytes: 1024},
+	}})
+	var deadline time.Duration
+	h := sv.limitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d, ok := r.Context().Deadline(); ok {
+			deadline = time.Until(d)
+		}
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}))
+	tests := []struct {
+		path     string
+		size     int
+		ok       bool
+		deadline time.Duration
+	}{
+		{"/v1/patient", 16, true, DefaultWriteTimeout},
+		{"/v1/patient", 17, false, DefaultWriteTimeout},
+		{"/v1/document/publish", 17, false, time.Minute},
+		{"/v1/document/upload", 1024, true, DefaultWriteTimeout},
+		{"/v1/document/upload", 1025, false, DefaultWriteTimeout},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(strings.Repeat("x", test.size)))
+		req.ContentLength = -1 // as for a streamed request, so the limit is enforced while reading
+		w := httptest.NewRecorder()
+		deadline = 0
+		h.ServeHTTP(w, req)
+		if (w.Code == http.StatusOK) != test.ok {
+			t.Errorf("%s (%d bytes): expected ok: %v, got %d", test.path, test.size, test.ok, w.Code)
+		}
+		if deadline > test.deadline || deadline < test.deadline-time.Second {
+			t.Errorf("%s: expected deadline of %s, got %s", test.path, test.deadline, deadline)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/patient", strings.NewReader(strings.Repeat("x", 17)))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected request with a content length over the limit to be rejected, got %d", w.Code)
+	}
+}
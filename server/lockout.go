@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// LoginLimits define how failed logins are throttled, to protect accounts, particularly those of the NHS Wales
+// directory, from password guessing and spraying. After the permitted number of consecutive failures for a user,
+// or from a network address, further attempts are refused for a delay that doubles with each subsequent failure,
+// up to the lockout. Attempts that are refused are not passed to the authentication provider, so they cannot
+// themselves lock the account in the directory.
+type LoginLimits struct {
+	MaxFailures        int           // failures permitted for a user before login is delayed - no limit if zero
+	MaxAddressFailures int           // failures permitted from a network address, for any user, before login is delayed - no limit if zero
+	Backoff            time.Duration // delay after the permitted failures, doubling with each subsequent failure
+	Lockout            time.Duration // maximum delay, after which failures are forgotten if there are no more
+}
+
+// DefaultLoginLimits are the limits used unless configured otherwise. Failures from a network address are
+// permitted more generously, as a client application logs in its users from a single address.
+var DefaultLoginLimits = LoginLimits{
+	MaxFailures:        5,
+	MaxAddressFailures: 50,
+	Backoff:            time.Second,
+	Lockout:            15 * time.Minute,
+}
+
+// loginFailures records failed logins by user and by network address
+type loginFailures struct {
+	mu      sync.Mutex
+	limits  LoginLimits
+	entries map[string]*failures
+	swept   time.Time
+	now     func() time.Time
+}
+
+type failures struct {
+	count int
+	last  time.Time
+}
+
+func newLoginFailures(limits LoginLimits) *loginFailures {
+	return &loginFailures{limits: limits, entries: make(map[string]*failures), now: time.Now}
+}
+
+// delay returns the time for which login is refused after the number of failures specified
+func (lf *loginFailures) delay(count int, max int) time.Duration {
+	if max <= 0 || count < max {
+		return 0
+	}
+	d := lf.limits.Backoff
+	for i := max; i < count && d < lf.limits.Lockout; i++ {
+		d *= 2
+	}
+	if d > lf.limits.Lockout {
+		d = lf.limits.Lockout
+	}
+	return d
+}
+
+// wait returns the time remaining before a login for the key specified is permitted
+func (lf *loginFailures) wait(key string, max int) time.Duration {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	f, ok := lf.entries[key]
+	if !ok {
+		return 0
+	}
+	if wait := f.last.Add(lf.delay(f.count, max)).Sub(lf.now()); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// fail records a failed login for the key specified, returning whether login is now delayed having
+// previously been permitted without delay
+func (lf *loginFailures) fail(key string, max int) bool {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	now := lf.now()
+	lf.sweep(now)
+	f, ok := lf.entries[key]
+	if !ok {
+		f = new(failures)
+		lf.entries[key] = f
+	}
+	f.count++
+	f.last = now
+	return max > 0 && f.count == max
+}
+
+// reset forgets failed logins for the key specified
+func (lf *loginFailures) reset(key string) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	delete(lf.entries, key)
+}
+
+// sweep forgets failures once twice the lockout has passed since the last, by which time any delay has ended,
+// so that entries for the many users tried by password spraying do not accumulate. Must be called with the lock held.
+func (lf *loginFailures) sweep(now time.Time) {
+	if now.Sub(lf.swept) < lf.limits.Lockout {
+		return
+	}
+	for key, f := range lf.entries {
+		if now.Sub(f.last) > 2*lf.limits.Lockout {
+			delete(lf.entries, key)
+		}
+	}
+	lf.swept = now
+}
+
+// SetLoginLimits sets the limits on failed logins, replacing any failures already recorded
+func (auth *Auth) SetLoginLimits(limits LoginLimits) {
+	auth.failures = newLoginFailures(limits)
+}
+
+// CheckLogin returns an error if login by the user specified, or from the caller's network address,
+// is refused because of previous failures. Services that verify a user's password other than by login,
+// such as to change it, should use this, LoginFailed and LoginSucceeded so that they are similarly throttled.
+func (auth *Auth) CheckLogin(ctx context.Context, id *apiv1.Identifier) error {
+	if auth.failures == nil {
+		return nil
+	}
+	wait := auth.failures.wait(userKey(id), auth.failures.limits.MaxFailures)
+	if addr := clientAddress(ctx); addr != "" {
+		if w := auth.failures.wait("addr|"+addr, auth.failures.limits.MaxAddressFailures); w > wait {
+			wait = w
+		}
+	}
+	if wait == 0 {
+		return nil
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+	return i18n.Errorf(ctx, codes.ResourceExhausted, "too many failed login attempts: retry after %d seconds", seconds)
+}
+
+// LoginFailed records a failed login by the user specified from the caller's network address
+func (auth *Auth) LoginFailed(ctx context.Context, id *apiv1.Identifier) {
+	if auth.failures == nil {
+		return
+	}
+	if auth.failures.fail(userKey(id), auth.failures.limits.MaxFailures) {
+		log.Printf("auth: locking out '%s|%s' after %d failed logins", id.GetSystem(), id.GetValue(), auth.failures.limits.MaxFailures)
+		metrics.Lockout("user")
+	}
+	if addr := clientAddress(ctx); addr != "" {
+		if auth.failures.fail("addr|"+addr, auth.failures.limits.MaxAddressFailures) {
+			log.Printf("auth: locking out address '%s' after %d failed logins", addr, auth.failures.limits.MaxAddressFailures)
+			metrics.Lockout("address")
+		}
+	}
+}
+
+// LoginSucceeded forgets failed logins by the user specified. Failures from the network address are not
+// forgotten, as otherwise an attacker could interleave guesses with logins to an account of their own.
+func (auth *Auth) LoginSucceeded(id *apiv1.Identifier) {
+	if auth.failures != nil {
+		auth.failures.reset(userKey(id))
+	}
+}
+
+// userKey returns the key for failed logins by the user specified. Providers such as NADEX do not
+// distinguish usernames by case or surrounding whitespace, so neither do the limits on failed logins.
+func userKey(id *apiv1.Identifier) string {
+	return "user|" + subjectKey(id.GetSystem()+"|"+id.GetValue())
+}
+
+// clientAddress returns the network address of the caller. Calls made through the HTTP gateway, which connects
+// from the loopback interface, use the address of the client of the gateway, which it appends to any
+// X-Forwarded-For header; earlier addresses in that header are provided by the client and cannot be trusted.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+				addrs := strings.Split(forwarded[0], ",")
+				return strings.TrimSpace(addrs[len(addrs)-1])
+			}
+		}
+	}
+	return host
+}
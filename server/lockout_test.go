@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginDelay(t *testing.T) {
+	lf := newLoginFailures(LoginLimits{MaxFailures: 3, Backoff: time.Second, Lockout: time.Minute})
+	tests := []struct {
+		count int
+		delay time.Duration
+	}{
+		{0, 0}, {2, 0}, {3, time.Second}, {4, 2 * time.Second}, {6, 8 * time.Second}, {9, time.Minute}, {1000, time.Minute},
+	}
+	for _, test := range tests {
+		if d := lf.delay(test.count, 3); d != test.delay {
+			t.Errorf("delay after %d failures: expected %s, got %s", test.count, test.delay, d)
+		}
+	}
+	if d := lf.delay(1000, 0); d != 0 {
+		t.Errorf("expected no delay without limit. got: %s", d)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetLoginLimits(LoginLimits{MaxFailures: 3, MaxAddressFailures: 5, Backoff: time.Second, Lockout: time.Minute})
+	now := time.Now()
+	auth.failures.now = func() time.Time { return now }
+	um := newMemoryUsers()
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-users", um, true)
+	newAccount := func(name string) (*apiv1.Identifier, string) {
+		id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: name}
+		sa, err := CreateServiceAccount(um, auth.policy, id, nil, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id, sa.GetPassword()
+	}
+	from := func(addr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 50000}})
+	}
+	login := func(ctx context.Context, id *apiv1.Identifier, password string) codes.Code {
+		_, err := auth.Login(ctx, &apiv1.LoginRequest{User: id, Password: password})
+		return status.Code(err)
+	}
+
+	// failures for a user delay further attempts, even with the correct password, from any address
+	user, password := newAccount("user")
+	for i := 0; i < 3; i++ {
+		if code := login(from("10.0.0.1"), user, "wrong"); code != codes.Unauthenticated {
+			t.Fatalf("expected invalid credentials. got: %s", code)
+		}
+	}
+	if code := login(from("10.0.0.2"), user, password); code != codes.ResourceExhausted {
+		t.Fatalf("expected login refused after failures. got: %s", code)
+	}
+	now = now.Add(time.Second)
+	if code := login(from("10.0.0.2"), user, "wrong"); code != codes.Unauthenticated {
+		t.Fatalf("expected login attempted after delay. got: %s", code)
+	}
+	now = now.Add(time.Second)
+	if code := login(from("10.0.0.2"), user, password); code != codes.ResourceExhausted {
+		t.Fatalf("expected delay to double after further failure. got: %s", code)
+	}
+	now = now.Add(time.Second)
+	if code := login(from("10.0.0.2"), user, password); code != codes.OK {
+		t.Fatalf("expected login after delay. got: %s", code)
+	}
+	if code := login(from("10.0.0.2"), user, "wrong"); code != codes.Unauthenticated {
+		t.Fatalf("expected failures forgotten after successful login. got: %s", code)
+	}
+
+	// failures from an address, spread across users, delay attempts from that address for any user
+	other, otherPassword := newAccount("other")
+	for i := 0; i < 5; i++ {
+		id, _ := newAccount("sprayed" + string('a'+rune(i)))
+		if code := login(from("10.0.0.3"), id, "Winter2020"); code != codes.Unauthenticated {
+			t.Fatalf("expected invalid credentials. got: %s", code)
+		}
+	}
+	if code := login(from("10.0.0.3"), other, otherPassword); code != codes.ResourceExhausted {
+		t.Fatalf("expected login from address refused after failures. got: %s", code)
+	}
+	if code := login(from("10.0.0.4"), other, otherPassword); code != codes.OK {
+		t.Fatalf("expected login from another address. got: %s", code)
+	}
+
+	// calls through the gateway use the address it forwards, rather than that of the gateway itself
+	gateway := metadata.NewIncomingContext(from("127.0.0.1"), metadata.Pairs("x-forwarded-for", "10.0.0.4, 10.0.0.3"))
+	if code := login(gateway, other, otherPassword); code != codes.ResourceExhausted {
+		t.Fatalf("expected login through gateway refused using forwarded address. got: %s", code)
+	}
+	now = now.Add(time.Minute)
+	if code := login(gateway, other, otherPassword); code != codes.OK {
+		t.Fatalf("expected login after lockout. got: %s", code)
+	}
+}
+
+// binder is an AuthProvider that, like a directory bind, returns an error rather than failure for invalid credentials
+type binder string
+
+func (b binder) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	if credential != string(b) {
+		return false, errors.New("ldap: bind failed: invalid credentials")
+	}
+	return true, nil
+}
+
+func TestLoginLockoutProviderError(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetLoginLimits(LoginLimits{MaxFailures: 3, Backoff: time.Second, Lockout: time.Minute})
+	auth.RegisterAuthProvider(identifiers.CymruUserID, "test-binder", binder("password"), true)
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	for i := 0; i < 3; i++ {
+		_, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: user, Password: "wrong"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected failure to authenticate. got: %s", err)
+		}
+		if strings.Contains(status.Convert(err).Message(), "ldap") {
+			t.Fatalf("error from authentication provider should not be returned to client: %s", err)
+		}
+	}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: user, Password: "password"}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected login refused after provider errors. got: %s", err)
+	}
+	_, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := NewSingleAuthProvider(hash).Authenticate(user, "wrong"); ok || err != nil {
+		t.Fatalf("expected invalid credentials to be a failure, not an error. got: %v %v", ok, err)
+	}
+}
+
+func TestLoginLockoutCase(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetLoginLimits(LoginLimits{MaxFailures: 3, Backoff: time.Second, Lockout: time.Minute})
+	auth.RegisterAuthProvider(identifiers.CymruUserID, "test-binder", binder("password"), true)
+	for _, value := range []string{"Alice", "ALICE", "alice"} {
+		user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: value}
+		if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: user, Password: "wrong"}); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected invalid credentials for '%s'. got: %v", value, err)
+		}
+	}
+	for _, value := range []string{"alice", "Alice", " ALICE "} {
+		user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: value}
+		if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: user, Password: "password"}); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("expected login as '%s' refused after failures using other cases of the username. got: %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+// swaggerUI is a page rendering the OpenAPI definition using Swagger UI, loaded from a CDN so that
+// its assets need not be bundled
+var swaggerUI = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"></script>
+<script>
+window.onload = function() {
+  window.ui = SwaggerUIBundle({url: "{{.URL}}", dom_id: "#swagger-ui"});
+};
+</script>
+</body>
+</html>
+`))
+
+// openAPIDefinition returns the OpenAPI definition of the REST API, with the title and version specified
+func openAPIDefinition(title, version string) ([]byte, error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal([]byte(apiv1.SwaggerJSON), &def); err != nil {
+		return nil, err
+	}
+	info, _ := def["info"].(map[string]interface{})
+	if info == nil {
+		info = make(map[string]interface{})
+		def["info"] = info
+	}
+	info["title"] = title
+	if version != "" {
+		info["version"] = version
+	}
+	return json.MarshalIndent(def, "", "  ")
+}
+
+// registerOpenAPI serves the OpenAPI definition at path/swagger.json, and Swagger UI at path/
+func (sv *Server) registerOpenAPI(mux *http.ServeMux, path string) error {
+	path = strings.TrimSuffix(path, "/")
+	def, err := openAPIDefinition("Concierge", sv.Options.Version)
+	if err != nil {
+		return fmt.Errorf("server: invalid OpenAPI definition: %w", err)
+	}
+	mux.HandleFunc(path+"/swagger.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(def)
+	})
+	mux.HandleFunc(path+"/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path+"/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		swaggerUI.Execute(w, struct{ Title, URL string }{"Concierge API", path + "/swagger.json"})
+	})
+	log.Printf("server: serving OpenAPI definition at %s/swagger.json and Swagger UI at %s/", path, path)
+	return nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+func TestOpenAPI(t *testing.T) {
+	sv := New(Options{Version: "1.2.3"})
+	mux := http.NewServeMux()
+	if err := sv.registerOpenAPI(mux, "/openapi/"); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi/swagger.json", nil))
+	var def struct {
+		Info struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &def); err != nil {
+		t.Fatal(err)
+	}
+	if def.Info.Title != "Concierge" || def.Info.Version != "1.2.3" {
+		t.Fatalf("unexpected info: %+v", def.Info)
+	}
+	if _, ok := def.Paths["/v1/patient"]; !ok {
+		t.Fatalf("missing path /v1/patient in OpenAPI definition")
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi/", nil))
+	if !strings.Contains(w.Body.String(), `swagger.json`) {
+		t.Fatalf("swagger UI does not reference definition: %s", w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi/wibble", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected not found, got %d", w.Code)
+	}
+}
+
+func TestReflection(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := New(Options{Reflection: true})
+	sv.RegisterAuthenticator(auth)
+	sv.Register("auth", auth)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go sv.ServeGRPC(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx) // without authentication
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := make(map[string]bool)
+	for _, s := range r.GetListServicesResponse().GetService() {
+		services[s.GetName()] = true
+	}
+	if !services["apiv1.Authenticator"] {
+		t.Fatalf("authenticator service not listed: %v", services)
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/wardle/concierge/identifiers"
+	"gopkg.in/yaml.v2"
+)
+
+// Scopes required to access gRPC methods, as used in the default policy
+const (
+	ScopeAll              = "*" // grants all scopes
+	ScopeAuthAdmin        = "auth:admin"
+	ScopeIdentifierRead   = "identifier:read"
+	ScopeIdentifierAdmin  = "identifier:admin"
+	ScopePatientRead      = "patient:read"
+	ScopePatientWrite     = "patient:write"
+	ScopePractitionerRead = "practitioner:read"
+	ScopeAccountAdmin     = "account:admin" // manage the directory accounts of users other than oneself
+	ScopeDocumentPublish  = "document:publish"
+	ScopeDocumentRead     = "document:read"
+	ScopeNotificationSend = "notification:send"
+	ScopeMaintenance      = "maintenance:admin"
+	ScopeServerAdmin      = "server:admin"
+)
+
+// Policy is a declarative role-based access control policy, defining the scope required for
+// each gRPC method and the scopes granted by each role.
+// A policy can be loaded from a YAML or JSON file, for example:
+//
+//	methods:
+//	  /apiv1.PatientDirectory/*: patient:read
+//	  /apiv1.DocumentService/PublishDocument: document:publish
+//	roles:
+//	  publisher: [document:publish, identifier:read]
+//	defaults:
+//	  https://fhir.nhs.uk/Id/cymru-user-id: [clinician]
+//
+// Methods not listed in the policy require authentication, but no specific scope.
+type Policy struct {
+	Methods  map[string]string   `yaml:"methods" json:"methods"`   // gRPC method (or /service/*) -> required scope
+	Roles    map[string][]string `yaml:"roles" json:"roles"`       // role -> scopes granted
+	Defaults map[string][]string `yaml:"defaults" json:"defaults"` // identifier system -> roles for users without role assignments
+}
+
+// DefaultPolicy is the policy used unless another is configured
+var DefaultPolicy = &Policy{
+	Methods: map[string]string{
+		"/apiv1.Authenticator/GetRoles":                     ScopeAuthAdmin,
+		"/apiv1.Authenticator/AssignRole":                   ScopeAuthAdmin,
+		"/apiv1.Authenticator/RevokeRole":                   ScopeAuthAdmin,
+		"/apiv1.Authenticator/GetServiceAccount":            ScopeAuthAdmin,
+		"/apiv1.Authenticator/CreateServiceAccount":         ScopeAuthAdmin,
+		"/apiv1.Authenticator/DisableServiceAccount":        ScopeAuthAdmin,
+		"/apiv1.Authenticator/RotateServiceAccountSecret":   ScopeAuthAdmin,
+		"/apiv1.Identifiers/*":                              ScopeIdentifierRead,
+		"/apiv1.IdentifierAdmin/*":                          ScopeIdentifierAdmin,
+		"/apiv1.PatientDirectory/*":                         ScopePatientRead,
+		"/apiv1.PatientDirectory/UpdatePatientDemographics": ScopePatientWrite,
+		"/apiv1.Terminology/*":                              ScopeIdentifierRead,
+		"/apiv1.Subscriptions/*":                            ScopePatientRead,
+		"/apiv1.ClinicService/*":                            ScopePatientRead,
+		"/apiv1.PractitionerDirectory/*":                    ScopePractitionerRead,
+		"/apiv1.DocumentService/*":                          ScopeDocumentPublish,
+		"/apiv1.DocumentRepository/*":                       ScopeDocumentRead,
+		"/apiv1.NotificationService/*":                      ScopeNotificationSend,
+		"/apiv1.Maintenance/*":                              ScopeMaintenance,
+		"/apiv1.Admin/*":                                    ScopeServerAdmin,
+	},
+	Roles: map[string][]string{
+		"admin":     {ScopeAll},
+		"clinician": {ScopeIdentifierRead, ScopePatientRead, ScopePractitionerRead, ScopeDocumentRead},
+		"registrar": {ScopeIdentifierRead, ScopePatientRead, ScopePatientWrite},
+		"publisher": {ScopeIdentifierRead, ScopeDocumentPublish, ScopeDocumentRead},
+		"service":   {ScopeIdentifierRead, ScopePatientRead, ScopePractitionerRead, ScopeDocumentPublish, ScopeDocumentRead, ScopeNotificationSend},
+	},
+	Defaults: map[string][]string{
+		identifiers.ConciergeServiceUser: {"service"},
+		identifiers.CymruUserID:          {"clinician"},
+	},
+}
+
+// LoadPolicy loads an access control policy from the YAML or JSON file specified
+func LoadPolicy(filename string) (*Policy, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Policy)
+	if err := yaml.UnmarshalStrict(b, p); err != nil {
+		return nil, fmt.Errorf("auth: invalid policy file: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks that the default roles are all defined
+func (p *Policy) Validate() error {
+	for system, roles := range p.Defaults {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return fmt.Errorf("auth: invalid policy: default role '%s' for '%s' not defined", role, system)
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredScope returns the scope required to call the gRPC method specified, or an empty string
+// if no specific scope is required
+func (p *Policy) RequiredScope(method string) string {
+	if scope, ok := p.Methods[method]; ok {
+		return scope
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		return p.Methods[method[:i]+"/*"]
+	}
+	return ""
+}
+
+// Permitted determines whether any of the roles grant the scope specified
+func (p *Policy) Permitted(roles []string, scope string) bool {
+	if scope == "" {
+		return true
+	}
+	for _, role := range roles {
+		for _, s := range p.Roles[role] {
+			if s == scope || s == ScopeAll {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DefaultRoles returns the roles for users of the identifier system specified, when the
+// authentication provider does not assign roles itself
+func (p *Policy) DefaultRoles(system string) []string {
+	return p.Defaults[system]
+}
+
+// IsRole determines whether the role is defined in this policy
+func (p *Policy) IsRole(role string) bool {
+	_, ok := p.Roles[role]
+	return ok
+}
+
+// RoleNames returns the names of the roles in this policy, sorted by name
+func (p *Policy) RoleNames() []string {
+	result := make([]string, 0, len(p.Roles))
+	for role := range p.Roles {
+		result = append(result, role)
+	}
+	sort.Strings(result)
+	return result
+}
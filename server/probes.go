@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/wardle/concierge/transport"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultDrainTimeout is the default time permitted for in-flight requests to complete on shutdown
+const DefaultDrainTimeout = 30 * time.Second
+
+// probeTimeout is the time permitted for the health checks of a readiness probe
+var probeTimeout = 5 * time.Second
+
+// DegradedFunc returns the reason a named service is degraded but intentionally so, such as during planned
+// maintenance, in which case it does not make the server unready, or an empty string if it is not.
+type DegradedFunc func(service string) string
+
+// SetDegradedFunc sets the function used to determine whether a service is intentionally degraded, for readiness.
+// This should not be called once server is running.
+func (sv *Server) SetDegradedFunc(f DegradedFunc) {
+	sv.degraded = f
+}
+
+// readiness is the result of a readiness probe
+type readiness struct {
+	Status   string            `json:"status"`             // "ready", "degraded" or "not ready"
+	Services map[string]string `json:"services,omitempty"` // status of each critical service: "ok", or the reason it is degraded or unavailable
+}
+
+// ready checks whether the server is ready to receive requests, being not shutting down, with all
+// critical services serving, or degraded but usable, such as with a half-open circuit breaker or in
+// planned maintenance
+func (sv *Server) ready(ctx context.Context) *readiness {
+	result := &readiness{Status: "ready", Services: make(map[string]string)}
+	if sv.isDraining() {
+		result.Status = "not ready"
+	}
+	states := transport.States()
+	for _, name := range sv.Options.CriticalServices {
+		if sv.degraded != nil {
+			if reason := sv.degraded(name); reason != "" {
+				result.Services[name] = "degraded: " + reason
+				if result.Status == "ready" {
+					result.Status = "degraded"
+				}
+				continue
+			}
+		}
+		st, err := sv.servingStatus(ctx, name)
+		switch {
+		case st == health.HealthCheckResponse_SERVING && states[name] == transport.HalfOpen:
+			result.Services[name] = "degraded: circuit breaker half-open"
+			if result.Status == "ready" {
+				result.Status = "degraded"
+			}
+		case st == health.HealthCheckResponse_SERVING:
+			result.Services[name] = "ok"
+		case err != nil:
+			result.Services[name] = err.Error()
+			result.Status = "not ready"
+		default:
+			result.Services[name] = st.String()
+			result.Status = "not ready"
+		}
+	}
+	return result
+}
+
+// registerProbes registers HTTP handlers for liveness and readiness probes, such as for Kubernetes, at the
+// configured paths. The server is live while it is running, even if backend services are unavailable, as
+// a restart would not help. It is ready unless shutting down or a critical service is unavailable.
+func (sv *Server) registerProbes(mux *http.ServeMux) {
+	if path := sv.Options.LivenessPath; path != "" {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			writeProbe(w, http.StatusOK, &readiness{Status: "live"})
+		})
+		log.Printf("server: serving liveness probe at %s", path)
+	}
+	if path := sv.Options.ReadinessPath; path != "" {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+			defer cancel()
+			result := sv.ready(ctx)
+			code := http.StatusOK
+			if result.Status == "not ready" {
+				code = http.StatusServiceUnavailable
+				log.Printf("server: readiness probe: not ready: %v", result.Services)
+			}
+			writeProbe(w, code, result)
+		})
+		log.Printf("server: serving readiness probe at %s (critical services: %v)", path, sortedCopy(sv.Options.CriticalServices))
+	}
+}
+
+func writeProbe(w http.ResponseWriter, code int, result *readiness) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(result)
+}
+
+func sortedCopy(s []string) []string {
+	result := append([]string{}, s...)
+	sort.Strings(result)
+	return result
+}
+
+// drainTimeout returns the time permitted for in-flight requests to complete on shutdown
+func (sv *Server) drainTimeout() time.Duration {
+	if sv.Options.DrainTimeout > 0 {
+		return sv.Options.DrainTimeout
+	}
+	return DefaultDrainTimeout
+}
+
+// setDraining marks the server as shutting down, so that it is no longer ready to receive requests
+func (sv *Server) setDraining() {
+	atomic.StoreInt32(&sv.draining, 1)
+}
+
+func (sv *Server) isDraining() bool {
+	return atomic.LoadInt32(&sv.draining) == 1
+}
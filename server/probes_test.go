@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbes(t *testing.T) {
+	sv := New(Options{LivenessPath: "/healthz", ReadinessPath: "/readyz", CriticalServices: []string{"backend", "planned"}})
+	cp := &checkedProvider{}
+	sv.Register("backend", cp)
+	sv.Register("planned", &checkedProvider{unhealthy: true})
+	sv.SetDegradedFunc(func(service string) string {
+		if service == "planned" {
+			return "in maintenance"
+		}
+		return ""
+	})
+	mux := http.NewServeMux()
+	sv.registerProbes(mux)
+	probe := func(path string) (int, *readiness) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var result readiness
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("%s: invalid response: %s", path, w.Body.String())
+		}
+		return w.Code, &result
+	}
+	if code, result := probe("/readyz"); code != http.StatusOK || result.Status != "degraded" || result.Services["backend"] != "ok" {
+		t.Fatalf("expected ready but degraded by planned maintenance, got %d: %+v", code, result)
+	}
+	cp.setUnhealthy(true)
+	if code, result := probe("/readyz"); code != http.StatusServiceUnavailable || result.Status != "not ready" {
+		t.Fatalf("expected not ready with failing critical service, got %d: %+v", code, result)
+	}
+	if code, _ := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("expected live despite failing critical service, got %d", code)
+	}
+	cp.setUnhealthy(false)
+	sv.setDraining()
+	if code, result := probe("/readyz"); code != http.StatusServiceUnavailable || result.Status != "not ready" {
+		t.Fatalf("expected not ready when shutting down, got %d: %+v", code, result)
+	}
+	if code, _ := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("expected live when shutting down, got %d", code)
+	}
+}
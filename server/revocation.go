@@ -0,0 +1,96 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// RevocationList records tokens that have been revoked before their expiry, either individually
+// (e.g. on logout) or for a subject (e.g. for incident response, when credentials have been compromised).
+type RevocationList interface {
+	// RevokeToken revokes a single token, identified by its token id, until it expires
+	RevokeToken(id string, expiresAt time.Time) error
+	// RevokeSubject revokes all tokens issued to the subject (system|value) at or before the time specified.
+	// The value is compared ignoring case and surrounding whitespace, as for limits on failed logins.
+	RevokeSubject(subject string, issuedBefore time.Time) error
+	// IsRevoked determines whether a token has been revoked
+	IsRevoked(id string, subject string, issuedAt time.Time) (bool, error)
+}
+
+// memoryRevocationList is a revocation list held in memory, suitable only for a single server instance.
+type memoryRevocationList struct {
+	mu       sync.RWMutex
+	tokens   map[string]time.Time // token id -> expiry
+	subjects map[string]time.Time // subject -> tokens issued before this time are revoked
+}
+
+// NewMemoryRevocationList creates a revocation list held in memory.
+// Revocations are lost on restart, and are not shared between server instances.
+func NewMemoryRevocationList() RevocationList {
+	return &memoryRevocationList{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]time.Time),
+	}
+}
+
+func (rl *memoryRevocationList) RevokeToken(id string, expiresAt time.Time) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	for id, expiry := range rl.tokens { // prune expired tokens, as they will fail validation anyway
+		if expiry.Before(now) {
+			delete(rl.tokens, id)
+		}
+	}
+	rl.tokens[id] = expiresAt
+	return nil
+}
+
+func (rl *memoryRevocationList) RevokeSubject(subject string, issuedBefore time.Time) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	subject = subjectKey(subject)
+	if issuedBefore.After(rl.subjects[subject]) {
+		rl.subjects[subject] = issuedBefore
+	}
+	return nil
+}
+
+func (rl *memoryRevocationList) IsRevoked(id string, subject string, issuedAt time.Time) (bool, error) {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	if _, revoked := rl.tokens[id]; revoked && id != "" {
+		return true, nil
+	}
+	if revokedAt, ok := rl.subjects[subjectKey(subject)]; ok && !issuedAt.After(revokedAt) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// subjectKey returns the subject (system|value) with its value in lower case without surrounding whitespace,
+// as providers such as NADEX do not distinguish usernames by case
+func subjectKey(subject string) string {
+	ids := strings.SplitN(subject, "|", 2)
+	if len(ids) != 2 {
+		return subject
+	}
+	return ids[0] + "|" + strings.ToLower(strings.TrimSpace(ids[1]))
+}
+
+// RevokeToken revokes the token specified, using the revocation list.
+// The token is not verified, so that it can be revoked by an administrator without access to the signing key.
+func RevokeToken(rl RevocationList, token string) error {
+	c := new(claims)
+	if _, _, err := new(jwt.Parser).ParseUnverified(strings.TrimPrefix(token, "Bearer "), c); err != nil {
+		return fmt.Errorf("auth: invalid token: %w", err)
+	}
+	if c.Id == "" {
+		return fmt.Errorf("auth: token has no token id: revoke by subject ('%s') instead", c.Subject)
+	}
+	return rl.RevokeToken(c.Id, time.Unix(c.ExpiresAt, 0))
+}
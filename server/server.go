@@ -2,23 +2,29 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
-	"github.com/rs/cors"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/tracing"
+	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	health "google.golang.org/grpc/health/grpc_health_v1"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/reflection"
 )
 
 // Provider represents a server provider - providing GRPC server implementation
@@ -40,6 +46,23 @@ type Server struct {
 	Options
 	auth      *Auth
 	providers map[string]Provider
+	unary     []grpc.UnaryServerInterceptor
+	stream    []grpc.StreamServerInterceptor
+	auditor   Auditor
+	checks    map[string]HealthCheck
+	certs     *certReloader     // server certificate loaded from files, if configured
+	acme      *autocert.Manager // server certificates obtained using ACME, if configured
+	degraded  DegradedFunc      // whether a service is intentionally degraded, for readiness
+	draining  int32             // set, atomically, once the server is shutting down
+}
+
+// HealthCheck checks the health of a named service, returning an error if it is not serving
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecker is an optional interface for a Provider that can check the health of its backend
+// services, reported by the gRPC health service using the name with which the provider is registered.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
 }
 
 // New creates a new server
@@ -51,12 +74,38 @@ func New(opts Options) *Server {
 
 // Options defines the options for a server.
 type Options struct {
-	RPCPort     int // port for main gRPC server
-	RESTPort    int // port for a gRPC gateway - switched off if zero
-	GRPCWebPort int // port for a gRPC-Web server - switched off if zero
+	RPCPort     int    // port for main gRPC server
+	RESTPort    int    // port for a gRPC gateway - switched off if zero
+	GRPCWebPort int    // port for a gRPC-Web server - switched off if zero
+	MetricsPath string // path on the HTTP server for prometheus metrics (e.g. "/metrics") - switched off if empty
+	OpenAPIPath string // path on the HTTP server for the OpenAPI definition and Swagger UI (e.g. "/openapi") - switched off if empty
+	Reflection  bool   // whether to enable gRPC server reflection, so that clients can discover services
+	Version     string // version reported in the OpenAPI definition
+
+	LivenessPath     string        // path on the HTTP server for a liveness probe (e.g. "/healthz") - switched off if empty
+	ReadinessPath    string        // path on the HTTP server for a readiness probe (e.g. "/readyz") - switched off if empty
+	CriticalServices []string      // services, as named for health checks, that must be available for the server to be ready
+	DrainTimeout     time.Duration // time permitted for in-flight requests to complete on shutdown - DefaultDrainTimeout if zero
+
+	CertFile           string
+	KeyFile            string
+	CertReloadInterval time.Duration // interval at which the certificate and key files are checked for renewal - DefaultCertReloadInterval if zero
+
+	ACMEHosts        []string // hosts for which to obtain certificates automatically using ACME (e.g. Let's Encrypt), instead of using files
+	ACMEEmail        string   // contact email address for the ACME account, optional
+	ACMECacheDir     string   // directory in which to cache certificates obtained using ACME, so they are not requested on every start
+	ACMEDirectoryURL string   // ACME directory URL - Let's Encrypt if empty
+
+	ClientCAFile       string // CA certificate(s) used to verify client certificates on the gRPC port - switched off if empty
+	ClientCertRequired bool   // whether a client certificate is required, rather than optional
+
+	CORSOrigins []string // origins permitted for cross-origin requests to the REST gateway and gRPC-Web (e.g. "https://app.example.nhs.uk") - all if empty
+	CORSHeaders []string // additional request headers permitted for cross-origin requests, when origins are configured
 
-	CertFile string
-	KeyFile  string
+	ReadTimeout     time.Duration // time permitted to read a request to the HTTP gateway - DefaultReadTimeout if zero
+	WriteTimeout    time.Duration // time permitted to process a request to the HTTP gateway and write the response - DefaultWriteTimeout if zero
+	MaxRequestBytes int64         // maximum size of a request body to the HTTP gateway - DefaultMaxRequestBytes if zero
+	Routes          []Route       // overrides of timeouts and maximum request sizes for specific routes of the HTTP gateway
 }
 
 // Close frees up any associated resources
@@ -74,6 +123,39 @@ func (sv *Server) RegisterAuthenticator(auth *Auth) {
 	sv.auth = auth
 }
 
+// RegisterInterceptor registers interceptors that will run after authentication, so that
+// the authenticated user is available from the context. Either may be nil.
+// This should not be called once server is running.
+func (sv *Server) RegisterInterceptor(unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) {
+	if unary != nil {
+		sv.unary = append(sv.unary, unary)
+	}
+	if stream != nil {
+		sv.stream = append(sv.stream, stream)
+	}
+}
+
+// Auditor records calls rejected because the caller is not authenticated, or is not permitted to make the call.
+// Such calls do not reach interceptors registered using RegisterInterceptor.
+type Auditor interface {
+	Rejected(ctx context.Context, method string, req interface{}, err error)
+}
+
+// RegisterAuditor registers an auditor for calls rejected by authentication or authorisation.
+// This should not be called once server is running.
+func (sv *Server) RegisterAuditor(a Auditor) {
+	sv.auditor = a
+}
+
+// RegisterHealthCheck registers a health check for the named service, reported by the gRPC health service.
+// This should not be called once server is running.
+func (sv *Server) RegisterHealthCheck(name string, check HealthCheck) {
+	if sv.checks == nil {
+		sv.checks = make(map[string]HealthCheck)
+	}
+	sv.checks[name] = check
+}
+
 // Register registers a provider with the server.
 // This should not be called once server is running.
 func (sv *Server) Register(name string, p Provider) {
@@ -84,6 +166,186 @@ func (sv *Server) Register(name string, p Provider) {
 	log.Printf("server: registered provider: '%s'", name)
 }
 
+// Providers returns the names of the registered providers, sorted by name
+func (sv *Server) Providers() []string {
+	result := make([]string, 0, len(sv.providers))
+	for name := range sv.providers {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// HealthChecks returns the names of the services with health checks, including providers with their own
+// health check, sorted by name
+func (sv *Server) HealthChecks() []string {
+	result := make([]string, 0, len(sv.checks))
+	for name := range sv.checks {
+		result = append(result, name)
+	}
+	for name, p := range sv.providers {
+		if _, registered := sv.checks[name]; !registered {
+			if _, ok := p.(HealthChecker); ok {
+				result = append(result, name)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// tlsEnabled returns whether the servers use TLS, with a certificate from files or obtained using ACME
+func (sv *Server) tlsEnabled() bool {
+	return (sv.Options.CertFile != "" && sv.Options.KeyFile != "") || len(sv.Options.ACMEHosts) > 0
+}
+
+// loadCertificates loads the server certificate from files, or configures ACME, if not already loaded
+func (sv *Server) loadCertificates() error {
+	if sv.certs != nil || sv.acme != nil {
+		return nil
+	}
+	if len(sv.Options.ACMEHosts) > 0 {
+		sv.acme = sv.newACMEManager()
+		return nil
+	}
+	certs, err := newCertReloader(sv.Options.CertFile, sv.Options.KeyFile)
+	if err != nil {
+		return err
+	}
+	sv.certs = certs
+	return nil
+}
+
+// certificateConfig returns a TLS configuration providing the current server certificate for each connection,
+// so that renewed certificates are used without a restart
+func (sv *Server) certificateConfig() (*tls.Config, error) {
+	if err := sv.loadCertificates(); err != nil {
+		return nil, err
+	}
+	if sv.acme != nil {
+		return sv.acme.TLSConfig(), nil
+	}
+	return &tls.Config{GetCertificate: sv.certs.getCertificate}, nil
+}
+
+func (sv *Server) certReloadInterval() time.Duration {
+	if sv.Options.CertReloadInterval > 0 {
+		return sv.Options.CertReloadInterval
+	}
+	return DefaultCertReloadInterval
+}
+
+// serverTLSConfig returns the TLS configuration for the gRPC server, verifying client certificates
+// using the client CA, if configured
+func (sv *Server) serverTLSConfig() (*tls.Config, error) {
+	if sv.Options.ClientCertRequired && sv.Options.ClientCAFile == "" {
+		return nil, fmt.Errorf("server: client certificates required but no client CA specified")
+	}
+	config, err := sv.certificateConfig()
+	if err != nil {
+		return nil, err
+	}
+	if sv.Options.ClientCAFile != "" {
+		pool, err := loadCertPool(sv.Options.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if sv.Options.ClientCertRequired {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		log.Printf("server: verifying client certificates using '%s' (required: %t)", sv.Options.ClientCAFile, sv.Options.ClientCertRequired)
+	}
+	return config, nil
+}
+
+// gatewayTLSConfig returns the TLS configuration used by the HTTP gateway to connect to the gRPC server.
+// If client certificates are required, the gateway presents the server's own certificate, which must
+// therefore also be issued by the client CA. The server certificate is not mapped to a service account,
+// so requests made via the gateway must still be authenticated using a token.
+func (sv *Server) gatewayTLSConfig() (*tls.Config, error) {
+	if err := sv.loadCertificates(); err != nil {
+		return nil, err
+	}
+	if sv.acme != nil {
+		if sv.Options.ClientCertRequired {
+			return nil, fmt.Errorf("server: client certificates cannot be required when using ACME")
+		}
+		return &tls.Config{ServerName: sv.Options.ACMEHosts[0]}, nil // publicly trusted, as issued using ACME
+	}
+	// the server's certificate is verified against the current certificate file, rather than a fixed pool,
+	// so that the gateway continues to connect once the certificate has been reloaded
+	config := &tls.Config{
+		InsecureSkipVerify:    true, // verified by VerifyPeerCertificate
+		VerifyPeerCertificate: sv.certs.verifyServer("localhost"),
+	}
+	if sv.Options.ClientCertRequired {
+		config.GetClientCertificate = sv.certs.getClientCertificate
+		if sv.auth != nil {
+			sv.auth.gatewayCertificate = sv.certs.isCertificate
+		}
+	}
+	return config, nil
+}
+
+func loadCertPool(filename string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("server: no certificates found in '%s'", filename)
+	}
+	return pool, nil
+}
+
+// newGRPCServer creates a gRPC server with the registered providers, interceptors and health service
+func (sv *Server) newGRPCServer() (*grpc.Server, error) {
+	opts := tracing.ServerOptions() // outermost, so that requests that fail authentication are traced
+	if sv.auth != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(sv.unaryAuthInterceptor))
+		opts = append(opts, grpc.ChainStreamInterceptor(sv.streamAuthInterceptor))
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(i18n.UnaryServerInterceptor), grpc.ChainStreamInterceptor(i18n.StreamServerInterceptor))
+	opts = append(opts, grpc.ChainUnaryInterceptor(sv.unary...), grpc.ChainStreamInterceptor(sv.stream...))
+	if sv.tlsEnabled() {
+		config, err := sv.serverTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(config)))
+	} else if sv.Options.ClientCAFile != "" {
+		return nil, fmt.Errorf("server: client certificate authentication requires a server certificate and key")
+	}
+	if n := sv.maxMessageBytes(); n > DefaultMaxRequestBytes {
+		opts = append(opts, grpc.MaxRecvMsgSize(n))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	health.RegisterHealthServer(grpcServer, sv)
+	for name, provider := range sv.providers {
+		provider.RegisterServer(grpcServer)
+		log.Printf("server: registered '%s' service", name)
+	}
+	if sv.Options.Reflection {
+		reflection.Register(grpcServer)
+		log.Printf("server: enabled gRPC server reflection")
+	}
+	return grpcServer, nil
+}
+
+// ServeGRPC serves only the gRPC API on the listener specified, without the HTTP gateway, until the
+// listener is closed. This is useful when embedding concierge, such as in integration tests.
+func (sv *Server) ServeGRPC(lis net.Listener) error {
+	grpcServer, err := sv.newGRPCServer()
+	if err != nil {
+		return err
+	}
+	defer grpcServer.Stop()
+	return grpcServer.Serve(lis)
+}
+
 // RunServer runs a GRPC and a gateway REST server concurrently
 func (sv *Server) RunServer() error {
 	ctx := context.Background()
@@ -101,38 +363,25 @@ func (sv *Server) RunServer() error {
 		return fmt.Errorf("failed to initialize TCP listen: %v", err)
 	}
 	defer lis.Close()
-	opts := make([]grpc.ServerOption, 0)
-	if sv.auth != nil {
-		opts = append(opts, grpc.UnaryInterceptor(sv.unaryAuthInterceptor))
-		opts = append(opts, grpc.StreamInterceptor(sv.streamAuthInterceptor))
-	}
-	if sv.Options.CertFile != "" && sv.Options.KeyFile != "" {
-		creds, err := credentials.NewServerTLSFromFile(sv.Options.CertFile, sv.Options.KeyFile)
-		if err != nil {
-			return err
-		}
-		opts = append(opts, grpc.Creds(creds))
-	}
-	grpcServer := grpc.NewServer(opts...)
-	health.RegisterHealthServer(grpcServer, sv)
-	for name, provider := range sv.providers {
-		provider.RegisterServer(grpcServer)
-		log.Printf("server: registered '%s' service", name)
+	grpcServer, err := sv.newGRPCServer()
+	if err != nil {
+		return err
 	}
 
 	// configure HTTP reverse gateway
 	clientAddr := fmt.Sprintf("localhost:%d", sv.RPCPort)
 	addr := fmt.Sprintf(":%d", sv.RESTPort)
 	var dialOpts []grpc.DialOption
-	if sv.Options.CertFile == "" || sv.Options.KeyFile == "" {
+	if !sv.tlsEnabled() {
 		dialOpts = append(dialOpts, grpc.WithInsecure())
 	} else {
-		creds, err := credentials.NewClientTLSFromFile(sv.Options.CertFile, "")
+		config, err := sv.gatewayTLSConfig()
 		if err != nil {
 			return err
 		}
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
 	}
+	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(sv.maxMessageBytes())))
 	mux := runtime.NewServeMux(
 		runtime.WithIncomingHeaderMatcher(headerMatcher),                                    // handle Accept-Language
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: false}), // handle JSON camelcase
@@ -144,28 +393,57 @@ func (sv *Server) RunServer() error {
 			log.Printf("server: registered reverse http proxy for '%s'", name)
 		}
 	}
+	readTimeout, writeTimeout := sv.maxTimeouts()
 	httpServer := &http.Server{
-		Addr:         addr,
-		Handler:      mux,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	}
-
-	// add CORS configuration
-	log.Printf("server: warning: using CORS 'allow-all' permissions")
-	httpServer.Handler = cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{
-			http.MethodHead,
-			http.MethodGet,
-			http.MethodPost,
-			http.MethodPut,
-			http.MethodPatch,
-			http.MethodDelete,
-		},
-		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"*"},
-		AllowCredentials: true}).Handler(httpServer.Handler)
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: sv.readTimeout(),
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+	}
+	if sv.Options.MetricsPath != "" || sv.Options.OpenAPIPath != "" || sv.Options.LivenessPath != "" || sv.Options.ReadinessPath != "" || sv.auth != nil {
+		root := http.NewServeMux()
+		sv.registerProbes(root)
+		if sv.auth != nil {
+			root.Handle(JWKSPath, sv.auth.JWKSHandler())
+			log.Printf("server: serving token verification keys at %s", JWKSPath)
+		}
+		if sv.Options.MetricsPath != "" {
+			root.Handle(sv.Options.MetricsPath, metrics.Handler())
+			log.Printf("server: serving prometheus metrics at %s", sv.Options.MetricsPath)
+		}
+		if sv.Options.OpenAPIPath != "" {
+			if err := sv.registerOpenAPI(root, sv.Options.OpenAPIPath); err != nil {
+				return err
+			}
+		}
+		root.Handle("/", mux)
+		httpServer.Handler = root
+	}
+
+	// add request limits and CORS configuration
+	httpServer.Handler = sv.corsHandler(sv.limitHandler(httpServer.Handler))
+
+	// configure TLS for the HTTP servers, reloading the certificate on renewal
+	if sv.tlsEnabled() {
+		if httpServer.TLSConfig, err = sv.certificateConfig(); err != nil {
+			return err
+		}
+		if sv.certs != nil {
+			go sv.certs.watch(ctx, sv.certReloadInterval())
+		}
+	}
+
+	// configure gRPC-Web server, on its own port
+	var webServer *http.Server
+	webAddr := fmt.Sprintf(":%d", sv.GRPCWebPort)
+	if sv.GRPCWebPort != 0 {
+		webServer = &http.Server{
+			Addr:      webAddr,
+			Handler:   sv.newGRPCWebHandler(grpcServer),
+			TLSConfig: httpServer.TLSConfig,
+		}
+	}
 
 	// and now run the servers
 	g, ctx := errgroup.WithContext(ctx)
@@ -174,13 +452,23 @@ func (sv *Server) RunServer() error {
 		return grpcServer.Serve(lis)
 	})
 	g.Go(func() error {
-		if sv.Options.CertFile == "" || sv.Options.KeyFile == "" {
+		if !sv.tlsEnabled() {
 			log.Printf("server: http listening on %s (not using https: no certificate or key specified)", addr)
 			return httpServer.ListenAndServe()
 		}
 		log.Printf("server: https listening on %s\n", addr)
-		return httpServer.ListenAndServeTLS(sv.Options.CertFile, sv.Options.KeyFile)
+		return httpServer.ListenAndServeTLS("", "") // using the certificate from the TLS configuration
 	})
+	if webServer != nil {
+		g.Go(func() error {
+			if !sv.tlsEnabled() {
+				log.Printf("server: gRPC-Web listening on %s (not using https: no certificate or key specified)", webAddr)
+				return webServer.ListenAndServe()
+			}
+			log.Printf("server: gRPC-Web (https) listening on %s\n", webAddr)
+			return webServer.ListenAndServeTLS("", "")
+		})
+	}
 	select {
 	case sig := <-sigs:
 		log.Printf("server: received signal: %v", sig)
@@ -188,42 +476,53 @@ func (sv *Server) RunServer() error {
 	case <-ctx.Done():
 		break
 	}
-	// graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// graceful shutdown, no longer ready for new requests, but permitting in-flight requests to complete
+	sv.setDraining()
+	log.Printf("server: shutting down: draining in-flight requests (timeout: %s)", sv.drainTimeout())
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), sv.drainTimeout())
 	defer shutdownCancel()
 	if httpServer != nil {
 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
 			log.Print(err)
 		}
 	}
+	if webServer != nil {
+		if err := webServer.Shutdown(shutdownCtx); err != nil {
+			log.Print(err)
+		}
+	}
 	if grpcServer != nil {
-		grpcServer.GracefulStop()
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			log.Printf("server: timed out draining gRPC requests")
+			grpcServer.Stop()
+		}
 		log.Print("server: grpc server shutdown")
 	}
-	return g.Wait()
+	if err := g.Wait(); err != nil && err != http.ErrServerClosed && err != grpc.ErrServerStopped {
+		return err
+	}
+	return nil
 }
 
 // ensures GRPC gateway passes through the standard HTTP header Accept-Language as "accept-language"
-// rather than munging the name prefixed with grpcgateway.
+// rather than munging the name prefixed with grpcgateway, and similarly W3C trace context headers
+// so that traces started by HTTP clients are continued.
 // delegates to default implementation for other headers.
 func headerMatcher(headerName string) (mdName string, ok bool) {
-	if headerName == "Accept-Language" {
+	switch headerName {
+	case "Accept-Language":
 		return "accept-language", true
+	case "Traceparent":
+		return "traceparent", true
+	case "Tracestate":
+		return "tracestate", true
 	}
 	return runtime.DefaultHeaderMatcher(headerName)
 }
-
-// Check is a health check, implementing the grpc-health service
-// see https://godoc.org/google.golang.org/grpc/health/grpc_health_v1#HealthServer
-func (sv *Server) Check(ctx context.Context, r *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
-	response := new(health.HealthCheckResponse)
-	response.Status = health.HealthCheckResponse_SERVING
-	log.Printf("server: health check received: %s", response.Status)
-	return response, nil
-}
-
-// Watch is a streaming health check to issue changes in health status
-func (sv *Server) Watch(r *health.HealthCheckRequest, w health.Health_WatchServer) error {
-	log.Printf("server: health watch request received but not implemented: %+v", r)
-	return status.Error(codes.Unimplemented, "grpc health watch operation not implemented")
-}
@@ -0,0 +1,113 @@
+package simulator
+
+import (
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Default returns the built-in dataset, which contains:
+//   - Albert Dummy (NHS number 1111111111, CAV CRN A999998), whose record A999995 has been merged into A999998
+//   - Alice Dummy (NHS number 2222222222, CAV CRN A999997)
+//   - Bertie Dummy (NHS number 3333333333, CAV CRN A999996), who has died
+//   - Jane Smith (NHS number 9434765919), who is registered in England and so known only to PDS
+//   - four users of the NHS Wales' directory
+//   - a morning clinic NEUR01, with a free slot
+//   - a single document for Albert Dummy
+func Default() *Dataset {
+	return &Dataset{
+		Patients: []*Patient{
+			{Sources: []string{EMPI, CAV}, Patient: &apiv1.Patient{
+				Lastname:                    "Dummy",
+				Firstnames:                  "Albert",
+				Title:                       "Dr",
+				Gender:                      apiv1.Gender_MALE,
+				BirthDate:                   birthDate(1960, 1, 1),
+				Surgery:                     &apiv1.Identifier{System: identifiers.ODSCode, Value: "W95010"},
+				GeneralPractitioner:         &apiv1.Identifier{System: identifiers.GMPNumber, Value: "G9342400"},
+				NhsNumberVerificationStatus: apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED,
+				Identifiers: []*apiv1.Identifier{
+					{System: identifiers.NHSNumber, Value: "1111111111"},
+					{System: identifiers.CardiffAndValeCRN, Value: "A999998"},
+					{System: identifiers.SwanseaBayCRN, Value: "M1147907"},
+				},
+				Addresses:  []*apiv1.Address{{Address1: "59 Robins Hill", Address2: "Brackla", Address3: "Bridgend", Postcode: "CF31 2PJ", Country: "WALES"}},
+				Telephones: []*apiv1.Telephone{{Number: "02920 747747", Description: "Home", Use: apiv1.Telephone_HOME}, {Number: "02920 711711", Description: "Mobile", Use: apiv1.Telephone_MOBILE}},
+				Emails:     []string{"test@test.com", "wibble@test.com"},
+			}},
+			{Sources: []string{EMPI, CAV}, Patient: &apiv1.Patient{
+				Lastname:                    "Dummy",
+				Firstnames:                  "Alice",
+				Title:                       "Mrs",
+				Gender:                      apiv1.Gender_FEMALE,
+				BirthDate:                   birthDate(1972, 6, 1),
+				Surgery:                     &apiv1.Identifier{System: identifiers.ODSCode, Value: "W97016"},
+				NhsNumberVerificationStatus: apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED,
+				Identifiers: []*apiv1.Identifier{
+					{System: identifiers.NHSNumber, Value: "2222222222"},
+					{System: identifiers.CardiffAndValeCRN, Value: "A999997"},
+				},
+				Addresses: []*apiv1.Address{{Address1: "1 Heol y Nant", Address2: "Whitchurch", Address3: "Cardiff", Postcode: "CF14 1AA", Country: "WALES"}},
+			}},
+			{Sources: []string{EMPI, CAV}, Patient: &apiv1.Patient{
+				Lastname:                    "Dummy",
+				Firstnames:                  "Bertie",
+				Title:                       "Mr",
+				Gender:                      apiv1.Gender_MALE,
+				BirthDate:                   birthDate(1932, 11, 20),
+				Deceased:                    &apiv1.Patient_DeceasedDate{DeceasedDate: date(2019, 12, 1)},
+				NhsNumberVerificationStatus: apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED,
+				Identifiers: []*apiv1.Identifier{
+					{System: identifiers.NHSNumber, Value: "3333333333"},
+					{System: identifiers.CardiffAndValeCRN, Value: "A999996"},
+				},
+				Addresses: []*apiv1.Address{{Address1: "1 Heol y Nant", Address2: "Whitchurch", Address3: "Cardiff", Postcode: "CF14 1AA", Country: "WALES"}},
+			}},
+			{Sources: []string{PDS}, Patient: &apiv1.Patient{
+				Lastname:    "Smith",
+				Firstnames:  "Jane Elizabeth",
+				Title:       "Mrs",
+				Gender:      apiv1.Gender_FEMALE,
+				BirthDate:   birthDate(1975, 3, 14),
+				Surgery:     &apiv1.Identifier{System: identifiers.ODSCode, Value: "Y12345"},
+				Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "9434765919"}},
+				Addresses:   []*apiv1.Address{{Address1: "1 Trevelyan Square", Address2: "Boar Lane", Address3: "Leeds", Postcode: "LS1 6AE"}},
+			}},
+		},
+		Merges: []*Merge{
+			{From: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999995"}, To: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}},
+		},
+		Practitioners: []*Practitioner{
+			{Username: "ma090906", Title: "Dr", FirstNames: "Mark", LastName: "Wardle", Department: "Neurology", JobTitle: "Consultant Neurologist", Email: "mark.wardle@wales.nhs.uk", GMC: "4624000"},
+			{Username: "fl012345", Title: "Mr", FirstNames: "Fred", LastName: "Flintstone", Department: "Neurology", JobTitle: "Specialist Nurse", Email: "fred.flintstone@wales.nhs.uk"},
+			{Username: "fl067890", Title: "Dr", FirstNames: "Wilma", LastName: "Flintstone", Department: "Cardiology", JobTitle: "Consultant Cardiologist", Email: "wilma.flintstone@wales.nhs.uk"},
+			{Username: "ru054321", Title: "Mr", FirstNames: "Barney", LastName: "Rubble", Department: "Medical Physics", JobTitle: "Clinical Scientist", Email: "barney.rubble@wales.nhs.uk"},
+		},
+		Clinics: []*Clinic{
+			{Code: "NEUR01", Name: "Neurology outpatients", Location: "University Hospital of Wales", Specialty: "400", Clinician: &Practitioner{Title: "Dr", FirstNames: "Mark", LastName: "Wardle", GMC: "4616734"}, Slots: []*Slot{
+				{Start: "09:00", End: "09:30", VisitType: "New", Patient: "A999998"},
+				{Start: "09:30", End: "09:45", VisitType: "Follow up"},
+				{Start: "09:45", End: "10:00", VisitType: "Follow up", Patient: "A999997"},
+			}},
+		},
+		Admissions: []*Admission{
+			{ID: "1000001", Patient: "A999997", Admitted: "2020-06-01 14:30", Ward: "C5", WardName: "Ward C5 North", Bed: "12", Specialty: "400", Consultant: &Practitioner{Title: "Dr", FirstNames: "Mark", LastName: "Wardle", GMC: "4616734"}},
+		},
+		Documents: []*Document{
+			{ID: "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1", Patient: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}, Title: "Clinic letter", ContentType: "application/pdf", Data: minimalPDF},
+		},
+	}
+}
+
+// minimalPDF is a valid PDF document containing a single blank page
+var minimalPDF = []byte("%PDF-1.4\n1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj 2 0 obj<</Type/Pages/Kids[3 0 R]/Count 1>>endobj 3 0 obj<</Type/Page/MediaBox[0 0 612 792]/Parent 2 0 R>>endobj\ntrailer<</Root 1 0 R>>\n%%EOF\n")
+
+func birthDate(year int, month time.Month, day int) *apiv1.Date {
+	return &apiv1.Date{Year: int32(year), Month: int32(month), Day: int32(day)}
+}
+
+func date(year int, month time.Month, day int) *timestamppb.Timestamp {
+	return timestamppb.New(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
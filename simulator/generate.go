@@ -0,0 +1,145 @@
+package simulator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// reference is the date from which ages are calculated in generated datasets, so that these are
+// the same whenever generated
+var reference = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	maleNames   = []string{"Aled", "Bryn", "Cai", "Dafydd", "Emrys", "Gareth", "Huw", "Iwan", "Rhys", "Tomos", "John", "David", "Michael", "Peter"}
+	femaleNames = []string{"Angharad", "Bethan", "Cerys", "Eleri", "Ffion", "Gwen", "Lowri", "Megan", "Nia", "Sian", "Mary", "Sarah", "Emma", "Rachel"}
+	lastNames   = []string{"Jones", "Williams", "Davies", "Evans", "Thomas", "Roberts", "Hughes", "Lewis", "Morgan", "Griffiths", "Price", "Jenkins", "Owen", "Rees", "Pritchard"}
+	streets     = []string{"Heol y Nant", "Ffordd Las", "Church Road", "Park Place", "Station Road", "Cathedral Road", "Heol Llanishen Fach", "Windsor Road"}
+	towns       = []struct{ town, postcode string }{{"Cardiff", "CF14"}, {"Penarth", "CF64"}, {"Barry", "CF62"}, {"Bridgend", "CF31"}, {"Pontypridd", "CF37"}, {"Caerphilly", "CF83"}}
+	surgeries   = []string{"W97016", "W97024", "W97036", "W97041", "W95010", "W97060"}
+	departments = []string{"Neurology", "Cardiology", "Medical Physics", "Rheumatology", "Respiratory Medicine"}
+	visitTypes  = []string{"New", "Follow up", "Follow up", "Follow up"}
+)
+
+// Generate returns a dataset of n patients, with practitioners, clinics and documents in proportion,
+// generated deterministically from the seed specified. Generated NHS numbers begin with 999 and so
+// cannot belong to real patients.
+func Generate(seed int64, n int) *Dataset {
+	r := rand.New(rand.NewSource(seed))
+	pick := func(s []string) string { return s[r.Intn(len(s))] }
+	ds := new(Dataset)
+	var crns []string
+	next := 0
+	for i := 0; i < n; i++ {
+		gender, title, first := apiv1.Gender_MALE, "Mr", pick(maleNames)
+		if r.Intn(2) == 0 {
+			gender, title, first = apiv1.Gender_FEMALE, "Mrs", pick(femaleNames)
+		}
+		birth := reference.AddDate(-18-r.Intn(80), 0, -r.Intn(365))
+		nnn, ok := nhsNumber(next)
+		for ; !ok; nnn, ok = nhsNumber(next) {
+			next++
+		}
+		next++
+		crn := fmt.Sprintf("A%06d", 100000+i)
+		town := towns[r.Intn(len(towns))]
+		pt := &apiv1.Patient{
+			Lastname:                    pick(lastNames),
+			Firstnames:                  first,
+			Title:                       title,
+			Gender:                      gender,
+			BirthDate:                   apiv1.NewDate(birth),
+			Surgery:                     &apiv1.Identifier{System: identifiers.ODSCode, Value: pick(surgeries)},
+			GeneralPractitioner:         &apiv1.Identifier{System: identifiers.GMPNumber, Value: fmt.Sprintf("G%07d", r.Intn(10000000))},
+			NhsNumberVerificationStatus: apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED,
+			Identifiers:                 []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: nnn}},
+			Addresses: []*apiv1.Address{{
+				Address1: fmt.Sprintf("%d %s", 1+r.Intn(150), pick(streets)),
+				Address3: town.town,
+				Postcode: fmt.Sprintf("%s %d%c%c", town.postcode, r.Intn(10), 'A'+r.Intn(26), 'A'+r.Intn(26)),
+				Country:  "WALES",
+			}},
+			Telephones: []*apiv1.Telephone{{Number: fmt.Sprintf("029 20%06d", r.Intn(1000000)), Description: "Home", Use: apiv1.Telephone_HOME}},
+		}
+		if r.Intn(20) == 0 {
+			died := birth.AddDate(0, 0, r.Intn(int(reference.Sub(birth).Hours()/24)))
+			pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: date(died.Year(), died.Month(), died.Day())}
+		}
+		sources := []string{EMPI}
+		if r.Intn(10) < 7 {
+			sources = append(sources, CAV)
+			pt.Identifiers = append(pt.Identifiers, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn})
+			crns = append(crns, crn)
+			if r.Intn(50) == 0 {
+				ds.Merges = append(ds.Merges, &Merge{
+					From: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: fmt.Sprintf("A%06d", 900000+i)},
+					To:   &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn},
+				})
+			}
+		}
+		ds.Patients = append(ds.Patients, &Patient{Sources: sources, Patient: pt})
+	}
+	for i := 0; i < n/10+1; i++ {
+		first, last := pick(append(maleNames, femaleNames...)), pick(lastNames)
+		p := &Practitioner{
+			Username:   fmt.Sprintf("%s%06d", strings.ToLower(last[:2]), r.Intn(1000000)),
+			Title:      "Dr",
+			FirstNames: first,
+			LastName:   last,
+			Department: pick(departments),
+			Email:      strings.ToLower(first + "." + last + "@wales.nhs.uk"),
+			GMC:        fmt.Sprintf("%07d", 1000000+r.Intn(9000000)),
+		}
+		p.JobTitle = "Consultant in " + p.Department
+		ds.Practitioners = append(ds.Practitioners, p)
+	}
+	for i := 0; i < n/20+1; i++ {
+		c := &Clinic{Code: fmt.Sprintf("CLIN%02d", i+1), Clinician: ds.Practitioners[r.Intn(len(ds.Practitioners))]}
+		c.Name = c.Clinician.Department + " outpatients"
+		start := 9 * 60
+		for j := 0; j < 6; j++ {
+			length := 15 * (1 + r.Intn(2))
+			slot := &Slot{Start: clock(start), End: clock(start + length), VisitType: pick(visitTypes)}
+			if len(crns) > 0 && r.Intn(4) != 0 {
+				slot.Patient = crns[r.Intn(len(crns))]
+			}
+			c.Slots = append(c.Slots, slot)
+			start += length
+		}
+		ds.Clinics = append(ds.Clinics, c)
+	}
+	for _, crn := range crns {
+		if r.Intn(2) == 0 {
+			id := uuid.NewSHA1(uuid.NameSpaceOID, randomBytes(r, 16)).String()
+			ds.Documents = append(ds.Documents, &Document{ID: id, Patient: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn}, Title: "Clinic letter", ContentType: "application/pdf", Data: minimalPDF})
+		}
+	}
+	return ds
+}
+
+// nhsNumber returns the NHS number beginning with 999 followed by the number specified, returning false
+// if there is no valid NHS number as the check digit would be 10
+func nhsNumber(n int) (string, bool) {
+	s := fmt.Sprintf("999%06d", n%1000000)
+	sum := 0
+	for i, c := range s {
+		sum += int(c-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	return s + string(rune('0'+check)), check != 10
+}
+
+func clock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
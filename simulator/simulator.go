@@ -0,0 +1,371 @@
+// Package simulator provides an in-memory dataset of patients, practitioners, clinics and documents,
+// used by back-end services running in fake mode so that integration tests and demonstration
+// environments behave realistically without live back-end services.
+//
+// Each patient record is held by one or more back-ends (e.g. the EMPI and Cardiff and Vale PMS), so that
+// lookups of patients not held by a back-end fail with NotFound, as they would in a live environment.
+// Merged records are represented by the identifiers that have been superseded, so that lookups using a
+// superseded identifier return the surviving record.
+//
+// A dataset may be loaded from JSON fixtures, or generated deterministically from a seed:
+//
+//	{
+//	  "patients": [{"sources": ["empi", "cav"], "patient": {"lastname": "DUMMY", "identifiers": [...]}}],
+//	  "merges": [{"from": {"system": "...", "value": "A999995"}, "to": {"system": "...", "value": "A999998"}}],
+//	  "practitioners": [{"username": "ma090906", "lastName": "Wardle", "department": "Neurology"}],
+//	  "clinics": [{"code": "NEUR01", "clinician": {...}, "slots": [{"start": "09:00", "end": "09:30", "patient": "A999998"}]}],
+//	  "admissions": [{"id": "1000001", "patient": "A999997", "admitted": "2020-06-01 14:30", "ward": "C5", "bed": "12"}],
+//	  "documents": [{"id": "...", "patient": {...}, "title": "Clinic letter", "contentType": "application/pdf", "data": "..."}]
+//	}
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Back-end services that may hold patient records
+const (
+	EMPI = "empi" // NHS Wales' enterprise master patient index
+	CAV  = "cav"  // Cardiff and Vale patient management system
+	PDS  = "pds"  // NHS England's personal demographics service
+)
+
+// Dataset is an in-memory dataset used to simulate back-end services. This is thread-safe.
+type Dataset struct {
+	Patients      []*Patient      `json:"patients,omitempty"`
+	Merges        []*Merge        `json:"merges,omitempty"`
+	Practitioners []*Practitioner `json:"practitioners,omitempty"`
+	Clinics       []*Clinic       `json:"clinics,omitempty"`
+	Admissions    []*Admission    `json:"admissions,omitempty"`
+	Documents     []*Document     `json:"documents,omitempty"`
+
+	mu sync.RWMutex
+}
+
+// Patient is a patient record held by one or more back-end services
+type Patient struct {
+	Sources []string // back-ends holding this record, e.g. empi, cav; all back-ends if empty
+	Patient *apiv1.Patient
+}
+
+// Merge records that an identifier has been superseded by another, as a result of a merge of patient records
+type Merge struct {
+	From *apiv1.Identifier `json:"from"`
+	To   *apiv1.Identifier `json:"to"`
+}
+
+// Practitioner is a user of the NHS Wales' directory service
+type Practitioner struct {
+	Username   string `json:"username"`
+	Title      string `json:"title,omitempty"`
+	FirstNames string `json:"firstNames,omitempty"`
+	LastName   string `json:"lastName"`
+	Department string `json:"department,omitempty"`
+	JobTitle   string `json:"jobTitle,omitempty"`
+	Email      string `json:"email,omitempty"`
+	GMC        string `json:"gmc,omitempty"` // GMC number, for doctors
+}
+
+// Clinic is an outpatient clinic, with slots that are repeated each day
+type Clinic struct {
+	Code      string        `json:"code"`
+	Name      string        `json:"name,omitempty"`
+	Location  string        `json:"location,omitempty"`
+	Specialty string        `json:"specialty,omitempty"` // NHS data dictionary main specialty code
+	Clinician *Practitioner `json:"clinician,omitempty"`
+	Slots     []*Slot       `json:"slots,omitempty"`
+}
+
+// Slot is an appointment slot in a clinic
+type Slot struct {
+	Start     string `json:"start"` // HH:MM
+	End       string `json:"end"`   // HH:MM
+	VisitType string `json:"visitType,omitempty"`
+	Patient   string `json:"patient,omitempty"` // identifier of patient booked in this slot in the clinic's system; free if empty
+}
+
+// Admission is a current inpatient admission to a ward
+type Admission struct {
+	ID         string        `json:"id"`
+	Patient    string        `json:"patient"`  // identifier of patient in the hospital's system
+	Admitted   string        `json:"admitted"` // YYYY-MM-DD HH:MM
+	Ward       string        `json:"ward"`
+	WardName   string        `json:"wardName,omitempty"`
+	Bed        string        `json:"bed,omitempty"`
+	Specialty  string        `json:"specialty,omitempty"` // NHS data dictionary main specialty code
+	Consultant *Practitioner `json:"consultant,omitempty"`
+}
+
+// Document is a document held in a document repository
+type Document struct {
+	ID          string            `json:"id"`
+	Patient     *apiv1.Identifier `json:"patient,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Data        []byte            `json:"data"`
+}
+
+var (
+	mu      sync.RWMutex
+	current *Dataset
+)
+
+// Set sets the dataset used by back-end services in fake mode
+func Set(ds *Dataset) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = ds
+}
+
+// Current returns the dataset used by back-end services in fake mode, which is the default dataset unless set
+func Current() *Dataset {
+	mu.RLock()
+	ds := current
+	mu.RUnlock()
+	if ds != nil {
+		return ds
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		current = Default()
+	}
+	return current
+}
+
+// Load loads a dataset from the JSON fixtures file specified
+func Load(filename string) (*Dataset, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parse(b)
+}
+
+func parse(b []byte) (*Dataset, error) {
+	ds := new(Dataset)
+	if err := json.Unmarshal(b, ds); err != nil {
+		return nil, fmt.Errorf("simulator: invalid fixtures: %w", err)
+	}
+	return ds, nil
+}
+
+// Write writes the dataset as JSON fixtures
+func (ds *Dataset) Write(w io.Writer) error {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ds)
+}
+
+// UnmarshalJSON decodes a patient record, with the patient in the JSON format of the API
+func (p *Patient) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Sources []string        `json:"sources"`
+		Patient json.RawMessage `json:"patient"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Sources, p.Patient = v.Sources, new(apiv1.Patient)
+	return protojson.Unmarshal(v.Patient, p.Patient)
+}
+
+// MarshalJSON encodes a patient record, with the patient in the JSON format of the API
+func (p *Patient) MarshalJSON() ([]byte, error) {
+	pt, err := protojson.Marshal(p.Patient)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Sources []string        `json:"sources,omitempty"`
+		Patient json.RawMessage `json:"patient"`
+	}{p.Sources, pt})
+}
+
+func (p *Patient) heldBy(source string) bool {
+	if len(p.Sources) == 0 {
+		return true
+	}
+	for _, s := range p.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Patient) hasIdentifier(id *apiv1.Identifier) bool {
+	for _, pid := range p.Patient.GetIdentifiers() {
+		if sameIdentifier(pid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameIdentifier(a, b *apiv1.Identifier) bool {
+	return a.GetSystem() == b.GetSystem() && strings.EqualFold(a.GetValue(), b.GetValue())
+}
+
+// resolve returns the identifier of the surviving record for the identifier specified, following merges
+func (ds *Dataset) resolve(id *apiv1.Identifier) *apiv1.Identifier {
+	for i := 0; i <= len(ds.Merges); i++ {
+		found := false
+		for _, m := range ds.Merges {
+			if sameIdentifier(m.From, id) {
+				id, found = m.To, true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return id
+}
+
+// Patient returns a copy of the patient with the identifier specified held by the back-end specified.
+// A superseded identifier returns the surviving record.
+func (ds *Dataset) Patient(source string, id *apiv1.Identifier) (*apiv1.Patient, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	id = ds.resolve(id)
+	for _, p := range ds.Patients {
+		if p.heldBy(source) && p.hasIdentifier(id) {
+			return proto.Clone(p.Patient).(*apiv1.Patient), true
+		}
+	}
+	return nil, false
+}
+
+// SearchPatients returns copies of the patients held by the back-end specified that match the search.
+// The last name must match exactly, first names match by prefix, and the date of birth, gender and
+// postcode, if specified, must match.
+func (ds *Dataset) SearchPatients(source string, r *apiv1.PatientSearchRequest) []*apiv1.Patient {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	dob := r.GetBirthDate().Format("2006-01-02")
+	result := make([]*apiv1.Patient, 0)
+	for _, p := range ds.Patients {
+		pt := p.Patient
+		if !p.heldBy(source) || !strings.EqualFold(pt.GetLastname(), strings.TrimSpace(r.GetLastname())) {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToLower(pt.GetFirstnames()), strings.ToLower(strings.TrimSpace(r.GetFirstnames()))) {
+			continue
+		}
+		if r.GetGender() != apiv1.Gender_UNKNOWN && pt.GetGender() != r.GetGender() {
+			continue
+		}
+		if dob != "" && pt.GetBirthDate().Format("2006-01-02") != dob {
+			continue
+		}
+		if postcode := normalisePostcode(r.GetPostcode()); postcode != "" && !hasPostcode(pt, postcode) {
+			continue
+		}
+		result = append(result, proto.Clone(pt).(*apiv1.Patient))
+	}
+	return result
+}
+
+func normalisePostcode(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+func hasPostcode(pt *apiv1.Patient, postcode string) bool {
+	for _, a := range pt.GetAddresses() {
+		if normalisePostcode(a.GetPostcode()) == postcode {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatePatient replaces the record of the patient with the identifier specified, returning false if
+// there is no such patient. The back-ends holding the record are unchanged.
+func (ds *Dataset) UpdatePatient(id *apiv1.Identifier, pt *apiv1.Patient) bool {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	id = ds.resolve(id)
+	for _, p := range ds.Patients {
+		if p.hasIdentifier(id) {
+			p.Patient = proto.Clone(pt).(*apiv1.Patient)
+			return true
+		}
+	}
+	return false
+}
+
+// Practitioner returns the practitioner with the username specified
+func (ds *Dataset) Practitioner(username string) (*Practitioner, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	for _, p := range ds.Practitioners {
+		if strings.EqualFold(p.Username, username) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// AllPractitioners returns all practitioners
+func (ds *Dataset) AllPractitioners() []*Practitioner {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return append([]*Practitioner(nil), ds.Practitioners...)
+}
+
+// Clinic returns the clinic with the code specified
+func (ds *Dataset) Clinic(code string) (*Clinic, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	for _, c := range ds.Clinics {
+		if strings.EqualFold(c.Code, code) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Admission returns the current admission of the patient with the identifier specified, in the hospital's system
+func (ds *Dataset) Admission(patient string) (*Admission, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	for _, a := range ds.Admissions {
+		if strings.EqualFold(a.Patient, patient) {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// AddDocument adds a document, returning the number of documents held
+func (ds *Dataset) AddDocument(d *Document) int {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.Documents = append(ds.Documents, d)
+	return len(ds.Documents)
+}
+
+// Document returns the document with the identifier specified, or the most recently added, if it has been replaced
+func (ds *Dataset) Document(id string) (*Document, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	for i := len(ds.Documents) - 1; i >= 0; i-- {
+		if d := ds.Documents[i]; d.ID == id {
+			return d, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,106 @@
+package simulator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDefault(t *testing.T) {
+	ds := Default()
+	crn := func(value string) *apiv1.Identifier {
+		return &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: value}
+	}
+	pt, found := ds.Patient(CAV, crn("A999998"))
+	if !found || pt.GetLastname() != "Dummy" || pt.GetFirstnames() != "Albert" {
+		t.Fatalf("expected Albert Dummy for A999998, got %v", pt)
+	}
+	if merged, found := ds.Patient(CAV, crn("a999995")); !found || !proto.Equal(merged, pt) {
+		t.Errorf("expected merged record A999995 to return A999998, got %v", merged)
+	}
+	if _, found := ds.Patient(CAV, crn("A000000")); found {
+		t.Error("expected unknown CRN to be not found")
+	}
+	nnn := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9434765919"}
+	if _, found := ds.Patient(EMPI, nnn); found {
+		t.Error("expected patient in England to be not found in EMPI")
+	}
+	if _, found := ds.Patient(PDS, nnn); !found {
+		t.Error("expected patient in England to be found in PDS")
+	}
+	pt.Lastname = "Changed"
+	if pt, _ = ds.Patient(CAV, crn("A999998")); pt.GetLastname() != "Dummy" {
+		t.Error("expected a copy of patient to be returned")
+	}
+	if pts := ds.SearchPatients(EMPI, &apiv1.PatientSearchRequest{Lastname: "dummy", Firstnames: "al"}); len(pts) != 2 {
+		t.Errorf("expected two patients matching search, got %d", len(pts))
+	}
+	if pts := ds.SearchPatients(EMPI, &apiv1.PatientSearchRequest{Lastname: "dummy", Postcode: "cf312pj"}); len(pts) != 1 || pts[0].GetFirstnames() != "Albert" {
+		t.Errorf("expected one patient matching search by postcode, got %v", pts)
+	}
+	pt.Emails = []string{"new@example.com"}
+	if ds.UpdatePatient(&apiv1.Identifier{System: identifiers.NHSNumber, Value: "4444444444"}, pt) {
+		t.Error("expected update of unknown patient to fail")
+	}
+	if !ds.UpdatePatient(crn("A999995"), pt) {
+		t.Error("expected update using merged record to succeed")
+	}
+	if pt, _ = ds.Patient(EMPI, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}); len(pt.GetEmails()) != 1 {
+		t.Errorf("expected updated patient, got %v", pt)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ds1, ds2 := Generate(42, 200), Generate(42, 200)
+	var b1, b2 bytes.Buffer
+	if err := ds1.Write(&b1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds2.Write(&b2); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Fatal("expected generated datasets to be the same for the same seed")
+	}
+	if len(ds1.Patients) != 200 || len(ds1.Practitioners) == 0 || len(ds1.Clinics) == 0 || len(ds1.Documents) == 0 {
+		t.Fatalf("unexpected dataset: %d patients, %d practitioners, %d clinics, %d documents", len(ds1.Patients), len(ds1.Practitioners), len(ds1.Clinics), len(ds1.Documents))
+	}
+	seen := make(map[string]bool)
+	for _, p := range ds1.Patients {
+		nnn := p.Patient.GetIdentifiers()[0].GetValue()
+		if !validNHSNumber(nnn) || seen[nnn] {
+			t.Errorf("invalid or duplicate NHS number: %s", nnn)
+		}
+		seen[nnn] = true
+	}
+	for _, m := range ds1.Merges {
+		if _, found := ds1.Patient(CAV, m.From); !found {
+			t.Errorf("expected merged record %s to be found", m.From.GetValue())
+		}
+	}
+	ds, err := parse(b1.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := ds.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), b1.Bytes()) {
+		t.Fatal("expected fixtures to be the same once loaded")
+	}
+}
+
+func validNHSNumber(nnn string) bool {
+	if len(nnn) != 10 {
+		return false
+	}
+	sum := 0
+	for i, c := range nnn[:9] {
+		sum += int(c-'0') * (10 - i)
+	}
+	return (11-sum%11)%11 == int(nnn[9]-'0')
+}
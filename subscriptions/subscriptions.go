@@ -0,0 +1,267 @@
+// Package subscriptions provides a service that streams changes to patient records to subscribed
+// clients, so that they need not poll for demographic updates, deaths, merges or new documents.
+//
+// Changes are recorded from internal events, such as those published on receipt of an inbound
+// ADT message, or when a back-end returns changed demographics for a patient. Patients with active
+// subscriptions may be periodically re-queried, so that changes are discovered even without an ADT feed.
+//
+// Recent changes are retained in memory, so that a client that loses its connection can resume its
+// subscription using the cursor of the last event received, without missing any changes.
+package subscriptions
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Defaults
+const (
+	DefaultJournalSize     = 10000            // number of recent changes retained for resumption
+	DefaultRefreshInterval = 15 * time.Minute // interval at which subscribed patients are re-queried
+	MaxIdentifiers         = 100              // maximum number of identifiers in a single subscription
+)
+
+// Directory provides patient data, used to periodically re-query subscribed patients.
+// Changes are discovered by the back-ends of the directory, which publish events when demographics change.
+type Directory interface {
+	GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error)
+}
+
+// Options configures the subscription service
+type Options struct {
+	JournalSize     int           // number of recent changes retained for resumption
+	RefreshInterval time.Duration // interval at which subscribed patients are re-queried; not re-queried if zero
+}
+
+// Service streams changes to patient records to subscribers
+type Service struct {
+	directory Directory // optional
+	opts      Options
+	epoch     string // distinguishes cursors issued by this process from those issued before a restart
+
+	mu      sync.Mutex
+	journal []*entry      // recent changes, oldest first
+	seq     uint64        // sequence number of the most recent change
+	changed chan struct{} // closed, and replaced, whenever a change is recorded
+}
+
+// entry is a change recorded in the journal
+type entry struct {
+	seq     uint64
+	event   *apiv1.ChangeEvent
+	patient []*apiv1.Identifier // identifiers of the patient to whom the change relates
+}
+
+var _ apiv1.SubscriptionsServer = (*Service)(nil)
+
+// New creates a new subscription service, recording changes from events published within this process.
+// The directory is optional, and is used to periodically re-query subscribed patients.
+func New(directory Directory, opts Options) *Service {
+	if opts.JournalSize <= 0 {
+		opts.JournalSize = DefaultJournalSize
+	}
+	s := &Service{
+		directory: directory,
+		opts:      opts,
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		changed:   make(chan struct{}),
+	}
+	events.Subscribe(s.record)
+	return s
+}
+
+// RegisterServer registers this server
+func (s *Service) RegisterServer(sv *grpc.Server) {
+	apiv1.RegisterSubscriptionsServer(sv, s)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (s *Service) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterSubscriptionsHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (s *Service) Close() error { return nil }
+
+// record records a change to a patient record from an event
+func (s *Service) record(e *events.Event) {
+	ts := timestamppb.New(e.Time)
+	if ts.CheckValid() != nil {
+		return
+	}
+	ce := &apiv1.ChangeEvent{DateTime: ts}
+	var patient []*apiv1.Identifier
+	switch e.Type {
+	case events.PatientUpdated, events.PatientDeceased:
+		pt, ok := e.Data.(*apiv1.Patient)
+		if !ok {
+			return
+		}
+		ce.Type = apiv1.ChangeEvent_DEMOGRAPHICS_UPDATED
+		if e.Type == events.PatientDeceased {
+			ce.Type = apiv1.ChangeEvent_DECEASED
+		}
+		ce.Patient = pt
+		patient = append([]*apiv1.Identifier{e.Subject}, pt.GetIdentifiers()...)
+	case events.PatientMerged:
+		merged, ok := e.Data.(*apiv1.Identifier)
+		if !ok {
+			return
+		}
+		ce.Type = apiv1.ChangeEvent_MERGED
+		ce.Merged = merged
+		patient = []*apiv1.Identifier{e.Subject, merged}
+	case events.DocumentPublished:
+		ce.Type = apiv1.ChangeEvent_DOCUMENT_PUBLISHED
+		ce.DocumentId = e.Subject
+		patient = e.Patient
+	default:
+		return
+	}
+	if len(patient) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	s.journal = append(s.journal, &entry{seq: s.seq, event: ce, patient: patient})
+	if n := len(s.journal) - s.opts.JournalSize; n > 0 {
+		s.journal = append([]*entry(nil), s.journal[n:]...)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// since returns the changes recorded after the sequence number specified, the sequence number of the
+// most recent change, and a channel closed when a further change is recorded. The returned boolean is
+// false if changes after the sequence number specified are no longer retained.
+func (s *Service) since(seq uint64) ([]*entry, uint64, <-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.journal) > 0 && s.journal[0].seq > seq+1 {
+		return nil, s.seq, s.changed, false
+	}
+	var result []*entry
+	for _, e := range s.journal {
+		if e.seq > seq {
+			result = append(result, e)
+		}
+	}
+	return result, s.seq, s.changed, true
+}
+
+// cursor returns the cursor for the sequence number specified
+func (s *Service) cursor(seq uint64) string {
+	return s.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseCursor returns the sequence number for a cursor, and whether it was issued by this process
+func (s *Service) parseCursor(cursor string) (uint64, bool, error) {
+	parts := strings.SplitN(cursor, "-", 2)
+	if len(parts) != 2 {
+		return 0, false, strconv.ErrSyntax
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return seq, parts[0] == s.epoch, nil
+}
+
+// Subscribe streams changes to the records of the patients with the identifiers specified
+func (s *Service) Subscribe(r *apiv1.SubscribeRequest, stream apiv1.Subscriptions_SubscribeServer) error {
+	ctx := stream.Context()
+	if len(r.GetIdentifiers()) == 0 {
+		return i18n.Errorf(ctx, codes.InvalidArgument, "subscriptions: at least one identifier required")
+	}
+	if len(r.GetIdentifiers()) > MaxIdentifiers {
+		return i18n.Errorf(ctx, codes.InvalidArgument, "subscriptions: too many identifiers (maximum %d)", MaxIdentifiers)
+	}
+	subscribed := make(map[string]*apiv1.Identifier)
+	for _, id := range r.GetIdentifiers() {
+		if id.GetSystem() == "" || id.GetValue() == "" {
+			return i18n.Errorf(ctx, codes.InvalidArgument, "subscriptions: invalid identifier: %s|%s", id.GetSystem(), id.GetValue())
+		}
+		subscribed[id.GetSystem()+"|"+id.GetValue()] = id
+	}
+	_, seq, _, _ := s.since(0)
+	if r.GetCursor() != "" {
+		var current bool
+		var err error
+		if seq, current, err = s.parseCursor(r.GetCursor()); err != nil {
+			return i18n.Errorf(ctx, codes.InvalidArgument, "subscriptions: invalid cursor: %s", r.GetCursor())
+		}
+		if !current {
+			return i18n.Errorf(ctx, codes.OutOfRange, "subscriptions: cursor has expired; resubscribe without a cursor")
+		}
+	}
+	if s.directory != nil && s.opts.RefreshInterval > 0 {
+		go s.refresh(ctx, r.GetIdentifiers())
+	}
+	log.Printf("subscriptions: subscribed to %d patient(s)", len(subscribed))
+	for {
+		entries, last, changed, ok := s.since(seq)
+		if !ok {
+			return i18n.Errorf(ctx, codes.OutOfRange, "subscriptions: cursor has expired; resubscribe without a cursor")
+		}
+		for _, e := range entries {
+			if id := match(subscribed, e.patient); id != nil {
+				ce := proto.Clone(e.event).(*apiv1.ChangeEvent)
+				ce.Subject = id
+				ce.Cursor = s.cursor(e.seq)
+				if err := stream.Send(ce); err != nil {
+					return err
+				}
+			}
+		}
+		seq = last
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// match returns the subscribed identifier matching one of the identifiers specified, or nil
+func match(subscribed map[string]*apiv1.Identifier, ids []*apiv1.Identifier) *apiv1.Identifier {
+	for _, id := range ids {
+		if s, ok := subscribed[id.GetSystem()+"|"+id.GetValue()]; ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// refresh periodically re-queries the patients specified until the context is cancelled, so that
+// back-ends can detect, and publish, changes. Patients are queried immediately, to record the data
+// against which subsequent changes are detected.
+func (s *Service) refresh(ctx context.Context, ids []*apiv1.Identifier) {
+	ticker := time.NewTicker(s.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		for _, id := range ids {
+			if _, err := s.directory.GetPatient(ctx, id); err != nil && ctx.Err() == nil {
+				log.Printf("subscriptions: failed to refresh %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
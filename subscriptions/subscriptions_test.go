@@ -0,0 +1,127 @@
+package subscriptions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *apiv1.ChangeEvent
+}
+
+func (ts *testStream) Context() context.Context { return ts.ctx }
+
+func (ts *testStream) Send(ce *apiv1.ChangeEvent) error {
+	ts.events <- ce
+	return nil
+}
+
+// subscribe starts a subscription in the background, returning the stream and a channel for the result
+func subscribe(ctx context.Context, s *Service, r *apiv1.SubscribeRequest) (*testStream, chan error) {
+	stream := &testStream{ctx: ctx, events: make(chan *apiv1.ChangeEvent, 10)}
+	result := make(chan error, 1)
+	go func() { result <- s.Subscribe(r, stream) }()
+	return stream, result
+}
+
+func receive(t *testing.T, stream *testStream) *apiv1.ChangeEvent {
+	select {
+	case ce := <-stream.events:
+		return ce
+	case <-time.After(time.Second):
+		t.Fatal("did not receive change event")
+	}
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	s := New(nil, Options{JournalSize: 3})
+	nnn := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	crn := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}
+	other := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "2222222222"}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, result := subscribe(ctx, s, &apiv1.SubscribeRequest{Identifiers: []*apiv1.Identifier{nnn}})
+	time.Sleep(50 * time.Millisecond) // permit subscription to start
+
+	events.Publish(&events.Event{Type: events.PatientUpdated, Subject: other, Data: &apiv1.Patient{Identifiers: []*apiv1.Identifier{other}}})
+	events.Publish(&events.Event{Type: events.PatientUpdated, Subject: crn, Data: &apiv1.Patient{Lastname: "DUMMY", Identifiers: []*apiv1.Identifier{crn, nnn}}})
+	ce := receive(t, stream)
+	if ce.GetType() != apiv1.ChangeEvent_DEMOGRAPHICS_UPDATED || ce.GetSubject().GetValue() != nnn.GetValue() || ce.GetPatient().GetLastname() != "DUMMY" || ce.GetCursor() == "" {
+		t.Fatalf("unexpected change event: %v", ce)
+	}
+	cursor := ce.GetCursor()
+	events.Publish(&events.Event{Type: events.DocumentPublished, Subject: &apiv1.Identifier{System: "https://example.com/Id/document", Value: "1"}, Patient: []*apiv1.Identifier{nnn}})
+	if ce := receive(t, stream); ce.GetType() != apiv1.ChangeEvent_DOCUMENT_PUBLISHED || ce.GetDocumentId().GetValue() != "1" {
+		t.Fatalf("unexpected change event: %v", ce)
+	}
+	cancel()
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+
+	// resume from cursor, receiving changes missed since
+	events.Publish(&events.Event{Type: events.PatientMerged, Subject: nnn, Data: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999997"}})
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream, _ = subscribe(ctx, s, &apiv1.SubscribeRequest{Identifiers: []*apiv1.Identifier{nnn}, Cursor: cursor})
+	if ce := receive(t, stream); ce.GetType() != apiv1.ChangeEvent_DOCUMENT_PUBLISHED {
+		t.Fatalf("expected missed document event on resumption, got: %v", ce)
+	}
+	if ce := receive(t, stream); ce.GetType() != apiv1.ChangeEvent_MERGED || ce.GetMerged().GetValue() != "A999997" {
+		t.Fatalf("expected missed merge event on resumption, got: %v", ce)
+	}
+
+	// cursors no longer retained, or from another process, have expired
+	for i := 0; i < 3; i++ {
+		events.Publish(&events.Event{Type: events.PatientUpdated, Subject: other, Data: &apiv1.Patient{}})
+	}
+	for _, c := range []string{cursor, "abc-1"} {
+		_, result := subscribe(context.Background(), s, &apiv1.SubscribeRequest{Identifiers: []*apiv1.Identifier{nnn}, Cursor: c})
+		if err := <-result; status.Code(err) != codes.OutOfRange {
+			t.Errorf("expected expired cursor '%s', got: %v", c, err)
+		}
+	}
+	for _, r := range []*apiv1.SubscribeRequest{{}, {Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber}}}, {Identifiers: []*apiv1.Identifier{nnn}, Cursor: "invalid"}} {
+		_, result := subscribe(context.Background(), s, r)
+		if err := <-result; status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected invalid argument for %v, got: %v", r, err)
+		}
+	}
+}
+
+type testDirectory struct {
+	queried chan *apiv1.Identifier
+}
+
+func (td *testDirectory) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	td.queried <- id
+	return &apiv1.Patient{Identifiers: []*apiv1.Identifier{id}}, nil
+}
+
+func TestRefresh(t *testing.T) {
+	td := &testDirectory{queried: make(chan *apiv1.Identifier, 10)}
+	s := New(td, Options{RefreshInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscribe(ctx, s, &apiv1.SubscribeRequest{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}})
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-td.queried:
+			if id.GetValue() != "1111111111" {
+				t.Fatalf("unexpected identifier re-queried: %v", id)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscribed patient not re-queried")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package tenant supports a shared concierge serving more than one organisation, such as several health
+// boards, each a tenant with its own isolated configuration of backend services.
+//
+// Each authenticated service account is mapped to a tenant, whose name is added to the context of each
+// call. Providers then use the configuration for that tenant, such as the processing ID for the EMPI,
+// credentials for CAV PMS, document routing rules and rate limits, in place of their defaults.
+// Accounts not mapped to a tenant use the default configuration.
+//
+// Tenants are configured in a YAML or JSON file, for example:
+//
+//	tenants:
+//	  abuhb:
+//	    accounts: [abuhb-epr, "https://fhir.nhs.uk/Id/cymru-user-id|ma090906"]
+//	    empi-processing-id: U
+//	    cav-username: abuhb-concierge
+//	    cav-password-secret: abuhb-cav-pms-password
+//	    doc-rules: /etc/concierge/abuhb-rules.yaml
+//	    rate-limits: /etc/concierge/abuhb-limits.yaml
+//
+// Accounts are service account names, or identifiers in the form system|value for other users.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+// Tenant is the configuration of a single tenant; options that are empty use the default configuration
+type Tenant struct {
+	Name              string   `yaml:"-" json:"-"`
+	Accounts          []string `yaml:"accounts" json:"accounts"`                       // accounts belonging to this tenant
+	EMPIProcessingID  string   `yaml:"empi-processing-id" json:"empi-processing-id"`   // P production, U testing, T development
+	CAVUsername       string   `yaml:"cav-username" json:"cav-username"`               // username for CAV PMS
+	CAVPasswordSecret string   `yaml:"cav-password-secret" json:"cav-password-secret"` // name of the secret holding the password for CAV PMS
+	DocRules          string   `yaml:"doc-rules" json:"doc-rules"`                     // document routing rules file
+	RateLimits        string   `yaml:"rate-limits" json:"rate-limits"`                 // rate limits file
+}
+
+// Config defines the tenants, by name
+type Config struct {
+	Tenants  map[string]*Tenant `yaml:"tenants" json:"tenants"`
+	accounts map[string]*Tenant // account system|value -> tenant
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// LoadConfig loads the configuration of tenants from the YAML or JSON file specified
+func LoadConfig(filename string) (*Config, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses the configuration of tenants, checking that each has a valid name, and that no account
+// belongs to more than one tenant
+func Parse(b []byte) (*Config, error) {
+	c := new(Config)
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("tenant: invalid configuration file: %w", err)
+	}
+	c.accounts = make(map[string]*Tenant)
+	for name, t := range c.Tenants {
+		if !validName.MatchString(name) {
+			return nil, fmt.Errorf("tenant: invalid name '%s': use lowercase letters, digits and hyphens", name)
+		}
+		if t == nil || len(t.Accounts) == 0 {
+			return nil, fmt.Errorf("tenant: no accounts for '%s'", name)
+		}
+		switch t.EMPIProcessingID {
+		case "", "P", "U", "T":
+		default:
+			return nil, fmt.Errorf("tenant: invalid EMPI processing ID for '%s': '%s': expected P, U or T", name, t.EMPIProcessingID)
+		}
+		if (t.CAVUsername == "") != (t.CAVPasswordSecret == "") {
+			return nil, fmt.Errorf("tenant: CAV username and password secret must be specified together for '%s'", name)
+		}
+		t.Name = name
+		for _, account := range t.Accounts {
+			key := accountKey(account)
+			if other, exists := c.accounts[key]; exists {
+				return nil, fmt.Errorf("tenant: account '%s' belongs to both '%s' and '%s'", account, other.Name, name)
+			}
+			c.accounts[key] = t
+		}
+	}
+	return c, nil
+}
+
+// accountKey returns the key for an account, which is a service account name, or an identifier as system|value
+func accountKey(account string) string {
+	if strings.Contains(account, "|") {
+		return account
+	}
+	return identifiers.ConciergeServiceUser + "|" + account
+}
+
+// ForAccount returns the tenant to which the account belongs, or nil if it belongs to no tenant
+func (c *Config) ForAccount(id *apiv1.Identifier) *Tenant {
+	return c.accounts[id.GetSystem()+"|"+id.GetValue()]
+}
+
+// List returns the tenants, sorted by name
+func (c *Config) List() []*Tenant {
+	result := make([]*Tenant, 0, len(c.Tenants))
+	for _, t := range c.Tenants {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+type contextKey string
+
+const tenantContextKey = contextKey("tenant")
+
+// NewContext returns a context for calls on behalf of the named tenant
+func NewContext(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantContextKey, name)
+}
+
+// FromContext returns the name of the tenant on whose behalf a call is made, or an empty string if
+// the default configuration should be used
+func FromContext(ctx context.Context) string {
+	name, _ := ctx.Value(tenantContextKey).(string)
+	return name
+}
+
+// contextForUser returns a context with the tenant of the authenticated user, if any
+func (c *Config) contextForUser(ctx context.Context) context.Context {
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	if user.GetValue() == "" {
+		return ctx
+	}
+	if t := c.ForAccount(user); t != nil {
+		return NewContext(ctx, t.Name)
+	}
+	return ctx
+}
+
+// UnaryServerInterceptor returns an interceptor adding the tenant of the authenticated user to the context
+// of unary calls. This must run after authentication, so that the authenticated user is known, and before
+// any interceptors that use the tenant, such as rate limiting.
+func (c *Config) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(c.contextForUser(ctx), req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor adding the tenant of the authenticated user to the context
+// of streaming calls. This must run after authentication, so that the authenticated user is known.
+func (c *Config) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &tenantStream{ServerStream: ss, ctx: c.contextForUser(ss.Context())})
+	}
+}
+
+// tenantStream is a server stream with a context including the tenant
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ts *tenantStream) Context() context.Context {
+	return ts.ctx
+}
@@ -0,0 +1,69 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(`
+tenants:
+  abuhb:
+    accounts: [abuhb-epr, "https://fhir.nhs.uk/Id/cymru-user-id|ma090906"]
+    empi-processing-id: U
+    cav-username: abuhb-concierge
+    cav-password-secret: abuhb-cav-pms-password
+  cav:
+    accounts: [cav-epr]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		account  *apiv1.Identifier
+		expected string
+	}{
+		{&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "abuhb-epr"}, "abuhb"},
+		{&apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}, "abuhb"},
+		{&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "cav-epr"}, "cav"},
+		{&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "other"}, ""},
+	}
+	for _, test := range tests {
+		var name string
+		if tenant := c.ForAccount(test.account); tenant != nil {
+			name = tenant.Name
+		}
+		if name != test.expected {
+			t.Errorf("%s|%s: expected tenant '%s', got '%s'", test.account.GetSystem(), test.account.GetValue(), test.expected, name)
+		}
+	}
+	if list := c.List(); len(list) != 2 || list[0].Name != "abuhb" || list[0].EMPIProcessingID != "U" {
+		t.Errorf("unexpected tenants: %v", list)
+	}
+	invalid := []string{
+		"tenants:\n  ABUHB:\n    accounts: [epr]",
+		"tenants:\n  abuhb:\n    accounts: []",
+		"tenants:\n  abuhb:\n    accounts: [epr]\n  cav:\n    accounts: [epr]",
+		"tenants:\n  abuhb:\n    accounts: [epr]\n    empi-processing-id: X",
+		"tenants:\n  abuhb:\n    accounts: [epr]\n    cav-username: abuhb",
+		"tenants:\n  abuhb:\n    accounts: [epr]\n    empi-procesing-id: U",
+	}
+	for _, s := range invalid {
+		if _, err := Parse([]byte(s)); err == nil {
+			t.Errorf("expected invalid configuration: %s", s)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if name := FromContext(ctx); name != "" {
+		t.Fatalf("expected no tenant, got '%s'", name)
+	}
+	if name := FromContext(NewContext(ctx, "abuhb")); name != "abuhb" {
+		t.Fatalf("expected tenant 'abuhb', got '%s'", name)
+	}
+}
@@ -0,0 +1,149 @@
+package terminology
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
+)
+
+// Default configuration for the cache of concepts fetched from the terminology server
+const (
+	DefaultCacheSize = 10000
+	DefaultCacheTTL  = time.Hour
+)
+
+// lru is a size-limited cache of extended concepts, evicting the least recently used when full,
+// with entries expiring after a fixed time
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	items   map[string]*list.Element
+	order   *list.List // most recently used at front
+	nowFunc func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   *snomed.ExtendedConcept
+	expires time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{size: size, ttl: ttl, items: make(map[string]*list.Element), order: list.New(), nowFunc: time.Now}
+}
+
+func (c *lru) get(key string) (*snomed.ExtendedConcept, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if c.ttl > 0 && c.nowFunc().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *lru) set(key string, value *snomed.ExtendedConcept) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.nowFunc().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// EnableCache caches extended concepts fetched from the terminology server, keyed by concept identifier
+// and requested language, retaining at most size concepts for the time specified (0 for no expiry).
+// The cache is invalidated whenever the connection to the terminology server is re-established, as
+// the server may have been restarted with a different release of SNOMED CT.
+func (term *Terminology) EnableCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		return
+	}
+	term.cache = newLRU(size, ttl)
+	if term.conn != nil {
+		go term.invalidateOnReconnect(term.conn, term.cache)
+	}
+}
+
+// invalidateOnReconnect purges the cache each time the connection becomes ready after having been lost,
+// returning when the connection is closed
+func (term *Terminology) invalidateOnReconnect(conn *grpc.ClientConn, cache *lru) {
+	ctx := context.Background()
+	state := conn.GetState()
+	lost := false
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			if lost {
+				log.Printf("terminology: reconnected to terminology server; invalidating cache")
+				cache.purge()
+				lost = false
+			}
+		case connectivity.TransientFailure, connectivity.Idle:
+			lost = true
+		case connectivity.Shutdown:
+			return
+		}
+	}
+}
+
+// extendedConcept returns the extended concept specified, using the cache if enabled, with terms
+// in the language requested by the client
+func (term *Terminology) extendedConcept(ctx context.Context, conceptID int64) (*snomed.ExtendedConcept, error) {
+	lang := acceptLanguage(ctx)
+	key := strconv.FormatInt(conceptID, 10) + "/" + lang
+	if term.cache != nil {
+		ec, found := term.cache.get(key)
+		metrics.CacheLookup("terminology", found)
+		if found {
+			return proto.Clone(ec).(*snomed.ExtendedConcept), nil
+		}
+	}
+	ctx, cancel, err := term.withDeadline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	ec, err := term.client.GetExtendedConcept(withLanguage(ctx, lang), &snomed.SctID{Identifier: conceptID})
+	if err != nil {
+		return nil, err
+	}
+	if term.cache != nil {
+		term.cache.set(key, proto.Clone(ec).(*snomed.ExtendedConcept))
+	}
+	return ec, nil
+}
@@ -0,0 +1,88 @@
+package terminology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	c := newLRU(2, time.Minute)
+	c.nowFunc = func() time.Time { return now }
+	c.set("a", &snomed.ExtendedConcept{Concept: &snomed.Concept{Id: 1}})
+	c.set("b", &snomed.ExtendedConcept{Concept: &snomed.Concept{Id: 2}})
+	if _, ok := c.get("a"); !ok { // a is now most recently used
+		t.Fatal("expected cached value")
+	}
+	c.set("c", &snomed.ExtendedConcept{Concept: &snomed.Concept{Id: 3}})
+	if _, ok := c.get("b"); ok {
+		t.Error("expected least recently used to be evicted")
+	}
+	if ec, ok := c.get("a"); !ok || ec.GetConcept().GetId() != 1 {
+		t.Errorf("expected cached value, got: %v", ec)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.get("c"); ok {
+		t.Error("expected expired value not to be returned")
+	}
+	c.set("d", &snomed.ExtendedConcept{})
+	c.purge()
+	if _, ok := c.get("d"); ok {
+		t.Error("expected purged value not to be returned")
+	}
+}
+
+type countingClient struct {
+	fakeClient
+	calls     int
+	languages []string
+}
+
+func (cc *countingClient) GetExtendedConcept(ctx context.Context, in *snomed.SctID, opts ...grpc.CallOption) (*snomed.ExtendedConcept, error) {
+	cc.calls++
+	md, _ := metadata.FromOutgoingContext(ctx)
+	cc.languages = append(cc.languages, md.Get("accept-language")...)
+	return cc.fakeClient.GetExtendedConcept(ctx, in, opts...)
+}
+
+func TestCachedExtendedConcept(t *testing.T) {
+	client := &countingClient{}
+	term := &Terminology{client: client}
+	term.EnableCache(10, time.Minute)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		ec, err := term.extendedConcept(ctx, 24700007)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ec.DirectParentIds = nil // callers must not be able to modify cached values
+	}
+	ec, err := term.extendedConcept(ctx, 24700007)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.GetDirectParentIds()) == 0 {
+		t.Error("cached value modified by caller")
+	}
+	cy := metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", "cy-GB"))
+	if _, err := term.extendedConcept(cy, 24700007); err != nil {
+		t.Fatal(err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected one call per language, got %d", client.calls)
+	}
+	if len(client.languages) != 2 || client.languages[0] != defaultLanguage || client.languages[1] != "cy-GB" {
+		t.Errorf("unexpected languages requested: %v", client.languages)
+	}
+	if _, err := term.extendedConcept(ctx, 24700008); err == nil {
+		t.Error("expected error for unknown concept")
+	}
+	if _, err := term.extendedConcept(ctx, 24700008); err == nil || client.calls != 4 {
+		t.Error("expected errors not to be cached")
+	}
+}
@@ -0,0 +1,190 @@
+package terminology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ParseExpression parses a SNOMED CT expression using compositional grammar, returning the expression
+// in canonical form, with its focus concepts. Parsing is delegated to the terminology server.
+func (term *Terminology) ParseExpression(ctx context.Context, s string) (*apiv1.SnomedExpression, error) {
+	ctx, cancel, err := term.withDeadline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	exp, err := term.client.Parse(ctx, &snomed.ParseRequest{S: s})
+	if err != nil {
+		return nil, fmt.Errorf("could not parse SNOMED CT expression '%s': %w", s, err)
+	}
+	clause := exp.GetClause()
+	result := &apiv1.SnomedExpression{
+		Expression: canonical(exp),
+		Refined:    len(clause.GetRefinements()) > 0 || len(clause.GetRefinementGroups()) > 0,
+	}
+	for _, c := range clause.GetFocusConcepts() {
+		result.FocusConcepts = append(result.FocusConcepts, c.GetConceptId())
+	}
+	sort.Slice(result.FocusConcepts, func(i, j int) bool { return result.FocusConcepts[i] < result.FocusConcepts[j] })
+	return result, nil
+}
+
+// Satisfies determines whether the SNOMED CT expression specified satisfies the expression constraint (ECL).
+// The expression may be a concept identifier or a post-coordinated expression, in which case each of its
+// focus concepts must satisfy the constraint, as refinements only specialise the meaning of the focus concepts.
+// Post-coordinated expressions are never members of a reference set.
+// Only a disjunction of simple constraints is supported; see parseConstraint.
+func (term *Terminology) Satisfies(ctx context.Context, expression string, ecl string) (bool, error) {
+	constraints, err := parseConstraint(ecl)
+	if err != nil {
+		return false, i18n.Errorf(ctx, codes.InvalidArgument, "terminology: unsupported expression constraint: %s", ecl)
+	}
+	if len(constraints) == 0 {
+		return true, nil
+	}
+	exp, err := term.ParseExpression(ctx, expression)
+	if err != nil {
+		return false, err
+	}
+	if len(exp.GetFocusConcepts()) == 0 {
+		return false, nil
+	}
+	for _, focus := range exp.GetFocusConcepts() {
+		ec, err := term.extendedConcept(ctx, focus)
+		if err != nil {
+			return false, fmt.Errorf("could not resolve SNOMED CT concept '%d': %w", focus, err)
+		}
+		if !satisfiesAny(ec, exp.GetRefined(), constraints) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// satisfiesAny determines whether a focus concept, optionally refined, satisfies any of the constraints
+func satisfiesAny(ec *snomed.ExtendedConcept, refined bool, constraints []constraint) bool {
+	id := ec.GetConcept().GetId()
+	for _, c := range constraints {
+		switch c.operator {
+		case descendantOrSelfOf:
+			if id == c.concept || contains(ec.GetAllParentIds(), c.concept) {
+				return true
+			}
+		case descendantOf:
+			if (id == c.concept && refined) || (id != c.concept && contains(ec.GetAllParentIds(), c.concept)) {
+				return true
+			}
+		case childOf:
+			if contains(ec.GetDirectParentIds(), c.concept) {
+				return true
+			}
+		case memberOf:
+			if !refined && contains(ec.GetConceptRefsets(), c.concept) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// canonical returns the canonical representation of an expression, without terms, and with focus concepts,
+// refinements and refinement groups sorted, so that equivalent expressions are represented identically.
+func canonical(exp *snomed.Expression) string {
+	var sb strings.Builder
+	if exp.GetDefinitionStatus() == snomed.Expression_SUBTYPE_OF {
+		sb.WriteString("<<<")
+	}
+	sb.WriteString(canonicalClause(exp.GetClause()))
+	return sb.String()
+}
+
+func canonicalClause(clause *snomed.Expression_Clause) string {
+	focus := make([]string, 0, len(clause.GetFocusConcepts()))
+	for _, c := range clause.GetFocusConcepts() {
+		focus = append(focus, strconv.FormatInt(c.GetConceptId(), 10))
+	}
+	sort.Strings(focus)
+	result := strings.Join(focus, "+")
+	if len(clause.GetRefinements()) == 0 && len(clause.GetRefinementGroups()) == 0 {
+		return result
+	}
+	groups := make([]string, 0, len(clause.GetRefinementGroups()))
+	for _, g := range clause.GetRefinementGroups() {
+		groups = append(groups, "{"+canonicalRefinements(g.GetRefinements())+"}")
+	}
+	sort.Strings(groups)
+	refinements := canonicalRefinements(clause.GetRefinements())
+	if refinements != "" && len(groups) > 0 {
+		refinements += ","
+	}
+	return result + ":" + refinements + strings.Join(groups, "")
+}
+
+func canonicalRefinements(refinements []*snomed.Expression_Refinement) string {
+	result := make([]string, 0, len(refinements))
+	for _, r := range refinements {
+		var value string
+		switch v := r.GetValue().(type) {
+		case *snomed.Expression_Refinement_ConceptValue:
+			value = strconv.FormatInt(v.ConceptValue.GetConceptId(), 10)
+		case *snomed.Expression_Refinement_ClauseValue:
+			value = "(" + canonicalClause(v.ClauseValue) + ")"
+		case *snomed.Expression_Refinement_StringValue:
+			value = strconv.Quote(v.StringValue)
+		case *snomed.Expression_Refinement_IntValue:
+			value = "#" + strconv.FormatInt(v.IntValue, 10)
+		case *snomed.Expression_Refinement_DoubleValue:
+			value = "#" + strconv.FormatFloat(v.DoubleValue, 'f', -1, 64)
+		}
+		result = append(result, strconv.FormatInt(r.GetRefinementConcept().GetConceptId(), 10)+"="+value)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+// DocumentTypeValidator returns a function that validates SNOMED CT document types, which may be
+// post-coordinated expressions, checking that each satisfies the expression constraint (ECL) specified.
+// Document types from other code systems are not validated.
+func (term *Terminology) DocumentTypeValidator(ecl string) (func(ctx context.Context, typ *apiv1.Identifier) error, error) {
+	if _, err := parseConstraint(ecl); err != nil {
+		return nil, fmt.Errorf("terminology: invalid document type constraint '%s': %w", ecl, err)
+	}
+	return func(ctx context.Context, typ *apiv1.Identifier) error {
+		if typ.GetSystem() != identifiers.SNOMEDCT {
+			return nil
+		}
+		ok, err := term.Satisfies(ctx, typ.GetValue(), ecl)
+		if err != nil {
+			log.Printf("terminology: failed to validate document type '%s': %s", typ.GetValue(), err)
+			if code := status.Code(errors.Unwrap(err)); code != codes.InvalidArgument && code != codes.NotFound {
+				return status.Errorf(codes.Unavailable, "terminology: unable to validate document type: %s", err)
+			}
+			return i18n.Errorf(ctx, codes.InvalidArgument, "invalid SNOMED CT document type: %s", typ.GetValue())
+		}
+		if !ok {
+			return i18n.Errorf(ctx, codes.InvalidArgument, "document type '%s' does not satisfy constraint '%s'", typ.GetValue(), ecl)
+		}
+		return nil
+	}, nil
+}
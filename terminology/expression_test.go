@@ -0,0 +1,100 @@
+package terminology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClient provides a small hierarchy: 24700007 (multiple sclerosis) is a 6118003 (demyelinating disease),
+// which is a 64572001 (disease)
+type fakeClient struct {
+	snomed.SnomedCTClient
+}
+
+func (fc *fakeClient) Parse(ctx context.Context, in *snomed.ParseRequest, opts ...grpc.CallOption) (*snomed.Expression, error) {
+	switch in.GetS() {
+	case "24700007":
+		return &snomed.Expression{Clause: &snomed.Expression_Clause{FocusConcepts: []*snomed.ConceptReference{{ConceptId: 24700007}}}}, nil
+	case "24700007 |Multiple sclerosis| : 246112005 |Severity| = 24484000 |Severe|, 363698007 = 12738006":
+		return &snomed.Expression{Clause: &snomed.Expression_Clause{
+			FocusConcepts: []*snomed.ConceptReference{{ConceptId: 24700007, Term: "Multiple sclerosis"}},
+			Refinements: []*snomed.Expression_Refinement{
+				{RefinementConcept: &snomed.ConceptReference{ConceptId: 363698007}, Value: &snomed.Expression_Refinement_ConceptValue{ConceptValue: &snomed.ConceptReference{ConceptId: 12738006}}},
+				{RefinementConcept: &snomed.ConceptReference{ConceptId: 246112005}, Value: &snomed.Expression_Refinement_ConceptValue{ConceptValue: &snomed.ConceptReference{ConceptId: 24484000}}},
+			},
+		}}, nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "invalid expression")
+}
+
+func (fc *fakeClient) GetExtendedConcept(ctx context.Context, in *snomed.SctID, opts ...grpc.CallOption) (*snomed.ExtendedConcept, error) {
+	if in.GetIdentifier() == 24700007 {
+		return &snomed.ExtendedConcept{
+			Concept:         &snomed.Concept{Id: 24700007},
+			AllParentIds:    []int64{6118003, 64572001, 138875005},
+			DirectParentIds: []int64{6118003},
+			ConceptRefsets:  []int64{991411000000109},
+		}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "not found")
+}
+
+func TestExpression(t *testing.T) {
+	term := &Terminology{client: &fakeClient{}}
+	ctx := context.Background()
+	exp, err := term.ParseExpression(ctx, "24700007 |Multiple sclerosis| : 246112005 |Severity| = 24484000 |Severe|, 363698007 = 12738006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.GetExpression() != "24700007:246112005=24484000,363698007=12738006" || !exp.GetRefined() || len(exp.GetFocusConcepts()) != 1 || exp.GetFocusConcepts()[0] != 24700007 {
+		t.Errorf("unexpected expression: %v", exp)
+	}
+	tests := []struct {
+		expression string
+		ecl        string
+		satisfies  bool
+	}{
+		{"24700007", "<< 64572001 |Disease|", true},
+		{"24700007", "<< 24700007", true},
+		{"24700007", "< 24700007", false},
+		{"24700007 |Multiple sclerosis| : 246112005 |Severity| = 24484000 |Severe|, 363698007 = 12738006", "< 24700007", true},
+		{"24700007", "<! 6118003", true},
+		{"24700007", "<! 64572001", false},
+		{"24700007", "^ 991411000000109", true},
+		{"24700007 |Multiple sclerosis| : 246112005 |Severity| = 24484000 |Severe|, 363698007 = 12738006", "^ 991411000000109", false},
+		{"24700007", "<< 71388002 OR << 6118003", true},
+		{"24700007", "<< 71388002", false},
+	}
+	for _, test := range tests {
+		ok, err := term.Satisfies(ctx, test.expression, test.ecl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.satisfies {
+			t.Errorf("'%s' with constraint '%s': expected %v, got %v", test.expression, test.ecl, test.satisfies, ok)
+		}
+	}
+	validate, err := term.DocumentTypeValidator("<< 64572001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validate(ctx, &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}); err != nil {
+		t.Errorf("expected valid document type, got: %v", err)
+	}
+	if err := validate(ctx, &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "wibble"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid document type, got: %v", err)
+	}
+	if err := validate(ctx, &apiv1.Identifier{System: identifiers.ReadV2, Value: "F20.."}); err != nil {
+		t.Errorf("expected document type from other code system not to be validated, got: %v", err)
+	}
+	if _, err := term.DocumentTypeValidator("<< 64572001 MINUS << 6118003"); err == nil {
+		t.Errorf("expected error for unsupported constraint")
+	}
+}
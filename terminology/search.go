@@ -0,0 +1,187 @@
+package terminology
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// defaultLanguage is the language used for terms when the client does not specify a preference
+const defaultLanguage = "en-GB"
+
+var _ apiv1.TerminologyServer = (*Terminology)(nil)
+
+// RegisterServer registers this server
+func (term *Terminology) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterTerminologyServer(s, term)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (term *Terminology) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterTerminologyHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// SearchConcepts searches for concepts using the terminology server, passing through the language
+// preferences of the client, so that terms are returned in the requested language and dialect.
+func (term *Terminology) SearchConcepts(ctx context.Context, r *apiv1.ConceptSearchRequest) (*apiv1.ConceptSearchResponse, error) {
+	if strings.TrimSpace(r.GetS()) == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "terminology: search text required")
+	}
+	req := &snomed.SearchRequest{
+		S:               r.GetS(),
+		ConceptRefsets:  r.GetRefsets(),
+		MaximumHits:     r.GetMaximumHits(),
+		IncludeInactive: r.GetIncludeInactive(),
+	}
+	if r.GetFuzzy() {
+		req.Fuzzy = snomed.SearchRequest_ALWAYS_FUZZY
+	}
+	exclude, err := applyConstraint(r.GetConstraint(), req)
+	if err != nil {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "terminology: unsupported expression constraint: %s", r.GetConstraint())
+	}
+	callCtx, cancel, err := term.withDeadline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	response, err := term.search.Search(outgoingLanguage(callCtx), req)
+	if err != nil {
+		return nil, err
+	}
+	result := &apiv1.ConceptSearchResponse{Items: make([]*apiv1.ConceptSearchResponse_Item, 0, len(response.GetItems()))}
+	for _, item := range response.GetItems() {
+		if exclude[item.GetConceptId()] {
+			continue
+		}
+		result.Items = append(result.Items, &apiv1.ConceptSearchResponse_Item{
+			Term:          item.GetTerm(),
+			ConceptId:     item.GetConceptId(),
+			PreferredTerm: item.GetPreferredTerm(),
+		})
+	}
+	return result, nil
+}
+
+// outgoingLanguage returns a context for calls to the terminology server, passing through the
+// accept-language of the incoming request, such as from the HTTP gateway, defaulting to British English
+func outgoingLanguage(ctx context.Context) context.Context {
+	return withLanguage(ctx, acceptLanguage(ctx))
+}
+
+// acceptLanguage returns the accept-language of the incoming request, or the default language
+func acceptLanguage(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if al := md.Get("accept-language"); len(al) > 0 && al[0] != "" {
+			return al[0]
+		}
+	}
+	return defaultLanguage
+}
+
+// withLanguage returns a context for calls to the terminology server requesting the language specified
+func withLanguage(ctx context.Context, lang string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "accept-language", lang)
+}
+
+var (
+	// rxConstraint matches a simple expression constraint: a constraint operator, and a concept with optional term
+	rxConstraint = regexp.MustCompile(`^(<<|<!|<|\^)\s*(\d+)\s*(?:\|[^|]*\|)?$`)
+	// rxDisjunction matches the disjunction operator between constraints
+	rxDisjunction = regexp.MustCompile(`(?i)\s+OR\s+`)
+)
+
+// errUnsupportedConstraint is returned if an expression constraint cannot be evaluated by the terminology server
+var errUnsupportedConstraint = errors.New("unsupported expression constraint")
+
+// Constraint operators supported
+const (
+	descendantOrSelfOf = "<<"
+	descendantOf       = "<"
+	childOf            = "<!"
+	memberOf           = "^"
+)
+
+// constraint is a simple expression constraint, with a single operator and focus concept
+type constraint struct {
+	operator string
+	concept  int64
+}
+
+// parseConstraint parses an expression constraint (ECL) that is a disjunction of simple constraints.
+// The terminology server supports evaluation of subsumption and reference set membership, so only the
+// descendantOrSelfOf (<<), descendantOf (<), childOf (<!) and memberOf (^) operators are supported.
+func parseConstraint(ecl string) ([]constraint, error) {
+	ecl = strings.TrimSpace(ecl)
+	if ecl == "" {
+		return nil, nil
+	}
+	result := make([]constraint, 0)
+	for _, part := range rxDisjunction.Split(ecl, -1) {
+		m := rxConstraint.FindStringSubmatch(strings.TrimSpace(strings.Trim(part, "() ")))
+		if m == nil {
+			return nil, errUnsupportedConstraint
+		}
+		id, err := snomed.ParseAndValidate(m[2])
+		if err != nil || !id.IsConcept() {
+			return nil, errUnsupportedConstraint
+		}
+		result = append(result, constraint{operator: m[1], concept: id.Integer()})
+	}
+	return result, nil
+}
+
+// applyConstraint limits a search request using an expression constraint (ECL), returning the concepts to
+// be excluded from the results. As the terminology server combines different kinds of limit by conjunction,
+// only a disjunction of constraints using the same kind of operator is supported: descendantOrSelfOf (<<)
+// and descendantOf (<), childOf (<!), or memberOf (^).
+func applyConstraint(ecl string, req *snomed.SearchRequest) (map[int64]bool, error) {
+	constraints, err := parseConstraint(ecl)
+	if err != nil {
+		return nil, err
+	}
+	exclude := make(map[int64]bool)
+	self := make(map[int64]bool) // concepts included by descendantOrSelfOf
+	var isA []int64
+	var directParents []int64
+	var refsets []int64
+	for _, c := range constraints {
+		switch c.operator {
+		case descendantOrSelfOf:
+			isA = append(isA, c.concept)
+			self[c.concept] = true
+		case descendantOf:
+			isA = append(isA, c.concept)
+			exclude[c.concept] = true
+		case childOf:
+			directParents = append(directParents, c.concept)
+		case memberOf:
+			refsets = append(refsets, c.concept)
+		}
+	}
+	kinds := 0
+	for _, ids := range [][]int64{isA, directParents, refsets} {
+		if len(ids) > 0 {
+			kinds++
+		}
+	}
+	if kinds > 1 || (len(refsets) > 0 && len(req.ConceptRefsets) > 0) {
+		return nil, errUnsupportedConstraint
+	}
+	for id := range self {
+		delete(exclude, id)
+	}
+	req.IsA = append(req.IsA, isA...)
+	req.DirectParents = append(req.DirectParents, directParents...)
+	req.ConceptRefsets = append(req.ConceptRefsets, refsets...)
+	return exclude, nil
+}
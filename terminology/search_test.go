@@ -0,0 +1,57 @@
+package terminology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestApplyConstraint(t *testing.T) {
+	tests := []struct {
+		ecl           string
+		isA           int
+		directParents int
+		refsets       int
+		exclude       int
+		ok            bool
+	}{
+		{ecl: "", ok: true},
+		{ecl: "<< 64572001 |Disease|", isA: 1, ok: true},
+		{ecl: "< 64572001", isA: 1, exclude: 1, ok: true},
+		{ecl: "< 64572001 OR << 64572001", isA: 2, ok: true},
+		{ecl: "<! 404684003 or <! 71388002", directParents: 2, ok: true},
+		{ecl: "^ 991411000000109", refsets: 1, ok: true},
+		{ecl: "<< 64572001 OR ^ 991411000000109", ok: false},
+		{ecl: "<< 64572001 AND << 71388002", ok: false},
+		{ecl: "64572001", ok: false},
+		{ecl: "<< 64572002", ok: false}, // invalid check digit
+	}
+	for _, test := range tests {
+		req := &snomed.SearchRequest{}
+		exclude, err := applyConstraint(test.ecl, req)
+		if (err == nil) != test.ok {
+			t.Errorf("'%s': expected ok: %v, got: %v", test.ecl, test.ok, err)
+			continue
+		}
+		if !test.ok {
+			continue
+		}
+		if len(req.IsA) != test.isA || len(req.DirectParents) != test.directParents || len(req.ConceptRefsets) != test.refsets || len(exclude) != test.exclude {
+			t.Errorf("'%s': unexpected request: %v (exclude: %v)", test.ecl, req, exclude)
+		}
+	}
+}
+
+func TestOutgoingLanguage(t *testing.T) {
+	md, _ := metadata.FromOutgoingContext(outgoingLanguage(context.Background()))
+	if al := md.Get("accept-language"); len(al) != 1 || al[0] != "en-GB" {
+		t.Errorf("expected default language, got: %v", al)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "en-US,en;q=0.8"))
+	md, _ = metadata.FromOutgoingContext(outgoingLanguage(ctx))
+	if al := md.Get("accept-language"); len(al) != 1 || al[0] != "en-US,en;q=0.8" {
+		t.Errorf("expected language to be passed through, got: %v", al)
+	}
+}
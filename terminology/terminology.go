@@ -2,34 +2,66 @@ package terminology
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/wardle/concierge/apiv1"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
 	"github.com/wardle/go-terminology/snomed"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 // Terminology provides a SNOMED identifier resolution service
 type Terminology struct {
-	conn   *grpc.ClientConn
-	client snomed.SnomedCTClient
+	conn    *grpc.ClientConn
+	client  snomed.SnomedCTClient
+	search  snomed.SearchClient
+	cache   *lru          // may be nil if not caching
+	timeout time.Duration // maximum time permitted for each call to the terminology server
 }
 
-// NewTerminology creates a new SNOMED identifier resolution service
-func NewTerminology(addr string) (*Terminology, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+// DefaultTimeout is the maximum time permitted for each call to the terminology server, unless set otherwise
+const DefaultTimeout = 5 * time.Second
+
+// NewTerminology creates a new SNOMED identifier resolution service, connecting via the proxy specified,
+// or that defined by the environment if nil
+func NewTerminology(addr string, proxy *transport.Proxy) (*Terminology, error) {
+	opts := append(tracing.DialOptions(), grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor("terminology")),
+		grpc.WithChainStreamInterceptor(metrics.StreamClientInterceptor("terminology")))
+	if proxy != nil {
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return proxy.DialContext(ctx, "tcp", addr)
+		}))
+	}
+	conn, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
-	client := snomed.NewSnomedCTClient(conn)
-	return &Terminology{conn: conn, client: client}, nil
+	return &Terminology{conn: conn, client: snomed.NewSnomedCTClient(conn), search: snomed.NewSearchClient(conn), timeout: DefaultTimeout}, nil
+}
+
+// SetTimeout sets the maximum time permitted for each call to the terminology server
+func (term *Terminology) SetTimeout(timeout time.Duration) {
+	term.timeout = timeout
+}
+
+// withDeadline returns a context for a call to the terminology server, derived from that of the caller
+func (term *Terminology) withDeadline(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	return transport.WithDeadline(ctx, "terminology", term.timeout)
 }
 
 // Close the connection to the terminology server
@@ -43,25 +75,43 @@ func (term *Terminology) Close() error {
 	return term.conn.Close()
 }
 
-// Resolve provides a resolution service for SNOMED CT identifiers (currently only concept identifiers, not expressions)
-// TODO: support parsing expression using expression.Parse() once SNOMED toolchain
-// supports deriving equivalent of an "ExtendedConcept" for any arbitrary expression
+// HealthCheck checks the health of the connection to the terminology server
+func (term *Terminology) HealthCheck(ctx context.Context) error {
+	if term.conn == nil {
+		return nil
+	}
+	switch state := term.conn.GetState(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return status.Errorf(codes.Unavailable, "terminology: connection to terminology server: %s", state)
+	}
+	return nil
+}
+
+// Resolve provides a resolution service for SNOMED CT identifiers: concept and description identifiers
+// are resolved to the concept or description, and other values are parsed as expressions using
+// compositional grammar, such as post-coordinated expressions, returning the expression in canonical form.
 func (term *Terminology) Resolve(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
 	sctID, err := snomed.ParseAndValidate(id.GetValue())
 	if err != nil {
-		return nil, fmt.Errorf("could not resolve SNOMED CT: %w", err)
+		if _, parseErr := strconv.ParseInt(strings.TrimSpace(id.GetValue()), 10, 64); parseErr == nil {
+			return nil, fmt.Errorf("could not resolve SNOMED CT: %w", err) // a single identifier, but invalid
+		}
+		return term.ParseExpression(outgoingLanguage(ctx), id.GetValue())
 	}
-	header := metadata.New(map[string]string{"accept-language": "en-GB"})
-	ctx = metadata.NewOutgoingContext(ctx, header)
 	if sctID.IsConcept() {
-		ec, err := term.client.GetExtendedConcept(ctx, &snomed.SctID{Identifier: sctID.Integer()})
+		ec, err := term.extendedConcept(ctx, sctID.Integer())
 		if err != nil {
 			return nil, fmt.Errorf("could not resolve SNOMED CT concept '%d': %w", sctID, err)
 		}
 		return ec, nil
 	}
 	if sctID.IsDescription() {
-		d, err := term.client.GetDescription(ctx, &snomed.SctID{Identifier: sctID.Integer()})
+		ctx, cancel, err := term.withDeadline(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		d, err := term.client.GetDescription(outgoingLanguage(ctx), &snomed.SctID{Identifier: sctID.Integer()})
 		if err != nil {
 			return nil, fmt.Errorf("could not resolve SNOMED CT description '%d': %w", sctID, err)
 		}
@@ -70,8 +120,77 @@ func (term *Terminology) Resolve(ctx context.Context, id *apiv1.Identifier) (pro
 	return nil, fmt.Errorf("could not resolve SNOMED CT entity '%d': only concepts and descriptions supported", sctID)
 }
 
+// Reference sets providing simple maps from SNOMED CT to Read codes
+const (
+	readV2SimpleMap = 900000000000497000
+	ctv3SimpleMap   = 900000000000498005
+)
+
+// ResolveReadCode provides a resolution service for Read V2 and CTV3 codes, resolving to the
+// SNOMED CT concept to which the code is mapped
+func (term *Terminology) ResolveReadCode(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	refset, err := readCodeMap(id.GetSystem())
+	if err != nil {
+		return nil, err
+	}
+	var sct *apiv1.Identifier
+	err = term.fromCrossMap(ctx, id, refset, func(result *apiv1.Identifier) error {
+		sct = result
+		return errFound // the first map is sufficient
+	})
+	if err != nil && err != errFound {
+		return nil, err
+	}
+	if sct == nil {
+		return nil, status.Errorf(codes.NotFound, "could not resolve '%s|%s': no map to SNOMED CT", id.GetSystem(), id.GetValue())
+	}
+	conceptID, err := strconv.ParseInt(sct.GetValue(), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := term.extendedConcept(ctx, conceptID)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve SNOMED CT concept '%d': %w", conceptID, err)
+	}
+	return ec, nil
+}
+
+// errFound is used to stop iteration once a result has been found
+var errFound = errors.New("found")
+
+// readCodeMap returns the reference set mapping SNOMED CT to the Read code system specified
+func readCodeMap(system string) (int64, error) {
+	switch system {
+	case identifiers.ReadV2:
+		return readV2SimpleMap, nil
+	case identifiers.ReadV3:
+		return ctv3SimpleMap, nil
+	}
+	return 0, fmt.Errorf("unsupported Read code system: %s", system)
+}
+
 // SNOMEDCTtoReadV2 performs a crossmap from SNOMED to Read V2
 func (term *Terminology) SNOMEDCTtoReadV2(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
+	return term.crossMap(ctx, id, readV2SimpleMap, identifiers.ReadV2, f)
+}
+
+// ReadV2toSNOMEDCT performs a crossmap from  Read V2 to SNOMED CT
+func (term *Terminology) ReadV2toSNOMEDCT(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
+	return term.fromCrossMap(ctx, id, readV2SimpleMap, f)
+}
+
+// SNOMEDCTtoCTV3 performs a crossmap from SNOMED to Read CTV3
+func (term *Terminology) SNOMEDCTtoCTV3(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
+	return term.crossMap(ctx, id, ctv3SimpleMap, identifiers.ReadV3, f)
+}
+
+// CTV3toSNOMEDCT performs a crossmap from Read CTV3 to SNOMED CT
+func (term *Terminology) CTV3toSNOMEDCT(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
+	return term.fromCrossMap(ctx, id, ctv3SimpleMap, f)
+}
+
+// crossMap maps a SNOMED CT concept to the target system using the simple map reference set specified
+func (term *Terminology) crossMap(ctx context.Context, id *apiv1.Identifier, refset int64, system string, f func(*apiv1.Identifier) error) error {
 	sctID, err := snomed.ParseAndValidate(id.GetValue())
 	if err != nil {
 		return fmt.Errorf("could not parse SNOMED identifier: %w", err)
@@ -79,11 +198,14 @@ func (term *Terminology) SNOMEDCTtoReadV2(ctx context.Context, id *apiv1.Identif
 	if sctID.IsConcept() == false {
 		return fmt.Errorf("can map only concepts: '%d' not a concept", sctID)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel, err := term.withDeadline(ctx)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 	stream, err := term.client.CrossMap(ctx, &snomed.CrossMapRequest{
 		ConceptId: sctID.Integer(),
-		RefsetId:  900000000000497000,
+		RefsetId:  refset,
 	})
 	if err != nil {
 		return fmt.Errorf("crossmap error: %w", err)
@@ -97,7 +219,7 @@ func (term *Terminology) SNOMEDCTtoReadV2(ctx context.Context, id *apiv1.Identif
 			return fmt.Errorf("crossmap error: %w", err)
 		}
 		err = f(&apiv1.Identifier{
-			System: identifiers.ReadV2,
+			System: system,
 			Value:  item.GetSimpleMap().GetMapTarget(),
 		})
 		if err != nil {
@@ -107,16 +229,19 @@ func (term *Terminology) SNOMEDCTtoReadV2(ctx context.Context, id *apiv1.Identif
 	return nil
 }
 
-// ReadV2toSNOMEDCT performs a crossmap from  Read V2 to SNOMED CT
-func (term *Terminology) ReadV2toSNOMEDCT(ctx context.Context, id *apiv1.Identifier, f func(*apiv1.Identifier) error) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// fromCrossMap maps a code to SNOMED CT using the simple map reference set specified
+func (term *Terminology) fromCrossMap(ctx context.Context, id *apiv1.Identifier, refset int64, f func(*apiv1.Identifier) error) error {
+	ctx, cancel, err := term.withDeadline(ctx)
+	if err != nil {
+		return err
+	}
 	defer cancel()
-	response, err := term.client.FromCrossMap(ctx, &snomed.TranslateFromRequest{S: id.GetValue(), RefsetId: 900000000000497000})
+	response, err := term.client.FromCrossMap(ctx, &snomed.TranslateFromRequest{S: id.GetValue(), RefsetId: refset})
 	if err != nil {
 		return err
 	}
 	if len(response.GetTranslations()) == 0 {
-		log.Printf("no translations found for map from '%s:%s' to '%s'", id.GetSystem(), id.GetValue(), identifiers.SNOMEDCT)
+		log.Printf("terminology: no translations found for map from '%s:%s' to '%s'", id.GetSystem(), id.GetValue(), identifiers.SNOMEDCT)
 	}
 	for _, t := range response.GetTranslations() {
 		ref := t.GetReferenceSetItem().GetReferencedComponentId()
@@ -0,0 +1,57 @@
+package terminology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/go-terminology/snomed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// crossMapClient maps the Read V2 and CTV3 codes for multiple sclerosis (F20..) to SNOMED CT
+type crossMapClient struct {
+	fakeClient
+}
+
+func (cc *crossMapClient) FromCrossMap(ctx context.Context, in *snomed.TranslateFromRequest, opts ...grpc.CallOption) (*snomed.TranslateFromResponse, error) {
+	if in.GetS() != "F20.." || (in.GetRefsetId() != readV2SimpleMap && in.GetRefsetId() != ctv3SimpleMap) {
+		return &snomed.TranslateFromResponse{}, nil
+	}
+	return &snomed.TranslateFromResponse{Translations: []*snomed.TranslateFromResponse_Item{
+		{ReferenceSetItem: &snomed.ReferenceSetItem{ReferencedComponentId: 24700007}},
+	}}, nil
+}
+
+func TestResolveReadCode(t *testing.T) {
+	term := &Terminology{client: &crossMapClient{}}
+	ctx := context.Background()
+	for _, system := range []string{identifiers.ReadV2, identifiers.ReadV3} {
+		result, err := term.ResolveReadCode(ctx, &apiv1.Identifier{System: system, Value: "F20.."})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ec, ok := result.(*snomed.ExtendedConcept); !ok || ec.GetConcept().GetId() != 24700007 {
+			t.Errorf("%s: unexpected result: %v", system, result)
+		}
+	}
+	if _, err := term.ResolveReadCode(ctx, &apiv1.Identifier{System: identifiers.ReadV2, Value: "XXXXX"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found for unmapped code, got: %v", err)
+	}
+	if _, err := term.ResolveReadCode(ctx, &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}); err == nil {
+		t.Error("expected error for unsupported system")
+	}
+	var results []*apiv1.Identifier
+	if err := term.CTV3toSNOMEDCT(ctx, &apiv1.Identifier{System: identifiers.ReadV3, Value: "F20.."}, func(id *apiv1.Identifier) error {
+		results = append(results, id)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].GetSystem() != identifiers.SNOMEDCT || results[0].GetValue() != "24700007" {
+		t.Errorf("unexpected results mapping CTV3: %v", results)
+	}
+}
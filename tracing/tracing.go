@@ -0,0 +1,197 @@
+// Package tracing provides distributed tracing of requests using OpenTelemetry, so that a single
+// request (e.g. publishing a document) can be followed across calls to backend services such as
+// the EMPI and the Cardiff and Vale PMS.
+//
+// Trace context is propagated to and from clients using gRPC metadata (and HTTP headers by the
+// gateway), and to outbound HTTP and SOAP calls using W3C trace context headers.
+// Until an exporter is started using Start, spans are not recorded.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/plugin/grpctrace"
+	"go.opentelemetry.io/otel/plugin/httptrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const instrumentationName = "github.com/wardle/concierge"
+
+// Options configures the export of traces
+type Options struct {
+	Exporter    string  // exporter to use: "otlp" or "stdout"
+	Addr        string  // address of OTLP collector (e.g. localhost:55680)
+	Insecure    bool    // whether to connect to OTLP collector without TLS
+	ServiceName string  // name of this service, as reported in traces
+	SampleRatio float64 // fraction of traces to sample, unless parent is sampled
+}
+
+var (
+	mu       sync.Mutex
+	shutdown func()
+)
+
+// Start starts the export of traces using the options specified
+func Start(opts Options) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if shutdown != nil {
+		return fmt.Errorf("tracing: already started")
+	}
+	var provider *sdktrace.Provider
+	cfg := sdktrace.Config{DefaultSampler: sdktrace.ProbabilitySampler(opts.SampleRatio)}
+	resource := sdktrace.WithResourceAttributes(key.String("service.name", opts.ServiceName))
+	switch opts.Exporter {
+	case "otlp":
+		exporterOpts := []otlp.ExporterOption{otlp.WithAddress(opts.Addr)}
+		if opts.Insecure {
+			exporterOpts = append(exporterOpts, otlp.WithInsecure())
+		}
+		exporter, err := otlp.NewExporter(exporterOpts...)
+		if err != nil {
+			return err
+		}
+		if provider, err = sdktrace.NewProvider(sdktrace.WithConfig(cfg), sdktrace.WithBatcher(exporter), resource); err != nil {
+			exporter.Stop()
+			return err
+		}
+		log.Printf("tracing: using OTLP collector at %s", opts.Addr)
+		shutdown = func() {
+			if err := exporter.Stop(); err != nil {
+				log.Printf("tracing: failed to stop exporter: %s", err)
+			}
+		}
+	case "stdout":
+		exporter, err := stdout.NewExporter(stdout.Options{})
+		if err != nil {
+			return err
+		}
+		if provider, err = sdktrace.NewProvider(sdktrace.WithConfig(cfg), sdktrace.WithSyncer(exporter), resource); err != nil {
+			return err
+		}
+		shutdown = func() {}
+	default:
+		return fmt.Errorf("tracing: unsupported exporter '%s'. supported: otlp, stdout", opts.Exporter)
+	}
+	global.SetTraceProvider(provider)
+	log.Printf("tracing: exporting traces using %s (sample ratio: %v)", opts.Exporter, opts.SampleRatio)
+	return nil
+}
+
+// Stop stops the export of traces, flushing any pending spans
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+	if shutdown != nil {
+		shutdown()
+		shutdown = nil
+	}
+}
+
+// Tracer returns the tracer for concierge
+func Tracer() trace.Tracer {
+	return global.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span as a child of any span within the context, with the attributes specified.
+// The caller must end the span, usually using defer span.End().
+func StartSpan(ctx context.Context, name string, attrs ...core.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// String returns a string attribute, for use with StartSpan
+func String(k string, v string) core.KeyValue {
+	return key.String(k, v)
+}
+
+// End ends the span, recording the error, if any. Use with defer, passing a pointer to the named error result:
+//
+//	ctx, span := tracing.StartSpan(ctx, "empi.fetch")
+//	defer tracing.End(ctx, span, &err)
+func End(ctx context.Context, span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(ctx, *err, trace.WithErrorStatus(status.Code(*err)))
+	}
+	span.End()
+}
+
+// ServerOptions returns options for a gRPC server to create a span for each incoming request,
+// continuing any trace propagated by the client.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpctrace.StreamServerInterceptor(Tracer())),
+	}
+}
+
+// unaryServerInterceptor wraps the grpctrace interceptor, which panics if a handler returns a nil
+// response (as is usual for an error), by substituting an empty message for the duration of the call
+func unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	traced := grpctrace.UnaryServerInterceptor(Tracer())
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var substituted bool
+		resp, err := traced(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			resp, err := handler(ctx, req)
+			if resp == nil {
+				substituted = true
+				return &emptypb.Empty{}, err
+			}
+			return resp, err
+		})
+		if substituted {
+			return nil, err
+		}
+		return resp, err
+	}
+}
+
+// DialOptions returns options for a gRPC client to create a span for each outgoing request,
+// propagating trace context to the server.
+func DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(grpctrace.UnaryClientInterceptor(Tracer())),
+		grpc.WithChainStreamInterceptor(grpctrace.StreamClientInterceptor(Tracer())),
+	}
+}
+
+// StartHTTPSpan starts a client span for an outbound HTTP request to the named endpoint, returning a
+// copy of the request that propagates the trace context to the remote server.
+func StartHTTPSpan(req *http.Request, endpoint string) (*http.Request, trace.Span) {
+	ctx, span := Tracer().Start(req.Context(), "HTTP "+req.Method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			key.String("http.method", req.Method),
+			key.String("http.url", req.URL.String()),
+			key.String("peer.service", endpoint)))
+	req = req.Clone(ctx)
+	httptrace.Inject(ctx, req)
+	return req, span
+}
+
+// EndHTTPSpan ends a span started by StartHTTPSpan, recording the response status or error
+func EndHTTPSpan(span trace.Span, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(context.Background(), err, trace.WithErrorStatus(codes.Unavailable))
+	case resp.StatusCode >= 400:
+		span.SetAttributes(key.Int("http.status_code", resp.StatusCode))
+		span.SetStatus(codes.Unknown, resp.Status)
+	default:
+		span.SetAttributes(key.Int("http.status_code", resp.StatusCode))
+	}
+	span.End()
+}
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// minDeadlines are the minimum times that must remain before a caller's deadline for a call to each named
+// backend service to be attempted
+var minDeadlines = make(map[string]time.Duration)
+
+// SetMinDeadline sets the minimum time that must remain before the caller's deadline for a call to the named
+// backend service, such as "cav" or "empi", to be attempted; calls are always attempted if zero.
+// This should not be called once server is running.
+func SetMinDeadline(name string, min time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if min <= 0 {
+		delete(minDeadlines, name)
+		return
+	}
+	minDeadlines[name] = min
+}
+
+// WithDeadline returns a context for a call to the named backend service, derived from that of the caller so
+// that the call is cancelled if the caller gives up, with a deadline no later than the timeout specified, being
+// the maximum time permitted for a call to that backend. An error is returned, rather than a call attempted, if
+// the caller has already given up, or if less than the minimum set for the backend remains before the caller's
+// deadline, as the call could not then complete in time. The caller must call cancel if there is no error.
+func WithDeadline(ctx context.Context, name string, timeout time.Duration) (context.Context, context.CancelFunc, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, status.FromContextError(err).Err()
+	}
+	mu.Lock()
+	min := minDeadlines[name]
+	mu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok && min > 0 {
+		if remaining := time.Until(deadline); remaining < min {
+			return nil, nil, status.Errorf(codes.DeadlineExceeded, "transport: %s: insufficient time remaining for call (%s remaining, minimum %s)", name, remaining.Round(time.Millisecond), min)
+		}
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
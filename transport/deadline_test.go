@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithDeadline(t *testing.T) {
+	// without a deadline from the caller, calls are limited to the timeout for the backend
+	ctx, cancel, err := WithDeadline(context.Background(), "test", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Fatalf("expected deadline within timeout. got: %v (%t)", deadline, ok)
+	}
+	cancel()
+
+	// an earlier deadline from the caller is retained
+	parent, cancelParent := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelParent()
+	expected, _ := parent.Deadline()
+	ctx, cancel, err = WithDeadline(parent, "test", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(expected) {
+		t.Fatalf("expected caller's deadline %s. got: %s", expected, deadline)
+	}
+	cancelParent()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected call cancelled when caller cancelled")
+	}
+	cancel()
+	if _, _, err := WithDeadline(parent, "test", time.Minute); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected call not attempted once caller cancelled. got: %v", err)
+	}
+
+	// calls fail fast if there is insufficient time remaining before the caller's deadline
+	SetMinDeadline("test", 500*time.Millisecond)
+	defer SetMinDeadline("test", 0)
+	parent, cancelParent = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelParent()
+	if _, _, err := WithDeadline(parent, "test", time.Minute); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded with insufficient time remaining. got: %v", err)
+	}
+	if _, cancel, err := WithDeadline(context.Background(), "test", time.Minute); err != nil {
+		t.Fatalf("calls without a deadline from the caller should always be attempted: %s", err)
+	} else {
+		cancel()
+	}
+	if _, cancel, err := WithDeadline(parent, "other", time.Minute); err != nil {
+		t.Fatalf("minimum should apply only to the backend for which it is set: %s", err)
+	} else {
+		cancel()
+	}
+}
@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/wardle/concierge/metrics"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Group deduplicates concurrent identical requests to a named backend service, so that callers
+// requesting the same key while a request is in flight share its result rather than each making
+// an upstream call. A nil group does not deduplicate requests. This is thread-safe.
+type Group struct {
+	name  string
+	group singleflight.Group
+}
+
+// NewGroup creates a group for deduplicating requests to the named backend
+func NewGroup(name string) *Group {
+	return &Group{name: name}
+}
+
+// Do performs the request for the key specified, unless an identical request is already in flight,
+// in which case its result is shared. Each caller receives its own copy of the result.
+//
+// The request is performed using a context that is not cancelled when the caller that started it
+// gives up, as other callers may be waiting on its result; fn should therefore apply its own timeout.
+// Each caller stops waiting when its own context is done.
+func (g *Group) Do(ctx context.Context, key string, fn func(ctx context.Context) (proto.Message, error)) (proto.Message, error) {
+	if g == nil {
+		return fn(ctx)
+	}
+	leader := false
+	ch := g.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		return fn(detached{ctx})
+	})
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, status.Errorf(codes.DeadlineExceeded, "transport: %s: deadline exceeded waiting for '%s'", g.name, key)
+		}
+		return nil, status.Errorf(codes.Canceled, "transport: %s: cancelled waiting for '%s'", g.name, key)
+	case result := <-ch:
+		if !leader {
+			metrics.Deduplicated(g.name)
+		}
+		msg, _ := result.Val.(proto.Message)
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Shared && msg != nil {
+			return proto.Clone(msg), nil
+		}
+		return msg, nil
+	}
+}
+
+// detached is a context with the values of its parent, such as the authenticated user and tracing
+// span, but which is never cancelled and has no deadline
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (d detached) Done() <-chan struct{}             { return nil }
+func (d detached) Err() error                        { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDedupe(t *testing.T) {
+	g := NewGroup("test-dedupe")
+	var calls int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (proto.Message, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		if ctx.Err() != nil {
+			t.Errorf("request cancelled: %v", ctx.Err())
+		}
+		return &apiv1.Identifier{System: "test", Value: "1111111111"}, nil
+	}
+	var wg sync.WaitGroup
+	results := make([]proto.Message, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+			if i == 0 { // the caller that starts the request gives up; others should be unaffected
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			result, err := g.Do(ctx, "1111111111", fetch)
+			if i == 0 {
+				if status.Code(err) != codes.Canceled {
+					t.Errorf("expected cancelled caller to return Canceled, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = result
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("expected a single upstream request, got %d", calls)
+	}
+	for _, result := range results[1:] {
+		if result.(*apiv1.Identifier).GetValue() != "1111111111" {
+			t.Errorf("unexpected result: %v", result)
+		}
+	}
+	if results[1] == results[2] {
+		t.Errorf("expected each caller to receive its own copy of the result")
+	}
+	var nilGroup *Group
+	if _, err := nilGroup.Do(context.Background(), "1111111111", func(ctx context.Context) (proto.Message, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected nil group to perform request, got: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// endpointConfig is the proxy and TLS configuration for a named endpoint, and a transport using that
+// configuration shared by requests to that endpoint
+type endpointConfig struct {
+	proxy     *Proxy
+	tlsConfig *tls.Config
+	transport *http.Transport
+}
+
+// SetProxy sets the proxy used for HTTP requests to the named endpoint, by clients subsequently created
+// without a base transport; other endpoints use the proxy defined by the environment, if any.
+// This should not be called once server is running.
+func SetProxy(name string, p *Proxy) {
+	configure(name, func(cfg *endpointConfig) { cfg.proxy = p })
+}
+
+// SetTLSConfig sets the TLS configuration used for HTTP requests to the named endpoint, by clients
+// subsequently created without a base transport; other endpoints use the default configuration.
+// This should not be called once server is running.
+func SetTLSConfig(name string, tlsConfig *tls.Config) {
+	configure(name, func(cfg *endpointConfig) { cfg.tlsConfig = tlsConfig })
+}
+
+func configure(name string, f func(cfg *endpointConfig)) {
+	mu.Lock()
+	defer mu.Unlock()
+	cfg, ok := configs[name]
+	if !ok {
+		cfg = new(endpointConfig)
+		configs[name] = cfg
+	}
+	f(cfg)
+	if cfg.proxy == nil && cfg.tlsConfig == nil {
+		delete(configs, name)
+		return
+	}
+	cfg.transport = NewHTTPTransport(HTTPOptions{Proxy: cfg.proxy.ProxyFunc(), TLSConfig: cfg.tlsConfig})
+}
+
+// ProxyFor returns a function for use as the proxy of a HTTP transport for the named endpoint
+func ProxyFor(name string) func(*http.Request) (*url.URL, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if cfg, ok := configs[name]; ok {
+		return cfg.proxy.ProxyFunc()
+	}
+	return http.ProxyFromEnvironment
+}
+
+// TLSConfigFor returns the TLS configuration for the named endpoint, or nil for the default configuration
+func TLSConfigFor(name string) *tls.Config {
+	mu.Lock()
+	defer mu.Unlock()
+	if cfg, ok := configs[name]; ok && cfg.tlsConfig != nil {
+		return cfg.tlsConfig.Clone()
+	}
+	return nil
+}
+
+// baseTransport returns the transport for requests to the named endpoint, for clients created without one
+func baseTransport(name string) http.RoundTripper {
+	mu.Lock()
+	defer mu.Unlock()
+	if cfg, ok := configs[name]; ok {
+		return cfg.transport
+	}
+	return http.DefaultTransport
+}
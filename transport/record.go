@@ -0,0 +1,203 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Mode determines whether interactions with backend services are recorded or replayed
+type Mode int
+
+// Modes of recording
+const (
+	Live   Mode = iota // requests are made to backend services
+	Record             // requests are made to backend services, and responses recorded to fixture files
+	Replay             // responses are replayed from fixture files, without requests to backend services
+)
+
+// Recording configures how interactions with a named endpoint are recorded and replayed.
+// Requests are matched to recorded responses using their method, path and body, so content that varies
+// between otherwise identical requests, such as timestamps, message identifiers and authentication tokens,
+// must be declared as volatile. Requests themselves are not recorded.
+type Recording struct {
+	Volatile []*regexp.Regexp // request content ignored when matching a request to a recorded response
+	Redact   []Redaction      // redactions of patient identifiable data in responses, applied before recording
+}
+
+// Redaction replaces content matching a pattern; the replacement may refer to submatches, e.g. ${1}REDACTED${2}
+type Redaction struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// RedactXML returns redactions that replace the text content of the XML elements specified
+func RedactXML(elements ...string) []Redaction {
+	result := make([]Redaction, 0, len(elements))
+	for _, e := range elements {
+		e = regexp.QuoteMeta(e)
+		result = append(result, Redaction{
+			Pattern:     regexp.MustCompile(`(<(?:[\w-]+:)?` + e + `(?:\s[^>]*)?>)[^<]+(</(?:[\w-]+:)?` + e + `>)`),
+			Replacement: "${1}REDACTED${2}",
+		})
+	}
+	return result
+}
+
+// fixture is a recorded response from a backend service
+type fixture struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body"`
+}
+
+var (
+	recordMode Mode
+	recordDir  string
+	recordings = make(map[string]Recording)
+)
+
+// SetMode sets whether interactions with backend services by clients subsequently created are recorded to,
+// or replayed from, fixture files in the directory specified, with a subdirectory for each named endpoint.
+// This should not be called once server is running.
+func SetMode(mode Mode, dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	recordMode, recordDir = mode, dir
+}
+
+// SetRecording sets how interactions with the named endpoint are recorded and replayed.
+// This should not be called once server is running.
+func SetRecording(name string, r Recording) {
+	mu.Lock()
+	defer mu.Unlock()
+	recordings[name] = r
+}
+
+// recorder returns a round tripper recording to, or replaying from, fixture files, or the base round tripper if live
+func recorder(name string, base http.RoundTripper) http.RoundTripper {
+	mu.Lock()
+	defer mu.Unlock()
+	if recordMode == Live {
+		return base
+	}
+	return &recordingRoundTripper{name: name, base: base, mode: recordMode, dir: filepath.Join(recordDir, name), recording: recordings[name]}
+}
+
+type recordingRoundTripper struct {
+	name      string
+	base      http.RoundTripper
+	mode      Mode
+	dir       string
+	recording Recording
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	filename := filepath.Join(rt.dir, rt.key(req, body)+".json")
+	if rt.mode == Replay {
+		return rt.replay(req, filename)
+	}
+	resp, err := rt.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if resp.StatusCode >= 500 {
+		return resp, nil // do not record transient failures
+	}
+	f := &fixture{Method: req.Method, Path: req.URL.RequestURI(), Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Body: string(rt.redact(data))}
+	if err := writeFixture(filename, f); err != nil {
+		log.Printf("transport: failed to record response from '%s': %s", rt.name, err)
+	} else {
+		log.Printf("transport: recorded response from '%s' for %s %s to %s", rt.name, req.Method, req.URL.Path, filename)
+	}
+	return resp, nil
+}
+
+func (rt *recordingRoundTripper) replay(req *http.Request, filename string) (*http.Response, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("transport: no recorded response from '%s' for %s %s (%s)", rt.name, req.Method, req.URL.Path, filename)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("transport: invalid recorded response %s: %w", filename, err)
+	}
+	header := make(http.Header)
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}, nil
+}
+
+// key returns the key for the request, derived from its method, path and body, ignoring volatile content.
+// Form encoded bodies are decoded, so that volatile content can be matched as is.
+func (rt *recordingRoundTripper) key(req *http.Request, body []byte) string {
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if decoded, err := url.QueryUnescape(string(body)); err == nil {
+			body = []byte(decoded)
+		}
+	}
+	for _, re := range rt.recording.Volatile {
+		body = re.ReplaceAll(body, nil)
+	}
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// redact removes patient identifiable data from a response
+func (rt *recordingRoundTripper) redact(data []byte) []byte {
+	for _, r := range rt.recording.Redact {
+		data = r.Pattern.ReplaceAll(data, []byte(r.Replacement))
+	}
+	return data
+}
+
+func writeFixture(filename string, f *fixture) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(`<PID><ns:FN.1 Type="ST">DUMMY</ns:FN.1><PID.8>M</PID.8></PID>`))
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	SetRecording("test-record", Recording{
+		Volatile: []*regexp.Regexp{regexp.MustCompile(`<MSH\.10>[^<]*</MSH\.10>`)},
+		Redact:   RedactXML("FN.1"),
+	})
+	defer SetMode(Live, "")
+	post := func(body string) (string, error) {
+		resp, err := NewClient("test-record", nil, true).Post(ts.URL+"/query", "text/xml", strings.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		return string(data), err
+	}
+	SetMode(Record, dir)
+	got, err := post("<MSH.10>1</MSH.10><QPD>1111111111</QPD>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "DUMMY") {
+		t.Fatalf("expected unredacted response when recording, got %s", got)
+	}
+	SetMode(Replay, dir)
+	if got, err = post("<MSH.10>2</MSH.10><QPD>1111111111</QPD>"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected replay without request, got %d requests", calls)
+	}
+	if got != `<PID><ns:FN.1 Type="ST">REDACTED</ns:FN.1><PID.8>M</PID.8></PID>` {
+		t.Fatalf("unexpected replayed response: %s", got)
+	}
+	if _, err = post("<MSH.10>1</MSH.10><QPD>2222222222</QPD>"); err == nil {
+		t.Fatal("expected error replaying request that was not recorded")
+	}
+}
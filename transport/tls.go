@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLSOptions configures TLS for connections to a backend service
+type TLSOptions struct {
+	CAFile     string // PEM bundle of CA certificates trusted in addition to the system roots, e.g. NHS Wales internal CAs
+	CertFile   string // PEM client certificate, for endpoints requiring mutual TLS
+	KeyFile    string // PEM private key of the client certificate
+	MinVersion string // minimum TLS version: 1.2 (default) or 1.3
+}
+
+// tlsVersions are the TLS versions that may be configured as a minimum
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSConfig creates a TLS configuration from the options specified. Server certificates are always verified.
+func (opts TLSOptions) TLSConfig() (*tls.Config, error) {
+	version, ok := tlsVersions[opts.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("transport: unsupported minimum TLS version '%s': expected 1.2 or 1.3", opts.MinVersion)
+	}
+	cfg := &tls.Config{MinVersion: version}
+	if opts.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		ca, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("transport: could not read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("transport: no certificates found in CA bundle '%s'", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("transport: could not load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
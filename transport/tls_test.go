@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSConfig(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "transport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := TLSOptions{}.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 || cfg.InsecureSkipVerify {
+		t.Fatalf("expected TLS 1.2 with verification by default, got: %+v", cfg)
+	}
+	client := &http.Client{Transport: NewHTTPTransport(HTTPOptions{TLSConfig: cfg})}
+	if _, err := client.Get(ts.URL); err == nil {
+		t.Fatalf("expected certificate from untrusted CA to be rejected")
+	}
+	cfg, err = TLSOptions{CAFile: caFile, MinVersion: "1.2"}.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: NewHTTPTransport(HTTPOptions{TLSConfig: cfg})}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected certificate from CA bundle to be trusted: %s", err)
+	}
+	resp.Body.Close()
+	if _, err := (TLSOptions{MinVersion: "1.0"}).TLSConfig(); err == nil {
+		t.Errorf("expected error for insecure minimum TLS version")
+	}
+	if _, err := (TLSOptions{CAFile: filepath.Join(dir, "missing.pem")}).TLSConfig(); err == nil {
+		t.Errorf("expected error for missing CA bundle")
+	}
+}
@@ -0,0 +1,342 @@
+// Package transport provides a resilient HTTP transport for outbound calls to backend services,
+// with retries (exponential backoff with jitter) and a circuit breaker for each named endpoint.
+//
+// Once an endpoint has failed a number of times in succession, its breaker opens and requests
+// fail immediately until a reset timeout has elapsed, after which a single trial request is
+// permitted. This protects both concierge and struggling backend services from a backlog of
+// requests that are likely to fail.
+package transport
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/tracing"
+)
+
+// Options configures retries and circuit breakers
+type Options struct {
+	MaxRetries       int           // maximum number of retries after the initial attempt
+	BaseDelay        time.Duration // delay before first retry; doubled for each subsequent retry
+	MaxDelay         time.Duration // maximum delay between retries
+	FailureThreshold int           // number of consecutive failures before a breaker opens; 0 = no breaker
+	ResetTimeout     time.Duration // time for which a breaker remains open before permitting a trial request
+}
+
+// DefaultOptions are the default options for retries and circuit breakers
+var DefaultOptions = Options{
+	MaxRetries:       2,
+	BaseDelay:        100 * time.Millisecond,
+	MaxDelay:         2 * time.Second,
+	FailureThreshold: 5,
+	ResetTimeout:     30 * time.Second,
+}
+
+var (
+	mu       sync.Mutex
+	options  = DefaultOptions
+	breakers = make(map[string]*Breaker)
+	configs  = make(map[string]*endpointConfig)
+)
+
+// Configure sets the options used for all clients subsequently created
+func Configure(opts Options) {
+	mu.Lock()
+	defer mu.Unlock()
+	options = opts
+}
+
+// ErrOpen is returned when a request is not attempted because the endpoint's circuit breaker is open
+var ErrOpen = errors.New("transport: circuit breaker open")
+
+// State is the state of a circuit breaker
+type State int
+
+// The states of a circuit breaker
+const (
+	Closed   State = iota // requests permitted
+	Open                  // requests fail immediately
+	HalfOpen              // a single trial request is permitted
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown (%d)", s)
+}
+
+// Breaker is a circuit breaker for a single named endpoint
+type Breaker struct {
+	name      string
+	threshold int
+	reset     time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// allow determines whether a request should be attempted
+func (b *Breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Open:
+		if time.Since(b.openedAt) < b.reset {
+			return false
+		}
+		b.setState(HalfOpen)
+		return true
+	case HalfOpen:
+		return false // trial request already in progress
+	}
+	return true
+}
+
+// record records the outcome of a request
+func (b *Breaker) record(success bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		if b.state != Closed {
+			log.Printf("transport: circuit breaker for '%s' closed", b.name)
+		}
+		b.setState(Closed)
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		if b.state != Open {
+			log.Printf("transport: circuit breaker for '%s' opened after %d failures", b.name, b.failures)
+		}
+		b.setState(Open)
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon records that a request was abandoned by its caller, which says nothing of the health of the endpoint.
+// An abandoned trial request permits another trial.
+func (b *Breaker) abandon() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen {
+		b.setState(Open)
+	}
+}
+
+// setState changes the state of the breaker, recording the change in metrics. Caller must hold lock.
+func (b *Breaker) setState(s State) {
+	if s != b.state {
+		metrics.BreakerState(b.name, int(s), s == Open)
+	}
+	b.state = s
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && time.Since(b.openedAt) >= b.reset {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// breaker returns the breaker for the named endpoint, creating it if necessary
+func breaker(name string, opts Options) *Breaker {
+	if opts.FailureThreshold <= 0 {
+		return nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	b, ok := breakers[name]
+	if !ok {
+		b = &Breaker{name: name, threshold: opts.FailureThreshold, reset: opts.ResetTimeout}
+		breakers[name] = b
+	}
+	return b
+}
+
+// States returns the state of all circuit breakers, keyed by endpoint name
+func States() map[string]State {
+	mu.Lock()
+	defer mu.Unlock()
+	result := make(map[string]State, len(breakers))
+	for name, b := range breakers {
+		result[name] = b.State()
+	}
+	return result
+}
+
+// Names returns the names of all endpoints with circuit breakers, sorted by name
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]string, 0, len(breakers))
+	for name := range breakers {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// NewClient creates a HTTP client for the named endpoint, using the base transport specified,
+// or, if nil, a transport using the proxy set for the endpoint or http.DefaultTransport.
+// Responses are recorded or replayed, if set using SetMode.
+// If idempotent is false, requests are only retried if a connection could not be established,
+// so that the request cannot have been received by the remote server.
+func NewClient(name string, base http.RoundTripper, idempotent bool) *http.Client {
+	return &http.Client{Transport: NewRoundTripper(name, base, idempotent)}
+}
+
+// NewRoundTripper creates a resilient round tripper for the named endpoint. See NewClient.
+func NewRoundTripper(name string, base http.RoundTripper, idempotent bool) http.RoundTripper {
+	if base == nil {
+		base = baseTransport(name)
+	}
+	base = recorder(name, base)
+	mu.Lock()
+	opts := options
+	mu.Unlock()
+	return &roundTripper{name: name, base: base, idempotent: idempotent, opts: opts, breaker: breaker(name, opts)}
+}
+
+// HTTPOptions configures the connection pool of a HTTP transport shared by requests to a backend service
+type HTTPOptions struct {
+	MaxIdleConnsPerHost int                                   // maximum idle (keep-alive) connections retained for each host; 0 uses the default of 2
+	IdleConnTimeout     time.Duration                         // time after which an idle connection is closed; 0 uses the default
+	TLSConfig           *tls.Config                           // TLS configuration; default configuration if nil
+	Proxy               func(*http.Request) (*url.URL, error) // proxy for each request; proxy from environment if nil
+}
+
+// NewHTTPTransport creates a HTTP transport, based on http.DefaultTransport, with the connection pool and
+// TLS configuration specified. A transport should be created once for each backend service and then shared,
+// so that connections, and their TLS sessions, are reused rather than established for each request.
+func NewHTTPTransport(opts HTTPOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		if t.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			t.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.TLSConfig != nil {
+		t.TLSClientConfig = opts.TLSConfig
+	}
+	if opts.Proxy != nil {
+		t.Proxy = opts.Proxy
+	}
+	return t
+}
+
+type roundTripper struct {
+	name       string
+	base       http.RoundTripper
+	idempotent bool
+	opts       Options
+	breaker    *Breaker
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if !rt.breaker.allow() {
+			return nil, fmt.Errorf("%w: %s", ErrOpen, rt.name)
+		}
+		start := time.Now()
+		treq, span := tracing.StartHTTPSpan(req, rt.name)
+		resp, err := rt.base.RoundTrip(treq)
+		tracing.EndHTTPSpan(span, resp, err)
+		if resp != nil {
+			metrics.ObserveHTTP(rt.name, resp.StatusCode, time.Since(start))
+		} else {
+			metrics.ObserveHTTP(rt.name, 0, time.Since(start))
+		}
+		failed := err != nil || isRetryableStatus(resp.StatusCode)
+		if failed && req.Context().Err() != nil {
+			rt.breaker.abandon() // the caller gave up or ran out of time, so this is not a failure of the endpoint
+			return resp, err
+		}
+		rt.breaker.record(!failed)
+		if !failed || attempt >= rt.opts.MaxRetries || !rt.retryable(req, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		delay := rt.backoff(attempt)
+		log.Printf("transport: request to '%s' failed (attempt %d): retrying in %s", rt.name, attempt+1, delay)
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryable determines whether a failed request can safely be retried
+func (rt *roundTripper) retryable(req *http.Request, err error) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false // cannot replay the request body
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if rt.idempotent {
+		return true
+	}
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoff returns the delay before the next attempt, using exponential backoff with "full jitter"
+func (rt *roundTripper) backoff(attempt int) time.Duration {
+	d := rt.opts.BaseDelay << uint(attempt)
+	if d > rt.opts.MaxDelay || d <= 0 {
+		d = rt.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	Configure(Options{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, FailureThreshold: 10, ResetTimeout: time.Minute})
+	defer Configure(DefaultOptions)
+	resp, err := NewClient("test-retry", nil, true).Post(ts.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("expected success after 3 attempts, got status %d after %d attempts", resp.StatusCode, calls)
+	}
+	// non-idempotent requests are not retried if the server responded
+	calls = 0
+	resp, err = NewClient("test-retry", nil, false).Post(ts.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("non-idempotent request retried: %d attempts", calls)
+	}
+}
+
+func TestBreakerCancellation(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	Configure(Options{MaxRetries: 2, FailureThreshold: 2, ResetTimeout: time.Minute})
+	defer Configure(DefaultOptions)
+	client := NewClient("test-breaker-cancel", nil, true)
+	removeBreaker(t, "test-breaker-cancel")
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Do(req); err == nil {
+			t.Fatal("expected error for request exceeding deadline")
+		}
+		cancel()
+	}
+	if state := States()["test-breaker-cancel"]; state != Closed {
+		t.Fatalf("expected breaker to remain closed after cancelled requests, got: %s", state)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("cancelled requests should not be retried: %d calls", n)
+	}
+	// an abandoned trial request permits another trial, rather than leaving the breaker half-open
+	b := &Breaker{name: "test-breaker-trial", threshold: 1}
+	b.record(false)
+	if !b.allow() || b.State() != HalfOpen {
+		t.Fatalf("expected trial request to be permitted")
+	}
+	b.abandon()
+	if !b.allow() {
+		t.Fatalf("expected another trial to be permitted after trial abandoned")
+	}
+}
+
+// removeBreaker removes the breaker for the named endpoint once the test completes,
+// so that the test starts with a closed breaker if run again
+func removeBreaker(t *testing.T, name string) {
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(breakers, name)
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+	Configure(Options{MaxRetries: 0, FailureThreshold: 2, ResetTimeout: 50 * time.Millisecond})
+	defer Configure(DefaultOptions)
+	client := NewClient("test-breaker", nil, true)
+	removeBreaker(t, "test-breaker")
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if state := States()["test-breaker"]; state != Open {
+		t.Fatalf("expected breaker to be open, got: %s", state)
+	}
+	if _, err := client.Get(ts.URL); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected breaker open error, got: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("request made despite open breaker: %d calls", calls)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if state := States()["test-breaker"]; state != HalfOpen {
+		t.Fatalf("expected breaker to be half-open, got: %s", state)
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if state := States()["test-breaker"]; state != Open {
+		t.Fatalf("expected breaker to re-open after failed trial, got: %s", state)
+	}
+}
@@ -0,0 +1,128 @@
+package cav
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetCurrentAdmission returns the current inpatient admission of the patient with the CRN specified,
+// or NotFound if the patient is not currently admitted
+func (pms *PMSService) GetCurrentAdmission(ctx context.Context, id *apiv1.Identifier) (admission *apiv1.Admission, err error) {
+	defer metrics.Observe("cav", "admission", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "cav.admission")
+	defer tracing.End(ctx, span, &err)
+	if id.GetSystem() != identifiers.CardiffAndValeCRN {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to fetch admission: incorrect 'system'. expected: '%s' got:'%s'", identifiers.CardiffAndValeCRN, id.GetSystem())
+	}
+	crn, err := parseCRN(id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	var rows []map[string]string
+	if pms.fake {
+		rows = fakeAdmission(crn.Type + crn.CRN)
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		token, err := pms.authenticationToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sql, err := sqlFetchCurrentAdmission.build(params{"type": crn.Type, "crn": crn.CRN})
+		if err != nil {
+			return nil, err
+		}
+		if rows, err = pms.performSQL(ctx, token, sql); err != nil {
+			return nil, err
+		}
+	}
+	if len(rows) == 0 { // rows are ordered by admission date, most recent first
+		return nil, status.Errorf(codes.NotFound, "patient %s not currently admitted", id.GetValue())
+	}
+	admission, err = parseAdmission(rows[0])
+	if err != nil {
+		return nil, err
+	}
+	admission.Patient = &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn.Type + crn.CRN}
+	return admission, nil
+}
+
+// parseAdmission parses an admission from a row returned by sqlFetchCurrentAdmission
+func parseAdmission(row map[string]string) (*apiv1.Admission, error) {
+	admitted, err := parseDateTime(row["ADMITTED"])
+	if err != nil {
+		return nil, err
+	}
+	admission := &apiv1.Admission{
+		Id:        &apiv1.Identifier{System: identifiers.CardiffAndValeAdmissionID, Value: row["SPELL_ID"]},
+		Admitted:  admitted,
+		Ward:      row["WARD"],
+		WardName:  row["WARD_NAME"],
+		Bed:       row["BED"],
+		Specialty: identifiers.New(identifiers.NHSSpecialty, row["SPECIALTY"]),
+	}
+	if row["HCP_SURNAME"] != "" {
+		admission.Consultant = parseClinician(row)
+	}
+	return admission, nil
+}
+
+// sqlFetchCurrentAdmission fetches the open hospital spells of a patient, with their current ward stay,
+// bed, and the consultant responsible, most recent first
+var sqlFetchCurrentAdmission = mustQuery("current-admission", `SELECT PROVIDER_SPELLS.PRVSP_ID AS SPELL_ID,
+to_char(PROVIDER_SPELLS.ADMIT_DTTM, 'yyyy/mm/dd hh24:mi:ss') AS ADMITTED,
+WARDS.CODE AS WARD, WARDS.DESCRIPTION AS WARD_NAME, WARD_STAYS.BED_CODE AS BED,
+SPECIALTIES.NATIONAL_CODE AS SPECIALTY,
+CONSULTANTS.national_no AS HCP_ID, CONSULTANTS.TITLE AS HCP_TITLE,
+CONSULTANTS.SURNAME AS HCP_SURNAME, CONSULTANTS.FORENAME AS HCP_FORENAME
+FROM PATIENT_IDENTIFIERS, PROVIDER_SPELLS, WARD_STAYS, WARDS, SPECIALTIES,
+HEALTHCARE_PRACTITIONERS CONSULTANTS
+WHERE PATIENT_IDENTIFIERS.PAID_TYPE = :type
+AND PATIENT_IDENTIFIERS.ID = :crn
+AND PATIENT_IDENTIFIERS.CRN = 'Y'
+AND PATIENT_IDENTIFIERS.MAJOR_FLAG = 'Y'
+AND PROVIDER_SPELLS.PATI_ID = PATIENT_IDENTIFIERS.PATI_ID
+AND PROVIDER_SPELLS.DISCH_DTTM IS NULL
+AND WARD_STAYS.PRVSP_ID (+) = PROVIDER_SPELLS.PRVSP_ID
+AND WARD_STAYS.END_DTTM (+) IS NULL
+AND WARDS.WARD_ID (+) = WARD_STAYS.WARD_ID
+AND SPECIALTIES.SPEC_ID (+) = PROVIDER_SPELLS.SPEC_ID
+AND CONSULTANTS.PERS_ID (+) = PROVIDER_SPELLS.HCP_ID
+ORDER BY PROVIDER_SPELLS.ADMIT_DTTM DESC`)
+
+// fakeAdmission returns a row for the current admission of a patient in the simulator, if any
+func fakeAdmission(crn string) []map[string]string {
+	a, found := simulator.Current().Admission(crn)
+	if !found {
+		return nil
+	}
+	row := map[string]string{
+		"SPELL_ID":  a.ID,
+		"ADMITTED":  strings.Replace(a.Admitted, "-", "/", -1) + ":00",
+		"WARD":      a.Ward,
+		"WARD_NAME": a.WardName,
+		"BED":       a.Bed,
+		"SPECIALTY": a.Specialty,
+	}
+	for k, v := range fakeClinician(a.Consultant) {
+		row[k] = v
+	}
+	return []map[string]string{row}
+}
@@ -18,33 +18,44 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"text/template"
 	"time"
 
-	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/patrickmn/go-cache"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/hl7time"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/secrets"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tenant"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
 	"github.com/wardle/concierge/wales/cav/soap"
-	"github.com/wardle/concierge/wales/empi"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // PMSService represents the Cardiff and Vale Patient Management System (PMS) service.
 // This is thread-safe.
 type PMSService struct {
-	username string
-	password string
-	timeout  time.Duration
-	fake     bool
+	timeout time.Duration
+	fake    bool
+	env     *Environment
 
-	tokenMu      sync.RWMutex
-	token        string
-	tokenExpires time.Time
+	session *session            // credentials and authentication token used by default
+	tenants map[string]*session // credentials and authentication token used for each tenant, if configured
+
+	published    *cache.Cache            // CAV document id -> our unique identifier, for documents published by this instance
+	contentTypes map[string]*ContentType // MIME type -> configuration, for content types that may be published
+	dedupe       *transport.Group        // deduplicates concurrent identical requests; may be nil
 }
 
 // NewPMSService creates a new (thread-safe) PMS Service with the specified timeout
@@ -56,11 +67,65 @@ func NewPMSService(username string, password string, timeout time.Duration, fake
 		log.Printf("cav: running in fake mode")
 	}
 	return &PMSService{
-		username: username,
-		password: password,
-		timeout:  timeout,
-		fake:     fake,
+		timeout:      timeout,
+		fake:         fake,
+		env:          &LiveEnvironment,
+		session:      &session{username: username, password: secrets.Static(password), tokens: NewMemoryTokenStore()},
+		published:    cache.New(publishedTTL, time.Hour),
+		contentTypes: DefaultContentTypes(),
+	}
+}
+
+// SetPassword sets the password used to authenticate to the PMS, which may be rotated while running.
+// This should not be called once server is running.
+func (pms *PMSService) SetPassword(password secrets.Secret) {
+	pms.session.password = password
+}
+
+// SetTenantCredentials sets the credentials used to authenticate to the PMS for calls on behalf of the named
+// tenant, in place of the default credentials. The authentication token for each tenant is kept in memory.
+// This should not be called once server is running.
+func (pms *PMSService) SetTenantCredentials(name string, username string, password secrets.Secret) {
+	if pms.tenants == nil {
+		pms.tenants = make(map[string]*session)
+	}
+	pms.tenants[name] = &session{username: username, password: password, tokens: NewMemoryTokenStore()}
+	log.Printf("cav: using separate credentials for tenant '%s'", name)
+}
+
+// SetDedupe sets the group used to deduplicate concurrent identical requests to the PMS.
+// This should not be called once server is running.
+func (pms *PMSService) SetDedupe(group *transport.Group) {
+	pms.dedupe = group
+}
+
+var _ apiv1.ClinicServiceServer = (*PMSService)(nil)
+var _ apiv1.DocumentRepositoryServer = (*PMSService)(nil)
+
+// RegisterServer registers this server, providing clinic and document repository services
+func (pms *PMSService) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterClinicServiceServer(s, pms)
+	apiv1.RegisterDocumentRepositoryServer(s, pms)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (pms *PMSService) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	if err := apiv1.RegisterClinicServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return err
+	}
+	return apiv1.RegisterDocumentRepositoryHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (pms *PMSService) Close() error { return nil }
+
+// HealthCheck checks that CAV PMS can be reached, by obtaining an authentication token
+func (pms *PMSService) HealthCheck(ctx context.Context) error {
+	if pms.fake {
+		return nil
 	}
+	_, err := pms.authenticationToken(ctx)
+	return err
 }
 
 // ResolveIdentifier provides an identifier/value resolution service for CAV CRNs
@@ -72,21 +137,49 @@ func (pms *PMSService) ResolveIdentifier(ctx context.Context, id *apiv1.Identifi
 	return pms.FetchPatient(ctx, id.GetValue())
 }
 
+// GetPatient returns the patient with the specified Cardiff and Vale identifier
+func (pms *PMSService) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if id.GetSystem() != identifiers.CardiffAndValeCRN {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to fetch patient: incorrect 'system'. expected: '%s' got:'%s'", identifiers.CardiffAndValeCRN, id.GetSystem())
+	}
+	return pms.FetchPatient(ctx, id.GetValue())
+}
+
 // FetchPatient fetches patient data from the CAV PAS (PMS)
 // This query returns multiple rows for a single patient because of the address history
-func (pms *PMSService) FetchPatient(ctx context.Context, crn string) (*apiv1.Patient, error) {
+func (pms *PMSService) FetchPatient(ctx context.Context, crn string) (pt *apiv1.Patient, err error) {
+	defer metrics.Observe("cav", "fetch", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "cav.fetch")
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
 	if pms.fake {
-		if crn != "A999998" {
+		pt, found := simulator.Current().Patient(simulator.CAV, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn})
+		if !found {
 			return nil, status.Errorf(codes.NotFound, "No patient found with identifier %s", crn)
 		}
-		result, err := (&empi.App{Fake: true}).ResolveIdentifier(ctx, &apiv1.Identifier{Value: crn})
-		if err != nil {
-			return nil, err
-		}
-		return result.(*apiv1.Patient), nil
+		return pt, nil
 	}
+	key := crn
+	if name := tenant.FromContext(ctx); pms.tenants[name] != nil {
+		key = name + "|" + crn // not shared with calls using other credentials
+	}
+	result, err := pms.dedupe.Do(ctx, key, func(ctx context.Context) (proto.Message, error) {
+		return pms.fetchPatient(ctx, crn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*apiv1.Patient), nil
+}
 
-	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
+// fetchPatient fetches the patient with the specified CRN from the PMS
+func (pms *PMSService) fetchPatient(ctx context.Context, crn string) (*apiv1.Patient, error) {
+	ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+	if err != nil {
+		return nil, err
+	}
 	defer cancelFunc()
 	token, err := pms.authenticationToken(ctx)
 	if err != nil {
@@ -97,19 +190,36 @@ func (pms *PMSService) FetchPatient(ctx context.Context, crn string) (*apiv1.Pat
 	if err != nil {
 		return nil, err
 	}
-	pts, err := performSQL(ctx, token, sql)
+	pts, err := pms.performSQL(ctx, token, sql)
 	if err != nil {
 		return nil, err
 	}
 	if len(pts) == 0 {
 		return nil, status.Errorf(codes.NotFound, "No patient found with identifier '%s'", crn)
 	}
-	return parsePatientAndAddresses(pts)
+	pt, err := parsePatientAndAddresses(pts)
+	if err != nil {
+		return nil, err
+	}
+	events.PublishPatientIfChanged("cav/"+crn, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn}, pt)
+	return pt, nil
 }
 
-// PatientsForClinics returns the patients scheduled for the specified clinics on the specified dates
-func (pms *PMSService) PatientsForClinics(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error) {
-	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
+// PatientsForClinics returns the patients scheduled for the specified clinics on the specified dates.
+// Patients are returned for each clinic in turn, ordered by name, so that results are in a consistent order.
+// Use GetClinicSchedule to page through the appointments of large clinics.
+func (pms *PMSService) PatientsForClinics(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) (pts []*apiv1.Patient, err error) {
+	defer metrics.Observe("cav", "clinics", time.Now(), &err)
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	if pms.fake {
+		return fakePatientsForClinics(date, clinics), nil
+	}
+	ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+	if err != nil {
+		return nil, err
+	}
 	defer cancelFunc()
 	token, err := pms.authenticationToken(ctx)
 	if err != nil {
@@ -124,7 +234,7 @@ func (pms *PMSService) PatientsForClinics(ctx context.Context, date time.Time, c
 		if err != nil {
 			return nil, err
 		}
-		rows, err := performSQL(ctx, token, sql)
+		rows, err := pms.performSQL(ctx, token, sql)
 		if err != nil {
 			return nil, err
 		}
@@ -143,16 +253,25 @@ func (pms *PMSService) PatientsForClinics(ctx context.Context, date time.Time, c
 // PublishDocument publishes the document into the CAV document repository
 // returning a receipt, which currently includes the identifier. You'll be able to (eventually)
 // resolve that identifier and get back the document, or perhaps another URL.
-func (pms *PMSService) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+// A document replacing a version previously published to CAV PMS is published using the identifier of
+// the original version, so that the PMS replaces it.
+func (pms *PMSService) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (response *apiv1.PublishDocumentResponse, err error) {
+	defer metrics.Observe("cav", "publish", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "cav.publish")
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckWrite(ctx, "cav"); err != nil {
+		return nil, err
+	}
 	d := r.GetDocument()
 	cavIDs, ok := d.GetPatient().GetIdentifiersForSystem(identifiers.CardiffAndValeCRN)
 	if !ok {
 		log.Printf("cav: unable to publish document '%s|%s' as no CRN identified for Cardiff and Vale", d.GetId().GetSystem(), d.GetId().GetValue())
 		return nil, fmt.Errorf("unable to publish document - no valid Cardiff and Vale identifier")
 	}
-	if d.GetData().GetContentType() != "application/pdf" {
-		log.Printf("cav: unable to publish document '%s|%s': wrong content-type expected: 'application/pdf' got: '%s'", d.GetId().GetSystem(), d.GetId().GetValue(), d.GetData().GetContentType())
-		return nil, fmt.Errorf("unable to publish document - incorrect content-type '%s'", d.GetData().GetContentType())
+	fileType, key, err := pms.fileTypeAndKey(d)
+	if err != nil {
+		log.Printf("cav: unable to publish document '%s|%s': %s", d.GetId().GetSystem(), d.GetId().GetValue(), err)
+		return nil, err
 	}
 	cavID := cavIDs[0] // use the first found identifier - underlying service should handle the issue of merged identifiers
 	// check that this CRN is correct by fetching against live PAS - basic sanity check in case wrong CRN
@@ -166,93 +285,93 @@ func (pms *PMSService) PublishDocument(ctx context.Context, r *apiv1.PublishDocu
 		log.Printf("cav: pas    : %s", protojson.MarshalOptions{}.Format(pt))
 		return nil, errors.New("unable to publish document: patient demographics don't match that in PAS")
 	}
-	var uid string // our unique identifier is made up of system|value unless system==uuid, in which case just a value
-	if d.GetId().GetSystem() == identifiers.UUID {
-		uid = d.GetId().GetValue()
-	} else {
-		uid = d.GetId().GetSystem() + "|" + d.GetId().GetValue()
+	uid := bfsID(d.GetId())
+	if original := originalVersion(r.GetPreviousVersions()); original != nil {
+		uid = bfsID(original) // re-publish using the identifier of the original, so that the PMS replaces it
+		log.Printf("cav: publishing document '%s|%s' as replacement for '%s'", d.GetId().GetSystem(), d.GetId().GetValue(), uid)
 	}
-	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
-	defer cancelFunc()
-	docID, err := performReceiveFileByCRN(ctx, cavID.GetValue(), uid, "GENERAL LETTER", d.GetTitle(), d.GetData().GetData())
-	if err != nil {
-		return nil, err
+	var docID string
+	if pms.fake {
+		docID = fakePublish(cavID, uid, d)
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		if docID, err = pms.performReceiveFileByCRN(ctx, cavID.GetValue(), uid, key, documentSource(d), fileType, d.GetData().GetData()); err != nil {
+			return nil, err
+		}
 	}
+	pms.published.SetDefault(docID, uid)
 	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.CardiffAndValeDocID, Value: docID}}, nil
 }
 
+// pmsDateSeparators are removed from CAV PMS dates and datetimes, to give HL7 dates and timestamps
+var pmsDateSeparators = strings.NewReplacer("/", "", " ", "", ":", "")
+
 // parseDate parses a CAV PMS date - format is "yyyy/MM/dd"
-func parseDate(d string) (*timestamp.Timestamp, error) {
-	if len(d) == 0 {
-		return nil, nil
-	}
-	layout := "2006/01/02" // reference date is : Mon Jan 2 15:04:05 MST 2006
-	t, err := time.Parse(layout, d)
-	if err != nil || t.IsZero() {
+func parseDate(d string) (*timestamppb.Timestamp, error) {
+	return parsePMSDate(d, hl7time.Day)
+}
+
+// parseBirthDate parses a CAV PMS date of birth - format is "yyyy/MM/dd", returning nil if empty
+func parseBirthDate(d string) (*apiv1.Date, error) {
+	t, err := parsePMSTime(d, hl7time.Day)
+	if err != nil {
 		return nil, err
 	}
-	return ptypes.TimestampProto(t)
+	return apiv1.NewDate(t.Time), nil
 }
 
-// parseDate parses a CAV PMS datetime - format is "yyyy/MM/dd hh:mm:ss"
-func parseDateTime(d string) (*timestamp.Timestamp, error) {
-	if len(d) == 0 {
-		return nil, nil
-	}
-	layout := "2006/01/02 15:04:05" // reference date is : Mon Jan 2 15:04:05 MST 2006
-	t, err := time.Parse(layout, d)
-	if err != nil || t.IsZero() {
+// parseDateTime parses a CAV PMS datetime - format is "yyyy/MM/dd hh:mm:ss"
+func parseDateTime(d string) (*timestamppb.Timestamp, error) {
+	return parsePMSDate(d, hl7time.Second)
+}
+
+// parsePMSDate parses a CAV PMS date or datetime, which must be of the precision specified, returning nil if empty
+func parsePMSDate(d string, precision hl7time.Precision) (*timestamppb.Timestamp, error) {
+	t, err := parsePMSTime(d, precision)
+	if err != nil {
 		return nil, err
 	}
-	return ptypes.TimestampProto(t)
+	return t.Timestamp(), nil
 }
 
-// authenticationToken (lazily) returns a valid authentication token
-func (pms *PMSService) authenticationToken(ctx context.Context) (string, error) {
-	pms.tokenMu.Lock()
-	defer pms.tokenMu.Unlock()
-	now := time.Now()
-	if pms.token != "" && now.Before(pms.tokenExpires) {
-		log.Printf("cavpms: using cached authentication token, expires %s", pms.tokenExpires)
-		return pms.token, nil
-	}
-	token, err := authenticate(ctx, pms.username, pms.password)
+func parsePMSTime(d string, precision hl7time.Precision) (hl7time.Time, error) {
+	t, err := hl7time.Parse(pmsDateSeparators.Replace(d))
 	if err != nil {
-		return "", err
+		return hl7time.Time{}, err
+	}
+	if t.Precision != precision && t.Precision != hl7time.None {
+		return hl7time.Time{}, fmt.Errorf("cav: invalid date: '%s': expected precision %s, got %s", d, precision, t.Precision)
 	}
-	pms.token = token
-	pms.tokenExpires = now.Add(10 * time.Minute)
-	log.Printf("cavpms: obtained new authentication token, expires %s", pms.tokenExpires)
-	return token, nil
+	return t, nil
 }
 
-// Authenticate authenticates against CAV PMS, returning an authentication token
-func authenticate(ctx context.Context, username string, password string) (string, error) {
-	lr := &loginRequest{Username: username, Password: password, Database: "vpmslive.world", UserString: "concierge"}
+// authenticate logs in to the PMS, returning a new authentication token
+func (pms *PMSService) authenticate(ctx context.Context) (token Token, err error) {
+	defer metrics.Observe("cav", "login", time.Now(), &err)
+	s := pms.sessionFor(ctx)
+	lr := &loginRequest{Username: s.username, Password: s.password.Value(), Database: pms.env.Database, UserString: pms.env.UserString}
 	lrs, err := createLoginRequestXML(lr)
 	if err != nil {
-		return "", err
+		return Token{}, err
 	}
 	var loginResponse GetDataResponse
-	if err := performGetData(ctx, lrs, &loginResponse); err != nil {
-		return "", err
+	if err := pms.performGetData(ctx, lrs, &loginResponse); err != nil {
+		return Token{}, err
 	}
-	success := loginResponse.Method.Summary.Success
-	if success == "true" && loginResponse.Method.Summary.Rowcount == "1" {
-		token := loginResponse.Method.Row[0].Column[0].Value
-		return token, nil
-	}
-	log.Printf("cavpms login error: %s", loginResponse.Method.Message)
-	return "", status.Error(codes.PermissionDenied, "Could not login to CAV PMS")
+	return parseLoginResponse(&loginResponse, time.Now())
 }
 
-func performSQL(ctx context.Context, token string, sql string) ([]map[string]string, error) {
+func (pms *PMSService) performSQL(ctx context.Context, token string, sql string) ([]map[string]string, error) {
 	sqlXML, err := createSQLRequestXML(token, sql)
 	if err != nil {
 		return nil, err
 	}
 	var sqlResponse GetDataResponse
-	if err := performGetData(ctx, sqlXML, &sqlResponse); err != nil {
+	if err := pms.performGetData(ctx, sqlXML, &sqlResponse); err != nil {
 		return nil, err
 	}
 	success := sqlResponse.Method.Summary.Success
@@ -278,20 +397,19 @@ func performSQL(ctx context.Context, token string, sql string) ([]map[string]str
 
 // performGetData performs a "GetData" operation on the underlying CAV PMS service, which acts
 // as a transport for the actual operation, codified within the xmlData
-func performGetData(ctx context.Context, xmlData string, result interface{}) error {
+func (pms *PMSService) performGetData(ctx context.Context, xmlData string, result interface{}) error {
 	data := &url.Values{
 		"XmlDataBlockIn": []string{xmlData},
 	}
-	endpointURL := "http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx/GetData"
+	endpointURL := pms.env.URL + "/GetData"
 	return performRequest(ctx, endpointURL, data.Encode(), result)
 }
 
 // this uses a SOAP call, because the HTTP POST failed to work with base64 encoding for some reason
-func performReceiveFileByCRN(ctx context.Context, crn string, uid string, key string, source string, pdfData []byte) (string, error) {
-	service := soap.NewPMSInterfaceWebServiceSoap("http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx", false, nil)
-	fileType := ".pdf"
-	data := []byte(base64.StdEncoding.EncodeToString(pdfData))
-	response, err := service.ReceiveFileByCrn(&soap.ReceiveFileByCrn{
+func (pms *PMSService) performReceiveFileByCRN(ctx context.Context, crn string, uid string, key string, source string, fileType string, fileData []byte) (string, error) {
+	service := soap.NewPMSInterfaceWebServiceSoap(pms.env.URL, nil)
+	data := []byte(base64.StdEncoding.EncodeToString(fileData))
+	response, err := service.ReceiveFileByCrnContext(ctx, &soap.ReceiveFileByCrn{
 		BfsId:       uid, // unfortunately, this must be 15 digits or less
 		Crn:         crn,
 		Key:         key,
@@ -301,7 +419,7 @@ func performReceiveFileByCRN(ctx context.Context, crn string, uid string, key st
 	})
 	if err != nil {
 		log.Printf("cav: publish document error: %s", err)
-		return "", err
+		return "", status.Errorf(codes.Unavailable, "CAV PMS error: %s", err)
 	}
 	if len(response.ErrorMessage) > 0 {
 		return "", fmt.Errorf("error publishing document: %s", response.ErrorMessage)
@@ -313,11 +431,11 @@ func performReceiveFileByCRN(ctx context.Context, crn string, uid string, key st
 			"bfsId":       []string{uid},                                        // unique document identifier
 			"key":         []string{key},                                        // agreed code word - e.g. "GENERAL LETTER"
 			"source":      []string{source},                                     // description of document
-			"fileContent": []string{base64.StdEncoding.EncodeToString(pdfData)}, // the file data
-			"fileType":    []string{".pdf"},                                     // filetype, but an extension, not mimetype
+			"fileContent": []string{base64.StdEncoding.EncodeToString(fileData)}, // the file data
+			"fileType":    []string{fileType},                                    // filetype, but an extension, not mimetype
 		}
 		post := fmt.Sprintf("%s", data.Encode())
-		endpointURL := "http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx/ReceiveFileByCrn"
+		endpointURL := pms.env.URL + "/ReceiveFileByCrn"
 		response := new(AcknowledgementResponse)
 		if err := performRequest(ctx, endpointURL, post, &response); err != nil {
 			return "", err
@@ -336,11 +454,11 @@ func performRequest(ctx context.Context, endpointURL string, post string, result
 		return err
 	}
 	req.Header.Set("Content-type", "application/x-www-form-urlencoded")
-	client := &http.Client{}
+	client := transport.NewClient("cav-pms", nil, true)
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Printf("cav: request error. client.do: %s", err)
-		return err
+		return status.Errorf(codes.Unavailable, "CAV PMS unavailable: %s", err)
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
@@ -350,7 +468,7 @@ func performRequest(ctx context.Context, endpointURL string, post string, result
 	if resp.StatusCode != 200 {
 		log.Printf("cav: received error response: %+v", resp)
 		log.Printf("body: %v", string(body))
-		return errors.New("remote service error")
+		return status.Errorf(codes.Unavailable, "CAV PMS remote service error (%d)", resp.StatusCode)
 	}
 	return xml.Unmarshal(body, result)
 }
@@ -455,22 +573,14 @@ func parseCRN(crn string) (*pmsCRN, error) {
 }
 
 func createSQLFetchPatientByCRN(crn string) (string, error) {
-	params, err := parseCRN(crn)
+	id, err := parseCRN(crn)
 	if err != nil {
 		return "", err
 	}
-	t, err := template.New("sql-patient-by-crn").Parse(sqlFetchPatientByCRN)
-	if err != nil {
-		return "", err
-	}
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, params); err != nil {
-		return "", err
-	}
-	return string(buf.Bytes()), nil
+	return sqlFetchPatientByCRN.build(params{"type": id.Type, "crn": id.CRN})
 }
 
-var sqlFetchPatientByCRN = `SELECT People.ID, NHS_NO AS NHS_NUMBER, 
+var sqlFetchPatientByCRN = mustQuery("patient-by-crn", `SELECT People.ID, NHS_NO AS NHS_NUMBER, 
 to_char(DATE_LAST_CHANGED, 'yyyy/mm/dd hh:mi:ss') as DATE_LAST_MODIFIED,
 PATIENT_IDENTIFIERS.PAID_TYPE || PATIENT_IDENTIFIERS.ID as HOSPITAL_ID, 
 TITLE, People.SURNAME AS LAST_NAME, People.FIRST_FORENAME, People.SECOND_FORENAME, OTHER_FORENAMES, 
@@ -483,15 +593,15 @@ COUNTRY_OF_BIRTH, ETHNIC_ORIGIN, MARITAL_STATUS, OCCUPATION, PLACE_OF_BIRTH, PLA
 HEALTHCARE_PRACTITIONERS.national_no AS GP_ID, 
 EXTERNAL_ORGANISATIONS.national_no AS GPPR_ID
 FROM	EXTERNAL_ORGANISATIONS, HEALTHCARE_PRACTITIONERS, LOCATIONS, PEOPLE, PATIENT_IDENTIFIERS
-WHERE	PATIENT_IDENTIFIERS.PAID_TYPE = '{{.Type}}'
-AND PATIENT_IDENTIFIERS.ID = '{{.CRN}}'
+WHERE	PATIENT_IDENTIFIERS.PAID_TYPE = :type
+AND PATIENT_IDENTIFIERS.ID = :crn
 AND PATIENT_IDENTIFIERS.CRN = 'Y'
 AND PATIENT_IDENTIFIERS.MAJOR_FLAG = 'Y'
 AND PEOPLE.ID = PATIENT_IDENTIFIERS.PATI_ID
 AND LOCATIONS.ORGA_PERS_ID (+) = PEOPLE.ID
 AND HEALTHCARE_PRACTITIONERS.PERS_ID (+) = PEOPLE.GP_ID
 AND EXTERNAL_ORGANISATIONS.ID (+) = PEOPLE.GPPR_ID
-ORDER BY LOCATIONS.DATE_FROM DESC`
+ORDER BY LOCATIONS.DATE_FROM DESC`)
 
 func parsePatientAndAddresses(rows []map[string]string) (*apiv1.Patient, error) {
 	if len(rows) == 0 {
@@ -507,7 +617,7 @@ func parsePatientAndAddresses(rows []map[string]string) (*apiv1.Patient, error)
 		address.Address1 = row["ADDRESS1"]
 		address.Address2 = row["ADDRESS2"]
 		address.Address3 = row["ADDRESS3"]
-		address.Country = row["ADDRESS4"]
+		address.County = row["ADDRESS4"]
 		address.Postcode = row["POSTCODE"]
 		from, _ := parseDate(row["DATE_FROM"])
 		to, _ := parseDate(row["DATE_TO"])
@@ -541,7 +651,7 @@ func parsePatient(row map[string]string) (*apiv1.Patient, error) {
 		pt.Gender = apiv1.Gender_UNKNOWN
 	}
 	var err error
-	pt.BirthDate, err = parseDate(row["DATE_BIRTH"])
+	pt.BirthDate, err = parseBirthDate(row["DATE_BIRTH"])
 	if err != nil {
 		return nil, err
 	}
@@ -560,38 +670,25 @@ func parsePatient(row map[string]string) (*apiv1.Patient, error) {
 	pt.Title = row["TITLE"]
 	pt.Telephones = make([]*apiv1.Telephone, 0)
 	if tel := row["HOME_PHONE_NO"]; len(tel) > 0 {
-		pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: tel, Description: "Home"})
+		pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: tel, Description: "Home", Use: apiv1.Telephone_HOME})
 	}
 	if tel := row["WORK_PHONE_NO"]; len(tel) > 0 {
-		pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: tel, Description: "Work"})
-	}
-	pt.GeneralPractitioner = row["GP_ID"]
-	pt.Surgery = row["GPPR_ID"]
+		pt.Telephones = append(pt.Telephones, &apiv1.Telephone{Number: tel, Description: "Work", Use: apiv1.Telephone_WORK})
+	}
+	pt.GeneralPractitioner = identifiers.New(identifiers.GMPNumber, row["GP_ID"])
+	pt.Surgery = identifiers.New(identifiers.ODSCode, row["GPPR_ID"])
+	pt.EthnicCategory = parseEthnicCategory(row["ETHNIC_ORIGIN"])
+	pt.MaritalStatus = parseMaritalStatus(row["MARITAL_STATUS"])
+	pt.Occupation = strings.TrimSpace(row["OCCUPATION"])
+	pt.CountryOfBirth = strings.TrimSpace(row["COUNTRY_OF_BIRTH"])
 	return pt, nil
 }
 
-type patientsForClinic struct {
-	ClinicCode string
-	DateString string
-}
-
 func createSQLFetchPatientsForClinic(clinicCode string, date time.Time) (string, error) {
-	params := &patientsForClinic{
-		ClinicCode: clinicCode,
-		DateString: date.Format("2006/01/02"),
-	}
-	t, err := template.New("sql-patients-for-clinic").Parse(sqlFetchPatientsForClinic)
-	if err != nil {
-		return "", err
-	}
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, params); err != nil {
-		return "", err
-	}
-	return string(buf.Bytes()), nil
+	return sqlFetchPatientsForClinic.build(params{"clinic": clinicCode, "date": date})
 }
 
-var sqlFetchPatientsForClinic = `SELECT People.ID, NHS_NO AS NHS_NUMBER,
+var sqlFetchPatientsForClinic = mustQuery("patients-for-clinic", `SELECT People.ID, NHS_NO AS NHS_NUMBER,
 to_char(DATE_LAST_CHANGED, 'yyyy/mm/dd hh:mi:ss') as
 DATE_LAST_MODIFIED,
 PATIENT_IDENTIFIERS.PAID_TYPE ||
@@ -614,9 +711,9 @@ FROM EXTERNAL_ORGANISATIONS,
 HEALTHCARE_PRACTITIONERS, LOCATIONS, PEOPLE,
 PATIENT_IDENTIFIERS, BOOKED_SLOTS, ACT_CLIN_SESSIONS,
 OUTPATIENT_CLINICS
-WHERE OUTPATIENT_CLINICS.SHORTNAME = '{{.ClinicCode}}'
+WHERE OUTPATIENT_CLINICS.SHORTNAME = :clinic
 AND ACT_CLIN_SESSIONS.OUCL_ID = OUTPATIENT_CLINICS.OUCL_ID
-AND ACT_CLIN_SESSIONS.SESSION_DATE = To_Date('{{.DateString}}', 'yyyy/mm/dd')
+AND ACT_CLIN_SESSIONS.SESSION_DATE = :date
 AND ACT_CLIN_SESSIONS.DATE_CANCD IS NULL
 AND BOOKED_SLOTS.ACS_ID = ACT_CLIN_SESSIONS.ACS_ID
 AND PATIENT_IDENTIFIERS.PATI_ID = BOOKED_SLOTS.PATI_ID
@@ -627,4 +724,4 @@ AND LOCATIONS.ORGA_PERS_ID (+) = PEOPLE.ID
 AND LOCATIONS.DATE_TO (+) IS NULL
 AND HEALTHCARE_PRACTITIONERS.PERS_ID (+) = PEOPLE.GP_ID
 AND EXTERNAL_ORGANISATIONS.ID (+) = PEOPLE.GPPR_ID
-ORDER BY LAST_NAME`
+ORDER BY LAST_NAME, People.FIRST_FORENAME, People.ID`)
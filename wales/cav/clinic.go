@@ -0,0 +1,372 @@
+package cav
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/paging"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// GetClinicSchedule returns the appointments, including free slots, for a single clinic on a single date
+func (pms *PMSService) GetClinicSchedule(ctx context.Context, r *apiv1.ClinicScheduleRequest) (schedule *apiv1.ClinicSchedule, err error) {
+	defer metrics.Observe("cav", "schedule", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "cav.schedule")
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	if r.GetClinic().GetSystem() != identifiers.CardiffAndValeClinicCode {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to fetch clinic schedule: incorrect 'system'. expected: '%s' got:'%s'", identifiers.CardiffAndValeClinicCode, r.GetClinic().GetSystem())
+	}
+	if r.GetClinic().GetValue() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to fetch clinic schedule: no clinic code specified")
+	}
+	page, err := paging.Parse(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	date := time.Now()
+	if r.GetDate() != "" {
+		if date, err = time.ParseInLocation("2006-01-02", r.GetDate(), time.Local); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date '%s': expected YYYY-MM-DD", r.GetDate())
+		}
+	}
+	schedule = &apiv1.ClinicSchedule{Clinic: r.GetClinic(), Date: date.Format("2006-01-02")}
+	var rows []map[string]string
+	if pms.fake {
+		rows = fakeSchedule(r.GetClinic().GetValue(), date)
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		token, err := pms.authenticationToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sql, err := createSQLFetchClinicSchedule(r.GetClinic().GetValue(), date)
+		if err != nil {
+			return nil, err
+		}
+		if rows, err = pms.performSQL(ctx, token, sql); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range rows { // rows are ordered by start time
+		appt, err := parseAppointment(row)
+		if err != nil {
+			log.Printf("cav: failed to parse appointment %s: %s", row["SLOT_ID"], err)
+			continue
+		}
+		appt.Clinic = r.GetClinic()
+		schedule.Appointments = append(schedule.Appointments, appt)
+	}
+	n := len(schedule.Appointments)
+	start, end := page.Bounds(n)
+	schedule.Appointments, schedule.NextPageToken = schedule.Appointments[start:end], page.NextToken(n)
+	return schedule, nil
+}
+
+// GetClinic returns the details of a clinic, such as its description, location, specialty and consultant
+func (pms *PMSService) GetClinic(ctx context.Context, id *apiv1.Identifier) (clinic *apiv1.Clinic, err error) {
+	defer metrics.Observe("cav", "clinic", time.Now(), &err)
+	if id.GetSystem() != identifiers.CardiffAndValeClinicCode {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to fetch clinic: incorrect 'system'. expected: '%s' got:'%s'", identifiers.CardiffAndValeClinicCode, id.GetSystem())
+	}
+	if !clinicCode.MatchString(id.GetValue()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid clinic code: '%s'", id.GetValue())
+	}
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	var rows []map[string]string
+	if pms.fake {
+		rows = fakeClinic(id.GetValue())
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		token, err := pms.authenticationToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sql, err := sqlFetchClinic.build(params{"clinic": strings.ToUpper(id.GetValue())})
+		if err != nil {
+			return nil, err
+		}
+		if rows, err = pms.performSQL(ctx, token, sql); err != nil {
+			return nil, err
+		}
+	}
+	if len(rows) == 0 {
+		return nil, status.Errorf(codes.NotFound, "clinic not found: %s", id.GetValue())
+	}
+	return parseClinic(rows[0]), nil
+}
+
+// ResolveClinic provides identifier resolution for clinic codes (see identifiers.CardiffAndValeClinicCode)
+func (pms *PMSService) ResolveClinic(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return pms.GetClinic(ctx, id)
+}
+
+// clinicCode is the format of a clinic's short name
+var clinicCode = regexp.MustCompile(`^[A-Za-z0-9]{1,12}$`)
+
+// parseClinic parses a row returned by sqlFetchClinic
+func parseClinic(row map[string]string) *apiv1.Clinic {
+	clinic := &apiv1.Clinic{
+		Id:          &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: row["SHORTNAME"]},
+		Description: row["DESCRIPTION"],
+		Location:    row["LOCATION"],
+		Specialty:   identifiers.New(identifiers.NHSSpecialty, row["SPECIALTY"]),
+		Active:      row["DATE_TO"] == "",
+	}
+	if row["HCP_SURNAME"] != "" {
+		clinic.Consultant = parseClinician(row)
+	}
+	return clinic
+}
+
+// consultantCode is the format of a consultant's national code, which is their GMC number prefixed by 'C'
+var consultantCode = regexp.MustCompile(`^C(\d{7})$`)
+
+// parseAppointment parses an appointment from a row returned by sqlFetchClinicSchedule
+func parseAppointment(row map[string]string) (*apiv1.Appointment, error) {
+	appt := &apiv1.Appointment{
+		Id:              &apiv1.Identifier{System: identifiers.CardiffAndValeAppointmentID, Value: row["SLOT_ID"]},
+		AppointmentType: row["VISIT_TYPE"],
+	}
+	var err error
+	if appt.Start, err = parseDateTime(row["START_TIME"]); err != nil {
+		return nil, err
+	}
+	if appt.End, err = parseDateTime(row["END_TIME"]); err != nil {
+		return nil, err
+	}
+	switch {
+	case row["HOSPITAL_ID"] == "":
+		appt.Status = apiv1.Appointment_FREE
+	case row["DATE_CANCD"] != "":
+		appt.Status = apiv1.Appointment_CANCELLED
+	case row["ATTENDED"] == "Y":
+		appt.Status = apiv1.Appointment_ATTENDED
+	case row["ATTENDED"] == "N":
+		appt.Status = apiv1.Appointment_DNA
+	default:
+		appt.Status = apiv1.Appointment_BOOKED
+	}
+	if appt.Status != apiv1.Appointment_FREE {
+		if appt.Patient, err = parsePatient(row); err != nil {
+			return nil, err
+		}
+	}
+	if row["HCP_SURNAME"] != "" {
+		appt.Clinician = parseClinician(row)
+	}
+	return appt, nil
+}
+
+// parseClinician parses the clinician responsible for a clinic from a row
+func parseClinician(row map[string]string) *apiv1.Practitioner {
+	p := &apiv1.Practitioner{
+		Active: true,
+		Names: []*apiv1.HumanName{{
+			Family:   row["HCP_SURNAME"],
+			Given:    row["HCP_FORENAME"],
+			Prefixes: []string{row["HCP_TITLE"]},
+			Use:      apiv1.HumanName_OFFICIAL,
+		}},
+	}
+	if m := consultantCode.FindStringSubmatch(row["HCP_ID"]); m != nil {
+		p.Identifiers = []*apiv1.Identifier{{System: identifiers.GMCNumber, Value: m[1]}}
+	}
+	return p
+}
+
+func createSQLFetchClinicSchedule(clinicCode string, date time.Time) (string, error) {
+	return sqlFetchClinicSchedule.build(params{"clinic": clinicCode, "date": date})
+}
+
+// sqlFetchClinicSchedule fetches all slots for the clinic sessions on a date, with details of the
+// patient for booked slots, and of the clinician responsible for the session.
+var sqlFetchClinicSchedule = mustQuery("clinic-schedule", `SELECT BOOKED_SLOTS.ID AS SLOT_ID,
+to_char(BOOKED_SLOTS.START_TIME, 'yyyy/mm/dd hh24:mi:ss') AS START_TIME,
+to_char(BOOKED_SLOTS.END_TIME, 'yyyy/mm/dd hh24:mi:ss') AS END_TIME,
+BOOKED_SLOTS.VISIT_TYPE, BOOKED_SLOTS.ATTENDED,
+to_char(BOOKED_SLOTS.DATE_CANCD, 'yyyy/mm/dd') AS DATE_CANCD,
+CONSULTANTS.national_no AS HCP_ID, CONSULTANTS.TITLE AS HCP_TITLE,
+CONSULTANTS.SURNAME AS HCP_SURNAME, CONSULTANTS.FORENAME AS HCP_FORENAME,
+People.ID, NHS_NO AS NHS_NUMBER,
+PATIENT_IDENTIFIERS.PAID_TYPE ||
+PATIENT_IDENTIFIERS.ID as HOSPITAL_ID,
+People.TITLE, People.SURNAME AS LAST_NAME,
+People.FIRST_FORENAME, People.SECOND_FORENAME, OTHER_FORENAMES,
+SEX,
+to_char(DOB,'yyyy/mm/dd') AS DATE_BIRTH,
+to_char(DOD,'yyyy/mm/dd') AS DATE_DEATH,
+HOME_PHONE_NO, WORK_PHONE_NO,
+HEALTHCARE_PRACTITIONERS.national_no AS GP_ID,
+EXTERNAL_ORGANISATIONS.national_no AS GPPR_ID
+FROM OUTPATIENT_CLINICS, ACT_CLIN_SESSIONS, BOOKED_SLOTS,
+HEALTHCARE_PRACTITIONERS CONSULTANTS,
+PATIENT_IDENTIFIERS, PEOPLE,
+HEALTHCARE_PRACTITIONERS, EXTERNAL_ORGANISATIONS
+WHERE OUTPATIENT_CLINICS.SHORTNAME = :clinic
+AND ACT_CLIN_SESSIONS.OUCL_ID = OUTPATIENT_CLINICS.OUCL_ID
+AND ACT_CLIN_SESSIONS.SESSION_DATE = :date
+AND ACT_CLIN_SESSIONS.DATE_CANCD IS NULL
+AND BOOKED_SLOTS.ACS_ID = ACT_CLIN_SESSIONS.ACS_ID
+AND CONSULTANTS.PERS_ID (+) = ACT_CLIN_SESSIONS.HCP_ID
+AND PATIENT_IDENTIFIERS.PATI_ID (+) = BOOKED_SLOTS.PATI_ID
+AND PATIENT_IDENTIFIERS.CRN (+) = 'Y'
+AND PATIENT_IDENTIFIERS.MAJOR_FLAG (+) = 'Y'
+AND PEOPLE.ID (+) = BOOKED_SLOTS.PATI_ID
+AND HEALTHCARE_PRACTITIONERS.PERS_ID (+) = PEOPLE.GP_ID
+AND EXTERNAL_ORGANISATIONS.ID (+) = PEOPLE.GPPR_ID
+ORDER BY BOOKED_SLOTS.START_TIME, BOOKED_SLOTS.ID`)
+
+// sqlFetchClinic fetches the details of a clinic, with its specialty, location and responsible consultant
+var sqlFetchClinic = mustQuery("clinic", `SELECT OUTPATIENT_CLINICS.SHORTNAME,
+OUTPATIENT_CLINICS.DESCRIPTION,
+to_char(OUTPATIENT_CLINICS.DATE_TO, 'yyyy/mm/dd') AS DATE_TO,
+SPECIALTIES.NATIONAL_CODE AS SPECIALTY,
+SERVICE_POINTS.DESCRIPTION AS LOCATION,
+CONSULTANTS.national_no AS HCP_ID, CONSULTANTS.TITLE AS HCP_TITLE,
+CONSULTANTS.SURNAME AS HCP_SURNAME, CONSULTANTS.FORENAME AS HCP_FORENAME
+FROM OUTPATIENT_CLINICS, SPECIALTIES, SERVICE_POINTS,
+HEALTHCARE_PRACTITIONERS CONSULTANTS
+WHERE UPPER(OUTPATIENT_CLINICS.SHORTNAME) = :clinic
+AND SPECIALTIES.SPEC_ID (+) = OUTPATIENT_CLINICS.SPEC_ID
+AND SERVICE_POINTS.SPONT_ID (+) = OUTPATIENT_CLINICS.SPONT_ID
+AND CONSULTANTS.PERS_ID (+) = OUTPATIENT_CLINICS.HCP_ID`)
+
+// fakeClinic returns a row for a clinic in the simulator, useful in testing without a live backend service.
+func fakeClinic(clinicCode string) []map[string]string {
+	clinic, found := simulator.Current().Clinic(clinicCode)
+	if !found {
+		return nil
+	}
+	row := map[string]string{"SHORTNAME": clinic.Code, "DESCRIPTION": clinic.Name, "LOCATION": clinic.Location, "SPECIALTY": clinic.Specialty}
+	for k, v := range fakeClinician(clinic.Clinician) {
+		row[k] = v
+	}
+	return []map[string]string{row}
+}
+
+// fakeClinician returns the columns for the clinician responsible for a clinic in the simulator
+func fakeClinician(c *simulator.Practitioner) map[string]string {
+	if c == nil {
+		return nil
+	}
+	row := map[string]string{"HCP_TITLE": c.Title, "HCP_SURNAME": c.LastName, "HCP_FORENAME": c.FirstNames}
+	if c.GMC != "" {
+		row["HCP_ID"] = "C" + c.GMC
+	}
+	return row
+}
+
+// fakeSchedule returns rows for a clinic in the simulator, useful in testing without a live backend service.
+// The simulator's clinics have the same slots every day.
+func fakeSchedule(clinicCode string, date time.Time) []map[string]string {
+	ds := simulator.Current()
+	clinic, found := ds.Clinic(clinicCode)
+	if !found {
+		return nil
+	}
+	consultant := fakeClinician(clinic.Clinician)
+	rows := make([]map[string]string, 0, len(clinic.Slots))
+	for i, slot := range clinic.Slots {
+		row := map[string]string{
+			"SLOT_ID":    clinicCode + "-" + strconv.Itoa(i+1),
+			"START_TIME": date.Format("2006/01/02") + " " + slot.Start + ":00",
+			"END_TIME":   date.Format("2006/01/02") + " " + slot.End + ":00",
+			"VISIT_TYPE": slot.VisitType,
+		}
+		for k, v := range consultant {
+			row[k] = v
+		}
+		if slot.Patient != "" {
+			if pt, found := ds.Patient(simulator.CAV, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: slot.Patient}); found {
+				for k, v := range patientRow(pt) {
+					row[k] = v
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// fakePatientsForClinics returns the patients booked into clinics in the simulator, useful in testing without
+// a live backend service.
+func fakePatientsForClinics(date time.Time, clinics []*apiv1.Identifier) []*apiv1.Patient {
+	result := make([]*apiv1.Patient, 0)
+	for _, clinic := range clinics {
+		for _, row := range fakeSchedule(clinic.GetValue(), date) {
+			if row["HOSPITAL_ID"] == "" {
+				continue
+			}
+			if pt, err := parsePatient(row); err == nil {
+				result = append(result, pt)
+			}
+		}
+	}
+	return result
+}
+
+// patientRow returns the patient as the columns of a row returned by CAV PMS
+func patientRow(pt *apiv1.Patient) map[string]string {
+	row := map[string]string{
+		"TITLE":            pt.GetTitle(),
+		"LAST_NAME":        pt.GetLastname(),
+		"FIRST_FORENAME":   pt.GetFirstnames(),
+		"GP_ID":            pt.GetGeneralPractitioner().GetValue(),
+		"GPPR_ID":          pt.GetSurgery().GetValue(),
+		"ETHNIC_ORIGIN":    pt.GetEthnicCategory().GetValue(),
+		"OCCUPATION":       pt.GetOccupation(),
+		"COUNTRY_OF_BIRTH": pt.GetCountryOfBirth(),
+	}
+	for code, v3 := range maritalStatuses {
+		if v3 == pt.GetMaritalStatus().GetValue() && v3 != "UNK" {
+			row["MARITAL_STATUS"] = code
+		}
+	}
+	switch pt.GetGender() {
+	case apiv1.Gender_MALE:
+		row["SEX"] = "M"
+	case apiv1.Gender_FEMALE:
+		row["SEX"] = "F"
+	}
+	if dob := pt.GetBirthDate().Format("2006/01/02"); dob != "" {
+		row["DATE_BIRTH"] = dob
+	}
+	if pt.GetDeceasedDate().CheckValid() == nil {
+		row["DATE_DEATH"] = pt.GetDeceasedDate().AsTime().Format("2006/01/02")
+	}
+	for _, id := range pt.GetIdentifiers() {
+		switch id.GetSystem() {
+		case identifiers.CardiffAndValeCRN:
+			row["HOSPITAL_ID"] = id.GetValue()
+		case identifiers.NHSNumber:
+			row["NHS_NUMBER"] = id.GetValue()
+		}
+	}
+	return row
+}
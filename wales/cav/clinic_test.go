@@ -0,0 +1,124 @@
+package cav
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClinicSchedule(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	clinic := &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR01"}
+	if _, err := pms.GetClinicSchedule(context.Background(), &apiv1.ClinicScheduleRequest{Clinic: clinic, Date: "01/06/2020"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for invalid date, got %v", err)
+	}
+	schedule, err := pms.GetClinicSchedule(context.Background(), &apiv1.ClinicScheduleRequest{Clinic: clinic, Date: "2020-06-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.GetDate() != "2020-06-01" || len(schedule.GetAppointments()) != 3 {
+		t.Fatalf("unexpected schedule: %v", schedule)
+	}
+	booked, free := schedule.GetAppointments()[0], schedule.GetAppointments()[1]
+	if booked.GetStatus() != apiv1.Appointment_BOOKED || booked.GetPatient().GetLastname() != "Dummy" || booked.GetAppointmentType() != "New" {
+		t.Errorf("unexpected booked appointment: %v", booked)
+	}
+	if gmc := booked.GetClinician().GetIdentifiers(); len(gmc) != 1 || gmc[0].GetSystem() != identifiers.GMCNumber || gmc[0].GetValue() != "4616734" {
+		t.Errorf("unexpected clinician: %v", booked.GetClinician())
+	}
+	if free.GetStatus() != apiv1.Appointment_FREE || free.GetPatient() != nil {
+		t.Errorf("unexpected free slot: %v", free)
+	}
+}
+
+func TestPatientsForClinics(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	clinics := []*apiv1.Identifier{{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR01"}}
+	pts, err := pms.PatientsForClinics(context.Background(), time.Now(), clinics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pts) == 0 || pts[0].GetLastname() != "Dummy" {
+		t.Fatalf("expected patients booked into clinic, got: %v", pts)
+	}
+}
+
+func TestAppointmentStatus(t *testing.T) {
+	tests := []struct {
+		row    map[string]string
+		status apiv1.Appointment_Status
+	}{
+		{map[string]string{"HOSPITAL_ID": "A999998", "ATTENDED": "Y"}, apiv1.Appointment_ATTENDED},
+		{map[string]string{"HOSPITAL_ID": "A999998", "ATTENDED": "N"}, apiv1.Appointment_DNA},
+		{map[string]string{"HOSPITAL_ID": "A999998", "ATTENDED": "N", "DATE_CANCD": "2020/05/01"}, apiv1.Appointment_CANCELLED},
+	}
+	for _, test := range tests {
+		appt, err := parseAppointment(test.row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if appt.GetStatus() != test.status {
+			t.Errorf("expected %s for %v, got %s", test.status, test.row, appt.GetStatus())
+		}
+	}
+}
+
+func TestClinicSchedulePaging(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	r := &apiv1.ClinicScheduleRequest{Clinic: &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR01"}, Date: "2020-06-01", PageSize: 2}
+	first, err := pms.GetClinicSchedule(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.GetAppointments()) != 2 || first.GetNextPageToken() == "" {
+		t.Fatalf("unexpected first page: %v", first)
+	}
+	r.PageToken = first.GetNextPageToken()
+	second, err := pms.GetClinicSchedule(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.GetAppointments()) != 1 || second.GetNextPageToken() != "" {
+		t.Fatalf("unexpected second page: %v", second)
+	}
+	r.Date = "2020-06-02"
+	if _, err := pms.GetClinicSchedule(context.Background(), r); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for token used with a different request, got %v", err)
+	}
+}
+
+func TestGetClinic(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	clinic, err := pms.GetClinic(context.Background(), &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "neur01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clinic.GetDescription() != "Neurology outpatients" || clinic.GetSpecialty().GetValue() != "400" || !clinic.GetActive() || clinic.GetConsultant().GetNames()[0].GetFamily() != "Wardle" {
+		t.Fatalf("unexpected clinic: %v", clinic)
+	}
+	if _, err := pms.GetClinic(context.Background(), &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR99"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	if _, err := pms.GetClinic(context.Background(), &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "' OR 1=1"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got: %v", err)
+	}
+}
+
+func TestGetCurrentAdmission(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	admission, err := pms.GetCurrentAdmission(context.Background(), &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999997"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admission.GetWard() != "C5" || admission.GetBed() != "12" || admission.GetAdmitted() == nil || admission.GetConsultant() == nil || admission.GetPatient().GetValue() != "A999997" {
+		t.Fatalf("unexpected admission: %v", admission)
+	}
+	if _, err := pms.GetCurrentAdmission(context.Background(), &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected patient not to be admitted, got: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package cav
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultDocumentKey is the document key used when publishing a document that does not specify one
+const DefaultDocumentKey = "GENERAL LETTER"
+
+// ContentType configures publication of a specific content type to CAV PMS.
+type ContentType struct {
+	FileType string            `yaml:"file_type" json:"file_type"`             // PMS fileType parameter, which is an extension rather than a MIME type e.g. ".pdf"
+	Keys     []string          `yaml:"keys,omitempty" json:"keys,omitempty"`   // document keys permitted for this content type; the first is the default
+	Types    map[string]string `yaml:"types,omitempty" json:"types,omitempty"` // SNOMED CT document types or specialties mapped to permitted document keys
+}
+
+// DefaultContentTypes returns the content types accepted by the CAV PMS repository, keyed by MIME type
+func DefaultContentTypes() map[string]*ContentType {
+	return map[string]*ContentType{
+		"application/pdf":         {FileType: ".pdf", Keys: []string{DefaultDocumentKey}},
+		"application/rtf":         {FileType: ".rtf", Keys: []string{DefaultDocumentKey}},
+		"text/rtf":                {FileType: ".rtf", Keys: []string{DefaultDocumentKey}},
+		"image/tiff":              {FileType: ".tif", Keys: []string{DefaultDocumentKey}},
+		"text/plain":              {FileType: ".txt", Keys: []string{DefaultDocumentKey}},
+		"application/hl7-cda+xml": {FileType: ".xml", Keys: []string{DefaultDocumentKey}},
+	}
+}
+
+// LoadContentTypes loads the configuration of content types from a YAML or JSON file, for example:
+//
+//	content_types:
+//	  application/pdf:
+//	    file_type: .pdf
+//	    keys: ["GENERAL LETTER", "CLINIC LETTER"]
+//	    types:
+//	      "371531000": CLINIC LETTER  # report of clinical encounter
+//	  image/tiff:
+//	    file_type: .tif
+//
+// Content types without keys permit only the default document key. Types map SNOMED CT concepts for
+// document types or specialties to one of the permitted keys.
+func LoadContentTypes(filename string) (map[string]*ContentType, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		ContentTypes map[string]*ContentType `yaml:"content_types" json:"content_types"`
+	}
+	if err := yaml.UnmarshalStrict(b, &config); err != nil { // YAML is a superset of JSON
+		return nil, fmt.Errorf("cav: invalid content types '%s': %w", filename, err)
+	}
+	if len(config.ContentTypes) == 0 {
+		return nil, fmt.Errorf("cav: no content types in '%s'", filename)
+	}
+	if err := validateContentTypes(config.ContentTypes); err != nil {
+		return nil, fmt.Errorf("cav: %w", err)
+	}
+	return config.ContentTypes, nil
+}
+
+// validateContentTypes checks the configuration of content types, normalising document keys
+func validateContentTypes(types map[string]*ContentType) error {
+	for name, ct := range types {
+		if ct == nil || !strings.HasPrefix(ct.FileType, ".") {
+			return fmt.Errorf("content type '%s': file_type must be an extension, such as '.pdf'", name)
+		}
+		if len(ct.Keys) == 0 {
+			ct.Keys = []string{DefaultDocumentKey}
+		}
+		for i, key := range ct.Keys {
+			ct.Keys[i] = strings.ToUpper(key)
+		}
+		for concept, key := range ct.Types {
+			key = strings.ToUpper(key)
+			if !ct.permits(key) {
+				return fmt.Errorf("content type '%s': document key '%s' for type '%s' not permitted", name, key, concept)
+			}
+			ct.Types[concept] = key
+		}
+	}
+	return nil
+}
+
+// SetContentTypes sets the content types that may be published, keyed by MIME type.
+// This should not be called once server is running.
+func (pms *PMSService) SetContentTypes(types map[string]*ContentType) {
+	pms.contentTypes = types
+}
+
+// permits returns whether the document key is permitted for this content type
+func (ct *ContentType) permits(key string) bool {
+	for _, k := range ct.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fileTypeAndKey returns the PMS file type and document key to be used to publish the document specified.
+// The document key is taken from the document type, if it is a CAV document key, and must be permitted
+// for the content type of the document. Otherwise, the key is that mapped from the SNOMED CT document type
+// or specialty, if any, or the default key for the content type.
+func (pms *PMSService) fileTypeAndKey(d *apiv1.Document) (string, string, error) {
+	contentType := d.GetData().GetContentType()
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mt
+	}
+	ct, ok := pms.contentTypes[contentType]
+	if !ok {
+		return "", "", status.Errorf(codes.InvalidArgument, "unable to publish document - unsupported content-type '%s'", d.GetData().GetContentType())
+	}
+	if d.GetType().GetSystem() != identifiers.CardiffAndValeDocumentKey {
+		for _, concept := range []*apiv1.Identifier{d.GetType(), d.GetSpecialty()} {
+			if key, ok := ct.Types[concept.GetValue()]; ok && concept.GetSystem() == identifiers.SNOMEDCT {
+				return ct.FileType, key, nil
+			}
+		}
+		return ct.FileType, ct.Keys[0], nil
+	}
+	key := strings.ToUpper(d.GetType().GetValue())
+	if ct.permits(key) {
+		return ct.FileType, key, nil
+	}
+	return "", "", status.Errorf(codes.InvalidArgument, "unable to publish document - document key '%s' not permitted for content-type '%s'", key, contentType)
+}
+
+// contentTypeForFileType returns the MIME type for a PMS file type. If more than one
+// content type uses the same file type, the first in alphabetical order is used.
+func (pms *PMSService) contentTypeForFileType(fileType string) string {
+	fileType = strings.ToLower(fileType)
+	names := make([]string, 0, len(pms.contentTypes))
+	for name, ct := range pms.contentTypes {
+		if ct.FileType == fileType {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names[0]
+	}
+	if contentType := mime.TypeByExtension(fileType); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
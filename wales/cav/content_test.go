@@ -0,0 +1,96 @@
+package cav
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testContentTypes = `
+content_types:
+  application/pdf:
+    file_type: .pdf
+    keys: ["general letter", "CLINIC LETTER", "NEUROLOGY LETTER"]
+    types:
+      "371531000": clinic letter
+      "394591006": NEUROLOGY LETTER
+  image/tiff:
+    file_type: .tif
+`
+
+func TestContentTypes(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	doc := func(contentType string, key string) *apiv1.Document {
+		d := &apiv1.Document{Data: &apiv1.Attachment{ContentType: contentType}}
+		if key != "" {
+			d.Type = &apiv1.Identifier{System: identifiers.CardiffAndValeDocumentKey, Value: key}
+		}
+		return d
+	}
+	tests := []struct {
+		doc      *apiv1.Document
+		fileType string
+		key      string
+		code     codes.Code
+	}{
+		{doc("application/pdf", ""), ".pdf", DefaultDocumentKey, codes.OK},
+		{doc("text/plain; charset=utf-8", ""), ".txt", DefaultDocumentKey, codes.OK},
+		{doc("application/hl7-cda+xml", ""), ".xml", DefaultDocumentKey, codes.OK},
+		{doc("application/msword", ""), "", "", codes.InvalidArgument},
+		{doc("image/tiff", "general letter"), ".tif", DefaultDocumentKey, codes.OK},
+		{doc("image/tiff", "CLINIC LETTER"), "", "", codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		fileType, key, err := pms.fileTypeAndKey(test.doc)
+		if status.Code(err) != test.code || fileType != test.fileType || key != test.key {
+			t.Errorf("%v: expected %s %s (%s), got %s %s (%v)", test.doc, test.fileType, test.key, test.code, fileType, key, err)
+		}
+	}
+	if ct := pms.contentTypeForFileType(".RTF"); ct != "application/rtf" {
+		t.Errorf("expected rtf content type, got %s", ct)
+	}
+
+	dir, err := ioutil.TempDir("", "cav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "content-types.yaml")
+	if err := ioutil.WriteFile(filename, []byte(testContentTypes), 0600); err != nil {
+		t.Fatal(err)
+	}
+	types, err := LoadContentTypes(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pms.SetContentTypes(types)
+	if _, key, err := pms.fileTypeAndKey(doc("application/pdf", "Clinic Letter")); err != nil || key != "CLINIC LETTER" {
+		t.Errorf("expected configured document key to be permitted, got %s (%v)", key, err)
+	}
+	if _, key, err := pms.fileTypeAndKey(doc("image/tiff", "")); err != nil || key != DefaultDocumentKey {
+		t.Errorf("expected default document key, got %s (%v)", key, err)
+	}
+	if _, _, err := pms.fileTypeAndKey(doc("text/plain", "")); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected unconfigured content type to be rejected, got %v", err)
+	}
+	encounter := doc("application/pdf", "")
+	encounter.Type = &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "371531000"}
+	encounter.Specialty = &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "394591006"}
+	if _, key, err := pms.fileTypeAndKey(encounter); err != nil || key != "CLINIC LETTER" {
+		t.Errorf("expected document key from document type, got %s (%v)", key, err)
+	}
+	encounter.Type = nil
+	if _, key, err := pms.fileTypeAndKey(encounter); err != nil || key != "NEUROLOGY LETTER" {
+		t.Errorf("expected document key from specialty, got %s (%v)", key, err)
+	}
+	if err := validateContentTypes(map[string]*ContentType{"application/pdf": {FileType: ".pdf", Types: map[string]string{"371531000": "CLINIC LETTER"}}}); err == nil {
+		t.Error("expected document key for type to be permitted for content type")
+	}
+}
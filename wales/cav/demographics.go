@@ -0,0 +1,44 @@
+package cav
+
+import (
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// ethnicCategories are the codes of the NHS data dictionary ethnic category, as used by CAV PMS,
+// which are the codes of the CareConnect-EthnicCategory-1 value set.
+var ethnicCategories = map[string]bool{
+	"A": true, "B": true, "C": true, "D": true, "E": true, "F": true, "G": true, "H": true, "J": true,
+	"K": true, "L": true, "M": true, "N": true, "P": true, "R": true, "S": true, "Z": true, "99": true,
+}
+
+// parseEthnicCategory parses the CAV PMS ethnic origin, returning nil if not recorded or not recognised
+func parseEthnicCategory(code string) *apiv1.Identifier {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !ethnicCategories[code] {
+		return nil
+	}
+	return &apiv1.Identifier{System: identifiers.CareConnectEthnicCategory, Value: code}
+}
+
+// maritalStatuses maps the NHS data dictionary marital status codes, as used by CAV PMS,
+// to the HL7 v3 marital status codes used by FHIR
+var maritalStatuses = map[string]string{
+	"S": "S",   // single
+	"M": "M",   // married / civil partner
+	"D": "D",   // divorced / dissolved civil partnership
+	"W": "W",   // widowed / surviving civil partner
+	"P": "L",   // separated
+	"N": "UNK", // not disclosed
+	"8": "UNK", // not known
+}
+
+// parseMaritalStatus parses the CAV PMS marital status, returning nil if not recorded or not recognised
+func parseMaritalStatus(code string) *apiv1.Identifier {
+	if v3, ok := maritalStatuses[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return &apiv1.Identifier{System: identifiers.MaritalStatus, Value: v3}
+	}
+	return nil
+}
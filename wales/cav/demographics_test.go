@@ -0,0 +1,67 @@
+package cav
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDemographics(t *testing.T) {
+	pt, err := parsePatient(map[string]string{"HOSPITAL_ID": "A999998", "LAST_NAME": "DUMMY", "ETHNIC_ORIGIN": "a ", "MARITAL_STATUS": "P", "OCCUPATION": "Teacher", "COUNTRY_OF_BIRTH": "Wales"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(pt.GetEthnicCategory(), &apiv1.Identifier{System: identifiers.CareConnectEthnicCategory, Value: "A"}) {
+		t.Errorf("unexpected ethnic category: %v", pt.GetEthnicCategory())
+	}
+	if !proto.Equal(pt.GetMaritalStatus(), &apiv1.Identifier{System: identifiers.MaritalStatus, Value: "L"}) {
+		t.Errorf("unexpected marital status: %v", pt.GetMaritalStatus())
+	}
+	if pt.GetOccupation() != "Teacher" || pt.GetCountryOfBirth() != "Wales" {
+		t.Errorf("unexpected occupation or country of birth: %v", pt)
+	}
+	if parseEthnicCategory("Q") != nil || parseMaritalStatus("") != nil {
+		t.Errorf("expected unrecognised codes to be ignored")
+	}
+}
+
+func TestResolveGeneralPractitioner(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	pt, err := pms.FetchPatient(context.Background(), "A999998")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := pms.ResolveGeneralPractitioner(context.Background(), pt.GetGeneralPractitioner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := msg.(*apiv1.Practitioner); p.GetNames()[0].GetFamily() != "Jones" {
+		t.Errorf("unexpected general practitioner: %v", p)
+	}
+	if _, err := pms.ResolveGeneralPractitioner(context.Background(), &apiv1.Identifier{System: identifiers.GMPNumber, Value: "G1' OR 1=1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument for invalid code, got: %v", err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	if d, err := parseDate("1960/01/31"); err != nil || d.GetSeconds() != time.Date(1960, 1, 31, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("failed to parse date: %v (%v)", d, err)
+	}
+	if d, err := parseDateTime("2020/06/01 14:30:00"); err != nil || d.GetSeconds() != time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC).Unix() {
+		t.Errorf("failed to parse datetime: %v (%v)", d, err)
+	}
+	if d, err := parseDate(""); err != nil || d != nil {
+		t.Errorf("expected empty date to be nil, got %v (%v)", d, err)
+	}
+	for _, d := range []string{"1960/02/31", "1960/01", "2020/06/01 14:30:00"} {
+		if _, err := parseDate(d); err == nil {
+			t.Errorf("expected error parsing date '%s'", d)
+		}
+	}
+}
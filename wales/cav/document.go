@@ -0,0 +1,157 @@
+package cav
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
+	"github.com/wardle/concierge/wales/cav/soap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// publishedTTL is the time for which the identifiers of documents published by this instance are retained
+const publishedTTL = 7 * 24 * time.Hour
+
+// bfsID returns the unique identifier used for a document within CAV PMS, which is made up
+// of system|value unless system==uuid, in which case just a value
+func bfsID(id *apiv1.Identifier) string {
+	if id.GetSystem() == identifiers.UUID {
+		return id.GetValue()
+	}
+	return id.GetSystem() + "|" + id.GetValue()
+}
+
+// GetDocument returns the content of a document previously published to CAV PMS.
+// Documents are retrieved using the identifier used at publication, so a document can be fetched using either the
+// identifier of the document as published, or, for documents published by this instance, the CAV document identifier.
+func (pms *PMSService) GetDocument(ctx context.Context, id *apiv1.Identifier) (result *apiv1.Attachment, err error) {
+	defer metrics.Observe("cav", "retrieve", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "cav.retrieve")
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "identifier: missing parameter: system")
+	}
+	uid := bfsID(id)
+	if id.GetSystem() == identifiers.CardiffAndValeDocID {
+		o, found := pms.published.Get(id.GetValue())
+		if !found {
+			return nil, i18n.Errorf(ctx, codes.NotFound, "document not found: %s|%s", id.GetSystem(), id.GetValue())
+		}
+		uid = o.(string)
+	}
+	var file *soap.ResultFile
+	if pms.fake {
+		file = pms.fakeDocument(uid)
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		token, err := pms.authenticationToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if file, err = pms.performRetrieveFile(ctx, token, uid); err != nil {
+			return nil, err
+		}
+	}
+	if file == nil || len(file.FileContent) == 0 {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "document not found: %s|%s", id.GetSystem(), id.GetValue())
+	}
+	data := file.FileContent
+	if decoded, err := base64.StdEncoding.DecodeString(string(data)); err == nil { // documents are published base64 encoded
+		data = decoded
+	}
+	contentType := pms.contentTypeForFileType(file.FileType)
+	hash := sha1.Sum(data)
+	return &apiv1.Attachment{
+		ContentType: contentType,
+		Data:        data,
+		Size:        uint64(len(data)),
+		Hash:        hash[:],
+		Title:       file.FileName,
+	}, nil
+}
+
+func (pms *PMSService) performRetrieveFile(ctx context.Context, token string, uid string) (*soap.ResultFile, error) {
+	service := soap.NewPMSInterfaceWebServiceSoap(pms.env.URL, nil)
+	response, err := service.RetrieveFileContext(ctx, &soap.RetrieveFile{BfsId: uid, AuthenticationToken: token})
+	if err != nil {
+		log.Printf("cav: retrieve document error: %s", err)
+		return nil, status.Errorf(codes.Unavailable, "CAV PMS error: %s", err)
+	}
+	return response.RetrieveFileResult, nil
+}
+
+// originalVersion returns the identifier of the original version of a document, if the previous versions
+// specified, most recent first, were published to CAV PMS, or nil
+func originalVersion(previous []*apiv1.DocumentVersion) *apiv1.Identifier {
+	if len(previous) == 0 {
+		return nil
+	}
+	original := previous[len(previous)-1]
+	if original.GetResponse().GetId().GetSystem() != identifiers.CardiffAndValeDocID {
+		return nil
+	}
+	return original.GetDocumentId()
+}
+
+// maxSourceLength is the maximum length, in characters, of the description of a document published to CAV PMS
+const maxSourceLength = 255
+
+// documentSource returns the description ("source") of a document published to CAV PMS, which is its title,
+// followed by the author and event date, if known, as the PMS does not otherwise record them.
+// e.g. "Clinic letter - Dr Mark Wardle - 01/06/2020"
+func documentSource(d *apiv1.Document) string {
+	parts := make([]string, 0, 3)
+	if title := strings.TrimSpace(d.GetTitle()); title != "" {
+		parts = append(parts, title)
+	}
+	if author := d.GetAuthor().DisplayName(); author != "" {
+		parts = append(parts, author)
+	}
+	if d.GetEventDateTime().CheckValid() == nil {
+		parts = append(parts, d.GetEventDateTime().AsTime().Local().Format("02/01/2006"))
+	}
+	source := strings.Join(parts, " - ")
+	if r := []rune(source); len(r) > maxSourceLength {
+		source = string(r[:maxSourceLength])
+	}
+	return source
+}
+
+// fakePublish adds the document to the simulator, returning a CAV document identifier
+func fakePublish(crn *apiv1.Identifier, uid string, d *apiv1.Document) string {
+	n := simulator.Current().AddDocument(&simulator.Document{ID: uid, Patient: crn, Title: documentSource(d), ContentType: d.GetData().GetContentType(), Data: d.GetData().GetData()})
+	return strconv.Itoa(100000 + n)
+}
+
+// fakeDocument returns the document from the simulator, base64 encoded as returned by CAV PMS, or nil if not found
+func (pms *PMSService) fakeDocument(uid string) *soap.ResultFile {
+	d, found := simulator.Current().Document(uid)
+	if !found {
+		return nil
+	}
+	fileType := ".pdf"
+	if ct, ok := pms.contentTypes[d.ContentType]; ok {
+		fileType = ct.FileType
+	}
+	return &soap.ResultFile{FileContent: []byte(base64.StdEncoding.EncodeToString(d.Data)), FileType: fileType, FileName: d.Title}
+}
@@ -0,0 +1,61 @@
+package cav
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetDocument(t *testing.T) {
+	pms := NewPMSService("", "", time.Second, true)
+	ctx := context.Background()
+	if _, err := pms.GetDocument(ctx, &apiv1.Identifier{System: identifiers.CardiffAndValeDocID, Value: "12345"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected unknown CAV document id to be not found, got %v", err)
+	}
+	pms.published.SetDefault("12345", "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1")
+	for _, id := range []*apiv1.Identifier{
+		{System: identifiers.CardiffAndValeDocID, Value: "12345"},
+		{System: identifiers.UUID, Value: "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1"},
+	} {
+		doc, err := pms.GetDocument(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.GetContentType() != "application/pdf" || !bytes.HasPrefix(doc.GetData(), []byte("%PDF")) || doc.GetSize() != uint64(len(doc.GetData())) {
+			t.Fatalf("unexpected document for %v: %s %d bytes", id, doc.GetContentType(), doc.GetSize())
+		}
+	}
+}
+
+func TestBfsID(t *testing.T) {
+	if id := bfsID(&apiv1.Identifier{System: identifiers.UUID, Value: "abc"}); id != "abc" {
+		t.Errorf("expected uuid to be used as is, got %s", id)
+	}
+	if id := bfsID(&apiv1.Identifier{System: identifiers.PatientCare, Value: "123"}); id != identifiers.PatientCare+"|123" {
+		t.Errorf("expected system|value, got %s", id)
+	}
+}
+
+func TestDocumentSource(t *testing.T) {
+	d := &apiv1.Document{Title: "Clinic letter"}
+	if source := documentSource(d); source != "Clinic letter" {
+		t.Errorf("expected title as source, got '%s'", source)
+	}
+	d.Author = &apiv1.Practitioner{Names: []*apiv1.HumanName{{Given: "Mark", Family: "Wardle", Prefixes: []string{"Dr"}}}}
+	d.EventDateTime = timestamppb.New(time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local))
+	if source := documentSource(d); source != "Clinic letter - Dr Mark Wardle - 01/06/2020" {
+		t.Errorf("expected title, author and event date as source, got '%s'", source)
+	}
+	d.Title = strings.Repeat("ä", maxSourceLength+10)
+	if source := documentSource(d); len([]rune(source)) != maxSourceLength {
+		t.Errorf("expected source to be truncated, got %d characters", len([]rune(source)))
+	}
+}
@@ -0,0 +1,130 @@
+package cav
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sync"
+
+	"github.com/wardle/concierge/transport"
+	"github.com/wardle/concierge/wales/cav/soap"
+	"gopkg.in/yaml.v2"
+)
+
+// Environment configures the PMS environment used, such as live or test
+type Environment struct {
+	URL          string                  `yaml:"url" json:"url"`                                         // URL of the PMS web service
+	Database     string                  `yaml:"database" json:"database"`                               // database used on login e.g. "vpmslive.world"
+	UserString   string                  `yaml:"user_string" json:"user_string"`                         // identifies this application on login
+	ContentTypes map[string]*ContentType `yaml:"content_types,omitempty" json:"content_types,omitempty"` // content types and document keys; defaults used if empty
+}
+
+// LiveEnvironment is the live PMS environment, used unless another environment is configured
+var LiveEnvironment = Environment{
+	URL:        "http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx",
+	Database:   "vpmslive.world",
+	UserString: "concierge",
+}
+
+// LoadEnvironments loads the configuration of PMS environments from a YAML or JSON file, for example:
+//
+//	environments:
+//	  test:
+//	    url: http://pms-test.example.org/PmsInterface/WebService/PMSInterfaceWebService.asmx
+//	    database: vpmstest.world
+//	    user_string: concierge-test
+//	    content_types:
+//	      application/pdf:
+//	        file_type: .pdf
+//	        keys: ["TEST LETTER"]
+//
+// The live environment is included, unless overridden by the file.
+func LoadEnvironments(filename string) (map[string]*Environment, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Environments map[string]*Environment `yaml:"environments" json:"environments"`
+	}
+	if err := yaml.UnmarshalStrict(b, &config); err != nil { // YAML is a superset of JSON
+		return nil, fmt.Errorf("cav: invalid environments '%s': %w", filename, err)
+	}
+	live := LiveEnvironment
+	result := map[string]*Environment{"live": &live}
+	for name, env := range config.Environments {
+		if env == nil {
+			return nil, fmt.Errorf("cav: environment '%s': missing configuration", name)
+		}
+		if u, err := url.Parse(env.URL); err != nil || u.Host == "" {
+			return nil, fmt.Errorf("cav: environment '%s': invalid url '%s'", name, env.URL)
+		}
+		if env.Database == "" {
+			return nil, fmt.Errorf("cav: environment '%s': missing database", name)
+		}
+		if env.UserString == "" {
+			env.UserString = LiveEnvironment.UserString
+		}
+		if len(env.ContentTypes) > 0 {
+			if err := validateContentTypes(env.ContentTypes); err != nil {
+				return nil, fmt.Errorf("cav: environment '%s': %w", name, err)
+			}
+		}
+		result[name] = env
+	}
+	return result, nil
+}
+
+// SetEnvironment sets the PMS environment used, such as live or test, including its content types,
+// if specified. This should not be called once server is running.
+func (pms *PMSService) SetEnvironment(env *Environment) {
+	pms.env = env
+	if len(env.ContentTypes) > 0 {
+		pms.contentTypes = env.ContentTypes
+	}
+	configureEndpoint(soap.EndpointName(env.URL))
+}
+
+var (
+	endpointMu sync.Mutex
+	endpoints  = make(map[string]bool) // names of endpoints used by the SOAP client, which are named by host
+	proxy      *transport.Proxy
+	tlsConfig  *tls.Config
+)
+
+func init() {
+	configureEndpoint("cav-pms")
+	configureEndpoint(soap.EndpointName(LiveEnvironment.URL))
+}
+
+// SetProxy sets the proxy used for requests to the PMS, rather than that defined by the environment.
+// This should not be called once server is running.
+func SetProxy(p *transport.Proxy) {
+	endpointMu.Lock()
+	proxy = p
+	endpointMu.Unlock()
+	configureEndpoint("cav-pms")
+}
+
+// SetTLSConfig sets the TLS configuration used for requests to the PMS.
+// This should not be called once server is running.
+func SetTLSConfig(cfg *tls.Config) {
+	endpointMu.Lock()
+	tlsConfig = cfg
+	endpointMu.Unlock()
+	configureEndpoint("cav-pms")
+}
+
+// configureEndpoint registers the named endpoint, and configures all registered endpoints with the proxy,
+// TLS configuration and recording for the PMS
+func configureEndpoint(name string) {
+	endpointMu.Lock()
+	defer endpointMu.Unlock()
+	endpoints[name] = true
+	for name := range endpoints {
+		transport.SetProxy(name, proxy)
+		transport.SetTLSConfig(name, tlsConfig)
+		transport.SetRecording(name, recording)
+	}
+}
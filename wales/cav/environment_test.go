@@ -0,0 +1,78 @@
+package cav
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+const testEnvironments = `
+environments:
+  test:
+    url: %s/PmsInterface/WebService/PMSInterfaceWebService.asmx
+    database: vpmstest.world
+    content_types:
+      application/pdf:
+        file_type: .pdf
+        keys: ["test letter"]
+`
+
+func TestEnvironment(t *testing.T) {
+	var login string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/GetData") {
+			http.NotFound(w, r)
+			return
+		}
+		login = r.FormValue("XmlDataBlockIn")
+		fmt.Fprint(w, `<response><method name="Login"><summary success="true" rowcount="1"/><row><column name="token" value="test-token"/></row></method></response>`)
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "cav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "environments.yaml")
+	if err := ioutil.WriteFile(filename, []byte(fmt.Sprintf(testEnvironments, ts.URL)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	envs, err := LoadEnvironments(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envs["live"].Database != LiveEnvironment.Database {
+		t.Errorf("expected live environment to be included, got: %v", envs["live"])
+	}
+	env := envs["test"]
+	if env == nil || env.UserString != LiveEnvironment.UserString || env.ContentTypes["application/pdf"].Keys[0] != "TEST LETTER" {
+		t.Fatalf("incorrect test environment: %v", env)
+	}
+	pms := NewPMSService("user", "password", time.Second, false)
+	pms.SetEnvironment(env)
+	token, err := pms.authenticationToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "test-token" || !strings.Contains(login, "vpmstest.world") {
+		t.Errorf("expected login to test environment, got token '%s' with request: %s", token, login)
+	}
+	if _, key, err := pms.fileTypeAndKey(&apiv1.Document{Data: &apiv1.Attachment{ContentType: "application/pdf"}}); err != nil || key != "TEST LETTER" {
+		t.Errorf("expected document key from test environment, got %s (%v)", key, err)
+	}
+	if err := ioutil.WriteFile(filename, []byte("environments:\n  test:\n    url: invalid\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEnvironments(filename); err == nil {
+		t.Errorf("expected invalid environment to be rejected")
+	}
+}
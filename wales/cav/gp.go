@@ -0,0 +1,84 @@
+package cav
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// gmpCode is the format of a general medical practitioner's national code
+var gmpCode = regexp.MustCompile(`^G\d{7}$`)
+
+// ResolveGeneralPractitioner provides identifier resolution for general practitioners (see identifiers.GMPNumber),
+// using the general practitioners known to CAV PMS
+func (pms *PMSService) ResolveGeneralPractitioner(ctx context.Context, id *apiv1.Identifier) (p proto.Message, err error) {
+	defer metrics.Observe("cav", "gp", time.Now(), &err)
+	if id.GetSystem() != identifiers.GMPNumber {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to resolve general practitioner: incorrect 'system'. expected: '%s' got:'%s'", identifiers.GMPNumber, id.GetSystem())
+	}
+	if !gmpCode.MatchString(id.GetValue()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid general practitioner code: '%s'", id.GetValue())
+	}
+	if err := maintenance.CheckRead(ctx, "cav"); err != nil {
+		return nil, err
+	}
+	var rows []map[string]string
+	if pms.fake {
+		if row, ok := fakeGeneralPractitioners[id.GetValue()]; ok {
+			rows = append(rows, row)
+		}
+	} else {
+		ctx, cancelFunc, err := transport.WithDeadline(ctx, "cav", pms.timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer cancelFunc()
+		token, err := pms.authenticationToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sql, err := sqlFetchGeneralPractitioner.build(params{"code": id.GetValue()})
+		if err != nil {
+			return nil, err
+		}
+		if rows, err = pms.performSQL(ctx, token, sql); err != nil {
+			return nil, err
+		}
+	}
+	if len(rows) == 0 {
+		return nil, status.Errorf(codes.NotFound, "general practitioner not found: %s", id.GetValue())
+	}
+	return parseGeneralPractitioner(rows[0]), nil
+}
+
+// parseGeneralPractitioner parses a row returned by sqlFetchGeneralPractitioner
+func parseGeneralPractitioner(row map[string]string) *apiv1.Practitioner {
+	name := &apiv1.HumanName{Family: row["SURNAME"], Given: row["FORENAME"], Use: apiv1.HumanName_OFFICIAL}
+	if row["TITLE"] != "" {
+		name.Prefixes = []string{row["TITLE"]}
+	}
+	return &apiv1.Practitioner{
+		Active:      true,
+		Identifiers: []*apiv1.Identifier{{System: identifiers.GMPNumber, Value: row["NATIONAL_NO"]}},
+		Names:       []*apiv1.HumanName{name},
+	}
+}
+
+var sqlFetchGeneralPractitioner = mustQuery("gp", `SELECT national_no AS NATIONAL_NO,
+TITLE, SURNAME, FORENAME
+FROM HEALTHCARE_PRACTITIONERS
+WHERE national_no = :code`)
+
+// fakeGeneralPractitioners are the general practitioners of the simulator's patients, used in fake mode
+var fakeGeneralPractitioners = map[string]map[string]string{
+	"G9342400": {"NATIONAL_NO": "G9342400", "TITLE": "Dr", "SURNAME": "Jones", "FORENAME": "Sian"},
+}
@@ -0,0 +1,28 @@
+package cav
+
+import (
+	"regexp"
+
+	"github.com/wardle/concierge/transport"
+)
+
+// recording defines how interactions with CAV PMS are recorded and replayed. Requests differ by the
+// authentication token, and credentials are ignored so that recorded responses may be replayed using
+// other credentials. Responses are redacted of names, addresses, contact details, identifiers and
+// the content of documents, with dates of birth replaced by the first of January of the same year.
+var recording = transport.Recording{
+	Volatile: []*regexp.Regexp{
+		regexp.MustCompile(`authenticationToken="[^"]*"`),
+		regexp.MustCompile(`<(?:\w+:)?authenticationToken>[^<]*</(?:\w+:)?authenticationToken>`),
+		regexp.MustCompile(`<parameter name="(?:username|password)">[^<]*</parameter>`),
+	},
+	Redact: append(transport.RedactXML("FileContent", "FileName"),
+		transport.Redaction{
+			Pattern:     regexp.MustCompile(`(<column name="(?:ID|HOSPITAL_ID|NHS_NUMBER|LAST_NAME|FIRST_FORENAME|SECOND_FORENAME|OTHER_FORENAMES|ADDRESS1|ADDRESS2|ADDRESS3|ADDRESS4|POSTCODE|HOME_PHONE_NO|WORK_PHONE_NO|OCCUPATION|PLACE_OF_BIRTH|PLACE_OF_DEATH)"[^>]*>)[^<]+(</column>)`),
+			Replacement: "${1}REDACTED${2}",
+		},
+		transport.Redaction{
+			Pattern:     regexp.MustCompile(`(<column name="DATE_BIRTH"[^>]*>\d{4})/\d{2}/\d{2}`),
+			Replacement: "${1}/01/01",
+		}),
+}
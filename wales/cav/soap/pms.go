@@ -3,13 +3,18 @@ package soap
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/xml"
 	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/transport"
 )
 
 // against "unused imports"
@@ -116,11 +121,11 @@ type PMSInterfaceWebServiceSoap struct {
 	client *SOAPClient
 }
 
-func NewPMSInterfaceWebServiceSoap(url string, tls bool, auth *BasicAuth) *PMSInterfaceWebServiceSoap {
+func NewPMSInterfaceWebServiceSoap(url string, auth *BasicAuth) *PMSInterfaceWebServiceSoap {
 	if url == "" {
 		url = "https://cavpmswsi.cymru.nhs.uk/PMSInterfaceWebService.asmx"
 	}
-	client := NewSOAPClient(url, tls, auth)
+	client := NewSOAPClient(url, auth)
 
 	return &PMSInterfaceWebServiceSoap{
 		client: client,
@@ -178,8 +183,12 @@ func (service *PMSInterfaceWebServiceSoap) ReceiveFile(request *ReceiveFile) (*R
 }
 
 func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrn(request *ReceiveFileByCrn) (*ReceiveFileByCrnResponse, error) {
+	return service.ReceiveFileByCrnContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrnContext(ctx context.Context, request *ReceiveFileByCrn) (*ReceiveFileByCrnResponse, error) {
 	response := new(ReceiveFileByCrnResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/ReceiveFileByCrn", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/ReceiveFileByCrn", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -188,8 +197,12 @@ func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrn(request *ReceiveFile
 }
 
 func (service *PMSInterfaceWebServiceSoap) RetrieveFile(request *RetrieveFile) (*RetrieveFileResponse, error) {
+	return service.RetrieveFileContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) RetrieveFileContext(ctx context.Context, request *RetrieveFile) (*RetrieveFileResponse, error) {
 	response := new(RetrieveFileResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/RetrieveFile", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/RetrieveFile", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -199,9 +212,8 @@ func (service *PMSInterfaceWebServiceSoap) RetrieveFile(request *RetrieveFile) (
 
 var timeout = time.Duration(30 * time.Second)
 
-func dialTimeout(network, addr string) (net.Conn, error) {
-	return net.DialTimeout(network, addr, timeout)
-}
+// dialer establishes connections, abandoning them if the context of the request is done before the timeout
+var dialer = &net.Dialer{Timeout: timeout}
 
 type SOAPEnvelope struct {
 	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
@@ -378,9 +390,12 @@ func (f *SOAPFault) Error() string {
 	return f.String
 }
 
-func NewSOAPClient(url string, insecureSkipVerify bool, auth *BasicAuth) *SOAPClient {
-	tlsCfg := &tls.Config{
-		InsecureSkipVerify: insecureSkipVerify,
+// NewSOAPClient creates a SOAP client for the url specified, using the TLS configuration set for its
+// endpoint, or requiring at least TLS 1.2 if not set. Server certificates are always verified.
+func NewSOAPClient(url string, auth *BasicAuth) *SOAPClient {
+	tlsCfg := transport.TLSConfigFor(EndpointName(url))
+	if tlsCfg == nil {
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
 	}
 	return NewSOAPClientWithTLSConfig(url, tlsCfg, auth)
 }
@@ -393,11 +408,23 @@ func NewSOAPClientWithTLSConfig(url string, tlsCfg *tls.Config, auth *BasicAuth)
 	}
 }
 
+// EndpointName returns the name used for the circuit breaker, and proxy, for the url specified
+func EndpointName(s string) string {
+	if u, err := url.Parse(s); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return s
+}
+
 func (s *SOAPClient) AddHeader(header interface{}) {
 	s.headers = append(s.headers, header)
 }
 
 func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
+	return s.CallContext(context.Background(), soapAction, request, response)
+}
+
+func (s *SOAPClient) CallContext(ctx context.Context, soapAction string, request, response interface{}) error {
 	envelope := SOAPEnvelope{}
 
 	if s.headers != nil && len(s.headers) > 0 {
@@ -419,7 +446,7 @@ func (s *SOAPClient) Call(soapAction string, request, response interface{}) erro
 	if err := encoder.Flush(); err != nil {
 		return err
 	}
-	req, err := http.NewRequest("POST", s.url, buffer)
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, buffer)
 	if err != nil {
 		return err
 	}
@@ -434,10 +461,12 @@ func (s *SOAPClient) Call(soapAction string, request, response interface{}) erro
 
 	tr := &http.Transport{
 		TLSClientConfig: s.tlsCfg,
-		Dial:            dialTimeout,
+		DialContext:     dialer.DialContext,
+		Proxy:           transport.ProxyFor(EndpointName(s.url)),
 	}
 
-	client := &http.Client{Transport: tr}
+	// SOAP calls may not be idempotent, so requests are only retried if the connection fails
+	client := transport.NewClient(EndpointName(s.url), tr, false)
 	res, err := client.Do(req)
 	if err != nil {
 		return err
@@ -459,6 +488,7 @@ func (s *SOAPClient) Call(soapAction string, request, response interface{}) erro
 	}
 	fault := respEnvelope.Body.Fault
 	if fault != nil {
+		metrics.SOAPFault(EndpointName(s.url))
 		return fault
 	}
 
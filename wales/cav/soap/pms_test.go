@@ -0,0 +1,44 @@
+package soap
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCallCancellation(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	aborted := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body) // the server notices the client has gone only once the request has been read
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+	client := NewSOAPClient(ts.URL, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if err := client.CallContext(ctx, "test", &RetrieveFile{}, &RetrieveFileResponse{}); err == nil {
+		t.Fatal("expected error when call cancelled")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected call to stop promptly once cancelled. took: %s", d)
+	}
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("expected outbound request to be abandoned once cancelled")
+	}
+}
@@ -0,0 +1,115 @@
+package cav
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The PMS accepts only SQL text, without bound parameters. Queries are therefore written with named
+// parameters, such as :crn, which are replaced by literals when the query is built. Values are checked
+// against a whitelist of characters, and quoted and escaped, so that crafted values, such as clinic codes,
+// cannot alter the query.
+
+// query is an SQL query with named parameters
+type query struct {
+	name     string
+	parts    []string // SQL text, alternating with parameter names
+	required map[string]bool
+}
+
+// params are the values of the parameters of a query: strings, integers or dates (time.Time)
+type params map[string]interface{}
+
+// safeValue is the whitelist of characters permitted in string parameters
+var safeValue = regexp.MustCompile(`^[A-Za-z0-9 _\-./]*$`)
+
+// maxValueLength is the maximum length of a string parameter
+const maxValueLength = 64
+
+// mustQuery parses an SQL query with named parameters, panicking if invalid, for use in initialising variables.
+// Parameters are a colon followed by a name (e.g. :clinic), and are not recognised within quoted literals,
+// so that formats such as 'hh24:mi:ss' are unaffected.
+func mustQuery(name string, sql string) *query {
+	q := &query{name: name, required: make(map[string]bool)}
+	var b strings.Builder
+	inLiteral := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			inLiteral = !inLiteral
+		case c == ':' && !inLiteral && i+1 < len(sql) && isParamChar(sql[i+1], true):
+			j := i + 1
+			for j < len(sql) && isParamChar(sql[j], false) {
+				j++
+			}
+			param := sql[i+1 : j]
+			q.parts = append(q.parts, b.String(), param)
+			q.required[param] = true
+			b.Reset()
+			i = j - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	if inLiteral {
+		panic(fmt.Sprintf("cav: query '%s': unterminated literal", name))
+	}
+	q.parts = append(q.parts, b.String())
+	return q
+}
+
+func isParamChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// build returns the SQL for the query with the parameters specified. An error is returned if a parameter
+// is missing or unknown, or if a value contains characters that are not permitted.
+func (q *query) build(p params) (string, error) {
+	for name := range p {
+		if !q.required[name] {
+			return "", fmt.Errorf("cav: query '%s': unknown parameter '%s'", q.name, name)
+		}
+	}
+	var b strings.Builder
+	for i, part := range q.parts {
+		if i%2 == 0 {
+			b.WriteString(part)
+			continue
+		}
+		v, ok := p[part]
+		if !ok {
+			return "", fmt.Errorf("cav: query '%s': missing parameter '%s'", q.name, part)
+		}
+		literal, err := sqlLiteral(v)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(literal)
+	}
+	return b.String(), nil
+}
+
+// sqlLiteral returns the value as an SQL literal
+func sqlLiteral(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		if len(v) > maxValueLength || !safeValue.MatchString(v) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid value: '%s'", v)
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case time.Time:
+		return "To_Date('" + v.Format("2006/01/02") + "', 'yyyy/mm/dd')", nil
+	}
+	return "", fmt.Errorf("cav: unsupported parameter type %T", v)
+}
@@ -0,0 +1,70 @@
+package cav
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestQuery(t *testing.T) {
+	q := mustQuery("test", `SELECT to_char(START_TIME, 'hh24:mi') FROM SLOTS WHERE CLINIC = :clinic AND SESSION_DATE = :date AND ID > :id`)
+	date := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	sql, err := q.build(params{"clinic": "NEUR01", "date": date, "id": 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `SELECT to_char(START_TIME, 'hh24:mi') FROM SLOTS WHERE CLINIC = 'NEUR01' AND SESSION_DATE = To_Date('2020/06/01', 'yyyy/mm/dd') AND ID > 12`
+	if sql != expected {
+		t.Fatalf("unexpected sql:\n%s\nexpected:\n%s", sql, expected)
+	}
+	if _, err := q.build(params{"clinic": "NEUR01", "date": date}); err == nil {
+		t.Error("expected error for missing parameter")
+	}
+	if _, err := q.build(params{"clinic": "NEUR01", "date": date, "id": 12, "other": "x"}); err == nil {
+		t.Error("expected error for unknown parameter")
+	}
+	if _, err := q.build(params{"clinic": 1.5, "date": date, "id": 12}); err == nil {
+		t.Error("expected error for unsupported parameter type")
+	}
+}
+
+func TestQueryInjection(t *testing.T) {
+	invalid := []string{
+		"NEUR01' OR '1'='1",
+		"NEUR01'; DROP TABLE PATIENTS; --",
+		"NEUR01\" OR 1=1",
+		"NEUR01]]><",
+		"NEUR01\n",
+		strings.Repeat("A", maxValueLength+1),
+	}
+	for _, clinic := range invalid {
+		if _, err := sqlFetchClinic.build(params{"clinic": clinic}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected invalid argument for clinic '%s', got: %v", clinic, err)
+		}
+		if _, err := createSQLFetchPatientsForClinic(clinic, time.Now()); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected invalid argument for clinic '%s', got: %v", clinic, err)
+		}
+	}
+	if _, err := sqlFetchGeneralPractitioner.build(params{"code": "G9999999' UNION SELECT PASSWORD FROM USERS --"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument for general practitioner, got: %v", err)
+	}
+	sql, err := createSQLFetchPatientByCRN("A999998")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, "PAID_TYPE = 'A'") || !strings.Contains(sql, "PATIENT_IDENTIFIERS.ID = '999998'") {
+		t.Errorf("unexpected sql for patient: %s", sql)
+	}
+}
+
+func TestMustQueryPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unterminated literal")
+		}
+	}()
+	mustQuery("invalid", `SELECT * FROM PATIENTS WHERE SURNAME = 'DUMMY`)
+}
@@ -0,0 +1,299 @@
+package cav
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/secrets"
+	"github.com/wardle/concierge/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultTokenLifetime is the lifetime assumed for an authentication token if the PMS does not report its expiry
+const defaultTokenLifetime = 10 * time.Minute
+
+// tokenRefreshMargin is the time before expiry at which a token is refreshed in the background, so that
+// requests are not delayed by logging in
+const tokenRefreshMargin = time.Minute
+
+// Token is an authentication token for the PMS
+type Token struct {
+	Value   string
+	Expires time.Time
+}
+
+// valid returns whether the token is valid for at least the duration specified
+func (t Token) valid(d time.Duration) bool {
+	return t.Value != "" && time.Until(t.Expires) > d
+}
+
+// TokenStore stores the authentication token for the PMS, so that it may be shared between instances
+type TokenStore interface {
+	// Get returns the current token, if any
+	Get(ctx context.Context) (Token, bool)
+	// Set stores the token until it expires
+	Set(ctx context.Context, t Token)
+	// Lock obtains an exclusive lock for logging in, so that only one instance logs in at a time,
+	// returning a function to release the lock
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// NewTokenStore creates a token store using the backend specified ("memory" or "redis")
+func NewTokenStore(backend string, addr string) (TokenStore, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryTokenStore(), nil
+	case "redis":
+		return NewRedisTokenStore(addr)
+	}
+	return nil, fmt.Errorf("cav: unsupported token store backend: '%s'. supported: memory, redis", backend)
+}
+
+// memoryTokenStore is an in-process token store, which is not shared between instances
+type memoryTokenStore struct {
+	mu    sync.RWMutex
+	token Token
+}
+
+// NewMemoryTokenStore creates an in-process token store
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{}
+}
+
+func (ms *memoryTokenStore) Get(ctx context.Context) (Token, bool) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.token, ms.token.Value != ""
+}
+
+func (ms *memoryTokenStore) Set(ctx context.Context, t Token) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.token = t
+}
+
+// Lock does nothing, as logins within a single instance are serialised by the PMS service
+func (ms *memoryTokenStore) Lock(ctx context.Context) (func(), error) {
+	return func() {}, nil
+}
+
+// redisTokenStore is a token store backed by redis, so that the token may be shared between instances and
+// survive restarts. The token is stored with its expiry, as "expiry-unix-seconds|token".
+type redisTokenStore struct {
+	client *redis.Client
+}
+
+const (
+	redisTokenKey     = "concierge:cav:token"
+	redisTokenLockKey = "concierge:cav:token:lock"
+	redisLockTTL      = 30 * time.Second // maximum time for which a lock is held, should an instance fail
+	redisLockRetry    = 100 * time.Millisecond
+)
+
+// unlockScript deletes the lock only if it is still held by the caller
+var unlockScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+
+// NewRedisTokenStore creates a token store backed by the redis server at the address specified (e.g. localhost:6379)
+func NewRedisTokenStore(addr string) (TokenStore, error) {
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping().Err(); err != nil {
+		return nil, fmt.Errorf("cav: failed to connect to redis at '%s': %w", addr, err)
+	}
+	return &redisTokenStore{client: client}, nil
+}
+
+func (rs *redisTokenStore) Get(ctx context.Context) (Token, bool) {
+	s, err := rs.client.WithContext(ctx).Get(redisTokenKey).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("cav: failed to get token from redis: %s", err)
+		}
+		return Token{}, false
+	}
+	parts := strings.SplitN(s, "|", 2)
+	if len(parts) != 2 {
+		log.Printf("cav: invalid token in redis")
+		return Token{}, false
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		log.Printf("cav: invalid token expiry in redis: %s", err)
+		return Token{}, false
+	}
+	return Token{Value: parts[1], Expires: time.Unix(expires, 0)}, true
+}
+
+func (rs *redisTokenStore) Set(ctx context.Context, t Token) {
+	ttl := time.Until(t.Expires)
+	if ttl <= 0 {
+		return
+	}
+	value := strconv.FormatInt(t.Expires.Unix(), 10) + "|" + t.Value
+	if err := rs.client.WithContext(ctx).Set(redisTokenKey, value, ttl).Err(); err != nil {
+		log.Printf("cav: failed to set token in redis: %s", err)
+	}
+}
+
+// Lock obtains a lock shared between instances, waiting until the lock is available or the context is done
+func (rs *redisTokenStore) Lock(ctx context.Context) (func(), error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(b)
+	client := rs.client.WithContext(ctx)
+	for {
+		ok, err := client.SetNX(redisTokenLockKey, id, redisLockTTL).Result()
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "cav: failed to obtain login lock: %s", err)
+		}
+		if ok {
+			return func() {
+				if err := unlockScript.Run(rs.client, []string{redisTokenLockKey}, id).Err(); err != nil {
+					log.Printf("cav: failed to release login lock: %s", err)
+				}
+			}, nil
+		}
+		select {
+		case <-time.After(redisLockRetry):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// session holds the credentials used to authenticate to the PMS, and the resulting authentication token
+type session struct {
+	username string
+	password secrets.Secret
+
+	tokens     TokenStore // shared authentication token
+	loginMu    sync.Mutex // serialises logins within this instance
+	tokenMu    sync.Mutex // protects refreshing
+	refreshing bool       // whether the token is being refreshed in the background
+}
+
+// sessionFor returns the session for calls on behalf of the tenant in the context specified, or the default session
+func (pms *PMSService) sessionFor(ctx context.Context) *session {
+	if s, ok := pms.tenants[tenant.FromContext(ctx)]; ok {
+		return s
+	}
+	return pms.session
+}
+
+// SetTokenStore sets the store used for the default authentication token, such as to share it between instances.
+// This should not be called once server is running.
+func (pms *PMSService) SetTokenStore(store TokenStore) {
+	pms.session.tokens = store
+}
+
+// authenticationToken (lazily) returns a valid authentication token, logging in if necessary.
+// A token close to expiry is refreshed in the background.
+func (pms *PMSService) authenticationToken(ctx context.Context) (string, error) {
+	t, _ := pms.sessionFor(ctx).tokens.Get(ctx)
+	cached := t.valid(0)
+	metrics.CacheLookup("cav-token", cached)
+	if cached {
+		if !t.valid(tokenRefreshMargin) {
+			pms.refreshInBackground(tenant.FromContext(ctx))
+		}
+		return t.Value, nil
+	}
+	t, err := pms.login(ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+// refreshInBackground refreshes the token for the named tenant in the background, unless already refreshing
+func (pms *PMSService) refreshInBackground(name string) {
+	ctx := tenant.NewContext(context.Background(), name)
+	s := pms.sessionFor(ctx)
+	s.tokenMu.Lock()
+	if s.refreshing {
+		s.tokenMu.Unlock()
+		return
+	}
+	s.refreshing = true
+	s.tokenMu.Unlock()
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, pms.timeout)
+		defer cancel()
+		if _, err := pms.login(ctx, tokenRefreshMargin); err != nil {
+			log.Printf("cav: failed to refresh authentication token: %s", err)
+		}
+		s.tokenMu.Lock()
+		s.refreshing = false
+		s.tokenMu.Unlock()
+	}()
+}
+
+// login logs in to the PMS and stores the new token, unless a token valid for longer than the duration specified
+// has been obtained in the meantime, such as by another request or instance
+func (pms *PMSService) login(ctx context.Context, valid time.Duration) (Token, error) {
+	s := pms.sessionFor(ctx)
+	s.loginMu.Lock()
+	defer s.loginMu.Unlock()
+	unlock, err := s.tokens.Lock(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+	defer unlock()
+	if t, _ := s.tokens.Get(ctx); t.valid(valid) {
+		return t, nil
+	}
+	t, err := pms.authenticate(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+	s.tokens.Set(ctx, t)
+	log.Printf("cav: obtained new authentication token, expires %s", t.Expires)
+	return t, nil
+}
+
+// parseLoginResponse returns the token from a login response. The expiry of the token is taken from an
+// "expires" column (date and time) or "timeout" column (minutes), if returned, or defaultTokenLifetime if not.
+func parseLoginResponse(r *GetDataResponse, now time.Time) (Token, error) {
+	if r.Method.Summary.Success != "true" || r.Method.Summary.Rowcount != "1" || len(r.Method.Row) == 0 || len(r.Method.Row[0].Column) == 0 {
+		log.Printf("cavpms login error: %s", r.Method.Message)
+		return Token{}, status.Error(codes.PermissionDenied, "Could not login to CAV PMS")
+	}
+	columns := r.Method.Row[0].Column
+	t := Token{Value: columns[0].Value, Expires: now.Add(defaultTokenLifetime)}
+	for _, col := range columns {
+		value := strings.TrimSpace(col.Value)
+		if value == "" {
+			value = strings.TrimSpace(col.Text)
+		}
+		switch strings.ToLower(col.Name) {
+		case "token":
+			t.Value = value
+		case "expires", "expiry":
+			if expires, err := time.ParseInLocation("2006/01/02 15:04:05", value, time.Local); err == nil {
+				t.Expires = expires
+			}
+		case "timeout":
+			if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+				t.Expires = now.Add(time.Duration(minutes) * time.Minute)
+			}
+		}
+	}
+	if t.Value == "" {
+		return Token{}, status.Error(codes.PermissionDenied, "Could not login to CAV PMS")
+	}
+	return t, nil
+}
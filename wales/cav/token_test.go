@@ -0,0 +1,61 @@
+package cav
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseLoginResponse(t *testing.T) {
+	now := time.Date(2020, 6, 1, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		response string
+		token    string
+		expires  time.Time
+	}{
+		{`<response><method name="Login"><summary success="true" rowcount="1"/><row><column name="token" value="abc"/></row></method></response>`, "abc", now.Add(defaultTokenLifetime)},
+		{`<response><method name="Login"><summary success="true" rowcount="1"/><row><column name="token" value="abc"/><column name="timeout" value="20"/></row></method></response>`, "abc", now.Add(20 * time.Minute)},
+		{`<response><method name="Login"><summary success="true" rowcount="1"/><row><column name="token" value="abc"/><column name="expires">2020/06/01 09:30:00</column></row></method></response>`, "abc", now.Add(30 * time.Minute)},
+	}
+	for _, test := range tests {
+		var r GetDataResponse
+		if err := xml.Unmarshal([]byte(test.response), &r); err != nil {
+			t.Fatal(err)
+		}
+		token, err := parseLoginResponse(&r, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Value != test.token || !token.Expires.Equal(test.expires) {
+			t.Errorf("expected %s expiring %s, got: %v", test.token, test.expires, token)
+		}
+	}
+	var r GetDataResponse
+	xml.Unmarshal([]byte(`<response><method name="Login"><message>invalid password</message><summary success="false" rowcount="0"/></method></response>`), &r)
+	if _, err := parseLoginResponse(&r, now); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected permission denied for failed login, got: %v", err)
+	}
+}
+
+func TestSharedToken(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenStore()
+	store.Set(ctx, Token{Value: "shared", Expires: time.Now().Add(time.Hour)})
+	pms := NewPMSService("", "", time.Second, false)
+	pms.SetTokenStore(store)
+	token, err := pms.authenticationToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "shared" {
+		t.Fatalf("expected token from store, got '%s'", token)
+	}
+	// a token obtained by another instance while waiting to log in is used, rather than logging in again
+	if token, err := pms.login(ctx, tokenRefreshMargin); err != nil || token.Value != "shared" {
+		t.Fatalf("expected token from store, got '%v' (%v)", token, err)
+	}
+}
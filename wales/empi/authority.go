@@ -101,6 +101,17 @@ func (a Authority) ToURI() string {
 	return uris[a]
 }
 
+// Systems returns the identifier systems (URIs) that can be used to look up patients in the EMPI
+func Systems() []string {
+	result := make([]string, 0, len(uris))
+	for a := AuthorityNHS; a < lastAuthority; a++ {
+		if a != AuthorityEMPI && uris[a] != "" && empiOrgCodes[a] != "" {
+			result = append(result, uris[a])
+		}
+	}
+	return result
+}
+
 // empiOrgCodes are the internal (proprietary) codes given to authorities within the Welsh EMPI
 var empiOrgCodes = [...]string{
 	"",
@@ -178,6 +189,21 @@ var authorityTypes = [...]string{
 	"PI",
 }
 
+// SystemForAuthority returns the identifier system (URI) for an EMPI authority code (e.g. "140"),
+// or an empty string if the authority is unknown
+func SystemForAuthority(code string) string {
+	return lookupFromEmpiOrgCode(code).ToURI()
+}
+
+// AuthorityForSystem returns the EMPI authority code (e.g. "140") for an identifier system (URI),
+// or an empty string if the system is not known to the EMPI
+func AuthorityForSystem(system string) string {
+	if a, ok := uriLookup[system]; ok && system != "" {
+		return a.empiOrganisationCode()
+	}
+	return ""
+}
+
 func lookupFromEmpiOrgCode(identifier string) Authority {
 	if a, ok := empiOrgLookup[identifier]; ok {
 		return a
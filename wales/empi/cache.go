@@ -0,0 +1,142 @@
+package empi
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// Cache is a cache of patients fetched from the EMPI, keyed by authority and identifier
+type Cache interface {
+	// Get returns the patient for the specified key, if cached
+	Get(key string) (*apiv1.Patient, bool)
+	// Set caches the patient for the specified key
+	Set(key string, pt *apiv1.Patient)
+	// Delete removes any cached patient for the specified key
+	Delete(key string)
+}
+
+// NewCache creates a cache using the backend specified ("memory" or "redis")
+func NewCache(backend string, addr string, ttl time.Duration) (Cache, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryCache(ttl), nil
+	case "redis":
+		return NewRedisCache(addr, ttl)
+	}
+	return nil, fmt.Errorf("empi: unsupported cache backend: '%s'. supported: memory, redis", backend)
+}
+
+// memoryCache is an in-process cache, which is not shared between instances
+type memoryCache struct {
+	cache *cache.Cache
+}
+
+// NewMemoryCache creates an in-process cache with the specified expiration
+func NewMemoryCache(ttl time.Duration) Cache {
+	return &memoryCache{cache: cache.New(ttl, ttl*2)}
+}
+
+func (mc *memoryCache) Get(key string) (*apiv1.Patient, bool) {
+	if o, found := mc.cache.Get(key); found {
+		return o.(*apiv1.Patient), true
+	}
+	return nil, false
+}
+
+func (mc *memoryCache) Set(key string, pt *apiv1.Patient) {
+	mc.cache.SetDefault(key, pt)
+}
+
+func (mc *memoryCache) Delete(key string) {
+	mc.cache.Delete(key)
+}
+
+// redisCache is a cache backed by redis, so that it may be shared between instances and survive restarts.
+// Patients are stored using the protobuf binary serialisation.
+type redisCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// redisKeyPrefix namespaces keys within redis
+const redisKeyPrefix = "concierge:empi:"
+
+// NewRedisCache creates a cache backed by the redis server at the address specified (e.g. localhost:6379)
+func NewRedisCache(addr string, ttl time.Duration) (Cache, error) {
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping().Err(); err != nil {
+		return nil, fmt.Errorf("empi: failed to connect to redis at '%s': %w", addr, err)
+	}
+	return &redisCache{client: client, ttl: ttl}, nil
+}
+
+func (rc *redisCache) Get(key string) (*apiv1.Patient, bool) {
+	b, err := rc.client.Get(redisKeyPrefix + key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("empi: failed to get '%s' from redis cache: %s", key, err)
+		}
+		return nil, false
+	}
+	pt := new(apiv1.Patient)
+	if err := proto.Unmarshal(b, pt); err != nil {
+		log.Printf("empi: invalid cached patient '%s': %s", key, err)
+		return nil, false
+	}
+	return pt, true
+}
+
+func (rc *redisCache) Set(key string, pt *apiv1.Patient) {
+	b, err := proto.Marshal(pt)
+	if err != nil {
+		log.Printf("empi: failed to marshal patient '%s' for cache: %s", key, err)
+		return
+	}
+	if err := rc.client.Set(redisKeyPrefix+key, b, rc.ttl).Err(); err != nil {
+		log.Printf("empi: failed to set '%s' in redis cache: %s", key, err)
+	}
+}
+
+func (rc *redisCache) Delete(key string) {
+	if err := rc.client.Del(redisKeyPrefix + key).Err(); err != nil {
+		log.Printf("empi: failed to delete '%s' from redis cache: %s", key, err)
+	}
+}
+
+// InvalidateOnEvents removes cached patients when notified that patients have been updated or merged,
+// such as by an inbound HL7 v2 ADT feed, so that subsequent requests fetch current data from the EMPI.
+func (app *App) InvalidateOnEvents() {
+	events.Subscribe(func(e *events.Event) {
+		if app.Cache == nil || (e.Type != events.PatientUpdated && e.Type != events.PatientMerged) {
+			return
+		}
+		ids := []*apiv1.Identifier{e.Subject}
+		switch data := e.Data.(type) {
+		case *apiv1.Patient:
+			ids = append(ids, data.GetIdentifiers()...)
+		case *apiv1.Identifier:
+			ids = append(ids, data)
+		}
+		for _, id := range ids {
+			code := id.GetSystem()
+			if authority, ok := uriLookup[code]; ok {
+				code = authority.empiOrganisationCode()
+			}
+			if code != "" && id.GetValue() != "" {
+				for _, key := range app.cacheKeys(code, id.GetValue()) {
+					app.Cache.Delete(key)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package empi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestInvalidateOnEvents(t *testing.T) {
+	app := &App{Cache: NewMemoryCache(time.Hour)}
+	app.InvalidateOnEvents()
+	app.Cache.Set("NHS/1111111111", &apiv1.Patient{Lastname: "DUMMY"})
+	app.Cache.Set("140/A999998", &apiv1.Patient{Lastname: "DUMMY"})
+	app.Cache.Set("140/A999997", &apiv1.Patient{Lastname: "DUMMY"})
+	events.Publish(&events.Event{Type: events.PatientUpdated, Subject: &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}})
+	if _, found := app.Cache.Get("NHS/1111111111"); found {
+		t.Errorf("expected updated patient to be removed from cache")
+	}
+	if _, found := app.Cache.Get("140/A999998"); !found {
+		t.Errorf("expected other patients to remain cached")
+	}
+	events.Publish(&events.Event{Type: events.PatientMerged, Subject: &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999998"}, Data: &apiv1.Identifier{System: "140", Value: "A999997"}})
+	for _, key := range []string{"140/A999998", "140/A999997"} {
+		if _, found := app.Cache.Get(key); found {
+			t.Errorf("expected merged patient %s to be removed from cache", key)
+		}
+	}
+}
@@ -10,33 +10,55 @@ import (
 	"net/http"
 	"regexp"
 
-	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"net/url"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/hl7time"
+	"github.com/wardle/concierge/i18n"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
 	"github.com/wardle/concierge/server"
-
-	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tenant"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
 )
 
 // App represents the EMPI application
 type App struct {
-	EndpointURL    string       // override URL for the specified endpoint
-	ProcessingID   string       // processing ID to use; their definitions are: P production, U testing, T development
-	Cache          *cache.Cache // may be nil if not caching
+	EndpointURL    string            // override URL for the specified endpoint
+	ProcessingID   string            // processing ID to use; their definitions are: P production, U testing, T development
+	TenantIDs      map[string]string // processing ID for each tenant, in place of ProcessingID
+	Cache          Cache             // may be nil if not caching
+	Dedupe         *transport.Group  // deduplicates concurrent identical requests; may be nil
+	Transport      http.RoundTripper // HTTP transport shared by requests to the EMPI; http.DefaultTransport if nil
+	UpdateAddr     string            // MLLP address of the EMPI update interface; updates are not supported if empty
 	Fake           bool
 	TimeoutSeconds int
+
+	clientOnce sync.Once
+	client     *http.Client
+}
+
+// httpClient returns the HTTP client used for requests to the EMPI, shared so that connections are reused
+func (app *App) httpClient() *http.Client {
+	app.clientOnce.Do(func() {
+		app.client = transport.NewClient("empi", app.Transport, true) // queries are safe to retry
+	})
+	return app.client
 }
 
 // ResolveIdentifier provides an identifier/value resolution service
@@ -44,8 +66,26 @@ func (app *App) ResolveIdentifier(ctx context.Context, id *apiv1.Identifier) (pr
 	return app.GetEMPIRequest(ctx, id)
 }
 
+// GetPatient returns the patient with the specified identifier
+func (app *App) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	return app.GetEMPIRequest(ctx, id)
+}
+
 // Close closes any linked resources
-func (app *App) Close() {}
+func (app *App) Close() error { return nil }
+
+// HealthCheck checks the health of the EMPI, returning an error if recent requests have failed such that
+// the circuit breaker for the EMPI endpoint is open. The EMPI provides no means of checking its health
+// without a patient lookup.
+func (app *App) HealthCheck(ctx context.Context) error {
+	if app.Fake {
+		return nil
+	}
+	if transport.States()["empi"] == transport.Open {
+		return status.Errorf(codes.Unavailable, "empi: circuit breaker open")
+	}
+	return nil
+}
 
 // GetEMPIRequest fetches a patient matching the identifier specified
 func (app *App) GetEMPIRequest(ctx context.Context, req *apiv1.Identifier) (*apiv1.Patient, error) {
@@ -67,9 +107,15 @@ func (app *App) GetEMPIRequest(ctx context.Context, req *apiv1.Identifier) (*api
 }
 
 // GetInternalEMPIRequest fetches a patient using raw authority and identifier codes
-func (app *App) GetInternalEMPIRequest(ctx context.Context, req *apiv1.Identifier) (*apiv1.Patient, error) {
+func (app *App) GetInternalEMPIRequest(ctx context.Context, req *apiv1.Identifier) (pt *apiv1.Patient, err error) {
 	start := time.Now()
-	key := req.System + "/" + req.Value
+	defer metrics.Observe("empi", "fetch", start, &err)
+	ctx, span := tracing.StartSpan(ctx, "empi.fetch", tracing.String("empi.authority", req.System))
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckRead(ctx, "wales-empi"); err != nil {
+		return nil, err
+	}
+	key := app.cacheKey(ctx, req.System, req.Value)
 	pt, found := app.getCache(key)
 	if found {
 		log.Printf("empi: serving request for %s/%s from cache in %s", req.System, req.Value, time.Since(start))
@@ -84,134 +130,176 @@ func (app *App) GetInternalEMPIRequest(ctx context.Context, req *apiv1.Identifie
 	if valid, req.Value = authority.ValidateIdentifier(req.Value); !valid {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid %s number: %s", req.System, req.Value)
 	}
-	if app.Fake {
-		log.Printf("empi: returning fake result for %s/%s", req.System, req.Value)
-		return performFake(authority, req.Value)
+	result, err := app.Dedupe.Do(ctx, key, func(ctx context.Context) (proto.Message, error) {
+		return app.fetch(ctx, authority, key, req)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result.(*apiv1.Patient), nil
+}
+
+// fetch fetches a patient from the EMPI, or the simulator in fake mode, caching the result
+func (app *App) fetch(ctx context.Context, authority Authority, key string, req *apiv1.Identifier) (*apiv1.Patient, error) {
 	timeout := app.TimeoutSeconds
 	if timeout == 0 {
 		timeout = 1
 	}
-	ctx, cancelFunc := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
-	pt, err := performRequest(ctx, app.EndpointURL, app.ProcessingID, authority, req.Value)
-	cancelFunc()
+	var pt *apiv1.Patient
+	var err error
+	if app.Fake {
+		log.Printf("empi: returning fake result for %s/%s", req.System, req.Value)
+		pt = performFake(authority, req.Value)
+	} else {
+		var reqCtx context.Context
+		var cancelFunc context.CancelFunc
+		if reqCtx, cancelFunc, err = transport.WithDeadline(ctx, "empi", time.Duration(timeout)*time.Second); err != nil {
+			return nil, err
+		}
+		pt, err = performRequest(reqCtx, app.httpClient(), app.EndpointURL, app.processingID(ctx), authority, req.Value)
+		cancelFunc()
+	}
 	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
 		if urlError, ok := err.(*url.Error); ok {
 			if urlError.Timeout() {
-				return nil, status.Errorf(codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", app.TimeoutSeconds)
+				return nil, i18n.Errorf(ctx, codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", app.TimeoutSeconds)
 			}
 		}
 		return nil, err
 	}
 	if pt == nil {
-		return nil, status.Errorf(codes.NotFound, "patient %s/%s not found", req.System, req.Value)
+		return nil, i18n.Errorf(ctx, codes.NotFound, "patient %s/%s not found", req.System, req.Value)
 	}
 	log.Printf("empi: response for %s: %s", req.Value, protojson.MarshalOptions{}.Format(pt))
+	requested := &apiv1.Identifier{System: authority.ToURI(), Value: req.Value}
+	events.PublishPatientIfChanged("empi/"+key, requested, pt)
+	if current := supersededBy(requested, pt); current != nil {
+		log.Printf("empi: %s|%s has been merged into %s|%s", requested.GetSystem(), requested.GetValue(), current.GetSystem(), current.GetValue())
+		events.PublishMergeIfNew(current, requested)
+	}
+	app.setCache(key, pt)
 	return pt, nil
 }
 
+// supersededBy returns the identifier of the record that has superseded the identifier requested, or nil.
+// The EMPI returns the surviving record when queried using an identifier that has since been merged, so
+// that record has a different identifier from the same authority, in place of that requested.
+func supersededBy(requested *apiv1.Identifier, pt *apiv1.Patient) *apiv1.Identifier {
+	var current *apiv1.Identifier
+	for _, id := range pt.GetIdentifiers() {
+		if id.GetSystem() != requested.GetSystem() {
+			continue
+		}
+		if strings.EqualFold(id.GetValue(), requested.GetValue()) {
+			return nil
+		}
+		if current == nil {
+			current = id
+		}
+	}
+	return current
+}
+
+// processingID returns the processing ID to use for requests on behalf of the tenant in the context specified
+func (app *App) processingID(ctx context.Context) string {
+	if id, ok := app.TenantIDs[tenant.FromContext(ctx)]; ok {
+		return id
+	}
+	return app.ProcessingID
+}
+
+// cacheKey returns the key used to cache a patient fetched using the EMPI organisation code and identifier
+// specified, such that patients fetched using a different processing ID, from a different environment, for
+// a tenant, are cached separately
+func (app *App) cacheKey(ctx context.Context, code string, value string) string {
+	if id := app.processingID(ctx); id != app.ProcessingID {
+		return id + ":" + code + "/" + value
+	}
+	return code + "/" + value
+}
+
+// cacheKeys returns the keys with which a patient may be cached, for all processing IDs
+func (app *App) cacheKeys(code string, value string) []string {
+	result := []string{code + "/" + value}
+	seen := map[string]bool{app.ProcessingID: true}
+	for _, id := range app.TenantIDs {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id+":"+code+"/"+value)
+		}
+	}
+	return result
+}
+
 func (app *App) getCache(key string) (*apiv1.Patient, bool) {
 	if app.Cache == nil {
 		return nil, false
 	}
-	if o, found := app.Cache.Get(key); found {
-		return o.(*apiv1.Patient), true
-	}
-	return nil, false
+	pt, found := app.Cache.Get(key)
+	metrics.CacheLookup("empi", found)
+	return pt, found
 }
 
 func (app *App) setCache(key string, value *apiv1.Patient) {
 	if app.Cache == nil {
 		return
 	}
-	app.Cache.Set(key, value, cache.DefaultExpiration)
+	app.Cache.Set(key, value)
 }
 
-func performFake(authority Authority, identifier string) (*apiv1.Patient, error) {
-	dob, err := ptypes.TimestampProto(time.Date(1960, 01, 01, 00, 00, 00, 0, time.UTC))
-	if err != nil {
-		return nil, err
+// performFake returns the patient from the simulator, as the EMPI would return it, or nil if not found
+func performFake(authority Authority, identifier string) *apiv1.Patient {
+	pt, found := simulator.Current().Patient(simulator.EMPI, &apiv1.Identifier{System: authority.ToURI(), Value: identifier})
+	if !found {
+		return nil
 	}
-	return &apiv1.Patient{
-		Lastname:   "DUMMY",
-		Firstnames: "ALBERT",
-		Title:      "DR",
-		Gender:     apiv1.Gender_MALE,
-		BirthDate:  dob,
-		//		Deceased:            &apiv1.Patient_DeceasedDate{DeceasedDate: dob},
-		Surgery:             "W95010",
-		GeneralPractitioner: "G9342400",
-		Identifiers: []*apiv1.Identifier{
-			{
-				System: authority.empiOrganisationCode(),
-				Value:  identifier,
-			},
-			{
-				System: "103",
-				Value:  "M1147907",
-			},
-			{
-				System: identifiers.CardiffAndValeCRN,
-				Value:  "X234567",
-			},
-			{
-				System: identifiers.NHSNumber,
-				Value:  "1111111111",
-			},
-		},
-
-		Addresses: []*apiv1.Address{
-			{
-				Address1: "59 Robins Hill",
-				Address2: "Brackla",
-				Address3: "Bridgend",
-				Postcode: "CF31 2PJ",
-				Country:  "WALES",
-			},
-		},
-		Telephones: []*apiv1.Telephone{
-			{
-				Number:      "02920 747747",
-				Description: "Home",
-			},
-			{
-				Number:      "02920 711711",
-				Description: "Mobile",
-			},
-		},
-		Emails: []string{"test@test.com", "wibble@test.com"},
-	}, nil
+	return pt
 }
 
-func performRequest(context context.Context, endpointURL string, processingID string, authority Authority, identifier string) (*apiv1.Patient, error) {
-	start := time.Now()
+func performRequest(context context.Context, client *http.Client, endpointURL string, processingID string, authority Authority, identifier string) (*apiv1.Patient, error) {
 	data, err := NewIdentifierRequest(strings.ToUpper(identifier), authority, "221", "100", processingID)
 	if err != nil {
 		return nil, err
 	}
+	e, err := performSOAP(context, client, endpointURL, data)
+	if err != nil {
+		return nil, err
+	}
+	return e.ToPatient()
+}
+
+// performSOAP sends a patient demographics query to the EMPI and parses the response
+func performSOAP(context context.Context, client *http.Client, endpointURL string, data []byte) (*envelope, error) {
+	start := time.Now()
 	req, err := http.NewRequestWithContext(context, "POST", endpointURL, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-type", "text/xml; charset=\"utf-8\"")
 	req.Header.Set("SOAPAction", "http://apps.wales.nhs.uk/mpi/InvokePatientDemographicsQuery")
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 	var e envelope
 	log.Printf("empi: response (%s): %v", time.Since(start), string(body))
 	err = xml.Unmarshal(body, &e)
 	if err != nil {
 		return nil, err
 	}
-	return e.ToPatient()
+	if fault := e.Body.Fault; fault != nil {
+		metrics.SOAPFault("empi")
+		return nil, status.Errorf(codes.Internal, "empi: soap fault: %s: %s", fault.Faultcode, fault.Faultstring)
+	}
+	return &e, nil
 }
 
 // IdentifierRequest is used to populate the template to make the XML request
@@ -258,16 +346,36 @@ func NewIdentifierRequest(identifier string, authority Authority, sender string,
 	return buf.Bytes(), nil
 }
 
-// ToPatient creates a "Patient" from the XML returned from the EMPI service
+// ToPatient creates a "Patient" from the XML returned from the EMPI service, using the first result
 func (e *envelope) ToPatient() (*apiv1.Patient, error) {
+	responses := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE
+	if len(responses) == 0 {
+		return nil, nil
+	}
+	return responses[0].toPatient(), nil
+}
+
+// ToPatients creates a list of "Patient"s from the XML returned from the EMPI service
+func (e *envelope) ToPatients() []*apiv1.Patient {
+	result := make([]*apiv1.Patient, 0)
+	for _, qr := range e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE {
+		if pt := qr.toPatient(); pt != nil {
+			result = append(result, pt)
+		}
+	}
+	return result
+}
+
+// toPatient creates a "Patient" from a single result, or nil if the result is empty
+func (qr *queryResponse) toPatient() *apiv1.Patient {
 	pt := new(apiv1.Patient)
-	pt.Lastname = e.surname()
-	pt.Firstnames = e.firstnames()
+	pt.Lastname = qr.surname()
+	pt.Firstnames = qr.firstnames()
 	if pt.Lastname == "" && pt.Firstnames == "" {
-		return nil, nil
+		return nil
 	}
-	pt.Title = e.title()
-	switch e.gender() {
+	pt.Title = qr.title()
+	switch qr.gender() {
 	case "M":
 		pt.Gender = apiv1.Gender_MALE
 	case "F":
@@ -275,29 +383,31 @@ func (e *envelope) ToPatient() (*apiv1.Patient, error) {
 	default:
 		pt.Gender = apiv1.Gender_UNKNOWN
 	}
-	pt.BirthDate = e.dateBirth()
-	if dd := e.dateDeath(); dd != nil {
+	pt.BirthDate = qr.dateBirth()
+	if dd := qr.dateDeath(); dd != nil {
 		pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: dd}
 	}
-	pt.Identifiers = e.identifiers()
-	pt.Addresses = e.addresses()
-	pt.Surgery = e.surgery()
-	pt.GeneralPractitioner = e.generalPractitioner()
-	pt.Telephones = e.telephones()
-	pt.Emails = e.emails()
-	return pt, nil
+	pt.Identifiers = qr.identifiers()
+	pt.Addresses = qr.addresses()
+	pt.Surgery = identifiers.New(identifiers.ODSCode, qr.surgery())
+	pt.GeneralPractitioner = identifiers.New(identifiers.GMPNumber, qr.generalPractitioner())
+	pt.Telephones = qr.telephones()
+	pt.Emails = qr.emails()
+	pt.NhsNumberVerificationStatus = qr.nhsNumberVerificationStatus()
+	pt.PreferredLanguage = qr.preferredLanguage()
+	return pt
 }
 
-func (e *envelope) surname() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) surname() string {
+	names := qr.PID.PID5
 	if len(names) > 0 {
 		return names[0].XPN1.FN1.Text
 	}
 	return ""
 }
 
-func (e *envelope) firstnames() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) firstnames() string {
+	names := qr.PID.PID5
 	var sb strings.Builder
 	if len(names) > 0 {
 		sb.WriteString(names[0].XPN2.Text) // given name - XPN.2
@@ -307,51 +417,69 @@ func (e *envelope) firstnames() string {
 	return strings.TrimSpace(sb.String())
 }
 
-func (e *envelope) title() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) title() string {
+	names := qr.PID.PID5
 	if len(names) > 0 {
 		return names[0].XPN5.Text
 	}
 	return ""
 }
 
-func (e *envelope) gender() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID8.Text
+func (qr *queryResponse) gender() string {
+	return qr.PID.PID8.Text
 }
 
-func (e *envelope) dateBirth() *timestamp.Timestamp {
-	dob := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID7.TS1.Text
-	if len(dob) > 0 {
-		d, err := parseDate(dob)
-		if err == nil {
-			return d
-		}
+func (qr *queryResponse) dateBirth() *apiv1.Date {
+	t, err := hl7time.Parse(qr.PID.PID7.TS1.Text)
+	if err != nil {
+		log.Printf("empi: invalid date of birth: %s", err)
+		return nil
 	}
-	return nil
+	return apiv1.NewDate(t.Date().Time)
 }
 
-func (e *envelope) dateDeath() *timestamp.Timestamp {
-	dod := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID29.TS1.Text
-	if len(dod) > 0 {
-		d, err := parseDate(dod)
-		if err == nil {
-			return d
-		}
+func (qr *queryResponse) dateDeath() *timestamppb.Timestamp {
+	d, err := parseDate(qr.PID.PID29.TS1.Text)
+	if err != nil {
+		log.Printf("empi: invalid date of death: %s", err)
 	}
-	return nil
+	return d
+}
+
+func (qr *queryResponse) surgery() string {
+	return qr.PD1.PD13.XON3.Text
+}
+
+func (qr *queryResponse) generalPractitioner() string {
+	return qr.PD1.PD14.XCN1.Text
 }
 
-func (e *envelope) surgery() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PD1.PD13.XON3.Text
+// nhsNumberVerificationStatus returns the NHS number status indicator from the identity reliability
+// code (PID.32), or from the identifier type code (CX.5) of the NHS number, if not specified
+func (qr *queryResponse) nhsNumberVerificationStatus() apiv1.NHSNumberVerificationStatus {
+	for _, code := range qr.PID.PID32 {
+		if status := apiv1.ParseNHSNumberVerificationStatus(code.Text); status != apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN {
+			return status
+		}
+	}
+	for _, id := range qr.PID.PID3 {
+		if Authority(AuthorityNHS).empiOrganisationCode() == id.CX4.HD1.Text {
+			if status := apiv1.ParseNHSNumberVerificationStatus(id.CX5.Text); status != apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN {
+				return status
+			}
+		}
+	}
+	return apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN
 }
 
-func (e *envelope) generalPractitioner() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PD1.PD14.XCN1.Text
+// preferredLanguage returns the patient's primary language (PID.15) as a BCP 47 language tag
+func (qr *queryResponse) preferredLanguage() string {
+	return i18n.ParseLanguage(qr.PID.PID15.CE1.Text)
 }
 
-func (e *envelope) identifiers() []*apiv1.Identifier {
+func (qr *queryResponse) identifiers() []*apiv1.Identifier {
 	result := make([]*apiv1.Identifier, 0)
-	ids := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID3
+	ids := qr.PID.PID3
 	for _, id := range ids {
 		authority := id.CX4.HD1.Text
 		identifier := id.CX1.Text
@@ -369,18 +497,22 @@ func (e *envelope) identifiers() []*apiv1.Identifier {
 	return result
 }
 
-func (e *envelope) addresses() []*apiv1.Address {
+// addresses returns the patient's addresses, mapping each component of XAD. The Welsh EMPI uses XAD-4
+// (state or province) for county.
+func (qr *queryResponse) addresses() []*apiv1.Address {
 	result := make([]*apiv1.Address, 0)
-	addresses := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID11
+	addresses := qr.PID.PID11
 	for _, address := range addresses {
 		dateFrom, _ := parseDate(address.XAD13.Text)
 		dateTo, _ := parseDate(address.XAD14.Text)
 		result = append(result, &apiv1.Address{
-			Address1: address.XAD1.SAD1.Text,
-			Address2: address.XAD2.Text,
-			Address3: address.XAD3.Text,
-			Country:  address.XAD4.Text,
-			Postcode: address.XAD5.Text,
+			Address1: streetAddress(address.XAD1.Text, address.XAD1.SAD1.Text, address.XAD1.SAD2.Text, address.XAD1.SAD3.Text),
+			Address2: strings.TrimSpace(address.XAD2.Text),
+			Address3: strings.TrimSpace(address.XAD3.Text),
+			County:   strings.TrimSpace(address.XAD4.Text),
+			Postcode: strings.TrimSpace(address.XAD5.Text),
+			Country:  strings.TrimSpace(address.XAD6.Text),
+			Use:      apiv1.ParseAddressUse(address.XAD7.Text),
 			Period: &apiv1.Period{
 				Start: dateFrom,
 				End:   dateTo,
@@ -390,44 +522,67 @@ func (e *envelope) addresses() []*apiv1.Address {
 	return result
 }
 
-func (e *envelope) telephones() []*apiv1.Telephone {
+// streetAddress returns the street address (XAD-1), which may be sent as text, or as components: the street or
+// mailing address (SAD-1) or, if absent, the dwelling number (SAD-3) and street name (SAD-2)
+func streetAddress(text, address, street, dwelling string) string {
+	if address = strings.TrimSpace(address); address != "" {
+		return address
+	}
+	if s := strings.Join(strings.Fields(dwelling+" "+street), " "); s != "" {
+		return s
+	}
+	return strings.TrimSpace(text)
+}
+
+// telephones returns home (PID-13) and business (PID-14) telephone numbers, using the use code (XTN-2) and
+// equipment type (XTN-3) to determine their use, if present. The LongName attribute is not a description
+// of the number, but simply the label for the field in the HL7 schema.
+func (qr *queryResponse) telephones() []*apiv1.Telephone {
 	result := make([]*apiv1.Telephone, 0)
-	pid13 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID13
+	pid13 := qr.PID.PID13
 	for _, telephone := range pid13 {
 		num := telephone.XTN1.Text
 		if num != "" {
 			result = append(result, &apiv1.Telephone{
-				Number:      num,
-				Description: telephone.LongName,
+				Number: num,
+				Use:    telephoneUse(telephone.XTN2.Text, telephone.XTN3.Text, apiv1.Telephone_HOME),
 			})
 		}
 	}
-	pid14 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID14
+	pid14 := qr.PID.PID14
 	for _, telephone := range pid14 {
 		num := telephone.XTN1.Text
 		if num != "" {
 			result = append(result, &apiv1.Telephone{
-				Number:      num,
-				Description: telephone.LongName,
+				Number: num,
+				Use:    telephoneUse(telephone.XTN2.Text, telephone.XTN3.Text, apiv1.Telephone_WORK),
 			})
 		}
 	}
 	return result
 }
 
+// telephoneUse returns the use of a telephone from its use code and equipment type, or the default if neither is recognised
+func telephoneUse(useCode, equipmentType string, def apiv1.Telephone_Use) apiv1.Telephone_Use {
+	if use := apiv1.ParseTelephoneUse(useCode, equipmentType); use != apiv1.Telephone_UNKNOWN {
+		return use
+	}
+	return def
+}
+
 // sanity check for emails
 var rxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
-func (e *envelope) emails() []string {
+func (qr *queryResponse) emails() []string {
 	result := make([]string, 0)
-	pid13 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID13
+	pid13 := qr.PID.PID13
 	for _, telephone := range pid13 {
 		email := telephone.XTN4.Text
 		if email != "" && len(email) < 255 && rxEmail.MatchString(email) {
 			result = append(result, email)
 		}
 	}
-	pid14 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID14
+	pid14 := qr.PID.PID14
 	for _, telephone := range pid14 {
 		email := telephone.XTN4.Text
 		if email != "" && len(email) < 255 && rxEmail.MatchString(email) {
@@ -437,16 +592,14 @@ func (e *envelope) emails() []string {
 	return result
 }
 
-func parseDate(d string) (*timestamp.Timestamp, error) {
-	layout := "20060102" // reference date is : Mon Jan 2 15:04:05 MST 2006
-	if len(d) > 8 {
-		d = d[:8]
-	}
-	t, err := time.Parse(layout, d)
-	if err != nil || t.IsZero() {
+// parseDate parses an HL7 date or timestamp, returning only the date, as for dates of birth and death,
+// or nil if absent or invalid
+func parseDate(d string) (*timestamppb.Timestamp, error) {
+	t, err := hl7time.Parse(d)
+	if err != nil {
 		return nil, err
 	}
-	return ptypes.TimestampProto(t)
+	return t.Date().Timestamp(), nil
 }
 
 var identifierRequestTemplate = `
@@ -548,7 +701,7 @@ var identifierRequestTemplate = `
 // See https://hl7-definition.caristix.com/v2/HL7v2.5.1/Segments/PID
 // which documents that the following can be repeated: PID3 PID4 PID5 PID6 PID9 PID10 PID11 PID13 PID14 PID21 PID22 PID26 PID32
 // Therefore, these have been manually added as []struct rather than struct.
-// Also, added PID.29 for date of death
+// Also, added PID.29 for date of death, and PID.32 for identity reliability (NHS number verification status)
 type envelope struct {
 	XMLName xml.Name `xml:"Envelope"`
 	Text    string   `xml:",chardata"`
@@ -556,7 +709,11 @@ type envelope struct {
 	Xsi     string   `xml:"xsi,attr"`
 	Xsd     string   `xml:"xsd,attr"`
 	Body    struct {
-		Text                                   string `xml:",chardata"`
+		Text  string `xml:",chardata"`
+		Fault *struct {
+			Faultcode   string `xml:"faultcode"`
+			Faultstring string `xml:"faultstring"`
+		} `xml:"Fault"`
 		InvokePatientDemographicsQueryResponse struct {
 			Text   string `xml:",chardata"`
 			Xmlns  string `xml:"xmlns,attr"`
@@ -782,316 +939,354 @@ type envelope struct {
 						} `xml:"QIP.2"`
 					} `xml:"QPD.3"`
 				} `xml:"QPD"`
-				RSPK21QUERYRESPONSE struct {
-					Text string `xml:",chardata"`
-					PID  struct {
-						Text string `xml:",chardata"`
-						PID1 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.1"`
-						PID3 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							CX1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CX.1"`
-							CX4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-								HD1      struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									Table    string `xml:"Table,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"HD.1"`
-							} `xml:"CX.4"`
-							CX5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CX.5"`
-						} `xml:"PID.3"`
-						PID5 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XPN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-								FN1      struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"FN.1"`
-							} `xml:"XPN.1"`
-							XPN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.2"`
-							XPN3 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.3"`
-							XPN5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.5"`
-							XPN7 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.7"`
-						} `xml:"PID.5"`
-						PID7 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							TS1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"TS.1"`
-						} `xml:"PID.7"`
-						PID8 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.8"`
-						PID9 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XPN7     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.7"`
-						} `xml:"PID.9"`
-						PID11 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XAD1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-								SAD1     struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"SAD.1"`
-							} `xml:"XAD.1"`
-							XAD2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.2"`
-							XAD3 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.3"`
-							XAD4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.4"`
-							XAD5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.5"`
-							XAD7 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.7"`
-							XAD13 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.13"`
-							XAD14 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.14"`
-						} `xml:"PID.11"`
-						PID13 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XTN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.1"`
-							XTN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.2"`
-							XTN4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.4"`
-						} `xml:"PID.13"`
-						PID14 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XTN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.1"`
-							XTN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.2"`
-							XTN4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.4"`
-						} `xml:"PID.14"`
-						PID15 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.15"`
-						PID16 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.16"`
-						PID17 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.17"`
-						PID22 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.22"`
-						PID24 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.24"`
-						PID28 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.28"`
-						PID29 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							TS1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"TS.1"`
-						} `xml:"PID.29"`
-					} `xml:"PID"`
-					PD1 struct {
-						Text string `xml:",chardata"`
-						PD13 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XON3     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XON.3"`
-						} `xml:"PD1.3"`
-						PD14 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XCN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XCN.1"`
-						} `xml:"PD1.4"`
-					} `xml:"PD1"`
-				} `xml:"RSP_K21.QUERY_RESPONSE"`
+				RSPK21QUERYRESPONSE []queryResponse `xml:"RSP_K21.QUERY_RESPONSE"`
 			} `xml:"RSP_K21"`
 		} `xml:"InvokePatientDemographicsQueryResponse"`
 	} `xml:"Body"`
 }
+
+// queryResponse is a single result from a patient demographics query. A query by identifier will
+// generally return a single result, but a demographic search may return multiple results.
+type queryResponse struct {
+	Text string `xml:",chardata"`
+	PID  struct {
+		Text string `xml:",chardata"`
+		PID1 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.1"`
+		PID3 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			CX1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CX.1"`
+			CX4 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+				HD1      struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					Table    string `xml:"Table,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"HD.1"`
+			} `xml:"CX.4"`
+			CX5 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CX.5"`
+		} `xml:"PID.3"`
+		PID5 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XPN1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+				FN1      struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"FN.1"`
+			} `xml:"XPN.1"`
+			XPN2 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XPN.2"`
+			XPN3 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XPN.3"`
+			XPN5 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XPN.5"`
+			XPN7 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XPN.7"`
+		} `xml:"PID.5"`
+		PID7 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			TS1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"TS.1"`
+		} `xml:"PID.7"`
+		PID8 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.8"`
+		PID9 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XPN7     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XPN.7"`
+		} `xml:"PID.9"`
+		PID11 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XAD1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+				SAD1     struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"SAD.1"`
+				SAD2 struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"SAD.2"`
+				SAD3 struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"SAD.3"`
+			} `xml:"XAD.1"`
+			XAD2 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.2"`
+			XAD3 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.3"`
+			XAD4 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.4"`
+			XAD5 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.5"`
+			XAD6 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.6"`
+			XAD7 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.7"`
+			XAD13 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.13"`
+			XAD14 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XAD.14"`
+		} `xml:"PID.11"`
+		PID13 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XTN1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.1"`
+			XTN2 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.2"`
+			XTN3 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.3"`
+			XTN4 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.4"`
+		} `xml:"PID.13"`
+		PID14 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XTN1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.1"`
+			XTN2 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.2"`
+			XTN3 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.3"`
+			XTN4 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XTN.4"`
+		} `xml:"PID.14"`
+		PID15 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.15"`
+		PID16 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.16"`
+		PID17 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.17"`
+		PID22 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.22"`
+		PID24 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.24"`
+		PID28 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.28"`
+		PID29 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			TS1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"TS.1"`
+		} `xml:"PID.29"`
+		PID32 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.32"`
+	} `xml:"PID"`
+	PD1 struct {
+		Text string `xml:",chardata"`
+		PD13 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XON3     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XON.3"`
+		} `xml:"PD1.3"`
+		PD14 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XCN1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XCN.1"`
+		} `xml:"PD1.4"`
+	} `xml:"PD1"`
+}
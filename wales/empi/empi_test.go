@@ -0,0 +1,266 @@
+package empi
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>1111111111</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NH</CX.5></PID.3><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1><XPN.2>ALBERT</XPN.2></PID.5><PID.8>M</PID.8><PID.15><CE.1>WEL</CE.1></PID.15></PID></RSP_K21.QUERY_RESPONSE>
+</RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+
+func newTestServer(connections *int32) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, testResponse)
+	}))
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(connections, 1)
+		}
+	}
+	ts.StartTLS()
+	return ts
+}
+
+func TestConnectionReuse(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	var connections int32
+	ts := newTestServer(&connections)
+	defer ts.Close()
+	app := &App{
+		EndpointURL:    ts.URL,
+		TimeoutSeconds: 5,
+		Transport:      transport.NewHTTPTransport(transport.HTTPOptions{TLSConfig: ts.Client().Transport.(*http.Transport).TLSClientConfig}),
+	}
+	for i := 0; i < 10; i++ {
+		pt, err := app.GetInternalEMPIRequest(context.Background(), &apiv1.Identifier{System: Authority(AuthorityNHS).empiOrganisationCode(), Value: "1111111111"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.GetFirstnames() != "ALBERT" || pt.GetPreferredLanguage() != "cy" {
+			t.Fatalf("unexpected patient: %v", pt)
+		}
+	}
+	if connections != 1 {
+		t.Fatalf("expected connection to be reused, got %d connections", connections)
+	}
+}
+
+// BenchmarkTransport compares latency under concurrent load using a transport shared by all requests,
+// with connections and TLS sessions reused, with that using a new connection for each request.
+// Latency at the 95th percentile is reported as p95-ms.
+func BenchmarkTransport(b *testing.B) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	var connections int32
+	ts := newTestServer(&connections)
+	defer ts.Close()
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	shared := transport.NewClient("empi-benchmark", transport.NewHTTPTransport(transport.HTTPOptions{MaxIdleConnsPerHost: 64, TLSConfig: tlsConfig}), true)
+	b.Run("shared", func(b *testing.B) {
+		benchmarkTransport(b, ts.URL, func() (*http.Client, func()) { return shared, func() {} })
+	})
+	b.Run("per-request", func(b *testing.B) {
+		benchmarkTransport(b, ts.URL, func() (*http.Client, func()) {
+			t := transport.NewHTTPTransport(transport.HTTPOptions{TLSConfig: tlsConfig})
+			return transport.NewClient("empi-benchmark", t, true), t.CloseIdleConnections
+		})
+	})
+}
+
+func benchmarkTransport(b *testing.B, endpointURL string, client func() (*http.Client, func())) {
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, b.N)
+	b.SetParallelism(8)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c, done := client()
+			start := time.Now()
+			_, err := performRequest(context.Background(), c, endpointURL, "T", AuthorityNHS, "1111111111")
+			d := time.Since(start)
+			done()
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			mu.Lock()
+			latencies = append(latencies, d)
+			mu.Unlock()
+		}
+	})
+	b.StopTimer()
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		b.ReportMetric(float64(latencies[len(latencies)*95/100])/float64(time.Millisecond), "p95-ms")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := map[string]time.Time{
+		"19600131":              time.Date(1960, 1, 31, 0, 0, 0, 0, time.UTC),
+		"196001310030+0100":     time.Date(1960, 1, 31, 0, 0, 0, 0, time.UTC), // date in its own offset, not UTC
+		"19600131235959":        time.Date(1960, 1, 31, 0, 0, 0, 0, time.UTC),
+		"196001":                time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+		"19600131120000.5-0500": time.Date(1960, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for s, expected := range tests {
+		if d, err := parseDate(s); err != nil || d.GetSeconds() != expected.Unix() {
+			t.Errorf("'%s': expected %s, got %v (%v)", s, expected, d, err)
+		}
+	}
+	if d, err := parseDate(""); err != nil || d != nil {
+		t.Errorf("expected empty date to be nil, got %v (%v)", d, err)
+	}
+	if _, err := parseDate("1960013"); err == nil {
+		t.Error("expected error for invalid date")
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>1111111111</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NH</CX.5></PID.3>
+<PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1></PID.5>
+<PID.11><XAD.1><SAD.1>1 Station Road</SAD.1></XAD.1><XAD.2>Heath</XAD.2><XAD.3>Cardiff</XAD.3><XAD.4>South Glamorgan</XAD.4><XAD.5>CF14 4XW</XAD.5><XAD.6>WALES</XAD.6><XAD.7>H</XAD.7></PID.11>
+<PID.11><XAD.1><SAD.2>Heol y Nant</SAD.2><SAD.3>12</SAD.3></XAD.1><XAD.3>Bridgend</XAD.3><XAD.7>C</XAD.7></PID.11>
+<PID.11><XAD.1>59 Robins Hill</XAD.1><XAD.7>M</XAD.7></PID.11>
+</PID></RSP_K21.QUERY_RESPONSE></RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+	var e envelope
+	if err := xml.Unmarshal([]byte(body), &e); err != nil {
+		t.Fatal(err)
+	}
+	pt, err := e.ToPatient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addresses := pt.GetAddresses()
+	if len(addresses) != 3 {
+		t.Fatalf("expected three addresses, got %v", addresses)
+	}
+	home := addresses[0]
+	if home.GetAddress1() != "1 Station Road" || home.GetAddress2() != "Heath" || home.GetAddress3() != "Cardiff" || home.GetCounty() != "South Glamorgan" ||
+		home.GetPostcode() != "CF14 4XW" || home.GetCountry() != "WALES" || home.GetUse() != apiv1.Address_HOME {
+		t.Errorf("unexpected home address: %v", home)
+	}
+	if lines := home.Lines(); len(lines) != 4 || lines[3] != "South Glamorgan" {
+		t.Errorf("unexpected address lines: %v", lines)
+	}
+	if a := addresses[1]; a.GetAddress1() != "12 Heol y Nant" || a.GetUse() != apiv1.Address_TEMPORARY {
+		t.Errorf("unexpected temporary address: %v", a)
+	}
+	if a := addresses[2]; a.GetAddress1() != "59 Robins Hill" || a.GetUse() != apiv1.Address_CORRESPONDENCE {
+		t.Errorf("unexpected correspondence address: %v", a)
+	}
+}
+
+func TestTelephones(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>1111111111</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NH</CX.5></PID.3>
+<PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1></PID.5>
+<PID.13 LongName="Phone Number - Home"><XTN.1>02920 747747</XTN.1><XTN.2>PRN</XTN.2><XTN.3>PH</XTN.3></PID.13>
+<PID.13 LongName="Phone Number - Home"><XTN.1>07700 900000</XTN.1><XTN.2>PRN</XTN.2><XTN.3>CP</XTN.3></PID.13>
+<PID.14 LongName="Phone Number - Business"><XTN.1>02920 711711</XTN.1></PID.14>
+</PID></RSP_K21.QUERY_RESPONSE></RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+	var e envelope
+	if err := xml.Unmarshal([]byte(body), &e); err != nil {
+		t.Fatal(err)
+	}
+	pt, err := e.ToPatient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []apiv1.Telephone_Use{apiv1.Telephone_HOME, apiv1.Telephone_MOBILE, apiv1.Telephone_WORK}
+	telephones := pt.GetTelephones()
+	if len(telephones) != len(expected) {
+		t.Fatalf("expected %d telephones, got %v", len(expected), telephones)
+	}
+	for i, tel := range telephones {
+		if tel.GetUse() != expected[i] || tel.GetDescription() != "" {
+			t.Errorf("unexpected telephone: %v", tel)
+		}
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	var requests int32
+	aborted := make(chan struct{}, 1)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		ioutil.ReadAll(r.Body) // the server notices the client has gone only once the request has been read
+		select {
+		case <-r.Context().Done():
+			aborted <- struct{}{}
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+	app := &App{EndpointURL: ts.URL, TimeoutSeconds: 30}
+	id := &apiv1.Identifier{System: Authority(AuthorityNHS).empiOrganisationCode(), Value: "1111111111"}
+
+	// the outbound call stops promptly when the caller gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := app.GetInternalEMPIRequest(ctx, id); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected cancelled. got: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected call to stop promptly once cancelled. took: %s", d)
+	}
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("expected outbound request to be abandoned once cancelled")
+	}
+
+	// the caller's deadline applies if earlier than the timeout for the backend
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, err := app.GetInternalEMPIRequest(ctx, id); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded. got: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected call to stop at caller's deadline. took: %s", d)
+	}
+
+	// no call is made if there is insufficient time remaining before the caller's deadline
+	transport.SetMinDeadline("empi", time.Second)
+	defer transport.SetMinDeadline("empi", 0)
+	atomic.StoreInt32(&requests, 0)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := app.GetInternalEMPIRequest(ctx, id); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded. got: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("expected no outbound request. got: %d", n)
+	}
+}
@@ -2,7 +2,8 @@ package empi
 
 import (
 	"strings"
-	"unicode"
+
+	"github.com/wardle/concierge/identifiers/validate"
 )
 
 // IsValidNHSNumber validates an NHS number
@@ -12,36 +13,11 @@ func IsValidNHSNumber(nnn string) bool {
 	return valid
 }
 
-// ValidateNHSNumber validates the specified identifier, removing spaces should they be present, removing that sanitised
-// identifier.
-// Note: This does not check for repeated (and supposedly invalid) NHS numbers such as 4444444444 and 6666666666
-// This is only an issue for NHS number generation and not the validation we have here.
+// ValidateNHSNumber validates the specified identifier, removing spaces should they be present, returning that sanitised
+// identifier. See validate.NHSNumber.
 func ValidateNHSNumber(nnn string) (bool, string) {
-	var err error
-	nnn = strings.ReplaceAll(nnn, " ", "")
-	if nnn == "" || len(nnn) != 10 {
-		return false, ""
-	}
-
-	nni := make([]int, 10)
-	sum, cd := 0, 0
-	for i, c := range nnn {
-		if unicode.IsDigit(c) == false {
-			return false, ""
-		}
-		nni[i] = int(c - '0')
-		if err != nil {
-			return false, ""
-		}
-		if i < 9 {
-			sum += nni[i] * (10 - i)
-		}
-	}
-	cd = 11 - (sum % 11)
-	if cd == 11 {
-		cd = 0
-	}
-	return cd != 10 && cd == nni[9], nnn
+	nnn, err := validate.NHSNumber(nnn)
+	return err == nil, nnn
 }
 
 // FormatNHSNumber returns a formatted NHS number with spaces
@@ -0,0 +1,26 @@
+package empi
+
+import (
+	"regexp"
+
+	"github.com/wardle/concierge/transport"
+)
+
+// recording defines how interactions with the EMPI are recorded and replayed. Requests differ by the
+// time and identifier of each message, and responses are redacted of names, addresses, contact details
+// and identifiers, with dates of birth replaced by the first of January of the same year.
+var recording = transport.Recording{
+	Volatile: []*regexp.Regexp{
+		regexp.MustCompile(`<MSH\.7>[\s\S]*?</MSH\.7>`),
+		regexp.MustCompile(`<MSH\.10>[^<]*</MSH\.10>`),
+	},
+	Redact: append(transport.RedactXML("FN.1", "XPN.2", "XPN.3", "XAD.1", "SAD.1", "SAD.2", "SAD.3", "XAD.2", "XAD.3", "XAD.4", "XAD.5", "XTN.1", "XTN.4", "CX.1"),
+		transport.Redaction{
+			Pattern:     regexp.MustCompile(`(<PID\.7(?:\s[^>]*)?>\s*<TS\.1(?:\s[^>]*)?>\d{4})\d{4}`),
+			Replacement: "${1}0101",
+		}),
+}
+
+func init() {
+	transport.SetRecording("empi", recording)
+}
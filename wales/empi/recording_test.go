@@ -0,0 +1,66 @@
+package empi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/transport"
+)
+
+const testRecordedResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>1111111111</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NH</CX.5></PID.3>
+<PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1><XPN.2>ALBERT</XPN.2></PID.5><PID.7><TS.1>19600615</TS.1></PID.7><PID.8>M</PID.8>
+<PID.11><XAD.1>59 ROBINS HILL</XAD.1><XAD.3>BRIDGEND</XAD.3><XAD.5>CF31 2PJ</XAD.5></PID.11>
+<PID.13><XTN.1>02920 747747</XTN.1></PID.13></PID></RSP_K21.QUERY_RESPONSE>
+</RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+
+func TestRecordReplay(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, testRecordedResponse)
+	}))
+	dir, err := ioutil.TempDir("", "empi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer transport.SetMode(transport.Live, "")
+	id := &apiv1.Identifier{System: Authority(AuthorityNHS).empiOrganisationCode(), Value: "1111111111"}
+	transport.SetMode(transport.Record, dir)
+	pt, err := (&App{EndpointURL: ts.URL, TimeoutSeconds: 5}).GetInternalEMPIRequest(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.GetLastname() != "DUMMY" {
+		t.Fatalf("expected unredacted patient when recording, got %v", pt)
+	}
+	ts.Close()
+	transport.SetMode(transport.Replay, dir)
+	if pt, err = (&App{EndpointURL: ts.URL, TimeoutSeconds: 5}).GetInternalEMPIRequest(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if pt.GetLastname() != "REDACTED" || pt.GetFirstnames() != "REDACTED" || pt.GetGender() != apiv1.Gender_MALE {
+		t.Errorf("expected redacted names, got %v", pt)
+	}
+	if pt.GetBirthDate().Format("2006-01-02") != "1960-01-01" {
+		t.Errorf("expected date of birth to be redacted to year of birth, got %v", pt.GetBirthDate())
+	}
+	if len(pt.GetAddresses()) != 1 || pt.GetAddresses()[0].GetPostcode() != "REDACTED" {
+		t.Errorf("expected redacted address, got %v", pt.GetAddresses())
+	}
+	for _, tel := range pt.GetTelephones() {
+		if tel.GetNumber() != "REDACTED" {
+			t.Errorf("expected redacted telephone, got %v", tel)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package empi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchPatient performs a demographic search (IHE PDQ) against the EMPI, returning matching patients.
+// A last name, and at least one other search criterion, must be specified.
+func (app *App) SearchPatient(ctx context.Context, r *apiv1.PatientSearchRequest) (pts []*apiv1.Patient, err error) {
+	defer metrics.Observe("empi", "search", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "empi.search")
+	defer tracing.End(ctx, span, &err)
+	ucd := server.GetContextData(ctx)
+	log.Printf("empi: search from '%s|%s': %+v", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), r)
+	if strings.TrimSpace(r.GetLastname()) == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "patient search requires a last name")
+	}
+	if strings.TrimSpace(r.GetFirstnames()) == "" && r.GetBirthDate() == nil && r.GetGender() == apiv1.Gender_UNKNOWN && strings.TrimSpace(r.GetPostcode()) == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "patient search requires at least one of first names, date of birth, gender or postcode")
+	}
+	if r.GetBirthDate() != nil && !r.GetBirthDate().IsValid() {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "invalid date of birth: %d-%02d-%02d", r.GetBirthDate().GetYear(), r.GetBirthDate().GetMonth(), r.GetBirthDate().GetDay())
+	}
+	if app.Fake {
+		return simulator.Current().SearchPatients(simulator.EMPI, r), nil
+	}
+	data, err := NewDemographicRequest(r, "221", "100", app.processingID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	timeout := app.TimeoutSeconds
+	if timeout == 0 {
+		timeout = 1
+	}
+	reqCtx, cancelFunc, err := transport.WithDeadline(ctx, "empi", time.Duration(timeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer cancelFunc()
+	e, err := performSOAP(reqCtx, app.httpClient(), app.EndpointURL, data)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if urlError, ok := err.(*url.Error); ok && urlError.Timeout() {
+			return nil, i18n.Errorf(ctx, codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", app.TimeoutSeconds)
+		}
+		return nil, err
+	}
+	return e.ToPatients(), nil
+}
+
+// DemographicRequest is used to populate the template to make the XML request for a demographic search
+type DemographicRequest struct {
+	Lastname             string
+	Firstnames           string
+	BirthDate            string // YYYYMMDD
+	Gender               string // M / F
+	Postcode             string
+	SendingApplication   string
+	SendingFacility      string
+	ReceivingApplication string
+	ReceivingFacility    string
+	DateTime             string
+	MessageControlID     string //for MSH.10 -  a UUID
+	ProcessingID         string //for MSH.11 - P/U/T production/testing/development
+}
+
+// NewDemographicRequest returns a correctly formatted XML request to search by demographics
+// sender : 221 (PatientCare)
+// receiver: 100 (NHS Wales EMPI)
+func NewDemographicRequest(r *apiv1.PatientSearchRequest, sender string, receiver string, processingID string) ([]byte, error) {
+	layout := "20060102150405" // YYYYMMDDHHMMSS
+	data := DemographicRequest{
+		Lastname:             strings.ToUpper(strings.TrimSpace(r.GetLastname())),
+		Firstnames:           strings.ToUpper(strings.TrimSpace(r.GetFirstnames())),
+		Postcode:             strings.ToUpper(strings.TrimSpace(r.GetPostcode())),
+		SendingApplication:   sender,
+		SendingFacility:      sender,
+		ReceivingApplication: receiver,
+		ReceivingFacility:    receiver,
+		DateTime:             time.Now().Format(layout),
+		MessageControlID:     uuid.New().String(),
+		ProcessingID:         processingID,
+	}
+	if r.GetBirthDate() != nil {
+		if !r.GetBirthDate().IsValid() {
+			return nil, fmt.Errorf("empi: invalid date of birth: %v", r.GetBirthDate())
+		}
+		data.BirthDate = r.GetBirthDate().Format("20060102")
+	}
+	switch r.GetGender() {
+	case apiv1.Gender_MALE:
+		data.Gender = "M"
+	case apiv1.Gender_FEMALE:
+		data.Gender = "F"
+	}
+	t, err := template.New("demographic-request").Parse(demographicRequestTemplate)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("empi request: %+v", data)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var demographicRequestTemplate = `
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:mpi="http://apps.wales.nhs.uk/mpi/" xmlns="urn:hl7-org:v2xml">
+<soapenv:Header/>
+<soapenv:Body>
+   <mpi:InvokePatientDemographicsQuery>
+
+	  <QBP_Q21>
+
+		 <MSH>
+			 <!--Field Separator -->
+			<MSH.1>|</MSH.1>
+			<!-- Encoding Characters -->
+			<MSH.2>^~\&amp;</MSH.2>
+			<!-- Sending Application -->
+			<MSH.3 >
+			   <HD.1>{{.SendingApplication}}</HD.1>
+			</MSH.3>
+			<!-- Sending Facility -->
+			<MSH.4 >
+			   <HD.1>{{.SendingFacility}}</HD.1>
+			</MSH.4>
+			<!-- Receiving Application -->
+			<MSH.5>
+			   <HD.1>{{.ReceivingApplication}}</HD.1>
+			</MSH.5>
+			<!-- Receiving Application -->
+			<MSH.6>
+			   <HD.1>{{.ReceivingFacility}}</HD.1>
+			</MSH.6>
+			<!-- Date / Time of message YYYYMMDDHHMMSS -->
+			<MSH.7>
+			   <TS.1>{{.DateTime}}</TS.1>
+			</MSH.7>
+			<!-- Message Type -->
+			<MSH.9>
+			   <MSG.1 >QBP</MSG.1>
+			   <MSG.2 >Q22</MSG.2>
+			   <MSG.3 >QBP_Q21</MSG.3>
+			</MSH.9>
+			<!-- Message Control ID -->
+			<MSH.10>{{.MessageControlID}}</MSH.10>
+			<MSH.11>
+			   <PT.1 >{{.ProcessingID}}</PT.1>
+			</MSH.11>
+			<!-- Version Id -->
+			<MSH.12>
+			   <VID.1 >2.5</VID.1>
+			</MSH.12>
+			<!-- Country Code -->
+			<MSH.17 >GBR</MSH.17>
+		 </MSH>
+
+		 <QPD>
+			<QPD.1 >
+			   <!--Message Query Name :-->
+			   <CE.1>IHE PDQ Query</CE.1>
+			</QPD.1>
+			<!--Query Tag:-->
+			<QPD.2>PatientQuery</QPD.2>
+		  <!--Demographic Fields:-->
+			<QPD.3>
+			   <!--PID.5.1 - Family name:-->
+			   <QIP.1>@PID.5.1.1</QIP.1>
+			   <QIP.2>{{html .Lastname}}</QIP.2>
+			</QPD.3>
+			{{- if .Firstnames}}
+			<QPD.3>
+			   <!--PID.5.2 - Given name:-->
+			   <QIP.1>@PID.5.2</QIP.1>
+			   <QIP.2>{{html .Firstnames}}</QIP.2>
+			</QPD.3>
+			{{- end}}
+			{{- if .BirthDate}}
+			<QPD.3>
+			   <!--PID.7 - Date of birth YYYYMMDD:-->
+			   <QIP.1>@PID.7.1</QIP.1>
+			   <QIP.2>{{.BirthDate}}</QIP.2>
+			</QPD.3>
+			{{- end}}
+			{{- if .Gender}}
+			<QPD.3>
+			   <!--PID.8 - Administrative sex:-->
+			   <QIP.1>@PID.8</QIP.1>
+			   <QIP.2>{{.Gender}}</QIP.2>
+			</QPD.3>
+			{{- end}}
+			{{- if .Postcode}}
+			<QPD.3>
+			   <!--PID.11.5 - Postcode:-->
+			   <QIP.1>@PID.11.5</QIP.1>
+			   <QIP.2>{{html .Postcode}}</QIP.2>
+			</QPD.3>
+			{{- end}}
+		 </QPD>
+
+		 <RCP>
+			<!--Query Priority:-->
+			<RCP.1 >I</RCP.1>
+			<!--Quantity Limited Request:-->
+			<RCP.2 >
+			   <CQ.1>50</CQ.1>
+			</RCP.2>
+
+		 </RCP>
+
+	  </QBP_Q21>
+   </mpi:InvokePatientDemographicsQuery>
+</soapenv:Body>
+</soapenv:Envelope>
+`
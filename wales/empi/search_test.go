@@ -0,0 +1,75 @@
+package empi
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+func TestDemographicRequest(t *testing.T) {
+	dob := &apiv1.Date{Year: 1960, Month: 1, Day: 1}
+	b, err := NewDemographicRequest(&apiv1.PatientSearchRequest{Lastname: "o'brien & sons", BirthDate: dob}, "221", "100", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	if !strings.Contains(s, "<QIP.2>O&#39;BRIEN &amp; SONS</QIP.2>") || !strings.Contains(s, "<QIP.2>19600101</QIP.2>") {
+		t.Fatalf("invalid demographic request: %s", s)
+	}
+	if strings.Contains(s, "@PID.8") || strings.Contains(s, "@PID.11.5") {
+		t.Fatalf("demographic request included unspecified criteria: %s", s)
+	}
+	var v interface{}
+	if err := xml.Unmarshal(b, &v); err != nil {
+		t.Fatalf("demographic request is not valid XML: %s", err)
+	}
+}
+
+func TestMultipleResults(t *testing.T) {
+	data := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1><XPN.2>ALBERT</XPN.2></PID.5><PID.8>M</PID.8></PID></RSP_K21.QUERY_RESPONSE>
+<RSP_K21.QUERY_RESPONSE><PID><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1><XPN.2>ALBERTA</XPN.2></PID.5><PID.8>F</PID.8></PID></RSP_K21.QUERY_RESPONSE>
+</RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+	var e envelope
+	if err := xml.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatal(err)
+	}
+	pts := e.ToPatients()
+	if len(pts) != 2 || pts[0].GetFirstnames() != "ALBERT" || pts[1].GetFirstnames() != "ALBERTA" || pts[1].GetGender() != apiv1.Gender_FEMALE {
+		t.Fatalf("unexpected patients: %v", pts)
+	}
+	pt, err := e.ToPatient()
+	if err != nil || pt.GetFirstnames() != "ALBERT" {
+		t.Fatalf("unexpected first patient: %v (%v)", pt, err)
+	}
+}
+
+func TestNHSNumberVerificationStatus(t *testing.T) {
+	data := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/"><RSP_K21 xmlns="urn:hl7-org:v2xml">
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>1111111111</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NH</CX.5></PID.3><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1></PID.5><PID.32>NSTS01</PID.32></PID></RSP_K21.QUERY_RESPONSE>
+<RSP_K21.QUERY_RESPONSE><PID><PID.3><CX.1>2222222222</CX.1><CX.4><HD.1>NHS</HD.1></CX.4><CX.5>NSTS02</CX.5></PID.3><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1></PID.5></PID></RSP_K21.QUERY_RESPONSE>
+<RSP_K21.QUERY_RESPONSE><PID><PID.5><XPN.1><FN.1>DUMMY</FN.1></XPN.1></PID.5></PID></RSP_K21.QUERY_RESPONSE>
+</RSP_K21></InvokePatientDemographicsQueryResponse></soap:Body></soap:Envelope>`
+	var e envelope
+	if err := xml.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatal(err)
+	}
+	pts := e.ToPatients()
+	expected := []apiv1.NHSNumberVerificationStatus{
+		apiv1.NHSNumberVerificationStatus_NHS_NUMBER_VERIFIED,
+		apiv1.NHSNumberVerificationStatus_NHS_NUMBER_NOT_TRACED,
+		apiv1.NHSNumberVerificationStatus_NHS_NUMBER_STATUS_UNKNOWN,
+	}
+	if len(pts) != len(expected) {
+		t.Fatalf("expected %d patients, got: %v", len(expected), pts)
+	}
+	for i, pt := range pts {
+		if pt.GetNhsNumberVerificationStatus() != expected[i] {
+			t.Errorf("patient %d: expected %s, got %s", i, expected[i], pt.GetNhsNumberVerificationStatus())
+		}
+	}
+}
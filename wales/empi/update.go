@@ -0,0 +1,116 @@
+package empi
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/events"
+	"github.com/wardle/concierge/hl7v2"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/maintenance"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/tracing"
+	"github.com/wardle/concierge/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UpdatePatientDemographics submits a change in contact details to the EMPI as an ADT^A08 message, sent
+// to the EMPI's update interface. The current record is fetched from the EMPI, and the details specified
+// replace those in the current record, as an A08 carries the complete patient record.
+// Updates must be made by an authenticated user, who is recorded as the operator responsible for the change.
+func (app *App) UpdatePatientDemographics(ctx context.Context, r *apiv1.UpdatePatientDemographicsRequest) (pt *apiv1.Patient, err error) {
+	defer metrics.Observe("empi", "update", time.Now(), &err)
+	ctx, span := tracing.StartSpan(ctx, "empi.update")
+	defer tracing.End(ctx, span, &err)
+	if err := maintenance.CheckWrite(ctx, "wales-empi"); err != nil {
+		return nil, err
+	}
+	user := server.GetContextData(ctx).GetAuthenticatedUser()
+	if user.GetSystem() == "" || user.GetValue() == "" {
+		return nil, i18n.Errorf(ctx, codes.PermissionDenied, "demographic updates require an authenticated user")
+	}
+	if len(r.GetAddresses()) == 0 && len(r.GetTelephones()) == 0 && len(r.GetEmails()) == 0 {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "no demographic changes specified")
+	}
+	for _, email := range r.GetEmails() {
+		if len(email) >= 255 || !rxEmail.MatchString(email) {
+			return nil, i18n.Errorf(ctx, codes.InvalidArgument, "invalid email address: %s", email)
+		}
+	}
+	if app.UpdateAddr == "" && !app.Fake {
+		return nil, i18n.Errorf(ctx, codes.Unimplemented, "NHS Wales' EMPI update interface not configured")
+	}
+	id := r.GetIdentifier()
+	authority, ok := uriLookup[id.GetSystem()]
+	if !ok || authority.empiOrganisationCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported authority: %s", id.GetSystem())
+	}
+	key := app.cacheKey(ctx, authority.empiOrganisationCode(), id.GetValue())
+	if app.Cache != nil {
+		app.Cache.Delete(key) // always update the current record, rather than one cached
+	}
+	current, err := app.GetEMPIRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pt = proto.Clone(current).(*apiv1.Patient)
+	if len(r.GetAddresses()) > 0 {
+		pt.Addresses = r.GetAddresses()
+	}
+	if len(r.GetTelephones()) > 0 {
+		pt.Telephones = r.GetTelephones()
+	}
+	if len(r.GetEmails()) > 0 {
+		pt.Emails = r.GetEmails()
+	}
+	log.Printf("empi: demographic update from '%s|%s' for %s|%s", user.GetSystem(), user.GetValue(), id.GetSystem(), id.GetValue())
+	msg := hl7v2.NewADTA08(pt, user, hl7v2.ADTOptions{
+		SendingApplication:   "221",
+		SendingFacility:      "221",
+		ReceivingApplication: "100",
+		ReceivingFacility:    "100",
+		ProcessingID:         app.processingID(ctx),
+		Authority:            updateAuthority,
+	})
+	if app.Fake {
+		log.Printf("empi: applying fake update to simulator: %q", msg)
+		simulator.Current().UpdatePatient(&apiv1.Identifier{System: authority.ToURI(), Value: id.GetValue()}, pt)
+	} else {
+		timeout := app.TimeoutSeconds
+		if timeout == 0 {
+			timeout = 1
+		}
+		sendCtx, cancel, err := transport.WithDeadline(ctx, "empi", time.Duration(timeout)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		if err := hl7v2.SendADT(sendCtx, app.UpdateAddr, msg); err != nil {
+			return nil, err
+		}
+	}
+	if app.Cache != nil {
+		app.Cache.Delete(key)
+	}
+	events.PublishPatientIfChanged("empi/"+key, &apiv1.Identifier{System: authority.ToURI(), Value: id.GetValue()}, pt)
+	return pt, nil
+}
+
+// updateAuthority returns the EMPI authority code for an identifier system, including systems that are
+// themselves authority codes, as returned by the EMPI for authorities without a known URI
+func updateAuthority(system string) string {
+	if code := AuthorityForSystem(system); code != "" {
+		return code
+	}
+	if !strings.Contains(system, ":") {
+		return system
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package empi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdatePatientDemographics(t *testing.T) {
+	app := &App{Fake: true}
+	r := &apiv1.UpdatePatientDemographicsRequest{
+		Identifier: &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"},
+		Emails:     []string{"test@example.com"},
+	}
+	if _, err := app.UpdatePatientDemographics(context.Background(), r); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected update without authenticated user to be refused, got: %v", err)
+	}
+	if code := updateAuthority(identifiers.NHSNumber); code != "NHS" {
+		t.Errorf("expected authority 'NHS' for NHS number, got: %s", code)
+	}
+	if code := updateAuthority("103"); code != "103" {
+		t.Errorf("expected raw authority code to be used as is, got: %s", code)
+	}
+	if code := updateAuthority("https://example.com/Id/unknown"); code != "" {
+		t.Errorf("expected no authority for unknown system, got: %s", code)
+	}
+}
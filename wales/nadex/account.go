@@ -0,0 +1,212 @@
+package nadex
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	auth "gopkg.in/korylprince/go-ad-auth.v2"
+	ldap "gopkg.in/ldap.v3"
+)
+
+// accountAttributes are the directory attributes fetched to determine the status of an account.
+// The msDS-* attributes are computed by Active Directory, taking into account the password policy.
+var accountAttributes = []string{
+	"sAMAccountName",
+	"userAccountControl",
+	"msDS-User-Account-Control-Computed",  // includes lockout and password expiry, unlike userAccountControl
+	"msDS-UserPasswordExpiryTimeComputed", // Windows file time
+	"pwdLastSet",                          // Windows file time
+}
+
+// flags of userAccountControl and msDS-User-Account-Control-Computed
+const (
+	ufAccountDisable     = 0x0002
+	ufLockout            = 0x0010
+	ufDontExpirePassword = 0x10000
+	ufPasswordExpired    = 0x800000
+)
+
+// GetAccountStatus returns the status of the user's account, including when their password expires
+func (app *App) GetAccountStatus(ctx context.Context, r *apiv1.Identifier) (st *apiv1.AccountStatus, err error) {
+	defer metrics.Observe("nadex", "account", time.Now(), &err)
+	if r.GetSystem() != identifiers.CymruUserID {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported identifier system: %s. supported: %s", r.GetSystem(), identifiers.CymruUserID)
+	}
+	var entry *ldap.Entry
+	if app.Fake {
+		entry = fakeAccount(r.GetValue(), time.Now().Add(-85*24*time.Hour))
+	} else {
+		entries, err := app.search(ctx, userFilter(r.GetValue()), accountAttributes, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) > 0 {
+			entry = entries[0]
+		}
+	}
+	if entry == nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", r.GetSystem(), r.GetValue())
+	}
+	return accountStatusFromEntry(entry, time.Now())
+}
+
+// ChangePassword changes the user's password, binding to the directory as the user with their current
+// password. Active Directory only permits password changes over an encrypted connection, and users
+// whose password has already expired cannot bind, and so must change their password by other means.
+func (app *App) ChangePassword(ctx context.Context, r *apiv1.ChangePasswordRequest) (st *apiv1.AccountStatus, err error) {
+	defer metrics.Observe("nadex", "password", time.Now(), &err)
+	user := r.GetUser()
+	if user.GetSystem() != identifiers.CymruUserID {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported identifier system: %s. supported: %s", user.GetSystem(), identifiers.CymruUserID)
+	}
+	if user.GetValue() == "" || r.GetCurrentPassword() == "" || r.GetNewPassword() == "" {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "username, current password and new password required")
+	}
+	if r.GetCurrentPassword() == r.GetNewPassword() {
+		return nil, i18n.Errorf(ctx, codes.InvalidArgument, "new password must be different from current password")
+	}
+	if app.Fake {
+		if r.GetCurrentPassword() != "password" {
+			return nil, i18n.Errorf(ctx, codes.Unauthenticated, "invalid credentials")
+		}
+		entry := fakeAccount(user.GetValue(), time.Now())
+		if entry == nil {
+			return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", user.GetSystem(), user.GetValue())
+		}
+		return accountStatusFromEntry(entry, time.Now())
+	}
+	config := directoryConfig(auth.SecurityStartTLS)
+	conn, err := app.dial(config)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "nadex: could not connect to directory: %s", err)
+	}
+	defer conn.Conn.Close()
+	conn.Conn.SetTimeout(requestTimeout)
+	upn, err := config.UPN(user.GetValue())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid username: %s", err)
+	}
+	success, err := conn.Bind(upn, r.GetCurrentPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "nadex: could not bind to directory: %s", err)
+	}
+	if !success {
+		log.Printf("nadex: password change failed for user %s: invalid credentials", user.GetValue())
+		return nil, i18n.Errorf(ctx, codes.Unauthenticated, "invalid credentials")
+	}
+	entry, err := conn.SearchOne(userFilter(user.GetValue()), accountAttributes)
+	if err != nil {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", user.GetSystem(), user.GetValue())
+	}
+	req := ldap.NewModifyRequest(entry.DN, nil)
+	req.Delete("unicodePwd", []string{encodePassword(r.GetCurrentPassword())})
+	req.Add("unicodePwd", []string{encodePassword(r.GetNewPassword())})
+	if err := conn.Conn.Modify(req); err != nil {
+		log.Printf("nadex: password change failed for user %s: %s", user.GetValue(), err)
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultConstraintViolation) || ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform) {
+			return nil, i18n.Errorf(ctx, codes.FailedPrecondition, "new password does not meet the password policy")
+		}
+		return nil, status.Errorf(codes.Unavailable, "nadex: could not change password: %s", err)
+	}
+	log.Printf("nadex: password changed for user %s", user.GetValue())
+	if entry, err = conn.SearchOne(userFilter(user.GetValue()), accountAttributes); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "nadex: could not fetch account status: %s", err)
+	}
+	return accountStatusFromEntry(entry, time.Now())
+}
+
+// accountStatusFromEntry returns the status of the account in the directory entry at the time specified
+func accountStatusFromEntry(entry *ldap.Entry, now time.Time) (*apiv1.AccountStatus, error) {
+	uac, _ := strconv.ParseInt(entry.GetAttributeValue("userAccountControl"), 10, 64)
+	computed, _ := strconv.ParseInt(entry.GetAttributeValue("msDS-User-Account-Control-Computed"), 10, 64)
+	flags := uac | computed
+	st := &apiv1.AccountStatus{
+		User:                 &apiv1.Identifier{System: identifiers.CymruUserID, Value: entry.GetAttributeValue("sAMAccountName")},
+		Disabled:             flags&ufAccountDisable != 0,
+		Locked:               flags&ufLockout != 0,
+		PasswordExpired:      flags&ufPasswordExpired != 0,
+		PasswordNeverExpires: flags&ufDontExpirePassword != 0,
+	}
+	if t, ok := parseFileTime(entry.GetAttributeValue("msDS-UserPasswordExpiryTimeComputed")); ok && !st.PasswordNeverExpires {
+		ts := timestamppb.New(t)
+		if err := ts.CheckValid(); err != nil {
+			return nil, err
+		}
+		st.PasswordExpires = ts
+		st.PasswordExpired = st.PasswordExpired || !now.Before(t)
+	}
+	if t, ok := parseFileTime(entry.GetAttributeValue("pwdLastSet")); ok {
+		ts := timestamppb.New(t)
+		if err := ts.CheckValid(); err != nil {
+			return nil, err
+		}
+		st.PasswordLastSet = ts
+	}
+	return st, nil
+}
+
+// fileTimeEpoch is the Windows file time of the Unix epoch, in 100-nanosecond intervals since 1601-01-01
+const fileTimeEpoch = 116444736000000000
+
+// parseFileTime parses a Windows file time, returning false if the value is not set, such as 0 or the
+// maximum value used by Active Directory to indicate 'never'
+func parseFileTime(s string) (time.Time, bool) {
+	v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || v <= 0 || v == math.MaxInt64 {
+		return time.Time{}, false
+	}
+	v -= fileTimeEpoch
+	return time.Unix(v/1e7, (v%1e7)*100).UTC(), true
+}
+
+// fileTime formats the time as a Windows file time
+func fileTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/100+fileTimeEpoch, 10)
+}
+
+// encodePassword encodes a password for the unicodePwd attribute: quoted, in UTF-16 little-endian
+func encodePassword(password string) string {
+	u := utf16.Encode([]rune(`"` + password + `"`))
+	b := make([]byte, 2*len(u))
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], c)
+	}
+	return string(b)
+}
+
+// fakeMaxPasswordAge is the maximum age of passwords of fake users
+const fakeMaxPasswordAge = 90 * 24 * time.Hour
+
+// fakeAccount returns a directory entry with the status of the account of a fake user with a password
+// last set at the time specified, or nil if there is no such user. The account of ru054321 is locked.
+func fakeAccount(username string, pwdLastSet time.Time) *ldap.Entry {
+	for _, e := range fakeUsers() {
+		if strings.EqualFold(e.GetAttributeValue("sAMAccountName"), username) {
+			computed := "0"
+			if strings.EqualFold(username, "ru054321") {
+				computed = strconv.Itoa(ufLockout)
+			}
+			return ldap.NewEntry(e.DN, map[string][]string{
+				"sAMAccountName":                      {e.GetAttributeValue("sAMAccountName")},
+				"userAccountControl":                  {"512"}, // normal account
+				"msDS-User-Account-Control-Computed":  {computed},
+				"msDS-UserPasswordExpiryTimeComputed": {fileTime(pwdLastSet.Add(fakeMaxPasswordAge))},
+				"pwdLastSet":                          {fileTime(pwdLastSet)},
+			})
+		}
+	}
+	return nil
+}
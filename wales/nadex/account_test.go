@@ -0,0 +1,55 @@
+package nadex
+
+import (
+	"testing"
+	"time"
+
+	ldap "gopkg.in/ldap.v3"
+)
+
+func TestFileTime(t *testing.T) {
+	if tt, ok := parseFileTime("132422688000000000"); !ok || !tt.Equal(time.Date(2020, 8, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected file time: %v", tt)
+	}
+	for _, never := range []string{"0", "9223372036854775807", ""} {
+		if _, ok := parseFileTime(never); ok {
+			t.Errorf("expected '%s' to be interpreted as not set", never)
+		}
+	}
+	now := time.Now().Truncate(100 * time.Nanosecond)
+	if tt, ok := parseFileTime(fileTime(now)); !ok || !tt.Equal(now) {
+		t.Errorf("expected file time to round-trip: %v != %v", tt, now)
+	}
+	if got := encodePassword("Pä"); got != "\"\x00P\x00\xe4\x00\"\x00" {
+		t.Errorf("unexpected encoding of password: %q", got)
+	}
+}
+
+func TestAccountStatus(t *testing.T) {
+	now := time.Now()
+	st, err := accountStatusFromEntry(fakeAccount("ma090906", now.Add(-85*24*time.Hour)), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires := st.GetPasswordExpires().AsTime()
+	if st.GetLocked() || st.GetPasswordExpired() || st.GetDisabled() || expires.Sub(now).Round(time.Hour) != 5*24*time.Hour {
+		t.Errorf("unexpected status for account with password expiring in five days: %v", st)
+	}
+	if st, _ = accountStatusFromEntry(fakeAccount("ru054321", now), now); !st.GetLocked() {
+		t.Errorf("expected account to be locked: %v", st)
+	}
+	if st, _ = accountStatusFromEntry(fakeAccount("ma090906", now.Add(-91*24*time.Hour)), now); !st.GetPasswordExpired() {
+		t.Errorf("expected password to have expired: %v", st)
+	}
+	entry := ldap.NewEntry("CN=test", map[string][]string{
+		"sAMAccountName":                      {"test"},
+		"userAccountControl":                  {"66050"}, // disabled, password never expires
+		"msDS-UserPasswordExpiryTimeComputed": {"9223372036854775807"},
+	})
+	if st, _ = accountStatusFromEntry(entry, now); !st.GetDisabled() || !st.GetPasswordNeverExpires() || st.GetPasswordExpires() != nil {
+		t.Errorf("unexpected status for disabled account: %v", st)
+	}
+	if fakeAccount("unknown", now) != nil {
+		t.Errorf("expected no account for unknown user")
+	}
+}
@@ -2,13 +2,24 @@ package nadex
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"github.com/wardle/concierge/secrets"
+	"github.com/wardle/concierge/simulator"
+	"github.com/wardle/concierge/transport"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -42,16 +53,23 @@ nhs.uk = CYMRU.NHS.UK
 
 // App reflects the NADEX server application, providing user services for NHS Wales
 type App struct {
-	Username string
-	Password string
-	Fake     bool
+	Username    string
+	Password    secrets.Secret // password, which may be rotated while running
+	Fake        bool
+	MaxResults  int              // maximum number of results from a practitioner search; DefaultMaxResults if zero
+	PoolSize    int              // maximum number of directory connections; DefaultPoolSize if zero
+	IdleTimeout time.Duration    // time after which an idle directory connection is closed; DefaultIdleTimeout if zero
+	Proxy       *transport.Proxy // proxy for directory connections; connects directly if nil
+
+	poolOnce sync.Once
+	pool     *Pool
 }
 
 var _ apiv1.PractitionerDirectoryServer = (*App)(nil)
 
 // RegisterServer registers this server
 func (app *App) RegisterServer(s *grpc.Server) {
-	if app.Username == "" || app.Password == "" {
+	if app.Username == "" || app.password() == "" {
 		log.Printf("nadex: warning! no credentials provided for NADEX lookup. ")
 	}
 	if app.Fake {
@@ -60,131 +78,319 @@ func (app *App) RegisterServer(s *grpc.Server) {
 	apiv1.RegisterPractitionerDirectoryServer(s, app)
 }
 
+// password returns the current password for directory lookups
+func (app *App) password() string {
+	if app.Password == nil {
+		return ""
+	}
+	return app.Password.Value()
+}
+
 // RegisterHTTPProxy registers this as a reverse HTTP proxy
 func (app *App) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
 	return apiv1.RegisterPractitionerDirectoryHandlerFromEndpoint(ctx, mux, endpoint, opts)
 }
 
 // Close closes any linked resources
-func (app *App) Close() error { return nil }
+func (app *App) Close() error {
+	if app.pool != nil {
+		return app.pool.Close()
+	}
+	return nil
+}
+
+// Check checks the health of the connection to the directory
+func (app *App) Check(ctx context.Context) error {
+	if app.Fake {
+		return nil
+	}
+	return app.connections().Check(ctx)
+}
+
+// connections returns the pool of directory connections, creating it if necessary
+func (app *App) connections() *Pool {
+	app.poolOnce.Do(func() {
+		app.pool = NewPool(app.connect, app.PoolSize, app.IdleTimeout)
+	})
+	return app.pool
+}
+
+// DefaultMaxResults is the default maximum number of results returned by a practitioner search
+const DefaultMaxResults = 50
+
+// pageSize is the number of entries requested in each page of results from the directory
+const pageSize = 100
 
-// SearchPractitioner permits a search for a practitioner
-// this currently only supports search by username!
-// TODO: implement search by name
-func (app *App) SearchPractitioner(r *apiv1.PractitionerSearchRequest, s apiv1.PractitionerDirectory_SearchPractitionerServer) error {
+// requestTimeout is the maximum time to wait for a response to a single directory request
+const requestTimeout = 10 * time.Second
+
+// attributes are the directory attributes fetched for each user
+var attributes = []string{
+	"sAMAccountName",       // username
+	"displayNamePrintable", // full name including title
+	"sn",                   // surname
+	"givenName",            // given names
+	"mail",                 // email
+	"title",                // job title, not name prefix
+	"physicalDeliveryOfficeName",
+	"postalAddress", "streetAddress",
+	"l",  // l=city
+	"st", // state/province
+	"postalCode", "telephoneNumber",
+	"mobile",
+	"company",
+	"department",
+	"wWWHomePage",
+	"postOfficeBox", // appears to be used for professional registration e.g. GMC: 4624000
+}
+
+// SearchPractitioner permits a search for a practitioner by username, or by name and/or department.
+// Results are streamed with exact username matches first, then exact surname matches, and are
+// limited to the configured maximum number of results.
+func (app *App) SearchPractitioner(r *apiv1.PractitionerSearchRequest, s apiv1.PractitionerDirectory_SearchPractitionerServer) (err error) {
+	defer metrics.Observe("nadex", "search", time.Now(), &err)
 	if r.GetSystem() != identifiers.CymruUserID {
 		return status.Errorf(codes.InvalidArgument, "practitioner search for namespace '%s' not supported", r.GetSystem())
 	}
-	if r.GetFirstName() != "" || r.GetLastName() != "" {
-		return status.Errorf(codes.Unimplemented, "practitioner search by name not implemented yet")
+	if r.GetFirstName() == "" && r.GetLastName() == "" && r.GetDepartment() == "" {
+		if r.GetUsername() != "" {
+			p, err := app.GetPractitioner(s.Context(), &apiv1.Identifier{System: r.GetSystem(), Value: r.GetUsername()})
+			if err != nil {
+				return err
+			}
+			return s.Send(p)
+		}
+		return status.Errorf(codes.InvalidArgument, "no search parameters specified")
+	}
+	log.Printf("nadex: search for username:'%s' first:'%s' last:'%s' department:'%s'", r.GetUsername(), r.GetFirstName(), r.GetLastName(), r.GetDepartment())
+	var entries []*ldap.Entry
+	if app.Fake {
+		entries = fakeSearch(r)
+	} else if entries, err = app.search(s.Context(), searchFilter(r), attributes, app.maxResults()); err != nil {
+		return err
 	}
-	if r.GetUsername() != "" {
-		p, err := app.GetPractitioner(s.Context(), &apiv1.Identifier{System: r.GetSystem(), Value: r.GetUsername()})
-		if err != nil {
-			return err
+	rank(entries, r)
+	for i, entry := range entries {
+		if i >= app.maxResults() {
+			break
 		}
-		if err := s.Send(p); err != nil {
+		if err := s.Send(practitionerFromEntry(entry)); err != nil {
 			return err
 		}
-		return nil
 	}
-	return status.Errorf(codes.InvalidArgument, "no search parameters specified")
+	return nil
 }
 
-// ResolvePractitioner provides identifier resolution for the CYMRU USER namespace (see identifiers.CymruUserID)
-func (app *App) ResolvePractitioner(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
-	return app.GetPractitioner(ctx, id)
+func (app *App) maxResults() int {
+	if app.MaxResults > 0 {
+		return app.MaxResults
+	}
+	return DefaultMaxResults
 }
 
-// GetPractitioner returns the specified practitioner
-func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv1.Practitioner, error) {
-	if r.System != identifiers.CymruUserID {
-		return nil, fmt.Errorf("unsupported identifier system: %s. supported: %s", r.System, identifiers.CymruUserID)
+// userFilter returns an LDAP filter for the user with the username specified
+func userFilter(username string) string {
+	return fmt.Sprintf("(&(objectClass=User)(sAMAccountName=%s))", ldap.EscapeFilter(username))
+}
+
+// searchFilter returns an LDAP filter for the search. A last name is also matched exactly against
+// the username, as users often search using a colleague's username.
+func searchFilter(r *apiv1.PractitionerSearchRequest) string {
+	var terms strings.Builder
+	if v := strings.TrimSpace(r.GetLastName()); v != "" {
+		fmt.Fprintf(&terms, "(sn=%s*)", ldap.EscapeFilter(v))
 	}
-	log.Printf("nadex: request for %s|%s", r.System, r.Value)
-	if app.Fake {
-		return app.GetFakePractitioner(ctx, r)
+	if v := strings.TrimSpace(r.GetFirstName()); v != "" {
+		fmt.Fprintf(&terms, "(givenName=%s*)", ldap.EscapeFilter(v))
 	}
-	config := &auth.Config{
-		Server:   "cymru.nhs.uk",
-		Port:     389,
-		BaseDN:   "OU=Users,DC=cymru,DC=nhs,DC=uk",
-		Security: auth.SecurityNone,
+	if v := strings.TrimSpace(r.GetDepartment()); v != "" {
+		fmt.Fprintf(&terms, "(department=*%s*)", ldap.EscapeFilter(v))
+	}
+	var usernames strings.Builder
+	for _, v := range []string{r.GetUsername(), r.GetLastName()} {
+		if v = strings.TrimSpace(v); v != "" {
+			fmt.Fprintf(&usernames, "(sAMAccountName=%s)", ldap.EscapeFilter(v))
+		}
+	}
+	return fmt.Sprintf("(&(objectClass=User)(|%s(&%s)))", usernames.String(), terms.String())
+}
+
+// rank sorts entries so that exact username matches are first, then exact surname matches,
+// and then by surname, given name and username, so that results are in a consistent order
+func rank(entries []*ldap.Entry, r *apiv1.PractitionerSearchRequest) {
+	score := func(e *ldap.Entry) int {
+		username := e.GetAttributeValue("sAMAccountName")
+		switch {
+		case username != "" && (strings.EqualFold(username, r.GetUsername()) || strings.EqualFold(username, r.GetLastName())):
+			return 0
+		case strings.EqualFold(e.GetAttributeValue("sn"), strings.TrimSpace(r.GetLastName())):
+			return 1
+		}
+		return 2
 	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		si, sj := score(entries[i]), score(entries[j])
+		if si != sj {
+			return si < sj
+		}
+		sni, snj := strings.ToLower(entries[i].GetAttributeValue("sn")), strings.ToLower(entries[j].GetAttributeValue("sn"))
+		if sni != snj {
+			return sni < snj
+		}
+		gi, gj := strings.ToLower(entries[i].GetAttributeValue("givenName")), strings.ToLower(entries[j].GetAttributeValue("givenName"))
+		if gi != gj {
+			return gi < gj
+		}
+		return strings.ToLower(entries[i].GetAttributeValue("sAMAccountName")) < strings.ToLower(entries[j].GetAttributeValue("sAMAccountName"))
+	})
+}
+
+// connect connects and binds to the directory using the configured credentials
+func (app *App) connect() (*ldap.Conn, error) {
 	if app.Username == "" {
 		return nil, fmt.Errorf("nadex: no credentials provided for directory lookup")
 	}
-	// for the moment, we use the fallback username/password configured - TODO: use user who is making request's own credentials
-	auth, err := auth.Authenticate(config, app.Username, app.Password)
+	config := directoryConfig(auth.SecurityNone)
+	conn, err := app.dial(config)
 	if err != nil {
 		return nil, err
 	}
-	if auth == false {
-		log.Printf("nadex: failed to login for user %s", app.Username)
-		return nil, status.Errorf(codes.Unavailable, "failed to login for user %s", app.Username)
-	}
-	conn, err := config.Connect()
+	upn, err := config.UPN(app.Username)
 	if err != nil {
+		conn.Conn.Close()
 		return nil, err
 	}
-	defer conn.Conn.Close()
-	// perform bind
-	upn, err := config.UPN(app.Username)
+	success, err := conn.Bind(upn, app.password())
 	if err != nil {
+		conn.Conn.Close()
 		return nil, err
 	}
-	success, err := conn.Bind(upn, app.Password)
+	if !success {
+		conn.Conn.Close()
+		log.Printf("nadex: failed to login for user %s", app.Username)
+		return nil, status.Errorf(codes.Unavailable, "failed to login for user %s", app.Username)
+	}
+	log.Printf("nadex: connected to directory as %s", app.Username)
+	conn.Conn.SetTimeout(requestTimeout)
+	return conn.Conn, nil
+}
+
+// directoryConfig returns the configuration for connections to the directory using the security specified
+func directoryConfig(security auth.SecurityType) *auth.Config {
+	return &auth.Config{
+		Server:   "cymru.nhs.uk",
+		Port:     389,
+		BaseDN:   "OU=Users,DC=cymru,DC=nhs,DC=uk",
+		Security: security,
+	}
+}
+
+// dial connects to the directory, via the proxy if configured. Connections are pooled and so outlive the
+// request for which they are made, and are not therefore bound to its context.
+func (app *App) dial(config *auth.Config) (*auth.Conn, error) {
+	if app.Proxy == nil {
+		return config.Connect()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	c, err := app.Proxy.DialContext(ctx, "tcp", net.JoinHostPort(config.Server, strconv.Itoa(config.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("nadex: could not connect to directory via proxy %s: %w", app.Proxy, err)
+	}
+	conn := ldap.NewConn(c, false)
+	conn.Start()
+	if config.Security == auth.SecurityStartTLS {
+		if err := conn.StartTLS(&tls.Config{ServerName: config.Server, MinVersion: tls.VersionTLS12}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("nadex: could not start TLS: %w", err)
+		}
+	}
+	return &auth.Conn{Conn: conn, Config: config}, nil
+}
+
+// search searches the directory using the filter specified, using server-side paging, and
+// returning at most max entries
+func (app *App) search(ctx context.Context, filter string, attributes []string, max int) (entries []*ldap.Entry, err error) {
+	ctx, cancel, err := transport.WithDeadline(ctx, "nadex", requestTimeout)
 	if err != nil {
 		return nil, err
 	}
-	if !success {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to login for user %s", app.Username)
-	}
-	// search for a user
-	searchRequest := ldap.NewSearchRequest(
-		"dc=cymru,dc=nhs,dc=uk", // The base dn to search
-		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-		fmt.Sprintf("(&(objectClass=User)(sAMAccountName=%s))", r.Value), // The filter to apply
-		// A list attributes to retrieve
-		[]string{
-			"sAMAccountName",       // username
-			"displayNamePrintable", // full name including title
-			"sn",                   // surname
-			"givenName",            // given names
-			"mail",                 // email
-			"title",                // job title, not name prefix
-			"photo",
-			"physicalDeliveryOfficeName",
-			"postalAddress", "streetAddress",
-			"l",  // l=city
-			"st", // state/province
-			"postalCode", "telephoneNumber",
-			"mobile",
-			"company",
-			"department",
-			"wWWHomePage",
-			"postOfficeBox", // appears to be used for professional registration e.g. GMC: 4624000
-		},
-		nil,
-	)
-	sr, err := conn.Conn.Search(searchRequest)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	err = app.connections().Do(ctx, func(conn *ldap.Conn) error {
+		// searches cannot be cancelled, so each is limited to the time remaining before the caller's deadline
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return status.FromContextError(context.DeadlineExceeded).Err()
+		}
+		conn.SetTimeout(remaining)
+		defer conn.SetTimeout(requestTimeout)
+		paging := ldap.NewControlPaging(pageSize)
+		req := ldap.NewSearchRequest("dc=cymru,dc=nhs,dc=uk", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, attributes, []ldap.Control{paging})
+		entries = make([]*ldap.Entry, 0)
+		for {
+			sr, err := conn.Search(req)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, sr.Entries...)
+			ctrl, ok := ldap.FindControl(sr.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+			if !ok || len(ctrl.Cookie) == 0 {
+				return nil
+			}
+			paging.SetCookie(ctrl.Cookie)
+			if len(entries) >= max {
+				paging.PagingSize = 0 // abandon the remaining results
+				if _, err := conn.Search(req); err != nil {
+					log.Printf("nadex: failed to abandon paged search: %s", err)
+				}
+				return nil
+			}
+		}
+	})
+	return entries, err
+}
+
+// ResolvePractitioner provides identifier resolution for the CYMRU USER namespace (see identifiers.CymruUserID)
+func (app *App) ResolvePractitioner(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	return app.GetPractitioner(ctx, id)
+}
+
+// GetPractitioner returns the specified practitioner
+func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (p *apiv1.Practitioner, err error) {
+	defer metrics.Observe("nadex", "fetch", time.Now(), &err)
+	if r.System != identifiers.CymruUserID {
+		return nil, fmt.Errorf("unsupported identifier system: %s. supported: %s", r.System, identifiers.CymruUserID)
+	}
+	log.Printf("nadex: request for %s|%s", r.System, r.Value)
+	if app.Fake {
+		return app.GetFakePractitioner(ctx, r)
+	}
+	// for the moment, we use the fallback username/password configured - TODO: use user who is making request's own credentials
+	entries, err := app.search(ctx, userFilter(r.Value), attributes, 2)
 	if err != nil {
 		return nil, err
 	}
-	if len(sr.Entries) == 0 {
+	if len(entries) == 0 {
 		log.Printf("nadex: user %s|%s not found", r.System, r.Value)
-		return nil, status.Errorf(codes.NotFound, "user not found: %s|%s", r.System, r.Value)
+		return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", r.System, r.Value)
 	}
-	if len(sr.Entries) > 1 {
+	if len(entries) > 1 {
 		return nil, status.Errorf(codes.InvalidArgument, "more than one match for username %s", r.Value)
 	}
-	entry := sr.Entries[0]
+	user := practitionerFromEntry(entries[0])
+	log.Printf("nadex: returning user: %+v", user)
+	return user, nil
+}
+
+// practitionerFromEntry creates a practitioner from a directory entry
+func practitionerFromEntry(entry *ldap.Entry) *apiv1.Practitioner {
 	phones := make([]*apiv1.Telephone, 0)
 	if n := entry.GetAttributeValue("mobile"); n != "" {
-		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Mobile"})
+		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Mobile", Use: apiv1.Telephone_MOBILE})
 	}
 	if n := entry.GetAttributeValue("telephoneNumber"); n != "" {
-		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Office"})
+		phones = append(phones, &apiv1.Telephone{Number: n, Description: "Office", Use: apiv1.Telephone_WORK})
 	}
 	ids := make([]*apiv1.Identifier, 0)
 	ids = append(ids, &apiv1.Identifier{
@@ -215,37 +421,92 @@ func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv
 		Telephones:  phones,
 		Identifiers: ids,
 	}
-	if title := entry.GetAttributeValue("title"); title != "" {
+	title, org := entry.GetAttributeValue("title"), organisation(entry.GetAttributeValue("company"))
+	if title != "" || org != nil {
 		user.Roles = []*apiv1.PractitionerRole{
-			{Role: &apiv1.Role{JobTitle: title}},
+			{Role: &apiv1.Role{JobTitle: title}, Organisation: org},
 		}
 	}
-	log.Printf("nadex: returning user: %+v", user)
-	return user, nil
+	return user
+}
+
+// organisations are the ODS codes of NHS Wales' organisations, by name as recorded in the directory ('company')
+var organisations = map[string]string{
+	"aneurin bevan university health board":     "7A6",
+	"betsi cadwaladr university health board":   "7A1",
+	"cardiff and vale university health board":  "7A4",
+	"cwm taf morgannwg university health board": "7A5",
+	"hywel dda university health board":         "7A2",
+	"powys teaching health board":               "7A7",
+	"swansea bay university health board":       "7A3",
+	"velindre university nhs trust":             "RQF",
+	"welsh ambulance services nhs trust":        "RT4",
+}
+
+// organisation returns the ODS code of the organisation with the name specified, or nil if not known.
+// Names are matched ignoring case, and with '&' and 'UHB' as abbreviations.
+func organisation(name string) *apiv1.Identifier {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	name = strings.ReplaceAll(name, "&", "and")
+	if strings.HasSuffix(name, " uhb") {
+		name = strings.TrimSuffix(name, "uhb") + "university health board"
+	}
+	if code, ok := organisations[name]; ok {
+		return &apiv1.Identifier{System: identifiers.ODSCode, Value: code}
+	}
+	return nil
 }
 
-// GetFakePractitioner returns a fake practitioner, useful in testing without a live backend service
+// GetFakePractitioner returns a practitioner from the simulator, useful in testing without a live backend service
 func (app *App) GetFakePractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv1.Practitioner, error) {
-	p := &apiv1.Practitioner{
-		Active: true,
-		Emails: []string{"wibble@wobble.org"},
-		Names: []*apiv1.HumanName{
-			{Given: "Fred", Family: "Flintstone", Prefixes: []string{"Mr"}},
-		},
-		Roles: []*apiv1.PractitionerRole{
-			{Role: &apiv1.Role{JobTitle: "Consultant Neurologist"}},
-		},
-		Identifiers: []*apiv1.Identifier{
-			{System: identifiers.CymruUserID, Value: r.GetValue()},
-			{System: identifiers.GMCNumber, Value: "4624000"},
-		},
+	p, found := simulator.Current().Practitioner(r.GetValue())
+	if !found {
+		log.Printf("nadex: user %s|%s not found", r.GetSystem(), r.GetValue())
+		return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", r.GetSystem(), r.GetValue())
+	}
+	user := practitionerFromEntry(fakeEntry(p))
+	log.Printf("nadex: returning fake practitioner: %+v", user)
+	return user, nil
+}
+
+// fakeUsers returns directory entries for the practitioners in the simulator, used in fake mode
+func fakeUsers() []*ldap.Entry {
+	practitioners := simulator.Current().AllPractitioners()
+	result := make([]*ldap.Entry, 0, len(practitioners))
+	for _, p := range practitioners {
+		result = append(result, fakeEntry(p))
+	}
+	return result
+}
+
+// fakeEntry returns a directory entry for a practitioner in the simulator
+func fakeEntry(p *simulator.Practitioner) *ldap.Entry {
+	attrs := map[string][]string{"sAMAccountName": {p.Username}, "sn": {p.LastName}, "givenName": {p.FirstNames}, "department": {p.Department}, "title": {p.JobTitle}, "mail": {p.Email},
+		"company": {"Cardiff and Vale University Health Board"}} // as per the simulated patients' case record numbers
+	if p.GMC != "" {
+		attrs["postOfficeBox"] = []string{"GMC: " + p.GMC}
+	}
+	return ldap.NewEntry("CN="+p.Username, attrs)
+}
+
+// fakeSearch returns the fake users matching the search, as per searchFilter
+func fakeSearch(r *apiv1.PractitionerSearchRequest) []*ldap.Entry {
+	hasPrefix := func(s, prefix string) bool { return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix)) }
+	contains := func(s, substr string) bool { return strings.Contains(strings.ToLower(s), strings.ToLower(substr)) }
+	result := make([]*ldap.Entry, 0)
+	for _, e := range fakeUsers() {
+		username := e.GetAttributeValue("sAMAccountName")
+		if (r.GetUsername() != "" && strings.EqualFold(username, r.GetUsername())) || (r.GetLastName() != "" && strings.EqualFold(username, r.GetLastName())) ||
+			(hasPrefix(e.GetAttributeValue("sn"), r.GetLastName()) && hasPrefix(e.GetAttributeValue("givenName"), r.GetFirstName()) && contains(e.GetAttributeValue("department"), r.GetDepartment())) {
+			result = append(result, e)
+		}
 	}
-	log.Printf("nadex: returning fake practitioner: %+v", p)
-	return p, nil
+	return result
 }
 
 // Authenticate authenticates a user against the NHS Wales' directory service
-func (app *App) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+func (app *App) Authenticate(id *apiv1.Identifier, credential string) (success bool, err error) {
+	defer metrics.Observe("nadex", "login", time.Now(), &err)
 	if id.GetSystem() != identifiers.CymruUserID {
 		return false, fmt.Errorf("nadex: unsupported uri: %s", id.GetSystem())
 	}
@@ -0,0 +1,63 @@
+package nadex
+
+import (
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+)
+
+func TestSearchFilter(t *testing.T) {
+	tests := []struct {
+		r      *apiv1.PractitionerSearchRequest
+		filter string
+	}{
+		{&apiv1.PractitionerSearchRequest{LastName: "wardle"}, "(&(objectClass=User)(|(sAMAccountName=wardle)(&(sn=wardle*))))"},
+		{&apiv1.PractitionerSearchRequest{FirstName: "mark", Department: "neuro"}, "(&(objectClass=User)(|(&(givenName=mark*)(department=*neuro*))))"},
+		{&apiv1.PractitionerSearchRequest{LastName: "o*)(x=y"}, "(&(objectClass=User)(|(sAMAccountName=o\\2a\\29\\28x=y)(&(sn=o\\2a\\29\\28x=y*))))"},
+	}
+	for _, test := range tests {
+		if got := searchFilter(test.r); got != test.filter {
+			t.Errorf("search filter for %v: expected %s, got %s", test.r, test.filter, got)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	r := &apiv1.PractitionerSearchRequest{LastName: "fl"}
+	entries := fakeSearch(r)
+	if len(entries) != 2 {
+		t.Fatalf("expected two matches for '%s', got %d", r.GetLastName(), len(entries))
+	}
+	r = &apiv1.PractitionerSearchRequest{LastName: "ru054321"}
+	entries = append(fakeSearch(&apiv1.PractitionerSearchRequest{LastName: "r"}), fakeSearch(r)...)
+	rank(entries, r)
+	if username := entries[0].GetAttributeValue("sAMAccountName"); username != "ru054321" {
+		t.Fatalf("expected exact username match first, got %s", username)
+	}
+	entries = fakeSearch(&apiv1.PractitionerSearchRequest{Department: "o"})
+	rank(entries, &apiv1.PractitionerSearchRequest{Department: "o"})
+	for i, expected := range []string{"Fred", "Wilma", "Mark"} {
+		if got := entries[i].GetAttributeValue("givenName"); got != expected {
+			t.Errorf("expected result %d to be %s, got %s", i, expected, got)
+		}
+	}
+}
+
+func TestOrganisation(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"Cardiff and Vale University Health Board", "7A4"},
+		{"Cardiff & Vale UHB", "7A4"},
+		{"  betsi cadwaladr   university health board", "7A1"},
+		{"Velindre University NHS Trust", "RQF"},
+		{"NHS Wales Informatics Service", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := organisation(test.name); got.GetValue() != test.code {
+			t.Errorf("organisation '%s': expected '%s', got %v", test.name, test.code, got)
+		}
+	}
+}
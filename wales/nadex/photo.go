@@ -0,0 +1,84 @@
+package nadex
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/i18n"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// photoTTL is the time for which photographs are cached
+const photoTTL = time.Hour
+
+// photoAttributes are the directory attributes that may contain a photograph, in order of preference
+var photoAttributes = []string{"thumbnailPhoto", "jpegPhoto", "photo"}
+
+var photos = cache.New(photoTTL, 2*photoTTL)
+
+// GetPractitionerPhoto returns the photograph of the practitioner specified
+func (app *App) GetPractitionerPhoto(ctx context.Context, r *apiv1.Identifier) (att *apiv1.Attachment, err error) {
+	defer metrics.Observe("nadex", "photo", time.Now(), &err)
+	if r.GetSystem() != identifiers.CymruUserID {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported identifier system: %s. supported: %s", r.GetSystem(), identifiers.CymruUserID)
+	}
+	if o, found := photos.Get(r.GetValue()); found {
+		metrics.CacheLookup("nadex-photo", true)
+		return o.(*apiv1.Attachment), nil
+	}
+	metrics.CacheLookup("nadex-photo", false)
+	var data []byte
+	if app.Fake {
+		data = fakePhoto
+	} else {
+		entries, err := app.search(ctx, userFilter(r.GetValue()), photoAttributes, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, i18n.Errorf(ctx, codes.NotFound, "user not found: %s|%s", r.GetSystem(), r.GetValue())
+		}
+		for _, attr := range photoAttributes {
+			if data = entries[0].GetRawAttributeValue(attr); len(data) > 0 {
+				break
+			}
+		}
+	}
+	if len(data) == 0 {
+		return nil, i18n.Errorf(ctx, codes.NotFound, "no photograph found for user: %s|%s", r.GetSystem(), r.GetValue())
+	}
+	hash := sha1.Sum(data)
+	att = &apiv1.Attachment{
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+		Size:        uint64(len(data)),
+		Hash:        hash[:],
+		Title:       "Photograph of " + r.GetValue(),
+	}
+	photos.SetDefault(r.GetValue(), att)
+	return att, nil
+}
+
+// fakePhoto is a plain grey JPEG image, used in fake mode
+var fakePhoto = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 96, 96))
+	for i := range img.Pix {
+		img.Pix[i] = color.Gray{Y: 0xc0}.Y
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}()
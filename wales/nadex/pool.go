@@ -0,0 +1,145 @@
+package nadex
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	ldap "gopkg.in/ldap.v3"
+)
+
+// Default configuration for the pool of directory connections
+const (
+	DefaultPoolSize    = 4
+	DefaultIdleTimeout = 5 * time.Minute
+)
+
+// ErrPoolClosed is returned when a connection is requested from a closed pool
+var ErrPoolClosed = errors.New("nadex: connection pool closed")
+
+// Pool is a pool of long-lived, bound connections to the directory.
+// Connections that fail with a network error are discarded and replaced, so that a request
+// is retried once using a newly connected and bound connection.
+type Pool struct {
+	dial        func() (*ldap.Conn, error) // connects and binds a new connection
+	idleTimeout time.Duration
+	sem         chan struct{} // limits the number of connections in use
+
+	mu     sync.Mutex
+	idle   []*pooledConn
+	open   int
+	closed bool
+}
+
+type pooledConn struct {
+	conn     *ldap.Conn
+	lastUsed time.Time
+}
+
+// NewPool creates a pool of at most size connections, created using the dial function specified.
+// Idle connections are closed after the idle timeout.
+func NewPool(dial func() (*ldap.Conn, error), size int, idleTimeout time.Duration) *Pool {
+	if size <= 0 {
+		size = DefaultPoolSize
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+	return &Pool{dial: dial, idleTimeout: idleTimeout, sem: make(chan struct{}, size)}
+}
+
+// Do runs f using a connection from the pool, retrying once with a new connection if f fails
+// with a network error, such as when the server has closed an idle connection.
+func (p *Pool) Do(ctx context.Context, f func(conn *ldap.Conn) error) error {
+	for attempt := 0; ; attempt++ {
+		conn, err := p.get(ctx)
+		if err != nil {
+			return err
+		}
+		err = f(conn)
+		failed := conn.IsClosing() || ldap.IsErrorWithCode(err, ldap.ErrorNetwork)
+		p.put(conn, failed)
+		if !failed || attempt > 0 {
+			return err
+		}
+		log.Printf("nadex: directory connection failed: reconnecting: %s", err)
+	}
+}
+
+// get returns a connection from the pool, connecting if there are no idle connections
+func (p *Pool) get(ctx context.Context) (*ldap.Conn, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		<-p.sem
+		return nil, ErrPoolClosed
+	}
+	for len(p.idle) > 0 {
+		pc := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if !pc.conn.IsClosing() && time.Since(pc.lastUsed) < p.idleTimeout {
+			p.mu.Unlock()
+			return pc.conn, nil
+		}
+		pc.conn.Close()
+		p.open--
+	}
+	p.mu.Unlock()
+	conn, err := p.dial()
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+	p.mu.Lock()
+	p.open++
+	p.mu.Unlock()
+	return conn, nil
+}
+
+// put returns a connection to the pool, or closes it if it has failed or the pool is closed
+func (p *Pool) put(conn *ldap.Conn, failed bool) {
+	defer func() { <-p.sem }()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if failed || p.closed {
+		conn.Close()
+		p.open--
+		return
+	}
+	p.idle = append(p.idle, &pooledConn{conn: conn, lastUsed: time.Now()})
+}
+
+// Check checks that the directory can be reached using a pooled connection, by reading the root DSE
+func (p *Pool) Check(ctx context.Context) error {
+	return p.Do(ctx, func(conn *ldap.Conn) error {
+		_, err := conn.Search(ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 10, false, "(objectClass=*)", []string{"defaultNamingContext"}, nil))
+		return err
+	})
+}
+
+// Stats returns the number of open connections, and the number of those that are idle
+func (p *Pool) Stats() (open int, idle int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.open, len(p.idle)
+}
+
+// Close closes all idle connections, and any connections in use when they are returned to the pool
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, pc := range p.idle {
+		pc.conn.Close()
+		p.open--
+	}
+	p.idle = nil
+	return nil
+}
@@ -0,0 +1,69 @@
+package nadex
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	ldap "gopkg.in/ldap.v3"
+)
+
+func TestPool(t *testing.T) {
+	dialled := 0
+	dial := func() (*ldap.Conn, error) {
+		dialled++
+		client, server := net.Pipe()
+		t.Cleanup(func() { server.Close() })
+		conn := ldap.NewConn(client, false)
+		conn.Start()
+		return conn, nil
+	}
+	pool := NewPool(dial, 2, time.Minute)
+	defer pool.Close()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := pool.Do(ctx, func(conn *ldap.Conn) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dialled != 1 {
+		t.Fatalf("expected connection to be reused, but dialled %d times", dialled)
+	}
+	if open, idle := pool.Stats(); open != 1 || idle != 1 {
+		t.Fatalf("expected one open, idle connection. got open: %d, idle: %d", open, idle)
+	}
+	attempts := 0
+	err := pool.Do(ctx, func(conn *ldap.Conn) error {
+		attempts++
+		if attempts == 1 {
+			return ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset"))
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 || dialled != 2 {
+		t.Fatalf("expected retry using new connection after network error. err: %v, attempts: %d, dialled: %d", err, attempts, dialled)
+	}
+	if open, _ := pool.Stats(); open != 1 {
+		t.Fatalf("expected failed connection to be closed. open: %d", open)
+	}
+	appErr := errors.New("application error")
+	if err := pool.Do(ctx, func(conn *ldap.Conn) error { return appErr }); err != appErr {
+		t.Fatalf("expected application error to be returned without retry. got: %v", err)
+	}
+	// exhaust pool, and check that a further request waits
+	ctx2, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	c1, _ := pool.get(ctx)
+	c2, _ := pool.get(ctx)
+	if _, err := pool.get(ctx2); err != context.DeadlineExceeded {
+		t.Fatalf("expected exhausted pool to block until deadline. got: %v", err)
+	}
+	pool.put(c1, false)
+	pool.put(c2, false)
+	pool.Close()
+	if err := pool.Do(ctx, func(conn *ldap.Conn) error { return nil }); err != ErrPoolClosed {
+		t.Fatalf("expected closed pool. got: %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package wcrs provides integration with the Welsh Care Records Service (WCRS), the NHS Wales
+// national document repository, permitting documents to be stored when no health-board specific
+// repository is available for a patient.
+//
+// The message structures represent the StoreDocument operation and its DocumentVersionStructure,
+// with authentication using WS-Security username tokens.
+// The WSDL for the service is not publicly available; these structures should be validated against
+// the service definition for the target environment before use against a live endpoint.
+package wcrs
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"log"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/secrets"
+	"github.com/wardle/concierge/wales/cav/soap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	namespace          = "http://apps.wales.nhs.uk/wcrs/"
+	storeDocumentSOAP  = namespace + "StoreDocument"
+	dateTimeLayout     = "2006-01-02T15:04:05"
+	defaultContentType = "application/pdf"
+)
+
+// StoreDocumentRequest is a request to store a document in the WCRS
+type StoreDocumentRequest struct {
+	XMLName  xml.Name                  `xml:"http://apps.wales.nhs.uk/wcrs/ StoreDocument"`
+	Document *DocumentVersionStructure `xml:"DocumentVersion"`
+}
+
+// StoreDocumentResponse is the response from a request to store a document
+type StoreDocumentResponse struct {
+	XMLName      xml.Name `xml:"http://apps.wales.nhs.uk/wcrs/ StoreDocumentResponse"`
+	DocumentID   string   `xml:"DocumentId"`
+	ErrorMessage string   `xml:"ErrorMessage,omitempty"`
+}
+
+// DocumentVersionStructure represents a single version of a document and its metadata
+type DocumentVersionStructure struct {
+	DocumentID          string                        `xml:"DocumentId"`        // unique identifier for the document from the source system
+	VersionNumber       int                           `xml:"VersionNumber"`     // version, starting at 1
+	NHSNumber           string                        `xml:"NhsNumber"`         // patient NHS number
+	Surname             string                        `xml:"Surname,omitempty"` // patient surname
+	Forenames           string                        `xml:"Forenames,omitempty"`
+	DateOfBirth         string                        `xml:"DateOfBirth,omitempty"`
+	Title               string                        `xml:"Title"`                                          // document title
+	Status              string                        `xml:"Status"`                                         // document status e.g. final
+	DocumentDate        string                        `xml:"DocumentDate"`                                   // logical date of the document
+	SourceOrganisation  string                        `xml:"SourceOrganisation"`                             // ODS code of the organisation submitting the document
+	SourceSystem        string                        `xml:"SourceSystem"`                                   // name of the submitting system
+	ContentType         string                        `xml:"ContentType"`                                    // mime type of content
+	Content             []byte                        `xml:"Content"`                                        // content, base64 encoded on the wire
+	SensitivityTypeCode string                        `xml:"SensitivityTypeCode"`                            // HL7 confidentiality code e.g. N (normal), R (restricted), V (very restricted)
+	SupersessionSet     []string                      `xml:"SupersessionSet>DocumentId,omitempty"`           // WCRS identifiers of versions superseded, most recent first
+	Attributes          []*DocumentAttributeStructure `xml:"DocumentAttributes>DocumentAttribute,omitempty"` // metadata e.g. document type
+}
+
+// DocumentAttributeStructure represents an item of metadata for a document, such as its type or specialty,
+// which may be coded
+type DocumentAttributeStructure struct {
+	Name       string `xml:"Name"`                 // name of the attribute e.g. DocumentType
+	Value      string `xml:"Value"`                // value e.g. code or date
+	CodeSystem string `xml:"CodeSystem,omitempty"` // coding system of the value, if coded e.g. http://snomed.info/sct
+	Display    string `xml:"Display,omitempty"`    // value for display, if any e.g. name of the author
+}
+
+// Names of document attributes
+const (
+	AttributeDocumentType = "DocumentType"
+	AttributeSpecialty    = "Specialty"
+	AttributeAuthor       = "Author"
+	AttributeEventDate    = "EventDate"
+)
+
+// Repository is a document repository backed by the Welsh Care Records Service
+type Repository struct {
+	url          string
+	username     string
+	password     secrets.Secret
+	organisation string      // ODS code of the organisation submitting documents
+	tlsConfig    *tls.Config // TLS configuration; requires at least TLS 1.2 if nil
+}
+
+// NewRepository creates a new WCRS repository for the specified endpoint, using the credentials specified
+// for WS-Security authentication.
+func NewRepository(url string, username string, password string, organisation string) *Repository {
+	return &Repository{url: url, username: username, password: secrets.Static(password), organisation: organisation}
+}
+
+// SetPassword sets the password used for WS-Security authentication, which may be rotated while running.
+// This should not be called once server is running.
+func (repo *Repository) SetPassword(password secrets.Secret) {
+	repo.password = password
+}
+
+// SetTLSConfig sets the TLS configuration used for requests to the WCRS.
+// This should not be called once server is running.
+func (repo *Repository) SetTLSConfig(cfg *tls.Config) {
+	repo.tlsConfig = cfg
+}
+
+// PublishDocument stores the document in the WCRS
+func (repo *Repository) PublishDocument(ctx context.Context, r *apiv1.PublishDocumentRequest) (*apiv1.PublishDocumentResponse, error) {
+	dvs, err := repo.NewDocumentVersionStructure(r.GetDocument())
+	if err != nil {
+		return nil, err
+	}
+	supersede(dvs, r.GetPreviousVersions())
+	client := soap.NewSOAPClient(repo.url, nil)
+	if repo.tlsConfig != nil {
+		client = soap.NewSOAPClientWithTLSConfig(repo.url, repo.tlsConfig, nil)
+	}
+	client.AddHeader(soap.NewWSSSecurityHeader(repo.username, repo.password.Value(), "1"))
+	response := new(StoreDocumentResponse)
+	if err := client.CallContext(ctx, storeDocumentSOAP, &StoreDocumentRequest{Document: dvs}, response); err != nil {
+		log.Printf("wcrs: failed to store document '%s': %s", dvs.DocumentID, err)
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "WCRS unavailable: %s", err)
+	}
+	if response.ErrorMessage != "" {
+		log.Printf("wcrs: failed to store document '%s': %s", dvs.DocumentID, response.ErrorMessage)
+		return nil, status.Errorf(codes.FailedPrecondition, "error storing document in WCRS: %s", response.ErrorMessage)
+	}
+	log.Printf("wcrs: stored document '%s' as '%s'", dvs.DocumentID, response.DocumentID)
+	return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.WCRSDocumentID, Value: response.DocumentID}}, nil
+}
+
+// NewDocumentVersionStructure creates a document version structure from the document specified.
+func (repo *Repository) NewDocumentVersionStructure(d *apiv1.Document) (*DocumentVersionStructure, error) {
+	nnn, found := d.GetPatient().GetIdentifiersForSystem(identifiers.NHSNumber)
+	if !found {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to store document in WCRS: no NHS number for patient")
+	}
+	if len(d.GetData().GetData()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to store document in WCRS: no document data")
+	}
+	docID := d.GetId().GetValue()
+	if d.GetId().GetSystem() != identifiers.UUID {
+		docID = d.GetId().GetSystem() + "|" + d.GetId().GetValue()
+	}
+	contentType := d.GetData().GetContentType()
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	dvs := &DocumentVersionStructure{
+		DocumentID:          docID,
+		VersionNumber:       1,
+		NHSNumber:           nnn[0].GetValue(),
+		Surname:             d.GetPatient().GetLastname(),
+		Forenames:           d.GetPatient().GetFirstnames(),
+		Title:               d.GetTitle(),
+		Status:              d.GetStatus().String(),
+		SourceOrganisation:  repo.organisation,
+		SourceSystem:        "concierge",
+		ContentType:         contentType,
+		Content:             d.GetData().GetData(),
+		SensitivityTypeCode: d.GetSensitivity().Code(),
+	}
+	dvs.DateOfBirth = d.GetPatient().GetBirthDate().Format("2006-01-02")
+	date := time.Now()
+	if d.GetDateTime().CheckValid() == nil {
+		date = d.GetDateTime().AsTime()
+	}
+	dvs.DocumentDate = date.Format(dateTimeLayout)
+	dvs.Attributes = documentAttributes(d)
+	return dvs, nil
+}
+
+// supersede marks the document as superseding the previous versions specified, most recent first. Only versions
+// stored in the WCRS can be superseded, but the version number reflects all previous versions.
+func supersede(dvs *DocumentVersionStructure, previous []*apiv1.DocumentVersion) {
+	dvs.VersionNumber = len(previous) + 1
+	for _, v := range previous {
+		if id := v.GetResponse().GetId(); id.GetSystem() == identifiers.WCRSDocumentID {
+			dvs.SupersessionSet = append(dvs.SupersessionSet, id.GetValue())
+		}
+	}
+}
+
+// documentAttributes returns the attributes of the document: its type, specialty, authors and event date, if known.
+// The principal author is included with name for display, in addition to the identifiers of each author.
+func documentAttributes(d *apiv1.Document) []*DocumentAttributeStructure {
+	var result []*DocumentAttributeStructure
+	coded := func(name string, id *apiv1.Identifier, display string) {
+		if id.GetValue() != "" {
+			result = append(result, &DocumentAttributeStructure{Name: name, Value: id.GetValue(), CodeSystem: id.GetSystem(), Display: display})
+		}
+	}
+	coded(AttributeDocumentType, d.GetType(), "")
+	coded(AttributeSpecialty, d.GetSpecialty(), "")
+	author := d.GetAuthor()
+	authors := d.GetAuthors()
+	if len(authors) == 0 && len(author.GetIdentifiers()) > 0 {
+		authors = author.GetIdentifiers()[:1]
+	}
+	for i, id := range authors {
+		display := ""
+		if i == 0 {
+			display = author.DisplayName()
+		}
+		coded(AttributeAuthor, id, display)
+	}
+	if len(authors) == 0 && author.DisplayName() != "" {
+		result = append(result, &DocumentAttributeStructure{Name: AttributeAuthor, Value: author.DisplayName()})
+	}
+	if d.GetEventDateTime().CheckValid() == nil {
+		result = append(result, &DocumentAttributeStructure{Name: AttributeEventDate, Value: d.GetEventDateTime().AsTime().Format(dateTimeLayout)})
+	}
+	return result
+}
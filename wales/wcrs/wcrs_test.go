@@ -0,0 +1,170 @@
+package wcrs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testDocument() *apiv1.Document {
+	dt := timestamppb.New(time.Date(2020, 5, 1, 14, 30, 0, 0, time.UTC))
+	event := timestamppb.New(time.Date(2020, 4, 28, 9, 0, 0, 0, time.UTC))
+	return &apiv1.Document{
+		Id:    &apiv1.Identifier{System: identifiers.UUID, Value: "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1"},
+		Title: "Clinic letter",
+		Patient: &apiv1.Patient{
+			Lastname:    "DUMMY",
+			Firstnames:  "ALBERT JOHN",
+			BirthDate:   &apiv1.Date{Year: 1960, Month: 1, Day: 1},
+			Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}},
+		},
+		Status:        apiv1.Document_FINAL,
+		DateTime:      dt,
+		EventDateTime: event,
+		Type:          &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "823691000000103"},
+		Specialty:     &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "394591006"},
+		Authors:       []*apiv1.Identifier{{System: identifiers.GMCNumber, Value: "4624000"}, {System: identifiers.CymruUserID, Value: "ma090906"}},
+		Author:        &apiv1.Practitioner{Names: []*apiv1.HumanName{{Prefixes: []string{"Dr"}, Given: "Mark", Family: "Wardle"}}},
+		Sensitivity:   apiv1.Document_RESTRICTED,
+		Data:          &apiv1.Attachment{Data: []byte("%PDF-1.4 test")},
+	}
+}
+
+func TestNewDocumentVersionStructure(t *testing.T) {
+	repo := NewRepository("", "user", "password", "7A4")
+	dvs, err := repo.NewDocumentVersionStructure(testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dvs.DocumentID != "c9a4a3c5-4b5e-4d52-9d43-2b1b4cb3c8a1" || dvs.VersionNumber != 1 || dvs.NHSNumber != "1111111111" || dvs.Surname != "DUMMY" || dvs.DateOfBirth != "1960-01-01" {
+		t.Errorf("unexpected document version: %+v", dvs)
+	}
+	if dvs.DocumentDate != "2020-05-01T14:30:00" || dvs.Status != "FINAL" || dvs.SourceOrganisation != "7A4" || dvs.ContentType != defaultContentType || dvs.SensitivityTypeCode != "R" {
+		t.Errorf("unexpected document metadata: %+v", dvs)
+	}
+	d := testDocument()
+	d.Id = &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "123"}
+	if dvs, err := repo.NewDocumentVersionStructure(d); err != nil || dvs.DocumentID != identifiers.CardiffAndValeCRN+"|123" {
+		t.Errorf("expected document identifier to include system if not a uuid. got: %v %v", dvs, err)
+	}
+	d.Patient.Identifiers = nil
+	if _, err := repo.NewDocumentVersionStructure(d); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument without NHS number. got: %v", err)
+	}
+	d = testDocument()
+	d.Data = nil
+	if _, err := repo.NewDocumentVersionStructure(d); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument without document data. got: %v", err)
+	}
+}
+
+func TestSupersede(t *testing.T) {
+	dvs := &DocumentVersionStructure{VersionNumber: 1}
+	supersede(dvs, []*apiv1.DocumentVersion{
+		{Version: 2, Response: &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.WCRSDocumentID, Value: "W2"}}},
+		{Version: 1, Response: &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.UUID, Value: "other"}}},
+	})
+	if dvs.VersionNumber != 3 || len(dvs.SupersessionSet) != 1 || dvs.SupersessionSet[0] != "W2" {
+		t.Fatalf("expected version 3 superseding only the version stored in the WCRS. got: %+v", dvs)
+	}
+}
+
+func TestDocumentAttributes(t *testing.T) {
+	attrs := documentAttributes(testDocument())
+	expected := []DocumentAttributeStructure{
+		{Name: AttributeDocumentType, Value: "823691000000103", CodeSystem: identifiers.SNOMEDCT},
+		{Name: AttributeSpecialty, Value: "394591006", CodeSystem: identifiers.SNOMEDCT},
+		{Name: AttributeAuthor, Value: "4624000", CodeSystem: identifiers.GMCNumber, Display: "Dr Mark Wardle"},
+		{Name: AttributeAuthor, Value: "ma090906", CodeSystem: identifiers.CymruUserID},
+		{Name: AttributeEventDate, Value: "2020-04-28T09:00:00"},
+	}
+	if len(attrs) != len(expected) {
+		t.Fatalf("expected %d attributes. got: %d", len(expected), len(attrs))
+	}
+	for i, attr := range attrs {
+		if *attr != expected[i] {
+			t.Errorf("attribute %d: expected %+v, got %+v", i, expected[i], *attr)
+		}
+	}
+	d := &apiv1.Document{Author: &apiv1.Practitioner{Names: []*apiv1.HumanName{{Given: "Mark", Family: "Wardle"}}}}
+	if attrs := documentAttributes(d); len(attrs) != 1 || attrs[0].Name != AttributeAuthor || attrs[0].Value != "Mark Wardle" || attrs[0].CodeSystem != "" {
+		t.Fatalf("expected author by name only without identifiers. got: %v", attrs)
+	}
+}
+
+const responseEnvelope = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<StoreDocumentResponse xmlns="http://apps.wales.nhs.uk/wcrs/"><DocumentId>%s</DocumentId><ErrorMessage>%s</ErrorMessage></StoreDocumentResponse>
+</soap:Body>
+</soap:Envelope>`
+
+func TestPublishDocument(t *testing.T) {
+	errorMessage := ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if r.Header.Get("SOAPAction") != storeDocumentSOAP {
+			t.Errorf("unexpected soap action: %s", r.Header.Get("SOAPAction"))
+		}
+		var envelope struct {
+			Body struct {
+				Request StoreDocumentRequest
+			}
+		}
+		if err := xml.Unmarshal(b, &envelope); err != nil {
+			t.Errorf("invalid request: %s", err)
+		}
+		if dvs := envelope.Body.Request.Document; dvs == nil || dvs.NHSNumber != "1111111111" || string(dvs.Content) != "%PDF-1.4 test" {
+			t.Errorf("unexpected document in request: %s", b)
+		}
+		if !strings.Contains(string(b), ">user</wsse:Username>") {
+			t.Errorf("expected WS-Security username token in request: %s", b)
+		}
+		if errorMessage == "wait" {
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprintf(w, responseEnvelope, "W123", errorMessage)
+	}))
+	defer ts.Close()
+	repo := NewRepository(ts.URL, "user", "password", "7A4")
+	r := &apiv1.PublishDocumentRequest{Document: testDocument()}
+	response, err := repo.PublishDocument(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := response.GetId(); id.GetSystem() != identifiers.WCRSDocumentID || id.GetValue() != "W123" {
+		t.Fatalf("unexpected document identifier: %v", id)
+	}
+	errorMessage = "invalid NHS number"
+	if _, err := repo.PublishDocument(context.Background(), r); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected failed precondition for error from service. got: %v", err)
+	}
+	errorMessage = "wait"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := repo.PublishDocument(ctx, r); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded. got: %v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := repo.PublishDocument(ctx, r); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected cancelled. got: %v", err)
+	}
+}